@@ -21,6 +21,8 @@ package v1
 
 import (
 	"context"
+	"fmt"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -28,6 +30,7 @@ import (
 
 	volumesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/utils/ptr"
@@ -220,6 +223,119 @@ func (list *BackupList) SortByCreationTimeAndName() {
 	})
 }
 
+// GetType returns the requested type of a pgBaseBackup backup,
+// defaulting to a full backup
+func (backup *Backup) GetType() BackupType {
+	if backup.Spec.Type == "" {
+		return BackupTypeFull
+	}
+
+	return backup.Spec.Type
+}
+
+// IsCompletedPgBaseBackup checks if a backup is a completed backup taken
+// with the pgBaseBackup method
+func (backup *Backup) IsCompletedPgBaseBackup() bool {
+	return backup != nil &&
+		backup.Spec.Method == BackupMethodPgBaseBackup &&
+		backup.Status.Phase == BackupPhaseCompleted &&
+		backup.Status.PgBaseBackupStatus != nil
+}
+
+// IsCompletedLogicalBackup checks if a backup is a completed backup taken
+// with the logical method
+func (backup *Backup) IsCompletedLogicalBackup() bool {
+	return backup != nil &&
+		backup.Spec.Method == BackupMethodLogical &&
+		backup.Status.Phase == BackupPhaseCompleted &&
+		backup.Status.LogicalBackupStatus != nil
+}
+
+// GetDatabase returns the dump of the passed database, or nil
+// if the database has not been exported
+func (logicalStatus *BackupLogicalStatus) GetDatabase(name string) *LogicalBackupDatabaseStatus {
+	if logicalStatus == nil {
+		return nil
+	}
+
+	for idx := range logicalStatus.Databases {
+		if logicalStatus.Databases[idx].Name == name {
+			return &logicalStatus.Databases[idx]
+		}
+	}
+
+	return nil
+}
+
+// FindPgBaseBackupParent returns the backup an incremental or differential
+// backup of the passed cluster should be based on, or nil if there is none.
+// Incremental backups are based on the latest completed backup stored in
+// the passed volume, while differential backups on the latest full one
+func (list *BackupList) FindPgBaseBackupParent(
+	clusterName string,
+	claimName string,
+	backupType BackupType,
+) *Backup {
+	var parent *Backup
+	for idx := range list.Items {
+		backup := &list.Items[idx]
+		if backup.Spec.Cluster.Name != clusterName ||
+			!backup.IsCompletedPgBaseBackup() ||
+			backup.Status.PgBaseBackupStatus.ClaimName != claimName ||
+			backup.Status.StoppedAt == nil {
+			continue
+		}
+
+		if backupType == BackupTypeDifferential &&
+			backup.Status.PgBaseBackupStatus.Type != BackupTypeFull {
+			continue
+		}
+
+		if parent == nil || backup.Status.StoppedAt.After(parent.Status.StoppedAt.Time) {
+			parent = backup
+		}
+	}
+
+	return parent
+}
+
+// GetPgBaseBackupChain returns the list of backups needed to restore the
+// passed pgBaseBackup backup, starting from the full one and ending with
+// the passed backup itself
+func (list *BackupList) GetPgBaseBackupChain(backup *Backup) ([]*Backup, error) {
+	backupsByName := make(map[string]*Backup, len(list.Items))
+	for idx := range list.Items {
+		backupsByName[list.Items[idx].Name] = &list.Items[idx]
+	}
+
+	var chain []*Backup
+	for current := backup; ; {
+		if !current.IsCompletedPgBaseBackup() {
+			return nil, fmt.Errorf("backup %s is not a completed pgBaseBackup backup", current.Name)
+		}
+
+		chain = append(chain, current)
+		if current.Status.PgBaseBackupStatus.ParentBackupName == "" {
+			break
+		}
+
+		// a chain longer than the list of backups contains a cycle
+		if len(chain) > len(list.Items) {
+			return nil, fmt.Errorf("detected a cycle in the parent chain of backup %s", backup.Name)
+		}
+
+		parentName := current.Status.PgBaseBackupStatus.ParentBackupName
+		parent, ok := backupsByName[parentName]
+		if !ok {
+			return nil, fmt.Errorf("parent backup %s of backup %s not found", parentName, current.Name)
+		}
+		current = parent
+	}
+
+	slices.Reverse(chain)
+	return chain, nil
+}
+
 // GetStatus gets the backup status
 func (backup *Backup) GetStatus() *BackupStatus {
 	return &backup.Status
@@ -317,7 +433,7 @@ func (configuration *BackupPluginConfiguration) IsEmpty() bool {
 
 // IsManagedByInstance returns true if the backup is managed by the instance manager
 func (b BackupMethod) IsManagedByInstance() bool {
-	return b == BackupMethodPlugin || b == BackupMethodBarmanObjectStore
+	return b == BackupMethodPlugin || b == BackupMethodBarmanObjectStore || b == BackupMethodPgBaseBackup
 }
 
 // IsManagedByOperator returns true if the backup is managed by the operator
@@ -325,6 +441,86 @@ func (b BackupMethod) IsManagedByOperator() bool {
 	return b == BackupMethodVolumeSnapshot
 }
 
+// SupportsVerification returns true if the backups taken with this method
+// can be verified by restoring them into a throwaway instance
+func (b BackupMethod) SupportsVerification() bool {
+	return b == BackupMethodBarmanObjectStore || b == BackupMethodVolumeSnapshot || b == BackupMethodPgBaseBackup
+}
+
+// IsVerificationPending checks if a completed backup has been
+// requested to be verified and its verification is not terminated yet
+func (backup *Backup) IsVerificationPending() bool {
+	return backup.Spec.Verification != nil &&
+		backup.Status.Phase == BackupPhaseCompleted &&
+		!backup.Status.Verification.IsDone()
+}
+
+// IsDone checks if the verification of a backup is terminated
+func (verificationStatus *BackupVerificationStatus) IsDone() bool {
+	return verificationStatus != nil &&
+		(verificationStatus.Phase == BackupVerificationPhaseSucceeded ||
+			verificationStatus.Phase == BackupVerificationPhaseFailed)
+}
+
+// SetVerificationAsRunning marks the verification of a backup as running
+func (backupStatus *BackupStatus) SetVerificationAsRunning() {
+	backupStatus.Verification = &BackupVerificationStatus{
+		Phase:     BackupVerificationPhaseRunning,
+		StartedAt: ptr.To(metav1.Now()),
+	}
+	meta.SetStatusCondition(&backupStatus.Conditions, metav1.Condition{
+		Type:    BackupConditionVerified,
+		Status:  metav1.ConditionUnknown,
+		Reason:  string(ConditionReasonVerificationRunning),
+		Message: "The backup is being restored into a throwaway instance",
+	})
+}
+
+// SetVerificationResult records the result of the checks run in the
+// instance restored from a backup. The verification fails when err is
+// not nil or when any check did not pass
+func (backupStatus *BackupStatus) SetVerificationResult(checks []BackupVerificationCheckStatus, err error) {
+	if backupStatus.Verification == nil {
+		backupStatus.Verification = &BackupVerificationStatus{}
+	}
+
+	verification := backupStatus.Verification
+	verification.Checks = checks
+	verification.StoppedAt = ptr.To(metav1.Now())
+	verification.Phase = BackupVerificationPhaseSucceeded
+	verification.Error = ""
+
+	var failedChecks []string
+	for _, check := range checks {
+		if !check.Passed {
+			failedChecks = append(failedChecks, check.Name)
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:    BackupConditionVerified,
+		Status:  metav1.ConditionTrue,
+		Reason:  string(ConditionReasonVerificationSucceeded),
+		Message: "The backup has been restored and every check passed",
+	}
+	switch {
+	case err != nil:
+		verification.Phase = BackupVerificationPhaseFailed
+		verification.Error = err.Error()
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = string(ConditionReasonVerificationFailed)
+		condition.Message = err.Error()
+
+	case len(failedChecks) > 0:
+		verification.Phase = BackupVerificationPhaseFailed
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = string(ConditionReasonVerificationFailed)
+		condition.Message = fmt.Sprintf("Failed checks: %s", strings.Join(failedChecks, ", "))
+	}
+
+	meta.SetStatusCondition(&backupStatus.Conditions, condition)
+}
+
 // SetAdmissionError sets the admission error status on the Backup resource
 func (backup *Backup) SetAdmissionError(msg string) {
 	if len(msg) > 0 {
@@ -25,6 +25,7 @@ import (
 
 	volumesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 
@@ -695,3 +696,206 @@ var _ = Describe("GetVolumeSnapshotConfiguration", func() {
 		})
 	})
 })
+
+var _ = Describe("pgBaseBackup backup chains", func() {
+	newPgBaseBackup := func(name string, backupType BackupType, parent string, stoppedAt time.Time) Backup {
+		return Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: BackupSpec{
+				Cluster: LocalObjectReference{Name: "cluster-example"},
+				Method:  BackupMethodPgBaseBackup,
+				Type:    backupType,
+			},
+			Status: BackupStatus{
+				Phase:     BackupPhaseCompleted,
+				StoppedAt: ptr.To(metav1.NewTime(stoppedAt)),
+				PgBaseBackupStatus: &BackupPgBaseBackupStatus{
+					Type:             backupType,
+					ParentBackupName: parent,
+					ClaimName:        "backups",
+				},
+			},
+		}
+	}
+
+	now := time.Now()
+	list := BackupList{
+		Items: []Backup{
+			newPgBaseBackup("full-1", BackupTypeFull, "", now.Add(-72*time.Hour)),
+			newPgBaseBackup("full-2", BackupTypeFull, "", now.Add(-48*time.Hour)),
+			newPgBaseBackup("incr-1", BackupTypeIncremental, "full-2", now.Add(-24*time.Hour)),
+			newPgBaseBackup("incr-2", BackupTypeIncremental, "incr-1", now.Add(-time.Hour)),
+		},
+	}
+
+	It("defaults the backup type to full", func() {
+		Expect((&Backup{}).GetType()).To(Equal(BackupTypeFull))
+	})
+
+	It("bases incremental backups on the latest backup", func() {
+		parent := list.FindPgBaseBackupParent("cluster-example", "backups", BackupTypeIncremental)
+		Expect(parent).ToNot(BeNil())
+		Expect(parent.Name).To(Equal("incr-2"))
+	})
+
+	It("bases differential backups on the latest full backup", func() {
+		parent := list.FindPgBaseBackupParent("cluster-example", "backups", BackupTypeDifferential)
+		Expect(parent).ToNot(BeNil())
+		Expect(parent.Name).To(Equal("full-2"))
+	})
+
+	It("ignores backups of other clusters or stored in other volumes", func() {
+		Expect(list.FindPgBaseBackupParent("another-cluster", "backups", BackupTypeIncremental)).To(BeNil())
+		Expect(list.FindPgBaseBackupParent("cluster-example", "other", BackupTypeIncremental)).To(BeNil())
+	})
+
+	It("returns the chain of backups from the full one", func() {
+		chain, err := list.GetPgBaseBackupChain(&list.Items[3])
+		Expect(err).ToNot(HaveOccurred())
+		names := make([]string, 0, len(chain))
+		for _, backup := range chain {
+			names = append(names, backup.Name)
+		}
+		Expect(names).To(Equal([]string{"full-2", "incr-1", "incr-2"}))
+	})
+
+	It("fails when a parent backup is missing", func() {
+		broken := BackupList{Items: []Backup{list.Items[2], list.Items[3]}}
+		_, err := broken.GetPgBaseBackupChain(&broken.Items[1])
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("detects cycles in the parent chain", func() {
+		cyclic := BackupList{
+			Items: []Backup{
+				newPgBaseBackup("a", BackupTypeIncremental, "b", now),
+				newPgBaseBackup("b", BackupTypeIncremental, "a", now),
+			},
+		}
+		_, err := cyclic.GetPgBaseBackupChain(&cyclic.Items[0])
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("logical backups", func() {
+	backup := Backup{
+		Spec: BackupSpec{
+			Method: BackupMethodLogical,
+		},
+		Status: BackupStatus{
+			Phase:  BackupPhaseCompleted,
+			Method: BackupMethodLogical,
+			LogicalBackupStatus: &BackupLogicalStatus{
+				ClaimName: "dumps",
+				Path:      "cluster-example/backup-example",
+				Databases: []LogicalBackupDatabaseStatus{
+					{Name: "app", FileName: "app.dump", Size: 1024, Checksum: "abc"},
+				},
+			},
+		},
+	}
+
+	It("detects completed logical backups", func() {
+		Expect(backup.IsCompletedLogicalBackup()).To(BeTrue())
+
+		running := backup.DeepCopy()
+		running.Status.Phase = BackupPhaseRunning
+		Expect(running.IsCompletedLogicalBackup()).To(BeFalse())
+
+		physical := backup.DeepCopy()
+		physical.Spec.Method = BackupMethodBarmanObjectStore
+		Expect(physical.IsCompletedLogicalBackup()).To(BeFalse())
+	})
+
+	It("finds the dump of a database", func() {
+		dump := backup.Status.LogicalBackupStatus.GetDatabase("app")
+		Expect(dump).ToNot(BeNil())
+		Expect(dump.FileName).To(Equal("app.dump"))
+		Expect(backup.Status.LogicalBackupStatus.GetDatabase("missing")).To(BeNil())
+	})
+})
+
+var _ = Describe("backup verification", func() {
+	backup := Backup{
+		Spec: BackupSpec{
+			Method:       BackupMethodBarmanObjectStore,
+			Verification: &BackupVerificationConfiguration{},
+		},
+		Status: BackupStatus{
+			Phase: BackupPhaseCompleted,
+		},
+	}
+
+	It("detects pending verifications", func() {
+		Expect(backup.IsVerificationPending()).To(BeTrue())
+
+		running := backup.DeepCopy()
+		running.Status.SetVerificationAsRunning()
+		Expect(running.IsVerificationPending()).To(BeTrue())
+
+		verified := backup.DeepCopy()
+		verified.Status.SetVerificationResult(nil, nil)
+		Expect(verified.IsVerificationPending()).To(BeFalse())
+
+		notRequested := backup.DeepCopy()
+		notRequested.Spec.Verification = nil
+		Expect(notRequested.IsVerificationPending()).To(BeFalse())
+
+		notCompleted := backup.DeepCopy()
+		notCompleted.Status.Phase = BackupPhaseRunning
+		Expect(notCompleted.IsVerificationPending()).To(BeFalse())
+	})
+
+	It("marks the verification as running", func() {
+		status := backup.Status.DeepCopy()
+		status.SetVerificationAsRunning()
+		Expect(status.Verification.Phase).To(Equal(BackupVerificationPhaseRunning))
+		Expect(status.Verification.StartedAt).ToNot(BeNil())
+		Expect(meta.IsStatusConditionPresentAndEqual(
+			status.Conditions, BackupConditionVerified, metav1.ConditionUnknown)).To(BeTrue())
+	})
+
+	It("succeeds when every check passed", func() {
+		status := backup.Status.DeepCopy()
+		status.SetVerificationAsRunning()
+		status.SetVerificationResult([]BackupVerificationCheckStatus{
+			{Name: BackupVerificationAmcheckName, Passed: true},
+			{Name: "orders", Passed: true},
+		}, nil)
+		Expect(status.Verification.Phase).To(Equal(BackupVerificationPhaseSucceeded))
+		Expect(status.Verification.StartedAt).ToNot(BeNil())
+		Expect(status.Verification.StoppedAt).ToNot(BeNil())
+		Expect(status.Verification.Checks).To(HaveLen(2))
+		Expect(meta.IsStatusConditionTrue(status.Conditions, BackupConditionVerified)).To(BeTrue())
+	})
+
+	It("fails when a check did not pass", func() {
+		status := backup.Status.DeepCopy()
+		status.SetVerificationResult([]BackupVerificationCheckStatus{
+			{Name: BackupVerificationAmcheckName, Passed: true},
+			{Name: "orders", Passed: false, Message: "the query returned false"},
+		}, nil)
+		Expect(status.Verification.Phase).To(Equal(BackupVerificationPhaseFailed))
+		condition := meta.FindStatusCondition(status.Conditions, BackupConditionVerified)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(ConditionReasonVerificationFailed)))
+		Expect(condition.Message).To(ContainSubstring("orders"))
+	})
+
+	It("fails when the backup could not be restored", func() {
+		status := backup.Status.DeepCopy()
+		status.SetVerificationResult(nil, errors.New("restore failed"))
+		Expect(status.Verification.Phase).To(Equal(BackupVerificationPhaseFailed))
+		Expect(status.Verification.Error).To(Equal("restore failed"))
+		Expect(meta.IsStatusConditionFalse(status.Conditions, BackupConditionVerified)).To(BeTrue())
+	})
+
+	It("supports verification only for physical backups", func() {
+		Expect(BackupMethodBarmanObjectStore.SupportsVerification()).To(BeTrue())
+		Expect(BackupMethodVolumeSnapshot.SupportsVerification()).To(BeTrue())
+		Expect(BackupMethodPgBaseBackup.SupportsVerification()).To(BeTrue())
+		Expect(BackupMethodLogical.SupportsVerification()).To(BeFalse())
+		Expect(BackupMethodPlugin.SupportsVerification()).To(BeFalse())
+	})
+})
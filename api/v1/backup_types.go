@@ -120,8 +120,96 @@ const (
 	// BackupMethodPlugin means that this backup should be handled by
 	// a plugin
 	BackupMethodPlugin BackupMethod = "plugin"
+
+	// BackupMethodPgBaseBackup means using pg_basebackup to take the
+	// backup into the volume configured in the cluster
+	BackupMethodPgBaseBackup BackupMethod = "pgBaseBackup"
+
+	// BackupMethodLogical means using pg_dump, in a dedicated job, to
+	// export the databases into the volume configured in the cluster
+	BackupMethodLogical BackupMethod = "logical"
+)
+
+// BackupType defines whether a pgBaseBackup backup is a full copy of the
+// instance or contains only the blocks changed since a previous backup
+type BackupType string
+
+const (
+	// BackupTypeFull means taking a full copy of the instance
+	BackupTypeFull BackupType = "full"
+
+	// BackupTypeIncremental means copying only the blocks changed since
+	// the latest completed backup, regardless of its type
+	BackupTypeIncremental BackupType = "incremental"
+
+	// BackupTypeDifferential means copying only the blocks changed since
+	// the latest completed full backup
+	BackupTypeDifferential BackupType = "differential"
+)
+
+// BackupVerificationPhase is the phase of the verification of a backup
+type BackupVerificationPhase string
+
+const (
+	// BackupVerificationPhaseRunning means that the backup is being
+	// restored into a throwaway instance and checked
+	BackupVerificationPhaseRunning BackupVerificationPhase = "running"
+
+	// BackupVerificationPhaseSucceeded means that the backup has been
+	// restored and every check passed
+	BackupVerificationPhaseSucceeded BackupVerificationPhase = "succeeded"
+
+	// BackupVerificationPhaseFailed means that the backup could not be
+	// restored or that at least one check failed
+	BackupVerificationPhaseFailed BackupVerificationPhase = "failed"
 )
 
+const (
+	// BackupConditionVerified is the condition reporting whether a backup
+	// has been verified by restoring it
+	BackupConditionVerified = "Verified"
+
+	// ConditionReasonVerificationRunning means that the backup is being verified
+	ConditionReasonVerificationRunning ConditionReason = "VerificationRunning"
+
+	// ConditionReasonVerificationSucceeded means that the backup has been verified
+	ConditionReasonVerificationSucceeded ConditionReason = "VerificationSucceeded"
+
+	// ConditionReasonVerificationFailed means that the verification of the backup failed
+	ConditionReasonVerificationFailed ConditionReason = "VerificationFailed"
+
+	// BackupVerificationAmcheckName is the name of the check running
+	// pg_amcheck in the instance restored from a backup
+	BackupVerificationAmcheckName = "pg_amcheck"
+)
+
+// BackupVerificationConfiguration defines how a completed backup is
+// verified, restoring it into a throwaway instance that is checked with
+// pg_amcheck and with the passed SQL assertions
+type BackupVerificationConfiguration struct {
+	// The SQL assertions to be run in the restored instance
+	// +optional
+	Assertions []BackupVerificationAssertion `json:"assertions,omitempty"`
+}
+
+// BackupVerificationAssertion is a SQL query run in the instance
+// restored from a backup. The query must return a single boolean
+// value, which is true when the assertion holds
+type BackupVerificationAssertion struct {
+	// The name of the assertion
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The database where the query is run
+	// +kubebuilder:default:=postgres
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// The query to be run
+	// +kubebuilder:validation:MinLength=1
+	Query string `json:"query"`
+}
+
 // BackupSpec defines the desired state of Backup
 // +kubebuilder:validation:XValidation:rule="oldSelf == self",message="BackupSpec is immutable once set"
 type BackupSpec struct {
@@ -139,13 +227,15 @@ type BackupSpec struct {
 	Target BackupTarget `json:"target,omitempty"`
 
 	// The backup method to be used, possible options are `barmanObjectStore`,
-	// `volumeSnapshot` or `plugin`. Defaults to: `barmanObjectStore`.
+	// `volumeSnapshot`, `plugin`, `pgBaseBackup` or `logical`. Defaults to: `barmanObjectStore`.
 	// +optional
-	// +kubebuilder:validation:Enum=barmanObjectStore;volumeSnapshot;plugin
+	// +kubebuilder:validation:Enum=barmanObjectStore;volumeSnapshot;plugin;pgBaseBackup;logical
 	// +kubebuilder:default:=barmanObjectStore
 	Method BackupMethod `json:"method,omitempty"`
 
-	// Configuration parameters passed to the plugin managing this backup
+	// Configuration parameters passed to the plugin managing this backup.
+	// Plugins can't be used as a destination by the `logical` method,
+	// which only stores the dumps in the volume configured in the cluster
 	// +optional
 	PluginConfiguration *BackupPluginConfiguration `json:"pluginConfiguration,omitempty"`
 
@@ -159,6 +249,24 @@ type BackupSpec struct {
 	// Overrides the default settings specified in the cluster '.backup.volumeSnapshot.onlineConfiguration' stanza
 	// +optional
 	OnlineConfiguration *OnlineConfiguration `json:"onlineConfiguration,omitempty"`
+
+	// The type of backup to be taken with the `pgBaseBackup` method, possible
+	// options are `full`, `incremental` and `differential`. Incremental and
+	// differential backups require PostgreSQL 17 or later. Defaults to: `full`.
+	// +optional
+	// +kubebuilder:validation:Enum=full;incremental;differential
+	Type BackupType `json:"type,omitempty"`
+
+	// The list of databases to be exported with the `logical` method. When
+	// empty, every database accepting connections is exported, except
+	// templates and the `postgres` database
+	// +optional
+	Databases []string `json:"databases,omitempty"`
+
+	// When set, the backup is restored into a throwaway instance
+	// once completed, to verify it
+	// +optional
+	Verification *BackupVerificationConfiguration `json:"verification,omitempty"`
 }
 
 // BackupPluginConfiguration contains the backup configuration used by
@@ -194,6 +302,65 @@ type BackupSnapshotElementStatus struct {
 	TablespaceName string `json:"tablespaceName,omitempty"`
 }
 
+// BackupPgBaseBackupStatus the fields exclusive to the pgBaseBackup method backup
+type BackupPgBaseBackupStatus struct {
+	// The type of the backup that has been taken. It may differ from the
+	// requested one when no parent backup was available
+	// +optional
+	Type BackupType `json:"type,omitempty"`
+
+	// The name of the Backup this backup is based on. Only set for
+	// incremental and differential backups
+	// +optional
+	ParentBackupName string `json:"parentBackupName,omitempty"`
+
+	// The name of the PersistentVolumeClaim containing the backup
+	// +optional
+	ClaimName string `json:"claimName,omitempty"`
+
+	// The path of the backup, relative to the root of the volume
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// The timeline of the WAL range required by the backup, as reported
+	// in the backup manifest
+	// +optional
+	TimeLineID int `json:"timeLineID,omitempty"`
+}
+
+// BackupLogicalStatus the fields exclusive to the logical method backup
+type BackupLogicalStatus struct {
+	// The name of the PersistentVolumeClaim containing the dumps
+	// +optional
+	ClaimName string `json:"claimName,omitempty"`
+
+	// The path of the directory containing the dumps, relative to the
+	// root of the volume
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// The dumps of the exported databases
+	// +optional
+	Databases []LogicalBackupDatabaseStatus `json:"databases,omitempty"`
+}
+
+// LogicalBackupDatabaseStatus describes the dump of a database taken
+// with the logical method
+type LogicalBackupDatabaseStatus struct {
+	// The name of the exported database
+	Name string `json:"name"`
+
+	// The name of the file containing the dump, in the pg_dump custom
+	// format, relative to the backup path
+	FileName string `json:"fileName"`
+
+	// The size of the dump in bytes
+	Size int64 `json:"size"`
+
+	// The SHA-256 checksum of the dump, hex encoded
+	Checksum string `json:"checksum"`
+}
+
 // BackupStatus defines the observed state of Backup
 type BackupStatus struct {
 	// The potential credentials for each cloud provider
@@ -312,6 +479,60 @@ type BackupStatus struct {
 	// A map containing the plugin metadata
 	// +optional
 	PluginMetadata map[string]string `json:"pluginMetadata,omitempty"`
+
+	// Status of the pgBaseBackup backup
+	// +optional
+	PgBaseBackupStatus *BackupPgBaseBackupStatus `json:"pgBaseBackupStatus,omitempty"`
+
+	// Status of the logical backup
+	// +optional
+	LogicalBackupStatus *BackupLogicalStatus `json:"logicalBackupStatus,omitempty"`
+
+	// Status of the verification of the backup
+	// +optional
+	Verification *BackupVerificationStatus `json:"verification,omitempty"`
+
+	// Conditions for the backup
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// BackupVerificationStatus contains the result of the verification
+// of a backup
+type BackupVerificationStatus struct {
+	// The phase of the verification
+	// +optional
+	Phase BackupVerificationPhase `json:"phase,omitempty"`
+
+	// When the verification was started
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// When the verification was terminated
+	// +optional
+	StoppedAt *metav1.Time `json:"stoppedAt,omitempty"`
+
+	// The result of each check run in the restored instance
+	// +optional
+	Checks []BackupVerificationCheckStatus `json:"checks,omitempty"`
+
+	// The error preventing the verification from completing
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// BackupVerificationCheckStatus is the result of a check run in the
+// instance restored from a backup
+type BackupVerificationCheckStatus struct {
+	// The name of the check, `pg_amcheck` or the name of the assertion
+	Name string `json:"name"`
+
+	// Whether the check passed
+	Passed bool `json:"passed"`
+
+	// The reason why the check failed
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // InstanceID contains the information to identify an instance
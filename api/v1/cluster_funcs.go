@@ -25,6 +25,7 @@ import (
 	"maps"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -34,6 +35,7 @@ import (
 	pgTime "github.com/cloudnative-pg/machinery/pkg/postgres/time"
 	"github.com/cloudnative-pg/machinery/pkg/postgres/version"
 	"github.com/cloudnative-pg/machinery/pkg/stringset"
+	"github.com/robfig/cron"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -41,6 +43,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/configfile"
@@ -60,6 +63,48 @@ func (configuration *VolumeSnapshotConfiguration) GetOnline() bool {
 	return *configuration.Online
 }
 
+// keepWithinRegex matches the recovery windows of the volume snapshot
+// retention policies, using the same syntax of the Barman ones
+var keepWithinRegex = regexp.MustCompile(`^([1-9][0-9]*)([dwm])$`)
+
+// IsEmpty tells whether the retention policy has no rules, and
+// then every backup is kept
+func (policy *VolumeSnapshotRetentionPolicy) IsEmpty() bool {
+	return policy == nil ||
+		(policy.KeepLast == nil && policy.KeepWithin == "" &&
+			policy.KeepDaily == nil && policy.KeepWeekly == nil && policy.KeepMonthly == nil)
+}
+
+// GetWindowStart returns the start of the recovery window defined
+// by KeepWithin, relative to the passed time, or nil when not set
+func (policy *VolumeSnapshotRetentionPolicy) GetWindowStart(now time.Time) (*time.Time, error) {
+	if policy == nil || policy.KeepWithin == "" {
+		return nil, nil
+	}
+
+	matches := keepWithinRegex.FindStringSubmatch(policy.KeepWithin)
+	if len(matches) < 3 {
+		return nil, fmt.Errorf("not a valid recovery window: %s", policy.KeepWithin)
+	}
+
+	value, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return nil, fmt.Errorf("not a valid recovery window: %s", policy.KeepWithin)
+	}
+
+	var windowStart time.Time
+	switch matches[2] {
+	case "d":
+		windowStart = now.AddDate(0, 0, -value)
+	case "w":
+		windowStart = now.AddDate(0, 0, -7*value)
+	case "m":
+		windowStart = now.AddDate(0, -value, 0)
+	}
+
+	return &windowStart, nil
+}
+
 // GetWaitForArchive tells whether to wait for archive or not
 func (o OnlineConfiguration) GetWaitForArchive() bool {
 	if o.WaitForArchive == nil {
@@ -403,6 +448,147 @@ func (s *StorageConfiguration) GetSizeOrNil() *resource.Quantity {
 	return nil
 }
 
+const (
+	// defaultStorageAutoscaleUsageThreshold is the percentage of used
+	// space triggering the growth of a PVC, when not specified
+	defaultStorageAutoscaleUsageThreshold = 80
+
+	// defaultStorageAutoscaleIncrement is how much a PVC
+	// is grown, when not specified
+	defaultStorageAutoscaleIncrement = "20%"
+
+	// defaultStorageAutoscaleCooldown is the minimum time between
+	// two growths of the same PVC, when not specified
+	defaultStorageAutoscaleCooldown = 5 * time.Minute
+
+	// storageAutoscaleRounding is the granularity of the sizes
+	// computed when growing a PVC by a percentage
+	storageAutoscaleRounding = 1024 * 1024
+)
+
+// GetUsageThreshold returns the percentage of used space
+// in the filesystem triggering the growth of a PVC
+func (c *StorageAutoscaleConfiguration) GetUsageThreshold() int {
+	if c.UsageThreshold == 0 {
+		return defaultStorageAutoscaleUsageThreshold
+	}
+
+	return c.UsageThreshold
+}
+
+// GetCooldown returns the minimum time between two growths of the same PVC
+func (c *StorageAutoscaleConfiguration) GetCooldown() time.Duration {
+	if c.Cooldown == nil {
+		return defaultStorageAutoscaleCooldown
+	}
+
+	return c.Cooldown.Duration
+}
+
+// GetMaxSize returns the maximum size a PVC can be grown to
+func (c *StorageAutoscaleConfiguration) GetMaxSize() (resource.Quantity, error) {
+	return resource.ParseQuantity(c.MaxSize)
+}
+
+// GetNextSize returns the size a PVC with the passed size should be grown
+// to, never exceeding the maximum size. The increment can be either a
+// quantity or a percentage of the current size
+func (c *StorageAutoscaleConfiguration) GetNextSize(currentSize resource.Quantity) (resource.Quantity, error) {
+	maxSize, err := c.GetMaxSize()
+	if err != nil {
+		return resource.Quantity{}, fmt.Errorf("invalid maxSize: %w", err)
+	}
+
+	increment := c.Increment
+	if increment == "" {
+		increment = defaultStorageAutoscaleIncrement
+	}
+
+	var nextSize resource.Quantity
+	if percentage, isPercentage := strings.CutSuffix(increment, "%"); isPercentage {
+		value, err := strconv.Atoi(percentage)
+		if err != nil || value < 1 {
+			return resource.Quantity{}, fmt.Errorf("invalid increment: %s", increment)
+		}
+		size := currentSize.Value() + currentSize.Value()*int64(value)/100
+		size = (size + storageAutoscaleRounding - 1) / storageAutoscaleRounding * storageAutoscaleRounding
+		nextSize = *resource.NewQuantity(size, resource.BinarySI)
+	} else {
+		quantity, err := resource.ParseQuantity(increment)
+		if err != nil || quantity.Sign() <= 0 {
+			return resource.Quantity{}, fmt.Errorf("invalid increment: %s", increment)
+		}
+		nextSize = currentSize.DeepCopy()
+		nextSize.Add(quantity)
+	}
+
+	if nextSize.Cmp(maxSize) > 0 {
+		return maxSize, nil
+	}
+
+	return nextSize, nil
+}
+
+// defaultReplicaAutoscalingCooldown is the minimum time between two
+// scaling operations of the read-replica autoscaler, when not specified
+const defaultReplicaAutoscalingCooldown = 5 * time.Minute
+
+// GetCooldown returns the minimum time between two scaling operations
+func (c *ReplicaAutoscalingConfiguration) GetCooldown() time.Duration {
+	if c.Cooldown == nil {
+		return defaultReplicaAutoscalingCooldown
+	}
+
+	return c.Cooldown.Duration
+}
+
+// defaultSwitchoverDrainTimeout is the maximum time to wait for the active
+// transactions on the primary to complete before a switchover, when not specified
+const defaultSwitchoverDrainTimeout = 30 * time.Second
+
+// GetTimeout returns the maximum time to wait for the active transactions
+// to complete before proceeding with the switchover
+func (c *SwitchoverDrainConfiguration) GetTimeout() time.Duration {
+	if c == nil || c.Timeout == nil {
+		return defaultSwitchoverDrainTimeout
+	}
+
+	return c.Timeout.Duration
+}
+
+// ShouldPausePoolers returns true if the Poolers pointing to the cluster
+// need to be paused during the switchover
+func (c *SwitchoverDrainConfiguration) ShouldPausePoolers() bool {
+	if c == nil {
+		return false
+	}
+
+	return c.PausePoolers == nil || *c.PausePoolers
+}
+
+// IsSwitchoverDrainEnabled returns true if the client connections need to
+// be drained before a switchover
+func (cluster *Cluster) IsSwitchoverDrainEnabled() bool {
+	return cluster.Spec.SwitchoverDrain != nil
+}
+
+// GetMinInstancesForSynchronousReplication returns the minimum number of
+// instances, including the primary, needed to satisfy the synchronous
+// replication requirements of the cluster
+func (cluster *Cluster) GetMinInstancesForSynchronousReplication() int {
+	result := 1
+	if cluster.Spec.MaxSyncReplicas > 0 {
+		result = cluster.Spec.MaxSyncReplicas + 1
+	}
+
+	synchronous := cluster.Spec.PostgresConfiguration.Synchronous
+	if synchronous != nil && synchronous.DataDurability != DataDurabilityLevelPreferred {
+		result = max(result, synchronous.Number+1)
+	}
+
+	return result
+}
+
 // AreDefaultQueriesDisabled checks whether default monitoring queries should be disabled
 func (m *MonitoringConfiguration) AreDefaultQueriesDisabled() bool {
 	return m != nil && m.DisableDefaultQueries != nil && *m.DisableDefaultQueries
@@ -832,6 +1018,72 @@ func (cluster *Cluster) GetPrimaryUpdateMethod() PrimaryUpdateMethod {
 	return strategy
 }
 
+// ParseSchedule parses the schedule of the maintenance window, returning
+// it together with the location where it is to be evaluated
+func (window MaintenanceWindow) ParseSchedule() (cron.Schedule, *time.Location, error) {
+	schedule, err := cron.Parse(window.Schedule)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	location := time.UTC
+	if window.TimeZone != "" {
+		if location, err = time.LoadLocation(window.TimeZone); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return schedule, location, nil
+}
+
+// IsInMaintenanceWindow checks if the passed time is inside one of the
+// maintenance windows of the cluster. When it is not, the beginning of
+// the next window is returned too, or the zero time if no window is
+// expected to open in the future. Clusters without maintenance windows
+// are always considered inside a window
+func (cluster *Cluster) IsInMaintenanceWindow(now time.Time) (bool, time.Time, error) {
+	if len(cluster.Spec.MaintenanceWindows) == 0 {
+		return true, time.Time{}, nil
+	}
+
+	var nextWindowStart time.Time
+	for _, window := range cluster.Spec.MaintenanceWindows {
+		schedule, location, err := window.ParseSchedule()
+		if err != nil {
+			return false, time.Time{}, fmt.Errorf("invalid maintenance window %q: %w", window.Schedule, err)
+		}
+
+		localNow := now.In(location)
+
+		// The window is open if it began in the last window.Duration
+		if lastStart := schedule.Next(localNow.Add(-window.Duration.Duration)); !lastStart.IsZero() &&
+			!lastStart.After(localNow) {
+			return true, time.Time{}, nil
+		}
+
+		start := schedule.Next(localNow)
+		if start.IsZero() {
+			continue
+		}
+		if nextWindowStart.IsZero() || start.Before(nextWindowStart) {
+			nextWindowStart = start
+		}
+	}
+
+	return false, nextWindowStart, nil
+}
+
+// ShouldWaitForMaintenanceWindow checks if the rollout of an instance
+// needs to wait for the next maintenance window
+func (cluster *Cluster) ShouldWaitForMaintenanceWindow(isPrimary bool, now time.Time) (bool, time.Time, error) {
+	if !isPrimary && cluster.Spec.AllowReplicaUpdatesOutsideMaintenanceWindows {
+		return false, time.Time{}, nil
+	}
+
+	inWindow, nextWindowStart, err := cluster.IsInMaintenanceWindow(now)
+	return !inWindow, nextWindowStart, err
+}
+
 // GetEnablePDB get the cluster EnablePDB value, defaults to true
 func (cluster *Cluster) GetEnablePDB() bool {
 	if cluster.Spec.EnablePDB == nil {
@@ -1255,6 +1507,116 @@ func (cluster *Cluster) GetMetricsQueriesTTL() metav1.Duration {
 	}
 }
 
+// defaultMetricsQueriesParallelism is the default number of monitoring
+// queries executed concurrently
+const defaultMetricsQueriesParallelism = 4
+
+// GetMetricsQueriesParallelism returns the maximum number of monitoring
+// queries executed concurrently
+func (cluster *Cluster) GetMetricsQueriesParallelism() int {
+	if cluster.Spec.Monitoring == nil || cluster.Spec.Monitoring.MetricsQueriesParallelism <= 0 {
+		return defaultMetricsQueriesParallelism
+	}
+
+	return int(cluster.Spec.Monitoring.MetricsQueriesParallelism)
+}
+
+// defaultQueryStatisticsTopN is the default number of statements exported
+// by the query statistics collector
+const defaultQueryStatisticsTopN = 20
+
+// GetQueryStatistics returns the configuration of the query statistics
+// exporter, or nil if it has not been enabled
+func (cluster *Cluster) GetQueryStatistics() *QueryStatisticsConfiguration {
+	if cluster.Spec.Monitoring == nil || cluster.Spec.Monitoring.QueryStatistics == nil ||
+		!cluster.Spec.Monitoring.QueryStatistics.Enabled {
+		return nil
+	}
+
+	return cluster.Spec.Monitoring.QueryStatistics
+}
+
+// GetTopN returns the maximum number of statements to be exported,
+// defaulting to 20
+func (config *QueryStatisticsConfiguration) GetTopN() int {
+	if config == nil || config.TopN <= 0 {
+		return defaultQueryStatisticsTopN
+	}
+
+	return int(config.TopN)
+}
+
+// GetSortBy returns the criterion used to rank the statements,
+// defaulting to the total execution time
+func (config *QueryStatisticsConfiguration) GetSortBy() QueryStatisticsSortKey {
+	if config == nil || config.SortBy == "" {
+		return QueryStatisticsSortByTotalTime
+	}
+
+	return config.SortBy
+}
+
+// GetOTLP returns the configuration of the OTLP exporter, or nil if
+// it has not been configured
+func (cluster *Cluster) GetOTLP() *OTLPConfiguration {
+	if cluster.Spec.Monitoring == nil {
+		return nil
+	}
+
+	return cluster.Spec.Monitoring.OTLP
+}
+
+// IsMetricsEnabled returns true if the metrics should be exported via OTLP
+func (config *OTLPConfiguration) IsMetricsEnabled() bool {
+	return config != nil && (config.Metrics == nil || *config.Metrics)
+}
+
+// IsLogsEnabled returns true if the PostgreSQL logs should be exported via OTLP
+func (config *OTLPConfiguration) IsLogsEnabled() bool {
+	return config != nil && (config.Logs == nil || *config.Logs)
+}
+
+// IsTracesEnabled returns true if the operator should export traces via OTLP
+func (config *OTLPConfiguration) IsTracesEnabled() bool {
+	return config != nil && (config.Traces == nil || *config.Traces)
+}
+
+// GetExportInterval returns the interval between two exports of the
+// metrics, defaulting to 30 seconds
+func (config *OTLPConfiguration) GetExportInterval() time.Duration {
+	if config == nil || config.ExportInterval == nil || config.ExportInterval.Duration <= 0 {
+		return 30 * time.Second
+	}
+
+	return config.ExportInterval.Duration
+}
+
+// defaultSlowQueryLogMaxFileSize is the size after which the slow
+// query log file is rotated
+const defaultSlowQueryLogMaxFileSize = 100 * 1024 * 1024
+
+// GetSlowQueryLogDestination returns where the statement duration
+// records and the auto_explain plans are written
+func (cluster *Cluster) GetSlowQueryLogDestination() SlowQueryLogDestination {
+	if cluster.Spec.Monitoring == nil || cluster.Spec.Monitoring.SlowQueryLog == nil ||
+		cluster.Spec.Monitoring.SlowQueryLog.Destination == "" {
+		return SlowQueryLogDestinationStdout
+	}
+
+	return cluster.Spec.Monitoring.SlowQueryLog.Destination
+}
+
+// GetSlowQueryLogMaxFileSize returns the size in bytes after which
+// the slow query log file is rotated
+func (cluster *Cluster) GetSlowQueryLogMaxFileSize() int64 {
+	if cluster.Spec.Monitoring == nil || cluster.Spec.Monitoring.SlowQueryLog == nil ||
+		cluster.Spec.Monitoring.SlowQueryLog.MaxFileSize == nil {
+		return defaultSlowQueryLogMaxFileSize
+	}
+
+	return cluster.Spec.Monitoring.SlowQueryLog.MaxFileSize.Value()
+}
+
 // GetEnableSuperuserAccess returns if the superuser access is enabled or not
 func (cluster *Cluster) GetEnableSuperuserAccess() bool {
 	if cluster.Spec.EnableSuperuserAccess != nil {
@@ -1392,6 +1754,75 @@ func (backupConfiguration *BackupConfiguration) IsBarmanBackupConfigured() bool
 		backupConfiguration.BarmanObjectStore.ArePopulated()
 }
 
+// IsPgBaseBackupConfigured returns true if the volume for the
+// pgBaseBackup backup method is configured, false otherwise
+func (backupConfiguration *BackupConfiguration) IsPgBaseBackupConfigured() bool {
+	return backupConfiguration != nil && backupConfiguration.PgBaseBackup != nil &&
+		backupConfiguration.PgBaseBackup.ClaimName != ""
+}
+
+// IsLogicalBackupConfigured returns true if the volume for the
+// logical backup method is configured, false otherwise
+func (backupConfiguration *BackupConfiguration) IsLogicalBackupConfigured() bool {
+	return backupConfiguration != nil && backupConfiguration.Logical != nil &&
+		backupConfiguration.Logical.ClaimName != ""
+}
+
+// GetLogicalBackupCredentialsSecretName returns the name of the secret
+// containing the credentials used to take logical backups
+func (cluster *Cluster) GetLogicalBackupCredentialsSecretName() string {
+	if cluster.Spec.Backup.IsLogicalBackupConfigured() && cluster.Spec.Backup.Logical.Credentials != nil {
+		return cluster.Spec.Backup.Logical.Credentials.Name
+	}
+
+	return cluster.GetSuperuserSecretName()
+}
+
+// backupVerificationSourceName is the name of the external cluster
+// used to replay the WAL archive of the cluster while verifying a
+// volume snapshot backup
+const backupVerificationSourceName = "backup-verification-source"
+
+// GetBackupVerificationCluster returns a copy of the cluster that restores
+// the passed backup up to its end, detached from the original cluster: the
+// backup and replica cluster configurations are removed, so that the
+// restored instance never archives WAL files or streams from the cluster.
+// Volume snapshot backups replay the WAL archive of the cluster, when it
+// is stored in an object store
+func (cluster *Cluster) GetBackupVerificationCluster(backup *Backup) *Cluster {
+	result := cluster.DeepCopy()
+
+	recovery := &BootstrapRecovery{
+		RecoveryTarget: &RecoveryTarget{
+			TargetImmediate: ptr.To(true),
+		},
+	}
+
+	switch {
+	case backup.Spec.Method != BackupMethodVolumeSnapshot:
+		recovery.Backup = &BackupSource{
+			LocalObjectReference: LocalObjectReference{Name: backup.Name},
+		}
+
+	case result.Spec.Backup.IsBarmanBackupConfigured():
+		objectStore := result.Spec.Backup.BarmanObjectStore.DeepCopy()
+		if objectStore.ServerName == "" {
+			objectStore.ServerName = cluster.Name
+		}
+		result.Spec.ExternalClusters = append(result.Spec.ExternalClusters, ExternalCluster{
+			Name:              backupVerificationSourceName,
+			BarmanObjectStore: objectStore,
+		})
+		recovery.Source = backupVerificationSourceName
+	}
+
+	result.Spec.Bootstrap = &BootstrapConfiguration{Recovery: recovery}
+	result.Spec.Backup = nil
+	result.Spec.ReplicaCluster = nil
+
+	return result
+}
+
 // IsBarmanEndpointCASet returns true if we have a CA bundle for the endpoint
 // false otherwise
 func (backupConfiguration *BackupConfiguration) IsBarmanEndpointCASet() bool {
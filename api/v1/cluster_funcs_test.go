@@ -345,6 +345,84 @@ var _ = Describe("resize in use volumes", func() {
 	})
 })
 
+var _ = Describe("storage autoscale", func() {
+	It("uses the default values", func() {
+		autoscale := StorageAutoscaleConfiguration{}
+		Expect(autoscale.GetUsageThreshold()).To(Equal(80))
+		Expect(autoscale.GetCooldown()).To(Equal(5 * time.Minute))
+	})
+
+	DescribeTable("computes the next size of a PVC",
+		func(increment, currentSize, expectedSize string) {
+			autoscale := StorageAutoscaleConfiguration{Increment: increment, MaxSize: "100Gi"}
+			nextSize, err := autoscale.GetNextSize(resource.MustParse(currentSize))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nextSize.Cmp(resource.MustParse(expectedSize))).To(BeZero(), nextSize.String())
+		},
+		Entry("with the default increment", "", "10Gi", "12Gi"),
+		Entry("with a percentage, rounding up to MiB", "10%", "1G", "1050Mi"),
+		Entry("with a quantity", "5Gi", "10Gi", "15Gi"),
+		Entry("without exceeding the maximum size", "50%", "90Gi", "100Gi"),
+		Entry("when the maximum size is already reached", "5Gi", "100Gi", "100Gi"),
+	)
+
+	DescribeTable("rejects invalid configurations",
+		func(autoscale StorageAutoscaleConfiguration) {
+			_, err := autoscale.GetNextSize(resource.MustParse("1Gi"))
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("with an invalid maximum size", StorageAutoscaleConfiguration{MaxSize: "a lot"}),
+		Entry("with a zero percentage", StorageAutoscaleConfiguration{MaxSize: "10Gi", Increment: "0%"}),
+		Entry("with an invalid percentage", StorageAutoscaleConfiguration{MaxSize: "10Gi", Increment: "ten%"}),
+		Entry("with a negative quantity", StorageAutoscaleConfiguration{MaxSize: "10Gi", Increment: "-1Gi"}),
+	)
+})
+
+var _ = Describe("synchronous replication requirements", func() {
+	It("requires only the primary without synchronous replication", func() {
+		Expect((&Cluster{}).GetMinInstancesForSynchronousReplication()).To(Equal(1))
+	})
+
+	It("considers maxSyncReplicas", func() {
+		cluster := Cluster{Spec: ClusterSpec{MaxSyncReplicas: 2}}
+		Expect(cluster.GetMinInstancesForSynchronousReplication()).To(Equal(3))
+	})
+
+	It("considers the required synchronous standbys", func() {
+		cluster := Cluster{Spec: ClusterSpec{PostgresConfiguration: PostgresConfiguration{
+			Synchronous: &SynchronousReplicaConfiguration{Number: 2},
+		}}}
+		Expect(cluster.GetMinInstancesForSynchronousReplication()).To(Equal(3))
+
+		cluster.Spec.PostgresConfiguration.Synchronous.DataDurability = DataDurabilityLevelPreferred
+		Expect(cluster.GetMinInstancesForSynchronousReplication()).To(Equal(1))
+	})
+})
+
+var _ = Describe("switchover drain configuration", func() {
+	It("is disabled by default", func() {
+		cluster := Cluster{}
+		Expect(cluster.IsSwitchoverDrainEnabled()).To(BeFalse())
+		Expect(cluster.Spec.SwitchoverDrain.ShouldPausePoolers()).To(BeFalse())
+	})
+
+	It("pauses the poolers and waits 30 seconds by default", func() {
+		cluster := Cluster{Spec: ClusterSpec{SwitchoverDrain: &SwitchoverDrainConfiguration{}}}
+		Expect(cluster.IsSwitchoverDrainEnabled()).To(BeTrue())
+		Expect(cluster.Spec.SwitchoverDrain.ShouldPausePoolers()).To(BeTrue())
+		Expect(cluster.Spec.SwitchoverDrain.GetTimeout()).To(Equal(30 * time.Second))
+	})
+
+	It("honors the user configuration", func() {
+		drain := &SwitchoverDrainConfiguration{
+			PausePoolers: ptr.To(false),
+			Timeout:      &metav1.Duration{Duration: 2 * time.Minute},
+		}
+		Expect(drain.ShouldPausePoolers()).To(BeFalse())
+		Expect(drain.GetTimeout()).To(Equal(2 * time.Minute))
+	})
+})
+
 var _ = Describe("external cluster list", func() {
 	emptyCluster := &Cluster{}
 	cluster := Cluster{
@@ -2039,3 +2117,248 @@ var _ = Describe("FailureDomainKeys", func() {
 		Expect(sync.FailureDomainKeys()).To(Equal([]string{"topology.kubernetes.io/zone"}))
 	})
 })
+
+var _ = Describe("Maintenance windows", func() {
+	// Saturday, 7 March 2026, 12:00 UTC
+	saturdayNoon := time.Date(2026, time.March, 7, 12, 0, 0, 0, time.UTC)
+
+	newCluster := func(windows ...MaintenanceWindow) *Cluster {
+		return &Cluster{Spec: ClusterSpec{MaintenanceWindows: windows}}
+	}
+
+	It("considers clusters without maintenance windows always inside a window", func() {
+		inWindow, nextWindowStart, err := newCluster().IsInMaintenanceWindow(saturdayNoon)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inWindow).To(BeTrue())
+		Expect(nextWindowStart).To(BeZero())
+	})
+
+	It("detects an open maintenance window", func() {
+		cluster := newCluster(MaintenanceWindow{
+			Schedule: "0 0 10 * * 6",
+			Duration: metav1.Duration{Duration: 4 * time.Hour},
+		})
+		inWindow, _, err := cluster.IsInMaintenanceWindow(saturdayNoon)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inWindow).To(BeTrue())
+	})
+
+	It("returns the beginning of the next maintenance window", func() {
+		cluster := newCluster(
+			MaintenanceWindow{
+				Schedule: "0 0 2 * * 6",
+				Duration: metav1.Duration{Duration: time.Hour},
+			},
+			MaintenanceWindow{
+				Schedule: "0 0 22 * * *",
+				Duration: metav1.Duration{Duration: time.Hour},
+			},
+		)
+		inWindow, nextWindowStart, err := cluster.IsInMaintenanceWindow(saturdayNoon)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inWindow).To(BeFalse())
+		Expect(nextWindowStart).To(BeTemporally("==", saturdayNoon.Add(10*time.Hour)))
+	})
+
+	It("evaluates the schedule in the configured time zone", func() {
+		// 10:00 in New York is 15:00 UTC on 7 March 2026 (EST)
+		cluster := newCluster(MaintenanceWindow{
+			Schedule: "0 0 10 * * 6",
+			Duration: metav1.Duration{Duration: 4 * time.Hour},
+			TimeZone: "America/New_York",
+		})
+		inWindow, nextWindowStart, err := cluster.IsInMaintenanceWindow(saturdayNoon)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inWindow).To(BeFalse())
+		Expect(nextWindowStart).To(BeTemporally("==", saturdayNoon.Add(3*time.Hour)))
+	})
+
+	It("fails with an invalid schedule", func() {
+		_, _, err := newCluster(MaintenanceWindow{Schedule: "whenever"}).IsInMaintenanceWindow(saturdayNoon)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("lets replicas be updated outside the windows when requested", func() {
+		cluster := newCluster(MaintenanceWindow{
+			Schedule: "0 0 2 * * 6",
+			Duration: metav1.Duration{Duration: time.Hour},
+		})
+		cluster.Spec.AllowReplicaUpdatesOutsideMaintenanceWindows = true
+
+		mustWait, _, err := cluster.ShouldWaitForMaintenanceWindow(false, saturdayNoon)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mustWait).To(BeFalse())
+
+		mustWait, _, err = cluster.ShouldWaitForMaintenanceWindow(true, saturdayNoon)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mustWait).To(BeTrue())
+	})
+})
+
+var _ = Describe("GetBackupVerificationCluster", func() {
+	cluster := &Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+		Spec: ClusterSpec{
+			Backup: &BackupConfiguration{
+				BarmanObjectStore: &BarmanObjectStoreConfiguration{
+					DestinationPath: "s3://backups/",
+					BarmanCredentials: BarmanCredentials{
+						AWS: &S3Credentials{InheritFromIAMRole: true},
+					},
+				},
+			},
+			ReplicaCluster: &ReplicaClusterConfiguration{Source: "origin"},
+		},
+	}
+
+	It("recovers from the backup object, detached from the cluster", func() {
+		backup := &Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup-example"},
+			Spec:       BackupSpec{Method: BackupMethodBarmanObjectStore},
+		}
+		result := cluster.GetBackupVerificationCluster(backup)
+		Expect(result.Spec.Backup).To(BeNil())
+		Expect(result.Spec.ReplicaCluster).To(BeNil())
+		Expect(result.Spec.Bootstrap.Recovery.Backup.Name).To(Equal("backup-example"))
+		Expect(result.Spec.Bootstrap.Recovery.RecoveryTarget.TargetImmediate).To(HaveValue(BeTrue()))
+		Expect(cluster.Spec.Backup).ToNot(BeNil())
+	})
+
+	It("replays the archived WAL after restoring the volume snapshots", func() {
+		backup := &Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup-example"},
+			Spec:       BackupSpec{Method: BackupMethodVolumeSnapshot},
+		}
+		result := cluster.GetBackupVerificationCluster(backup)
+		Expect(result.Spec.Bootstrap.Recovery.Backup).To(BeNil())
+		Expect(result.Spec.Bootstrap.Recovery.Source).To(Equal(backupVerificationSourceName))
+
+		source, ok := result.ExternalCluster(backupVerificationSourceName)
+		Expect(ok).To(BeTrue())
+		Expect(source.BarmanObjectStore.DestinationPath).To(Equal("s3://backups/"))
+		Expect(source.BarmanObjectStore.ServerName).To(Equal("cluster-example"))
+		Expect(cluster.Spec.ExternalClusters).To(BeEmpty())
+	})
+})
+
+var _ = Describe("VolumeSnapshotRetentionPolicy", func() {
+	now := time.Date(2025, time.March, 31, 12, 0, 0, 0, time.UTC)
+
+	It("detects empty policies", func() {
+		var policy *VolumeSnapshotRetentionPolicy
+		Expect(policy.IsEmpty()).To(BeTrue())
+		Expect((&VolumeSnapshotRetentionPolicy{}).IsEmpty()).To(BeTrue())
+		Expect((&VolumeSnapshotRetentionPolicy{KeepLast: ptr.To(3)}).IsEmpty()).To(BeFalse())
+	})
+
+	DescribeTable("computes the start of the recovery window",
+		func(keepWithin string, expected time.Time) {
+			policy := &VolumeSnapshotRetentionPolicy{KeepWithin: keepWithin}
+			windowStart, err := policy.GetWindowStart(now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*windowStart).To(Equal(expected))
+		},
+		Entry("days", "3d", time.Date(2025, time.March, 28, 12, 0, 0, 0, time.UTC)),
+		Entry("weeks", "2w", time.Date(2025, time.March, 17, 12, 0, 0, 0, time.UTC)),
+		Entry("months", "1m", time.Date(2025, time.March, 3, 12, 0, 0, 0, time.UTC)),
+	)
+
+	It("has no recovery window when not requested", func() {
+		windowStart, err := (&VolumeSnapshotRetentionPolicy{KeepLast: ptr.To(1)}).GetWindowStart(now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(windowStart).To(BeNil())
+	})
+
+	It("fails with an invalid recovery window", func() {
+		_, err := (&VolumeSnapshotRetentionPolicy{KeepWithin: "1y"}).GetWindowStart(now)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("query statistics configuration", func() {
+	It("is disabled by default", func() {
+		cluster := &Cluster{}
+		Expect(cluster.GetQueryStatistics()).To(BeNil())
+
+		cluster.Spec.Monitoring = &MonitoringConfiguration{
+			QueryStatistics: &QueryStatisticsConfiguration{TopN: 5},
+		}
+		Expect(cluster.GetQueryStatistics()).To(BeNil())
+	})
+
+	It("applies the defaults", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Monitoring: &MonitoringConfiguration{
+					QueryStatistics: &QueryStatisticsConfiguration{Enabled: true},
+				},
+			},
+		}
+		config := cluster.GetQueryStatistics()
+		Expect(config).ToNot(BeNil())
+		Expect(config.GetTopN()).To(Equal(20))
+		Expect(config.GetSortBy()).To(Equal(QueryStatisticsSortByTotalTime))
+	})
+
+	It("honors the configured values", func() {
+		config := &QueryStatisticsConfiguration{Enabled: true, TopN: 7, SortBy: QueryStatisticsSortByIO}
+		Expect(config.GetTopN()).To(Equal(7))
+		Expect(config.GetSortBy()).To(Equal(QueryStatisticsSortByIO))
+	})
+})
+
+var _ = Describe("OTLP configuration", func() {
+	It("is disabled when not configured", func() {
+		cluster := &Cluster{}
+		config := cluster.GetOTLP()
+		Expect(config).To(BeNil())
+		Expect(config.IsMetricsEnabled()).To(BeFalse())
+		Expect(config.IsLogsEnabled()).To(BeFalse())
+		Expect(config.IsTracesEnabled()).To(BeFalse())
+	})
+
+	It("enables every signal by default", func() {
+		config := &OTLPConfiguration{Endpoint: "collector:4317"}
+		Expect(config.IsMetricsEnabled()).To(BeTrue())
+		Expect(config.IsLogsEnabled()).To(BeTrue())
+		Expect(config.IsTracesEnabled()).To(BeTrue())
+		Expect(config.GetExportInterval()).To(Equal(30 * time.Second))
+	})
+
+	It("honors the configured values", func() {
+		config := &OTLPConfiguration{
+			Endpoint:       "collector:4317",
+			Metrics:        ptr.To(false),
+			Traces:         ptr.To(false),
+			ExportInterval: &metav1.Duration{Duration: time.Minute},
+		}
+		Expect(config.IsMetricsEnabled()).To(BeFalse())
+		Expect(config.IsLogsEnabled()).To(BeTrue())
+		Expect(config.IsTracesEnabled()).To(BeFalse())
+		Expect(config.GetExportInterval()).To(Equal(time.Minute))
+	})
+})
+
+var _ = Describe("Slow query log configuration", func() {
+	It("writes to the standard output by default", func() {
+		cluster := &Cluster{}
+		Expect(cluster.GetSlowQueryLogDestination()).To(Equal(SlowQueryLogDestinationStdout))
+		Expect(cluster.GetSlowQueryLogMaxFileSize()).To(BeEquivalentTo(100 * 1024 * 1024))
+	})
+
+	It("honors the configured values", func() {
+		maxFileSize := resource.MustParse("10Mi")
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Monitoring: &MonitoringConfiguration{
+					SlowQueryLog: &SlowQueryLogConfiguration{
+						Destination: SlowQueryLogDestinationFile,
+						MaxFileSize: &maxFileSize,
+					},
+				},
+			},
+		}
+		Expect(cluster.GetSlowQueryLogDestination()).To(Equal(SlowQueryLogDestinationFile))
+		Expect(cluster.GetSlowQueryLogMaxFileSize()).To(BeEquivalentTo(10 * 1024 * 1024))
+	})
+})
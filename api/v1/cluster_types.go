@@ -170,6 +170,50 @@ type VolumeSnapshotConfiguration struct {
 	// +kubebuilder:default:={waitForArchive:true,immediateCheckpoint:false}
 	// +optional
 	OnlineConfiguration OnlineConfiguration `json:"onlineConfiguration,omitempty"`
+
+	// RetentionPolicy defines which backups taken with volume snapshots
+	// are kept by the operator. When not set, backups are never removed
+	// +optional
+	RetentionPolicy *VolumeSnapshotRetentionPolicy `json:"retentionPolicy,omitempty"`
+}
+
+// VolumeSnapshotRetentionPolicy defines the rules deciding which backups
+// taken with volume snapshots are kept. A backup is kept when at least one
+// rule selects it, while the others are removed together with their
+// snapshots. The most recent backup is always kept
+type VolumeSnapshotRetentionPolicy struct {
+	// KeepLast is the number of most recent backups to keep
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	KeepLast *int `json:"keepLast,omitempty"`
+
+	// KeepWithin is the recovery window to keep backups for (i.e. '30d'),
+	// expressed in the form of `XXu` where `XX` is a positive integer
+	// and `u` is in `[dwm]` - days, weeks, months. Together with the
+	// backups completed in the window, the most recent backup completed
+	// before the window start is kept, as it is needed to recover to
+	// any point of the window
+	// +kubebuilder:validation:Pattern=^[1-9][0-9]*[dwm]$
+	// +optional
+	KeepWithin string `json:"keepWithin,omitempty"`
+
+	// KeepDaily is the number of days for which the most recent
+	// backup of the day is kept
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	KeepDaily *int `json:"keepDaily,omitempty"`
+
+	// KeepWeekly is the number of weeks for which the most recent
+	// backup of the week is kept
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	KeepWeekly *int `json:"keepWeekly,omitempty"`
+
+	// KeepMonthly is the number of months for which the most recent
+	// backup of the month is kept
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	KeepMonthly *int `json:"keepMonthly,omitempty"`
 }
 
 // OnlineConfiguration contains the configuration parameters for the online volume snapshot
@@ -274,6 +318,12 @@ type ClusterSpec struct {
 	// +optional
 	MaxSyncReplicas int `json:"maxSyncReplicas,omitempty"`
 
+	// Autoscaling configures the operator to change the number of instances
+	// depending on the load of the replicas. When enabled, the operator
+	// manages the `instances` field
+	// +optional
+	Autoscaling *ReplicaAutoscalingConfiguration `json:"autoscaling,omitempty"`
+
 	// Configuration of the PostgreSQL server
 	// +optional
 	PostgresConfiguration PostgresConfiguration `json:"postgresql,omitempty"`
@@ -379,6 +429,12 @@ type ClusterSpec struct {
 	// +optional
 	MaxSwitchoverDelay int32 `json:"switchoverDelay,omitempty"`
 
+	// SwitchoverDrain enables draining the client connections before a
+	// switchover, pausing every Pooler pointing to the cluster and waiting
+	// for the active transactions on the primary to complete
+	// +optional
+	SwitchoverDrain *SwitchoverDrainConfiguration `json:"switchoverDrain,omitempty"`
+
 	// The amount of time (in seconds) to wait before triggering a failover
 	// after the primary PostgreSQL instance in the cluster was detected
 	// to be unhealthy
@@ -440,6 +496,19 @@ type ClusterSpec struct {
 	// +optional
 	PrimaryUpdateMethod PrimaryUpdateMethod `json:"primaryUpdateMethod,omitempty"`
 
+	// Recurring time windows in which the operator is allowed to perform
+	// disruptive operations, like the rolling update of the instances and
+	// in-place major version upgrades. When empty, these operations are
+	// performed as soon as they are required
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// When set to true, replicas are updated as soon as required even
+	// outside the maintenance windows, which only gate the update of
+	// the primary instance
+	// +optional
+	AllowReplicaUpdatesOutsideMaintenanceWindows bool `json:"allowReplicaUpdatesOutsideMaintenanceWindows,omitempty"`
+
 	// The configuration to be used for backups
 	// +optional
 	Backup *BackupConfiguration `json:"backup,omitempty"`
@@ -717,6 +786,10 @@ const (
 	// but the operation is being delayed by the operator configuration
 	PhaseUpgradeDelayed = "Cluster upgrade delayed"
 
+	// PhaseWaitingForMaintenanceWindow is set when a cluster needs to be
+	// upgraded, but the operation is waiting for the next maintenance window
+	PhaseWaitingForMaintenanceWindow = "Waiting for the next maintenance window"
+
 	// PhaseWaitingForUser set the status to wait for an action from the user
 	PhaseWaitingForUser = "Waiting for user action"
 
@@ -908,6 +981,16 @@ type ClusterStatus struct {
 	// +optional
 	Selector string `json:"selector,omitempty"`
 
+	// Autoscaling reports the last decision taken by the
+	// read-replica autoscaler
+	// +optional
+	Autoscaling *ReplicaAutoscalingStatus `json:"autoscaling,omitempty"`
+
+	// SwitchoverDrain reports the progress of the connection draining
+	// that precedes a switchover, when enabled
+	// +optional
+	SwitchoverDrain *SwitchoverDrainStatus `json:"switchoverDrain,omitempty"`
+
 	// InstancesStatus indicates in which status the instances are
 	// +optional
 	InstancesStatus map[PodStatus][]string `json:"instancesStatus,omitempty"`
@@ -1190,6 +1273,11 @@ const (
 	// or .spec.postgresql.synchronous.nodeFailureDomainKeys.
 	// Only set when one of those fields is configured.
 	ConditionSyncReplicationTopologySatisfied ClusterConditionType = "SyncReplicationTopologySatisfied"
+
+	// ConditionMaintenancePending is True when a disruptive operation
+	// is required but waiting for the next maintenance window.
+	// Only set when .spec.maintenanceWindows is configured.
+	ConditionMaintenancePending ClusterConditionType = "MaintenancePending"
 )
 
 // ConditionStatus defines conditions of resources
@@ -1236,6 +1324,10 @@ const (
 	// ClusterIsNotReady means that the condition changed because the cluster is not ready
 	ClusterIsNotReady ConditionReason = "ClusterIsNotReady"
 
+	// WaitingForMaintenanceWindow means that a disruptive operation is
+	// required but the cluster is outside its maintenance windows
+	WaitingForMaintenanceWindow ConditionReason = "WaitingForMaintenanceWindow"
+
 	// DetachedVolume is the reason that is set when we do a rolling upgrade to add a PVC volume to a cluster
 	DetachedVolume ConditionReason = "DetachedVolume"
 
@@ -1432,6 +1524,23 @@ type NodeMaintenanceWindow struct {
 	InProgress bool `json:"inProgress,omitempty"`
 }
 
+// MaintenanceWindow is a recurring time window in which the operator is
+// allowed to perform disruptive operations on the cluster
+type MaintenanceWindow struct {
+	// The schedule of the beginning of the window, in Cron format, including
+	// the seconds, as in ScheduledBackup. For example, `0 0 2 * * 6`
+	// opens a window every Saturday at 2 AM
+	Schedule string `json:"schedule"`
+
+	// How long the window stays open after its beginning
+	Duration metav1.Duration `json:"duration"`
+
+	// The time zone of the schedule, as an IANA time zone name
+	// like `Europe/Rome`. Defaults to `UTC`
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
 // PrimaryUpdateStrategy contains the strategy to follow when upgrading
 // the primary server of the cluster as part of rolling updates
 type PrimaryUpdateStrategy string
@@ -2327,6 +2436,131 @@ type StorageConfiguration struct {
 	// Template to be used to generate the Persistent Volume Claim
 	// +optional
 	PersistentVolumeClaimTemplate *corev1.PersistentVolumeClaimSpec `json:"pvcTemplate,omitempty"`
+
+	// Autoscale enables the automatic growth of the PVCs when the usage
+	// of their filesystem, as reported by the instances, goes above a
+	// threshold. The PVCs are never shrunk, and `size` is used as the
+	// initial size
+	// +optional
+	Autoscale *StorageAutoscaleConfiguration `json:"autoscale,omitempty"`
+}
+
+// StorageAutoscaleConfiguration configures the automatic growth of
+// the PVCs of a storage configuration
+type StorageAutoscaleConfiguration struct {
+	// The percentage of used space in the filesystem that triggers
+	// the growth of the PVC, defaults to 80
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	// +kubebuilder:default:=80
+	// +optional
+	UsageThreshold int `json:"usageThreshold,omitempty"`
+
+	// How much a PVC is grown every time the threshold is reached,
+	// either as a quantity (e.g. `10Gi`) or as a percentage of the
+	// current size (e.g. `20%`). Defaults to `20%`
+	// +kubebuilder:default:="20%"
+	// +optional
+	Increment string `json:"increment,omitempty"`
+
+	// The maximum size a PVC can be grown to
+	MaxSize string `json:"maxSize"`
+
+	// The minimum time between two growths of the same PVC, giving
+	// the storage provider the time to complete the expansion of the
+	// volume and of its filesystem. Defaults to 5 minutes
+	// +optional
+	Cooldown *metav1.Duration `json:"cooldown,omitempty"`
+}
+
+// ReplicaAutoscalingConfiguration is the configuration of the autoscaler
+// changing the number of instances depending on the load of the replicas
+// +kubebuilder:validation:XValidation:rule="self.maxInstances >= self.minInstances",message="maxInstances must be greater than or equal to minInstances"
+type ReplicaAutoscalingConfiguration struct {
+	// The minimum number of instances of the cluster, including the primary
+	// +kubebuilder:validation:Minimum=2
+	MinInstances int `json:"minInstances"`
+
+	// The maximum number of instances of the cluster, including the primary
+	// +kubebuilder:validation:Minimum=2
+	MaxInstances int `json:"maxInstances"`
+
+	// The target average CPU usage of the replicas, as a percentage of
+	// the CPU they requested
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetCPUUtilization *int `json:"targetCPUUtilization,omitempty"`
+
+	// The target average number of active connections per replica, which
+	// are the ones served by the `-ro` service
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetActiveConnections *int `json:"targetActiveConnections,omitempty"`
+
+	// The maximum replay lag of the replicas: when a replica lags behind
+	// the primary more than this, the cluster is scaled up, and never
+	// scaled down
+	// +optional
+	MaxReplicationLag *metav1.Duration `json:"maxReplicationLag,omitempty"`
+
+	// The minimum time between two scaling operations, defaulting to 5 minutes
+	// +optional
+	Cooldown *metav1.Duration `json:"cooldown,omitempty"`
+}
+
+// ReplicaAutoscalingStatus is the last decision taken by the read-replica autoscaler
+type ReplicaAutoscalingStatus struct {
+	// The time of the last change of the number of instances
+	// +optional
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
+
+	// The number of instances before the last scaling operation
+	// +optional
+	PreviousInstances int `json:"previousInstances,omitempty"`
+
+	// The number of instances requested by the last scaling operation
+	// +optional
+	DesiredInstances int `json:"desiredInstances,omitempty"`
+
+	// The reason of the last scaling operation
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// SwitchoverDrainConfiguration controls how client connections are drained
+// before the primary is switched over to another instance
+type SwitchoverDrainConfiguration struct {
+	// When set to true (default), every Pooler pointing to the cluster
+	// is paused before the switchover and resumed once the new primary
+	// has been promoted
+	// +kubebuilder:default:=true
+	// +optional
+	PausePoolers *bool `json:"pausePoolers,omitempty"`
+
+	// The maximum amount of time to wait for the active transactions
+	// on the primary to complete before proceeding with the switchover
+	// anyway. Defaults to 30s.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// SwitchoverDrainStatus is the status of the connection draining
+// preceding a switchover
+type SwitchoverDrainStatus struct {
+	// The instance that will be promoted once the draining is complete
+	TargetPrimary string `json:"targetPrimary"`
+
+	// The time when the draining started
+	StartedAt metav1.Time `json:"startedAt"`
+
+	// The names of the Poolers that have been paused
+	// +optional
+	PausedPoolers []string `json:"pausedPoolers,omitempty"`
+
+	// The time when the switchover has been requested to the instances,
+	// empty while the active transactions are being drained
+	// +optional
+	SwitchoverRequestedAt *metav1.Time `json:"switchoverRequestedAt,omitempty"`
 }
 
 // TablespaceConfiguration is the configuration of a tablespace, and includes
@@ -2448,11 +2682,23 @@ type BackupConfiguration struct {
 	// +optional
 	BarmanObjectStore *BarmanObjectStoreConfiguration `json:"barmanObjectStore,omitempty"`
 
+	// PgBaseBackup provides the configuration for the execution of backups
+	// with the `pgBaseBackup` method.
+	// +optional
+	PgBaseBackup *PgBaseBackupConfiguration `json:"pgBaseBackup,omitempty"`
+
+	// Logical provides the configuration for the execution of backups
+	// with the `logical` method.
+	// +optional
+	Logical *LogicalBackupConfiguration `json:"logical,omitempty"`
+
 	// RetentionPolicy is the retention policy to be used for backups
 	// and WALs (i.e. '60d'). The retention policy is expressed in the form
 	// of `XXu` where `XX` is a positive integer and `u` is in `[dwm]` -
 	// days, weeks, months.
-	// It's currently only applicable when using the BarmanObjectStore method.
+	// It's currently only applicable when using the BarmanObjectStore method:
+	// backups taken with volume snapshots are retained according to
+	// `.spec.backup.volumeSnapshot.retentionPolicy`.
 	// +kubebuilder:validation:Pattern=^[1-9][0-9]*[dwm]$
 	// +optional
 	RetentionPolicy string `json:"retentionPolicy,omitempty"`
@@ -2467,6 +2713,36 @@ type BackupConfiguration struct {
 	Target BackupTarget `json:"target,omitempty"`
 }
 
+// PgBaseBackupConfiguration represents the configuration for the execution
+// of backups with the `pgBaseBackup` method
+type PgBaseBackupConfiguration struct {
+	// The name of the PersistentVolumeClaim where the backups are stored.
+	// The volume is mounted in every instance of the cluster, so it needs
+	// to support the `ReadWriteMany` access mode when the cluster has more
+	// than one instance
+	// +kubebuilder:validation:MinLength=1
+	ClaimName string `json:"claimName"`
+}
+
+// LogicalBackupConfiguration represents the configuration for the execution
+// of backups with the `logical` method
+type LogicalBackupConfiguration struct {
+	// The name of the PersistentVolumeClaim where the dumps are stored.
+	// The volume is mounted in the jobs taking the backups and in every
+	// instance of the cluster, to restore the dumps into `Database`
+	// resources, so it needs to support the `ReadWriteMany` access mode
+	// +kubebuilder:validation:MinLength=1
+	ClaimName string `json:"claimName"`
+
+	// The secret, of type `kubernetes.io/basic-auth`, containing the
+	// credentials used by pg_dump to connect to the primary. The user must
+	// be able to read every object in the exported databases, for example
+	// being a member of `pg_read_all_data`. Defaults to the superuser
+	// secret, which requires `enableSuperuserAccess` to be `true`
+	// +optional
+	Credentials *LocalObjectReference `json:"credentials,omitempty"`
+}
+
 // MonitoringConfiguration is the type containing all the monitoring
 // configuration for a certain cluster
 type MonitoringConfiguration struct {
@@ -2519,6 +2795,143 @@ type MonitoringConfiguration struct {
 	// Setting this to zero disables the caching mechanism and can cause heavy load on the PostgreSQL server.
 	// +optional
 	MetricsQueriesTTL *metav1.Duration `json:"metricsQueriesTTL,omitempty"`
+
+	// The maximum number of monitoring queries executed concurrently while
+	// refreshing the metrics, counting each target database of a query
+	// separately. Defaults to 4.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=64
+	// +kubebuilder:default:=4
+	// +optional
+	MetricsQueriesParallelism int32 `json:"metricsQueriesParallelism,omitempty"`
+
+	// Configure the built-in exporter of query-level statistics
+	// gathered from the `pg_stat_statements` extension
+	// +optional
+	QueryStatistics *QueryStatisticsConfiguration `json:"queryStatistics,omitempty"`
+
+	// Configure the export of metrics, logs and traces to an
+	// OpenTelemetry collector via OTLP, in addition to the
+	// Prometheus metrics endpoint
+	// +optional
+	OTLP *OTLPConfiguration `json:"otlp,omitempty"`
+
+	// Configure where the statement duration records and the execution
+	// plans logged by `auto_explain` are written
+	// +optional
+	SlowQueryLog *SlowQueryLogConfiguration `json:"slowQueryLog,omitempty"`
+}
+
+// SlowQueryLogDestination is where the statement duration records
+// and the `auto_explain` plans are written
+// +kubebuilder:validation:Enum=stdout;file
+type SlowQueryLogDestination string
+
+const (
+	// SlowQueryLogDestinationStdout writes the records to the standard
+	// output of the instance manager, together with the other logs
+	SlowQueryLogDestinationStdout SlowQueryLogDestination = "stdout"
+
+	// SlowQueryLogDestinationFile writes the records to a dedicated
+	// file in the log directory of the instance
+	SlowQueryLogDestinationFile SlowQueryLogDestination = "file"
+)
+
+// SlowQueryLogConfiguration contains the configuration of the routing
+// of the statement duration records, emitted when
+// `log_min_duration_statement` is set, and of the execution plans logged
+// by `auto_explain`
+type SlowQueryLogConfiguration struct {
+	// Where the records are written: `stdout`, as distinct
+	// `slow_query` and `auto_explain` loggers, or `file`, as JSON
+	// lines in the `slow_queries.json` file of the log directory
+	// +kubebuilder:default:=stdout
+	// +optional
+	Destination SlowQueryLogDestination `json:"destination,omitempty"`
+
+	// The size after which the file is rotated, keeping only the
+	// previous one. Defaults to `100Mi`.
+	// +optional
+	MaxFileSize *resource.Quantity `json:"maxFileSize,omitempty"`
+}
+
+// OTLPConfiguration contains the configuration of the OpenTelemetry
+// Protocol (OTLP) exporter. Metrics and PostgreSQL logs are pushed by
+// the instance manager, while traces of the reconciliation loops are
+// sent by the operator.
+type OTLPConfiguration struct {
+	// The address of the OTLP/gRPC receiver, in the `host:port` format
+	// +kubebuilder:validation:MinLength=1
+	Endpoint string `json:"endpoint"`
+
+	// Whether to connect to the receiver without TLS
+	// +kubebuilder:default:=false
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+
+	// Whether the metrics of the instances, gathered from the same
+	// collectors of the Prometheus endpoint, should be exported
+	// +kubebuilder:default:=true
+	// +optional
+	Metrics *bool `json:"metrics,omitempty"`
+
+	// Whether the PostgreSQL logs, including pgaudit records,
+	// should be exported
+	// +kubebuilder:default:=true
+	// +optional
+	Logs *bool `json:"logs,omitempty"`
+
+	// Whether the operator should export the traces of the
+	// reconciliation loops and of the calls to the instance manager
+	// +kubebuilder:default:=true
+	// +optional
+	Traces *bool `json:"traces,omitempty"`
+
+	// The interval between two exports of the metrics.
+	// If not set, defaults to 30 seconds.
+	// +optional
+	ExportInterval *metav1.Duration `json:"exportInterval,omitempty"`
+}
+
+// QueryStatisticsSortKey is the criterion used to rank the statements
+// exported by the query statistics collector
+// +kubebuilder:validation:Enum=totalTime;calls;io
+type QueryStatisticsSortKey string
+
+const (
+	// QueryStatisticsSortByTotalTime ranks statements by their total execution time
+	QueryStatisticsSortByTotalTime QueryStatisticsSortKey = "totalTime"
+
+	// QueryStatisticsSortByCalls ranks statements by the number of times they were executed
+	QueryStatisticsSortByCalls QueryStatisticsSortKey = "calls"
+
+	// QueryStatisticsSortByIO ranks statements by the number of blocks they read and wrote
+	QueryStatisticsSortByIO QueryStatisticsSortKey = "io"
+)
+
+// QueryStatisticsConfiguration contains the configuration of the
+// exporter of query-level statistics. Only the top statements, ranked
+// by the chosen sort key, are exported to keep the cardinality of the
+// metrics bounded. The text of the statements is never exported as a
+// label, and can be retrieved from the instance manager by `queryid`.
+type QueryStatisticsConfiguration struct {
+	// Whether the statistics from `pg_stat_statements` should be exported.
+	// The extension must be installed in the `postgres` database.
+	// +kubebuilder:default:=false
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The maximum number of statements to be exported
+	// +kubebuilder:default:=20
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=500
+	// +optional
+	TopN int32 `json:"topN,omitempty"`
+
+	// The criterion used to rank the statements: `totalTime`, `calls` or `io`
+	// +kubebuilder:default:=totalTime
+	// +optional
+	SortBy QueryStatisticsSortKey `json:"sortBy,omitempty"`
 }
 
 // ClusterMonitoringTLSConfiguration is the type containing the TLS configuration
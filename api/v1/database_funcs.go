@@ -20,6 +20,10 @@ SPDX-License-Identifier: Apache-2.0
 package v1
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/cloudnative-pg/machinery/pkg/stringset"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/utils/ptr"
 )
@@ -94,6 +98,77 @@ func (dbObject DatabaseObjectSpec) GetName() string {
 	return dbObject.Name
 }
 
+// GetEnsure gets the ensure status of the privileges
+func (grant GrantSpec) GetEnsure() EnsureOption {
+	return grant.Ensure
+}
+
+// GetName gets a name identifying the privileges in the status,
+// like "SELECT,INSERT on table public.* to app"
+func (grant GrantSpec) GetName() string {
+	target := ""
+	switch grant.ObjectType {
+	case PrivilegeObjectTypeDatabase:
+	case PrivilegeObjectTypeSchema:
+		target = " " + grant.Schema
+	default:
+		objectName := grant.ObjectName
+		if objectName == "" {
+			objectName = "*"
+		}
+		target = fmt.Sprintf(" %s.%s", grant.Schema, objectName)
+	}
+
+	return fmt.Sprintf("%s on %s%s to %s",
+		joinPrivileges(grant.Privileges), grant.ObjectType, target, grant.Role)
+}
+
+// GetEnsure gets the ensure status of the default privileges
+func (defaultPrivilege DefaultPrivilegeSpec) GetEnsure() EnsureOption {
+	return defaultPrivilege.Ensure
+}
+
+// GetName gets a name identifying the default privileges in the status,
+// like "SELECT on table in schema public for owner to app"
+func (defaultPrivilege DefaultPrivilegeSpec) GetName() string {
+	var name strings.Builder
+	fmt.Fprintf(&name, "%s on %s",
+		joinPrivileges(defaultPrivilege.Privileges), defaultPrivilege.ObjectType)
+	if defaultPrivilege.Schema != "" {
+		fmt.Fprintf(&name, " in schema %s", defaultPrivilege.Schema)
+	}
+	if defaultPrivilege.ForRole != "" {
+		fmt.Fprintf(&name, " for %s", defaultPrivilege.ForRole)
+	}
+	fmt.Fprintf(&name, " to %s", defaultPrivilege.Role)
+
+	return name.String()
+}
+
+// GetUserMappingSecretNames gets the sorted list of the secrets
+// used by the options of the user mappings of the foreign servers
+func (spec *DatabaseSpec) GetUserMappingSecretNames() []string {
+	secretNames := stringset.New()
+	for _, server := range spec.Servers {
+		for _, userMapping := range server.UserMappings {
+			for _, option := range userMapping.Options {
+				if option.SecretKeyRef != nil && option.SecretKeyRef.Name != "" {
+					secretNames.Put(option.SecretKeyRef.Name)
+				}
+			}
+		}
+	}
+	return secretNames.ToSortedList()
+}
+
+func joinPrivileges(privileges []Privilege) string {
+	result := make([]string, len(privileges))
+	for i, privilege := range privileges {
+		result[i] = string(privilege)
+	}
+	return strings.Join(result, ",")
+}
+
 // SetAdmissionError sets the admission error status on the Database resource
 func (db *Database) SetAdmissionError(msg string) {
 	db.Status.Message = msg
@@ -196,6 +196,45 @@ type DatabaseSpec struct {
 	// The list of foreign servers to be managed in the database
 	// +optional
 	Servers []ServerSpec `json:"servers,omitempty"`
+
+	// The list of privileges to be granted or revoked on the objects
+	// of the database
+	// +optional
+	Grants []GrantSpec `json:"grants,omitempty"`
+
+	// The list of privileges to be granted or revoked on the objects
+	// that will be created in the database, through
+	// `ALTER DEFAULT PRIVILEGES`
+	// +optional
+	DefaultPrivileges []DefaultPrivilegeSpec `json:"defaultPrivileges,omitempty"`
+
+	// The logical backup used to populate the database. The dump is
+	// restored once, after the database has been created, and again
+	// only when a different backup is referenced
+	// +optional
+	Restore *DatabaseRestoreSource `json:"restore,omitempty"`
+}
+
+// DatabaseRestoreSource describes the dump, taken with the `logical`
+// backup method, to be restored into a database
+type DatabaseRestoreSource struct {
+	// The name of the Backup, taken with the `logical` method, in the
+	// same namespace of the database
+	BackupRef corev1.LocalObjectReference `json:"backup"`
+
+	// The name of the exported database to be restored. Defaults to the
+	// name of the database
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// Restore only the objects in the listed schemas
+	// +optional
+	Schemas []string `json:"schemas,omitempty"`
+
+	// Restore only the listed tables, views, materialized views,
+	// sequences and foreign tables
+	// +optional
+	Tables []string `json:"tables,omitempty"`
 }
 
 // DatabaseObjectSpec contains the fields which are common to every
@@ -291,6 +330,55 @@ type ServerSpec struct {
 	// List of roles for which `USAGE` privileges on the server are granted or revoked.
 	// +optional
 	Usages []UsageSpec `json:"usage,omitempty"`
+
+	// The user mappings of the server, defining the options used by
+	// the local roles to connect to it
+	// +optional
+	UserMappings []UserMappingSpec `json:"userMappings,omitempty"`
+}
+
+// UserMappingSpec configures the mapping of a local role to the
+// options used to connect to a foreign server
+type UserMappingSpec struct {
+	// The local role, or `PUBLIC` to define the options used by
+	// every role without a specific user mapping
+	// +kubebuilder:validation:XValidation:rule="self != ''",message="name is required"
+	Name string `json:"name"`
+
+	// The options of the user mapping, like `user` and `password`
+	// +optional
+	Options []UserMappingOptionSpec `json:"options,omitempty"`
+
+	// Specifies whether the user mapping should be present or absent
+	// +kubebuilder:default:="present"
+	// +kubebuilder:validation:Enum=present;absent
+	// +optional
+	Ensure EnsureOption `json:"ensure,omitempty"`
+}
+
+// UserMappingOptionSpec holds an option of a user mapping, whose value
+// can be read from a secret
+// +kubebuilder:validation:XValidation:rule="!(has(self.value) && has(self.secretKeyRef))",message="value and secretKeyRef are mutually exclusive"
+type UserMappingOptionSpec struct {
+	// Name of the option
+	Name string `json:"name"`
+
+	// Value of the option
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// The key of the secret containing the value of the option,
+	// to be used for sensitive values like `password`. The secret
+	// must be in the same namespace of the database.
+	// +optional
+	SecretKeyRef *SecretKeySelector `json:"secretKeyRef,omitempty"`
+
+	// Specifies whether an option should be present or absent in
+	// the user mapping
+	// +kubebuilder:default:="present"
+	// +kubebuilder:validation:Enum=present;absent
+	// +optional
+	Ensure EnsureOption `json:"ensure,omitempty"`
 }
 
 // OptionSpec holds the name, value and the ensure field for an option
@@ -324,6 +412,118 @@ type UsageSpec struct {
 	Type UsageSpecType `json:"type,omitempty"`
 }
 
+// Privilege is a privilege on a database object, as accepted by the
+// `GRANT` and `REVOKE` commands. `ALL` stands for every privilege
+// available for the type of the object.
+// +kubebuilder:validation:Enum=ALL;SELECT;INSERT;UPDATE;DELETE;TRUNCATE;REFERENCES;TRIGGER;MAINTAIN;CREATE;CONNECT;TEMPORARY;EXECUTE;USAGE
+type Privilege string
+
+// PrivilegeObjectType is the type of the objects on which privileges
+// are granted or revoked
+type PrivilegeObjectType string
+
+const (
+	// PrivilegeObjectTypeDatabase refers to the database itself
+	PrivilegeObjectTypeDatabase PrivilegeObjectType = "database"
+
+	// PrivilegeObjectTypeSchema refers to a schema
+	PrivilegeObjectTypeSchema PrivilegeObjectType = "schema"
+
+	// PrivilegeObjectTypeTable refers to a table, including views,
+	// materialized views and foreign tables
+	PrivilegeObjectTypeTable PrivilegeObjectType = "table"
+
+	// PrivilegeObjectTypeSequence refers to a sequence
+	PrivilegeObjectTypeSequence PrivilegeObjectType = "sequence"
+
+	// PrivilegeObjectTypeFunction refers to a function
+	PrivilegeObjectTypeFunction PrivilegeObjectType = "function"
+
+	// PrivilegeObjectTypeType refers to a type or a domain, and is only
+	// supported by the default privileges
+	PrivilegeObjectTypeType PrivilegeObjectType = "type"
+)
+
+// GrantSpec configures the privileges of a role on a database object,
+// built around the `GRANT` and `REVOKE` SQL commands of PostgreSQL
+// +kubebuilder:validation:XValidation:rule="self.objectType == 'database' || has(self.schema)",message="schema is required unless objectType is database"
+// +kubebuilder:validation:XValidation:rule="!has(self.schema) || self.objectType != 'database'",message="schema is not allowed when objectType is database"
+// +kubebuilder:validation:XValidation:rule="!has(self.objectName) || self.objectType in ['table', 'sequence', 'function']",message="objectName is only allowed when objectType is table, sequence or function"
+type GrantSpec struct {
+	// The role receiving the privileges, or `PUBLIC`
+	// +kubebuilder:validation:MinLength=1
+	Role string `json:"role"`
+
+	// The privileges to be granted or revoked
+	// +kubebuilder:validation:MinItems=1
+	Privileges []Privilege `json:"privileges"`
+
+	// The type of the object
+	// +kubebuilder:validation:Enum=database;schema;table;sequence;function
+	ObjectType PrivilegeObjectType `json:"objectType"`
+
+	// The schema of the object, or the schema itself when objectType
+	// is `schema`
+	// +optional
+	Schema string `json:"schema,omitempty"`
+
+	// The name of the table, sequence or function in the schema. Functions
+	// must include the argument types, like `my_function(integer, text)`.
+	// When empty, the privileges apply to all the objects of the given
+	// type in the schema, like in `GRANT ... ON ALL TABLES IN SCHEMA`.
+	// +optional
+	ObjectName string `json:"objectName,omitempty"`
+
+	// Allow the role to grant the privileges to other roles
+	// +optional
+	WithGrantOption bool `json:"withGrantOption,omitempty"`
+
+	// Specifies whether the privileges should be granted (`present`)
+	// or revoked (`absent`)
+	// +kubebuilder:default:="present"
+	// +kubebuilder:validation:Enum=present;absent
+	// +optional
+	Ensure EnsureOption `json:"ensure,omitempty"`
+}
+
+// DefaultPrivilegeSpec configures the privileges of a role on the
+// objects that will be created in the database, built around the
+// `ALTER DEFAULT PRIVILEGES` SQL command of PostgreSQL
+// +kubebuilder:validation:XValidation:rule="!has(self.schema) || self.objectType != 'schema'",message="schema is not allowed when objectType is schema"
+type DefaultPrivilegeSpec struct {
+	// The role receiving the privileges, or `PUBLIC`
+	// +kubebuilder:validation:MinLength=1
+	Role string `json:"role"`
+
+	// The privileges to be granted or revoked
+	// +kubebuilder:validation:MinItems=1
+	Privileges []Privilege `json:"privileges"`
+
+	// The type of the objects
+	// +kubebuilder:validation:Enum=schema;table;sequence;function;type
+	ObjectType PrivilegeObjectType `json:"objectType"`
+
+	// Restrict the default privileges to the objects created in this
+	// schema. When empty, they apply to the whole database.
+	// +optional
+	Schema string `json:"schema,omitempty"`
+
+	// The role creating the objects. Defaults to the owner of the database.
+	// +optional
+	ForRole string `json:"forRole,omitempty"`
+
+	// Allow the role to grant the privileges to other roles
+	// +optional
+	WithGrantOption bool `json:"withGrantOption,omitempty"`
+
+	// Specifies whether the privileges should be granted (`present`)
+	// or revoked (`absent`)
+	// +kubebuilder:default:="present"
+	// +kubebuilder:validation:Enum=present;absent
+	// +optional
+	Ensure EnsureOption `json:"ensure,omitempty"`
+}
+
 // DatabaseStatus defines the observed state of Database
 type DatabaseStatus struct {
 	// A sequence number representing the latest
@@ -354,6 +554,28 @@ type DatabaseStatus struct {
 	// Servers is the status of the managed servers
 	// +optional
 	Servers []DatabaseObjectStatus `json:"servers,omitempty"`
+
+	// Grants is the status of the managed privileges
+	// +optional
+	Grants []DatabaseObjectStatus `json:"grants,omitempty"`
+
+	// DefaultPrivileges is the status of the managed default privileges
+	// +optional
+	DefaultPrivileges []DatabaseObjectStatus `json:"defaultPrivileges,omitempty"`
+
+	// UserMappings is the status of the managed user mappings
+	// +optional
+	UserMappings []DatabaseObjectStatus `json:"userMappings,omitempty"`
+
+	// The resource versions of the secrets used by the user mappings,
+	// keyed by secret name, as they were when the user mappings
+	// were last applied
+	// +optional
+	SecretResourceVersions map[string]string `json:"secretResourceVersions,omitempty"`
+
+	// The name of the logical backup restored into the database
+	// +optional
+	RestoredFrom string `json:"restoredFrom,omitempty"`
 }
 
 // DatabaseObjectStatus is the status of the managed database objects
@@ -49,6 +49,12 @@ const (
 
 	// DatabaseKind is the kind name of databases
 	DatabaseKind = "Database"
+
+	// SwitchoverKind is the kind name of switchovers
+	SwitchoverKind = "Switchover"
+
+	// MajorMigrationKind is the kind name of major migrations
+	MajorMigrationKind = "MajorMigration"
 )
 
 var (
@@ -80,6 +86,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&Publication{}, &PublicationList{},
 		&ScheduledBackup{}, &ScheduledBackupList{},
 		&Subscription{}, &SubscriptionList{},
+		&Switchover{}, &SwitchoverList{},
+		&MajorMigration{}, &MajorMigrationList{},
 	)
 
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
@@ -0,0 +1,81 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// defaultMajorMigrationCutoverTimeout is the maximum time the Poolers are
+// kept paused during the cutover when not specified by the user
+const defaultMajorMigrationCutoverTimeout = 5 * time.Minute
+
+// GetCutoverTimeout returns the maximum time the Poolers
+// are kept paused during the cutover
+func (migration *MajorMigration) GetCutoverTimeout() time.Duration {
+	if migration.Spec.CutoverTimeout == nil {
+		return defaultMajorMigrationCutoverTimeout
+	}
+
+	return migration.Spec.CutoverTimeout.Duration
+}
+
+// GetDatabases returns the databases to be migrated, defaulting
+// to the application database of the source cluster
+func (migration *MajorMigration) GetDatabases(source *Cluster) []string {
+	if len(migration.Spec.Databases) > 0 {
+		return migration.Spec.Databases
+	}
+
+	return []string{source.GetApplicationDatabaseName()}
+}
+
+// SetPhase sets the phase of the migration, explaining it with the given message
+func (migrationStatus *MajorMigrationStatus) SetPhase(phase MajorMigrationPhase, message string) {
+	migrationStatus.Phase = phase
+	migrationStatus.Message = message
+}
+
+// SetAsCompleted marks the migration as completed
+func (migrationStatus *MajorMigrationStatus) SetAsCompleted() {
+	migrationStatus.SetPhase(MajorMigrationPhaseCompleted, "")
+	migrationStatus.StoppedAt = ptr.To(metav1.Now())
+}
+
+// SetAsFailed marks the migration as failed with the given error
+func (migrationStatus *MajorMigrationStatus) SetAsFailed(err error) {
+	migrationStatus.SetPhase(MajorMigrationPhaseFailed, err.Error())
+	migrationStatus.StoppedAt = ptr.To(metav1.Now())
+}
+
+// IsDone checks if the migration reached a final phase
+func (migrationStatus *MajorMigrationStatus) IsDone() bool {
+	return migrationStatus.Phase == MajorMigrationPhaseCompleted ||
+		migrationStatus.Phase == MajorMigrationPhaseFailed
+}
+
+// IsCutoverStarted checks if the Poolers of the source
+// cluster have been paused for the cutover
+func (migrationStatus *MajorMigrationStatus) IsCutoverStarted() bool {
+	return migrationStatus.Cutover != nil
+}
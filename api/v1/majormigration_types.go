@@ -0,0 +1,220 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MajorMigrationPhase is the phase of a major migration
+type MajorMigrationPhase string
+
+const (
+	// MajorMigrationPhaseCreatingTarget means that the target cluster has
+	// been created, importing the schema of the source cluster, and the
+	// operator is waiting for it to be ready
+	MajorMigrationPhaseCreatingTarget MajorMigrationPhase = "creatingTarget"
+
+	// MajorMigrationPhaseReplicating means that the data of the source
+	// cluster is being copied to the target cluster
+	MajorMigrationPhaseReplicating MajorMigrationPhase = "replicating"
+
+	// MajorMigrationPhaseReadyForCutover means that every table has been
+	// copied to the target cluster, which is kept in sync with the source
+	// cluster until the cutover is requested
+	MajorMigrationPhaseReadyForCutover MajorMigrationPhase = "readyForCutover"
+
+	// MajorMigrationPhaseCuttingOver means that the writes on the source
+	// cluster are being stopped to move the applications to the target cluster
+	MajorMigrationPhaseCuttingOver MajorMigrationPhase = "cuttingOver"
+
+	// MajorMigrationPhaseCompleted means that the applications have been
+	// moved to the target cluster and the source cluster has been fenced
+	MajorMigrationPhaseCompleted MajorMigrationPhase = "completed"
+
+	// MajorMigrationPhaseFailed means that the migration could not be
+	// completed and will not be retried
+	MajorMigrationPhaseFailed MajorMigrationPhase = "failed"
+)
+
+// MajorMigrationSpec defines the desired state of MajorMigration
+// +kubebuilder:validation:XValidation:rule="has(self.databases) == has(oldSelf.databases)",message="databases is immutable"
+type MajorMigrationSpec struct {
+	// The cluster to be migrated. It needs to have the superuser access
+	// enabled, as the superuser is used to import its schema and to
+	// replicate its data
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="sourceCluster is immutable"
+	SourceCluster corev1.LocalObjectReference `json:"sourceCluster"`
+
+	// The cluster that will be created by the operator, copying the
+	// configuration of the source cluster. It must not exist
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="targetCluster is immutable"
+	TargetCluster corev1.LocalObjectReference `json:"targetCluster"`
+
+	// The PostgreSQL image of the target cluster, which needs to run
+	// a newer major version than the source cluster
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="imageName is immutable"
+	ImageName string `json:"imageName"`
+
+	// The databases to be migrated. Defaults to the application
+	// database of the source cluster
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="databases is immutable"
+	// +kubebuilder:validation:items:Pattern=`^[a-z_][a-z0-9_]*$`
+	// +kubebuilder:validation:items:MaxLength=63
+	// +listType=set
+	// +optional
+	Databases []string `json:"databases,omitempty"`
+
+	// When true, the operator moves the applications to the target
+	// cluster as soon as it is ready for the cutover. The writes on the
+	// source cluster are stopped by pausing its Poolers, which are
+	// pointed to the target cluster once it has caught up
+	// +optional
+	Cutover bool `json:"cutover,omitempty"`
+
+	// The maximum time the Poolers are kept paused during the cutover,
+	// waiting for the source cluster to become read-only
+	// and for the target cluster to catch up. When expired, the Poolers
+	// are resumed on the source cluster and the migration fails.
+	// Defaults to 5 minutes
+	// +optional
+	CutoverTimeout *metav1.Duration `json:"cutoverTimeout,omitempty"`
+}
+
+// MajorMigrationStatus defines the observed state of MajorMigration
+type MajorMigrationStatus struct {
+	// The current phase of the migration
+	// +optional
+	Phase MajorMigrationPhase `json:"phase,omitempty"`
+
+	// A human-readable message explaining the current phase
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// The PostgreSQL major version of the source cluster
+	// +optional
+	SourceMajorVersion int `json:"sourceMajorVersion,omitempty"`
+
+	// The PostgreSQL major version of the target cluster
+	// +optional
+	TargetMajorVersion int `json:"targetMajorVersion,omitempty"`
+
+	// The replication progress of every migrated database
+	// +optional
+	Databases []MajorMigrationDatabaseStatus `json:"databases,omitempty"`
+
+	// The progress of the cutover, once started
+	// +optional
+	Cutover *MajorMigrationCutoverStatus `json:"cutover,omitempty"`
+
+	// When the migration was completed or failed
+	// +optional
+	StoppedAt *metav1.Time `json:"stoppedAt,omitempty"`
+}
+
+// MajorMigrationDatabaseStatus is the replication progress of a database
+type MajorMigrationDatabaseStatus struct {
+	// The name of the database
+	Name string `json:"name"`
+
+	// The number of tables whose initial copy is completed
+	// +optional
+	ReadyTables int32 `json:"readyTables,omitempty"`
+
+	// The number of tables whose initial copy is still in progress
+	// +optional
+	PendingTables int32 `json:"pendingTables,omitempty"`
+
+	// The amount of WAL, in bytes, generated by the source cluster and
+	// not yet confirmed by the target cluster
+	// +optional
+	LagBytes *int64 `json:"lagBytes,omitempty"`
+
+	// When the sequences were last copied to the target cluster
+	// +optional
+	SequencesSyncedAt *metav1.Time `json:"sequencesSyncedAt,omitempty"`
+}
+
+// MajorMigrationCutoverStatus is the progress of the cutover
+type MajorMigrationCutoverStatus struct {
+	// When the Poolers of the source cluster were paused
+	StartedAt metav1.Time `json:"startedAt"`
+
+	// The Poolers that have been paused and will be pointed to the
+	// target cluster
+	// +optional
+	PausedPoolers []string `json:"pausedPoolers,omitempty"`
+
+	// When the source cluster became read-only and its client
+	// connections were terminated
+	// +optional
+	WritesStoppedAt *metav1.Time `json:"writesStoppedAt,omitempty"`
+
+	// The WAL location of the source cluster when the writes stopped,
+	// which the target cluster needs to reach
+	// +optional
+	LSN string `json:"lsn,omitempty"`
+
+	// When the target cluster reached the WAL location of the source
+	// cluster and the Poolers were pointed to it
+	// +optional
+	PoolersSwitchedAt *metav1.Time `json:"poolersSwitchedAt,omitempty"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Source",type="string",JSONPath=".spec.sourceCluster.name"
+// +kubebuilder:printcolumn:name="Target",type="string",JSONPath=".spec.targetCluster.name"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.message"
+
+// MajorMigration is a request to move the databases of a Cluster to a new
+// Cluster running a newer PostgreSQL major version, using logical
+// replication to keep the downtime to a minimum
+type MajorMigration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Specification of the desired behavior of the migration.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Spec MajorMigrationSpec `json:"spec"`
+	// Most recently observed status of the migration. This data may not be up to
+	// date. Populated by the system. Read-only.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	// +optional
+	Status MajorMigrationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MajorMigrationList contains a list of MajorMigration
+type MajorMigrationList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of major migrations
+	Items []MajorMigration `json:"items"`
+}
@@ -19,16 +19,90 @@ SPDX-License-Identifier: Apache-2.0
 
 package v1
 
-import corev1 "k8s.io/api/core/v1"
+import (
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// defaultReplicaLoadBalancingCheckInterval is the default interval
+// between two consecutive checks of the replicas of a cluster
+const defaultReplicaLoadBalancingCheckInterval = 10 * time.Second
+
+// defaultPgCatPoolSize is the default maximum number of server
+// connections of each user of a PgCat database
+const defaultPgCatPoolSize = 10
+
+// defaultPoolerScaleDownStabilizationWindow is the default time the load
+// must allow fewer instances before a Pooler is scaled down
+const defaultPoolerScaleDownStabilizationWindow = 5 * time.Minute
 
 // IsPaused returns whether all database should be paused or not.
 func (in PgBouncerSpec) IsPaused() bool {
 	return in.Paused != nil && *in.Paused
 }
 
+// IsPaused returns whether all database should be paused or not.
+func (in PgCatSpec) IsPaused() bool {
+	return in.Paused != nil && *in.Paused
+}
+
+// GetPoolSize returns the maximum number of server connections of
+// each user of the database
+func (in *PgCatDatabase) GetPoolSize() int32 {
+	if in.PoolSize != nil {
+		return *in.PoolSize
+	}
+
+	return defaultPgCatPoolSize
+}
+
+// GetDBName returns the name of the database in the cluster
+func (in *PgCatDatabase) GetDBName() string {
+	if in.DBName != "" {
+		return in.DBName
+	}
+
+	return in.Name
+}
+
+// GetBackend returns the connection pooler software run by the Pooler
+func (in *Pooler) GetBackend() PoolerBackend {
+	if in.Spec.PgCat != nil {
+		return PoolerBackendPgCat
+	}
+
+	return PoolerBackendPgBouncer
+}
+
+// ShouldBePaused returns whether the pooler should be paused, either because
+// the user requested it or because the operator is draining the connections
+// to the cluster before a switchover or the cutover of a major migration
+func (in *Pooler) ShouldBePaused() bool {
+	if in.Spec.PgBouncer != nil && in.Spec.PgBouncer.IsPaused() {
+		return true
+	}
+
+	if in.Spec.PgCat != nil && in.Spec.PgCat.IsPaused() {
+		return true
+	}
+
+	_, pausedForSwitchover := in.Annotations[utils.PoolerPausedForSwitchoverAnnotationName]
+	_, pausedForMigration := in.Annotations[utils.PoolerPausedForMigrationAnnotationName]
+	return pausedForSwitchover || pausedForMigration
+}
+
 // GetAuthQuerySecretName returns the specified AuthQuerySecret name for PgBouncer
 // if provided or the default name otherwise.
 func (in *Pooler) GetAuthQuerySecretName() string {
+	if in.Spec.PgCat != nil {
+		return in.Spec.PgCat.AuthQuerySecret.Name
+	}
+
 	if in.Spec.PgBouncer != nil && in.Spec.PgBouncer.AuthQuerySecret != nil {
 		return in.Spec.PgBouncer.AuthQuerySecret.Name
 	}
@@ -36,6 +110,62 @@ func (in *Pooler) GetAuthQuerySecretName() string {
 	return in.Spec.Cluster.Name + DefaultPgBouncerPoolerSecretSuffix
 }
 
+// GetAuthQuerySecretNameForCluster returns the name of the secret containing
+// the credentials used to run the authentication query against the passed
+// cluster, that may be different from the one referenced by the Pooler.
+// When the integration is automated, every cluster has its own secret,
+// otherwise the secret specified by the user is used for every cluster.
+func (in *Pooler) GetAuthQuerySecretNameForCluster(clusterName string) string {
+	if clusterName == in.Spec.Cluster.Name || !in.IsAutomatedIntegration() {
+		return in.GetAuthQuerySecretName()
+	}
+
+	return clusterName + DefaultPgBouncerPoolerSecretSuffix
+}
+
+// GetReferencedClusterNames returns the names of the clusters serving the
+// databases exposed by PgBouncer. The cluster referenced by the Pooler
+// always comes first, followed by the other ones in alphabetical order
+func (in *Pooler) GetReferencedClusterNames() []string {
+	var additionalClusters []string
+	if in.Spec.PgBouncer != nil {
+		for idx := range in.Spec.PgBouncer.Databases {
+			clusterName := in.Spec.PgBouncer.Databases[idx].GetClusterName(in)
+			if clusterName != in.Spec.Cluster.Name && !slices.Contains(additionalClusters, clusterName) {
+				additionalClusters = append(additionalClusters, clusterName)
+			}
+		}
+	}
+	slices.Sort(additionalClusters)
+
+	return append([]string{in.Spec.Cluster.Name}, additionalClusters...)
+}
+
+// GetClusterName returns the name of the cluster serving the database
+func (in *PgBouncerDatabase) GetClusterName(pooler *Pooler) string {
+	if in.Cluster != nil && in.Cluster.Name != "" {
+		return in.Cluster.Name
+	}
+
+	return pooler.Spec.Cluster.Name
+}
+
+// GetType returns the type of the service the connections
+// to the database are forwarded to
+func (in *PgBouncerDatabase) GetType(pooler *Pooler) PoolerType {
+	if in.Type != "" {
+		return in.Type
+	}
+
+	return pooler.Spec.Type
+}
+
+// IsFallback returns true if this is the entry used for the
+// databases not explicitly listed
+func (in *PgBouncerDatabase) IsFallback() bool {
+	return in.Name == "*"
+}
+
 // GetServerTLSSecretName returns the specified server TLS secret name
 // for PgBouncer if provided or the default name otherwise.
 func (in *Pooler) GetServerTLSSecretName() string {
@@ -76,10 +206,17 @@ func (in *Pooler) GetClientTLSSecretNameOrDefault(cluster *Cluster) string {
 	return cluster.GetServerTLSSecretName()
 }
 
-// GetAuthQuery returns the specified AuthQuery name for PgBouncer
-// if provided or the default name otherwise.
+// GetAuthQuery returns the specified AuthQuery for the pooler
+// if provided or the default one otherwise.
 func (in *Pooler) GetAuthQuery() string {
-	if in.Spec.PgBouncer.AuthQuery != "" {
+	if in.Spec.PgCat != nil {
+		if in.Spec.PgCat.AuthQuery != "" {
+			return in.Spec.PgCat.AuthQuery
+		}
+		return DefaultPgCatPoolerAuthQuery
+	}
+
+	if in.Spec.PgBouncer != nil && in.Spec.PgBouncer.AuthQuery != "" {
 		return in.Spec.PgBouncer.AuthQuery
 	}
 
@@ -89,6 +226,13 @@ func (in *Pooler) GetAuthQuery() string {
 // IsAutomatedIntegration returns whether the Pooler integration with the
 // Cluster is automated or not.
 func (in *Pooler) IsAutomatedIntegration() bool {
+	// PgCat can't authenticate against PostgreSQL with a
+	// client certificate, so the user always provides the
+	// credentials to run the authentication query
+	if in.Spec.PgCat != nil {
+		return false
+	}
+
 	if in.Spec.PgBouncer == nil {
 		return true
 	}
@@ -132,6 +276,68 @@ func (in *Pooler) IsMetricsTLSEnabled() bool {
 	return false
 }
 
+// IsReplicaLoadBalancingEnabled checks if PgBouncer should balance the
+// connections among the replicas of the cluster, excluding the lagging ones
+func (in *Pooler) IsReplicaLoadBalancingEnabled() bool {
+	return in.Spec.Type == PoolerTypeRO && in.Spec.ReplicaLoadBalancing != nil
+}
+
+// GetReplicaLoadBalancingHosts gets the addresses of the replicas PgBouncer
+// should connect to, or nil if the connections should be forwarded
+// to the service of the cluster
+func (in *Pooler) GetReplicaLoadBalancingHosts() []string {
+	if !in.IsReplicaLoadBalancingEnabled() || in.Status.ReplicaLoadBalancing == nil {
+		return nil
+	}
+
+	hosts := make([]string, 0, len(in.Status.ReplicaLoadBalancing.Members))
+	for _, member := range in.Status.ReplicaLoadBalancing.Members {
+		hosts = append(hosts, member.IP)
+	}
+	if len(hosts) == 0 {
+		return nil
+	}
+
+	return hosts
+}
+
+// GetCheckInterval gets the interval between two consecutive
+// checks of the replicas
+func (in *PoolerReplicaLoadBalancing) GetCheckInterval() time.Duration {
+	if in == nil || in.CheckInterval == nil || in.CheckInterval.Duration <= 0 {
+		return defaultReplicaLoadBalancingCheckInterval
+	}
+
+	return in.CheckInterval.Duration
+}
+
+// GetDesiredInstances gets the number of PgBouncer instances the
+// Deployment should have. When autoscaling is enabled, this is the number
+// of instances requested by the autoscaler, within the configured limits
+func (in *Pooler) GetDesiredInstances() *int32 {
+	autoscaling := in.Spec.Autoscaling
+	if autoscaling == nil {
+		return in.Spec.Instances
+	}
+
+	instances := ptr.Deref(in.Spec.Instances, 1)
+	if in.Status.Autoscaling != nil && in.Status.Autoscaling.DesiredInstances > 0 {
+		instances = in.Status.Autoscaling.DesiredInstances
+	}
+
+	return ptr.To(min(max(instances, autoscaling.MinInstances), autoscaling.MaxInstances))
+}
+
+// GetScaleDownStabilizationWindow gets the time the load must allow
+// fewer instances before PgBouncer is scaled down
+func (in *PoolerAutoscalingConfiguration) GetScaleDownStabilizationWindow() time.Duration {
+	if in == nil || in.ScaleDownStabilizationWindow == nil {
+		return defaultPoolerScaleDownStabilizationWindow
+	}
+
+	return in.ScaleDownStabilizationWindow.Duration
+}
+
 // SetAdmissionError sets the admission error status on the Pooler resource
 func (in *Pooler) SetAdmissionError(msg string) {
 	in.Status.Error = msg
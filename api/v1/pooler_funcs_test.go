@@ -20,7 +20,12 @@ SPDX-License-Identifier: Apache-2.0
 package v1
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -118,3 +123,190 @@ var _ = Describe("Pooler IsMetricsTLSEnabled", func() {
 		Expect(pooler.IsMetricsTLSEnabled()).To(BeTrue())
 	})
 })
+
+var _ = Describe("Pooler ShouldBePaused", func() {
+	It("returns false by default", func() {
+		pooler := &Pooler{Spec: PoolerSpec{PgBouncer: &PgBouncerSpec{}}}
+		Expect(pooler.ShouldBePaused()).To(BeFalse())
+	})
+
+	It("returns true when the user requested it", func() {
+		pooler := &Pooler{Spec: PoolerSpec{PgBouncer: &PgBouncerSpec{Paused: ptr.To(true)}}}
+		Expect(pooler.ShouldBePaused()).To(BeTrue())
+	})
+
+	It("returns true while draining the connections for a switchover", func() {
+		pooler := &Pooler{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					utils.PoolerPausedForSwitchoverAnnotationName: "cluster-example-2",
+				},
+			},
+			Spec: PoolerSpec{PgBouncer: &PgBouncerSpec{Paused: ptr.To(false)}},
+		}
+		Expect(pooler.ShouldBePaused()).To(BeTrue())
+	})
+
+	It("returns true during the cutover of a major migration", func() {
+		pooler := &Pooler{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					utils.PoolerPausedForMigrationAnnotationName: "migration-example",
+				},
+			},
+			Spec: PoolerSpec{PgBouncer: &PgBouncerSpec{}},
+		}
+		Expect(pooler.ShouldBePaused()).To(BeTrue())
+	})
+})
+
+var _ = Describe("Pooler referenced clusters", func() {
+	pooler := &Pooler{
+		ObjectMeta: metav1.ObjectMeta{Name: "pooler-example"},
+		Spec: PoolerSpec{
+			Cluster: LocalObjectReference{Name: "cluster-example"},
+			PgBouncer: &PgBouncerSpec{
+				Databases: []PgBouncerDatabase{
+					{Name: "*"},
+					{Name: "zeta", Cluster: &LocalObjectReference{Name: "cluster-zeta"}},
+					{Name: "alpha", Cluster: &LocalObjectReference{Name: "cluster-alpha"}},
+					{Name: "other", Cluster: &LocalObjectReference{Name: "cluster-zeta"}},
+					{Name: "main", Cluster: &LocalObjectReference{Name: "cluster-example"}},
+				},
+			},
+		},
+	}
+
+	It("lists the pooler cluster first, followed by the other ones", func() {
+		Expect(pooler.GetReferencedClusterNames()).To(Equal(
+			[]string{"cluster-example", "cluster-alpha", "cluster-zeta"}))
+	})
+
+	It("resolves the cluster and the service of each database", func() {
+		Expect(pooler.Spec.PgBouncer.Databases[0].GetClusterName(pooler)).To(Equal("cluster-example"))
+		Expect(pooler.Spec.PgBouncer.Databases[0].IsFallback()).To(BeTrue())
+		Expect(pooler.Spec.PgBouncer.Databases[1].GetClusterName(pooler)).To(Equal("cluster-zeta"))
+		Expect(pooler.Spec.PgBouncer.Databases[1].IsFallback()).To(BeFalse())
+	})
+
+	It("uses a dedicated auth query secret for the other clusters", func() {
+		Expect(pooler.GetAuthQuerySecretNameForCluster("cluster-example")).To(Equal(pooler.GetAuthQuerySecretName()))
+		Expect(pooler.GetAuthQuerySecretNameForCluster("cluster-zeta")).To(Equal("cluster-zeta-pooler"))
+	})
+})
+
+var _ = Describe("Pooler replica load balancing", func() {
+	It("is only enabled for ro poolers", func() {
+		pooler := &Pooler{Spec: PoolerSpec{Type: PoolerTypeRW, ReplicaLoadBalancing: &PoolerReplicaLoadBalancing{}}}
+		Expect(pooler.IsReplicaLoadBalancingEnabled()).To(BeFalse())
+
+		pooler.Spec.Type = PoolerTypeRO
+		Expect(pooler.IsReplicaLoadBalancingEnabled()).To(BeTrue())
+
+		pooler.Spec.ReplicaLoadBalancing = nil
+		Expect(pooler.IsReplicaLoadBalancingEnabled()).To(BeFalse())
+	})
+
+	It("uses the addresses of the members", func() {
+		pooler := &Pooler{
+			Spec: PoolerSpec{Type: PoolerTypeRO, ReplicaLoadBalancing: &PoolerReplicaLoadBalancing{}},
+		}
+		Expect(pooler.GetReplicaLoadBalancingHosts()).To(BeNil())
+
+		pooler.Status.ReplicaLoadBalancing = &PoolerReplicaLoadBalancingStatus{
+			Members: []PoolerReplicaMember{{Name: "cluster-example-2", IP: "10.0.0.2"}},
+		}
+		Expect(pooler.GetReplicaLoadBalancingHosts()).To(Equal([]string{"10.0.0.2"}))
+	})
+
+	It("checks the replicas every 10 seconds by default", func() {
+		var configuration *PoolerReplicaLoadBalancing
+		Expect(configuration.GetCheckInterval()).To(Equal(10 * time.Second))
+
+		configuration = &PoolerReplicaLoadBalancing{CheckInterval: &metav1.Duration{Duration: time.Minute}}
+		Expect(configuration.GetCheckInterval()).To(Equal(time.Minute))
+	})
+})
+
+var _ = Describe("Pooler desired instances", func() {
+	It("uses the instances of the specification without autoscaling", func() {
+		pooler := &Pooler{
+			Spec:   PoolerSpec{Instances: ptr.To(int32(3))},
+			Status: PoolerStatus{Autoscaling: &PoolerAutoscalingStatus{DesiredInstances: 5}},
+		}
+		Expect(pooler.GetDesiredInstances()).To(HaveValue(Equal(int32(3))))
+	})
+
+	It("uses the instances requested by the autoscaler within the limits", func() {
+		pooler := &Pooler{
+			Spec: PoolerSpec{
+				Instances:   ptr.To(int32(1)),
+				Autoscaling: &PoolerAutoscalingConfiguration{MinInstances: 2, MaxInstances: 4},
+			},
+		}
+		Expect(pooler.GetDesiredInstances()).To(HaveValue(Equal(int32(2))))
+
+		pooler.Status.Autoscaling = &PoolerAutoscalingStatus{DesiredInstances: 3}
+		Expect(pooler.GetDesiredInstances()).To(HaveValue(Equal(int32(3))))
+
+		pooler.Status.Autoscaling.DesiredInstances = 10
+		Expect(pooler.GetDesiredInstances()).To(HaveValue(Equal(int32(4))))
+	})
+
+	It("waits 5 minutes before scaling down by default", func() {
+		var autoscaling *PoolerAutoscalingConfiguration
+		Expect(autoscaling.GetScaleDownStabilizationWindow()).To(Equal(5 * time.Minute))
+	})
+})
+
+var _ = Describe("Pooler backends", func() {
+	pgCatPooler := func() *Pooler {
+		return &Pooler{
+			Spec: PoolerSpec{
+				Cluster: LocalObjectReference{Name: "cluster-example"},
+				PgCat: &PgCatSpec{
+					AuthQuerySecret: LocalObjectReference{Name: "pgcat-auth"},
+				},
+			},
+		}
+	}
+
+	It("runs PgBouncer by default", func() {
+		pooler := &Pooler{Spec: PoolerSpec{PgBouncer: &PgBouncerSpec{}}}
+		Expect(pooler.GetBackend()).To(Equal(PoolerBackendPgBouncer))
+		Expect(pgCatPooler().GetBackend()).To(Equal(PoolerBackendPgCat))
+	})
+
+	It("never automates the integration for PgCat", func() {
+		pooler := pgCatPooler()
+		Expect(pooler.IsAutomatedIntegration()).To(BeFalse())
+		Expect(pooler.GetAuthQuerySecretName()).To(Equal("pgcat-auth"))
+		Expect(pooler.GetAuthQuerySecretNameForCluster("cluster-example")).To(Equal("pgcat-auth"))
+	})
+
+	It("uses the PgCat auth query", func() {
+		pooler := pgCatPooler()
+		Expect(pooler.GetAuthQuery()).To(Equal(DefaultPgCatPoolerAuthQuery))
+
+		pooler.Spec.PgCat.AuthQuery = "SELECT usename, passwd FROM pg_shadow WHERE usename='$1'"
+		Expect(pooler.GetAuthQuery()).To(Equal(pooler.Spec.PgCat.AuthQuery))
+	})
+
+	It("pauses PgCat when requested", func() {
+		pooler := pgCatPooler()
+		Expect(pooler.ShouldBePaused()).To(BeFalse())
+
+		pooler.Spec.PgCat.Paused = ptr.To(true)
+		Expect(pooler.ShouldBePaused()).To(BeTrue())
+	})
+
+	It("defaults the name and the pool size of the PgCat databases", func() {
+		database := &PgCatDatabase{Name: "app"}
+		Expect(database.GetDBName()).To(Equal("app"))
+		Expect(database.GetPoolSize()).To(Equal(int32(10)))
+
+		database = &PgCatDatabase{Name: "orders", DBName: "shop", PoolSize: ptr.To(int32(5))}
+		Expect(database.GetDBName()).To(Equal("shop"))
+		Expect(database.GetPoolSize()).To(Equal(int32(5)))
+	})
+})
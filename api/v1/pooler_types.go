@@ -68,6 +68,10 @@ const (
 	// DefaultPgBouncerPoolerAuthQuery is the default auth_query for PgBouncer
 	DefaultPgBouncerPoolerAuthQuery = "SELECT usename, passwd FROM public.user_search($1)"
 
+	// DefaultPgCatPoolerAuthQuery is the default auth_query for PgCat,
+	// which replaces the placeholder with the user name as-is
+	DefaultPgCatPoolerAuthQuery = "SELECT usename, passwd FROM public.user_search('$1')"
+
 	// PoolerAuthDBName is the database name used to run the auth_query
 	PoolerAuthDBName = "postgres"
 )
@@ -84,7 +88,19 @@ const (
 	PgBouncerPoolModeTransaction = PgBouncerPoolMode("transaction")
 )
 
+// PoolerBackend is the connection pooler software a Pooler is running
+type PoolerBackend string
+
+const (
+	// PoolerBackendPgBouncer means that the Pooler is running PgBouncer
+	PoolerBackendPgBouncer = PoolerBackend("pgbouncer")
+
+	// PoolerBackendPgCat means that the Pooler is running PgCat
+	PoolerBackendPgCat = PoolerBackend("pgcat")
+)
+
 // PoolerSpec defines the desired state of Pooler
+// +kubebuilder:validation:XValidation:rule="has(self.pgbouncer) != has(self.pgcat)",message="exactly one between pgbouncer and pgcat must be specified"
 type PoolerSpec struct {
 	// This is the cluster reference on which the Pooler will work.
 	// Pooler name should never match with any cluster name within the same namespace.
@@ -105,8 +121,15 @@ type PoolerSpec struct {
 	// +optional
 	Template *PodTemplateSpec `json:"template,omitempty"`
 
-	// The PgBouncer configuration
-	PgBouncer *PgBouncerSpec `json:"pgbouncer"`
+	// The PgBouncer configuration. Exactly one between `pgbouncer`
+	// and `pgcat` must be specified
+	// +optional
+	PgBouncer *PgBouncerSpec `json:"pgbouncer,omitempty"`
+
+	// The PgCat configuration. Exactly one between `pgbouncer`
+	// and `pgcat` must be specified
+	// +optional
+	PgCat *PgCatSpec `json:"pgcat,omitempty"`
 
 	// The deployment strategy to use for pgbouncer to replace existing pods with new ones
 	// +optional
@@ -130,6 +153,63 @@ type PoolerSpec struct {
 	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
 	// +kubebuilder:validation:MaxLength=253
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// The configuration of the load balancing among the replicas of
+	// the cluster, only allowed for `ro` poolers. When set, PgBouncer
+	// connects directly to the replicas, excluding the ones that are
+	// lagging behind the primary
+	// +optional
+	ReplicaLoadBalancing *PoolerReplicaLoadBalancing `json:"replicaLoadBalancing,omitempty"`
+
+	// The configuration of the autoscaler changing the number of
+	// PgBouncer instances depending on the clients waiting for a
+	// server connection. When set, `instances` is only used as the
+	// initial number of instances
+	// +optional
+	Autoscaling *PoolerAutoscalingConfiguration `json:"autoscaling,omitempty"`
+}
+
+// PoolerAutoscalingConfiguration is the configuration of the autoscaler
+// changing the number of PgBouncer instances depending on the load
+// +kubebuilder:validation:XValidation:rule="self.maxInstances >= self.minInstances",message="maxInstances must be greater than or equal to minInstances"
+type PoolerAutoscalingConfiguration struct {
+	// The minimum number of PgBouncer instances
+	// +kubebuilder:validation:Minimum=1
+	MinInstances int32 `json:"minInstances"`
+
+	// The maximum number of PgBouncer instances
+	// +kubebuilder:validation:Minimum=1
+	MaxInstances int32 `json:"maxInstances"`
+
+	// The target average number of clients waiting for a server
+	// connection per PgBouncer instance, as reported by `cl_waiting`
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetClientsWaiting *int32 `json:"targetClientsWaiting,omitempty"`
+
+	// The target time the oldest client waits for a server
+	// connection, as reported by `maxwait`
+	// +optional
+	TargetMaxWait *metav1.Duration `json:"targetMaxWait,omitempty"`
+
+	// The time the load must allow fewer instances before PgBouncer
+	// is scaled down, to avoid continuously changing the number of
+	// instances with fluctuating loads. Defaults to 5 minutes
+	// +optional
+	ScaleDownStabilizationWindow *metav1.Duration `json:"scaleDownStabilizationWindow,omitempty"`
+}
+
+// PoolerReplicaLoadBalancing is the configuration of the load
+// balancing among the replicas of a cluster
+type PoolerReplicaLoadBalancing struct {
+	// The maximum replay lag for a replica to receive connections.
+	// Replicas lagging more than this are excluded until they catch up
+	MaxLag metav1.Duration `json:"maxLag"`
+
+	// The interval between two consecutive checks of the replicas.
+	// Default: 10s
+	// +optional
+	CheckInterval *metav1.Duration `json:"checkInterval,omitempty"`
 }
 
 // PoolerMonitoringConfiguration is the type containing all the monitoring
@@ -300,6 +380,168 @@ type PgBouncerSpec struct {
 	// Mutually exclusive with Image.
 	// +optional
 	ImageCatalogRef *ImageCatalogComponentRef `json:"imageCatalogRef,omitempty"`
+
+	// The list of databases exposed by PgBouncer, each one possibly
+	// served by a different cluster in the same namespace. When empty,
+	// every database of the referenced cluster is exposed.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Databases []PgBouncerDatabase `json:"databases,omitempty"`
+
+	// The per-user settings of PgBouncer, rendered in the `[users]`
+	// section of the configuration
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Users []PgBouncerUser `json:"users,omitempty"`
+}
+
+// PgBouncerDatabase is an entry of the `[databases]` section of the
+// PgBouncer configuration
+type PgBouncerDatabase struct {
+	// The name of the database as seen by the clients. Use `*` for the
+	// fallback entry, used for the databases not explicitly listed
+	// +kubebuilder:validation:Pattern=`^(\*|[A-Za-z0-9_.-]+)$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// The cluster serving the database, defaults to the cluster
+	// referenced by the Pooler
+	// +optional
+	Cluster *LocalObjectReference `json:"cluster,omitempty"`
+
+	// The service of the cluster the connections are forwarded to,
+	// defaults to the type of the Pooler
+	// +optional
+	Type PoolerType `json:"type,omitempty"`
+
+	// The name of the database in the cluster, defaults to the name
+	// of the entry. Not allowed for the fallback entry
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_.-]+$`
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	DBName string `json:"dbname,omitempty"`
+
+	// The maximum size of the pool for this database, overriding
+	// the `default_pool_size` parameter
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PoolSize *int32 `json:"poolSize,omitempty"`
+
+	// The pool mode for this database, overriding the pool
+	// mode of the Pooler
+	// +optional
+	PoolMode PgBouncerPoolMode `json:"poolMode,omitempty"`
+
+	// The maximum number of server connections for this database,
+	// overriding the `max_db_connections` parameter
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxDBConnections *int32 `json:"maxDBConnections,omitempty"`
+}
+
+// PgBouncerUser is an entry of the `[users]` section of the
+// PgBouncer configuration
+type PgBouncerUser struct {
+	// The name of the user
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_.@-]+$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// The pool mode for the connections of this user, overriding
+	// the pool mode of the database and of the Pooler
+	// +optional
+	PoolMode PgBouncerPoolMode `json:"poolMode,omitempty"`
+
+	// The maximum size of the pools of this user, overriding
+	// the pool size of the database (requires PgBouncer 1.23+)
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PoolSize *int32 `json:"poolSize,omitempty"`
+
+	// The maximum number of server connections for this user,
+	// overriding the `max_user_connections` parameter
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxUserConnections *int32 `json:"maxUserConnections,omitempty"`
+}
+
+// PgCatSpec defines how to configure PgCat
+type PgCatSpec struct {
+	// Image is the PgCat container image to use, which needs to
+	// provide the `pgcat` executable in `/usr/bin`. An image set
+	// in the pod template takes precedence over it.
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+
+	// The pool mode. Default: `transaction`.
+	// +kubebuilder:default:=transaction
+	// +optional
+	PoolMode PgBouncerPoolMode `json:"poolMode,omitempty"`
+
+	// The basic-auth secret containing the credentials of the user
+	// running the authentication query. The integration with the
+	// cluster is never automated when PgCat is used.
+	AuthQuerySecret LocalObjectReference `json:"authQuerySecret"`
+
+	// The query that will be used to download the hash of the password
+	// of a certain user. Default: "SELECT usename, passwd FROM public.user_search('$1')".
+	// +optional
+	AuthQuery string `json:"authQuery,omitempty"`
+
+	// When set to `true`, PgCat parses the queries and sends the
+	// read-only ones to the replicas and the other ones to the primary,
+	// regardless of the type of the Pooler
+	// +optional
+	ReadWriteSplitting bool `json:"readWriteSplitting,omitempty"`
+
+	// The list of databases exposed by PgCat, each one with
+	// the users allowed to connect to it
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MinItems=1
+	Databases []PgCatDatabase `json:"databases"`
+
+	// Additional parameters to be passed to PgCat in the `[general]`
+	// section - please check the CNPG documentation for a list of
+	// options you can configure
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// When set to `true`, PgCat will pause all new client connections
+	// until this value is set to `false` (default). Internally, the
+	// operator calls PgCat's `PAUSE` and `RESUME` commands.
+	// +kubebuilder:default:=false
+	// +optional
+	Paused *bool `json:"paused,omitempty"`
+}
+
+// PgCatDatabase is a pool of the PgCat configuration
+type PgCatDatabase struct {
+	// The name of the database as seen by the clients
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_.-]+$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// The name of the database in the cluster, defaults to the name
+	// of the entry
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_.-]+$`
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	DBName string `json:"dbname,omitempty"`
+
+	// The users allowed to connect to the database, whose passwords
+	// are retrieved with the authentication query
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	Users []string `json:"users"`
+
+	// The maximum number of server connections of each user.
+	// Default: 10.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PoolSize *int32 `json:"poolSize,omitempty"`
 }
 
 // PoolerStatus defines the observed state of Pooler
@@ -332,6 +574,86 @@ type PoolerStatus struct {
 	// Error is the latest admission validation error
 	// +optional
 	Error string `json:"error,omitempty"`
+
+	// The replicas PgBouncer is balancing the connections among,
+	// when replica load balancing is enabled
+	// +optional
+	ReplicaLoadBalancing *PoolerReplicaLoadBalancingStatus `json:"replicaLoadBalancing,omitempty"`
+
+	// The last decision taken by the autoscaler
+	// +optional
+	Autoscaling *PoolerAutoscalingStatus `json:"autoscaling,omitempty"`
+}
+
+// PoolerAutoscalingStatus is the last decision taken by the pooler autoscaler
+type PoolerAutoscalingStatus struct {
+	// The number of PgBouncer instances requested by the autoscaler
+	// +optional
+	DesiredInstances int32 `json:"desiredInstances,omitempty"`
+
+	// The time of the last change of the number of instances
+	// +optional
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
+
+	// The reason of the last scaling operation
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// The time since the load allows fewer instances, used to
+	// apply the scale down stabilization window
+	// +optional
+	ScaleDownRecommendedSince *metav1.Time `json:"scaleDownRecommendedSince,omitempty"`
+}
+
+// PoolerReplicaLoadBalancingStatus is the current membership of the
+// replicas receiving the connections of a Pooler
+type PoolerReplicaLoadBalancingStatus struct {
+	// The replicas receiving the connections
+	// +optional
+	Members []PoolerReplicaMember `json:"members,omitempty"`
+
+	// The replicas that are not receiving connections
+	// +optional
+	Excluded []PoolerExcludedReplica `json:"excluded,omitempty"`
+
+	// The last time the membership changed
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// PoolerReplicaMember is a replica receiving the connections of a Pooler
+type PoolerReplicaMember struct {
+	// The name of the instance
+	Name string `json:"name"`
+
+	// The IP address PgBouncer connects to
+	IP string `json:"ip"`
+}
+
+// PoolerExcludedReplicaReason is the reason why a replica
+// isn't receiving the connections of a Pooler
+type PoolerExcludedReplicaReason string
+
+const (
+	// PoolerExcludedReplicaReasonLag means that the replay lag
+	// of the replica exceeds the configured maximum
+	PoolerExcludedReplicaReasonLag PoolerExcludedReplicaReason = "ReplayLag"
+
+	// PoolerExcludedReplicaReasonNotReady means that the replica is not ready
+	PoolerExcludedReplicaReasonNotReady PoolerExcludedReplicaReason = "NotReady"
+
+	// PoolerExcludedReplicaReasonUnknownStatus means that the status
+	// of the replica couldn't be retrieved
+	PoolerExcludedReplicaReasonUnknownStatus PoolerExcludedReplicaReason = "UnknownStatus"
+)
+
+// PoolerExcludedReplica is a replica excluded from the load balancing
+type PoolerExcludedReplica struct {
+	// The name of the instance
+	Name string `json:"name"`
+
+	// The reason why the replica has been excluded
+	Reason PoolerExcludedReplicaReason `json:"reason"`
 }
 
 // PoolerSecrets contains the versions of all the secrets used
@@ -355,6 +677,26 @@ type PoolerSecrets struct {
 	// The version of the secrets used by PgBouncer
 	// +optional
 	PgBouncerSecrets *PgBouncerSecrets `json:"pgBouncerSecrets,omitempty"`
+
+	// The versions of the secrets used to connect to the clusters,
+	// other than the referenced one, serving the databases of PgBouncer
+	// +optional
+	Clusters []PoolerClusterSecrets `json:"clusters,omitempty"`
+}
+
+// PoolerClusterSecrets contains the versions of the secrets
+// used to connect to a cluster serving some databases of PgBouncer
+type PoolerClusterSecrets struct {
+	// The name of the cluster
+	Cluster string `json:"cluster"`
+
+	// The server CA secret version
+	// +optional
+	ServerCA SecretVersion `json:"serverCA,omitempty"`
+
+	// The auth query secret version
+	// +optional
+	AuthQuery SecretVersion `json:"authQuery,omitempty"`
 }
 
 // PgBouncerSecrets contains the versions of the secrets used
@@ -38,6 +38,24 @@ const (
 	PublicationReclaimRetain PublicationReclaimPolicy = "retain"
 )
 
+// PublicationOperation is a DML operation published by a publication
+// +kubebuilder:validation:Enum=insert;update;delete;truncate
+type PublicationOperation string
+
+const (
+	// PublicationOperationInsert publishes the INSERT operations
+	PublicationOperationInsert PublicationOperation = "insert"
+
+	// PublicationOperationUpdate publishes the UPDATE operations
+	PublicationOperationUpdate PublicationOperation = "update"
+
+	// PublicationOperationDelete publishes the DELETE operations
+	PublicationOperationDelete PublicationOperation = "delete"
+
+	// PublicationOperationTruncate publishes the TRUNCATE operations
+	PublicationOperationTruncate PublicationOperation = "truncate"
+)
+
 // PublicationSpec defines the desired state of Publication
 type PublicationSpec struct {
 	// The name of the PostgreSQL cluster that identifies the "publisher"
@@ -58,6 +76,23 @@ type PublicationSpec struct {
 	// +optional
 	Parameters map[string]string `json:"parameters,omitempty"`
 
+	// The DML operations published by the publication, corresponding
+	// to the `publish` publication parameter. When not set here nor in
+	// the parameters, all the operations are published, which is the
+	// PostgreSQL default
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	// +optional
+	Publish []PublicationOperation `json:"publish,omitempty"`
+
+	// Whether the changes of a partition are published using the
+	// identity and schema of its partitioned table, corresponding to
+	// the `publish_via_partition_root` publication parameter.
+	// When not set here nor in the parameters, the PostgreSQL default
+	// `false` is used
+	// +optional
+	PublishViaPartitionRoot *bool `json:"publishViaPartitionRoot,omitempty"`
+
 	// Target of the publication as expected by PostgreSQL `CREATE PUBLICATION` command
 	Target PublicationTarget `json:"target"`
 
@@ -116,6 +151,14 @@ type PublicationTargetTable struct {
 	// The columns to publish
 	// +optional
 	Columns []string `json:"columns,omitempty"`
+
+	// The row filter expression, corresponding to the `WHERE` clause
+	// of the table in PostgreSQL. Only the rows satisfying the
+	// expression are published. Semicolons, comments, dollar quoting,
+	// backslashes and unbalanced parentheses are not allowed.
+	// Requires PostgreSQL 15 or later
+	// +optional
+	Where string `json:"where,omitempty"`
 }
 
 // PublicationStatus defines the observed state of Publication
@@ -90,6 +90,9 @@ func (scheduledBackup *ScheduledBackup) CreateBackup(name string) *Backup {
 			Online:              scheduledBackup.Spec.Online,
 			OnlineConfiguration: scheduledBackup.Spec.OnlineConfiguration,
 			PluginConfiguration: scheduledBackup.Spec.PluginConfiguration,
+			Type:                scheduledBackup.Spec.Type,
+			Databases:           scheduledBackup.Spec.Databases,
+			Verification:        scheduledBackup.Spec.Verification,
 		},
 	}
 	utils.InheritAnnotations(&backup.ObjectMeta, scheduledBackup.Annotations, nil, configuration.Current)
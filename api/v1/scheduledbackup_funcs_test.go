@@ -84,4 +84,22 @@ var _ = Describe("Scheduled backup", func() {
 		Expect(backup.ObjectMeta.Name).To(BeEquivalentTo(backupName))
 		Expect(backup.Spec.Target).To(BeEquivalentTo(BackupTargetPrimary))
 	})
+
+	It("properly creates a pgBaseBackup backup with the requested type", func() {
+		scheduledBackup.Spec.Method = BackupMethodPgBaseBackup
+		scheduledBackup.Spec.Type = BackupTypeIncremental
+		backup := scheduledBackup.CreateBackup("test")
+		Expect(backup).ToNot(BeNil())
+		Expect(backup.Spec.Method).To(Equal(BackupMethodPgBaseBackup))
+		Expect(backup.Spec.Type).To(Equal(BackupTypeIncremental))
+	})
+
+	It("properly creates a logical backup of the requested databases", func() {
+		scheduledBackup.Spec.Method = BackupMethodLogical
+		scheduledBackup.Spec.Databases = []string{"app", "reporting"}
+		backup := scheduledBackup.CreateBackup("test")
+		Expect(backup).ToNot(BeNil())
+		Expect(backup.Spec.Method).To(Equal(BackupMethodLogical))
+		Expect(backup.Spec.Databases).To(Equal([]string{"app", "reporting"}))
+	})
 })
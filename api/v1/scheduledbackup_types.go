@@ -62,13 +62,15 @@ type ScheduledBackupSpec struct {
 	Target BackupTarget `json:"target,omitempty"`
 
 	// The backup method to be used, possible options are `barmanObjectStore`,
-	// `volumeSnapshot` or `plugin`. Defaults to: `barmanObjectStore`.
+	// `volumeSnapshot`, `plugin`, `pgBaseBackup` or `logical`. Defaults to: `barmanObjectStore`.
 	// +optional
-	// +kubebuilder:validation:Enum=barmanObjectStore;volumeSnapshot;plugin
+	// +kubebuilder:validation:Enum=barmanObjectStore;volumeSnapshot;plugin;pgBaseBackup;logical
 	// +kubebuilder:default:=barmanObjectStore
 	Method BackupMethod `json:"method,omitempty"`
 
-	// Configuration parameters passed to the plugin managing this backup
+	// Configuration parameters passed to the plugin managing this backup.
+	// Plugins can't be used as a destination by the `logical` method,
+	// which only stores the dumps in the volume configured in the cluster
 	// +optional
 	PluginConfiguration *BackupPluginConfiguration `json:"pluginConfiguration,omitempty"`
 
@@ -82,6 +84,24 @@ type ScheduledBackupSpec struct {
 	// Overrides the default settings specified in the cluster '.backup.volumeSnapshot.onlineConfiguration' stanza
 	// +optional
 	OnlineConfiguration *OnlineConfiguration `json:"onlineConfiguration,omitempty"`
+
+	// The type of backup to be taken with the `pgBaseBackup` method, possible
+	// options are `full`, `incremental` and `differential`. Incremental and
+	// differential backups require PostgreSQL 17 or later. Defaults to: `full`.
+	// +optional
+	// +kubebuilder:validation:Enum=full;incremental;differential
+	Type BackupType `json:"type,omitempty"`
+
+	// The list of databases to be exported with the `logical` method. When
+	// empty, every database accepting connections is exported, except
+	// templates and the `postgres` database
+	// +optional
+	Databases []string `json:"databases,omitempty"`
+
+	// When set, each backup is restored into a throwaway instance
+	// once completed, to verify it
+	// +optional
+	Verification *BackupVerificationConfiguration `json:"verification,omitempty"`
 }
 
 // ScheduledBackupStatus defines the observed state of ScheduledBackup
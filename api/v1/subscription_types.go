@@ -38,7 +38,73 @@ const (
 	SubscriptionReclaimRetain SubscriptionReclaimPolicy = "retain"
 )
 
+// SubscriptionStreaming is the way a subscription applies the
+// changes of the in-progress transactions
+// +kubebuilder:validation:Enum=off;on;parallel
+type SubscriptionStreaming string
+
+const (
+	// SubscriptionStreamingOff means that the changes of the transactions
+	// are sent to the subscriber only once they are committed
+	SubscriptionStreamingOff SubscriptionStreaming = "off"
+
+	// SubscriptionStreamingOn means that the changes of the in-progress
+	// transactions are written to temporary files on the subscriber, and
+	// applied once the transaction is committed
+	SubscriptionStreamingOn SubscriptionStreaming = "on"
+
+	// SubscriptionStreamingParallel means that the changes of the
+	// in-progress transactions are applied by parallel apply workers.
+	// Requires PostgreSQL 16 or later
+	SubscriptionStreamingParallel SubscriptionStreaming = "parallel"
+)
+
+// SubscriptionOrigin controls which changes the publisher sends,
+// depending on their origin
+// +kubebuilder:validation:Enum=any;none
+type SubscriptionOrigin string
+
+const (
+	// SubscriptionOriginAny means that the publisher sends every change,
+	// regardless of its origin
+	SubscriptionOriginAny SubscriptionOrigin = "any"
+
+	// SubscriptionOriginNone means that the publisher only sends the
+	// changes that don't have an origin, i.e. that weren't replicated
+	SubscriptionOriginNone SubscriptionOrigin = "none"
+)
+
+// SubscriptionTableState is the synchronization state of a table of
+// a subscription, as reported by `pg_subscription_rel`
+type SubscriptionTableState string
+
+const (
+	// SubscriptionTableStateInitialize means that the synchronization
+	// of the table is being initialized
+	SubscriptionTableStateInitialize SubscriptionTableState = "initialize"
+
+	// SubscriptionTableStateDataCopy means that the data of the table
+	// is being copied
+	SubscriptionTableStateDataCopy SubscriptionTableState = "dataCopy"
+
+	// SubscriptionTableStateFinishedCopy means that the initial copy
+	// of the data of the table is finished
+	SubscriptionTableStateFinishedCopy SubscriptionTableState = "finishedCopy"
+
+	// SubscriptionTableStateSynchronized means that the table has been
+	// synchronized with the apply worker
+	SubscriptionTableStateSynchronized SubscriptionTableState = "synchronized"
+
+	// SubscriptionTableStateReady means that the table is replicated
+	// by the apply worker
+	SubscriptionTableStateReady SubscriptionTableState = "ready"
+)
+
 // SubscriptionSpec defines the desired state of Subscription
+// +kubebuilder:validation:XValidation:rule="!has(self.streaming) || !has(self.parameters) || !('streaming' in self.parameters)",message="streaming cannot be set in both streaming and parameters"
+// +kubebuilder:validation:XValidation:rule="!has(self.disableOnError) || !has(self.parameters) || !('disable_on_error' in self.parameters)",message="disable_on_error cannot be set in both disableOnError and parameters"
+// +kubebuilder:validation:XValidation:rule="!has(self.origin) || !has(self.parameters) || !('origin' in self.parameters)",message="origin cannot be set in both origin and parameters"
+// +kubebuilder:validation:XValidation:rule="!has(self.failover) || !has(self.parameters) || !('failover' in self.parameters)",message="failover cannot be set in both failover and parameters"
 type SubscriptionSpec struct {
 	// The name of the PostgreSQL cluster that identifies the "subscriber"
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="cluster reference is immutable after creation"
@@ -61,6 +127,45 @@ type SubscriptionSpec struct {
 	// +optional
 	Parameters map[string]string `json:"parameters,omitempty"`
 
+	// How the changes of the in-progress transactions are applied,
+	// corresponding to the `streaming` subscription parameter
+	// +optional
+	Streaming SubscriptionStreaming `json:"streaming,omitempty"`
+
+	// Whether the subscription is disabled when the apply worker detects
+	// an error, corresponding to the `disable_on_error` subscription
+	// parameter. Requires PostgreSQL 15 or later
+	// +optional
+	DisableOnError *bool `json:"disableOnError,omitempty"`
+
+	// Which changes the publisher sends depending on their origin,
+	// corresponding to the `origin` subscription parameter.
+	// Requires PostgreSQL 16 or later
+	// +optional
+	Origin SubscriptionOrigin `json:"origin,omitempty"`
+
+	// Whether the replication slot of the subscription is synchronized
+	// to the standbys of the publisher, corresponding to the `failover`
+	// subscription parameter. It can only be changed while the
+	// subscription is disabled. Requires PostgreSQL 17 or later
+	// +optional
+	Failover *bool `json:"failover,omitempty"`
+
+	// The LSN of the remote transaction to be skipped by the apply worker,
+	// as reported in the subscriber log when the transaction fails,
+	// corresponding to `ALTER SUBSCRIPTION ... SKIP`. Each LSN is applied
+	// only once. Requires PostgreSQL 15 or later
+	// +kubebuilder:validation:Pattern=`^[0-9A-Fa-f]{1,8}/[0-9A-Fa-f]{1,8}$`
+	// +optional
+	SkipLSN string `json:"skipLSN,omitempty"`
+
+	// Whether the values of the sequences are periodically copied from
+	// the publisher, as logical replication doesn't replicate them. Only
+	// the sequences existing in both databases are updated, and the user
+	// of the external cluster needs to be allowed to read them
+	// +optional
+	SyncSequences bool `json:"syncSequences,omitempty"`
+
 	// The name of the publication inside the PostgreSQL database in the
 	// "publisher"
 	PublicationName string `json:"publicationName"`
@@ -94,6 +199,85 @@ type SubscriptionStatus struct {
 	// Message is the reconciliation output message
 	// +optional
 	Message string `json:"message,omitempty"`
+
+	// SkippedLSN is the latest LSN requested through `skipLSN` that
+	// was applied to the subscription
+	// +optional
+	SkippedLSN string `json:"skippedLSN,omitempty"`
+
+	// Statistics are the statistics of the subscription, periodically
+	// refreshed by the primary instance
+	// +optional
+	Statistics *SubscriptionStatistics `json:"statistics,omitempty"`
+
+	// SequencesSyncedAt is when the sequences were last copied from
+	// the publisher, when `syncSequences` is enabled
+	// +optional
+	SequencesSyncedAt *metav1.Time `json:"sequencesSyncedAt,omitempty"`
+}
+
+// SubscriptionStatistics are the statistics of a subscription, read from
+// `pg_stat_subscription`, `pg_stat_subscription_stats` and
+// `pg_subscription_rel`
+type SubscriptionStatistics struct {
+	// UpdateTime is when the statistics were collected
+	UpdateTime metav1.Time `json:"updateTime"`
+
+	// Enabled is true if the subscription is enabled. A subscription can
+	// be disabled by the apply worker when `disableOnError` is set
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// ReceivedLSN is the last write-ahead log location received
+	// +optional
+	ReceivedLSN string `json:"receivedLSN,omitempty"`
+
+	// LatestEndLSN is the last write-ahead log location reported
+	// to the publisher
+	// +optional
+	LatestEndLSN string `json:"latestEndLSN,omitempty"`
+
+	// LatestEndTime is the time of the last write-ahead log location
+	// reported to the publisher
+	// +optional
+	LatestEndTime *metav1.Time `json:"latestEndTime,omitempty"`
+
+	// ApplyErrorCount is the number of errors that occurred while
+	// applying changes. Requires PostgreSQL 15 or later
+	// +optional
+	ApplyErrorCount int64 `json:"applyErrorCount,omitempty"`
+
+	// SyncErrorCount is the number of errors that occurred during
+	// the initial table synchronization. Requires PostgreSQL 15 or later
+	// +optional
+	SyncErrorCount int64 `json:"syncErrorCount,omitempty"`
+
+	// ReadyTables is the number of tables in the `ready` state
+	// +optional
+	ReadyTables int32 `json:"readyTables,omitempty"`
+
+	// Tables are the tables of the subscription which are not in
+	// the `ready` state yet, with their synchronization state
+	// +optional
+	Tables []SubscriptionTableStatus `json:"tables,omitempty"`
+}
+
+// SubscriptionTableStatus is the synchronization state of a table
+// of a subscription
+type SubscriptionTableStatus struct {
+	// Schema is the schema of the table
+	Schema string `json:"schema"`
+
+	// Name is the name of the table
+	Name string `json:"name"`
+
+	// State is the synchronization state of the table
+	State SubscriptionTableState `json:"state"`
+
+	// LSN is the remote LSN of the state change used for synchronization
+	// coordination when in the `synchronized` or `ready` state
+	// +optional
+	LSN string `json:"lsn,omitempty"`
 }
 
 // +genclient
@@ -0,0 +1,98 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1
+
+import (
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// GetClusterRef returns the cluster reference of the switchover
+func (switchover *Switchover) GetClusterRef() corev1.LocalObjectReference {
+	return switchover.Spec.Cluster
+}
+
+// SetAsPending marks the switchover as waiting for its window or its
+// preconditions, explaining why with the given message
+func (switchoverStatus *SwitchoverStatus) SetAsPending(message string) {
+	switchoverStatus.Phase = SwitchoverPhasePending
+	switchoverStatus.Message = message
+}
+
+// SetAsRunning marks the switchover as started between the passed instances
+func (switchoverStatus *SwitchoverStatus) SetAsRunning(oldPrimary, newPrimary string) {
+	switchoverStatus.Phase = SwitchoverPhaseRunning
+	switchoverStatus.Message = ""
+	switchoverStatus.OldPrimary = oldPrimary
+	switchoverStatus.NewPrimary = newPrimary
+	switchoverStatus.StartedAt = ptr.To(metav1.Now())
+}
+
+// SetAsCompleted marks the switchover as completed on the passed timeline
+func (switchoverStatus *SwitchoverStatus) SetAsCompleted(timeLineID int) {
+	switchoverStatus.Phase = SwitchoverPhaseCompleted
+	switchoverStatus.Message = ""
+	switchoverStatus.TimeLineID = timeLineID
+	switchoverStatus.StoppedAt = ptr.To(metav1.Now())
+}
+
+// SetAsFailed marks the switchover as failed with the given error
+func (switchoverStatus *SwitchoverStatus) SetAsFailed(err error) {
+	switchoverStatus.Phase = SwitchoverPhaseFailed
+	switchoverStatus.Message = err.Error()
+	switchoverStatus.StoppedAt = ptr.To(metav1.Now())
+}
+
+// IsDone checks if the switchover reached a final phase
+func (switchoverStatus *SwitchoverStatus) IsDone() bool {
+	return switchoverStatus.Phase == SwitchoverPhaseCompleted ||
+		switchoverStatus.Phase == SwitchoverPhaseFailed
+}
+
+// TimeToWindowStart returns how long we need to wait before the
+// switchover window opens, or zero if it is already open
+func (switchover *Switchover) TimeToWindowStart(now time.Time) time.Duration {
+	if switchover.Spec.NotBefore == nil || !now.Before(switchover.Spec.NotBefore.Time) {
+		return 0
+	}
+	return switchover.Spec.NotBefore.Sub(now)
+}
+
+// IsWindowExpired checks if the switchover window closed at the passed time
+func (switchover *Switchover) IsWindowExpired(now time.Time) bool {
+	return switchover.Spec.NotAfter != nil && now.After(switchover.Spec.NotAfter.Time)
+}
+
+// SortByCreationTimeAndName sorts the switchover items in creation time order and,
+// in case of switchovers with the same creation time, in alphabetical order
+func (list *SwitchoverList) SortByCreationTimeAndName() {
+	sort.Slice(list.Items, func(i, j int) bool {
+		ti := list.Items[i].CreationTimestamp.Time
+		tj := list.Items[j].CreationTimestamp.Time
+		if ti.Equal(tj) {
+			return list.Items[i].Name < list.Items[j].Name
+		}
+		return ti.Before(tj)
+	})
+}
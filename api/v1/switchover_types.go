@@ -0,0 +1,150 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SwitchoverPhase is the phase of a planned switchover
+type SwitchoverPhase string
+
+const (
+	// SwitchoverPhasePending means that the switchover is waiting
+	// for its time window to open or for its preconditions to be met
+	SwitchoverPhasePending SwitchoverPhase = "pending"
+
+	// SwitchoverPhaseRunning means that the operator requested the
+	// promotion of the target instance and is waiting for it to complete
+	SwitchoverPhaseRunning SwitchoverPhase = "running"
+
+	// SwitchoverPhaseCompleted means that the target instance has been
+	// promoted and is now the primary of the cluster
+	SwitchoverPhaseCompleted SwitchoverPhase = "completed"
+
+	// SwitchoverPhaseFailed means that the switchover could not be executed
+	// and will not be retried
+	SwitchoverPhaseFailed SwitchoverPhase = "failed"
+)
+
+// SwitchoverSpec defines the desired state of Switchover
+// +kubebuilder:validation:XValidation:rule="oldSelf == self",message="SwitchoverSpec is immutable once set"
+type SwitchoverSpec struct {
+	// The cluster where the switchover will be executed
+	Cluster corev1.LocalObjectReference `json:"cluster"`
+
+	// The name of the instance to be promoted. When empty, the operator
+	// selects the most advanced replica, following the same ordering used
+	// for failovers
+	// +optional
+	TargetInstance string `json:"targetInstance,omitempty"`
+
+	// The switchover will not be started before this time
+	// +optional
+	NotBefore *metav1.Time `json:"notBefore,omitempty"`
+
+	// The switchover will not be started after this time. If the window
+	// closes before the preconditions are met, the switchover fails
+	// +optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+
+	// The maximum replay lag, in bytes, of the target instance with respect
+	// to the current primary. The switchover will wait, within its time
+	// window, for the target instance to catch up
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxLagBytes *int64 `json:"maxLagBytes,omitempty"`
+
+	// The reason why this switchover has been requested. It is recorded
+	// in the Kubernetes events for auditing purposes
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// SwitchoverStatus defines the observed state of Switchover
+type SwitchoverStatus struct {
+	// The current phase of the switchover
+	// +optional
+	Phase SwitchoverPhase `json:"phase,omitempty"`
+
+	// A human-readable message explaining the current phase
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// When the operator requested the promotion of the target instance
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// When the switchover was completed or failed
+	// +optional
+	StoppedAt *metav1.Time `json:"stoppedAt,omitempty"`
+
+	// The primary instance before the switchover
+	// +optional
+	OldPrimary string `json:"oldPrimary,omitempty"`
+
+	// The primary instance after the switchover
+	// +optional
+	NewPrimary string `json:"newPrimary,omitempty"`
+
+	// The timeline of the cluster after the promotion
+	// +optional
+	TimeLineID int `json:"timeLineID,omitempty"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.cluster.name"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Old primary",type="string",JSONPath=".status.oldPrimary"
+// +kubebuilder:printcolumn:name="New primary",type="string",JSONPath=".status.newPrimary"
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.message"
+
+// Switchover is a request to promote a replica of a Cluster in a planned
+// way, keeping an auditable record of the operation
+type Switchover struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Specification of the desired behavior of the switchover.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Spec SwitchoverSpec `json:"spec"`
+	// Most recently observed status of the switchover. This data may not be up to
+	// date. Populated by the system. Read-only.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	// +optional
+	Status SwitchoverStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SwitchoverList contains a list of Switchover
+type SwitchoverList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of switchovers
+	Items []Switchover `json:"items"`
+}
@@ -135,6 +135,16 @@ func (in *BackupConfiguration) DeepCopyInto(out *BackupConfiguration) {
 		*out = new(BarmanObjectStoreConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PgBaseBackup != nil {
+		in, out := &in.PgBaseBackup, &out.PgBaseBackup
+		*out = new(PgBaseBackupConfiguration)
+		**out = **in
+	}
+	if in.Logical != nil {
+		in, out := &in.Logical, &out.Logical
+		*out = new(LogicalBackupConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupConfiguration.
@@ -179,6 +189,41 @@ func (in *BackupList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupLogicalStatus) DeepCopyInto(out *BackupLogicalStatus) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]LogicalBackupDatabaseStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupLogicalStatus.
+func (in *BackupLogicalStatus) DeepCopy() *BackupLogicalStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupLogicalStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupPgBaseBackupStatus) DeepCopyInto(out *BackupPgBaseBackupStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupPgBaseBackupStatus.
+func (in *BackupPgBaseBackupStatus) DeepCopy() *BackupPgBaseBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupPgBaseBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupPluginConfiguration) DeepCopyInto(out *BackupPluginConfiguration) {
 	*out = *in
@@ -276,6 +321,16 @@ func (in *BackupSpec) DeepCopyInto(out *BackupSpec) {
 		*out = new(OnlineConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(BackupVerificationConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupSpec.
@@ -341,6 +396,28 @@ func (in *BackupStatus) DeepCopyInto(out *BackupStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.PgBaseBackupStatus != nil {
+		in, out := &in.PgBaseBackupStatus, &out.PgBaseBackupStatus
+		*out = new(BackupPgBaseBackupStatus)
+		**out = **in
+	}
+	if in.LogicalBackupStatus != nil {
+		in, out := &in.LogicalBackupStatus, &out.LogicalBackupStatus
+		*out = new(BackupLogicalStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(BackupVerificationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupStatus.
@@ -353,6 +430,84 @@ func (in *BackupStatus) DeepCopy() *BackupStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupVerificationAssertion) DeepCopyInto(out *BackupVerificationAssertion) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupVerificationAssertion.
+func (in *BackupVerificationAssertion) DeepCopy() *BackupVerificationAssertion {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupVerificationAssertion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupVerificationCheckStatus) DeepCopyInto(out *BackupVerificationCheckStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupVerificationCheckStatus.
+func (in *BackupVerificationCheckStatus) DeepCopy() *BackupVerificationCheckStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupVerificationCheckStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupVerificationConfiguration) DeepCopyInto(out *BackupVerificationConfiguration) {
+	*out = *in
+	if in.Assertions != nil {
+		in, out := &in.Assertions, &out.Assertions
+		*out = make([]BackupVerificationAssertion, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupVerificationConfiguration.
+func (in *BackupVerificationConfiguration) DeepCopy() *BackupVerificationConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupVerificationConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupVerificationStatus) DeepCopyInto(out *BackupVerificationStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.StoppedAt != nil {
+		in, out := &in.StoppedAt, &out.StoppedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]BackupVerificationCheckStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupVerificationStatus.
+func (in *BackupVerificationStatus) DeepCopy() *BackupVerificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupVerificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BootstrapConfiguration) DeepCopyInto(out *BootstrapConfiguration) {
 	*out = *in
@@ -763,6 +918,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(ImageCatalogRef)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(ReplicaAutoscalingConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	in.PostgresConfiguration.DeepCopyInto(&out.PostgresConfiguration)
 	if in.PodSelectorRefs != nil {
 		in, out := &in.PodSelectorRefs, &out.PodSelectorRefs
@@ -827,6 +987,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.SwitchoverDrain != nil {
+		in, out := &in.SwitchoverDrain, &out.SwitchoverDrain
+		*out = new(SwitchoverDrainConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.LivenessProbeTimeout != nil {
 		in, out := &in.LivenessProbeTimeout, &out.LivenessProbeTimeout
 		*out = new(int32)
@@ -846,6 +1011,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(EphemeralVolumesSizeLimitConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 	if in.Backup != nil {
 		in, out := &in.Backup, &out.Backup
 		*out = new(BackupConfiguration)
@@ -951,6 +1121,16 @@ func (in *ClusterSpec) DeepCopy() *ClusterSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 	*out = *in
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(ReplicaAutoscalingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SwitchoverDrain != nil {
+		in, out := &in.SwitchoverDrain, &out.SwitchoverDrain
+		*out = new(SwitchoverDrainStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.InstancesStatus != nil {
 		in, out := &in.InstancesStatus, &out.InstancesStatus
 		*out = make(map[PodStatus][]string, len(*in))
@@ -1221,6 +1401,32 @@ func (in *DatabaseObjectStatus) DeepCopy() *DatabaseObjectStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseRestoreSource) DeepCopyInto(out *DatabaseRestoreSource) {
+	*out = *in
+	out.BackupRef = in.BackupRef
+	if in.Schemas != nil {
+		in, out := &in.Schemas, &out.Schemas
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseRestoreSource.
+func (in *DatabaseRestoreSource) DeepCopy() *DatabaseRestoreSource {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseRestoreSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatabaseRole) DeepCopyInto(out *DatabaseRole) {
 	*out = *in
@@ -1392,6 +1598,25 @@ func (in *DatabaseSpec) DeepCopyInto(out *DatabaseSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Grants != nil {
+		in, out := &in.Grants, &out.Grants
+		*out = make([]GrantSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DefaultPrivileges != nil {
+		in, out := &in.DefaultPrivileges, &out.DefaultPrivileges
+		*out = make([]DefaultPrivilegeSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Restore != nil {
+		in, out := &in.Restore, &out.Restore
+		*out = new(DatabaseRestoreSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseSpec.
@@ -1432,6 +1657,28 @@ func (in *DatabaseStatus) DeepCopyInto(out *DatabaseStatus) {
 		*out = make([]DatabaseObjectStatus, len(*in))
 		copy(*out, *in)
 	}
+	if in.Grants != nil {
+		in, out := &in.Grants, &out.Grants
+		*out = make([]DatabaseObjectStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.DefaultPrivileges != nil {
+		in, out := &in.DefaultPrivileges, &out.DefaultPrivileges
+		*out = make([]DatabaseObjectStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.UserMappings != nil {
+		in, out := &in.UserMappings, &out.UserMappings
+		*out = make([]DatabaseObjectStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecretResourceVersions != nil {
+		in, out := &in.SecretResourceVersions, &out.SecretResourceVersions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseStatus.
@@ -1444,6 +1691,26 @@ func (in *DatabaseStatus) DeepCopy() *DatabaseStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefaultPrivilegeSpec) DeepCopyInto(out *DefaultPrivilegeSpec) {
+	*out = *in
+	if in.Privileges != nil {
+		in, out := &in.Privileges, &out.Privileges
+		*out = make([]Privilege, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultPrivilegeSpec.
+func (in *DefaultPrivilegeSpec) DeepCopy() *DefaultPrivilegeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DefaultPrivilegeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EmbeddedObjectMetadata) DeepCopyInto(out *EmbeddedObjectMetadata) {
 	*out = *in
@@ -1726,6 +1993,26 @@ func (in *FailoverQuorumStatus) DeepCopy() *FailoverQuorumStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrantSpec) DeepCopyInto(out *GrantSpec) {
+	*out = *in
+	if in.Privileges != nil {
+		in, out := &in.Privileges, &out.Privileges
+		*out = make([]Privilege, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrantSpec.
+func (in *GrantSpec) DeepCopy() *GrantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImageCatalog) DeepCopyInto(out *ImageCatalog) {
 	*out = *in
@@ -2068,108 +2355,329 @@ func (in *LivenessProbe) DeepCopy() *LivenessProbe {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManagedConfiguration) DeepCopyInto(out *ManagedConfiguration) {
+func (in *LogicalBackupConfiguration) DeepCopyInto(out *LogicalBackupConfiguration) {
 	*out = *in
-	if in.Roles != nil {
-		in, out := &in.Roles, &out.Roles
-		*out = make([]RoleConfiguration, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Services != nil {
-		in, out := &in.Services, &out.Services
-		*out = new(ManagedServices)
-		(*in).DeepCopyInto(*out)
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = new(LocalObjectReference)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedConfiguration.
-func (in *ManagedConfiguration) DeepCopy() *ManagedConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogicalBackupConfiguration.
+func (in *LogicalBackupConfiguration) DeepCopy() *LogicalBackupConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(ManagedConfiguration)
+	out := new(LogicalBackupConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManagedRoles) DeepCopyInto(out *ManagedRoles) {
+func (in *LogicalBackupDatabaseStatus) DeepCopyInto(out *LogicalBackupDatabaseStatus) {
 	*out = *in
-	if in.ByStatus != nil {
-		in, out := &in.ByStatus, &out.ByStatus
-		*out = make(map[RoleStatus][]string, len(*in))
-		for key, val := range *in {
-			var outVal []string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				inVal := (*in)[key]
-				in, out := &inVal, &outVal
-				*out = make([]string, len(*in))
-				copy(*out, *in)
-			}
-			(*out)[key] = outVal
-		}
-	}
-	if in.CannotReconcile != nil {
-		in, out := &in.CannotReconcile, &out.CannotReconcile
-		*out = make(map[string][]string, len(*in))
-		for key, val := range *in {
-			var outVal []string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				inVal := (*in)[key]
-				in, out := &inVal, &outVal
-				*out = make([]string, len(*in))
-				copy(*out, *in)
-			}
-			(*out)[key] = outVal
-		}
-	}
-	if in.PasswordStatus != nil {
-		in, out := &in.PasswordStatus, &out.PasswordStatus
-		*out = make(map[string]PasswordState, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedRoles.
-func (in *ManagedRoles) DeepCopy() *ManagedRoles {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogicalBackupDatabaseStatus.
+func (in *LogicalBackupDatabaseStatus) DeepCopy() *LogicalBackupDatabaseStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ManagedRoles)
+	out := new(LogicalBackupDatabaseStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManagedService) DeepCopyInto(out *ManagedService) {
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
 	*out = *in
-	in.ServiceTemplate.DeepCopyInto(&out.ServiceTemplate)
+	out.Duration = in.Duration
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedService.
-func (in *ManagedService) DeepCopy() *ManagedService {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
 	if in == nil {
 		return nil
 	}
-	out := new(ManagedService)
+	out := new(MaintenanceWindow)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManagedServices) DeepCopyInto(out *ManagedServices) {
+func (in *MajorMigration) DeepCopyInto(out *MajorMigration) {
 	*out = *in
-	if in.DisabledDefaultServices != nil {
-		in, out := &in.DisabledDefaultServices, &out.DisabledDefaultServices
-		*out = make([]ServiceSelectorType, len(*in))
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MajorMigration.
+func (in *MajorMigration) DeepCopy() *MajorMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(MajorMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MajorMigration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MajorMigrationCutoverStatus) DeepCopyInto(out *MajorMigrationCutoverStatus) {
+	*out = *in
+	in.StartedAt.DeepCopyInto(&out.StartedAt)
+	if in.PausedPoolers != nil {
+		in, out := &in.PausedPoolers, &out.PausedPoolers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.WritesStoppedAt != nil {
+		in, out := &in.WritesStoppedAt, &out.WritesStoppedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.PoolersSwitchedAt != nil {
+		in, out := &in.PoolersSwitchedAt, &out.PoolersSwitchedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MajorMigrationCutoverStatus.
+func (in *MajorMigrationCutoverStatus) DeepCopy() *MajorMigrationCutoverStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MajorMigrationCutoverStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MajorMigrationDatabaseStatus) DeepCopyInto(out *MajorMigrationDatabaseStatus) {
+	*out = *in
+	if in.LagBytes != nil {
+		in, out := &in.LagBytes, &out.LagBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SequencesSyncedAt != nil {
+		in, out := &in.SequencesSyncedAt, &out.SequencesSyncedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MajorMigrationDatabaseStatus.
+func (in *MajorMigrationDatabaseStatus) DeepCopy() *MajorMigrationDatabaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MajorMigrationDatabaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MajorMigrationList) DeepCopyInto(out *MajorMigrationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MajorMigration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MajorMigrationList.
+func (in *MajorMigrationList) DeepCopy() *MajorMigrationList {
+	if in == nil {
+		return nil
+	}
+	out := new(MajorMigrationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MajorMigrationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MajorMigrationSpec) DeepCopyInto(out *MajorMigrationSpec) {
+	*out = *in
+	out.SourceCluster = in.SourceCluster
+	out.TargetCluster = in.TargetCluster
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CutoverTimeout != nil {
+		in, out := &in.CutoverTimeout, &out.CutoverTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MajorMigrationSpec.
+func (in *MajorMigrationSpec) DeepCopy() *MajorMigrationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MajorMigrationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MajorMigrationStatus) DeepCopyInto(out *MajorMigrationStatus) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]MajorMigrationDatabaseStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Cutover != nil {
+		in, out := &in.Cutover, &out.Cutover
+		*out = new(MajorMigrationCutoverStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StoppedAt != nil {
+		in, out := &in.StoppedAt, &out.StoppedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MajorMigrationStatus.
+func (in *MajorMigrationStatus) DeepCopy() *MajorMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MajorMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedConfiguration) DeepCopyInto(out *ManagedConfiguration) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]RoleConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = new(ManagedServices)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedConfiguration.
+func (in *ManagedConfiguration) DeepCopy() *ManagedConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedRoles) DeepCopyInto(out *ManagedRoles) {
+	*out = *in
+	if in.ByStatus != nil {
+		in, out := &in.ByStatus, &out.ByStatus
+		*out = make(map[RoleStatus][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.CannotReconcile != nil {
+		in, out := &in.CannotReconcile, &out.CannotReconcile
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.PasswordStatus != nil {
+		in, out := &in.PasswordStatus, &out.PasswordStatus
+		*out = make(map[string]PasswordState, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedRoles.
+func (in *ManagedRoles) DeepCopy() *ManagedRoles {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedRoles)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedService) DeepCopyInto(out *ManagedService) {
+	*out = *in
+	in.ServiceTemplate.DeepCopyInto(&out.ServiceTemplate)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedService.
+func (in *ManagedService) DeepCopy() *ManagedService {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedServices) DeepCopyInto(out *ManagedServices) {
+	*out = *in
+	if in.DisabledDefaultServices != nil {
+		in, out := &in.DisabledDefaultServices, &out.DisabledDefaultServices
+		*out = make([]ServiceSelectorType, len(*in))
 		copy(*out, *in)
 	}
 	if in.Additional != nil {
@@ -2262,6 +2770,21 @@ func (in *MonitoringConfiguration) DeepCopyInto(out *MonitoringConfiguration) {
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.QueryStatistics != nil {
+		in, out := &in.QueryStatistics, &out.QueryStatistics
+		*out = new(QueryStatisticsConfiguration)
+		**out = **in
+	}
+	if in.OTLP != nil {
+		in, out := &in.OTLP, &out.OTLP
+		*out = new(OTLPConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SlowQueryLog != nil {
+		in, out := &in.SlowQueryLog, &out.SlowQueryLog
+		*out = new(SlowQueryLogConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringConfiguration.
@@ -2294,6 +2817,41 @@ func (in *NodeMaintenanceWindow) DeepCopy() *NodeMaintenanceWindow {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OTLPConfiguration) DeepCopyInto(out *OTLPConfiguration) {
+	*out = *in
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Logs != nil {
+		in, out := &in.Logs, &out.Logs
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Traces != nil {
+		in, out := &in.Traces, &out.Traces
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ExportInterval != nil {
+		in, out := &in.ExportInterval, &out.ExportInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OTLPConfiguration.
+func (in *OTLPConfiguration) DeepCopy() *OTLPConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(OTLPConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OnlineConfiguration) DeepCopyInto(out *OnlineConfiguration) {
 	*out = *in
@@ -2320,31 +2878,76 @@ func (in *OnlineConfiguration) DeepCopy() *OnlineConfiguration {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OptionSpec) DeepCopyInto(out *OptionSpec) {
+func (in *OptionSpec) DeepCopyInto(out *OptionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OptionSpec.
+func (in *OptionSpec) DeepCopy() *OptionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OptionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordState) DeepCopyInto(out *PasswordState) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordState.
+func (in *PasswordState) DeepCopy() *PasswordState {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBaseBackupConfiguration) DeepCopyInto(out *PgBaseBackupConfiguration) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OptionSpec.
-func (in *OptionSpec) DeepCopy() *OptionSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBaseBackupConfiguration.
+func (in *PgBaseBackupConfiguration) DeepCopy() *PgBaseBackupConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(OptionSpec)
+	out := new(PgBaseBackupConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PasswordState) DeepCopyInto(out *PasswordState) {
+func (in *PgBouncerDatabase) DeepCopyInto(out *PgBouncerDatabase) {
 	*out = *in
+	if in.Cluster != nil {
+		in, out := &in.Cluster, &out.Cluster
+		*out = new(LocalObjectReference)
+		**out = **in
+	}
+	if in.PoolSize != nil {
+		in, out := &in.PoolSize, &out.PoolSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxDBConnections != nil {
+		in, out := &in.MaxDBConnections, &out.MaxDBConnections
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordState.
-func (in *PasswordState) DeepCopy() *PasswordState {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerDatabase.
+func (in *PgBouncerDatabase) DeepCopy() *PgBouncerDatabase {
 	if in == nil {
 		return nil
 	}
-	out := new(PasswordState)
+	out := new(PgBouncerDatabase)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -2435,6 +3038,20 @@ func (in *PgBouncerSpec) DeepCopyInto(out *PgBouncerSpec) {
 		*out = new(ImageCatalogComponentRef)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]PgBouncerDatabase, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]PgBouncerUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerSpec.
@@ -2447,6 +3064,91 @@ func (in *PgBouncerSpec) DeepCopy() *PgBouncerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerUser) DeepCopyInto(out *PgBouncerUser) {
+	*out = *in
+	if in.PoolSize != nil {
+		in, out := &in.PoolSize, &out.PoolSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxUserConnections != nil {
+		in, out := &in.MaxUserConnections, &out.MaxUserConnections
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerUser.
+func (in *PgBouncerUser) DeepCopy() *PgBouncerUser {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgCatDatabase) DeepCopyInto(out *PgCatDatabase) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PoolSize != nil {
+		in, out := &in.PoolSize, &out.PoolSize
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgCatDatabase.
+func (in *PgCatDatabase) DeepCopy() *PgCatDatabase {
+	if in == nil {
+		return nil
+	}
+	out := new(PgCatDatabase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgCatSpec) DeepCopyInto(out *PgCatSpec) {
+	*out = *in
+	in.AuthQuerySecret.DeepCopyInto(&out.AuthQuerySecret)
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]PgCatDatabase, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgCatSpec.
+func (in *PgCatSpec) DeepCopy() *PgCatSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PgCatSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PluginConfiguration) DeepCopyInto(out *PluginConfiguration) {
 	*out = *in
@@ -2620,6 +3322,91 @@ func (in *Pooler) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerAutoscalingConfiguration) DeepCopyInto(out *PoolerAutoscalingConfiguration) {
+	*out = *in
+	if in.TargetClientsWaiting != nil {
+		in, out := &in.TargetClientsWaiting, &out.TargetClientsWaiting
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetMaxWait != nil {
+		in, out := &in.TargetMaxWait, &out.TargetMaxWait
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ScaleDownStabilizationWindow != nil {
+		in, out := &in.ScaleDownStabilizationWindow, &out.ScaleDownStabilizationWindow
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerAutoscalingConfiguration.
+func (in *PoolerAutoscalingConfiguration) DeepCopy() *PoolerAutoscalingConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerAutoscalingConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerAutoscalingStatus) DeepCopyInto(out *PoolerAutoscalingStatus) {
+	*out = *in
+	if in.LastScaleTime != nil {
+		in, out := &in.LastScaleTime, &out.LastScaleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ScaleDownRecommendedSince != nil {
+		in, out := &in.ScaleDownRecommendedSince, &out.ScaleDownRecommendedSince
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerAutoscalingStatus.
+func (in *PoolerAutoscalingStatus) DeepCopy() *PoolerAutoscalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerAutoscalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerClusterSecrets) DeepCopyInto(out *PoolerClusterSecrets) {
+	*out = *in
+	out.ServerCA = in.ServerCA
+	out.AuthQuery = in.AuthQuery
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerClusterSecrets.
+func (in *PoolerClusterSecrets) DeepCopy() *PoolerClusterSecrets {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerClusterSecrets)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerExcludedReplica) DeepCopyInto(out *PoolerExcludedReplica) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerExcludedReplica.
+func (in *PoolerExcludedReplica) DeepCopy() *PoolerExcludedReplica {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerExcludedReplica)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PoolerIntegrations) DeepCopyInto(out *PoolerIntegrations) {
 	*out = *in
@@ -2717,6 +3504,71 @@ func (in *PoolerMonitoringTLSConfiguration) DeepCopy() *PoolerMonitoringTLSConfi
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerReplicaLoadBalancing) DeepCopyInto(out *PoolerReplicaLoadBalancing) {
+	*out = *in
+	out.MaxLag = in.MaxLag
+	if in.CheckInterval != nil {
+		in, out := &in.CheckInterval, &out.CheckInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerReplicaLoadBalancing.
+func (in *PoolerReplicaLoadBalancing) DeepCopy() *PoolerReplicaLoadBalancing {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerReplicaLoadBalancing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerReplicaLoadBalancingStatus) DeepCopyInto(out *PoolerReplicaLoadBalancingStatus) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]PoolerReplicaMember, len(*in))
+		copy(*out, *in)
+	}
+	if in.Excluded != nil {
+		in, out := &in.Excluded, &out.Excluded
+		*out = make([]PoolerExcludedReplica, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerReplicaLoadBalancingStatus.
+func (in *PoolerReplicaLoadBalancingStatus) DeepCopy() *PoolerReplicaLoadBalancingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerReplicaLoadBalancingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerReplicaMember) DeepCopyInto(out *PoolerReplicaMember) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerReplicaMember.
+func (in *PoolerReplicaMember) DeepCopy() *PoolerReplicaMember {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerReplicaMember)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PoolerSecrets) DeepCopyInto(out *PoolerSecrets) {
 	*out = *in
@@ -2729,6 +3581,11 @@ func (in *PoolerSecrets) DeepCopyInto(out *PoolerSecrets) {
 		*out = new(PgBouncerSecrets)
 		**out = **in
 	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]PoolerClusterSecrets, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerSecrets.
@@ -2760,6 +3617,11 @@ func (in *PoolerSpec) DeepCopyInto(out *PoolerSpec) {
 		*out = new(PgBouncerSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PgCat != nil {
+		in, out := &in.PgCat, &out.PgCat
+		*out = new(PgCatSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.DeploymentStrategy != nil {
 		in, out := &in.DeploymentStrategy, &out.DeploymentStrategy
 		*out = new(appsv1.DeploymentStrategy)
@@ -2775,6 +3637,16 @@ func (in *PoolerSpec) DeepCopyInto(out *PoolerSpec) {
 		*out = new(ServiceTemplateSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ReplicaLoadBalancing != nil {
+		in, out := &in.ReplicaLoadBalancing, &out.ReplicaLoadBalancing
+		*out = new(PoolerReplicaLoadBalancing)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(PoolerAutoscalingConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerSpec.
@@ -2795,6 +3667,16 @@ func (in *PoolerStatus) DeepCopyInto(out *PoolerStatus) {
 		*out = new(PoolerSecrets)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ReplicaLoadBalancing != nil {
+		in, out := &in.ReplicaLoadBalancing, &out.ReplicaLoadBalancing
+		*out = new(PoolerReplicaLoadBalancingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(PoolerAutoscalingStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerStatus.
@@ -3038,6 +3920,16 @@ func (in *PublicationSpec) DeepCopyInto(out *PublicationSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.Publish != nil {
+		in, out := &in.Publish, &out.Publish
+		*out = make([]PublicationOperation, len(*in))
+		copy(*out, *in)
+	}
+	if in.PublishViaPartitionRoot != nil {
+		in, out := &in.PublishViaPartitionRoot, &out.PublishViaPartitionRoot
+		*out = new(bool)
+		**out = **in
+	}
 	in.Target.DeepCopyInto(&out.Target)
 }
 
@@ -3133,6 +4025,21 @@ func (in *PublicationTargetTable) DeepCopy() *PublicationTargetTable {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryStatisticsConfiguration) DeepCopyInto(out *QueryStatisticsConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryStatisticsConfiguration.
+func (in *QueryStatisticsConfiguration) DeepCopy() *QueryStatisticsConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryStatisticsConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RecoveryTarget) DeepCopyInto(out *RecoveryTarget) {
 	*out = *in
@@ -3148,12 +4055,66 @@ func (in *RecoveryTarget) DeepCopyInto(out *RecoveryTarget) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecoveryTarget.
-func (in *RecoveryTarget) DeepCopy() *RecoveryTarget {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecoveryTarget.
+func (in *RecoveryTarget) DeepCopy() *RecoveryTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(RecoveryTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaAutoscalingConfiguration) DeepCopyInto(out *ReplicaAutoscalingConfiguration) {
+	*out = *in
+	if in.TargetCPUUtilization != nil {
+		in, out := &in.TargetCPUUtilization, &out.TargetCPUUtilization
+		*out = new(int)
+		**out = **in
+	}
+	if in.TargetActiveConnections != nil {
+		in, out := &in.TargetActiveConnections, &out.TargetActiveConnections
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxReplicationLag != nil {
+		in, out := &in.MaxReplicationLag, &out.MaxReplicationLag
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Cooldown != nil {
+		in, out := &in.Cooldown, &out.Cooldown
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaAutoscalingConfiguration.
+func (in *ReplicaAutoscalingConfiguration) DeepCopy() *ReplicaAutoscalingConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaAutoscalingConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaAutoscalingStatus) DeepCopyInto(out *ReplicaAutoscalingStatus) {
+	*out = *in
+	if in.LastScaleTime != nil {
+		in, out := &in.LastScaleTime, &out.LastScaleTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaAutoscalingStatus.
+func (in *ReplicaAutoscalingStatus) DeepCopy() *ReplicaAutoscalingStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RecoveryTarget)
+	out := new(ReplicaAutoscalingStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -3375,6 +4336,16 @@ func (in *ScheduledBackupSpec) DeepCopyInto(out *ScheduledBackupSpec) {
 		*out = new(OnlineConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(BackupVerificationConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupSpec.
@@ -3495,6 +4466,13 @@ func (in *ServerSpec) DeepCopyInto(out *ServerSpec) {
 		*out = make([]UsageSpec, len(*in))
 		copy(*out, *in)
 	}
+	if in.UserMappings != nil {
+		in, out := &in.UserMappings, &out.UserMappings
+		*out = make([]UserMappingSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerSpec.
@@ -3540,6 +4518,46 @@ func (in *ServiceTemplateSpec) DeepCopy() *ServiceTemplateSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlowQueryLogConfiguration) DeepCopyInto(out *SlowQueryLogConfiguration) {
+	*out = *in
+	if in.MaxFileSize != nil {
+		in, out := &in.MaxFileSize, &out.MaxFileSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlowQueryLogConfiguration.
+func (in *SlowQueryLogConfiguration) DeepCopy() *SlowQueryLogConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(SlowQueryLogConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageAutoscaleConfiguration) DeepCopyInto(out *StorageAutoscaleConfiguration) {
+	*out = *in
+	if in.Cooldown != nil {
+		in, out := &in.Cooldown, &out.Cooldown
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageAutoscaleConfiguration.
+func (in *StorageAutoscaleConfiguration) DeepCopy() *StorageAutoscaleConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageAutoscaleConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageConfiguration) DeepCopyInto(out *StorageConfiguration) {
 	*out = *in
@@ -3558,6 +4576,11 @@ func (in *StorageConfiguration) DeepCopyInto(out *StorageConfiguration) {
 		*out = new(corev1.PersistentVolumeClaimSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Autoscale != nil {
+		in, out := &in.Autoscale, &out.Autoscale
+		*out = new(StorageAutoscaleConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageConfiguration.
@@ -3640,6 +4663,16 @@ func (in *SubscriptionSpec) DeepCopyInto(out *SubscriptionSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.DisableOnError != nil {
+		in, out := &in.DisableOnError, &out.DisableOnError
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Failover != nil {
+		in, out := &in.Failover, &out.Failover
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionSpec.
@@ -3652,6 +4685,31 @@ func (in *SubscriptionSpec) DeepCopy() *SubscriptionSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionStatistics) DeepCopyInto(out *SubscriptionStatistics) {
+	*out = *in
+	in.UpdateTime.DeepCopyInto(&out.UpdateTime)
+	if in.LatestEndTime != nil {
+		in, out := &in.LatestEndTime, &out.LatestEndTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make([]SubscriptionTableStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionStatistics.
+func (in *SubscriptionStatistics) DeepCopy() *SubscriptionStatistics {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionStatistics)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubscriptionStatus) DeepCopyInto(out *SubscriptionStatus) {
 	*out = *in
@@ -3660,6 +4718,15 @@ func (in *SubscriptionStatus) DeepCopyInto(out *SubscriptionStatus) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Statistics != nil {
+		in, out := &in.Statistics, &out.Statistics
+		*out = new(SubscriptionStatistics)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SequencesSyncedAt != nil {
+		in, out := &in.SequencesSyncedAt, &out.SequencesSyncedAt
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionStatus.
@@ -3672,6 +4739,21 @@ func (in *SubscriptionStatus) DeepCopy() *SubscriptionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionTableStatus) DeepCopyInto(out *SubscriptionTableStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionTableStatus.
+func (in *SubscriptionTableStatus) DeepCopy() *SubscriptionTableStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionTableStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SwitchReplicaClusterStatus) DeepCopyInto(out *SwitchReplicaClusterStatus) {
 	*out = *in
@@ -3687,6 +4769,167 @@ func (in *SwitchReplicaClusterStatus) DeepCopy() *SwitchReplicaClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Switchover) DeepCopyInto(out *Switchover) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Switchover.
+func (in *Switchover) DeepCopy() *Switchover {
+	if in == nil {
+		return nil
+	}
+	out := new(Switchover)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Switchover) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwitchoverDrainConfiguration) DeepCopyInto(out *SwitchoverDrainConfiguration) {
+	*out = *in
+	if in.PausePoolers != nil {
+		in, out := &in.PausePoolers, &out.PausePoolers
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchoverDrainConfiguration.
+func (in *SwitchoverDrainConfiguration) DeepCopy() *SwitchoverDrainConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(SwitchoverDrainConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwitchoverDrainStatus) DeepCopyInto(out *SwitchoverDrainStatus) {
+	*out = *in
+	in.StartedAt.DeepCopyInto(&out.StartedAt)
+	if in.PausedPoolers != nil {
+		in, out := &in.PausedPoolers, &out.PausedPoolers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SwitchoverRequestedAt != nil {
+		in, out := &in.SwitchoverRequestedAt, &out.SwitchoverRequestedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchoverDrainStatus.
+func (in *SwitchoverDrainStatus) DeepCopy() *SwitchoverDrainStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SwitchoverDrainStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwitchoverList) DeepCopyInto(out *SwitchoverList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Switchover, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchoverList.
+func (in *SwitchoverList) DeepCopy() *SwitchoverList {
+	if in == nil {
+		return nil
+	}
+	out := new(SwitchoverList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SwitchoverList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwitchoverSpec) DeepCopyInto(out *SwitchoverSpec) {
+	*out = *in
+	out.Cluster = in.Cluster
+	if in.NotBefore != nil {
+		in, out := &in.NotBefore, &out.NotBefore
+		*out = (*in).DeepCopy()
+	}
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
+	if in.MaxLagBytes != nil {
+		in, out := &in.MaxLagBytes, &out.MaxLagBytes
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchoverSpec.
+func (in *SwitchoverSpec) DeepCopy() *SwitchoverSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SwitchoverSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwitchoverStatus) DeepCopyInto(out *SwitchoverStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.StoppedAt != nil {
+		in, out := &in.StoppedAt, &out.StoppedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchoverStatus.
+func (in *SwitchoverStatus) DeepCopy() *SwitchoverStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SwitchoverStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SyncReplicaElectionConstraints) DeepCopyInto(out *SyncReplicaElectionConstraints) {
 	*out = *in
@@ -3852,6 +5095,48 @@ func (in *UsageSpec) DeepCopy() *UsageSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserMappingOptionSpec) DeepCopyInto(out *UserMappingOptionSpec) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserMappingOptionSpec.
+func (in *UserMappingOptionSpec) DeepCopy() *UserMappingOptionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UserMappingOptionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserMappingSpec) DeepCopyInto(out *UserMappingSpec) {
+	*out = *in
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make([]UserMappingOptionSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserMappingSpec.
+func (in *UserMappingSpec) DeepCopy() *UserMappingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UserMappingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeSnapshotConfiguration) DeepCopyInto(out *VolumeSnapshotConfiguration) {
 	*out = *in
@@ -3882,6 +5167,11 @@ func (in *VolumeSnapshotConfiguration) DeepCopyInto(out *VolumeSnapshotConfigura
 		**out = **in
 	}
 	in.OnlineConfiguration.DeepCopyInto(&out.OnlineConfiguration)
+	if in.RetentionPolicy != nil {
+		in, out := &in.RetentionPolicy, &out.RetentionPolicy
+		*out = new(VolumeSnapshotRetentionPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeSnapshotConfiguration.
@@ -3893,3 +5183,38 @@ func (in *VolumeSnapshotConfiguration) DeepCopy() *VolumeSnapshotConfiguration {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeSnapshotRetentionPolicy) DeepCopyInto(out *VolumeSnapshotRetentionPolicy) {
+	*out = *in
+	if in.KeepLast != nil {
+		in, out := &in.KeepLast, &out.KeepLast
+		*out = new(int)
+		**out = **in
+	}
+	if in.KeepDaily != nil {
+		in, out := &in.KeepDaily, &out.KeepDaily
+		*out = new(int)
+		**out = **in
+	}
+	if in.KeepWeekly != nil {
+		in, out := &in.KeepWeekly, &out.KeepWeekly
+		*out = new(int)
+		**out = **in
+	}
+	if in.KeepMonthly != nil {
+		in, out := &in.KeepMonthly, &out.KeepMonthly
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeSnapshotRetentionPolicy.
+func (in *VolumeSnapshotRetentionPolicy) DeepCopy() *VolumeSnapshotRetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeSnapshotRetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -45,6 +45,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/pgbench"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/promote"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/psql"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/recovery"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/reload"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/report"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/restart"
@@ -127,6 +128,7 @@ func main() {
 		promote.NewCmd(),
 		psql.NewCmd(),
 		publication.NewCmd(),
+		recovery.NewCmd(),
 		reload.NewCmd(),
 		report.NewCmd(),
 		restart.NewCmd(),
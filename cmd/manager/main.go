@@ -33,7 +33,9 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/controller"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/debug"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/logicalbackup"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/pgbouncer"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/pgcat"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/show"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/walarchive"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/walrestore"
@@ -67,11 +69,13 @@ func newRootCmd() *cobra.Command {
 	cmd.AddCommand(bootstrap.NewCmd())
 	cmd.AddCommand(controller.NewCmd())
 	cmd.AddCommand(instance.NewCmd())
+	cmd.AddCommand(logicalbackup.NewCmd())
 	cmd.AddCommand(show.NewCmd())
 	cmd.AddCommand(walarchive.NewCmd())
 	cmd.AddCommand(walrestore.NewCmd())
 	cmd.AddCommand(versions.NewCmd())
 	cmd.AddCommand(pgbouncer.NewCmd())
+	cmd.AddCommand(pgcat.NewCmd())
 	cmd.AddCommand(debug.NewCmd())
 
 	return cmd
@@ -38,6 +38,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
@@ -335,6 +336,11 @@ func setupReconcilers(
 		return err
 	}
 
+	if err := metrics.Registry.Register(controller.NewBackupVerificationCollector(mgr.GetClient())); err != nil {
+		setupLog.Error(err, "unable to register the backup verification metrics")
+		return err
+	}
+
 	if err := controller.NewPluginReconciler(mgr, conf.OperatorNamespace, pluginRepository).
 		SetupWithManager(mgr, maxConcurrentReconciles); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Plugin")
@@ -370,6 +376,12 @@ func setupReconcilers(
 		return err
 	}
 
+	if err := controller.NewMajorMigrationReconciler(mgr, operatorClientCert).
+		SetupWithManager(mgr, maxConcurrentReconciles); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MajorMigration")
+		return err
+	}
+
 	return nil
 }
 
@@ -400,6 +412,11 @@ func setupWebhooks(mgr ctrl.Manager) error {
 		return err
 	}
 
+	if err := webhookv1.SetupPublicationWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "Publication", "version", "v1")
+		return err
+	}
+
 	// Setup the handler used by the readiness and liveliness probe.
 	//
 	// Unfortunately the readiness of the probe is not sufficient for the operator to be
@@ -34,6 +34,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/run"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/status"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/upgrade"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/verifybackup"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
@@ -58,6 +59,7 @@ func NewCmd() *cobra.Command {
 	cmd.AddCommand(restore.NewCmd())
 	cmd.AddCommand(restoresnapshot.NewCmd())
 	cmd.AddCommand(upgrade.NewCmd())
+	cmd.AddCommand(verifybackup.NewCmd())
 
 	return cmd
 }
@@ -45,10 +45,11 @@ import (
 // NewCmd creates the "restore" subcommand
 func NewCmd() *cobra.Command {
 	var (
-		clusterName string
-		namespace   string
-		pgData      string
-		pgWal       string
+		clusterName    string
+		namespace      string
+		pgData         string
+		pgWal          string
+		backupToVerify string
 	)
 
 	cmd := &cobra.Command{
@@ -84,12 +85,13 @@ func NewCmd() *cobra.Command {
 
 			// Step 2: add the restore process to the manager
 			restoreProcess := restoreRunnable{
-				cli:         mgr.GetClient(),
-				clusterName: clusterName,
-				namespace:   namespace,
-				pgData:      pgData,
-				pgWal:       pgWal,
-				cancel:      cancel,
+				cli:            mgr.GetClient(),
+				clusterName:    clusterName,
+				namespace:      namespace,
+				pgData:         pgData,
+				pgWal:          pgWal,
+				backupToVerify: backupToVerify,
+				cancel:         cancel,
 			}
 			if mgr.Add(&restoreProcess) != nil {
 				contextLogger.Error(err, "while building the restore process")
@@ -125,6 +127,8 @@ func NewCmd() *cobra.Command {
 		"the cluster and the Pod in k8s")
 	cmd.Flags().StringVar(&pgData, "pg-data", os.Getenv("PGDATA"), "The PGDATA to be restored")
 	cmd.Flags().StringVar(&pgWal, "pg-wal", "", "The PGWAL to be restored")
+	cmd.Flags().StringVar(&backupToVerify, "verify-backup", "", "The name of the backup to be restored, "+
+		"instead of the recovery source of the cluster, in a throwaway instance that is used to verify it")
 
 	return cmd
 }
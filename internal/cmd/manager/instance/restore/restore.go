@@ -35,12 +35,13 @@ import (
 )
 
 type restoreRunnable struct {
-	cli         client.Client
-	clusterName string
-	namespace   string
-	pgData      string
-	pgWal       string
-	cancel      context.CancelFunc
+	cli            client.Client
+	clusterName    string
+	namespace      string
+	pgData         string
+	pgWal          string
+	backupToVerify string
+	cancel         context.CancelFunc
 }
 
 func (r *restoreRunnable) Start(ctx context.Context) error {
@@ -53,10 +54,11 @@ func (r *restoreRunnable) Start(ctx context.Context) error {
 	}
 
 	info := postgres.InitInfo{
-		ClusterName: r.clusterName,
-		Namespace:   r.namespace,
-		PgData:      r.pgData,
-		PgWal:       r.pgWal,
+		ClusterName:    r.clusterName,
+		Namespace:      r.namespace,
+		PgData:         r.pgData,
+		PgWal:          r.pgWal,
+		BackupToVerify: r.backupToVerify,
 	}
 
 	if err := restoreSubCommand(ctx, info, r.cli); err != nil {
@@ -46,13 +46,14 @@ import (
 // NewCmd creates the "restoresnapshot" subcommand
 func NewCmd() *cobra.Command {
 	var (
-		clusterName   string
-		namespace     string
-		pgData        string
-		pgWal         string
-		backupLabel   string
-		tablespaceMap string
-		immediate     bool
+		clusterName    string
+		namespace      string
+		pgData         string
+		pgWal          string
+		backupToVerify string
+		backupLabel    string
+		tablespaceMap  string
+		immediate      bool
 	)
 
 	cmd := &cobra.Command{
@@ -88,13 +89,14 @@ func NewCmd() *cobra.Command {
 
 			// Step 2: add the restore process to the manager
 			restoreProcess := restoreRunnable{
-				cli:         mgr.GetClient(),
-				clusterName: clusterName,
-				namespace:   namespace,
-				pgData:      pgData,
-				pgWal:       pgWal,
-				immediate:   immediate,
-				cancel:      cancel,
+				cli:            mgr.GetClient(),
+				clusterName:    clusterName,
+				namespace:      namespace,
+				pgData:         pgData,
+				pgWal:          pgWal,
+				backupToVerify: backupToVerify,
+				immediate:      immediate,
+				cancel:         cancel,
 			}
 			if mgr.Add(&restoreProcess) != nil {
 				contextLogger.Error(err, "while building the restore process")
@@ -146,6 +148,8 @@ func NewCmd() *cobra.Command {
 		"the cluster")
 	cmd.Flags().StringVar(&pgData, "pg-data", os.Getenv("PGDATA"), "The PGDATA to be restored")
 	cmd.Flags().StringVar(&pgWal, "pg-wal", "", "The PGWAL to be restored")
+	cmd.Flags().StringVar(&backupToVerify, "verify-backup", "", "The name of the backup to be restored, "+
+		"instead of the recovery source of the cluster, in a throwaway instance that is used to verify it")
 	cmd.Flags().StringVar(&backupLabel, "backuplabel", "", "The restore backup_label file content")
 	cmd.Flags().StringVar(&tablespaceMap, "tablespacemap", "", "The restore tablespace_map file content")
 	cmd.Flags().BoolVar(&immediate, "immediate", false, "Do not start PostgreSQL but just recover the snapshot")
@@ -36,6 +36,7 @@ type restoreRunnable struct {
 	namespace         string
 	pgData            string
 	pgWal             string
+	backupToVerify    string
 	backupLabelFile   []byte
 	tablespaceMapFile []byte
 	immediate         bool
@@ -58,6 +59,7 @@ func (r *restoreRunnable) Start(ctx context.Context) error {
 		Namespace:         r.namespace,
 		PgData:            r.pgData,
 		PgWal:             r.pgWal,
+		BackupToVerify:    r.backupToVerify,
 		BackupLabelFile:   r.backupLabelFile,
 		TablespaceMapFile: r.tablespaceMapFile,
 	}
@@ -57,6 +57,7 @@ import (
 	webhookv1 "github.com/cloudnative-pg/cloudnative-pg/internal/webhook/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/concurrency"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/otlp"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/logpipe"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/metrics"
@@ -255,10 +256,36 @@ func runSubCommand( //nolint: gocyclo,gocognit
 	leaseRunnable := lease.New(kubeClientset, instance)
 
 	metricsExporter := metricserver.NewExporter(instance, metrics.NewPluginCollector(pluginRepository))
+
+	// OTLP push pipeline, configured by the instance reconciler
+	otlpPipeline, err := otlp.NewPipeline(
+		otlp.ResourceInfo{
+			Component:   "instance",
+			Namespace:   instance.GetNamespaceName(),
+			ClusterName: instance.GetClusterName(),
+			PodName:     instance.GetPodName(),
+		},
+		metricsExporter,
+	)
+	if err != nil {
+		contextLogger.Error(err, "unable to create OTLP pipeline")
+		return err
+	}
+	if err := mgr.Add(otlpPipeline); err != nil {
+		contextLogger.Error(err, "unable to add OTLP pipeline runnable")
+		return err
+	}
+
+	// routing of the statement duration and auto_explain records,
+	// configured by the instance reconciler
+	slowQueryRouter := logpipe.NewSlowQueryRouter()
+
 	reconciler := controller.NewInstanceReconciler(
 		instance,
 		mgr.GetClient(),
 		metricsExporter,
+		otlpPipeline,
+		slowQueryRouter,
 		pluginRepository,
 		leaseRunnable,
 		webhookv1.NewClusterAdmissionGuard(),
@@ -306,7 +333,10 @@ func runSubCommand( //nolint: gocyclo,gocognit
 	}
 
 	// postgres CSV logs handler (PGAudit too)
-	postgresLogPipe := logpipe.NewLogPipe()
+	postgresLogPipe := logpipe.NewLogPipe().
+		WithSlowQueryRouter(slowQueryRouter).
+		WithRecordWriter(metricsExporter.StatementDurationObserver()).
+		WithRecordWriter(otlpPipeline)
 	if err := mgr.Add(postgresLogPipe); err != nil {
 		contextLogger.Error(err, "unable to add CSV logs handler")
 		return err
@@ -0,0 +1,118 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifybackup
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/istio"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/linkerd"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// NewCmd creates the "verifybackup" subcommand
+func NewCmd() *cobra.Command {
+	var (
+		clusterName string
+		namespace   string
+		pgData      string
+	)
+
+	cmd := &cobra.Command{
+		Use:           "verifybackup [backup_name]",
+		Args:          cobra.ExactArgs(1),
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			cli, err := management.NewControllerRuntimeClient()
+			if err != nil {
+				return err
+			}
+
+			info := postgres.InitInfo{
+				ClusterName:    clusterName,
+				Namespace:      namespace,
+				PgData:         pgData,
+				BackupToVerify: args[0],
+			}
+
+			return verifyBackup(ctx, cli, info)
+		},
+		PostRunE: func(cmd *cobra.Command, _ []string) error {
+			if err := istio.TryInvokeQuitEndpoint(cmd.Context()); err != nil {
+				return err
+			}
+
+			return linkerd.TryInvokeShutdownEndpoint(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster-name", os.Getenv("CLUSTER_NAME"), "The name of the "+
+		"cluster the backup belongs to")
+	cmd.Flags().StringVar(&namespace, "namespace", os.Getenv("NAMESPACE"), "The namespace of "+
+		"the cluster and of the backup")
+	cmd.Flags().StringVar(&pgData, "pg-data", os.Getenv("PGDATA"), "The PGDATA restored from the backup")
+
+	return cmd
+}
+
+// verifyBackup runs the checks in the instance restored from the backup,
+// recording their result in the backup status
+func verifyBackup(ctx context.Context, cli client.Client, info postgres.InitInfo) error {
+	contextLogger := log.FromContext(ctx).WithValues("backupName", info.BackupToVerify)
+	ctx = log.IntoContext(ctx, contextLogger)
+
+	var backup apiv1.Backup
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: info.Namespace, Name: info.BackupToVerify}, &backup); err != nil {
+		return fmt.Errorf("while getting backup: %w", err)
+	}
+
+	var cluster apiv1.Cluster
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: info.Namespace, Name: info.ClusterName}, &cluster); err != nil {
+		return fmt.Errorf("while getting cluster: %w", err)
+	}
+
+	var assertions []apiv1.BackupVerificationAssertion
+	if backup.Spec.Verification != nil {
+		assertions = backup.Spec.Verification.Assertions
+	}
+
+	checks, err := info.VerifyRestoredBackup(ctx, cluster.GetBackupVerificationCluster(&backup), assertions)
+	if err != nil {
+		contextLogger.Error(err, "Backup verification failed")
+	}
+
+	backup.Status.SetVerificationResult(checks, err)
+	if err := postgres.PatchBackupStatusAndRetry(ctx, cli, &backup); err != nil {
+		return fmt.Errorf("while recording the backup verification result: %w", err)
+	}
+	contextLogger.Info("Backup verification completed", "phase", backup.Status.Verification.Phase)
+
+	return nil
+}
@@ -0,0 +1,158 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package logicalbackup implement the "manager logicalbackup" command,
+// executed by the jobs taking backups with the logical method
+package logicalbackup
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/istio"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/linkerd"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources/status"
+)
+
+// NewCmd creates the "logicalbackup" command
+func NewCmd() *cobra.Command {
+	var namespace string
+
+	cmd := &cobra.Command{
+		Use:  "logicalbackup [backup_name]",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			cli, err := management.NewControllerRuntimeClient()
+			if err != nil {
+				return err
+			}
+
+			return takeLogicalBackup(ctx, cli, client.ObjectKey{Namespace: namespace, Name: args[0]})
+		},
+		PostRunE: func(cmd *cobra.Command, _ []string) error {
+			if err := istio.TryInvokeQuitEndpoint(cmd.Context()); err != nil {
+				return err
+			}
+
+			return linkerd.TryInvokeShutdownEndpoint(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVar(&namespace, "namespace", os.Getenv("NAMESPACE"), "The namespace of "+
+		"the backup and of the cluster")
+
+	return cmd
+}
+
+// takeLogicalBackup exports the databases selected by the backup and
+// records the outcome in its status
+func takeLogicalBackup(ctx context.Context, cli client.Client, backupKey client.ObjectKey) error {
+	contextLogger := log.FromContext(ctx).WithValues("backupName", backupKey.Name)
+	ctx = log.IntoContext(ctx, contextLogger)
+
+	var backup apiv1.Backup
+	if err := cli.Get(ctx, backupKey, &backup); err != nil {
+		return fmt.Errorf("while getting backup: %w", err)
+	}
+
+	var cluster apiv1.Cluster
+	if err := cli.Get(
+		ctx,
+		client.ObjectKey{Namespace: backup.Namespace, Name: backup.Spec.Cluster.Name},
+		&cluster,
+	); err != nil {
+		return fmt.Errorf("while getting cluster: %w", err)
+	}
+
+	if err := exportDatabases(ctx, &backup); err != nil {
+		contextLogger.Error(err, "Backup failed")
+		_ = status.FlagBackupAsFailed(ctx, cli, &backup, &cluster, err)
+		return err
+	}
+
+	backup.Status.SetAsCompleted()
+	backup.Status.StoppedAt = ptr.To(metav1.Now())
+	if err := postgres.PatchBackupStatusAndRetry(ctx, cli, &backup); err != nil {
+		return fmt.Errorf("while setting backup status as completed: %w", err)
+	}
+	contextLogger.Info("Backup completed")
+
+	if err := status.PatchConditionsWithOptimisticLock(
+		ctx,
+		cli,
+		&cluster,
+		apiv1.BackupSucceededCondition,
+	); err != nil {
+		contextLogger.Error(err, "Can't update the cluster with the completed backup data")
+	}
+
+	return nil
+}
+
+// exportDatabases dumps the databases selected by the backup into the
+// backup directory, collecting their size and checksum
+func exportDatabases(ctx context.Context, backup *apiv1.Backup) error {
+	if backup.Status.LogicalBackupStatus == nil {
+		return fmt.Errorf("the backup has no logical backup status")
+	}
+
+	databases := backup.Spec.Databases
+	if len(databases) == 0 {
+		db, err := pool.NewDBConnection("dbname=postgres", pool.ConnectionProfilePostgresql)
+		if err != nil {
+			return fmt.Errorf("while connecting to the primary: %w", err)
+		}
+		defer func() {
+			_ = db.Close()
+		}()
+
+		if databases, err = postgres.ListDatabasesToDump(ctx, db); err != nil {
+			return fmt.Errorf("while listing the databases: %w", err)
+		}
+	}
+
+	backupDirectory := postgres.GetLogicalBackupDirectory(backup.Status.LogicalBackupStatus)
+	if err := os.MkdirAll(backupDirectory, 0o700); err != nil {
+		return fmt.Errorf("while creating the backup directory: %w", err)
+	}
+
+	backup.Status.LogicalBackupStatus.Databases = make([]apiv1.LogicalBackupDatabaseStatus, 0, len(databases))
+	for _, database := range databases {
+		dump, err := postgres.DumpDatabase(ctx, database, backupDirectory)
+		if err != nil {
+			return err
+		}
+		backup.Status.LogicalBackupStatus.Databases = append(backup.Status.LogicalBackupStatus.Databases, *dump)
+	}
+
+	return nil
+}
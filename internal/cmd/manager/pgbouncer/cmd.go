@@ -26,7 +26,8 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/pgbouncer/run"
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/pooler/run"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
@@ -44,7 +45,7 @@ func NewCmd() *cobra.Command {
 		},
 	}
 
-	cmd.AddCommand(run.NewCmd())
+	cmd.AddCommand(run.NewCmd(apiv1.PoolerBackendPgBouncer))
 
 	return cmd
 }
@@ -0,0 +1,51 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package pgcat implements the "pgcat" subcommand of the operator
+package pgcat
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/pooler/run"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// NewCmd creates the "pgcat" command
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "pgcat",
+		Short:         "pgcat management subfeatures",
+		SilenceErrors: true,
+		PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
+			return os.MkdirAll(postgres.TemporaryDirectory, 0o1777) //nolint:gosec
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return fmt.Errorf("missing subcommand")
+		},
+	}
+
+	cmd.AddCommand(run.NewCmd(apiv1.PoolerBackendPgCat))
+
+	return cmd
+}
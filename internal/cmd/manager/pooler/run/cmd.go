@@ -17,7 +17,8 @@ limitations under the License.
 SPDX-License-Identifier: Apache-2.0
 */
 
-// Package run implements the "pgbouncer run" subcommand of the operator
+// Package run implements the "run" subcommand of the connection poolers
+// supported by the operator
 package run
 
 import (
@@ -25,10 +26,10 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"os/signal"
-	"path/filepath"
 	"strconv"
 	"syscall"
 
@@ -37,14 +38,14 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/types"
 
-	"github.com/cloudnative-pg/cloudnative-pg/internal/pgbouncer/management/controller"
-	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/config"
-	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/metricsserver"
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/pooler/management/controller"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pooler/metricsserver"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 )
 
-// NewCmd creates the "instance run" subcommand
-func NewCmd() *cobra.Command {
+// NewCmd creates the "run" subcommand of the passed pooler backend
+func NewCmd(backend apiv1.PoolerBackend) *cobra.Command {
 	var (
 		poolerNamespacedName types.NamespacedName
 		metricsTLS           bool
@@ -74,11 +75,12 @@ func NewCmd() *cobra.Command {
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			ctx := log.IntoContext(
 				cmd.Context(),
-				log.GetLogger().WithValues("logger", "pgbouncer-manager"),
+				log.GetLogger().WithValues("logger", fmt.Sprintf("%s-manager", backend)),
 			)
 			contextLogger := log.FromContext(ctx)
 
 			opts := runOptions{
+				backend:              backend,
 				poolerNamespacedName: poolerNamespacedName,
 				metricsPortTLS:       metricsTLS,
 			}
@@ -94,7 +96,8 @@ func NewCmd() *cobra.Command {
 		&poolerNamespacedName.Name,
 		"pooler-name",
 		os.Getenv(poolerNameEnvVar),
-		"The name of the Pooler in k8s, used to generate configuration and refresh pgbouncer when needed. "+
+		fmt.Sprintf("The name of the Pooler in k8s, used to generate configuration and refresh %s when needed. ",
+			backend)+
 			"Defaults to the value of the POOLER_NAME environment variable")
 	cmd.Flags().StringVar(
 		&poolerNamespacedName.Namespace,
@@ -113,19 +116,23 @@ func NewCmd() *cobra.Command {
 }
 
 func runSubCommand(ctx context.Context, opts runOptions) error {
-	var err error
-
 	contextLogger := log.FromContext(ctx)
-	contextLogger.Info("Starting CloudNativePG PgBouncer Instance Manager",
+	contextLogger.Info("Starting CloudNativePG Pooler Instance Manager",
+		"backend", opts.backend,
 		"version", versions.Version,
 		"build", versions.Info,
 		"metricsPortTLS", opts.metricsPortTLS)
 
-	if err = startWebServer(ctx, opts.metricsTLSConfig()); err != nil {
+	instance, err := controller.NewPoolerInstance(opts.backend)
+	if err != nil {
+		return fmt.Errorf("while initializing the %s instance: %w", opts.backend, err)
+	}
+
+	if err = startWebServer(ctx, instance, opts.metricsTLSConfig()); err != nil {
 		return fmt.Errorf("while starting the web server: %w", err)
 	}
 
-	reconciler, err := controller.NewPgBouncerReconciler(opts.poolerNamespacedName)
+	reconciler, err := controller.NewPoolerReconciler(opts.poolerNamespacedName, instance)
 	if err != nil {
 		return fmt.Errorf("while initializing the new reconciler: %w", err)
 	}
@@ -135,31 +142,28 @@ func runSubCommand(ctx context.Context, opts runOptions) error {
 		return fmt.Errorf("while initializing reconciler: %w", err)
 	}
 
-	// Start PgBouncer with the generated configuration
-	const pgBouncerCommandName = "/usr/bin/pgbouncer"
-	pgBouncerIni := filepath.Join(config.ConfigsDir, config.PgBouncerIniFileName)
-	pgBouncerCmd := exec.Command(pgBouncerCommandName, pgBouncerIni) //nolint:gosec
+	// Start the pooler with the generated configuration
+	poolerCmd := instance.Command()
+	commandName := poolerCmd.Path
 	stdoutWriter := &execlog.LogWriter{
 		Logger: contextLogger.WithValues(execlog.PipeKey, execlog.StdOut),
 	}
-	stderrWriter := &pgBouncerLogWriter{
-		Logger: contextLogger.WithValues(execlog.PipeKey, execlog.StdErr),
-	}
+	stderrWriter := newStderrWriter(opts.backend, contextLogger.WithValues(execlog.PipeKey, execlog.StdErr))
 	streamingCmd, err := execlog.RunStreamingNoWaitWithWriter(
-		pgBouncerCmd, pgBouncerCommandName, stdoutWriter, stderrWriter)
+		poolerCmd, commandName, stdoutWriter, stderrWriter)
 	if err != nil {
-		return fmt.Errorf("running pgbouncer: %w", err)
+		return fmt.Errorf("running %s: %w", opts.backend, err)
 	}
 
 	startReconciler(ctx, reconciler)
-	registerSignalHandler(ctx, reconciler, pgBouncerCmd)
+	registerSignalHandler(ctx, opts.backend, reconciler, poolerCmd)
 
 	if err = streamingCmd.Wait(); err != nil {
 		var exitError *exec.ExitError
 		if !errors.As(err, &exitError) {
-			contextLogger.Error(err, "Error waiting on pgbouncer process")
+			contextLogger.Error(err, "Error waiting on pooler process", "backend", opts.backend)
 		} else {
-			contextLogger.Error(exitError, "pgbouncer process exited with errors")
+			contextLogger.Error(exitError, "pooler process exited with errors", "backend", opts.backend)
 		}
 		return err
 	}
@@ -167,9 +171,25 @@ func runSubCommand(ctx context.Context, opts runOptions) error {
 	return nil
 }
 
-// registerSignalHandler handles signals from k8s, notifying postgres as
-// needed
-func registerSignalHandler(ctx context.Context, reconciler *controller.PgBouncerReconciler, command *exec.Cmd) {
+// newStderrWriter creates the writer logging what the pooler writes
+// on its standard error stream, parsing the log lines when the
+// format is known
+func newStderrWriter(backend apiv1.PoolerBackend, logger log.Logger) io.Writer {
+	if backend == apiv1.PoolerBackendPgBouncer {
+		return &pgBouncerLogWriter{Logger: logger}
+	}
+
+	return &execlog.LogWriter{Logger: logger}
+}
+
+// registerSignalHandler handles signals from k8s, asking the
+// pooler to shut down gracefully
+func registerSignalHandler(
+	ctx context.Context,
+	backend apiv1.PoolerBackend,
+	reconciler *controller.PoolerReconciler,
+	command *exec.Cmd,
+) {
 	contextLogger := log.FromContext(ctx)
 	signals := make(chan os.Signal, 1)
 	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
@@ -189,20 +209,24 @@ func registerSignalHandler(ctx context.Context, reconciler *controller.PgBouncer
 		reconciler.Stop()
 
 		if command != nil {
-			contextLogger.Info("Shutting down pgbouncer instance")
+			contextLogger.Info("Shutting down pooler instance", "backend", backend)
 			err := command.Process.Signal(syscall.SIGINT)
 			if err != nil {
-				contextLogger.Error(err, "Unable to send SIGINT to pgbouncer instance")
+				contextLogger.Error(err, "Unable to send SIGINT to pooler instance", "backend", backend)
 			}
 		}
 	}()
 }
 
-// startWebServer starts the web server for exposing metrics given
-// a certain PgBouncer instance
-func startWebServer(ctx context.Context, tlsConfig *tls.Config) error {
+// startWebServer starts the web server for exposing the metrics
+// of a certain pooler instance
+func startWebServer(
+	ctx context.Context,
+	instance controller.PoolerInstanceInterface,
+	tlsConfig *tls.Config,
+) error {
 	contextLogger := log.FromContext(ctx)
-	if err := metricsserver.Setup(ctx); err != nil {
+	if err := metricsserver.Setup(instance.NewMetricsCollector(ctx)); err != nil {
 		return err
 	}
 
@@ -217,8 +241,9 @@ func startWebServer(ctx context.Context, tlsConfig *tls.Config) error {
 }
 
 // startReconciler start the reconciliation loop
-func startReconciler(ctx context.Context, reconciler *controller.PgBouncerReconciler) {
+func startReconciler(ctx context.Context, reconciler *controller.PoolerReconciler) {
 	go reconciler.Run(ctx)
+	go reconciler.RunReplicaLoadBalancing(ctx)
 }
 
 // boolFromEnv reads a boolean value from the given environment variable.
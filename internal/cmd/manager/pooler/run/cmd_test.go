@@ -20,6 +20,11 @@ SPDX-License-Identifier: Apache-2.0
 package run
 
 import (
+	"github.com/cloudnative-pg/machinery/pkg/execlog"
+	"github.com/cloudnative-pg/machinery/pkg/log"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -57,3 +62,15 @@ var _ = Describe("boolFromEnv", func() {
 	// do not exercise that path here — it would terminate the ginkgo process.
 	// The behavior is covered by code review and by the boolFromEnv godoc.
 })
+
+var _ = Describe("newStderrWriter", func() {
+	It("parses the log lines of PgBouncer", func() {
+		Expect(newStderrWriter(apiv1.PoolerBackendPgBouncer, log.GetLogger())).
+			To(BeAssignableToTypeOf(&pgBouncerLogWriter{}))
+	})
+
+	It("streams the log lines of PgCat as they are", func() {
+		Expect(newStderrWriter(apiv1.PoolerBackendPgCat, log.GetLogger())).
+			To(BeAssignableToTypeOf(&execlog.LogWriter{}))
+	})
+})
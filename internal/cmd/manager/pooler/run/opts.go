@@ -25,10 +25,12 @@ import (
 
 	"k8s.io/apimachinery/pkg/types"
 
-	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/config"
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pooler/config"
 )
 
 type runOptions struct {
+	backend              apiv1.PoolerBackend
 	poolerNamespacedName types.NamespacedName
 	metricsPortTLS       bool
 }
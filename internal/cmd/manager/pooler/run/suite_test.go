@@ -28,5 +28,5 @@ import (
 
 func TestPgbouncer(t *testing.T) {
 	RegisterFailHandler(Fail)
-	RunSpecs(t, "pgbouncer instance manager tests")
+	RunSpecs(t, "pooler instance manager tests")
 }
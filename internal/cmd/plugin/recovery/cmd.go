@@ -0,0 +1,66 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package recovery
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+// NewCmd creates the new "recovery" command
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "recovery",
+		Short:   "Recovery related commands",
+		GroupID: plugin.GroupIDDatabase,
+	}
+	cmd.AddCommand(newPlanCmd())
+
+	return cmd
+}
+
+func newPlanCmd() *cobra.Command {
+	var fileName string
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "plan -f FILE",
+		Short: "Preview the base backup and the WAL range used to bootstrap a cluster from a recovery source",
+		Long: "Resolve the base backup that the cluster defined in FILE would be recovered from, " +
+			"using the backups and volume snapshots available in its namespace, and " +
+			"check that its recovery target can be reached. Use - to read the cluster from the standard input.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cluster, err := loadCluster(fileName, cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+
+			return Plan(cmd.Context(), cluster, plugin.OutputFormat(output), cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringVarP(&fileName, "file", "f", "", "The file containing the Cluster definition")
+	_ = cmd.MarkFlagRequired("file")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format. One of text|json|yaml")
+
+	return cmd
+}
@@ -0,0 +1,178 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package recovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cheynewallace/tabby"
+	"github.com/logrusorgru/aurora/v4"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/backup/recoveryplan"
+)
+
+// errRecoveryNotFeasible is returned when the recovery target cannot be reached
+var errRecoveryNotFeasible = errors.New("the recovery target cannot be reached")
+
+// loadCluster reads the Cluster definition from the passed file,
+// or from the passed reader when the file name is "-"
+func loadCluster(fileName string, stdin io.Reader) (*apiv1.Cluster, error) {
+	var content []byte
+	var err error
+	if fileName == "-" {
+		content, err = io.ReadAll(stdin)
+	} else {
+		content, err = os.ReadFile(fileName) // #nosec
+	}
+	if err != nil {
+		return nil, fmt.Errorf("while reading the cluster definition: %w", err)
+	}
+
+	var cluster apiv1.Cluster
+	if err := yaml.UnmarshalStrict(content, &cluster); err != nil {
+		return nil, fmt.Errorf("while decoding the cluster definition: %w", err)
+	}
+	if cluster.Kind != apiv1.ClusterKind {
+		return nil, fmt.Errorf("expected a %s definition, found %q", apiv1.ClusterKind, cluster.Kind)
+	}
+	if cluster.Namespace == "" {
+		cluster.Namespace = plugin.Namespace
+	}
+
+	return &cluster, nil
+}
+
+// Plan prints the recovery plan of the passed cluster, returning an
+// error when its recovery target cannot be reached
+func Plan(ctx context.Context, cluster *apiv1.Cluster, format plugin.OutputFormat, writer io.Writer) error {
+	plan, err := recoveryplan.Build(ctx, plugin.Client, cluster)
+	if err != nil {
+		return err
+	}
+
+	if format == plugin.OutputFormatText {
+		printPlan(plan, writer)
+	} else if err := plugin.Print(plan, format, writer); err != nil {
+		return err
+	}
+
+	if !plan.IsFeasible() {
+		return errRecoveryNotFeasible
+	}
+
+	return nil
+}
+
+func printPlan(plan *recoveryplan.Plan, writer io.Writer) {
+	_, _ = fmt.Fprintln(writer, aurora.Green("Recovery Plan"))
+	summary := tabby.NewCustom(newTabWriter(writer))
+	summary.AddLine("Source:", fmt.Sprintf("%s (%s)", plan.SourceName, plan.Source))
+	summary.AddLine("Recovery target:", formatRecoveryTarget(plan.RecoveryTarget))
+	if plan.FirstRecoverabilityPoint != nil {
+		summary.AddLine("First recoverability point:", formatTime(plan.FirstRecoverabilityPoint))
+	}
+	summary.Print()
+	_, _ = fmt.Fprintln(writer)
+
+	if baseBackup := plan.BaseBackup; baseBackup != nil {
+		_, _ = fmt.Fprintln(writer, aurora.Green("Base Backup"))
+		backupTable := tabby.NewCustom(newTabWriter(writer))
+		backupTable.AddLine("Name:", baseBackup.Name)
+		backupTable.AddLine("Backup ID:", baseBackup.BackupID)
+		backupTable.AddLine("Method:", baseBackup.Method)
+		backupTable.AddLine("Begin time:", formatTime(baseBackup.BeginTime))
+		backupTable.AddLine("End time:", formatTime(baseBackup.EndTime))
+		backupTable.AddLine("Begin LSN:", baseBackup.BeginLSN)
+		backupTable.AddLine("End LSN:", baseBackup.EndLSN)
+		backupTable.AddLine("Timeline:", baseBackup.Timeline)
+		backupTable.Print()
+		_, _ = fmt.Fprintln(writer)
+
+		lastRequiredWAL := plan.LastRequiredWAL
+		if lastRequiredWAL == "" {
+			lastRequiredWAL = "until the recovery target is reached"
+		}
+		_, _ = fmt.Fprintln(writer, aurora.Green("Required WAL Range"))
+		walTable := tabby.NewCustom(newTabWriter(writer))
+		walTable.AddLine("First WAL:", plan.FirstRequiredWAL)
+		walTable.AddLine("Last WAL:", lastRequiredWAL)
+		walTable.Print()
+		_, _ = fmt.Fprintln(writer)
+	}
+
+	for _, warning := range plan.Warnings {
+		_, _ = fmt.Fprintln(writer, aurora.Yellow("Warning: "+warning))
+	}
+	for _, problem := range plan.Problems {
+		_, _ = fmt.Fprintln(writer, aurora.Red("Error: "+problem))
+	}
+}
+
+// newTabWriter creates a tabwriter with the same settings used by tabby.New
+func newTabWriter(writer io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+}
+
+func formatRecoveryTarget(target *apiv1.RecoveryTarget) string {
+	if target == nil {
+		return "end of the available WAL"
+	}
+
+	var fields []string
+	for _, item := range []struct {
+		name  string
+		value string
+	}{
+		{name: "backupID", value: target.BackupID},
+		{name: "targetTime", value: target.TargetTime},
+		{name: "targetLSN", value: target.TargetLSN},
+		{name: "targetXID", value: target.TargetXID},
+		{name: "targetName", value: target.TargetName},
+		{name: "targetTLI", value: target.TargetTLI},
+	} {
+		if item.value != "" {
+			fields = append(fields, fmt.Sprintf("%s=%s", item.name, item.value))
+		}
+	}
+	if target.TargetImmediate != nil && *target.TargetImmediate {
+		fields = append(fields, "targetImmediate")
+	}
+
+	return strings.Join(fields, ", ")
+}
+
+func formatTime(value *metav1.Time) string {
+	if value == nil {
+		return ""
+	}
+
+	return value.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,73 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package recovery
+
+import (
+	"strings"
+
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("loadCluster", func() {
+	It("reads the cluster from the standard input", func() {
+		plugin.Namespace = "default"
+		cluster, err := loadCluster("-", strings.NewReader(`
+apiVersion: postgresql.cnpg.io/v1
+kind: Cluster
+metadata:
+  name: restored
+spec:
+  instances: 1
+  bootstrap:
+    recovery:
+      backup:
+        name: backup-1
+`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cluster.Name).To(Equal("restored"))
+		Expect(cluster.Namespace).To(Equal("default"))
+		Expect(cluster.Spec.Bootstrap.Recovery.Backup.Name).To(Equal("backup-1"))
+	})
+
+	It("refuses other kinds of objects", func() {
+		_, err := loadCluster("-", strings.NewReader("apiVersion: postgresql.cnpg.io/v1\nkind: Backup\n"))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("formatRecoveryTarget", func() {
+	It("describes the latest recovery when there is no target", func() {
+		Expect(formatRecoveryTarget(nil)).To(Equal("end of the available WAL"))
+	})
+
+	It("lists the defined fields", func() {
+		Expect(formatRecoveryTarget(&apiv1.RecoveryTarget{
+			BackupID:        "20250310T110000",
+			TargetTLI:       "2",
+			TargetImmediate: ptr.To(true),
+		})).To(Equal("backupID=20250310T110000, targetTLI=2, targetImmediate"))
+	})
+})
@@ -30,6 +30,7 @@ import (
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
 	volumesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -116,7 +117,8 @@ func NewBackupReconciler(
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=backups,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=backups/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters,verbs=get
-// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;create;watch;list;patch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;create;watch;list;patch;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups="",resources=pods/exec,verbs=get;list;delete;patch;create;watch
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get
@@ -150,10 +152,13 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	// checks depend on the runtime environment rather than the (immutable)
 	// spec (for example the VolumeSnapshot CRD presence), so a transient
 	// environment change would otherwise clobber a Completed/Failed record
-	// with the "invalid backup definition" phase.
+	// with the "invalid backup definition" phase. The only work left on a
+	// Completed backup is its verification, when requested.
 	switch backup.Status.Phase {
-	case apiv1.BackupPhaseFailed, apiv1.BackupPhaseCompleted:
+	case apiv1.BackupPhaseFailed:
 		return ctrl.Result{}, nil
+	case apiv1.BackupPhaseCompleted:
+		return r.reconcileBackupVerification(ctx, &backup)
 	}
 
 	if result, err := r.admission.EnsureResourceIsAdmitted(
@@ -240,6 +245,14 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	}
 
 	switch {
+	case backup.Spec.Method == apiv1.BackupMethodLogical:
+		res, err := r.reconcileLogicalBackup(ctx, &cluster, &backup)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if res != nil {
+			return *res, nil
+		}
 	case backup.Spec.Method.IsManagedByInstance():
 		res, err := r.startBackupManagedByInstance(ctx, cluster, &backup)
 		if err != nil {
@@ -442,6 +455,41 @@ func (r *BackupReconciler) checkPrerequisites(
 		}
 	}
 
+	if backup.Spec.Method == apiv1.BackupMethodLogical {
+		if !cluster.Spec.Backup.IsLogicalBackupConfigured() {
+			const message = "no logical section defined on the target cluster"
+			return flagMissingPrerequisite(message, "ClusterHasNoLogicalBackupSection")
+		}
+
+		if cluster.Spec.Backup.Logical.Credentials == nil && !cluster.GetEnableSuperuserAccess() {
+			const message = "logical backups without credentials require the superuser access to be enabled"
+			return flagMissingPrerequisite(message, "ClusterHasNoLogicalBackupCredentials")
+		}
+	}
+
+	if backup.Spec.Method == apiv1.BackupMethodPgBaseBackup {
+		if !cluster.Spec.Backup.IsPgBaseBackupConfigured() {
+			const message = "no pgBaseBackup section defined on the target cluster"
+			return flagMissingPrerequisite(message, "ClusterHasNoPgBaseBackupSection")
+		}
+
+		if cluster.ContainsTablespaces() {
+			const message = "the pgBaseBackup method does not support clusters with tablespaces"
+			return flagMissingPrerequisite(message, "ClusterHasTablespaces")
+		}
+
+		if backup.GetType() != apiv1.BackupTypeFull {
+			majorVersion, err := cluster.GetPostgresqlMajorVersion()
+			if err != nil {
+				return nil, err
+			}
+			if majorVersion < 17 {
+				const message = "incremental and differential backups require PostgreSQL 17 or later"
+				return flagMissingPrerequisite(message, "IncrementalBackupNotSupported")
+			}
+		}
+	}
+
 	return nil, nil
 }
 
@@ -754,6 +802,8 @@ func (r *BackupReconciler) reconcileSnapshotBackup(
 		contextLogger.Error(err, "Can't update the cluster with the completed snapshot backup data")
 	}
 
+	enforceSnapshotRetentionPolicy(ctx, r.Client, r.Recorder, cluster)
+
 	if err := updateClusterWithSnapshotsBackupTimes(ctx, r.Client, cluster.Namespace, cluster.Name); err != nil {
 		contextLogger.Error(err, "could not update cluster's backups metadata")
 	}
@@ -1039,6 +1089,7 @@ func (r *BackupReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manage
 
 	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
 		For(&apiv1.Backup{}).
+		Owns(&batchv1.Job{}).
 		Named("backup").
 		Watches(&apiv1.Cluster{},
 			handler.EnqueueRequestsFromMapFunc(r.mapClustersToBackup()),
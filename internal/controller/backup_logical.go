@@ -0,0 +1,109 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	batchv1 "k8s.io/api/batch/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	resourcestatus "github.com/cloudnative-pg/cloudnative-pg/pkg/resources/status"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// reconcileLogicalBackup starts the job exporting the databases with
+// pg_dump and follows its progress. The job itself marks the backup as
+// completed, while the failures of the job are detected here
+func (r *BackupReconciler) reconcileLogicalBackup(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	backup *apiv1.Backup,
+) (*ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	if len(backup.Status.Phase) == 0 || backup.Status.Phase == apiv1.BackupPhasePending {
+		backup.Status.Phase = apiv1.BackupPhaseStarted
+		backup.Status.Error = ""
+		backup.Status.Method = apiv1.BackupMethodLogical
+		backup.Status.ReconciliationStartedAt = ptr.To(metav1.Now())
+		// given that the dumps are stored in a directory named
+		// after the backup, we can use the backup name as ID
+		backup.Status.BackupID = backup.Name
+		backup.Status.BackupName = backup.Name
+		backup.Status.StartedAt = backup.Status.ReconciliationStartedAt.DeepCopy()
+		backup.Status.LogicalBackupStatus = &apiv1.BackupLogicalStatus{
+			ClaimName: cluster.Spec.Backup.Logical.ClaimName,
+			Path:      postgres.GetLogicalBackupPath(cluster.Name, backup.Name),
+		}
+		if err := postgres.PatchBackupStatusAndRetry(ctx, r.Client, backup); err != nil {
+			return nil, err
+		}
+
+		if err := resourcestatus.PatchConditionsWithOptimisticLock(
+			ctx,
+			r.Client,
+			cluster,
+			apiv1.BackupStartingCondition,
+		); err != nil {
+			contextLogger.Error(err, "Error while updating backup condition (backup starting)")
+		}
+	}
+
+	var job batchv1.Job
+	err := r.Get(ctx, client.ObjectKey{
+		Namespace: backup.Namespace,
+		Name:      specs.GetLogicalBackupJobName(backup.Name),
+	}, &job)
+	if apierrs.IsNotFound(err) {
+		newJob := specs.CreateLogicalBackupJob(*cluster, backup)
+		contextLogger.Info("Creating logical backup job", "jobName", newJob.Name)
+		r.Recorder.Eventf(backup, "Normal", "Starting",
+			"Starting logical backup job %s for cluster %v", newJob.Name, cluster.Name)
+		if err := r.Create(ctx, newJob); err != nil && !apierrs.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("while creating the logical backup job: %w", err)
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("while getting the logical backup job: %w", err)
+	}
+
+	if utils.JobHasFailed(job) {
+		failure := errors.New("the logical backup job failed")
+		contextLogger.Info("Logical backup job failed", "jobName", job.Name)
+		r.Recorder.Eventf(backup, "Warning", "Error", "Logical backup job %s failed", job.Name)
+		_ = resourcestatus.FlagBackupAsFailed(ctx, r.Client, backup, cluster, failure)
+		return &ctrl.Result{}, reconcile.TerminalError(failure)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/backup/volumesnapshot"
+)
+
+// snapshotRetentionPolicyInterval is how often the retention policy of the
+// backups taken with volume snapshots is enforced, so that the backups
+// expire even when no new backup is taken
+const snapshotRetentionPolicyInterval = 5 * time.Minute
+
+// enforceSnapshotRetentionPolicy removes the backups taken with volume
+// snapshots which are expired by the retention policy of the cluster,
+// updating the backup times in the status of the cluster
+func enforceSnapshotRetentionPolicy(
+	ctx context.Context,
+	cli client.Client,
+	recorder record.EventRecorder,
+	cluster *apiv1.Cluster,
+) {
+	contextLogger := log.FromContext(ctx)
+
+	removedBackups, err := volumesnapshot.EnforceRetentionPolicy(ctx, cli, cluster, time.Now())
+	if err != nil {
+		contextLogger.Error(err, "could not enforce the volume snapshot retention policy")
+	}
+	if len(removedBackups) == 0 {
+		return
+	}
+
+	for _, backupName := range removedBackups {
+		recorder.Eventf(cluster, "Normal", "BackupRetention",
+			"Removed backup %s, expired by the volume snapshot retention policy", backupName)
+	}
+
+	if err := updateClusterWithSnapshotsBackupTimes(ctx, cli, cluster.Namespace, cluster.Name); err != nil {
+		contextLogger.Error(err, "could not update cluster's backups metadata")
+	}
+}
+
+// reconcileSnapshotRetentionPolicy periodically enforces the retention
+// policy of the backups taken with volume snapshots, returning when it
+// needs to be enforced again, or zero if the cluster has no such policy
+func (r *ClusterReconciler) reconcileSnapshotRetentionPolicy(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) time.Duration {
+	if cluster.Spec.Backup == nil || cluster.Spec.Backup.VolumeSnapshot == nil ||
+		cluster.Spec.Backup.VolumeSnapshot.RetentionPolicy.IsEmpty() {
+		return 0
+	}
+
+	enforceSnapshotRetentionPolicy(ctx, r.Client, r.Recorder, cluster)
+	return snapshotRetentionPolicyInterval
+}
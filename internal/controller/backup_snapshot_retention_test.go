@@ -0,0 +1,100 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"time"
+
+	volumesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("reconcileSnapshotRetentionPolicy", func() {
+	var (
+		env       *testingEnvironment
+		namespace string
+		cluster   *apiv1.Cluster
+	)
+
+	newSnapshot := func(backupName string, endTime time.Time) *volumesnapshotv1.VolumeSnapshot {
+		return &volumesnapshotv1.VolumeSnapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      backupName,
+				Labels: map[string]string{
+					utils.ClusterLabelName:    cluster.Name,
+					utils.BackupNameLabelName: backupName,
+				},
+				Annotations: map[string]string{
+					utils.PvcRoleLabelName:            string(utils.PVCRolePgData),
+					utils.BackupEndTimeAnnotationName: endTime.Format(time.RFC3339),
+				},
+			},
+		}
+	}
+
+	BeforeEach(func(ctx SpecContext) {
+		env = buildTestEnvironment()
+		namespace = newFakeNamespace(env.client)
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: namespace},
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{
+					VolumeSnapshot: &apiv1.VolumeSnapshotConfiguration{
+						RetentionPolicy: &apiv1.VolumeSnapshotRetentionPolicy{KeepLast: ptr.To(1)},
+					},
+				},
+			},
+		}
+		Expect(env.client.Create(ctx, cluster)).To(Succeed())
+	})
+
+	It("doesn't requeue clusters without a retention policy", func(ctx SpecContext) {
+		cluster.Spec.Backup.VolumeSnapshot.RetentionPolicy = nil
+		Expect(env.clusterReconciler.reconcileSnapshotRetentionPolicy(ctx, cluster)).To(BeZero())
+	})
+
+	It("removes the expired backups and requeues the cluster", func(ctx SpecContext) {
+		now := time.Now().Truncate(time.Second)
+		Expect(env.client.Create(ctx, newSnapshot("backup-1", now.Add(-time.Hour)))).To(Succeed())
+		Expect(env.client.Create(ctx, newSnapshot("backup-2", now.Add(-2*time.Hour)))).To(Succeed())
+
+		Expect(env.clusterReconciler.reconcileSnapshotRetentionPolicy(ctx, cluster)).
+			To(Equal(snapshotRetentionPolicyInterval))
+
+		var snapshots volumesnapshotv1.VolumeSnapshotList
+		Expect(env.client.List(ctx, &snapshots, client.InNamespace(namespace))).To(Succeed())
+		Expect(snapshots.Items).To(HaveLen(1))
+		Expect(snapshots.Items[0].Name).To(Equal("backup-1"))
+
+		var updatedCluster apiv1.Cluster
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		Expect(updatedCluster.Status.FirstRecoverabilityPointByMethod[apiv1.BackupMethodVolumeSnapshot].Time).
+			To(BeTemporally("==", now.Add(-time.Hour)))
+	})
+})
@@ -0,0 +1,204 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	volumesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// errBackupVerificationJobFailed is recorded in the status of the backups
+// whose verification job failed before reporting the result of the checks
+var errBackupVerificationJobFailed = errors.New("the verification job failed")
+
+// reconcileBackupVerification starts the job restoring a completed backup
+// into a throwaway instance and follows its progress. The job itself
+// records the result of the checks, while the failures of the job are
+// detected here. The job is removed once the verification succeeds, and
+// kept after a failure to allow troubleshooting
+func (r *BackupReconciler) reconcileBackupVerification(
+	ctx context.Context,
+	backup *apiv1.Backup,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	if backup.Spec.Verification == nil || backup.Status.Phase != apiv1.BackupPhaseCompleted {
+		return ctrl.Result{}, nil
+	}
+
+	var job batchv1.Job
+	err := r.Get(ctx, client.ObjectKey{
+		Namespace: backup.Namespace,
+		Name:      specs.GetBackupVerificationJobName(backup.Name),
+	}, &job)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return ctrl.Result{}, fmt.Errorf("while getting the backup verification job: %w", err)
+	}
+	jobExists := err == nil
+
+	switch {
+	case backup.Status.Verification.IsDone():
+		if jobExists && backup.Status.Verification.Phase == apiv1.BackupVerificationPhaseSucceeded {
+			contextLogger.Info("Deleting completed backup verification job", "jobName", job.Name)
+			err := r.Delete(ctx, &job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+			if err != nil && !apierrs.IsNotFound(err) {
+				return ctrl.Result{}, fmt.Errorf("while deleting the backup verification job: %w", err)
+			}
+		}
+		return ctrl.Result{}, nil
+
+	case jobExists && utils.JobHasFailed(job):
+		contextLogger.Info("Backup verification job failed", "jobName", job.Name)
+		r.Recorder.Eventf(backup, "Warning", "VerificationFailed", "Backup verification job %s failed", job.Name)
+		backup.Status.SetVerificationResult(nil, errBackupVerificationJobFailed)
+		return ctrl.Result{}, postgres.PatchBackupStatusAndRetry(ctx, r.Client, backup)
+
+	case jobExists:
+		return ctrl.Result{}, nil
+	}
+
+	var cluster apiv1.Cluster
+	if err := r.Get(ctx, client.ObjectKey{
+		Namespace: backup.Namespace,
+		Name:      backup.Spec.Cluster.Name,
+	}, &cluster); err != nil {
+		if apierrs.IsNotFound(err) {
+			return ctrl.Result{}, r.failBackupVerification(ctx, backup,
+				fmt.Errorf("unknown cluster %v", backup.Spec.Cluster.Name))
+		}
+		return ctrl.Result{}, err
+	}
+
+	newJob, err := r.createBackupVerificationJob(ctx, &cluster, backup)
+	if err != nil {
+		return ctrl.Result{}, r.failBackupVerification(ctx, backup, err)
+	}
+
+	if backup.Status.Verification == nil {
+		backup.Status.SetVerificationAsRunning()
+		if err := postgres.PatchBackupStatusAndRetry(ctx, r.Client, backup); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	contextLogger.Info("Creating backup verification job", "jobName", newJob.Name)
+	r.Recorder.Eventf(backup, "Normal", "VerificationStarting",
+		"Starting backup verification job %s", newJob.Name)
+	if err := r.Create(ctx, newJob); err != nil && !apierrs.IsAlreadyExists(err) {
+		return ctrl.Result{}, fmt.Errorf("while creating the backup verification job: %w", err)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// createBackupVerificationJob builds the job verifying the passed backup,
+// checking that the backup can be restored with the current configuration
+// of the cluster
+func (r *BackupReconciler) createBackupVerificationJob(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	backup *apiv1.Backup,
+) (*batchv1.Job, error) {
+	if !backup.Spec.Method.SupportsVerification() {
+		return nil, fmt.Errorf("backups taken with the %s method cannot be verified", backup.Spec.Method)
+	}
+
+	majorVersion, err := cluster.GetPostgresqlMajorVersion()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get major version from cluster: %w", err)
+	}
+	if backup.Status.MajorVersion != 0 && backup.Status.MajorVersion != majorVersion {
+		return nil, fmt.Errorf(
+			"the backup was taken with PostgreSQL %d, while the cluster is running PostgreSQL %d",
+			backup.Status.MajorVersion, majorVersion)
+	}
+
+	var dataSnapshot *metav1.ObjectMeta
+	if backup.Spec.Method == apiv1.BackupMethodVolumeSnapshot {
+		dataSnapshot, err = r.getBackupDataSnapshotMetadata(ctx, backup)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return specs.CreateBackupVerificationJob(*cluster, backup, dataSnapshot)
+}
+
+// getBackupDataSnapshotMetadata returns the metadata of the PG_DATA
+// snapshot taken by a volume snapshot backup
+func (r *BackupReconciler) getBackupDataSnapshotMetadata(
+	ctx context.Context,
+	backup *apiv1.Backup,
+) (*metav1.ObjectMeta, error) {
+	for _, element := range backup.Status.BackupSnapshotStatus.Elements {
+		if utils.PVCRole(element.Type) != utils.PVCRolePgData {
+			continue
+		}
+
+		metadata, err := persistentvolumeclaim.GetSourceMetadataOrNil(
+			ctx,
+			r.Client,
+			backup.Namespace,
+			corev1.TypedLocalObjectReference{
+				APIGroup: ptr.To(volumesnapshotv1.GroupName),
+				Kind:     apiv1.VolumeSnapshotKind,
+				Name:     element.Name,
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if metadata == nil {
+			return nil, fmt.Errorf("the PG_DATA volume snapshot %s no longer exists", element.Name)
+		}
+		return metadata, nil
+	}
+
+	return nil, errors.New("the backup has no PG_DATA volume snapshot")
+}
+
+// failBackupVerification records that a backup cannot be verified
+func (r *BackupReconciler) failBackupVerification(
+	ctx context.Context,
+	backup *apiv1.Backup,
+	failure error,
+) error {
+	log.FromContext(ctx).Info("Cannot verify backup", "reason", failure.Error())
+	r.Recorder.Eventf(backup, "Warning", "VerificationFailed", "Cannot verify backup: %v", failure)
+	backup.Status.SetVerificationResult(nil, failure)
+	return postgres.PatchBackupStatusAndRetry(ctx, r.Client, backup)
+}
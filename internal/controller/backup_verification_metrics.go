@@ -0,0 +1,158 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+const (
+	// backupVerificationMetricsNamespace is the namespace of the metrics
+	// exported by the operator about the verification of the backups
+	backupVerificationMetricsNamespace = "cnpg"
+
+	// backupVerificationMetricsSubsystem is the subsystem of the metrics
+	// exported by the operator about the verification of the backups
+	backupVerificationMetricsSubsystem = "backup_verification"
+
+	// backupVerificationListTimeout is the maximum time spent listing the
+	// backups while the metrics are being scraped
+	backupVerificationListTimeout = 10 * time.Second
+)
+
+// backupVerificationMetrics contains the metrics about the verification of
+// the backups of each cluster
+type backupVerificationMetrics struct {
+	// Backups is the number of backups whose verification succeeded or failed
+	Backups *prometheus.GaugeVec
+
+	// LastTimestamp is the time when the latest verification that
+	// succeeded or failed was terminated
+	LastTimestamp *prometheus.GaugeVec
+}
+
+// newBackupVerificationMetrics creates the metrics about the verification
+// of the backups
+func newBackupVerificationMetrics() *backupVerificationMetrics {
+	labels := []string{"namespace", "cluster", "result"}
+
+	return &backupVerificationMetrics{
+		Backups: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: backupVerificationMetricsNamespace,
+			Subsystem: backupVerificationMetricsSubsystem,
+			Name:      "backups",
+			Help:      "Number of backups of the cluster whose verification terminated with the given result",
+		}, labels),
+		LastTimestamp: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: backupVerificationMetricsNamespace,
+			Subsystem: backupVerificationMetricsSubsystem,
+			Name:      "last_timestamp_seconds",
+			Help:      "Time when the latest verification of a backup of the cluster terminated with the given result",
+		}, labels),
+	}
+}
+
+// update recomputes the metrics from the status of the passed backups
+func (m *backupVerificationMetrics) update(backups []apiv1.Backup) {
+	m.Backups.Reset()
+	m.LastTimestamp.Reset()
+
+	type metricKey struct {
+		namespace string
+		cluster   string
+		result    apiv1.BackupVerificationPhase
+	}
+	lastTimestamps := make(map[metricKey]time.Time)
+
+	for _, backup := range backups {
+		verification := backup.Status.Verification
+		if !verification.IsDone() {
+			continue
+		}
+
+		key := metricKey{
+			namespace: backup.Namespace,
+			cluster:   backup.Spec.Cluster.Name,
+			result:    verification.Phase,
+		}
+		m.Backups.WithLabelValues(key.namespace, key.cluster, string(key.result)).Inc()
+
+		if verification.StoppedAt != nil && verification.StoppedAt.After(lastTimestamps[key]) {
+			lastTimestamps[key] = verification.StoppedAt.Time
+		}
+	}
+
+	for key, lastTimestamp := range lastTimestamps {
+		m.LastTimestamp.WithLabelValues(key.namespace, key.cluster, string(key.result)).
+			Set(float64(lastTimestamp.Unix()))
+	}
+}
+
+// BackupVerificationCollector exports the metrics about the verification
+// of the backups, computing them from the status of the Backup resources
+// every time they are scraped
+type BackupVerificationCollector struct {
+	cli     client.Reader
+	metrics *backupVerificationMetrics
+	mu      sync.Mutex
+}
+
+// NewBackupVerificationCollector creates a collector exporting the
+// metrics about the verification of the backups readable by the passed
+// client
+func NewBackupVerificationCollector(cli client.Reader) *BackupVerificationCollector {
+	return &BackupVerificationCollector{
+		cli:     cli,
+		metrics: newBackupVerificationMetrics(),
+	}
+}
+
+// Describe implements the prometheus.Collector interface
+func (c *BackupVerificationCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.metrics.Backups.Describe(ch)
+	c.metrics.LastTimestamp.Describe(ch)
+}
+
+// Collect implements the prometheus.Collector interface
+func (c *BackupVerificationCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), backupVerificationListTimeout)
+	defer cancel()
+
+	var backups apiv1.BackupList
+	if err := c.cli.List(ctx, &backups); err != nil {
+		log.Error(err, "while listing backups to collect the verification metrics")
+		return
+	}
+
+	c.metrics.update(backups.Items)
+	c.metrics.Backups.Collect(ch)
+	c.metrics.LastTimestamp.Collect(ch)
+}
@@ -0,0 +1,101 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/metricstest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BackupVerificationCollector", func() {
+	const namespace = "default"
+
+	newBackup := func(
+		name string,
+		phase apiv1.BackupVerificationPhase,
+		stoppedAt time.Time,
+	) *apiv1.Backup {
+		backup := &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: apiv1.BackupSpec{
+				Cluster: apiv1.LocalObjectReference{Name: "cluster-example"},
+			},
+		}
+		if phase != "" {
+			backup.Status.Verification = &apiv1.BackupVerificationStatus{
+				Phase:     phase,
+				StoppedAt: &metav1.Time{Time: stoppedAt},
+			}
+		}
+		return backup
+	}
+
+	It("exports the result of the verifications of each cluster", func() {
+		firstSuccess := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+		lastSuccess := time.Date(2026, 10, 2, 12, 0, 0, 0, time.UTC)
+		failure := time.Date(2026, 10, 3, 12, 0, 0, 0, time.UTC)
+
+		cli := fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(
+				newBackup("backup-1", apiv1.BackupVerificationPhaseSucceeded, firstSuccess),
+				newBackup("backup-2", apiv1.BackupVerificationPhaseSucceeded, lastSuccess),
+				newBackup("backup-3", apiv1.BackupVerificationPhaseFailed, failure),
+				newBackup("backup-4", apiv1.BackupVerificationPhaseRunning, time.Time{}),
+				newBackup("backup-5", "", time.Time{}),
+			).
+			Build()
+
+		collector := NewBackupVerificationCollector(cli)
+		Expect(metricstest.Count(collector)).To(Equal(4))
+
+		succeeded := []string{namespace, "cluster-example", string(apiv1.BackupVerificationPhaseSucceeded)}
+		failed := []string{namespace, "cluster-example", string(apiv1.BackupVerificationPhaseFailed)}
+		Expect(metricstest.Value(collector.metrics.Backups.WithLabelValues(succeeded...))).To(BeEquivalentTo(2))
+		Expect(metricstest.Value(collector.metrics.Backups.WithLabelValues(failed...))).To(BeEquivalentTo(1))
+		Expect(metricstest.Value(collector.metrics.LastTimestamp.WithLabelValues(succeeded...))).
+			To(BeEquivalentTo(lastSuccess.Unix()))
+		Expect(metricstest.Value(collector.metrics.LastTimestamp.WithLabelValues(failed...))).
+			To(BeEquivalentTo(failure.Unix()))
+	})
+
+	It("drops the metrics of the backups that have been deleted", func(ctx SpecContext) {
+		backup := newBackup("backup-1", apiv1.BackupVerificationPhaseFailed, time.Now())
+		cli := fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(backup).
+			Build()
+
+		collector := NewBackupVerificationCollector(cli)
+		Expect(metricstest.Count(collector)).To(Equal(2))
+
+		Expect(cli.Delete(ctx, backup)).To(Succeed())
+		Expect(metricstest.Count(collector)).To(BeZero())
+	})
+})
@@ -0,0 +1,162 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("backup verification", func() {
+	var env *testingEnvironment
+	var cluster *apiv1.Cluster
+	var backup *apiv1.Backup
+
+	BeforeEach(func(ctx context.Context) {
+		env = buildTestEnvironment()
+		namespace := newFakeNamespace(env.client)
+
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: namespace,
+			},
+			Spec: apiv1.ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:17.2",
+				StorageConfiguration: apiv1.StorageConfiguration{
+					Size: "1Gi",
+				},
+			},
+		}
+		Expect(env.client.Create(ctx, cluster)).To(Succeed())
+
+		backup = &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "backup-example",
+				Namespace: namespace,
+			},
+			Spec: apiv1.BackupSpec{
+				Cluster:      apiv1.LocalObjectReference{Name: cluster.Name},
+				Method:       apiv1.BackupMethodBarmanObjectStore,
+				Verification: &apiv1.BackupVerificationConfiguration{},
+			},
+		}
+		Expect(env.client.Create(ctx, backup)).To(Succeed())
+		backup.Status.Phase = apiv1.BackupPhaseCompleted
+		backup.Status.MajorVersion = 17
+		Expect(env.client.Status().Update(ctx, backup)).To(Succeed())
+	})
+
+	reconcileBackup := func(ctx context.Context) {
+		result, err := env.backupReconciler.Reconcile(ctx, ctrl.Request{
+			NamespacedName: client.ObjectKeyFromObject(backup),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(ctrl.Result{}))
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(backup), backup)).To(Succeed())
+	}
+
+	getJob := func(ctx context.Context) (*batchv1.Job, error) {
+		var job batchv1.Job
+		err := env.client.Get(ctx, client.ObjectKey{
+			Namespace: backup.Namespace,
+			Name:      specs.GetBackupVerificationJobName(backup.Name),
+		}, &job)
+		return &job, err
+	}
+
+	It("starts the verification job of a completed backup", func(ctx context.Context) {
+		reconcileBackup(ctx)
+
+		Expect(backup.Status.Phase).To(BeEquivalentTo(apiv1.BackupPhaseCompleted))
+		Expect(backup.Status.Verification).ToNot(BeNil())
+		Expect(backup.Status.Verification.Phase).To(Equal(apiv1.BackupVerificationPhaseRunning))
+		Expect(meta.IsStatusConditionPresentAndEqual(
+			backup.Status.Conditions, apiv1.BackupConditionVerified, metav1.ConditionUnknown)).To(BeTrue())
+
+		job, err := getJob(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(job.OwnerReferences[0].Name).To(Equal(backup.Name))
+	})
+
+	It("fails the verification when the job fails, keeping the job", func(ctx context.Context) {
+		reconcileBackup(ctx)
+
+		job, err := getJob(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		job.Status.Conditions = []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: corev1.ConditionTrue},
+		}
+		Expect(env.client.Status().Update(ctx, job)).To(Succeed())
+
+		reconcileBackup(ctx)
+		Expect(backup.Status.Verification.Phase).To(Equal(apiv1.BackupVerificationPhaseFailed))
+		Expect(backup.Status.Verification.Error).To(Equal(errBackupVerificationJobFailed.Error()))
+
+		_, err = getJob(ctx)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("removes the job once the verification succeeded", func(ctx context.Context) {
+		reconcileBackup(ctx)
+
+		backup.Status.SetVerificationResult([]apiv1.BackupVerificationCheckStatus{
+			{Name: apiv1.BackupVerificationAmcheckName, Passed: true},
+		}, nil)
+		Expect(env.client.Status().Update(ctx, backup)).To(Succeed())
+
+		reconcileBackup(ctx)
+		_, err := getJob(ctx)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("refuses to verify a backup of a different major version", func(ctx context.Context) {
+		backup.Status.MajorVersion = 16
+		Expect(env.client.Status().Update(ctx, backup)).To(Succeed())
+
+		reconcileBackup(ctx)
+		Expect(backup.Status.Verification.Phase).To(Equal(apiv1.BackupVerificationPhaseFailed))
+		Expect(backup.Status.Verification.Error).To(ContainSubstring("PostgreSQL 16"))
+
+		_, err := getJob(ctx)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("ignores completed backups without verification", func(ctx context.Context) {
+		backup.Spec.Verification = nil
+		Expect(env.client.Update(ctx, backup)).To(Succeed())
+
+		reconcileBackup(ctx)
+		Expect(backup.Status.Verification).To(BeNil())
+	})
+})
@@ -0,0 +1,83 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+)
+
+// reconcileStorageAutoscaling grows the PVCs whose filesystem usage reached
+// the threshold set in their autoscale configuration. The instances that
+// stopped because of missing disk space don't report any usage, and their
+// WAL volume is considered full
+func (r *ClusterReconciler) reconcileStorageAutoscaling(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+	instancesStatus postgres.PostgresqlStatusList,
+) error {
+	volumesUsage := make(map[string][]postgres.VolumeUsage, len(instancesStatus.Items))
+	for _, item := range instancesStatus.Items {
+		if item.Pod == nil {
+			continue
+		}
+
+		usages := item.VolumesUsage
+		if !isWALSpaceAvailableOnPod(item.Pod) {
+			usages = append(usages, persistentvolumeclaim.GetFullWALVolumeUsage(cluster))
+		}
+		if len(usages) > 0 {
+			volumesUsage[item.Pod.Name] = usages
+		}
+	}
+	if len(volumesUsage) == 0 {
+		return nil
+	}
+
+	autoscaledPVCs, err := persistentvolumeclaim.ReconcileAutoscaling(
+		ctx,
+		r.Client,
+		cluster,
+		volumesUsage,
+		resources.pvcs.Items,
+		time.Now(),
+	)
+	for _, autoscaledPVC := range autoscaledPVCs {
+		if autoscaledPVC.IsLimitReached() {
+			r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "StorageAutoscaleLimitReached",
+				"PVC %s reached its maximum size of %s, filesystem usage %d%%",
+				autoscaledPVC.Name, autoscaledPVC.OldSize.String(), autoscaledPVC.Usage)
+			continue
+		}
+
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "StorageAutoscale",
+			"Growing PVC %s from %s to %s, filesystem usage %d%%",
+			autoscaledPVC.Name, autoscaledPVC.OldSize.String(), autoscaledPVC.NewSize.String(), autoscaledPVC.Usage)
+	}
+
+	return err
+}
@@ -59,6 +59,7 @@ import (
 	rolloutManager "github.com/cloudnative-pg/cloudnative-pg/internal/controller/rollout"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/webhook/guard"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/otlp"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver/client/remote"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/hibernation"
@@ -79,6 +80,7 @@ const (
 	disableDefaultQueriesSpecPath = ".spec.monitoring.disableDefaultQueries"
 	imageCatalogKey               = ".spec.imageCatalog.name"
 	databaseRoleClusterKey        = ".spec.cluster.name"
+	databaseClusterKey            = ".spec.cluster.name"
 	// usedPluginsClusterKey is a synthetic index key, not a real Cluster spec field;
 	// it is populated by getPluginsNeededForReconcile.
 	usedPluginsClusterKey = ".spec.usedPlugins"
@@ -102,9 +104,10 @@ type ClusterReconciler struct {
 	Plugins            repository.Interface
 	OperatorClientCert *tls.Certificate
 
-	drainTaints    []string
-	rolloutManager *rolloutManager.Manager
-	admission      *guard.Admission[*apiv1.Cluster]
+	drainTaints     []string
+	rolloutManager  *rolloutManager.Manager
+	admission       *guard.Admission[*apiv1.Cluster]
+	tracerProviders *otlp.TracerProviders
 }
 
 // NewClusterReconciler creates a new ClusterReconciler initializing it
@@ -128,8 +131,9 @@ func NewClusterReconciler(
 			configuration.Current.GetClustersRolloutDelay(),
 			configuration.Current.GetInstancesRolloutDelay(),
 		),
-		drainTaints: drainTaints,
-		admission:   admission,
+		drainTaints:     drainTaints,
+		admission:       admission,
+		tracerProviders: otlp.NewTracerProviders("operator"),
 	}
 }
 
@@ -164,6 +168,8 @@ var ErrNextLoop = utils.ErrNextLoop
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusterimagecatalogs,verbs=get;watch;list
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=failoverquorums,verbs=create;get;watch;delete;list
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=failoverquorums/status,verbs=get;patch;update;watch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=switchovers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=switchovers/status,verbs=get;patch;update
 
 // Reconcile is the operator reconcile loop
 func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -180,6 +186,7 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	}
 
 	if cluster == nil || cluster.GetDeletionTimestamp() != nil {
+		r.tracerProviders.Remove(ctx, req.NamespacedName)
 		if err := r.deleteDanglingMonitoringQueries(ctx, req.Namespace); err != nil {
 			contextLogger.Error(
 				err,
@@ -263,14 +270,21 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 
 	ctx = cnpgiClient.SetPluginClientInContext(ctx, pluginClient)
 
+	// Trace the inner reconcile loop, including the calls to the instance manager,
+	// if requested in the monitoring configuration of the cluster
+	ctx, span := r.tracerProviders.StartSpan(ctx, cluster, "Reconcile Cluster")
+
 	// Run the inner reconcile loop. Translate any ErrNextLoop to an errorless return
 	result, err := r.reconcile(ctx, cluster)
 	if errors.Is(err, ErrNextLoop) {
+		otlp.EndSpan(span, nil)
 		return result, nil
 	}
 	if errors.Is(err, utils.ErrTerminateLoop) {
+		otlp.EndSpan(span, nil)
 		return ctrl.Result{}, nil
 	}
+	otlp.EndSpan(span, err)
 
 	// This code assumes that we always end the reconciliation loop if we encounter an error.
 	// In case that the assumption is false this code could overwrite an error phase.
@@ -514,6 +528,10 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return ctrl.Result{}, err
 	}
 
+	if err := r.reconcileStorageAutoscaling(ctx, cluster, resources, instancesStatus); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	if instancesStatus.AllReadyInstancesStatusUnreachable() {
 		contextLogger.Warning(
 			"Failed to extract instance status from ready instances. Attempting to requeue...",
@@ -582,6 +600,15 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 
 		return ctrl.Result{}, fmt.Errorf("cannot update the resource status: %w", err)
 	}
+
+	// Drain the connections to the primary before a switchover, if requested
+	if result, err := r.reconcileSwitchoverDrain(ctx, cluster, instancesStatus); err != nil || result != nil {
+		if result == nil {
+			return ctrl.Result{}, err
+		}
+		return *result, err
+	}
+
 	result, err := r.handleSwitchover(ctx, cluster, resources, instancesStatus)
 	if err != nil {
 		return ctrl.Result{}, err
@@ -590,6 +617,25 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return *result, nil
 	}
 
+	// Execute the planned switchovers requested via Switchover objects
+	plannedSwitchover, err := r.reconcilePlannedSwitchovers(ctx, cluster, instancesStatus)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if plannedSwitchover.started {
+		contextLogger.Info("Waiting for the new primary to notice the planned switchover request")
+		return ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+	}
+
+	// Change the number of instances depending on the load of the replicas
+	autoscalingRecheckAfter, err := r.reconcileReplicaAutoscaling(ctx, cluster, instancesStatus)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Remove the expired backups taken with volume snapshots
+	retentionRecheckAfter := r.reconcileSnapshotRetentionPolicy(ctx, cluster)
+
 	// Updates all the objects managed by the controller
 	res, err := r.reconcileResources(ctx, cluster, resources, instancesStatus)
 	if err != nil || !res.IsZero() {
@@ -598,7 +644,23 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 
 	// Run plugin post-reconcile hooks, sync per-plugin statuses, and
 	// register PhaseHealthy as the LAST status mutation. See #8582.
-	return r.finalizeReconciliation(ctx, cnpgiClient.GetPluginClientFromContext(ctx), cluster)
+	res, err = r.finalizeReconciliation(ctx, cnpgiClient.GetPluginClientFromContext(ctx), cluster)
+	if err != nil {
+		return res, err
+	}
+
+	// Wake up when a pending switchover, the load of the replicas
+	// or the retention policy needs to be evaluated again
+	for _, recheckAfter := range []time.Duration{
+		plannedSwitchover.recheckAfter,
+		autoscalingRecheckAfter,
+		retentionRecheckAfter,
+	} {
+		if recheckAfter > 0 && (res.RequeueAfter == 0 || recheckAfter < res.RequeueAfter) {
+			res.RequeueAfter = recheckAfter
+		}
+	}
+	return res, nil
 }
 
 // evaluatePodReadinessGuards short-circuits the reconciliation loop with a
@@ -1202,6 +1264,12 @@ func (r *ClusterReconciler) handleRollingUpdate(
 
 	// If we need to roll out a restart of any instance, this is the right moment
 	done, err := r.rolloutRequiredInstances(ctx, cluster, &instancesStatus)
+	requestedMajor, isMajorUpgradeWaiting := getMajorUpgradeWaitingForMaintenanceWindow(cluster)
+	if !errors.Is(err, errMaintenanceWindowClosed) && !isMajorUpgradeWaiting {
+		if err := r.clearMaintenancePending(ctx, cluster); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
 	switch {
 	case errors.Is(err, errLogShippingReplicaElected):
 		contextLogger.Warning(
@@ -1209,6 +1277,11 @@ func (r *ClusterReconciler) handleRollingUpdate(
 				"not connected via streaming replication, waiting for 5 seconds",
 		)
 		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	case errors.Is(err, errMaintenanceWindowClosed):
+		contextLogger.Info(
+			"A Pod need to be rolled out, but the rollout is waiting for the next maintenance window",
+		)
+		return r.waitForMaintenanceWindow(ctx, cluster, "The cluster needs to be updated")
 	case errors.Is(err, errRolloutDelayed):
 		contextLogger.Warning(
 			"A Pod need to be rolled out, but the rollout is being delayed",
@@ -1261,6 +1334,15 @@ func (r *ClusterReconciler) handleRollingUpdate(
 		}
 	}
 
+	// The rest of the cluster has been reconciled: only the major
+	// version upgrade is waiting for the next maintenance window
+	if isMajorUpgradeWaiting {
+		contextLogger.Info("A major version upgrade is waiting for the next maintenance window",
+			"requestedMajor", requestedMajor)
+		return r.waitForMaintenanceWindow(ctx, cluster,
+			fmt.Sprintf("Upgrade to major version %v requested", requestedMajor))
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -1271,6 +1353,12 @@ func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 		return err
 	}
 
+	if r.tracerProviders != nil {
+		if err := mgr.Add(r.tracerProviders); err != nil {
+			return err
+		}
+	}
+
 	ctrlBuilder := ctrl.NewControllerManagedBy(mgr).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: maxConcurrentReconciles,
@@ -1331,7 +1419,7 @@ func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 				isBeingDeletedPredicate,
 			)),
 		).
-		// Watch the owned Database, Publication and Subscription resources only
+		// Watch the owned Database, Publication and Subscription resources
 		// while they are being deleted. Their reconcilers run in the instance
 		// manager, so when the cluster is torn down together with its pods the
 		// finalizer can only be removed by the operator controller.
@@ -1339,10 +1427,15 @@ func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 		// the Cluster object is gone and nothing would ever re-trigger
 		// that cleanup after a restart; these watches deliver the lingering
 		// resources on the initial cache sync so the cleanup runs.
+		// Databases are also watched for spec changes, as the secrets used by
+		// their user mappings are part of the instance RBAC.
 		Watches(
 			&apiv1.Database{},
 			handler.EnqueueRequestsFromMapFunc(mapClusterOwnedResourceToCluster),
-			builder.WithPredicates(isBeingDeletedPredicate),
+			builder.WithPredicates(predicate.Or(
+				predicate.GenerationChangedPredicate{},
+				isBeingDeletedPredicate,
+			)),
 		).
 		Watches(
 			&apiv1.Publication{},
@@ -1353,6 +1446,13 @@ func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 			&apiv1.Subscription{},
 			handler.EnqueueRequestsFromMapFunc(mapClusterOwnedResourceToCluster),
 			builder.WithPredicates(isBeingDeletedPredicate),
+		).
+		// Planned switchovers are executed by the cluster reconciliation loop,
+		// which is also the only writer of their status.
+		Watches(
+			&apiv1.Switchover{},
+			handler.EnqueueRequestsFromMapFunc(mapClusterOwnedResourceToCluster),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
 		)
 
 	if configuration.Current.OperatorNamespace != "" {
@@ -1477,7 +1577,7 @@ func (r *ClusterReconciler) createFieldIndexes(ctx context.Context, mgr ctrl.Man
 				return nil
 			}
 
-			return []string{pooler.Spec.Cluster.Name}
+			return pooler.GetReferencedClusterNames()
 		}); err != nil {
 		return err
 	}
@@ -1515,6 +1615,24 @@ func (r *ClusterReconciler) createFieldIndexes(ctx context.Context, mgr ctrl.Man
 		return err
 	}
 
+	// Create a new indexed field on Databases. This field will be used to easily
+	// find all the Databases pointing to a cluster.
+	if err := mgr.GetFieldIndexer().IndexField(
+		ctx,
+		&apiv1.Database{},
+		databaseClusterKey,
+		func(rawObj client.Object) []string {
+			database := rawObj.(*apiv1.Database)
+			if database.Spec.ClusterRef.Name == "" {
+				return nil
+			}
+
+			return []string{database.Spec.ClusterRef.Name}
+		},
+	); err != nil {
+		return err
+	}
+
 	// Create a new indexed field on Jobs.
 	return mgr.GetFieldIndexer().IndexField(
 		ctx,
@@ -1602,16 +1720,20 @@ func (r *ClusterReconciler) mapPoolersToClusters() handler.MapFunc {
 		if !ok || pooler.Spec.Cluster.Name == "" {
 			return nil
 		}
-		var cluster apiv1.Cluster
-		clusterNamespacedName := types.NamespacedName{Namespace: pooler.Namespace, Name: pooler.Spec.Cluster.Name}
-		// get all the clusters handled by the operator in the secret namespaces
-		err := r.Get(ctx, clusterNamespacedName, &cluster)
-		if err != nil {
-			log.FromContext(ctx).Error(err, "while getting cluster for pooler", "pooler", pooler)
-			return nil
+
+		// build requests for every cluster serving the databases of the pooler
+		var requests []reconcile.Request
+		for _, clusterName := range pooler.GetReferencedClusterNames() {
+			var cluster apiv1.Cluster
+			clusterNamespacedName := types.NamespacedName{Namespace: pooler.Namespace, Name: clusterName}
+			err := r.Get(ctx, clusterNamespacedName, &cluster)
+			if err != nil {
+				log.FromContext(ctx).Error(err, "while getting cluster for pooler", "pooler", pooler)
+				continue
+			}
+			requests = append(requests, reconcile.Request{NamespacedName: clusterNamespacedName})
 		}
-		// build requests for cluster referring the secret
-		return []reconcile.Request{{NamespacedName: clusterNamespacedName}}
+		return requests
 	}
 }
 
@@ -773,6 +773,28 @@ func (r *ClusterReconciler) createOrPatchRole(ctx context.Context, cluster *apiv
 		return fmt.Errorf("while listing database roles: %w", err)
 	}
 
+	// The same applies to the databases, whose user mappings may
+	// read the values of their options from secrets
+	var databaseList apiv1.DatabaseList
+
+	if err := r.List(
+		ctx,
+		&databaseList,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingFields{
+			databaseClusterKey: cluster.Name,
+		},
+	); err != nil {
+		return fmt.Errorf("while listing databases: %w", err)
+	}
+
+	roleOptions := specs.RoleOptions{
+		Cluster:      cluster,
+		BackupOrigin: originBackup,
+		Roles:        roleList.Items,
+		Databases:    databaseList.Items,
+	}
+
 	var role rbacv1.Role
 	if err := r.Get(ctx, client.ObjectKey{Name: cluster.Name, Namespace: cluster.Namespace}, &role); err != nil {
 		if !apierrs.IsNotFound(err) {
@@ -780,16 +802,10 @@ func (r *ClusterReconciler) createOrPatchRole(ctx context.Context, cluster *apiv
 		}
 
 		r.Recorder.Event(cluster, "Normal", "CreatingRole", "Creating Cluster Role")
-		return r.createRole(ctx, cluster, originBackup, roleList.Items)
+		return r.createRole(ctx, cluster, roleOptions)
 	}
 
-	generatedRole := specs.CreateRole(
-		specs.RoleOptions{
-			Cluster:      cluster,
-			BackupOrigin: originBackup,
-			Roles:        roleList.Items,
-		},
-	)
+	generatedRole := specs.CreateRole(roleOptions)
 	if equality.Semantic.DeepEqual(generatedRole.Rules, role.Rules) {
 		// Everything fine, the two rules have the same content
 		return nil
@@ -1086,16 +1102,9 @@ func createOrPatchPodMonitor(
 func (r *ClusterReconciler) createRole(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
-	backupOrigin *apiv1.Backup,
-	roles []apiv1.DatabaseRole,
+	roleOptions specs.RoleOptions,
 ) error {
-	role := specs.CreateRole(
-		specs.RoleOptions{
-			Cluster:      cluster,
-			BackupOrigin: backupOrigin,
-			Roles:        roles,
-		},
-	)
+	role := specs.CreateRole(roleOptions)
 	cluster.SetInheritedDataAndOwnership(&role.ObjectMeta)
 
 	err := r.Create(ctx, &role)
@@ -23,6 +23,7 @@ import (
 	"context"
 	"fmt"
 	"slices"
+	"time"
 
 	"github.com/cloudnative-pg/machinery/pkg/image/reference"
 	"github.com/cloudnative-pg/machinery/pkg/log"
@@ -95,7 +96,21 @@ func (r *ClusterReconciler) reconcileImage(ctx context.Context, cluster *apiv1.C
 		}
 
 		if currentMajorVersion < requestedMajorVersion {
-			// Major version upgrade requested
+			// Major version upgrade requested. The new image starts the
+			// upgrade, so it is only selected inside a maintenance window:
+			// in the meantime the instances keep running the current one
+			inWindow, _, err := cluster.IsInMaintenanceWindow(time.Now())
+			if err != nil {
+				return nil, err
+			}
+			if !inWindow {
+				contextLogger.Info(
+					"Major version upgrade waiting for the next maintenance window",
+					"currentImage", cluster.Status.PGDataImageInfo.Image,
+					"requestedImage", requestedImageInfo.Image)
+				return nil, nil
+			}
+
 			return nil, status.PatchWithOptimisticLock(
 				ctx,
 				r.Client,
@@ -22,6 +22,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -226,6 +227,44 @@ var _ = Describe("Cluster image detection", func() {
 		Expect(cluster.Status.PGDataImageInfo.Image).To(Equal("postgres:16.2"))
 		Expect(cluster.Status.PGDataImageInfo.MajorVersion).To(Equal(16))
 	})
+
+	It("keeps the current image for major version upgrades outside the maintenance windows",
+		func(ctx SpecContext) {
+			nextHour := time.Now().UTC().Add(time.Hour)
+			cluster := &apiv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cluster-example",
+					Namespace: "default",
+				},
+				Spec: apiv1.ClusterSpec{
+					ImageName: "postgres:17.2",
+					MaintenanceWindows: []apiv1.MaintenanceWindow{
+						{
+							Schedule: fmt.Sprintf("0 0 %d * * *", nextHour.Hour()),
+							Duration: metav1.Duration{Duration: time.Minute},
+						},
+					},
+				},
+				Status: apiv1.ClusterStatus{
+					Image: "postgres:16.2",
+					PGDataImageInfo: &apiv1.ImageInfo{
+						Image:        "postgres:16.2",
+						MajorVersion: 16,
+					},
+				},
+			}
+
+			r := newFakeReconcilerFor(cluster, nil)
+
+			result, err := r.reconcileImage(ctx, cluster)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(BeNil())
+			Expect(cluster.Status.Image).To(Equal("postgres:16.2"))
+
+			requestedMajor, isWaiting := getMajorUpgradeWaitingForMaintenanceWindow(cluster)
+			Expect(isWaiting).To(BeTrue())
+			Expect(requestedMajor).To(Equal(17))
+		})
 })
 
 var _ = Describe("Cluster image detection with errors", func() {
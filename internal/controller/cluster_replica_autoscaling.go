@@ -0,0 +1,226 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources/status"
+)
+
+const (
+	// replicaAutoscalingInterval is how often the read-replica
+	// autoscaler evaluates the load of the replicas
+	replicaAutoscalingInterval = 30 * time.Second
+
+	// replicaAutoscalingTolerance is the relative distance from the target
+	// values within which the number of instances is not changed, to avoid
+	// continuously scaling around the target
+	replicaAutoscalingTolerance = 0.1
+)
+
+// replicaAutoscalingDecision is the number of instances
+// requested by the read-replica autoscaler
+type replicaAutoscalingDecision struct {
+	// desiredInstances is the number of instances the cluster should have
+	desiredInstances int
+
+	// reasons are the metrics which lead to the decision
+	reasons []string
+}
+
+// reconcileReplicaAutoscaling changes the number of instances of the cluster
+// depending on the load of the replicas, as reported by the instance
+// managers. It returns the time after which the load should be evaluated again
+func (r *ClusterReconciler) reconcileReplicaAutoscaling(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) (time.Duration, error) {
+	autoscaling := cluster.Spec.Autoscaling
+	if autoscaling == nil {
+		return 0, nil
+	}
+
+	contextLogger := log.FromContext(ctx).WithName("replica_autoscaler")
+
+	// The load is evaluated only when the cluster is stable, as
+	// instances being created or restarted don't serve traffic
+	if cluster.Status.Phase != apiv1.PhaseHealthy ||
+		cluster.Status.Instances != cluster.Spec.Instances ||
+		cluster.Status.ReadyInstances != cluster.Spec.Instances ||
+		!instancesStatus.IsComplete() {
+		return replicaAutoscalingInterval, nil
+	}
+
+	if autoscalingStatus := cluster.Status.Autoscaling; autoscalingStatus != nil &&
+		autoscalingStatus.LastScaleTime != nil {
+		if remaining := time.Until(autoscalingStatus.LastScaleTime.Add(autoscaling.GetCooldown())); remaining > 0 {
+			return max(remaining, replicaAutoscalingInterval), nil
+		}
+	}
+
+	decision := computeReplicaAutoscaling(cluster, instancesStatus)
+	if decision.desiredInstances == cluster.Spec.Instances {
+		return replicaAutoscalingInterval, nil
+	}
+
+	reason := strings.Join(decision.reasons, ", ")
+	previousInstances := cluster.Spec.Instances
+	contextLogger.Info("Changing the number of instances",
+		"from", previousInstances, "to", decision.desiredInstances, "reason", reason)
+
+	origCluster := cluster.DeepCopy()
+	cluster.Spec.Instances = decision.desiredInstances
+	if err := r.Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+		return 0, err
+	}
+
+	r.Recorder.Eventf(cluster, "Normal", "ReplicaAutoscale",
+		"Scaling from %d to %d instances: %s", previousInstances, decision.desiredInstances, reason)
+
+	now := metav1.Now()
+	if err := status.PatchWithOptimisticLock(
+		ctx,
+		r.Client,
+		cluster,
+		status.SetReplicaAutoscalingStatus(&apiv1.ReplicaAutoscalingStatus{
+			LastScaleTime:     &now,
+			PreviousInstances: previousInstances,
+			DesiredInstances:  decision.desiredInstances,
+			Reason:            reason,
+		}),
+	); err != nil {
+		return 0, err
+	}
+
+	return max(autoscaling.GetCooldown(), replicaAutoscalingInterval), nil
+}
+
+// computeReplicaAutoscaling computes the number of instances needed to
+// bring the average load of the replicas to the target values, like the
+// HorizontalPodAutoscaler does. The cluster is never scaled down when one
+// of the metrics is not available or a replica is lagging behind, and
+// the result always satisfies the synchronous replication requirements
+func computeReplicaAutoscaling(
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) replicaAutoscalingDecision {
+	autoscaling := cluster.Spec.Autoscaling
+
+	var replicas []postgres.PostgresqlStatus
+	for _, item := range instancesStatus.Items {
+		if !item.IsPrimary && item.Error == nil {
+			replicas = append(replicas, item)
+		}
+	}
+
+	currentReplicas := cluster.Spec.Instances - 1
+	desiredReplicas := 0
+	canScaleDown := len(replicas) > 0
+	evaluated := false
+	var reasons []string
+
+	evaluate := func(metric string, value, target float64) {
+		evaluated = true
+		reasons = append(reasons, fmt.Sprintf("%s %.0f (target %.0f)", metric, value, target))
+
+		replicasForMetric := currentReplicas
+		if ratio := value / target; math.Abs(ratio-1) > replicaAutoscalingTolerance {
+			replicasForMetric = int(math.Ceil(float64(len(replicas)) * ratio))
+		}
+		desiredReplicas = max(desiredReplicas, replicasForMetric)
+	}
+
+	if autoscaling.TargetCPUUtilization != nil {
+		if utilization, ok := getReplicasCPUUtilization(cluster, replicas); ok {
+			evaluate("average CPU utilization %", utilization, float64(*autoscaling.TargetCPUUtilization))
+		} else {
+			canScaleDown = false
+		}
+	}
+
+	if autoscaling.TargetActiveConnections != nil && len(replicas) > 0 {
+		var activeConnections int
+		for _, replica := range replicas {
+			activeConnections += replica.ActiveConnections
+		}
+		evaluate("average active connections",
+			float64(activeConnections)/float64(len(replicas)), float64(*autoscaling.TargetActiveConnections))
+	}
+
+	if autoscaling.MaxReplicationLag != nil {
+		maxLag := autoscaling.MaxReplicationLag.Seconds()
+		for _, replica := range replicas {
+			if replica.ReplayLagSeconds > maxLag {
+				reasons = append(reasons, fmt.Sprintf("replica %s lagging %.0fs behind the primary",
+					replica.Pod.Name, replica.ReplayLagSeconds))
+				desiredReplicas = max(desiredReplicas, currentReplicas+1)
+				canScaleDown = false
+			}
+		}
+	}
+
+	// The replication lag alone is not a measure of the load of the replicas
+	if !canScaleDown || !evaluated {
+		desiredReplicas = max(desiredReplicas, currentReplicas)
+	}
+
+	minInstances := max(autoscaling.MinInstances, cluster.GetMinInstancesForSynchronousReplication())
+	desiredInstances := min(max(desiredReplicas+1, minInstances), autoscaling.MaxInstances)
+	switch {
+	case cluster.Spec.Instances < minInstances:
+		reasons = append(reasons, "instances lower than the minimum")
+	case cluster.Spec.Instances > autoscaling.MaxInstances:
+		reasons = append(reasons, "instances greater than the maximum")
+	}
+
+	return replicaAutoscalingDecision{desiredInstances: desiredInstances, reasons: reasons}
+}
+
+// getReplicasCPUUtilization returns the average CPU usage of the replicas,
+// as a percentage of the requested CPU. The utilization is not available
+// when the CPU request is not set or a replica didn't report its usage
+func getReplicasCPUUtilization(cluster *apiv1.Cluster, replicas []postgres.PostgresqlStatus) (float64, bool) {
+	cpuRequest := cluster.Spec.Resources.Requests.Cpu().MilliValue()
+	if cpuRequest == 0 || len(replicas) == 0 {
+		return 0, false
+	}
+
+	var cpuUsage int64
+	for _, replica := range replicas {
+		if replica.CPUUsageMillicores == nil {
+			return 0, false
+		}
+		cpuUsage += *replica.CPUUsageMillicores
+	}
+
+	return float64(cpuUsage) * 100 / float64(cpuRequest*int64(len(replicas))), true
+}
@@ -0,0 +1,234 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func newAutoscalingTestStatus(name string, isPrimary bool, cpuUsage int64, activeConnections int) postgres.PostgresqlStatus {
+	return postgres.PostgresqlStatus{
+		Pod:                &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}},
+		IsPrimary:          isPrimary,
+		IsPodReady:         true,
+		CPUUsageMillicores: ptr.To(cpuUsage),
+		ActiveConnections:  activeConnections,
+	}
+}
+
+var _ = Describe("read-replica autoscaling decision", func() {
+	var cluster *apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+				},
+				Autoscaling: &apiv1.ReplicaAutoscalingConfiguration{
+					MinInstances:            2,
+					MaxInstances:            6,
+					TargetCPUUtilization:    ptr.To(50),
+					TargetActiveConnections: ptr.To(10),
+				},
+			},
+		}
+	})
+
+	statusList := func(replicas ...postgres.PostgresqlStatus) postgres.PostgresqlStatusList {
+		return postgres.PostgresqlStatusList{
+			Items: append([]postgres.PostgresqlStatus{
+				newAutoscalingTestStatus("cluster-example-1", true, 2000, 50),
+			}, replicas...),
+		}
+	}
+
+	It("keeps the number of instances when the load is near the targets", func() {
+		decision := computeReplicaAutoscaling(cluster, statusList(
+			newAutoscalingTestStatus("cluster-example-2", false, 500, 10),
+			newAutoscalingTestStatus("cluster-example-3", false, 520, 9),
+		))
+		Expect(decision.desiredInstances).To(Equal(3))
+	})
+
+	It("scales up when the CPU utilization is over the target", func() {
+		decision := computeReplicaAutoscaling(cluster, statusList(
+			newAutoscalingTestStatus("cluster-example-2", false, 1000, 10),
+			newAutoscalingTestStatus("cluster-example-3", false, 800, 10),
+		))
+		// 90% average utilization on 2 replicas needs 4 replicas
+		Expect(decision.desiredInstances).To(Equal(5))
+		Expect(decision.reasons).To(ContainElement("average CPU utilization % 90 (target 50)"))
+	})
+
+	It("scales up when the active connections are over the target", func() {
+		decision := computeReplicaAutoscaling(cluster, statusList(
+			newAutoscalingTestStatus("cluster-example-2", false, 500, 15),
+			newAutoscalingTestStatus("cluster-example-3", false, 500, 15),
+		))
+		Expect(decision.desiredInstances).To(Equal(4))
+	})
+
+	It("never exceeds the maximum number of instances", func() {
+		decision := computeReplicaAutoscaling(cluster, statusList(
+			newAutoscalingTestStatus("cluster-example-2", false, 1000, 100),
+			newAutoscalingTestStatus("cluster-example-3", false, 1000, 100),
+		))
+		Expect(decision.desiredInstances).To(Equal(6))
+	})
+
+	It("scales down when all the metrics are under the target", func() {
+		cluster.Spec.Instances = 5
+		decision := computeReplicaAutoscaling(cluster, statusList(
+			newAutoscalingTestStatus("cluster-example-2", false, 100, 2),
+			newAutoscalingTestStatus("cluster-example-3", false, 100, 2),
+			newAutoscalingTestStatus("cluster-example-4", false, 100, 2),
+			newAutoscalingTestStatus("cluster-example-5", false, 100, 2),
+		))
+		Expect(decision.desiredInstances).To(Equal(2))
+	})
+
+	It("doesn't scale down when the CPU usage is not available", func() {
+		replica := newAutoscalingTestStatus("cluster-example-3", false, 0, 2)
+		replica.CPUUsageMillicores = nil
+		decision := computeReplicaAutoscaling(cluster, statusList(
+			newAutoscalingTestStatus("cluster-example-2", false, 100, 2),
+			replica,
+		))
+		Expect(decision.desiredInstances).To(Equal(3))
+	})
+
+	It("doesn't scale down below the synchronous replication requirements", func() {
+		cluster.Spec.Instances = 4
+		cluster.Spec.PostgresConfiguration.Synchronous = &apiv1.SynchronousReplicaConfiguration{
+			Method: apiv1.SynchronousReplicaConfigurationMethodAny,
+			Number: 2,
+		}
+		decision := computeReplicaAutoscaling(cluster, statusList(
+			newAutoscalingTestStatus("cluster-example-2", false, 0, 0),
+			newAutoscalingTestStatus("cluster-example-3", false, 0, 0),
+			newAutoscalingTestStatus("cluster-example-4", false, 0, 0),
+		))
+		Expect(decision.desiredInstances).To(Equal(3))
+	})
+
+	It("scales up and never down when a replica is lagging behind", func() {
+		cluster.Spec.Autoscaling.MaxReplicationLag = &metav1.Duration{Duration: 30 * time.Second}
+		lagging := newAutoscalingTestStatus("cluster-example-3", false, 0, 0)
+		lagging.ReplayLagSeconds = 60
+		decision := computeReplicaAutoscaling(cluster, statusList(
+			newAutoscalingTestStatus("cluster-example-2", false, 0, 0),
+			lagging,
+		))
+		Expect(decision.desiredInstances).To(Equal(4))
+		Expect(decision.reasons).To(ContainElement("replica cluster-example-3 lagging 60s behind the primary"))
+	})
+
+	It("brings the instances within the configured range", func() {
+		cluster.Spec.Instances = 1
+		cluster.Spec.Autoscaling.MinInstances = 3
+		decision := computeReplicaAutoscaling(cluster, statusList())
+		Expect(decision.desiredInstances).To(Equal(3))
+	})
+})
+
+var _ = Describe("read-replica autoscaling reconciliation", func() {
+	var env *testingEnvironment
+	BeforeEach(func() {
+		env = buildTestEnvironment()
+	})
+
+	newAutoscaledCluster := func(namespace string) *apiv1.Cluster {
+		cluster := newFakeCNPGCluster(env.client, namespace, func(cluster *apiv1.Cluster) {
+			cluster.Spec.Autoscaling = &apiv1.ReplicaAutoscalingConfiguration{
+				MinInstances:            2,
+				MaxInstances:            5,
+				TargetActiveConnections: ptr.To(10),
+			}
+		})
+		cluster.Status.Instances = cluster.Spec.Instances
+		cluster.Status.ReadyInstances = cluster.Spec.Instances
+		cluster.Status.Phase = apiv1.PhaseHealthy
+		return cluster
+	}
+
+	overloaded := postgres.PostgresqlStatusList{
+		Items: []postgres.PostgresqlStatus{
+			newAutoscalingTestStatus("cluster-example-1", true, 0, 0),
+			newAutoscalingTestStatus("cluster-example-2", false, 0, 20),
+			newAutoscalingTestStatus("cluster-example-3", false, 0, 20),
+		},
+	}
+
+	It("changes the number of instances and records the decision", func(ctx context.Context) {
+		namespace := newFakeNamespace(env.client)
+		cluster := newAutoscaledCluster(namespace)
+
+		recheckAfter, err := env.clusterReconciler.reconcileReplicaAutoscaling(ctx, cluster, overloaded)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(recheckAfter).To(Equal(5 * time.Minute))
+
+		var updatedCluster apiv1.Cluster
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		Expect(updatedCluster.Spec.Instances).To(Equal(5))
+		Expect(updatedCluster.Status.Autoscaling).ToNot(BeNil())
+		Expect(updatedCluster.Status.Autoscaling.PreviousInstances).To(Equal(3))
+		Expect(updatedCluster.Status.Autoscaling.DesiredInstances).To(Equal(5))
+		Expect(updatedCluster.Status.Autoscaling.LastScaleTime).ToNot(BeNil())
+	})
+
+	It("waits for the cooldown period", func(ctx context.Context) {
+		namespace := newFakeNamespace(env.client)
+		cluster := newAutoscaledCluster(namespace)
+		cluster.Status.Autoscaling = &apiv1.ReplicaAutoscalingStatus{
+			LastScaleTime: ptr.To(metav1.NewTime(time.Now().Add(-time.Minute))),
+		}
+
+		_, err := env.clusterReconciler.reconcileReplicaAutoscaling(ctx, cluster, overloaded)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cluster.Spec.Instances).To(Equal(3))
+	})
+
+	It("waits for the cluster to be healthy", func(ctx context.Context) {
+		namespace := newFakeNamespace(env.client)
+		cluster := newAutoscaledCluster(namespace)
+		cluster.Status.Phase = apiv1.PhaseUpgrade
+
+		recheckAfter, err := env.clusterReconciler.reconcileReplicaAutoscaling(ctx, cluster, overloaded)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(recheckAfter).To(Equal(replicaAutoscalingInterval))
+		Expect(cluster.Spec.Instances).To(Equal(3))
+	})
+})
@@ -485,7 +485,9 @@ func (r *ClusterReconciler) getPgbouncerIntegrationStatus(
 			continue
 		}
 
-		secretName := pooler.GetAuthQuerySecretName()
+		// A pooler may serve databases from several clusters, each
+		// one having its own secret for the automated integration
+		secretName := pooler.GetAuthQuerySecretNameForCluster(cluster.Name)
 		// there is no need to examine further, the potential secret we may add is already present.
 		// This saves us:
 		// - further API calls to the kube-api server,
@@ -498,7 +500,7 @@ func (r *ClusterReconciler) getPgbouncerIntegrationStatus(
 		authQuerySecret := corev1.Secret{}
 		err := r.Get(
 			ctx,
-			client.ObjectKey{Namespace: cluster.Namespace, Name: pooler.GetAuthQuerySecretName()},
+			client.ObjectKey{Namespace: cluster.Namespace, Name: secretName},
 			&authQuerySecret,
 		)
 		if apierrs.IsNotFound(err) {
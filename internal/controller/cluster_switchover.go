@@ -0,0 +1,317 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// plannedSwitchoverRecheckInterval is how often the operator re-evaluates
+// the preconditions of a switchover whose window is open
+const plannedSwitchoverRecheckInterval = 10 * time.Second
+
+// plannedSwitchoverResult is the outcome of the evaluation of the
+// Switchover objects referring to a cluster
+type plannedSwitchoverResult struct {
+	// started is true when the operator requested the promotion of
+	// a new primary, and the reconciliation loop should be stopped
+	started bool
+
+	// recheckAfter is the time after which the pending switchovers
+	// should be evaluated again, zero if there's nothing to wait for
+	recheckAfter time.Duration
+}
+
+// reconcilePlannedSwitchovers drives the Switchover objects referring to
+// the cluster: running switchovers are completed once the promotion is
+// done, and the oldest pending one is started when its time window is
+// open and its preconditions are met
+func (r *ClusterReconciler) reconcilePlannedSwitchovers(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) (plannedSwitchoverResult, error) {
+	var switchovers apiv1.SwitchoverList
+	if err := r.List(ctx, &switchovers, client.InNamespace(cluster.Namespace)); err != nil {
+		return plannedSwitchoverResult{}, fmt.Errorf("while listing switchovers: %w", err)
+	}
+	switchovers.SortByCreationTimeAndName()
+
+	var pending *apiv1.Switchover
+	for idx := range switchovers.Items {
+		switchover := &switchovers.Items[idx]
+		if switchover.Spec.Cluster.Name != cluster.Name || switchover.Status.IsDone() {
+			continue
+		}
+
+		if switchover.Status.Phase == apiv1.SwitchoverPhaseRunning {
+			if err := r.completePlannedSwitchover(ctx, cluster, switchover); err != nil {
+				return plannedSwitchoverResult{}, err
+			}
+			continue
+		}
+
+		if pending == nil {
+			pending = switchover
+		}
+	}
+
+	if pending == nil {
+		return plannedSwitchoverResult{}, nil
+	}
+
+	return r.startPlannedSwitchover(ctx, cluster, instancesStatus, pending)
+}
+
+// completePlannedSwitchover records the outcome of a running switchover.
+// It is called when no switchover or failover is in progress, so the
+// current primary is the result of the promotion
+func (r *ClusterReconciler) completePlannedSwitchover(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	switchover *apiv1.Switchover,
+) error {
+	origSwitchover := switchover.DeepCopy()
+	if cluster.Status.CurrentPrimary == switchover.Status.NewPrimary {
+		switchover.Status.SetAsCompleted(cluster.Status.TimelineID)
+		r.Recorder.Eventf(cluster, "Normal", "SwitchoverCompleted",
+			"Switchover %s completed, the new primary is %s",
+			switchover.Name, switchover.Status.NewPrimary)
+	} else {
+		switchover.Status.SetAsFailed(fmt.Errorf(
+			"the primary instance changed to %s instead of %s",
+			cluster.Status.CurrentPrimary, switchover.Status.NewPrimary))
+	}
+
+	if err := r.Status().Patch(ctx, switchover, client.MergeFrom(origSwitchover)); err != nil {
+		return fmt.Errorf("while patching switchover %s status: %w", switchover.Name, err)
+	}
+	return nil
+}
+
+// startPlannedSwitchover starts the passed switchover if its time window
+// is open and its preconditions are met, updating its status otherwise
+func (r *ClusterReconciler) startPlannedSwitchover(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+	switchover *apiv1.Switchover,
+) (plannedSwitchoverResult, error) {
+	contextLogger := log.FromContext(ctx).WithValues("switchover", switchover.Name)
+	origSwitchover := switchover.DeepCopy()
+
+	patchStatus := func() error {
+		if reflect.DeepEqual(origSwitchover.Status, switchover.Status) {
+			return nil
+		}
+		if err := r.Status().Patch(ctx, switchover, client.MergeFrom(origSwitchover)); err != nil {
+			return fmt.Errorf("while patching switchover %s status: %w", switchover.Name, err)
+		}
+		return nil
+	}
+
+	now := time.Now()
+	if switchover.IsWindowExpired(now) {
+		switchover.Status.SetAsFailed(errors.New("the switchover window expired before the switchover could start"))
+		return plannedSwitchoverResult{}, patchStatus()
+	}
+
+	if timeToWait := switchover.TimeToWindowStart(now); timeToWait > 0 {
+		switchover.Status.SetAsPending("Waiting for the switchover window to open")
+		return plannedSwitchoverResult{recheckAfter: timeToWait}, patchStatus()
+	}
+
+	targetPrimary, err := selectPlannedSwitchoverTarget(cluster, instancesStatus, switchover)
+	if err != nil {
+		switchover.Status.SetAsFailed(err)
+		return plannedSwitchoverResult{}, patchStatus()
+	}
+
+	if reason := checkPlannedSwitchoverPreconditions(
+		cluster, instancesStatus, switchover, targetPrimary,
+	); reason != "" {
+		contextLogger.Info("Switchover preconditions not met", "reason", reason)
+		switchover.Status.SetAsPending(reason)
+		return plannedSwitchoverResult{recheckAfter: plannedSwitchoverRecheckInterval}, patchStatus()
+	}
+
+	currentPrimary := cluster.Status.CurrentPrimary
+	switchover.Status.SetAsRunning(currentPrimary, targetPrimary)
+	if err := patchStatus(); err != nil {
+		return plannedSwitchoverResult{}, err
+	}
+
+	reason := fmt.Sprintf("Planned switchover %s requested", switchover.Name)
+	if switchover.Spec.Reason != "" {
+		reason = fmt.Sprintf("%s: %s", reason, switchover.Spec.Reason)
+	}
+	contextLogger.Info("Starting planned switchover",
+		"currentPrimary", currentPrimary,
+		"targetPrimary", targetPrimary,
+		"reason", reason)
+
+	started, err := r.switchPrimary(ctx, cluster, currentPrimary, targetPrimary, apiv1.PhaseSwitchover, reason)
+	if err != nil {
+		// The promotion was not requested, let the next reconciliation loop retry it
+		runningSwitchover := switchover.DeepCopy()
+		switchover.Status = origSwitchover.Status
+		if patchErr := r.Status().Patch(ctx, switchover, client.MergeFrom(runningSwitchover)); patchErr != nil {
+			contextLogger.Error(patchErr, "while restoring the pending switchover status")
+		}
+		return plannedSwitchoverResult{}, err
+	}
+	return plannedSwitchoverResult{started: started}, nil
+}
+
+// selectPlannedSwitchoverTarget returns the name of the instance to be
+// promoted, validating the requested one against the instances reported
+// in the cluster status. An error means the switchover cannot be executed
+func selectPlannedSwitchoverTarget(
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+	switchover *apiv1.Switchover,
+) (string, error) {
+	if switchover.Spec.TargetInstance != "" {
+		reportedState, ok := cluster.Status.InstancesReportedState[apiv1.PodName(switchover.Spec.TargetInstance)]
+		if !ok {
+			return "", fmt.Errorf("instance %s is not part of cluster %s",
+				switchover.Spec.TargetInstance, cluster.Name)
+		}
+		if reportedState.IsPrimary || switchover.Spec.TargetInstance == cluster.Status.CurrentPrimary {
+			return "", fmt.Errorf("instance %s is already the primary", switchover.Spec.TargetInstance)
+		}
+		return switchover.Spec.TargetInstance, nil
+	}
+
+	// The instances status list is sorted with the primary first, and
+	// the replicas in the same order we use for failovers
+	for _, item := range instancesStatus.Items {
+		if item.Pod == nil || item.Pod.Name == cluster.Status.CurrentPrimary || item.Error != nil {
+			continue
+		}
+		if cluster.IsInstanceFenced(item.Pod.Name) {
+			continue
+		}
+		return item.Pod.Name, nil
+	}
+
+	return "", fmt.Errorf("no replica available for promotion in cluster %s", cluster.Name)
+}
+
+// checkPlannedSwitchoverPreconditions verifies that the cluster is in a
+// state where the target instance can be promoted. It returns a message
+// explaining why the switchover must wait, or an empty string if it can
+// be started
+func checkPlannedSwitchoverPreconditions(
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+	switchover *apiv1.Switchover,
+	targetPrimary string,
+) string {
+	// A cluster waiting for a maintenance window is otherwise healthy
+	if cluster.Status.Phase != apiv1.PhaseHealthy &&
+		cluster.Status.Phase != apiv1.PhaseWaitingForUser &&
+		cluster.Status.Phase != apiv1.PhaseWaitingForMaintenanceWindow {
+		return fmt.Sprintf("Waiting for the cluster to be healthy, current phase: %s", cluster.Status.Phase)
+	}
+
+	if cluster.IsInstanceFenced(targetPrimary) {
+		return fmt.Sprintf("Instance %s is fenced", targetPrimary)
+	}
+
+	var primaryStatus, targetStatus *postgres.PostgresqlStatus
+	for idx := range instancesStatus.Items {
+		item := &instancesStatus.Items[idx]
+		if item.Pod == nil {
+			continue
+		}
+		switch item.Pod.Name {
+		case cluster.Status.CurrentPrimary:
+			primaryStatus = item
+		case targetPrimary:
+			targetStatus = item
+		}
+	}
+
+	if targetStatus == nil || targetStatus.Error != nil || !targetStatus.IsPodReady {
+		return fmt.Sprintf("Instance %s is not ready", targetPrimary)
+	}
+
+	// Before promoting a replica, the instance manager will wait for the WAL receiver
+	// process to be down. This protection can work only when the streaming connection
+	// is active, as we do in the rolling update process.
+	if !targetStatus.IsWalReceiverActive {
+		return fmt.Sprintf("Instance %s is not connected via streaming replication", targetPrimary)
+	}
+
+	if switchover.Spec.MaxLagBytes == nil {
+		return ""
+	}
+
+	if primaryStatus == nil || primaryStatus.Error != nil {
+		return "Cannot compute the replication lag without the status of the current primary"
+	}
+
+	lag, err := getReplayLagBytes(primaryStatus, targetStatus)
+	if err != nil {
+		return fmt.Sprintf("Cannot compute the replication lag of instance %s: %s", targetPrimary, err.Error())
+	}
+	if lag > *switchover.Spec.MaxLagBytes {
+		return fmt.Sprintf("Instance %s is lagging %d bytes behind the primary, the maximum allowed is %d",
+			targetPrimary, lag, *switchover.Spec.MaxLagBytes)
+	}
+
+	return ""
+}
+
+// getReplayLagBytes returns how many bytes of WAL the target instance has
+// still to replay to reach the position of the primary
+func getReplayLagBytes(primaryStatus, targetStatus *postgres.PostgresqlStatus) (int64, error) {
+	// In a replica cluster the designated primary is a standby too,
+	// so its position is the replayed one
+	primaryLSN := primaryStatus.CurrentLsn
+	if !primaryStatus.IsPrimary {
+		primaryLSN = primaryStatus.ReplayLsn
+	}
+
+	primaryPosition, err := primaryLSN.Parse()
+	if err != nil {
+		return 0, err
+	}
+	targetPosition, err := targetStatus.ReplayLsn.Parse()
+	if err != nil {
+		return 0, err
+	}
+
+	if targetPosition >= primaryPosition {
+		return 0, nil
+	}
+	return int64(primaryPosition - targetPosition), nil //nolint:gosec
+}
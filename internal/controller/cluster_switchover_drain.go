@@ -0,0 +1,283 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources/status"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// switchoverDrainInterval is how often the active transactions
+// on the primary are checked while draining the connections
+const switchoverDrainInterval = 1 * time.Second
+
+// startSwitchoverDrain pauses the Poolers pointing to the cluster, when
+// requested, and records that the connections need to be drained before
+// promoting the target primary
+func (r *ClusterReconciler) startSwitchoverDrain(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	targetPrimaryName string,
+) error {
+	contextLogger := log.FromContext(ctx).WithValues("targetPrimary", targetPrimaryName)
+
+	var pausedPoolers []string
+	if cluster.Spec.SwitchoverDrain.ShouldPausePoolers() {
+		var err error
+		if pausedPoolers, err = r.pausePoolersForSwitchover(ctx, cluster, targetPrimaryName); err != nil {
+			return err
+		}
+	}
+
+	contextLogger.Info("Draining the connections before the switchover",
+		"pausedPoolers", pausedPoolers,
+		"timeout", cluster.Spec.SwitchoverDrain.GetTimeout())
+	r.Recorder.Eventf(cluster, "Normal", "SwitchoverDrain",
+		"Draining the connections before promoting %s, paused poolers: %v",
+		targetPrimaryName, pausedPoolers)
+
+	return status.PatchWithOptimisticLock(
+		ctx,
+		r.Client,
+		cluster,
+		status.SetSwitchoverDrainStatus(&apiv1.SwitchoverDrainStatus{
+			TargetPrimary: targetPrimaryName,
+			StartedAt:     metav1.Now(),
+			PausedPoolers: pausedPoolers,
+		}),
+	)
+}
+
+// reconcileSwitchoverDrain drives a switchover started with connection
+// draining enabled: it waits for the active transactions on the primary
+// to complete (or for the drain timeout to expire), requests the
+// promotion of the target primary and resumes the Poolers once the
+// new primary is in place. A non-nil result means the reconciliation
+// loop should be stopped while the connections are being drained
+func (r *ClusterReconciler) reconcileSwitchoverDrain(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) (*ctrl.Result, error) {
+	drain := cluster.Status.SwitchoverDrain
+	if drain == nil {
+		return nil, nil
+	}
+
+	contextLogger := log.FromContext(ctx).WithValues("targetPrimary", drain.TargetPrimary)
+
+	// The switchover has already been requested to the instances
+	if drain.SwitchoverRequestedAt != nil || cluster.Status.TargetPrimary == drain.TargetPrimary {
+		switch {
+		case cluster.Status.CurrentPrimary == drain.TargetPrimary &&
+			cluster.Status.TargetPrimary == drain.TargetPrimary:
+			r.Recorder.Eventf(cluster, "Normal", "SwitchoverDrainCompleted",
+				"Switchover to %s completed, resuming the poolers", drain.TargetPrimary)
+			return nil, r.endSwitchoverDrain(ctx, cluster)
+
+		case cluster.Status.TargetPrimary != drain.TargetPrimary:
+			r.Recorder.Eventf(cluster, "Warning", "SwitchoverDrainAborted",
+				"The primary instance changed to %s instead of %s, resuming the poolers",
+				cluster.Status.TargetPrimary, drain.TargetPrimary)
+			return nil, r.endSwitchoverDrain(ctx, cluster)
+
+		case drain.SwitchoverRequestedAt == nil:
+			// The promotion was requested, but we didn't manage to
+			// record it in the drain status
+			now := metav1.Now()
+			updatedDrain := drain.DeepCopy()
+			updatedDrain.SwitchoverRequestedAt = &now
+			return nil, status.PatchWithOptimisticLock(
+				ctx, r.Client, cluster, status.SetSwitchoverDrainStatus(updatedDrain))
+		}
+
+		// The switchover is in progress, the usual reconciliation
+		// loop will wait for the new primary to be promoted
+		return nil, nil
+	}
+
+	// A failover changed the primary while we were draining the connections
+	if cluster.Status.TargetPrimary != cluster.Status.CurrentPrimary {
+		r.Recorder.Eventf(cluster, "Warning", "SwitchoverDrainAborted",
+			"The primary instance is changing to %s, aborting the switchover to %s",
+			cluster.Status.TargetPrimary, drain.TargetPrimary)
+		return nil, r.endSwitchoverDrain(ctx, cluster)
+	}
+
+	if !isSwitchoverDrainTargetReady(instancesStatus, drain.TargetPrimary) {
+		r.Recorder.Eventf(cluster, "Warning", "SwitchoverDrainAborted",
+			"The target primary %s is not ready anymore, aborting the switchover",
+			drain.TargetPrimary)
+		if err := r.endSwitchoverDrain(ctx, cluster); err != nil {
+			return nil, err
+		}
+		return nil, r.RegisterPhase(ctx, cluster, apiv1.PhaseHealthy, "")
+	}
+
+	activeConnections, reporting := getPrimaryActiveConnections(instancesStatus, cluster.Status.CurrentPrimary)
+	elapsed := time.Since(drain.StartedAt.Time)
+	timeout := cluster.Spec.SwitchoverDrain.GetTimeout()
+	if (!reporting || activeConnections > 0) && elapsed < timeout {
+		contextLogger.Info("Waiting for the active transactions on the primary to complete",
+			"activeConnections", activeConnections,
+			"elapsed", elapsed,
+			"timeout", timeout)
+		return &ctrl.Result{RequeueAfter: switchoverDrainInterval}, nil
+	}
+
+	if activeConnections > 0 || !reporting {
+		contextLogger.Warning("Switchover drain timeout expired, proceeding with the switchover",
+			"activeConnections", activeConnections,
+			"timeout", timeout)
+	}
+
+	if err := r.setPrimaryInstance(ctx, cluster, drain.TargetPrimary); err != nil {
+		return nil, err
+	}
+
+	now := metav1.Now()
+	updatedDrain := drain.DeepCopy()
+	updatedDrain.SwitchoverRequestedAt = &now
+	if err := status.PatchWithOptimisticLock(
+		ctx, r.Client, cluster, status.SetSwitchoverDrainStatus(updatedDrain),
+	); err != nil {
+		return nil, err
+	}
+
+	contextLogger.Info("Connections drained, waiting for the new primary to notice the promotion request")
+	return &ctrl.Result{RequeueAfter: switchoverDrainInterval}, nil
+}
+
+// endSwitchoverDrain resumes the Poolers paused for the switchover
+// and removes the connection draining status
+func (r *ClusterReconciler) endSwitchoverDrain(ctx context.Context, cluster *apiv1.Cluster) error {
+	if err := r.resumePoolersAfterSwitchover(ctx, cluster); err != nil {
+		return err
+	}
+
+	return status.PatchWithOptimisticLock(ctx, r.Client, cluster, status.SetSwitchoverDrainStatus(nil))
+}
+
+// pausePoolersForSwitchover marks every Pooler pointing to the cluster
+// as paused, returning the names of the Poolers that have been paused
+func (r *ClusterReconciler) pausePoolersForSwitchover(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	targetPrimaryName string,
+) ([]string, error) {
+	poolers, err := r.getClusterPoolers(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	pausedPoolers := make([]string, 0, len(poolers.Items))
+	for idx := range poolers.Items {
+		pooler := &poolers.Items[idx]
+		origPooler := pooler.DeepCopy()
+		if pooler.Annotations == nil {
+			pooler.Annotations = make(map[string]string)
+		}
+		pooler.Annotations[utils.PoolerPausedForSwitchoverAnnotationName] = targetPrimaryName
+		if err := r.Patch(ctx, pooler, client.MergeFrom(origPooler)); err != nil {
+			return nil, fmt.Errorf("while pausing pooler %s: %w", pooler.Name, err)
+		}
+		pausedPoolers = append(pausedPoolers, pooler.Name)
+	}
+
+	return pausedPoolers, nil
+}
+
+// resumePoolersAfterSwitchover resumes every Pooler pointing to the
+// cluster that has been paused by the operator for a switchover
+func (r *ClusterReconciler) resumePoolersAfterSwitchover(ctx context.Context, cluster *apiv1.Cluster) error {
+	poolers, err := r.getClusterPoolers(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	for idx := range poolers.Items {
+		pooler := &poolers.Items[idx]
+		if _, ok := pooler.Annotations[utils.PoolerPausedForSwitchoverAnnotationName]; !ok {
+			continue
+		}
+
+		origPooler := pooler.DeepCopy()
+		delete(pooler.Annotations, utils.PoolerPausedForSwitchoverAnnotationName)
+		if err := r.Patch(ctx, pooler, client.MergeFrom(origPooler)); err != nil {
+			return fmt.Errorf("while resuming pooler %s: %w", pooler.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// getClusterPoolers returns the Poolers pointing to the cluster
+func (r *ClusterReconciler) getClusterPoolers(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (*apiv1.PoolerList, error) {
+	var poolers apiv1.PoolerList
+	if err := r.List(ctx, &poolers,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingFields{poolerClusterKey: cluster.Name},
+	); err != nil {
+		return nil, fmt.Errorf("while getting poolers for cluster %s: %w", cluster.Name, err)
+	}
+
+	return &poolers, nil
+}
+
+// isSwitchoverDrainTargetReady checks if the instance
+// that will be promoted is still ready and reporting its status
+func isSwitchoverDrainTargetReady(instancesStatus postgres.PostgresqlStatusList, targetPrimary string) bool {
+	for _, item := range instancesStatus.Items {
+		if item.Pod != nil && item.Pod.Name == targetPrimary {
+			return item.IsPodReady && item.Error == nil
+		}
+	}
+
+	return false
+}
+
+// getPrimaryActiveConnections returns the number of active client
+// connections on the primary, and whether the primary reported it
+func getPrimaryActiveConnections(
+	instancesStatus postgres.PostgresqlStatusList,
+	primaryName string,
+) (int, bool) {
+	for _, item := range instancesStatus.Items {
+		if item.Pod != nil && item.Pod.Name == primaryName {
+			return item.ActiveConnections, item.Error == nil
+		}
+	}
+
+	return 0, false
+}
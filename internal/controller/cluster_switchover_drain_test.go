@@ -0,0 +1,183 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("switchover with connection draining", func() {
+	var (
+		env       *testingEnvironment
+		cluster   *apiv1.Cluster
+		pooler    *apiv1.Pooler
+		primary   string
+		candidate string
+	)
+
+	BeforeEach(func(ctx context.Context) {
+		env = buildTestEnvironment()
+		namespace := newFakeNamespace(env.client)
+		cluster = newFakeCNPGCluster(env.client, namespace, func(cluster *apiv1.Cluster) {
+			cluster.Spec.SwitchoverDrain = &apiv1.SwitchoverDrainConfiguration{
+				Timeout: &metav1.Duration{Duration: time.Minute},
+			}
+		})
+		primary = cluster.Name + "-1"
+		candidate = cluster.Name + "-2"
+		cluster.Status.CurrentPrimary = primary
+		cluster.Status.TargetPrimary = primary
+		Expect(env.client.Status().Update(ctx, cluster)).To(Succeed())
+		pooler = newFakePooler(env.client, cluster)
+	})
+
+	newInstancesStatus := func(activeConnections int) postgres.PostgresqlStatusList {
+		return postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:               &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: primary}},
+					IsPrimary:         true,
+					IsPodReady:        true,
+					ActiveConnections: activeConnections,
+				},
+				{
+					Pod:        &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: candidate}},
+					IsPodReady: true,
+				},
+			},
+		}
+	}
+
+	getPooler := func(ctx context.Context) *apiv1.Pooler {
+		var updatedPooler apiv1.Pooler
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(pooler), &updatedPooler)).To(Succeed())
+		return &updatedPooler
+	}
+
+	startDrain := func(ctx context.Context) {
+		started, err := env.clusterReconciler.switchPrimary(
+			ctx, cluster, primary, candidate, apiv1.PhaseSwitchover, "test")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(started).To(BeTrue())
+	}
+
+	It("pauses the poolers instead of promoting the target primary", func(ctx context.Context) {
+		startDrain(ctx)
+
+		Expect(cluster.Status.TargetPrimary).To(Equal(primary))
+		Expect(cluster.Status.SwitchoverDrain).ToNot(BeNil())
+		Expect(cluster.Status.SwitchoverDrain.TargetPrimary).To(Equal(candidate))
+		Expect(cluster.Status.SwitchoverDrain.PausedPoolers).To(ConsistOf(pooler.Name))
+		Expect(getPooler(ctx).Annotations).To(
+			HaveKeyWithValue(utils.PoolerPausedForSwitchoverAnnotationName, candidate))
+		Expect(getPooler(ctx).ShouldBePaused()).To(BeTrue())
+	})
+
+	It("doesn't pause the poolers when not requested", func(ctx context.Context) {
+		cluster.Spec.SwitchoverDrain.PausePoolers = new(bool)
+		startDrain(ctx)
+
+		Expect(cluster.Status.SwitchoverDrain).ToNot(BeNil())
+		Expect(cluster.Status.SwitchoverDrain.PausedPoolers).To(BeEmpty())
+		Expect(getPooler(ctx).ShouldBePaused()).To(BeFalse())
+	})
+
+	It("waits for the active transactions to complete", func(ctx context.Context) {
+		startDrain(ctx)
+
+		result, err := env.clusterReconciler.reconcileSwitchoverDrain(ctx, cluster, newInstancesStatus(3))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).ToNot(BeNil())
+		Expect(result.RequeueAfter).To(Equal(switchoverDrainInterval))
+		Expect(cluster.Status.TargetPrimary).To(Equal(primary))
+
+		result, err = env.clusterReconciler.reconcileSwitchoverDrain(ctx, cluster, newInstancesStatus(0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).ToNot(BeNil())
+		Expect(cluster.Status.TargetPrimary).To(Equal(candidate))
+		Expect(cluster.Status.SwitchoverDrain.SwitchoverRequestedAt).ToNot(BeNil())
+		Expect(getPooler(ctx).ShouldBePaused()).To(BeTrue())
+	})
+
+	It("proceeds with the switchover when the timeout expires", func(ctx context.Context) {
+		startDrain(ctx)
+		cluster.Status.SwitchoverDrain.StartedAt = metav1.NewTime(time.Now().Add(-2 * time.Minute))
+
+		result, err := env.clusterReconciler.reconcileSwitchoverDrain(ctx, cluster, newInstancesStatus(3))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).ToNot(BeNil())
+		Expect(cluster.Status.TargetPrimary).To(Equal(candidate))
+	})
+
+	It("resumes the poolers once the new primary is in place", func(ctx context.Context) {
+		startDrain(ctx)
+		_, err := env.clusterReconciler.reconcileSwitchoverDrain(ctx, cluster, newInstancesStatus(0))
+		Expect(err).ToNot(HaveOccurred())
+
+		// The switchover is still in progress
+		result, err := env.clusterReconciler.reconcileSwitchoverDrain(ctx, cluster, newInstancesStatus(0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(BeNil())
+		Expect(getPooler(ctx).ShouldBePaused()).To(BeTrue())
+
+		cluster.Status.CurrentPrimary = candidate
+		result, err = env.clusterReconciler.reconcileSwitchoverDrain(ctx, cluster, newInstancesStatus(0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(BeNil())
+		Expect(cluster.Status.SwitchoverDrain).To(BeNil())
+		Expect(getPooler(ctx).Annotations).ToNot(HaveKey(utils.PoolerPausedForSwitchoverAnnotationName))
+	})
+
+	It("aborts the switchover when the target primary is not ready", func(ctx context.Context) {
+		startDrain(ctx)
+
+		instancesStatus := newInstancesStatus(3)
+		instancesStatus.Items[1].IsPodReady = false
+		result, err := env.clusterReconciler.reconcileSwitchoverDrain(ctx, cluster, instancesStatus)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(BeNil())
+		Expect(cluster.Status.TargetPrimary).To(Equal(primary))
+		Expect(cluster.Status.SwitchoverDrain).To(BeNil())
+		Expect(getPooler(ctx).ShouldBePaused()).To(BeFalse())
+	})
+
+	It("aborts the switchover when a failover is in progress", func(ctx context.Context) {
+		startDrain(ctx)
+
+		cluster.Status.TargetPrimary = cluster.Name + "-3"
+		result, err := env.clusterReconciler.reconcileSwitchoverDrain(ctx, cluster, newInstancesStatus(3))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(BeNil())
+		Expect(cluster.Status.SwitchoverDrain).To(BeNil())
+		Expect(getPooler(ctx).ShouldBePaused()).To(BeFalse())
+	})
+})
@@ -0,0 +1,262 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func newSwitchoverTestStatus(name string, isPrimary bool, lsn types.LSN) postgres.PostgresqlStatus {
+	return postgres.PostgresqlStatus{
+		Pod:                 &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}},
+		IsPrimary:           isPrimary,
+		IsPodReady:          true,
+		IsWalReceiverActive: !isPrimary,
+		CurrentLsn:          lsn,
+		ReplayLsn:           lsn,
+	}
+}
+
+var _ = Describe("planned switchover target selection", func() {
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+		Status: apiv1.ClusterStatus{
+			CurrentPrimary: "cluster-example-1",
+			TargetPrimary:  "cluster-example-1",
+			InstancesReportedState: map[apiv1.PodName]apiv1.InstanceReportedState{
+				"cluster-example-1": {IsPrimary: true},
+				"cluster-example-2": {},
+				"cluster-example-3": {},
+			},
+		},
+	}
+	statusList := postgres.PostgresqlStatusList{
+		Items: []postgres.PostgresqlStatus{
+			newSwitchoverTestStatus("cluster-example-1", true, "0/5000000"),
+			newSwitchoverTestStatus("cluster-example-3", false, "0/5000000"),
+			newSwitchoverTestStatus("cluster-example-2", false, "0/4000000"),
+		},
+	}
+
+	It("selects the most advanced replica when no target is specified", func() {
+		target, err := selectPlannedSwitchoverTarget(cluster, statusList, &apiv1.Switchover{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(target).To(Equal("cluster-example-3"))
+	})
+
+	It("accepts a replica reported in the cluster status", func() {
+		switchover := &apiv1.Switchover{Spec: apiv1.SwitchoverSpec{TargetInstance: "cluster-example-2"}}
+		target, err := selectPlannedSwitchoverTarget(cluster, statusList, switchover)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(target).To(Equal("cluster-example-2"))
+	})
+
+	It("rejects an instance that is not part of the cluster", func() {
+		switchover := &apiv1.Switchover{Spec: apiv1.SwitchoverSpec{TargetInstance: "cluster-example-9"}}
+		_, err := selectPlannedSwitchoverTarget(cluster, statusList, switchover)
+		Expect(err).To(MatchError(ContainSubstring("is not part of cluster")))
+	})
+
+	It("rejects the current primary", func() {
+		switchover := &apiv1.Switchover{Spec: apiv1.SwitchoverSpec{TargetInstance: "cluster-example-1"}}
+		_, err := selectPlannedSwitchoverTarget(cluster, statusList, switchover)
+		Expect(err).To(MatchError(ContainSubstring("is already the primary")))
+	})
+})
+
+var _ = Describe("planned switchover preconditions", func() {
+	var cluster *apiv1.Cluster
+	var statusList postgres.PostgresqlStatusList
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Status: apiv1.ClusterStatus{
+				Phase:          apiv1.PhaseHealthy,
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+		statusList = postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newSwitchoverTestStatus("cluster-example-1", true, "0/5000000"),
+				newSwitchoverTestStatus("cluster-example-2", false, "0/4000000"),
+			},
+		}
+	})
+
+	It("are met by a healthy streaming replica", func() {
+		Expect(checkPlannedSwitchoverPreconditions(
+			cluster, statusList, &apiv1.Switchover{}, "cluster-example-2")).To(BeEmpty())
+	})
+
+	It("wait for the cluster to be healthy", func() {
+		cluster.Status.Phase = apiv1.PhaseUpgrade
+		Expect(checkPlannedSwitchoverPreconditions(
+			cluster, statusList, &apiv1.Switchover{}, "cluster-example-2")).
+			To(ContainSubstring("Waiting for the cluster to be healthy"))
+	})
+
+	It("can be met while waiting for a supervised switchover", func() {
+		cluster.Status.Phase = apiv1.PhaseWaitingForUser
+		Expect(checkPlannedSwitchoverPreconditions(
+			cluster, statusList, &apiv1.Switchover{}, "cluster-example-2")).To(BeEmpty())
+	})
+
+	It("can be met while waiting for the next maintenance window", func() {
+		cluster.Status.Phase = apiv1.PhaseWaitingForMaintenanceWindow
+		Expect(checkPlannedSwitchoverPreconditions(
+			cluster, statusList, &apiv1.Switchover{}, "cluster-example-2")).To(BeEmpty())
+	})
+
+	It("wait for the target to be connected via streaming replication", func() {
+		statusList.Items[1].IsWalReceiverActive = false
+		Expect(checkPlannedSwitchoverPreconditions(
+			cluster, statusList, &apiv1.Switchover{}, "cluster-example-2")).
+			To(ContainSubstring("is not connected via streaming replication"))
+	})
+
+	It("wait for the target to catch up with the primary", func() {
+		switchover := &apiv1.Switchover{Spec: apiv1.SwitchoverSpec{MaxLagBytes: ptr.To(int64(1024))}}
+		Expect(checkPlannedSwitchoverPreconditions(
+			cluster, statusList, switchover, "cluster-example-2")).
+			To(ContainSubstring("is lagging 16777216 bytes behind the primary"))
+
+		switchover.Spec.MaxLagBytes = ptr.To(int64(16777216))
+		Expect(checkPlannedSwitchoverPreconditions(
+			cluster, statusList, switchover, "cluster-example-2")).To(BeEmpty())
+	})
+})
+
+var _ = Describe("planned switchover reconciliation", func() {
+	var env *testingEnvironment
+	var cluster *apiv1.Cluster
+	var statusList postgres.PostgresqlStatusList
+
+	BeforeEach(func() {
+		env = buildTestEnvironment()
+		namespace := newFakeNamespace(env.client)
+		cluster = newFakeCNPGCluster(env.client, namespace, func(cluster *apiv1.Cluster) {
+			cluster.Status.Phase = apiv1.PhaseHealthy
+			cluster.Status.CurrentPrimary = cluster.Name + "-1"
+			cluster.Status.TargetPrimary = cluster.Name + "-1"
+			cluster.Status.TimelineID = 1
+			cluster.Status.InstancesReportedState = map[apiv1.PodName]apiv1.InstanceReportedState{
+				apiv1.PodName(cluster.Name + "-1"): {IsPrimary: true, TimeLineID: 1},
+				apiv1.PodName(cluster.Name + "-2"): {TimeLineID: 1},
+			}
+		})
+		statusList = postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newSwitchoverTestStatus(cluster.Name+"-1", true, "0/5000000"),
+				newSwitchoverTestStatus(cluster.Name+"-2", false, "0/5000000"),
+			},
+		}
+	})
+
+	createSwitchover := func(ctx SpecContext, spec apiv1.SwitchoverSpec) *apiv1.Switchover {
+		spec.Cluster = corev1.LocalObjectReference{Name: cluster.Name}
+		switchover := &apiv1.Switchover{
+			ObjectMeta: metav1.ObjectMeta{Name: "switchover", Namespace: cluster.Namespace},
+			Spec:       spec,
+		}
+		Expect(env.client.Create(ctx, switchover)).To(Succeed())
+		return switchover
+	}
+
+	getSwitchover := func(ctx SpecContext, switchover *apiv1.Switchover) *apiv1.Switchover {
+		var result apiv1.Switchover
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(switchover), &result)).To(Succeed())
+		return &result
+	}
+
+	It("starts an open switchover and completes it once the new primary is in charge", func(ctx SpecContext) {
+		switchover := createSwitchover(ctx, apiv1.SwitchoverSpec{})
+
+		result, err := env.clusterReconciler.reconcilePlannedSwitchovers(ctx, cluster, statusList)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.started).To(BeTrue())
+		Expect(cluster.Status.TargetPrimary).To(Equal(cluster.Name + "-2"))
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseSwitchover))
+
+		running := getSwitchover(ctx, switchover)
+		Expect(running.Status.Phase).To(Equal(apiv1.SwitchoverPhaseRunning))
+		Expect(running.Status.OldPrimary).To(Equal(cluster.Name + "-1"))
+		Expect(running.Status.NewPrimary).To(Equal(cluster.Name + "-2"))
+		Expect(running.Status.StartedAt).ToNot(BeNil())
+
+		cluster.Status.CurrentPrimary = cluster.Name + "-2"
+		cluster.Status.TimelineID = 2
+		result, err = env.clusterReconciler.reconcilePlannedSwitchovers(ctx, cluster, statusList)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.started).To(BeFalse())
+
+		completed := getSwitchover(ctx, switchover)
+		Expect(completed.Status.Phase).To(Equal(apiv1.SwitchoverPhaseCompleted))
+		Expect(completed.Status.TimeLineID).To(Equal(2))
+		Expect(completed.Status.StoppedAt).ToNot(BeNil())
+	})
+
+	It("waits for the switchover window to open", func(ctx SpecContext) {
+		switchover := createSwitchover(ctx, apiv1.SwitchoverSpec{
+			NotBefore: ptr.To(metav1.NewTime(time.Now().Add(time.Hour))),
+		})
+
+		result, err := env.clusterReconciler.reconcilePlannedSwitchovers(ctx, cluster, statusList)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.started).To(BeFalse())
+		Expect(result.recheckAfter).To(BeNumerically(">", 59*time.Minute))
+		Expect(getSwitchover(ctx, switchover).Status.Phase).To(Equal(apiv1.SwitchoverPhasePending))
+		Expect(cluster.Status.TargetPrimary).To(Equal(cluster.Name + "-1"))
+	})
+
+	It("fails when the switchover window expired", func(ctx SpecContext) {
+		switchover := createSwitchover(ctx, apiv1.SwitchoverSpec{
+			NotAfter: ptr.To(metav1.NewTime(time.Now().Add(-time.Minute))),
+		})
+
+		result, err := env.clusterReconciler.reconcilePlannedSwitchovers(ctx, cluster, statusList)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.started).To(BeFalse())
+		Expect(getSwitchover(ctx, switchover).Status.Phase).To(Equal(apiv1.SwitchoverPhaseFailed))
+	})
+
+	It("fails when the target instance is unknown", func(ctx SpecContext) {
+		switchover := createSwitchover(ctx, apiv1.SwitchoverSpec{TargetInstance: "unknown"})
+
+		_, err := env.clusterReconciler.reconcilePlannedSwitchovers(ctx, cluster, statusList)
+		Expect(err).ToNot(HaveOccurred())
+		failed := getSwitchover(ctx, switchover)
+		Expect(failed.Status.Phase).To(Equal(apiv1.SwitchoverPhaseFailed))
+		Expect(failed.Status.Message).To(ContainSubstring("is not part of cluster"))
+	})
+})
@@ -25,10 +25,13 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -36,6 +39,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver/client/remote"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources/status"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
@@ -49,8 +53,25 @@ var errLogShippingReplicaElected = errors.New("log shipping replica elected as a
 // of the operator configuration
 var errRolloutDelayed = errors.New("pod rollout delayed")
 
+// errMaintenanceWindowClosed is raised when a pod rollout needs to wait
+// for the next maintenance window of the cluster
+var errMaintenanceWindowClosed = errors.New("pod rollout waiting for the next maintenance window")
+
 type rolloutReason = string
 
+// ensureMaintenanceWindowIsOpen returns errMaintenanceWindowClosed when the
+// rollout of an instance needs to wait for the next maintenance window
+func ensureMaintenanceWindowIsOpen(cluster *apiv1.Cluster, isPrimary bool) error {
+	mustWait, _, err := cluster.ShouldWaitForMaintenanceWindow(isPrimary, time.Now())
+	if err != nil {
+		return err
+	}
+	if mustWait {
+		return errMaintenanceWindowClosed
+	}
+	return nil
+}
+
 func (r *ClusterReconciler) rolloutRequiredInstances(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -79,6 +100,10 @@ func (r *ClusterReconciler) rolloutRequiredInstances(
 			continue
 		}
 
+		if err := ensureMaintenanceWindowIsOpen(cluster, false); err != nil {
+			return false, err
+		}
+
 		managerResult := r.rolloutManager.CoordinateRollout(client.ObjectKeyFromObject(cluster), postgresqlStatus.Pod.Name)
 		if !managerResult.RolloutAllowed {
 			r.Recorder.Eventf(
@@ -139,6 +164,10 @@ func (r *ClusterReconciler) rolloutRequiredInstances(
 		return true, nil
 	}
 
+	if err := ensureMaintenanceWindowIsOpen(cluster, true); err != nil {
+		return false, err
+	}
+
 	managerResult := r.rolloutManager.CoordinateRollout(
 		client.ObjectKeyFromObject(cluster),
 		primaryPostgresqlStatus.Pod.Name)
@@ -158,18 +187,91 @@ func (r *ClusterReconciler) rolloutRequiredInstances(
 		podRollout.canBeInPlace, podRollout.reason)
 }
 
+// getMajorUpgradeWaitingForMaintenanceWindow returns the requested major
+// version if its upgrade is waiting for the next maintenance window, as
+// the image of the new major version has not been selected yet
+func getMajorUpgradeWaitingForMaintenanceWindow(cluster *apiv1.Cluster) (int, bool) {
+	if cluster.Status.PGDataImageInfo == nil || cluster.Status.Image != cluster.Status.PGDataImageInfo.Image {
+		return 0, false
+	}
+
+	requestedMajor, err := cluster.GetPostgresqlMajorVersion()
+	if err != nil {
+		return 0, false
+	}
+
+	return requestedMajor, requestedMajor > cluster.Status.PGDataImageInfo.MajorVersion
+}
+
+// waitForMaintenanceWindow reports that the update of the cluster, described
+// by the passed reason, is waiting for the next maintenance window, and
+// requeues the reconciliation loop when it opens
+func (r *ClusterReconciler) waitForMaintenanceWindow(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	reason string,
+) (ctrl.Result, error) {
+	_, nextWindowStart, err := cluster.IsInMaintenanceWindow(time.Now())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	message := fmt.Sprintf("%s, but no maintenance window is scheduled", reason)
+	if !nextWindowStart.IsZero() {
+		message = fmt.Sprintf("%s, waiting for the next maintenance window at %s",
+			reason, nextWindowStart.Format(time.RFC3339))
+	}
+
+	if err := status.PatchWithOptimisticLock(
+		ctx,
+		r.Client,
+		cluster,
+		status.SetPhase(apiv1.PhaseWaitingForMaintenanceWindow, message),
+		status.SetClusterReadyCondition,
+		status.SetMaintenancePendingCondition(message),
+	); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if nextWindowStart.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: time.Until(nextWindowStart)}, nil
+}
+
+// clearMaintenancePending removes the condition reporting an update waiting
+// for the next maintenance window, if present
+func (r *ClusterReconciler) clearMaintenancePending(ctx context.Context, cluster *apiv1.Cluster) error {
+	if meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionMaintenancePending)) == nil {
+		return nil
+	}
+
+	return status.PatchWithOptimisticLock(ctx, r.Client, cluster, status.RemoveMaintenancePendingCondition)
+}
+
+// switchPrimary requests the promotion of targetPrimaryName, registering
+// the passed phase in the cluster status
 func (r *ClusterReconciler) switchPrimary(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
 	currentPrimaryName string,
 	targetPrimaryName string,
+	phase string,
 	reason rolloutReason,
 ) (bool, error) {
 	r.Recorder.Eventf(cluster, "Normal", "Switchover",
-		"Initiating switchover to %s to upgrade %s", targetPrimaryName, currentPrimaryName)
-	if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseUpgrade, reason); err != nil {
+		"Initiating switchover from %s to %s: %s", currentPrimaryName, targetPrimaryName, reason)
+	if err := r.RegisterPhase(ctx, cluster, phase, reason); err != nil {
 		return false, err
 	}
+	if cluster.IsSwitchoverDrainEnabled() {
+		// The target primary will be promoted once the
+		// connections to the current primary have been drained
+		if err := r.startSwitchoverDrain(ctx, cluster, targetPrimaryName); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
 	if err := r.setPrimaryInstance(ctx, cluster, targetPrimaryName); err != nil {
 		return false, err
 	}
@@ -261,7 +363,7 @@ func (r *ClusterReconciler) updatePrimaryPod(
 			"targetPrimary", targetInstance.Pod.Name)
 		podList.LogStatus(ctx)
 
-		return r.switchPrimary(ctx, cluster, primaryPod.Name, targetInstance.Pod.Name, reason)
+		return r.switchPrimary(ctx, cluster, primaryPod.Name, targetInstance.Pod.Name, apiv1.PhaseUpgrade, reason)
 	}
 
 	// if there is only one instance in the cluster, we should upgrade it even if it's a primary
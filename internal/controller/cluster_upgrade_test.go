@@ -23,9 +23,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
@@ -1290,3 +1292,62 @@ var _ = Describe("archiverSidecarMissingOnPrimary", func() {
 		Expect(missing).To(BeFalse())
 	})
 })
+
+var _ = Describe("Maintenance windows", func() {
+	It("reports a major version upgrade waiting for the next maintenance window", func(ctx SpecContext) {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				ImageName: "postgres:17.2",
+				MaintenanceWindows: []apiv1.MaintenanceWindow{
+					{
+						Schedule: "0 0 0 1 1 *",
+						Duration: metav1.Duration{Duration: time.Minute},
+					},
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				Phase: apiv1.PhaseHealthy,
+				Image: "postgres:16.2",
+				PGDataImageInfo: &apiv1.ImageInfo{
+					Image:        "postgres:16.2",
+					MajorVersion: 16,
+				},
+			},
+		}
+		r := newFakeReconcilerFor(cluster, nil)
+
+		requestedMajor, isWaiting := getMajorUpgradeWaitingForMaintenanceWindow(cluster)
+		Expect(isWaiting).To(BeTrue())
+		result, err := r.waitForMaintenanceWindow(ctx, cluster,
+			fmt.Sprintf("Upgrade to major version %v requested", requestedMajor))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+
+		var updated apiv1.Cluster
+		Expect(r.Get(ctx, k8client.ObjectKeyFromObject(cluster), &updated)).To(Succeed())
+		Expect(updated.Status.Phase).To(Equal(apiv1.PhaseWaitingForMaintenanceWindow))
+		Expect(updated.Status.PhaseReason).To(HavePrefix(
+			"Upgrade to major version 17 requested, waiting for the next maintenance window at "))
+		Expect(meta.IsStatusConditionTrue(
+			updated.Status.Conditions, string(apiv1.ConditionMaintenancePending))).To(BeTrue())
+	})
+
+	It("doesn't report a major version upgrade once its image has been selected", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{ImageName: "postgres:17.2"},
+			Status: apiv1.ClusterStatus{
+				Image: "postgres:17.2",
+				PGDataImageInfo: &apiv1.ImageInfo{
+					Image:        "postgres:16.2",
+					MajorVersion: 16,
+				},
+			},
+		}
+		_, isWaiting := getMajorUpgradeWaitingForMaintenanceWindow(cluster)
+		Expect(isWaiting).To(BeFalse())
+	})
+})
@@ -0,0 +1,700 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	cnpgTypes "github.com/cloudnative-pg/machinery/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver/client/remote"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// majorMigrationReplicationInterval is how often the replication
+// progress is checked while the target cluster is catching up
+const majorMigrationReplicationInterval = 10 * time.Second
+
+// majorMigrationMaxCutoverLag is the maximum replication lag, in bytes,
+// of every database for the target cluster to be ready for the cutover.
+// It bounds the time the writes are stopped during the cutover, waiting
+// for the target cluster to catch up
+const majorMigrationMaxCutoverLag = 16 * 1024 * 1024
+
+// MajorMigrationReconciler reconciles a MajorMigration object
+type MajorMigrationReconciler struct {
+	client.Client
+
+	Scheme             *runtime.Scheme
+	Recorder           record.EventRecorder
+	OperatorClientCert *tls.Certificate
+
+	instanceStatusClient remote.InstanceClient
+}
+
+// NewMajorMigrationReconciler properly initializes the MajorMigrationReconciler
+func NewMajorMigrationReconciler(
+	mgr manager.Manager,
+	operatorClientCert *tls.Certificate,
+) *MajorMigrationReconciler {
+	return &MajorMigrationReconciler{
+		Client:               mgr.GetClient(),
+		Scheme:               mgr.GetScheme(),
+		Recorder:             mgr.GetEventRecorderFor("cloudnative-pg-majormigration"), //nolint:staticcheck
+		OperatorClientCert:   operatorClientCert,
+		instanceStatusClient: remote.NewClient().Instance(),
+	}
+}
+
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=majormigrations,verbs=get;list;watch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=majormigrations/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=majormigrations/finalizers,verbs=update
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=publications,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=subscriptions,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=poolers,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get
+
+// Reconcile is the main reconciliation loop
+func (r *MajorMigrationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	contextLogger, ctx := log.SetupLogger(ctx)
+
+	var migration apiv1.MajorMigration
+	if err := r.Get(ctx, req.NamespacedName, &migration); err != nil {
+		if apierrs.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if utils.IsReconciliationDisabled(&migration.ObjectMeta) {
+		contextLogger.Warning("Disable reconciliation loop annotation set, skipping the reconciliation.")
+		return ctrl.Result{}, nil
+	}
+
+	if migration.Status.IsDone() {
+		return ctrl.Result{}, nil
+	}
+
+	origMigration := migration.DeepCopy()
+	result, err := r.reconcileSourceCluster(ctx, &migration)
+	if !reflect.DeepEqual(origMigration.Status, migration.Status) {
+		if patchErr := r.Status().Patch(ctx, &migration, client.MergeFrom(origMigration)); patchErr != nil {
+			return ctrl.Result{}, fmt.Errorf("while patching the migration status: %w", patchErr)
+		}
+	}
+
+	return result, err
+}
+
+// reconcileSourceCluster loads the source cluster and the TLS
+// configuration needed to query its instances, then drives the migration
+func (r *MajorMigrationReconciler) reconcileSourceCluster(
+	ctx context.Context,
+	migration *apiv1.MajorMigration,
+) (ctrl.Result, error) {
+	var source apiv1.Cluster
+	if err := r.Get(ctx, types.NamespacedName{
+		Namespace: migration.Namespace,
+		Name:      migration.Spec.SourceCluster.Name,
+	}, &source); err != nil {
+		if apierrs.IsNotFound(err) {
+			return ctrl.Result{}, r.failMigration(ctx, migration,
+				fmt.Errorf("source cluster %s not found", migration.Spec.SourceCluster.Name))
+		}
+		return ctrl.Result{}, err
+	}
+
+	// The operator client certificate is presented to authenticate with the instance manager.
+	ctx, err := certs.NewTLSConfigForContext(ctx, certs.TLSConfigOptions{
+		Client:     r.Client,
+		CASecret:   source.GetServerCASecretObjectKey(),
+		ClientCert: r.OperatorClientCert,
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return r.reconcileMigration(ctx, migration, &source)
+}
+
+// reconcileMigration moves the migration forward, depending on its phase
+func (r *MajorMigrationReconciler) reconcileMigration(
+	ctx context.Context,
+	migration *apiv1.MajorMigration,
+	source *apiv1.Cluster,
+) (ctrl.Result, error) {
+	if migration.Status.Phase == "" {
+		return ctrl.Result{}, r.startMigration(ctx, migration, source)
+	}
+
+	var target apiv1.Cluster
+	if err := r.Get(ctx, types.NamespacedName{
+		Namespace: migration.Namespace,
+		Name:      migration.Spec.TargetCluster.Name,
+	}, &target); err != nil {
+		if apierrs.IsNotFound(err) {
+			return ctrl.Result{}, r.failMigration(ctx, migration,
+				fmt.Errorf("target cluster %s not found", migration.Spec.TargetCluster.Name))
+		}
+		return ctrl.Result{}, err
+	}
+
+	switch migration.Status.Phase {
+	case apiv1.MajorMigrationPhaseCreatingTarget:
+		if target.Status.Phase != apiv1.PhaseHealthy || target.Status.ReadyInstances != target.Spec.Instances {
+			migration.Status.SetPhase(apiv1.MajorMigrationPhaseCreatingTarget,
+				fmt.Sprintf("Waiting for the target cluster to be ready: %s", target.Status.Phase))
+			return ctrl.Result{}, nil
+		}
+
+		r.Recorder.Event(migration, "Normal", "ReplicationStarted",
+			"Target cluster ready, replicating the data of the source cluster")
+		migration.Status.SetPhase(apiv1.MajorMigrationPhaseReplicating, "")
+		return r.reconcileReplication(ctx, migration, source, &target)
+
+	case apiv1.MajorMigrationPhaseReplicating, apiv1.MajorMigrationPhaseReadyForCutover:
+		return r.reconcileReplication(ctx, migration, source, &target)
+
+	case apiv1.MajorMigrationPhaseCuttingOver:
+		return r.reconcileCutover(ctx, migration, source, &target)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// startMigration checks that the source cluster can be migrated
+// and creates the target cluster, importing its schema
+func (r *MajorMigrationReconciler) startMigration(
+	ctx context.Context,
+	migration *apiv1.MajorMigration,
+	source *apiv1.Cluster,
+) error {
+	target := buildMajorMigrationTarget(migration, source)
+
+	sourceMajorVersion, err := source.GetPostgresqlMajorVersion()
+	if err != nil {
+		return r.failMigration(ctx, migration,
+			fmt.Errorf("cannot detect the major version of the source cluster: %w", err))
+	}
+	targetMajorVersion, err := target.GetPostgresqlMajorVersion()
+	if err != nil {
+		return r.failMigration(ctx, migration,
+			fmt.Errorf("cannot detect the major version of the target image: %w", err))
+	}
+
+	if err := checkMajorMigrationPrerequisites(
+		migration, source, sourceMajorVersion, targetMajorVersion,
+	); err != nil {
+		return r.failMigration(ctx, migration, err)
+	}
+
+	var existingTarget apiv1.Cluster
+	err = r.Get(ctx, client.ObjectKeyFromObject(target), &existingTarget)
+	switch {
+	case apierrs.IsNotFound(err):
+		if err := r.Create(ctx, target); err != nil {
+			return fmt.Errorf("while creating the target cluster: %w", err)
+		}
+		r.Recorder.Eventf(migration, "Normal", "TargetCreated",
+			"Created the target cluster %s running PostgreSQL %d", target.Name, targetMajorVersion)
+
+	case err != nil:
+		return err
+
+	case existingTarget.Labels[utils.MajorMigrationLabelName] != migration.Name:
+		return r.failMigration(ctx, migration,
+			fmt.Errorf("target cluster %s already exists", target.Name))
+	}
+
+	migration.Status.SourceMajorVersion = sourceMajorVersion
+	migration.Status.TargetMajorVersion = targetMajorVersion
+	migration.Status.SetPhase(apiv1.MajorMigrationPhaseCreatingTarget, "Waiting for the target cluster to be ready")
+	return nil
+}
+
+// checkMajorMigrationPrerequisites checks that the source
+// cluster can be migrated to the requested major version
+func checkMajorMigrationPrerequisites(
+	migration *apiv1.MajorMigration,
+	source *apiv1.Cluster,
+	sourceMajorVersion, targetMajorVersion int,
+) error {
+	if !source.GetEnableSuperuserAccess() {
+		return fmt.Errorf("the superuser access needs to be enabled on the source cluster")
+	}
+
+	if source.IsReplica() {
+		return fmt.Errorf("the source cluster is a replica cluster")
+	}
+
+	if targetMajorVersion <= sourceMajorVersion {
+		return fmt.Errorf("the target image runs PostgreSQL %d, which is not newer than the %d of the source cluster",
+			targetMajorVersion, sourceMajorVersion)
+	}
+
+	databases := migration.GetDatabases(source)
+	if len(databases) == 0 {
+		return fmt.Errorf("the source cluster has no application database, the databases need to be specified")
+	}
+
+	for _, database := range databases {
+		if name := getMajorMigrationReplicationName(migration, database); len(name) > 63 {
+			return fmt.Errorf("the name of the publication of database %s, %q, is longer than 63 characters",
+				database, name)
+		}
+
+		name := getMajorMigrationResourceName(migration, database)
+		if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+			return fmt.Errorf("cannot name the replication objects of database %s %q: %s",
+				database, name, strings.Join(errs, ", "))
+		}
+	}
+
+	return nil
+}
+
+// buildMajorMigrationTarget creates the definition of the target cluster,
+// copying the configuration of the source one and importing its schema
+func buildMajorMigrationTarget(migration *apiv1.MajorMigration, source *apiv1.Cluster) *apiv1.Cluster {
+	target := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      migration.Spec.TargetCluster.Name,
+			Namespace: migration.Namespace,
+			Labels: map[string]string{
+				utils.MajorMigrationLabelName: migration.Name,
+			},
+		},
+		Spec: *source.Spec.DeepCopy(),
+	}
+
+	// The settings bound to the source cluster, which
+	// would clash with it, are not copied
+	target.Spec.ImageName = migration.Spec.ImageName
+	target.Spec.ImageCatalogRef = nil
+	target.Spec.Backup = nil
+	target.Spec.ReplicaCluster = nil
+	target.Spec.Certificates = nil
+	if target.Spec.Managed != nil {
+		target.Spec.Managed.Services = nil
+	}
+	target.Spec.Plugins = slices.DeleteFunc(target.Spec.Plugins, func(plugin apiv1.PluginConfiguration) bool {
+		return plugin.IsWALArchiver != nil && *plugin.IsWALArchiver
+	})
+
+	target.Spec.ExternalClusters = []apiv1.ExternalCluster{
+		{
+			Name: source.Name,
+			ConnectionParameters: map[string]string{
+				"host":    source.GetServiceReadWriteName(),
+				"user":    "postgres",
+				"dbname":  "postgres",
+				"sslmode": "verify-full",
+			},
+			Password: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: source.GetSuperuserSecretName()},
+				Key:                  corev1.BasicAuthPasswordKey,
+			},
+			SSLRootCert: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: source.GetServerCASecretName()},
+				Key:                  certs.CACertKey,
+			},
+		},
+	}
+
+	initDB := &apiv1.BootstrapInitDB{}
+	if source.Spec.Bootstrap != nil && source.Spec.Bootstrap.InitDB != nil {
+		initDB = source.Spec.Bootstrap.InitDB.DeepCopy()
+		initDB.PostInitSQL = nil
+		initDB.PostInitApplicationSQL = nil
+		initDB.PostInitTemplateSQL = nil
+		initDB.PostInitSQLRefs = nil
+		initDB.PostInitApplicationSQLRefs = nil
+		initDB.PostInitTemplateSQLRefs = nil
+	}
+	initDB.Database = source.GetApplicationDatabaseName()
+	initDB.Owner = source.GetApplicationDatabaseOwner()
+	initDB.Secret = &apiv1.LocalObjectReference{Name: source.GetApplicationSecretName()}
+	initDB.Import = &apiv1.Import{
+		Source: apiv1.ImportSource{
+			ExternalCluster: source.Name,
+		},
+		Type:       apiv1.MonolithSnapshotType,
+		Databases:  migration.GetDatabases(source),
+		Roles:      []string{"*"},
+		SchemaOnly: true,
+	}
+	target.Spec.Bootstrap = &apiv1.BootstrapConfiguration{InitDB: initDB}
+
+	return target
+}
+
+// reconcileReplication creates the publications and the subscriptions
+// replicating the migrated databases, and tracks their progress until
+// the target cluster is ready for the cutover
+func (r *MajorMigrationReconciler) reconcileReplication(
+	ctx context.Context,
+	migration *apiv1.MajorMigration,
+	source *apiv1.Cluster,
+	target *apiv1.Cluster,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	subscriptions, err := r.ensureReplicationObjects(ctx, migration, source, target)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var sourceLSN string
+	if primaryStatus, err := r.getPrimaryStatus(ctx, source); err != nil {
+		contextLogger.Info("Cannot get the WAL location of the source cluster, the lag is unknown",
+			"err", err.Error())
+	} else {
+		sourceLSN = string(primaryStatus.CurrentLsn)
+	}
+
+	databases := migration.GetDatabases(source)
+	migration.Status.Databases = buildMajorMigrationDatabasesStatus(databases, subscriptions, sourceLSN)
+	if message := getMajorMigrationReplicationMessage(databases, subscriptions); message != "" {
+		migration.Status.SetPhase(apiv1.MajorMigrationPhaseReplicating, message)
+		return ctrl.Result{RequeueAfter: majorMigrationReplicationInterval}, nil
+	}
+	if message := getMajorMigrationLagMessage(migration.Status.Databases); message != "" {
+		migration.Status.SetPhase(apiv1.MajorMigrationPhaseReplicating, message)
+		return ctrl.Result{RequeueAfter: majorMigrationReplicationInterval}, nil
+	}
+
+	if migration.Status.Phase != apiv1.MajorMigrationPhaseReadyForCutover {
+		r.Recorder.Event(migration, "Normal", "ReadyForCutover",
+			"Every table has been copied to the target cluster")
+	}
+	migration.Status.SetPhase(apiv1.MajorMigrationPhaseReadyForCutover, "")
+
+	if !migration.Spec.Cutover {
+		return ctrl.Result{RequeueAfter: majorMigrationReplicationInterval}, nil
+	}
+
+	return r.startCutover(ctx, migration, source)
+}
+
+// ensureReplicationObjects creates the publication on the source cluster
+// and, once it is in place, the subscription on the target cluster of
+// every migrated database. The subscriptions are returned by database
+// name, missing when not created yet
+func (r *MajorMigrationReconciler) ensureReplicationObjects(
+	ctx context.Context,
+	migration *apiv1.MajorMigration,
+	source *apiv1.Cluster,
+	target *apiv1.Cluster,
+) (map[string]*apiv1.Subscription, error) {
+	subscriptions := make(map[string]*apiv1.Subscription)
+	for _, database := range migration.GetDatabases(source) {
+		resourceName := getMajorMigrationResourceName(migration, database)
+		replicationName := getMajorMigrationReplicationName(migration, database)
+
+		publication := &apiv1.Publication{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      resourceName,
+				Namespace: migration.Namespace,
+				Labels:    map[string]string{utils.MajorMigrationLabelName: migration.Name},
+			},
+			Spec: apiv1.PublicationSpec{
+				ClusterRef:    corev1.LocalObjectReference{Name: source.Name},
+				Name:          replicationName,
+				DBName:        database,
+				Target:        apiv1.PublicationTarget{AllTables: true},
+				ReclaimPolicy: apiv1.PublicationReclaimDelete,
+			},
+		}
+		if err := r.ensureReplicationObject(ctx, migration, publication); err != nil {
+			return nil, err
+		}
+		if publication.Status.Applied == nil || !*publication.Status.Applied {
+			continue
+		}
+
+		subscription := &apiv1.Subscription{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      resourceName,
+				Namespace: migration.Namespace,
+				Labels:    map[string]string{utils.MajorMigrationLabelName: migration.Name},
+			},
+			Spec: apiv1.SubscriptionSpec{
+				ClusterRef:          corev1.LocalObjectReference{Name: target.Name},
+				Name:                replicationName,
+				DBName:              database,
+				PublicationName:     replicationName,
+				ExternalClusterName: source.Name,
+				SyncSequences:       true,
+				ReclaimPolicy:       apiv1.SubscriptionReclaimDelete,
+			},
+		}
+		if err := r.ensureReplicationObject(ctx, migration, subscription); err != nil {
+			return nil, err
+		}
+		subscriptions[database] = subscription
+	}
+
+	return subscriptions, nil
+}
+
+// ensureReplicationObject creates the passed object, owned by the
+// migration, when it doesn't exist. Otherwise, the object is
+// refreshed with the content stored in Kubernetes
+func (r *MajorMigrationReconciler) ensureReplicationObject(
+	ctx context.Context,
+	migration *apiv1.MajorMigration,
+	obj client.Object,
+) error {
+	err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+	if err == nil || !apierrs.IsNotFound(err) {
+		return err
+	}
+
+	if err := controllerutil.SetControllerReference(migration, obj, r.Scheme); err != nil {
+		return err
+	}
+
+	if err := r.Create(ctx, obj); err != nil {
+		return fmt.Errorf("while creating %s: %w", obj.GetName(), err)
+	}
+
+	return nil
+}
+
+// getPrimaryStatus queries the instance manager of the primary
+// instance of the passed cluster for its status
+func (r *MajorMigrationReconciler) getPrimaryStatus(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (*postgres.PostgresqlStatus, error) {
+	var pod corev1.Pod
+	if err := r.Get(ctx, types.NamespacedName{
+		Namespace: cluster.Namespace,
+		Name:      cluster.Status.CurrentPrimary,
+	}, &pod); err != nil {
+		return nil, fmt.Errorf("while getting the primary instance of cluster %s: %w", cluster.Name, err)
+	}
+
+	statusList := r.instanceStatusClient.GetStatusFromInstances(ctx, corev1.PodList{Items: []corev1.Pod{pod}})
+	if len(statusList.Items) == 0 {
+		return nil, fmt.Errorf("could not get instance status for pod %s: %w", pod.Name, ErrInstanceStatusUnavailable)
+	}
+	if statusList.Items[0].Error != nil {
+		return nil, statusList.Items[0].Error
+	}
+
+	return &statusList.Items[0], nil
+}
+
+// failMigration marks the migration as failed, making the source
+// cluster writable and resuming the Poolers when the cutover was in progress
+func (r *MajorMigrationReconciler) failMigration(
+	ctx context.Context,
+	migration *apiv1.MajorMigration,
+	migrationErr error,
+) error {
+	cutover := migration.Status.Cutover
+	if cutover != nil && cutover.PoolersSwitchedAt == nil {
+		if err := r.resetSourceReadOnly(ctx, migration); err != nil {
+			return err
+		}
+		if err := r.resumePoolers(ctx, migration); err != nil {
+			return err
+		}
+	}
+
+	log.FromContext(ctx).Info("Major migration failed", "err", migrationErr.Error())
+	r.Recorder.Event(migration, "Warning", "MigrationFailed", migrationErr.Error())
+	migration.Status.SetAsFailed(migrationErr)
+	return nil
+}
+
+// getMajorMigrationResourceName gets the name of the Publication
+// and of the Subscription replicating the passed database
+func getMajorMigrationResourceName(migration *apiv1.MajorMigration, database string) string {
+	return fmt.Sprintf("%s-%s", migration.Name, strings.ReplaceAll(database, "_", "-"))
+}
+
+// getMajorMigrationReplicationName gets the name, inside PostgreSQL,
+// of the publication and of the subscription replicating the passed database
+func getMajorMigrationReplicationName(migration *apiv1.MajorMigration, database string) string {
+	return fmt.Sprintf("%s_%s", strings.NewReplacer("-", "_", ".", "_").Replace(migration.Name), database)
+}
+
+// SetupWithManager sets up this controller given a controller manager
+func (r *MajorMigrationReconciler) SetupWithManager(mgr ctrl.Manager, maxConcurrentReconciles int) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconciles}).
+		For(&apiv1.MajorMigration{}).
+		Named("major-migration").
+		Owns(&apiv1.Publication{}).
+		Owns(&apiv1.Subscription{}).
+		Watches(&apiv1.Cluster{}, handler.EnqueueRequestsFromMapFunc(r.mapClustersToMajorMigrations())).
+		Complete(r)
+}
+
+// mapClustersToMajorMigrations returns a function mapping a cluster
+// to the migrations using it as source or target
+func (r *MajorMigrationReconciler) mapClustersToMajorMigrations() handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		var migrations apiv1.MajorMigrationList
+		if err := r.List(ctx, &migrations, client.InNamespace(obj.GetNamespace())); err != nil {
+			log.FromContext(ctx).Error(err, "while getting the major migrations")
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for idx := range migrations.Items {
+			migration := &migrations.Items[idx]
+			if migration.Status.IsDone() {
+				continue
+			}
+			if migration.Spec.SourceCluster.Name == obj.GetName() ||
+				migration.Spec.TargetCluster.Name == obj.GetName() {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: client.ObjectKeyFromObject(migration),
+				})
+			}
+		}
+
+		return requests
+	}
+}
+
+// buildMajorMigrationDatabasesStatus reports the replication progress
+// of the migrated databases, given the WAL location of the source cluster
+func buildMajorMigrationDatabasesStatus(
+	databases []string,
+	subscriptions map[string]*apiv1.Subscription,
+	sourceLSN string,
+) []apiv1.MajorMigrationDatabaseStatus {
+	result := make([]apiv1.MajorMigrationDatabaseStatus, 0, len(databases))
+	for _, database := range databases {
+		databaseStatus := apiv1.MajorMigrationDatabaseStatus{Name: database}
+		if subscription := subscriptions[database]; subscription != nil {
+			databaseStatus.SequencesSyncedAt = subscription.Status.SequencesSyncedAt
+			if statistics := subscription.Status.Statistics; statistics != nil {
+				databaseStatus.ReadyTables = statistics.ReadyTables
+				databaseStatus.PendingTables = int32(len(statistics.Tables)) //nolint:gosec
+				databaseStatus.LagBytes = getReplicationLag(sourceLSN, statistics.LatestEndLSN)
+			}
+		}
+		result = append(result, databaseStatus)
+	}
+
+	return result
+}
+
+// getMajorMigrationReplicationMessage explains what the target cluster
+// is waiting for before being ready for the cutover, returning an empty
+// string when every database has been copied
+func getMajorMigrationReplicationMessage(
+	databases []string,
+	subscriptions map[string]*apiv1.Subscription,
+) string {
+	for _, database := range databases {
+		subscription := subscriptions[database]
+		switch {
+		case subscription == nil:
+			return fmt.Sprintf("Waiting for the publication of database %s", database)
+		case subscription.Status.Applied == nil:
+			return fmt.Sprintf("Waiting for the subscription of database %s", database)
+		case !*subscription.Status.Applied:
+			return fmt.Sprintf("Cannot subscribe to database %s: %s", database, subscription.Status.Message)
+		case subscription.Status.Statistics == nil || !subscription.Status.Statistics.Enabled:
+			return fmt.Sprintf("Waiting for the subscription of database %s to be enabled", database)
+		case len(subscription.Status.Statistics.Tables) > 0:
+			return fmt.Sprintf("Copying %d tables of database %s",
+				len(subscription.Status.Statistics.Tables), database)
+		case subscription.Status.SequencesSyncedAt == nil:
+			return fmt.Sprintf("Waiting for the sequences of database %s to be copied", database)
+		}
+	}
+
+	return ""
+}
+
+// getMajorMigrationLagMessage explains which database is lagging too much
+// behind the source cluster for the cutover to start, returning an empty
+// string when the lag of every database is known and below the threshold
+func getMajorMigrationLagMessage(databases []apiv1.MajorMigrationDatabaseStatus) string {
+	for _, database := range databases {
+		switch {
+		case database.LagBytes == nil:
+			return fmt.Sprintf("Waiting for the replication lag of database %s to be known", database.Name)
+		case *database.LagBytes > majorMigrationMaxCutoverLag:
+			return fmt.Sprintf("Waiting for the replication lag of database %s to go below %d bytes, currently %d",
+				database.Name, majorMigrationMaxCutoverLag, *database.LagBytes)
+		}
+	}
+
+	return ""
+}
+
+// getReplicationLag returns the distance, in bytes, between the WAL
+// location of the source cluster and the one confirmed by the
+// subscriber, or nil when unknown
+func getReplicationLag(sourceLSN, confirmedLSN string) *int64 {
+	if sourceLSN == "" || confirmedLSN == "" {
+		return nil
+	}
+
+	source, err := cnpgTypes.LSN(sourceLSN).Parse()
+	if err != nil {
+		return nil
+	}
+	confirmed, err := cnpgTypes.LSN(confirmedLSN).Parse()
+	if err != nil {
+		return nil
+	}
+
+	if confirmed >= source {
+		return ptr.To(int64(0))
+	}
+
+	return ptr.To(int64(source - confirmed)) //nolint:gosec
+}
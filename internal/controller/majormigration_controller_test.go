@@ -0,0 +1,446 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	cnpgTypes "github.com/cloudnative-pg/machinery/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver/client/remote"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakePrimaryStatusClient reports a fixed WAL location for every queried
+// pod, and stops the writes only once the source cluster is read-only
+type fakePrimaryStatusClient struct {
+	remote.InstanceClient
+	client     client.Client
+	source     types.NamespacedName
+	currentLSN string
+
+	// readOnlyPending simulates an instance that didn't
+	// load the read-only configuration yet
+	readOnlyPending bool
+	stopWritesCalls int
+}
+
+func (f *fakePrimaryStatusClient) GetStatusFromInstances(
+	_ context.Context,
+	pods corev1.PodList,
+) postgres.PostgresqlStatusList {
+	items := make([]postgres.PostgresqlStatus, 0, len(pods.Items))
+	for i := range pods.Items {
+		items = append(items, postgres.PostgresqlStatus{
+			Pod:        &pods.Items[i],
+			IsPrimary:  true,
+			CurrentLsn: cnpgTypes.LSN(f.currentLSN),
+		})
+	}
+	return postgres.PostgresqlStatusList{Items: items}
+}
+
+func (f *fakePrimaryStatusClient) StopWrites(ctx context.Context, _ *corev1.Pod) (string, error) {
+	var cluster apiv1.Cluster
+	if err := f.client.Get(ctx, f.source, &cluster); err != nil {
+		return "", err
+	}
+	if f.readOnlyPending || cluster.Spec.PostgresConfiguration.Parameters["default_transaction_read_only"] != "on" {
+		return "", &remote.StatusError{StatusCode: http.StatusBadRequest, Body: "NOT_READ_ONLY"}
+	}
+
+	f.stopWritesCalls++
+	return f.currentLSN, nil
+}
+
+var _ = Describe("major migration", func() {
+	var (
+		env          *testingEnvironment
+		source       *apiv1.Cluster
+		migration    *apiv1.MajorMigration
+		statusClient *fakePrimaryStatusClient
+	)
+
+	BeforeEach(func(ctx context.Context) {
+		env = buildTestEnvironment()
+		statusClient = &fakePrimaryStatusClient{client: env.client, currentLSN: "0/3000060"}
+		env.majorMigrationReconciler.instanceStatusClient = statusClient
+
+		namespace := newFakeNamespace(env.client)
+		source = newFakeCNPGCluster(env.client, namespace, func(cluster *apiv1.Cluster) {
+			cluster.Spec.ImageName = "ghcr.io/cloudnative-pg/postgresql:16.4"
+			cluster.Spec.EnableSuperuserAccess = ptr.To(true)
+			cluster.Spec.Bootstrap = &apiv1.BootstrapConfiguration{
+				InitDB: &apiv1.BootstrapInitDB{
+					Database:    "app",
+					Owner:       "app",
+					PostInitSQL: []string{"CREATE EXTENSION pg_stat_statements"},
+				},
+			}
+			cluster.Spec.Backup = &apiv1.BackupConfiguration{}
+			cluster.Status.CurrentPrimary = cluster.Name + "-1"
+		})
+		statusClient.source = client.ObjectKeyFromObject(source)
+		Expect(env.client.Create(ctx, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      source.Status.CurrentPrimary,
+				Namespace: namespace,
+			},
+		})).To(Succeed())
+
+		migration = &apiv1.MajorMigration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "upgrade",
+				Namespace: namespace,
+			},
+			Spec: apiv1.MajorMigrationSpec{
+				SourceCluster: corev1.LocalObjectReference{Name: source.Name},
+				TargetCluster: corev1.LocalObjectReference{Name: source.Name + "-17"},
+				ImageName:     "ghcr.io/cloudnative-pg/postgresql:17.2",
+			},
+		}
+		Expect(env.client.Create(ctx, migration)).To(Succeed())
+	})
+
+	getTarget := func(ctx context.Context) *apiv1.Cluster {
+		var target apiv1.Cluster
+		Expect(env.client.Get(ctx, types.NamespacedName{
+			Namespace: migration.Namespace,
+			Name:      migration.Spec.TargetCluster.Name,
+		}, &target)).To(Succeed())
+		return &target
+	}
+
+	getSource := func(ctx context.Context) *apiv1.Cluster {
+		var updatedSource apiv1.Cluster
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(source), &updatedSource)).To(Succeed())
+		return &updatedSource
+	}
+
+	getPooler := func(ctx context.Context, pooler *apiv1.Pooler) *apiv1.Pooler {
+		var updatedPooler apiv1.Pooler
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(pooler), &updatedPooler)).To(Succeed())
+		return &updatedPooler
+	}
+
+	reconcile := func(ctx context.Context) {
+		_, err := env.majorMigrationReconciler.reconcileMigration(ctx, migration, source)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	// markAsReady simulates the target cluster becoming healthy
+	// and the publication being created on the source cluster
+	markAsReady := func(ctx context.Context) {
+		target := getTarget(ctx)
+		target.Status.Phase = apiv1.PhaseHealthy
+		target.Status.ReadyInstances = target.Spec.Instances
+		Expect(env.client.Status().Update(ctx, target)).To(Succeed())
+
+		reconcile(ctx)
+		var publication apiv1.Publication
+		Expect(env.client.Get(ctx, types.NamespacedName{
+			Namespace: migration.Namespace,
+			Name:      "upgrade-app",
+		}, &publication)).To(Succeed())
+		publication.Status.Applied = ptr.To(true)
+		Expect(env.client.Status().Update(ctx, &publication)).To(Succeed())
+	}
+
+	// updateSubscription simulates the instance manager of the
+	// target cluster reporting the status of the subscription
+	updateSubscription := func(ctx context.Context, latestEndLSN string, pendingTables int) {
+		var subscription apiv1.Subscription
+		Expect(env.client.Get(ctx, types.NamespacedName{
+			Namespace: migration.Namespace,
+			Name:      "upgrade-app",
+		}, &subscription)).To(Succeed())
+		now := metav1.NewTime(time.Now().Add(time.Second))
+		subscription.Status.Applied = ptr.To(true)
+		subscription.Status.SequencesSyncedAt = &now
+		subscription.Status.Statistics = &apiv1.SubscriptionStatistics{
+			UpdateTime:   now,
+			Enabled:      true,
+			LatestEndLSN: latestEndLSN,
+			ReadyTables:  3,
+			Tables:       make([]apiv1.SubscriptionTableStatus, pendingTables),
+		}
+		Expect(env.client.Status().Update(ctx, &subscription)).To(Succeed())
+	}
+
+	It("builds the target cluster importing the schema of the source cluster", func() {
+		target := buildMajorMigrationTarget(migration, source)
+
+		Expect(target.Name).To(Equal(migration.Spec.TargetCluster.Name))
+		Expect(target.Labels).To(HaveKeyWithValue(utils.MajorMigrationLabelName, migration.Name))
+		Expect(target.Spec.ImageName).To(Equal(migration.Spec.ImageName))
+		Expect(target.Spec.Backup).To(BeNil())
+		Expect(target.Spec.Certificates).To(BeNil())
+		Expect(target.Spec.Instances).To(Equal(source.Spec.Instances))
+
+		Expect(target.Spec.ExternalClusters).To(HaveLen(1))
+		externalCluster := target.Spec.ExternalClusters[0]
+		Expect(externalCluster.Name).To(Equal(source.Name))
+		Expect(externalCluster.ConnectionParameters).To(HaveKeyWithValue("host", source.Name+"-rw"))
+		Expect(externalCluster.Password.Name).To(Equal(source.GetSuperuserSecretName()))
+		Expect(externalCluster.SSLRootCert.Name).To(Equal(source.GetServerCASecretName()))
+
+		initDB := target.Spec.Bootstrap.InitDB
+		Expect(initDB.Database).To(Equal("app"))
+		Expect(initDB.Secret.Name).To(Equal(source.GetApplicationSecretName()))
+		Expect(initDB.PostInitSQL).To(BeEmpty())
+		Expect(initDB.Import.Type).To(Equal(apiv1.MonolithSnapshotType))
+		Expect(initDB.Import.Databases).To(ConsistOf("app"))
+		Expect(initDB.Import.Roles).To(ConsistOf("*"))
+		Expect(initDB.Import.SchemaOnly).To(BeTrue())
+
+		Expect(source.Spec.Backup).ToNot(BeNil())
+		Expect(source.Spec.Bootstrap.InitDB.PostInitSQL).ToNot(BeEmpty())
+	})
+
+	It("fails when the superuser access is disabled", func(ctx context.Context) {
+		source.Spec.EnableSuperuserAccess = ptr.To(false)
+		reconcile(ctx)
+
+		Expect(migration.Status.Phase).To(Equal(apiv1.MajorMigrationPhaseFailed))
+		Expect(migration.Status.Message).To(ContainSubstring("superuser"))
+	})
+
+	It("fails when the target image is not running a newer major version", func(ctx context.Context) {
+		migration.Spec.ImageName = "ghcr.io/cloudnative-pg/postgresql:16.6"
+		reconcile(ctx)
+
+		Expect(migration.Status.Phase).To(Equal(apiv1.MajorMigrationPhaseFailed))
+		Expect(migration.Status.Message).To(ContainSubstring("not newer"))
+	})
+
+	It("fails when the target cluster already exists", func(ctx context.Context) {
+		Expect(env.client.Create(ctx, &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      migration.Spec.TargetCluster.Name,
+				Namespace: migration.Namespace,
+			},
+		})).To(Succeed())
+		reconcile(ctx)
+
+		Expect(migration.Status.Phase).To(Equal(apiv1.MajorMigrationPhaseFailed))
+		Expect(migration.Status.Message).To(ContainSubstring("already exists"))
+	})
+
+	It("creates the target cluster", func(ctx context.Context) {
+		reconcile(ctx)
+
+		Expect(migration.Status.Phase).To(Equal(apiv1.MajorMigrationPhaseCreatingTarget))
+		Expect(migration.Status.SourceMajorVersion).To(Equal(16))
+		Expect(migration.Status.TargetMajorVersion).To(Equal(17))
+		Expect(getTarget(ctx).Spec.ImageName).To(Equal(migration.Spec.ImageName))
+
+		reconcile(ctx)
+		Expect(migration.Status.Phase).To(Equal(apiv1.MajorMigrationPhaseCreatingTarget))
+	})
+
+	It("subscribes to the source cluster once the publication is in place", func(ctx context.Context) {
+		reconcile(ctx)
+		markAsReady(ctx)
+		Expect(migration.Status.Phase).To(Equal(apiv1.MajorMigrationPhaseReplicating))
+
+		subscriptionKey := types.NamespacedName{Namespace: migration.Namespace, Name: "upgrade-app"}
+		var subscription apiv1.Subscription
+		err := env.client.Get(ctx, subscriptionKey, &subscription)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+
+		reconcile(ctx)
+		Expect(env.client.Get(ctx, subscriptionKey, &subscription)).To(Succeed())
+		Expect(subscription.Spec.ClusterRef.Name).To(Equal(migration.Spec.TargetCluster.Name))
+		Expect(subscription.Spec.ExternalClusterName).To(Equal(source.Name))
+		Expect(subscription.Spec.PublicationName).To(Equal("upgrade_app"))
+		Expect(subscription.Spec.SyncSequences).To(BeTrue())
+		Expect(subscription.OwnerReferences).To(HaveLen(1))
+
+		updateSubscription(ctx, "0/3000000", 2)
+		reconcile(ctx)
+		Expect(migration.Status.Phase).To(Equal(apiv1.MajorMigrationPhaseReplicating))
+		Expect(migration.Status.Message).To(Equal("Copying 2 tables of database app"))
+		Expect(migration.Status.Databases).To(HaveLen(1))
+		Expect(migration.Status.Databases[0].PendingTables).To(BeEquivalentTo(2))
+		Expect(migration.Status.Databases[0].LagBytes).To(HaveValue(BeEquivalentTo(0x60)))
+
+		updateSubscription(ctx, "0/3000060", 0)
+		reconcile(ctx)
+		Expect(migration.Status.Phase).To(Equal(apiv1.MajorMigrationPhaseReadyForCutover))
+	})
+
+	It("is not ready for the cutover while the replication lag is too high", func(ctx context.Context) {
+		reconcile(ctx)
+		markAsReady(ctx)
+		reconcile(ctx)
+
+		updateSubscription(ctx, "0/1000000", 0)
+		reconcile(ctx)
+		Expect(migration.Status.Phase).To(Equal(apiv1.MajorMigrationPhaseReplicating))
+		Expect(migration.Status.Message).To(ContainSubstring(
+			"Waiting for the replication lag of database app to go below 16777216 bytes"))
+
+		updateSubscription(ctx, "0/3000000", 0)
+		reconcile(ctx)
+		Expect(migration.Status.Phase).To(Equal(apiv1.MajorMigrationPhaseReadyForCutover))
+	})
+
+	When("the cutover is requested", func() {
+		var pooler *apiv1.Pooler
+
+		BeforeEach(func(ctx context.Context) {
+			pooler = newFakePooler(env.client, source)
+			migration.Spec.Cutover = true
+			migration.Spec.CutoverTimeout = &metav1.Duration{Duration: time.Minute}
+
+			reconcile(ctx)
+			markAsReady(ctx)
+			reconcile(ctx)
+			updateSubscription(ctx, "0/3000000", 0)
+			reconcile(ctx)
+		})
+
+		It("pauses the poolers of the source cluster", func(ctx context.Context) {
+			Expect(migration.Status.Phase).To(Equal(apiv1.MajorMigrationPhaseCuttingOver))
+			Expect(migration.Status.Cutover.PausedPoolers).To(ConsistOf(pooler.Name))
+			Expect(getPooler(ctx, pooler).Annotations).To(
+				HaveKeyWithValue(utils.PoolerPausedForMigrationAnnotationName, migration.Name))
+			Expect(getPooler(ctx, pooler).ShouldBePaused()).To(BeTrue())
+		})
+
+		It("makes the source cluster read-only before recording its WAL location", func(ctx context.Context) {
+			Expect(getSource(ctx).Annotations).To(
+				HaveKeyWithValue(utils.ClusterReadOnlyForMigrationAnnotationName, migration.Name))
+			Expect(getSource(ctx).Spec.PostgresConfiguration.Parameters).To(
+				HaveKeyWithValue("default_transaction_read_only", "on"))
+			Expect(migration.Status.Cutover.WritesStoppedAt).To(BeNil())
+			Expect(migration.Status.Cutover.LSN).To(BeEmpty())
+
+			statusClient.readOnlyPending = true
+			reconcile(ctx)
+			Expect(statusClient.stopWritesCalls).To(BeZero())
+			Expect(migration.Status.Cutover.WritesStoppedAt).To(BeNil())
+			Expect(migration.Status.Cutover.LSN).To(BeEmpty())
+
+			statusClient.readOnlyPending = false
+			reconcile(ctx)
+			Expect(statusClient.stopWritesCalls).To(Equal(1))
+			Expect(migration.Status.Cutover.WritesStoppedAt).ToNot(BeNil())
+			Expect(migration.Status.Cutover.LSN).To(Equal("0/3000060"))
+		})
+
+		It("moves the applications to the target cluster once it caught up", func(ctx context.Context) {
+			reconcile(ctx)
+			Expect(migration.Status.Cutover.WritesStoppedAt).ToNot(BeNil())
+			Expect(migration.Status.Cutover.LSN).To(Equal("0/3000060"))
+
+			reconcile(ctx)
+			Expect(migration.Status.Cutover.PoolersSwitchedAt).To(BeNil())
+			Expect(migration.Status.Message).To(ContainSubstring("Waiting for database app"))
+
+			updateSubscription(ctx, "0/3000060", 0)
+			reconcile(ctx)
+			Expect(migration.Status.Cutover.PoolersSwitchedAt).ToNot(BeNil())
+			switchedPooler := getPooler(ctx, pooler)
+			Expect(switchedPooler.ShouldBePaused()).To(BeFalse())
+			Expect(switchedPooler.Spec.PgBouncer.Databases).To(ConsistOf(apiv1.PgBouncerDatabase{
+				Name:    "*",
+				Cluster: &apiv1.LocalObjectReference{Name: migration.Spec.TargetCluster.Name},
+			}))
+
+			// The subscriptions are removed before the publications
+			reconcile(ctx)
+			var subscriptions apiv1.SubscriptionList
+			Expect(env.client.List(ctx, &subscriptions, client.InNamespace(migration.Namespace))).To(Succeed())
+			Expect(subscriptions.Items).To(BeEmpty())
+			var publications apiv1.PublicationList
+			Expect(env.client.List(ctx, &publications, client.InNamespace(migration.Namespace))).To(Succeed())
+			Expect(publications.Items).To(HaveLen(1))
+			Expect(migration.Status.Phase).To(Equal(apiv1.MajorMigrationPhaseCuttingOver))
+
+			reconcile(ctx)
+			Expect(env.client.List(ctx, &publications, client.InNamespace(migration.Namespace))).To(Succeed())
+			Expect(publications.Items).To(BeEmpty())
+
+			reconcile(ctx)
+			Expect(migration.Status.Phase).To(Equal(apiv1.MajorMigrationPhaseCompleted))
+			var fencedSource apiv1.Cluster
+			Expect(env.client.Get(ctx, client.ObjectKeyFromObject(source), &fencedSource)).To(Succeed())
+			Expect(fencedSource.Annotations).To(HaveKeyWithValue(utils.FencedInstanceAnnotation, `["*"]`))
+			Expect(fencedSource.Spec.PostgresConfiguration.Parameters).To(
+				HaveKeyWithValue("default_transaction_read_only", "on"))
+		})
+
+		It("resumes the poolers and the writes when the timeout expires", func(ctx context.Context) {
+			statusClient.readOnlyPending = true
+			migration.Status.Cutover.StartedAt = metav1.NewTime(time.Now().Add(-2 * time.Minute))
+			reconcile(ctx)
+
+			Expect(migration.Status.Phase).To(Equal(apiv1.MajorMigrationPhaseFailed))
+			Expect(getPooler(ctx, pooler).ShouldBePaused()).To(BeFalse())
+			Expect(getPooler(ctx, pooler).Spec.PgBouncer.Databases).To(BeEmpty())
+			Expect(getSource(ctx).Annotations).ToNot(HaveKey(utils.ClusterReadOnlyForMigrationAnnotationName))
+			Expect(getSource(ctx).Spec.PostgresConfiguration.Parameters).ToNot(
+				HaveKey("default_transaction_read_only"))
+		})
+	})
+})
+
+var _ = Describe("redirectPoolerDatabases", func() {
+	It("points the databases served by the source cluster to the target one", func() {
+		pooler := &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				Cluster: apiv1.LocalObjectReference{Name: "source"},
+				PgBouncer: &apiv1.PgBouncerSpec{
+					Databases: []apiv1.PgBouncerDatabase{
+						{Name: "app"},
+						{Name: "reports", Cluster: &apiv1.LocalObjectReference{Name: "other"}},
+					},
+				},
+			},
+		}
+
+		redirectPoolerDatabases(pooler, "source", "target")
+		Expect(pooler.Spec.PgBouncer.Databases[0].GetClusterName(pooler)).To(Equal("target"))
+		Expect(pooler.Spec.PgBouncer.Databases[1].GetClusterName(pooler)).To(Equal("other"))
+	})
+})
+
+var _ = Describe("getReplicationLag", func() {
+	It("computes the distance between the WAL locations", func() {
+		Expect(getReplicationLag("0/3000060", "0/3000000")).To(HaveValue(BeEquivalentTo(0x60)))
+		Expect(getReplicationLag("0/3000000", "0/3000060")).To(HaveValue(BeEquivalentTo(0)))
+		Expect(getReplicationLag("", "0/3000060")).To(BeNil())
+		Expect(getReplicationLag("0/3000060", "invalid")).To(BeNil())
+	})
+})
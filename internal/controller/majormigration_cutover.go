@@ -0,0 +1,477 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	cnpgTypes "github.com/cloudnative-pg/machinery/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// majorMigrationCutoverInterval is how often the progress
+// of the cutover is checked while the Poolers are paused
+const majorMigrationCutoverInterval = 1 * time.Second
+
+// defaultTransactionReadOnlyParameter is the PostgreSQL parameter
+// making the new transactions read-only
+const defaultTransactionReadOnlyParameter = "default_transaction_read_only"
+
+// startCutover pauses the Poolers pointing to the source cluster and
+// makes its new transactions read-only, stopping the writes
+func (r *MajorMigrationReconciler) startCutover(
+	ctx context.Context,
+	migration *apiv1.MajorMigration,
+	source *apiv1.Cluster,
+) (ctrl.Result, error) {
+	poolers, err := r.getSourcePoolers(ctx, migration)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// The databases served by PgCat cannot be pointed to another cluster
+	for idx := range poolers.Items {
+		if poolers.Items[idx].Spec.PgBouncer == nil {
+			migration.Status.SetPhase(apiv1.MajorMigrationPhaseReadyForCutover,
+				fmt.Sprintf("Pooler %s cannot be pointed to the target cluster, as it is not based on PgBouncer",
+					poolers.Items[idx].Name))
+			return ctrl.Result{RequeueAfter: majorMigrationReplicationInterval}, nil
+		}
+	}
+
+	pausedPoolers := make([]string, 0, len(poolers.Items))
+	for idx := range poolers.Items {
+		pooler := &poolers.Items[idx]
+		origPooler := pooler.DeepCopy()
+		if pooler.Annotations == nil {
+			pooler.Annotations = make(map[string]string)
+		}
+		pooler.Annotations[utils.PoolerPausedForMigrationAnnotationName] = migration.Name
+		if err := r.Patch(ctx, pooler, client.MergeFrom(origPooler)); err != nil {
+			return ctrl.Result{}, fmt.Errorf("while pausing pooler %s: %w", pooler.Name, err)
+		}
+		pausedPoolers = append(pausedPoolers, pooler.Name)
+	}
+
+	if err := r.setSourceReadOnly(ctx, migration, source); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	log.FromContext(ctx).Info("Starting the cutover",
+		"pausedPoolers", pausedPoolers,
+		"timeout", migration.GetCutoverTimeout())
+	r.Recorder.Eventf(migration, "Normal", "CutoverStarted",
+		"Stopping the writes on cluster %s, paused poolers: %v", source.Name, pausedPoolers)
+
+	migration.Status.Cutover = &apiv1.MajorMigrationCutoverStatus{
+		StartedAt:     metav1.Now(),
+		PausedPoolers: pausedPoolers,
+	}
+	migration.Status.SetPhase(apiv1.MajorMigrationPhaseCuttingOver,
+		"Waiting for the source cluster to become read-only")
+	return ctrl.Result{RequeueAfter: majorMigrationCutoverInterval}, nil
+}
+
+// reconcileCutover waits for the source cluster to become read-only,
+// terminates its client connections and records its WAL location. Once the
+// target cluster caught up, it points the Poolers to the target cluster,
+// removes the replication objects and fences the source cluster. When the
+// Poolers are kept paused for longer than the cutover timeout, they are
+// resumed, the source cluster is made writable again and the migration fails
+func (r *MajorMigrationReconciler) reconcileCutover(
+	ctx context.Context,
+	migration *apiv1.MajorMigration,
+	source *apiv1.Cluster,
+	target *apiv1.Cluster,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	cutover := migration.Status.Cutover
+	if cutover == nil {
+		return r.startCutover(ctx, migration, source)
+	}
+
+	if cutover.PoolersSwitchedAt == nil {
+		if timeout := migration.GetCutoverTimeout(); time.Since(cutover.StartedAt.Time) > timeout {
+			return ctrl.Result{}, r.failMigration(ctx, migration,
+				fmt.Errorf("the cutover did not complete within %s, the poolers have been resumed", timeout))
+		}
+
+		if cutover.WritesStoppedAt == nil {
+			// The WAL location is read only once the source cluster is
+			// read-only and its client connections have been terminated,
+			// so no transaction can be committed after it
+			lsn, err := r.stopSourceWrites(ctx, source)
+			if err != nil {
+				contextLogger.Info("Waiting for the writes on the source cluster to stop",
+					"err", err.Error(),
+					"elapsed", time.Since(cutover.StartedAt.Time))
+				return ctrl.Result{RequeueAfter: majorMigrationCutoverInterval}, nil
+			}
+
+			// The stop of the writes is recorded before going on, so the
+			// subscriptions will be checked against a persisted time
+			cutover.WritesStoppedAt = ptr.To(metav1.Now())
+			cutover.LSN = lsn
+			migration.Status.SetPhase(apiv1.MajorMigrationPhaseCuttingOver,
+				fmt.Sprintf("Waiting for the target cluster to reach the WAL location %s", cutover.LSN))
+			return ctrl.Result{RequeueAfter: majorMigrationCutoverInterval}, nil
+		}
+
+		subscriptions, err := r.getMajorMigrationSubscriptions(ctx, migration)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		databases := migration.GetDatabases(source)
+		migration.Status.Databases = buildMajorMigrationDatabasesStatus(databases, subscriptions, cutover.LSN)
+		if database := getMajorMigrationLaggingDatabase(databases, subscriptions, cutover); database != "" {
+			migration.Status.SetPhase(apiv1.MajorMigrationPhaseCuttingOver,
+				fmt.Sprintf("Waiting for database %s to reach the WAL location %s", database, cutover.LSN))
+			return ctrl.Result{RequeueAfter: majorMigrationCutoverInterval}, nil
+		}
+
+		if err := r.switchPoolers(ctx, migration, source, target); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		cutover.PoolersSwitchedAt = ptr.To(metav1.Now())
+		r.Recorder.Eventf(migration, "Normal", "PoolersSwitched",
+			"The poolers now point to cluster %s", target.Name)
+		migration.Status.SetPhase(apiv1.MajorMigrationPhaseCuttingOver, "Removing the replication objects")
+		return ctrl.Result{RequeueAfter: majorMigrationCutoverInterval}, nil
+	}
+
+	if deleted, err := r.deleteReplicationObjects(ctx, migration); err != nil || !deleted {
+		return ctrl.Result{RequeueAfter: majorMigrationCutoverInterval}, err
+	}
+
+	origSource := source.DeepCopy()
+	if fenced, err := utils.AddFencedInstance(utils.FenceAllInstances, source); err != nil {
+		return ctrl.Result{}, err
+	} else if fenced {
+		if err := r.Patch(ctx, source, client.MergeFrom(origSource)); err != nil {
+			return ctrl.Result{}, fmt.Errorf("while fencing the source cluster: %w", err)
+		}
+	}
+
+	r.Recorder.Eventf(migration, "Normal", "MigrationCompleted",
+		"Migrated to cluster %s, the source cluster %s has been fenced", target.Name, source.Name)
+	migration.Status.SetAsCompleted()
+	return ctrl.Result{}, nil
+}
+
+// setSourceReadOnly makes the new transactions on the source cluster
+// read-only, annotating it so that the change can be reverted if the
+// migration fails
+func (r *MajorMigrationReconciler) setSourceReadOnly(
+	ctx context.Context,
+	migration *apiv1.MajorMigration,
+	source *apiv1.Cluster,
+) error {
+	if source.Spec.PostgresConfiguration.Parameters[defaultTransactionReadOnlyParameter] == "on" {
+		return nil
+	}
+
+	origSource := source.DeepCopy()
+	if source.Annotations == nil {
+		source.Annotations = make(map[string]string)
+	}
+	source.Annotations[utils.ClusterReadOnlyForMigrationAnnotationName] = migration.Name
+	if source.Spec.PostgresConfiguration.Parameters == nil {
+		source.Spec.PostgresConfiguration.Parameters = make(map[string]string)
+	}
+	source.Spec.PostgresConfiguration.Parameters[defaultTransactionReadOnlyParameter] = "on"
+	if err := r.Patch(ctx, source, client.MergeFrom(origSource)); err != nil {
+		return fmt.Errorf("while making the source cluster read-only: %w", err)
+	}
+
+	return nil
+}
+
+// resetSourceReadOnly makes the source cluster writable again,
+// if it was made read-only by the migration
+func (r *MajorMigrationReconciler) resetSourceReadOnly(
+	ctx context.Context,
+	migration *apiv1.MajorMigration,
+) error {
+	var source apiv1.Cluster
+	if err := r.Get(ctx, types.NamespacedName{
+		Namespace: migration.Namespace,
+		Name:      migration.Spec.SourceCluster.Name,
+	}, &source); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	if source.Annotations[utils.ClusterReadOnlyForMigrationAnnotationName] != migration.Name {
+		return nil
+	}
+
+	origSource := source.DeepCopy()
+	delete(source.Annotations, utils.ClusterReadOnlyForMigrationAnnotationName)
+	delete(source.Spec.PostgresConfiguration.Parameters, defaultTransactionReadOnlyParameter)
+	if err := r.Patch(ctx, &source, client.MergeFrom(origSource)); err != nil {
+		return fmt.Errorf("while making the source cluster writable: %w", err)
+	}
+
+	return nil
+}
+
+// stopSourceWrites terminates the client connections of the primary of
+// the source cluster, once its new transactions are read-only, and
+// returns the WAL location where the writes stopped
+func (r *MajorMigrationReconciler) stopSourceWrites(
+	ctx context.Context,
+	source *apiv1.Cluster,
+) (string, error) {
+	var pod corev1.Pod
+	if err := r.Get(ctx, types.NamespacedName{
+		Namespace: source.Namespace,
+		Name:      source.Status.CurrentPrimary,
+	}, &pod); err != nil {
+		return "", fmt.Errorf("while getting the primary instance of cluster %s: %w", source.Name, err)
+	}
+
+	return r.instanceStatusClient.StopWrites(ctx, &pod)
+}
+
+// getMajorMigrationLaggingDatabase returns the first database that didn't
+// receive every change made before the writes stopped, including the
+// values of the sequences, or an empty string when all of them did
+func getMajorMigrationLaggingDatabase(
+	databases []string,
+	subscriptions map[string]*apiv1.Subscription,
+	cutover *apiv1.MajorMigrationCutoverStatus,
+) string {
+	for _, database := range databases {
+		subscription := subscriptions[database]
+		if subscription == nil || subscription.Status.Statistics == nil ||
+			subscription.Status.SequencesSyncedAt == nil {
+			return database
+		}
+
+		statistics := subscription.Status.Statistics
+		if !statistics.UpdateTime.After(cutover.WritesStoppedAt.Time) ||
+			!subscription.Status.SequencesSyncedAt.After(cutover.WritesStoppedAt.Time) ||
+			cnpgTypes.LSN(statistics.LatestEndLSN).Less(cnpgTypes.LSN(cutover.LSN)) {
+			return database
+		}
+	}
+
+	return ""
+}
+
+// switchPoolers points the Poolers paused for the cutover
+// to the target cluster, resuming them
+func (r *MajorMigrationReconciler) switchPoolers(
+	ctx context.Context,
+	migration *apiv1.MajorMigration,
+	source *apiv1.Cluster,
+	target *apiv1.Cluster,
+) error {
+	poolers, err := r.getSourcePoolers(ctx, migration)
+	if err != nil {
+		return err
+	}
+
+	for idx := range poolers.Items {
+		pooler := &poolers.Items[idx]
+		if pooler.Annotations[utils.PoolerPausedForMigrationAnnotationName] != migration.Name {
+			continue
+		}
+
+		origPooler := pooler.DeepCopy()
+		redirectPoolerDatabases(pooler, source.Name, target.Name)
+		delete(pooler.Annotations, utils.PoolerPausedForMigrationAnnotationName)
+		if err := r.Patch(ctx, pooler, client.MergeFrom(origPooler)); err != nil {
+			return fmt.Errorf("while pointing pooler %s to the target cluster: %w", pooler.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// redirectPoolerDatabases forwards the databases served by the source
+// cluster to the target one. As the cluster referenced by a Pooler
+// cannot be changed, a fallback entry is added when the Pooler
+// doesn't list its databases
+func redirectPoolerDatabases(pooler *apiv1.Pooler, sourceName, targetName string) {
+	if pooler.Spec.PgBouncer == nil {
+		return
+	}
+
+	if len(pooler.Spec.PgBouncer.Databases) == 0 {
+		if pooler.Spec.Cluster.Name == sourceName {
+			pooler.Spec.PgBouncer.Databases = []apiv1.PgBouncerDatabase{
+				{
+					Name:    "*",
+					Cluster: &apiv1.LocalObjectReference{Name: targetName},
+				},
+			}
+		}
+		return
+	}
+
+	for idx := range pooler.Spec.PgBouncer.Databases {
+		database := &pooler.Spec.PgBouncer.Databases[idx]
+		if database.GetClusterName(pooler) == sourceName {
+			database.Cluster = &apiv1.LocalObjectReference{Name: targetName}
+		}
+	}
+}
+
+// resumePoolers resumes the Poolers paused for the cutover of the migration
+func (r *MajorMigrationReconciler) resumePoolers(ctx context.Context, migration *apiv1.MajorMigration) error {
+	poolers, err := r.getSourcePoolers(ctx, migration)
+	if err != nil {
+		return err
+	}
+
+	for idx := range poolers.Items {
+		pooler := &poolers.Items[idx]
+		if pooler.Annotations[utils.PoolerPausedForMigrationAnnotationName] != migration.Name {
+			continue
+		}
+
+		origPooler := pooler.DeepCopy()
+		delete(pooler.Annotations, utils.PoolerPausedForMigrationAnnotationName)
+		if err := r.Patch(ctx, pooler, client.MergeFrom(origPooler)); err != nil {
+			return fmt.Errorf("while resuming pooler %s: %w", pooler.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// deleteReplicationObjects deletes the subscriptions and, once they
+// are gone, the publications created for the migration. It returns
+// true when every replication object has been removed
+func (r *MajorMigrationReconciler) deleteReplicationObjects(
+	ctx context.Context,
+	migration *apiv1.MajorMigration,
+) (bool, error) {
+	var subscriptions apiv1.SubscriptionList
+	if err := r.List(ctx, &subscriptions,
+		client.InNamespace(migration.Namespace),
+		client.MatchingLabels{utils.MajorMigrationLabelName: migration.Name},
+	); err != nil {
+		return false, err
+	}
+	if len(subscriptions.Items) > 0 {
+		for idx := range subscriptions.Items {
+			if err := r.deleteIfNotDeleting(ctx, &subscriptions.Items[idx]); err != nil {
+				return false, err
+			}
+		}
+		return false, nil
+	}
+
+	// The publications are removed after the subscriptions, which
+	// need the source cluster to drop their replication slots
+	var publications apiv1.PublicationList
+	if err := r.List(ctx, &publications,
+		client.InNamespace(migration.Namespace),
+		client.MatchingLabels{utils.MajorMigrationLabelName: migration.Name},
+	); err != nil {
+		return false, err
+	}
+	for idx := range publications.Items {
+		// The source cluster is read-only and cannot drop the publications,
+		// which are retained in its databases
+		publication := &publications.Items[idx]
+		if publication.Spec.ReclaimPolicy != apiv1.PublicationReclaimRetain {
+			origPublication := publication.DeepCopy()
+			publication.Spec.ReclaimPolicy = apiv1.PublicationReclaimRetain
+			if err := r.Patch(ctx, publication, client.MergeFrom(origPublication)); err != nil {
+				return false, fmt.Errorf("while retaining publication %s: %w", publication.Name, err)
+			}
+		}
+
+		if err := r.deleteIfNotDeleting(ctx, publication); err != nil {
+			return false, err
+		}
+	}
+
+	return len(publications.Items) == 0, nil
+}
+
+// deleteIfNotDeleting deletes the passed object, unless
+// its deletion has already been requested
+func (r *MajorMigrationReconciler) deleteIfNotDeleting(ctx context.Context, obj client.Object) error {
+	if !obj.GetDeletionTimestamp().IsZero() {
+		return nil
+	}
+
+	if err := r.Delete(ctx, obj); err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("while deleting %s: %w", obj.GetName(), err)
+	}
+
+	return nil
+}
+
+// getMajorMigrationSubscriptions returns the subscriptions
+// created for the migration, by database name
+func (r *MajorMigrationReconciler) getMajorMigrationSubscriptions(
+	ctx context.Context,
+	migration *apiv1.MajorMigration,
+) (map[string]*apiv1.Subscription, error) {
+	var subscriptions apiv1.SubscriptionList
+	if err := r.List(ctx, &subscriptions,
+		client.InNamespace(migration.Namespace),
+		client.MatchingLabels{utils.MajorMigrationLabelName: migration.Name},
+	); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*apiv1.Subscription, len(subscriptions.Items))
+	for idx := range subscriptions.Items {
+		result[subscriptions.Items[idx].Spec.DBName] = &subscriptions.Items[idx]
+	}
+
+	return result, nil
+}
+
+// getSourcePoolers returns the Poolers pointing to the source cluster
+func (r *MajorMigrationReconciler) getSourcePoolers(
+	ctx context.Context,
+	migration *apiv1.MajorMigration,
+) (*apiv1.PoolerList, error) {
+	var poolers apiv1.PoolerList
+	if err := r.List(ctx, &poolers,
+		client.InNamespace(migration.Namespace),
+		client.MatchingFields{poolerClusterKey: migration.Spec.SourceCluster.Name},
+	); err != nil {
+		return nil, fmt.Errorf("while getting poolers for cluster %s: %w", migration.Spec.SourceCluster.Name, err)
+	}
+
+	return &poolers, nil
+}
@@ -0,0 +1,352 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pooler/metricsserver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// poolerAutoscalingInterval is how often the pooler
+	// autoscaler evaluates the load of PgBouncer
+	poolerAutoscalingInterval = 15 * time.Second
+
+	// poolerAutoscalingTolerance is the relative distance from the target
+	// values within which the number of instances is not changed, to avoid
+	// continuously scaling around the target
+	poolerAutoscalingTolerance = 0.1
+
+	// poolerMetricsRequestTimeout is the timeout of the
+	// requests to the metrics endpoint of PgBouncer
+	poolerMetricsRequestTimeout = 5 * time.Second
+
+	poolerClientsWaitingMetric = "cl_waiting"
+	poolerMaxWaitMetric        = "maxwait"
+	poolerMaxWaitUsMetric      = "maxwait_us"
+)
+
+// poolerInstanceLoad is the load of a PgBouncer instance,
+// as reported by its metrics endpoint
+type poolerInstanceLoad struct {
+	// clientsWaiting is the number of clients waiting
+	// for a server connection, across every pool
+	clientsWaiting float64
+
+	// maxWait is the time the oldest client waited for a
+	// server connection, across every pool
+	maxWait time.Duration
+}
+
+// poolerLoadGetter retrieves the load of a PgBouncer instance
+type poolerLoadGetter func(ctx context.Context, pooler *apiv1.Pooler, pod *corev1.Pod) (*poolerInstanceLoad, error)
+
+// reconcileAutoscaling updates the number of PgBouncer instances requested
+// by the autoscaler depending on the clients waiting for a server
+// connection. It returns the time after which the load should be
+// evaluated again
+func (r *PoolerReconciler) reconcileAutoscaling(
+	ctx context.Context,
+	pooler *apiv1.Pooler,
+	resources *poolerManagedResources,
+) (time.Duration, error) {
+	autoscaling := pooler.Spec.Autoscaling
+	if autoscaling == nil {
+		if pooler.Status.Autoscaling == nil {
+			return 0, nil
+		}
+		return 0, r.patchAutoscalingStatus(ctx, pooler, nil)
+	}
+
+	contextLogger := log.FromContext(ctx).WithName("pooler_autoscaler")
+
+	// The load is evaluated only when every instance is running,
+	// as the ones being created or paused don't serve clients
+	currentInstances := *pooler.GetDesiredInstances()
+	if resources.Deployment == nil ||
+		pooler.Status.Phase != apiv1.PoolerPhaseActive ||
+		resources.Deployment.Status.ReadyReplicas != currentInstances ||
+		resources.Deployment.Status.UpdatedReplicas != currentInstances {
+		return poolerAutoscalingInterval, nil
+	}
+
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods,
+		client.InNamespace(pooler.Namespace),
+		client.MatchingLabels{utils.PgbouncerNameLabel: pooler.Name},
+	); err != nil {
+		return 0, fmt.Errorf("while listing the PgBouncer pods: %w", err)
+	}
+
+	getLoad := r.getPoolerLoad
+	if getLoad == nil {
+		getLoad = getPoolerInstanceLoad
+	}
+
+	var loads []poolerInstanceLoad
+	complete := true
+	for idx := range pods.Items {
+		pod := &pods.Items[idx]
+		if !utils.IsPodReady(*pod) {
+			continue
+		}
+
+		load, err := getLoad(ctx, pooler, pod)
+		if err != nil {
+			contextLogger.Info("Cannot get the load of a PgBouncer instance", "pod", pod.Name, "error", err)
+			complete = false
+			continue
+		}
+		loads = append(loads, *load)
+	}
+
+	desiredInstances, reason := computePoolerAutoscaling(autoscaling, currentInstances, loads, complete)
+	newStatus := &apiv1.PoolerAutoscalingStatus{DesiredInstances: currentInstances}
+	if pooler.Status.Autoscaling != nil {
+		newStatus = pooler.Status.Autoscaling.DeepCopy()
+		newStatus.DesiredInstances = currentInstances
+	}
+
+	now := metav1.Now()
+	switch {
+	case desiredInstances > currentInstances:
+		newStatus.ScaleDownRecommendedSince = nil
+
+	case desiredInstances == currentInstances:
+		newStatus.ScaleDownRecommendedSince = nil
+
+	case newStatus.ScaleDownRecommendedSince == nil:
+		// The load must allow fewer instances for the
+		// whole stabilization window before scaling down
+		newStatus.ScaleDownRecommendedSince = &now
+		desiredInstances = currentInstances
+
+	case now.Sub(newStatus.ScaleDownRecommendedSince.Time) < autoscaling.GetScaleDownStabilizationWindow():
+		desiredInstances = currentInstances
+
+	default:
+		newStatus.ScaleDownRecommendedSince = nil
+	}
+
+	if desiredInstances != currentInstances {
+		contextLogger.Info("Changing the number of PgBouncer instances",
+			"from", currentInstances, "to", desiredInstances, "reason", reason)
+		r.Recorder.Eventf(pooler, "Normal", "PoolerAutoscale",
+			"Scaling from %d to %d instances: %s", currentInstances, desiredInstances, reason)
+
+		newStatus.DesiredInstances = desiredInstances
+		newStatus.LastScaleTime = &now
+		newStatus.Reason = reason
+	}
+
+	if pooler.Status.Autoscaling == nil || !isSamePoolerAutoscalingStatus(*pooler.Status.Autoscaling, *newStatus) {
+		if err := r.patchAutoscalingStatus(ctx, pooler, newStatus); err != nil {
+			return 0, err
+		}
+	}
+
+	return poolerAutoscalingInterval, nil
+}
+
+// patchAutoscalingStatus stores the decision of the autoscaler in the Pooler status
+func (r *PoolerReconciler) patchAutoscalingStatus(
+	ctx context.Context,
+	pooler *apiv1.Pooler,
+	autoscalingStatus *apiv1.PoolerAutoscalingStatus,
+) error {
+	origPooler := pooler.DeepCopy()
+	pooler.Status.Autoscaling = autoscalingStatus
+	return r.Status().Patch(ctx, pooler, client.MergeFromWithOptions(origPooler, client.MergeFromWithOptimisticLock{}))
+}
+
+// isSamePoolerAutoscalingStatus checks if two autoscaler statuses are
+// the same, comparing the timestamps with the precision of the API server
+func isSamePoolerAutoscalingStatus(a, b apiv1.PoolerAutoscalingStatus) bool {
+	sameTime := func(a, b *metav1.Time) bool {
+		if a == nil || b == nil {
+			return a == b
+		}
+		return a.Unix() == b.Unix()
+	}
+
+	return a.DesiredInstances == b.DesiredInstances &&
+		a.Reason == b.Reason &&
+		sameTime(a.LastScaleTime, b.LastScaleTime) &&
+		sameTime(a.ScaleDownRecommendedSince, b.ScaleDownRecommendedSince)
+}
+
+// computePoolerAutoscaling computes the number of PgBouncer instances
+// needed to bring the load to the target values, like the
+// HorizontalPodAutoscaler does. PgBouncer is never scaled down when
+// the load of one of the instances is not available
+func computePoolerAutoscaling(
+	autoscaling *apiv1.PoolerAutoscalingConfiguration,
+	currentInstances int32,
+	loads []poolerInstanceLoad,
+	complete bool,
+) (int32, string) {
+	var reasons []string
+	desiredInstances := int32(0)
+	evaluated := false
+
+	evaluate := func(metric string, value, target float64, format string) {
+		evaluated = true
+		reasons = append(reasons, fmt.Sprintf("%s "+format+" (target "+format+")", metric, value, target))
+
+		instancesForMetric := currentInstances
+		if ratio := value / target; math.Abs(ratio-1) > poolerAutoscalingTolerance {
+			instancesForMetric = int32(math.Ceil(float64(len(loads)) * ratio))
+		}
+		desiredInstances = max(desiredInstances, instancesForMetric)
+	}
+
+	if len(loads) > 0 && autoscaling.TargetClientsWaiting != nil {
+		var clientsWaiting float64
+		for _, load := range loads {
+			clientsWaiting += load.clientsWaiting
+		}
+		evaluate("average clients waiting",
+			clientsWaiting/float64(len(loads)), float64(*autoscaling.TargetClientsWaiting), "%.1f")
+	}
+
+	if len(loads) > 0 && autoscaling.TargetMaxWait != nil && autoscaling.TargetMaxWait.Duration > 0 {
+		var maxWait time.Duration
+		for _, load := range loads {
+			maxWait = max(maxWait, load.maxWait)
+		}
+		evaluate("max wait", maxWait.Seconds(), autoscaling.TargetMaxWait.Seconds(), "%.1fs")
+	}
+
+	if !complete || !evaluated {
+		desiredInstances = max(desiredInstances, currentInstances)
+	}
+
+	switch {
+	case currentInstances < autoscaling.MinInstances:
+		reasons = append(reasons, "instances lower than the minimum")
+	case currentInstances > autoscaling.MaxInstances:
+		reasons = append(reasons, "instances greater than the maximum")
+	}
+
+	return min(max(desiredInstances, autoscaling.MinInstances), autoscaling.MaxInstances), strings.Join(reasons, ", ")
+}
+
+// getPoolerInstanceLoad retrieves the load of a PgBouncer
+// instance from its metrics endpoint
+func getPoolerInstanceLoad(ctx context.Context, pooler *apiv1.Pooler, pod *corev1.Pod) (*poolerInstanceLoad, error) {
+	scheme := "http"
+	httpClient := &http.Client{Timeout: poolerMetricsRequestTimeout}
+	if pooler.IsMetricsTLSEnabled() {
+		// Like the generated PodMonitor, the certificate is not verified, as
+		// the metrics endpoint is reached by IP address and only exposes
+		// aggregated statistics
+		scheme = "https"
+		httpClient.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{
+				MinVersion:         tls.VersionTLS13,
+				InsecureSkipVerify: true, //#nosec G402 -- see above
+			},
+		}
+	}
+
+	metricsURL := url.Build(scheme, pod.Status.PodIP, url.PathMetrics, url.PoolerMetricsPort)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metricsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpClient.Do(req) //nolint:gosec // URL built from internal pod IP
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	parser := expfmt.NewTextParser(model.UTF8Validation)
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("while parsing the metrics: %w", err)
+	}
+
+	return parsePoolerInstanceLoad(families, metricsserver.PoolsMetricsPrefix(pooler.GetBackend())), nil
+}
+
+// parsePoolerInstanceLoad extracts the load of a pooler instance
+// from the metrics it exposes, whose names start with the passed prefix
+func parsePoolerInstanceLoad(families map[string]*dto.MetricFamily, prefix string) *poolerInstanceLoad {
+	var result poolerInstanceLoad
+
+	if family, ok := families[prefix+poolerClientsWaitingMetric]; ok {
+		for _, metric := range family.GetMetric() {
+			result.clientsWaiting += metric.GetGauge().GetValue()
+		}
+	}
+
+	// The waiting time is exposed in two metrics, holding
+	// the seconds and the microseconds part
+	waitTimes := make(map[string]time.Duration)
+	if family, ok := families[prefix+poolerMaxWaitMetric]; ok {
+		for _, metric := range family.GetMetric() {
+			waitTimes[getPoolKey(metric)] += time.Duration(metric.GetGauge().GetValue()) * time.Second
+		}
+	}
+	if family, ok := families[prefix+poolerMaxWaitUsMetric]; ok {
+		for _, metric := range family.GetMetric() {
+			waitTimes[getPoolKey(metric)] += time.Duration(metric.GetGauge().GetValue()) * time.Microsecond
+		}
+	}
+	for _, waitTime := range waitTimes {
+		result.maxWait = max(result.maxWait, waitTime)
+	}
+
+	return &result
+}
+
+// getPoolKey identifies the pool a metric refers to
+func getPoolKey(metric *dto.Metric) string {
+	labels := make([]string, 0, len(metric.GetLabel()))
+	for _, label := range metric.GetLabel() {
+		labels = append(labels, label.GetName()+"="+label.GetValue())
+	}
+	return strings.Join(labels, ",")
+}
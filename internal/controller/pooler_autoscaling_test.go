@@ -0,0 +1,267 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pooler/metricsserver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("computePoolerAutoscaling", func() {
+	autoscaling := &apiv1.PoolerAutoscalingConfiguration{
+		MinInstances:         2,
+		MaxInstances:         10,
+		TargetClientsWaiting: ptr.To(int32(5)),
+		TargetMaxWait:        &metav1.Duration{Duration: 2 * time.Second},
+	}
+
+	It("scales up when the clients are waiting", func() {
+		desired, reason := computePoolerAutoscaling(autoscaling, 2, []poolerInstanceLoad{
+			{clientsWaiting: 20},
+			{clientsWaiting: 10},
+		}, true)
+		Expect(desired).To(Equal(int32(6)))
+		Expect(reason).To(ContainSubstring("average clients waiting 15.0 (target 5.0)"))
+	})
+
+	It("scales up when the clients are waiting for too long", func() {
+		desired, reason := computePoolerAutoscaling(autoscaling, 3, []poolerInstanceLoad{
+			{maxWait: time.Second},
+			{maxWait: 4 * time.Second},
+			{},
+		}, true)
+		Expect(desired).To(Equal(int32(6)))
+		Expect(reason).To(ContainSubstring("max wait 4.0s (target 2.0s)"))
+	})
+
+	It("scales down to the minimum when no client is waiting", func() {
+		desired, _ := computePoolerAutoscaling(autoscaling, 4, []poolerInstanceLoad{{}, {}, {}, {}}, true)
+		Expect(desired).To(Equal(int32(2)))
+	})
+
+	It("doesn't change the instances when the load is close to the target", func() {
+		desired, _ := computePoolerAutoscaling(autoscaling, 3, []poolerInstanceLoad{
+			{clientsWaiting: 5},
+			{clientsWaiting: 5},
+			{clientsWaiting: 5.2},
+		}, true)
+		Expect(desired).To(Equal(int32(3)))
+	})
+
+	It("never scales down when the load of an instance is unknown", func() {
+		desired, _ := computePoolerAutoscaling(autoscaling, 4, []poolerInstanceLoad{{}, {}}, false)
+		Expect(desired).To(Equal(int32(4)))
+	})
+
+	It("respects the maximum number of instances", func() {
+		desired, _ := computePoolerAutoscaling(autoscaling, 8, []poolerInstanceLoad{
+			{clientsWaiting: 100},
+		}, true)
+		Expect(desired).To(Equal(int32(10)))
+	})
+})
+
+var _ = Describe("parsePoolerInstanceLoad", func() {
+	It("sums the clients waiting and takes the longest wait", func() {
+		const metrics = `# TYPE cnpg_pgbouncer_pools_cl_waiting gauge
+cnpg_pgbouncer_pools_cl_waiting{database="app",user="app"} 3
+cnpg_pgbouncer_pools_cl_waiting{database="pgbouncer",user="pgbouncer"} 0
+cnpg_pgbouncer_pools_cl_waiting{database="reports",user="app"} 4
+# TYPE cnpg_pgbouncer_pools_maxwait gauge
+cnpg_pgbouncer_pools_maxwait{database="app",user="app"} 1
+cnpg_pgbouncer_pools_maxwait{database="reports",user="app"} 1
+# TYPE cnpg_pgbouncer_pools_maxwait_us gauge
+cnpg_pgbouncer_pools_maxwait_us{database="app",user="app"} 200000
+cnpg_pgbouncer_pools_maxwait_us{database="reports",user="app"} 500000
+`
+		parser := expfmt.NewTextParser(model.UTF8Validation)
+		families, err := parser.TextToMetricFamilies(strings.NewReader(metrics))
+		Expect(err).ToNot(HaveOccurred())
+
+		load := parsePoolerInstanceLoad(families, metricsserver.PoolsMetricsPrefix(apiv1.PoolerBackendPgBouncer))
+		Expect(load.clientsWaiting).To(BeEquivalentTo(7))
+		Expect(load.maxWait).To(Equal(1500 * time.Millisecond))
+	})
+
+	It("reads the metrics exposed by PgCat", func() {
+		const metrics = `# TYPE cnpg_pgcat_pools_cl_waiting gauge
+cnpg_pgcat_pools_cl_waiting{database="app",user="app"} 2
+cnpg_pgcat_pools_cl_waiting{database="reports",user="app"} 1
+# TYPE cnpg_pgcat_pools_maxwait gauge
+cnpg_pgcat_pools_maxwait{database="app",user="app"} 2
+# TYPE cnpg_pgcat_pools_maxwait_us gauge
+cnpg_pgcat_pools_maxwait_us{database="app",user="app"} 100000
+`
+		parser := expfmt.NewTextParser(model.UTF8Validation)
+		families, err := parser.TextToMetricFamilies(strings.NewReader(metrics))
+		Expect(err).ToNot(HaveOccurred())
+
+		load := parsePoolerInstanceLoad(families, metricsserver.PoolsMetricsPrefix(apiv1.PoolerBackendPgCat))
+		Expect(load.clientsWaiting).To(BeEquivalentTo(3))
+		Expect(load.maxWait).To(Equal(2100 * time.Millisecond))
+
+		load = parsePoolerInstanceLoad(families, metricsserver.PoolsMetricsPrefix(apiv1.PoolerBackendPgBouncer))
+		Expect(load.clientsWaiting).To(BeZero())
+	})
+})
+
+var _ = Describe("reconcileAutoscaling", func() {
+	var (
+		env       *testingEnvironment
+		pooler    *apiv1.Pooler
+		resources *poolerManagedResources
+		loads     map[string]*poolerInstanceLoad
+	)
+
+	BeforeEach(func(ctx context.Context) {
+		env = buildTestEnvironment()
+		namespace := newFakeNamespace(env.client)
+		cluster := newFakeCNPGCluster(env.client, namespace)
+		pooler = newFakePooler(env.client, cluster)
+
+		pooler.Spec.Instances = ptr.To(int32(2))
+		pooler.Spec.Autoscaling = &apiv1.PoolerAutoscalingConfiguration{
+			MinInstances:                 1,
+			MaxInstances:                 5,
+			TargetClientsWaiting:         ptr.To(int32(5)),
+			ScaleDownStabilizationWindow: &metav1.Duration{Duration: time.Hour},
+		}
+		Expect(env.client.Update(ctx, pooler)).To(Succeed())
+		pooler.Status.Phase = apiv1.PoolerPhaseActive
+		Expect(env.client.Status().Update(ctx, pooler)).To(Succeed())
+
+		loads = map[string]*poolerInstanceLoad{}
+		for _, name := range []string{"pooler-a", "pooler-b"} {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+					Labels:    map[string]string{utils.PgbouncerNameLabel: pooler.Name},
+				},
+			}
+			Expect(env.client.Create(ctx, pod)).To(Succeed())
+			pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+			Expect(env.client.Status().Update(ctx, pod)).To(Succeed())
+			loads[name] = &poolerInstanceLoad{}
+		}
+
+		resources = &poolerManagedResources{
+			Deployment: &appsv1.Deployment{
+				Status: appsv1.DeploymentStatus{ReadyReplicas: 2, UpdatedReplicas: 2},
+			},
+		}
+		env.poolerReconciler.getPoolerLoad = func(
+			_ context.Context,
+			_ *apiv1.Pooler,
+			pod *corev1.Pod,
+		) (*poolerInstanceLoad, error) {
+			if load := loads[pod.Name]; load != nil {
+				return load, nil
+			}
+			return nil, errors.New("unreachable")
+		}
+	})
+
+	It("scales up immediately", func(ctx context.Context) {
+		loads["pooler-a"].clientsWaiting = 20
+		loads["pooler-b"].clientsWaiting = 20
+
+		interval, err := env.poolerReconciler.reconcileAutoscaling(ctx, pooler, resources)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(interval).To(Equal(poolerAutoscalingInterval))
+
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(pooler), pooler)).To(Succeed())
+		Expect(pooler.Status.Autoscaling.DesiredInstances).To(Equal(int32(5)))
+		Expect(pooler.Status.Autoscaling.LastScaleTime).ToNot(BeNil())
+		Expect(*pooler.GetDesiredInstances()).To(Equal(int32(5)))
+	})
+
+	It("waits for the stabilization window before scaling down", func(ctx context.Context) {
+		_, err := env.poolerReconciler.reconcileAutoscaling(ctx, pooler, resources)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(pooler), pooler)).To(Succeed())
+		Expect(pooler.Status.Autoscaling.DesiredInstances).To(Equal(int32(2)))
+		Expect(pooler.Status.Autoscaling.ScaleDownRecommendedSince).ToNot(BeNil())
+
+		pooler.Status.Autoscaling.ScaleDownRecommendedSince = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+		Expect(env.client.Status().Update(ctx, pooler)).To(Succeed())
+
+		_, err = env.poolerReconciler.reconcileAutoscaling(ctx, pooler, resources)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(pooler), pooler)).To(Succeed())
+		Expect(pooler.Status.Autoscaling.DesiredInstances).To(Equal(int32(1)))
+		Expect(pooler.Status.Autoscaling.ScaleDownRecommendedSince).To(BeNil())
+	})
+
+	It("resets the stabilization window when the load increases", func(ctx context.Context) {
+		pooler.Status.Autoscaling = &apiv1.PoolerAutoscalingStatus{
+			DesiredInstances:          2,
+			ScaleDownRecommendedSince: &metav1.Time{Time: time.Now().Add(-time.Minute)},
+		}
+		Expect(env.client.Status().Update(ctx, pooler)).To(Succeed())
+		loads["pooler-a"].clientsWaiting = 5
+		loads["pooler-b"].clientsWaiting = 5
+
+		_, err := env.poolerReconciler.reconcileAutoscaling(ctx, pooler, resources)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(pooler), pooler)).To(Succeed())
+		Expect(pooler.Status.Autoscaling.DesiredInstances).To(Equal(int32(2)))
+		Expect(pooler.Status.Autoscaling.ScaleDownRecommendedSince).To(BeNil())
+	})
+
+	It("doesn't scale down when an instance is unreachable", func(ctx context.Context) {
+		delete(loads, "pooler-b")
+
+		_, err := env.poolerReconciler.reconcileAutoscaling(ctx, pooler, resources)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(pooler), pooler)).To(Succeed())
+		Expect(pooler.Status.Autoscaling.DesiredInstances).To(Equal(int32(2)))
+		Expect(pooler.Status.Autoscaling.ScaleDownRecommendedSince).To(BeNil())
+	})
+
+	It("doesn't evaluate the load while the instances are not ready", func(ctx context.Context) {
+		resources.Deployment.Status.ReadyReplicas = 1
+
+		_, err := env.poolerReconciler.reconcileAutoscaling(ctx, pooler, resources)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pooler.Status.Autoscaling).To(BeNil())
+	})
+})
@@ -54,6 +54,10 @@ type PoolerReconciler struct {
 	Scheme          *runtime.Scheme
 	Recorder        record.EventRecorder
 	Admission       *guard.Admission[*apiv1.Pooler]
+
+	// getPoolerLoad retrieves the load of a PgBouncer instance,
+	// defaulting to the metrics endpoint of the instance
+	getPoolerLoad poolerLoadGetter
 }
 
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=poolers,verbs=get;list;watch;create;update;patch;delete
@@ -147,12 +151,23 @@ func (r *PoolerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return ctrl.Result{}, fmt.Errorf("while updating pooler status: %w", err)
 	}
 
+	// Evaluate the load of PgBouncer, updating the number of
+	// instances requested by the autoscaler
+	autoscalingInterval, err := r.reconcileAutoscaling(ctx, &pooler, resources)
+	if err != nil {
+		if apierrs.IsConflict(err) {
+			contextLogger.Debug("Conflict while updating the pooler autoscaling status", "error", err)
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("while evaluating the pooler autoscaling: %w", err)
+	}
+
 	// Take the required actions to align the spec with the collected status.
 	// When the pgbouncer image cannot be resolved (Phase=Failed) updateDeployment
 	// is a no-op, but service accounts, RBAC, services and PodMonitor are still
 	// reconciled, so drift in those resources is corrected even when the catalog
 	// reference is misconfigured.
-	return ctrl.Result{}, r.updateOwnedObjects(ctx, &pooler, resources)
+	return ctrl.Result{RequeueAfter: autoscalingInterval}, r.updateOwnedObjects(ctx, &pooler, resources)
 }
 
 // SetupWithManager setup this controller inside the controller manager
@@ -333,6 +348,26 @@ func (r *PoolerReconciler) waitForPrerequisites(
 				pooler.GetServerCASecretNameOrDefault(resources.Cluster)))
 	}
 
+	// The other clusters serving the databases of the pooler
+	for _, clusterResources := range resources.AdditionalClusters {
+		if clusterResources.Cluster == nil {
+			return r.markInactiveAndWait(ctx, pooler,
+				fmt.Sprintf("Cluster %q not found", clusterResources.Name))
+		}
+
+		if clusterResources.ServerCASecret == nil {
+			return r.markInactiveAndWait(ctx, pooler,
+				fmt.Sprintf("ServerCASecret %q not found",
+					clusterResources.Cluster.GetServerCASecretName()))
+		}
+
+		if pooler.IsAutomatedIntegration() && clusterResources.AuthUserSecret == nil {
+			return r.markInactiveAndWait(ctx, pooler,
+				fmt.Sprintf("AuthUserSecret %q not found",
+					pooler.GetAuthQuerySecretNameForCluster(clusterResources.Name)))
+		}
+	}
+
 	return nil
 }
 
@@ -411,7 +446,7 @@ func getPoolersUsingSecret(poolers apiv1.PoolerList, secret *corev1.Secret) (req
 			continue
 		}
 
-		if pooler.Spec.PgBouncer != nil && pooler.GetAuthQuerySecretName() == secret.Name {
+		if pooler.GetAuthQuerySecretName() == secret.Name {
 			requests = append(requests,
 				types.NamespacedName{
 					Name:      pooler.Name,
@@ -430,6 +465,42 @@ func getPoolersUsingSecret(poolers apiv1.PoolerList, secret *corev1.Secret) (req
 			)
 			continue
 		}
+
+		if isSecretUsedForPoolerClusters(&pooler, secret.Name) {
+			requests = append(requests,
+				types.NamespacedName{
+					Name:      pooler.Name,
+					Namespace: pooler.Namespace,
+				},
+			)
+			continue
+		}
 	}
 	return requests
 }
+
+// isSecretUsedForPoolerClusters checks if the secret is used by the pooler
+// to connect to one of the other clusters serving its databases
+func isSecretUsedForPoolerClusters(pooler *apiv1.Pooler, secretName string) bool {
+	if pooler.Spec.PgBouncer == nil {
+		return false
+	}
+
+	for _, clusterName := range pooler.GetReferencedClusterNames()[1:] {
+		if pooler.GetAuthQuerySecretNameForCluster(clusterName) == secretName {
+			return true
+		}
+	}
+
+	if pooler.Status.Secrets == nil {
+		return false
+	}
+
+	for _, clusterSecrets := range pooler.Status.Secrets.Clusters {
+		if clusterSecrets.ServerCA.Name == secretName {
+			return true
+		}
+	}
+
+	return false
+}
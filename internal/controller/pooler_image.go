@@ -35,23 +35,28 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 )
 
-// resolvePoolerImage determines the pgbouncer image that the resulting
+// resolvePoolerImage determines the pooler image that the resulting
 // Deployment will run, applying the following priority (highest wins):
 //
-//  1. an explicit image set on the pgbouncer container in spec.template
-//  2. spec.pgbouncer.image
-//  3. spec.pgbouncer.imageCatalogRef
-//  4. operator default (config.Current.PgbouncerImageName)
+//  1. an explicit image set on the pooler container in spec.template
+//  2. spec.pgcat.image, when PgCat is used
+//  3. spec.pgbouncer.image
+//  4. spec.pgbouncer.imageCatalogRef
+//  5. operator default (config.Current.PgbouncerImageName)
 //
 // The pod-template override is honored here so that status.image always
 // matches what is actually written into the Deployment by the builder
-// (which keeps any image already set on the pgbouncer container in
+// (which keeps any image already set on the pooler container in
 // spec.template).
 func (r *PoolerReconciler) resolvePoolerImage(ctx context.Context, pooler *apiv1.Pooler) (string, error) {
-	if image := podTemplatePgbouncerImage(pooler); image != "" {
+	if image := podTemplatePoolerImage(pooler); image != "" {
 		return image, nil
 	}
 
+	if pooler.Spec.PgCat != nil {
+		return pooler.Spec.PgCat.Image, nil
+	}
+
 	if pooler.Spec.PgBouncer == nil {
 		return configuration.Current.PgbouncerImageName, nil
 	}
@@ -67,27 +72,26 @@ func (r *PoolerReconciler) resolvePoolerImage(ctx context.Context, pooler *apiv1
 	return configuration.Current.PgbouncerImageName, nil
 }
 
-// podTemplatePgbouncerImage returns a non-empty image when the user has pinned
-// the pgbouncer container image in spec.template; the deployment builder
+// podTemplatePoolerImage returns a non-empty image when the user has pinned
+// the pooler container image in spec.template; the deployment builder
 // preserves that value, so it must win over every other resolution source.
-func podTemplatePgbouncerImage(pooler *apiv1.Pooler) string {
+func podTemplatePoolerImage(pooler *apiv1.Pooler) string {
 	if pooler.Spec.Template == nil {
 		return ""
 	}
 	for _, c := range pooler.Spec.Template.Spec.Containers {
-		if c.Name == "pgbouncer" {
+		if c.Name == string(pooler.GetBackend()) {
 			return c.Image
 		}
 	}
 	return ""
 }
 
-// isPgBouncerPaused reports whether the user has requested PgBouncer to pause
-// new client connections via spec.pgbouncer.paused.
+// isPgBouncerPaused reports whether PgBouncer is pausing new client
+// connections, either via spec.pgbouncer.paused or because the operator
+// is draining the connections before a switchover.
 func isPgBouncerPaused(pooler *apiv1.Pooler) bool {
-	return pooler.Spec.PgBouncer != nil &&
-		pooler.Spec.PgBouncer.Paused != nil &&
-		*pooler.Spec.PgBouncer.Paused
+	return pooler.ShouldBePaused()
 }
 
 func (r *PoolerReconciler) resolveImageFromCatalog(ctx context.Context, pooler *apiv1.Pooler) (string, error) {
@@ -116,6 +116,28 @@ var _ = Describe("resolvePoolerImage", func() {
 		Expect(image).To(Equal("explicit:9"))
 	})
 
+	It("uses spec.pgcat.image when running PgCat", func() {
+		pooler := newPooler()
+		pooler.Spec.PgBouncer = nil
+		pooler.Spec.PgCat = &apiv1.PgCatSpec{Image: "pgcat:1"}
+
+		image, err := env.poolerReconciler.resolvePoolerImage(context.Background(), pooler)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(image).To(Equal("pgcat:1"))
+
+		pooler.Spec.Template = &apiv1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "pgbouncer", Image: "pgbouncer:1"},
+					{Name: "pgcat", Image: "pgcat-from-template:2"},
+				},
+			},
+		}
+		image, err = env.poolerReconciler.resolvePoolerImage(context.Background(), pooler)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(image).To(Equal("pgcat-from-template:2"))
+	})
+
 	Context("with imageCatalogRef", func() {
 		const (
 			imageCatalogName        = "pgbouncer-catalog"
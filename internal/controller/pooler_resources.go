@@ -61,6 +61,9 @@ type poolerManagedResources struct {
 	// The referenced Cluster
 	Cluster *apiv1.Cluster
 
+	// The other clusters serving the databases of the pooler
+	AdditionalClusters []poolerClusterResources
+
 	// The RBAC resources needed for the pooler instance manager
 	// to watch over the relative Pooler resource
 	ServiceAccount *corev1.ServiceAccount
@@ -68,6 +71,24 @@ type poolerManagedResources struct {
 	Role           *rbacv1.Role
 }
 
+// poolerClusterResources contains the resources needed to connect
+// to a cluster, other than the referenced one, serving some of the
+// databases of the pooler
+type poolerClusterResources struct {
+	// The name of the cluster
+	Name string
+
+	// The cluster, nil if it doesn't exist
+	Cluster *apiv1.Cluster
+
+	// The root certificate to validate the cluster server certificates
+	ServerCASecret *corev1.Secret
+
+	// The secret used to authenticate the auth_query connection
+	// against the cluster
+	AuthUserSecret *corev1.Secret
+}
+
 // getManagedResources detects the list of the resources created and managed
 // by this pooler. The caller is responsible for resolving the referenced
 // Cluster and passing it in: this function requires a non-nil Cluster.
@@ -134,6 +155,16 @@ func (r *PoolerReconciler) getManagedResources(
 		return nil, err
 	}
 
+	// Get the resources needed to connect to the other clusters
+	// serving the databases of the pooler
+	for _, clusterName := range pooler.GetReferencedClusterNames()[1:] {
+		clusterResources, err := r.getPoolerClusterResources(ctx, pooler, clusterName)
+		if err != nil {
+			return nil, err
+		}
+		result.AdditionalClusters = append(result.AdditionalClusters, *clusterResources)
+	}
+
 	// Get the pooler deployment
 	result.Deployment, err = getDeploymentOrNil(
 		ctx, r.Client, client.ObjectKey{Name: pooler.Name, Namespace: pooler.Namespace})
@@ -173,6 +204,47 @@ func (r *PoolerReconciler) getManagedResources(
 	return result, nil
 }
 
+// getPoolerClusterResources gets the resources needed to connect to a
+// cluster, other than the referenced one, serving some of the databases
+// of the pooler
+func (r *PoolerReconciler) getPoolerClusterResources(
+	ctx context.Context,
+	pooler *apiv1.Pooler,
+	clusterName string,
+) (*poolerClusterResources, error) {
+	result := &poolerClusterResources{Name: clusterName}
+
+	cluster, err := getClusterOrNil(ctx, r.Client, client.ObjectKey{Name: clusterName, Namespace: pooler.Namespace})
+	if err != nil {
+		return nil, err
+	}
+	if cluster == nil {
+		return result, nil
+	}
+	result.Cluster = cluster
+
+	result.ServerCASecret, err = getSecretOrNil(
+		ctx, r.Client, client.ObjectKey{Name: cluster.GetServerCASecretName(), Namespace: pooler.Namespace})
+	if err != nil {
+		return nil, err
+	}
+
+	// When PgBouncer authenticates with a client certificate
+	// there is no auth query secret to load
+	if pooler.GetServerTLSSecretName() == "" {
+		result.AuthUserSecret, err = getSecretOrNil(
+			ctx, r.Client, client.ObjectKey{
+				Name:      pooler.GetAuthQuerySecretNameForCluster(clusterName),
+				Namespace: pooler.Namespace,
+			})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
 // getDeploymentOrNil gets a deployment with a certain name, returning nil when it doesn't exist
 func getDeploymentOrNil(
 	ctx context.Context, r client.Client, objectKey client.ObjectKey,
@@ -89,6 +89,30 @@ func (r *PoolerReconciler) updatePoolerStatus(
 		updatedStatus.Secrets.ServerTLS = apiv1.SecretVersion{}
 	}
 
+	updatedStatus.Secrets.Clusters = nil
+	for _, clusterResources := range resources.AdditionalClusters {
+		clusterSecrets := apiv1.PoolerClusterSecrets{Cluster: clusterResources.Name}
+		if clusterResources.ServerCASecret != nil {
+			clusterSecrets.ServerCA = apiv1.SecretVersion{
+				Name:    clusterResources.ServerCASecret.Name,
+				Version: clusterResources.ServerCASecret.ResourceVersion,
+			}
+		}
+		if clusterResources.AuthUserSecret != nil {
+			clusterSecrets.AuthQuery = apiv1.SecretVersion{
+				Name:    clusterResources.AuthUserSecret.Name,
+				Version: clusterResources.AuthUserSecret.ResourceVersion,
+			}
+		}
+		updatedStatus.Secrets.Clusters = append(updatedStatus.Secrets.Clusters, clusterSecrets)
+	}
+
+	// The replicas receiving the connections are managed
+	// by the PgBouncer instance manager
+	if !pooler.IsReplicaLoadBalancingEnabled() {
+		updatedStatus.ReplicaLoadBalancing = nil
+	}
+
 	if resources.Deployment != nil {
 		updatedStatus.Instances = resources.Deployment.Status.Replicas
 	}
@@ -62,12 +62,13 @@ func TestAPIs(t *testing.T) {
 }
 
 type testingEnvironment struct {
-	backupReconciler  *BackupReconciler
-	scheme            *runtime.Scheme
-	clusterReconciler *ClusterReconciler
-	poolerReconciler  *PoolerReconciler
-	discoveryClient   *fakediscovery.FakeDiscovery
-	client            client.WithWatch
+	backupReconciler         *BackupReconciler
+	scheme                   *runtime.Scheme
+	clusterReconciler        *ClusterReconciler
+	poolerReconciler         *PoolerReconciler
+	majorMigrationReconciler *MajorMigrationReconciler
+	discoveryClient          *fakediscovery.FakeDiscovery
+	client                   client.WithWatch
 }
 
 func buildTestEnvironment() *testingEnvironment {
@@ -76,8 +77,9 @@ func buildTestEnvironment() *testingEnvironment {
 
 	scheme := schemeBuilder.BuildWithAllKnownScheme()
 	k8sClient := fake.NewClientBuilder().WithScheme(scheme).
-		WithStatusSubresource(&apiv1.Cluster{}, &apiv1.Backup{}, &apiv1.Pooler{}, &corev1.Service{},
-			&corev1.ConfigMap{}, &corev1.Secret{}).
+		WithStatusSubresource(&apiv1.Cluster{}, &apiv1.Backup{}, &apiv1.Pooler{}, &apiv1.Switchover{}, &corev1.Service{},
+			&corev1.ConfigMap{}, &corev1.Secret{}, &apiv1.MajorMigration{}, &apiv1.Publication{},
+			&apiv1.Subscription{}).
 		WithIndex(&batchv1.Job{}, jobOwnerKey, jobOwnerIndexFunc).
 		WithIndex(&apiv1.Backup{}, ".spec.cluster.name", func(rawObj client.Object) []string {
 			return []string{rawObj.(*apiv1.Backup).Spec.Cluster.Name}
@@ -85,6 +87,9 @@ func buildTestEnvironment() *testingEnvironment {
 		WithIndex(&apiv1.Backup{}, backupPhase, func(rawObj client.Object) []string {
 			return []string{string(rawObj.(*apiv1.Backup).Status.Phase)}
 		}).
+		WithIndex(&apiv1.Pooler{}, poolerClusterKey, func(rawObj client.Object) []string {
+			return rawObj.(*apiv1.Pooler).GetReferencedClusterNames()
+		}).
 		Build()
 	Expect(err).ToNot(HaveOccurred())
 
@@ -126,13 +131,20 @@ func buildTestEnvironment() *testingEnvironment {
 		Recorder: record.NewFakeRecorder(120),
 	}
 
+	majorMigrationReconciler := &MajorMigrationReconciler{
+		Client:   k8sClient,
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(120),
+	}
+
 	return &testingEnvironment{
-		scheme:            scheme,
-		client:            k8sClient,
-		clusterReconciler: clusterReconciler,
-		backupReconciler:  backupReconciler,
-		poolerReconciler:  poolerReconciler,
-		discoveryClient:   discoveryClient,
+		scheme:                   scheme,
+		client:                   k8sClient,
+		clusterReconciler:        clusterReconciler,
+		backupReconciler:         backupReconciler,
+		poolerReconciler:         poolerReconciler,
+		majorMigrationReconciler: majorMigrationReconciler,
+		discoveryClient:          discoveryClient,
 	}
 }
 
@@ -26,6 +26,8 @@ import (
 	"time"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -51,6 +53,7 @@ type DatabaseReconciler struct {
 
 	getSuperUserDB func() (*sql.DB, error)
 	getTargetDB    func(dbname string) (*sql.DB, error)
+	restoreDump    restoreDumpFunc
 }
 
 // ErrFailedDatabaseObjectReconciliation is raised when a database object failed to reconcile
@@ -88,6 +91,30 @@ var serverObjectManager = databaseObjectManager[apiv1.ServerSpec, serverInfo]{
 	drop:   dropDatabaseForeignServer,
 }
 
+// userMappingObjectManager is the manager of the user mappings
+var userMappingObjectManager = databaseObjectManager[databaseUserMapping, userMappingInfo]{
+	get:    getDatabaseUserMappingInfo,
+	create: createDatabaseUserMapping,
+	update: updateDatabaseUserMapping,
+	drop:   dropDatabaseUserMapping,
+}
+
+// grantObjectManager is the manager of the privileges
+var grantObjectManager = databaseObjectManager[databaseGrant, privilegeInfo]{
+	get:    getDatabaseGrantInfo,
+	create: createDatabaseGrant,
+	update: updateDatabaseGrant,
+	drop:   dropDatabaseGrant,
+}
+
+// defaultPrivilegeObjectManager is the manager of the default privileges
+var defaultPrivilegeObjectManager = databaseObjectManager[databaseDefaultPrivilege, privilegeInfo]{
+	get:    getDatabaseDefaultPrivilegeInfo,
+	create: createDatabaseDefaultPrivilege,
+	update: updateDatabaseDefaultPrivilege,
+	drop:   dropDatabaseDefaultPrivilege,
+}
+
 // databaseReconciliationInterval is the time between the
 // database reconciliation loop failures
 const databaseReconciliationInterval = 30 * time.Second
@@ -149,9 +176,16 @@ func (r *DatabaseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		// evaluate the database again after the promotion.
 		result, proceed, err := handleReplicaRoleTransition(
 			ctx, r.Client, r.instance, cluster, &database, databaseReconciliationInterval)
-		if err != nil || !proceed {
+		if err != nil {
 			return result, err
 		}
+		if !proceed {
+			// ...or unless the secrets used by the user mappings changed
+			result, proceed, err = r.checkUserMappingSecrets(ctx, cluster, &database, result)
+			if err != nil || !proceed {
+				return result, err
+			}
+		}
 	}
 
 	contextLogger.Info("Reconciling database")
@@ -259,6 +293,7 @@ func NewDatabaseReconciler(
 		getTargetDB: func(dbname string) (*sql.DB, error) {
 			return instance.ConnectionPool().Connection(dbname)
 		},
+		restoreDump: newRestoreDumpFunc(instance),
 	}
 
 	dr.finalizerReconciler = newFinalizerReconciler(
@@ -304,6 +339,10 @@ func (r *DatabaseReconciler) reconcileDatabaseResource(ctx context.Context, obj
 		return err
 	}
 
+	if err := r.reconcileDatabaseRestore(ctx, obj); err != nil {
+		return err
+	}
+
 	if err := r.reconcileDatabaseObjects(ctx, obj); err != nil {
 		return err
 	}
@@ -328,6 +367,21 @@ func (r *DatabaseReconciler) reconcileDatabaseResource(ctx context.Context, obj
 			return ErrFailedDatabaseObjectReconciliation
 		}
 	}
+	for _, status := range obj.Status.UserMappings {
+		if !status.Applied {
+			return ErrFailedDatabaseObjectReconciliation
+		}
+	}
+	for _, status := range obj.Status.Grants {
+		if !status.Applied {
+			return ErrFailedDatabaseObjectReconciliation
+		}
+	}
+	for _, status := range obj.Status.DefaultPrivileges {
+		if !status.Applied {
+			return ErrFailedDatabaseObjectReconciliation
+		}
+	}
 
 	return nil
 }
@@ -340,6 +394,8 @@ func (r *DatabaseReconciler) reconcileDatabaseObjects(
 	objectCount += len(obj.Spec.Extensions)
 	objectCount += len(obj.Spec.FDWs)
 	objectCount += len(obj.Spec.Servers)
+	objectCount += len(obj.Spec.Grants)
+	objectCount += len(obj.Spec.DefaultPrivileges)
 
 	if objectCount == 0 {
 		return nil
@@ -353,11 +409,153 @@ func (r *DatabaseReconciler) reconcileDatabaseObjects(
 	obj.Status.Schemas = schemaObjectManager.reconcileList(ctx, db, obj.Spec.Schemas)
 	obj.Status.Extensions = extensionObjectManager.reconcileList(ctx, db, obj.Spec.Extensions)
 	obj.Status.FDWs = fdwObjectManager.reconcileList(ctx, db, obj.Spec.FDWs)
+
+	// The user mappings of the servers to be dropped are dropped
+	// beforehand, as they would prevent the servers from being dropped
+	var droppedUserMappings, userMappings []databaseUserMapping
+	secretResourceVersions := make(map[string]string)
+	for i := range obj.Spec.Servers {
+		server := &obj.Spec.Servers[i]
+		serverUserMappings := r.getUserMappings(ctx, obj.Namespace, server, secretResourceVersions)
+		if server.Ensure == apiv1.EnsureAbsent {
+			droppedUserMappings = append(droppedUserMappings, serverUserMappings...)
+		} else {
+			userMappings = append(userMappings, serverUserMappings...)
+		}
+	}
+	obj.Status.UserMappings = userMappingObjectManager.reconcileList(ctx, db, droppedUserMappings)
 	obj.Status.Servers = serverObjectManager.reconcileList(ctx, db, obj.Spec.Servers)
+	obj.Status.UserMappings = append(
+		obj.Status.UserMappings,
+		userMappingObjectManager.reconcileList(ctx, db, userMappings)...)
+	obj.Status.SecretResourceVersions = secretResourceVersions
+
+	// Privileges are reconciled last, as they may refer to
+	// the objects created above
+	grants := make([]databaseGrant, len(obj.Spec.Grants))
+	for i := range obj.Spec.Grants {
+		grants[i] = databaseGrant{GrantSpec: obj.Spec.Grants[i], database: obj.Spec.Name}
+	}
+	obj.Status.Grants = grantObjectManager.reconcileList(ctx, db, grants)
+
+	defaultPrivileges := make([]databaseDefaultPrivilege, len(obj.Spec.DefaultPrivileges))
+	for i := range obj.Spec.DefaultPrivileges {
+		defaultPrivileges[i] = databaseDefaultPrivilege{
+			DefaultPrivilegeSpec: obj.Spec.DefaultPrivileges[i],
+			owner:                obj.Spec.Owner,
+		}
+	}
+	obj.Status.DefaultPrivileges = defaultPrivilegeObjectManager.reconcileList(ctx, db, defaultPrivileges)
 
 	return nil
 }
 
+// getUserMappings gets the user mappings of a foreign server, reading the
+// values of their options from the secrets. The resource versions of the
+// secrets that have been read are stored in secretResourceVersions.
+func (r *DatabaseReconciler) getUserMappings(
+	ctx context.Context,
+	namespace string,
+	server *apiv1.ServerSpec,
+	secretResourceVersions map[string]string,
+) []databaseUserMapping {
+	result := make([]databaseUserMapping, len(server.UserMappings))
+	for i, userMappingSpec := range server.UserMappings {
+		userMapping := databaseUserMapping{
+			UserMappingSpec: userMappingSpec,
+			server:          server.Name,
+		}
+
+		// A server being dropped takes its user mappings away
+		if server.Ensure == apiv1.EnsureAbsent {
+			userMapping.Ensure = apiv1.EnsureAbsent
+			result[i] = userMapping
+			continue
+		}
+
+		userMapping.options = make([]apiv1.OptionSpec, len(userMappingSpec.Options))
+		for j, option := range userMappingSpec.Options {
+			userMapping.options[j] = apiv1.OptionSpec{
+				Name:   option.Name,
+				Value:  option.Value,
+				Ensure: option.Ensure,
+			}
+			if option.SecretKeyRef == nil || option.Ensure == apiv1.EnsureAbsent {
+				continue
+			}
+
+			value, err := r.getSecretValue(ctx, namespace, option.SecretKeyRef, secretResourceVersions)
+			if err != nil {
+				userMapping.err = fmt.Errorf("while reading option %q: %w", option.Name, err)
+				break
+			}
+			userMapping.options[j].Value = value
+		}
+
+		result[i] = userMapping
+	}
+
+	return result
+}
+
+// getSecretValue reads a key of a secret, storing the resource
+// version of the secret in secretResourceVersions
+func (r *DatabaseReconciler) getSecretValue(
+	ctx context.Context,
+	namespace string,
+	selector *apiv1.SecretKeySelector,
+	secretResourceVersions map[string]string,
+) (string, error) {
+	var secret corev1.Secret
+	if err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: selector.Name}, &secret); err != nil {
+		return "", fmt.Errorf("while getting secret %q: %w", selector.Name, err)
+	}
+
+	value, ok := secret.Data[selector.Key]
+	if !ok {
+		return "", fmt.Errorf("missing key %q in secret %q", selector.Key, selector.Name)
+	}
+
+	secretResourceVersions[secret.Name] = secret.ResourceVersion
+	return string(value), nil
+}
+
+// checkUserMappingSecrets checks whether an applied database needs to be
+// reconciled again because the secrets used by its user mappings changed.
+// As these secrets are not watched, they are polled by the primary instance.
+func (r *DatabaseReconciler) checkUserMappingSecrets(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	database *apiv1.Database,
+	result ctrl.Result,
+) (ctrl.Result, bool, error) {
+	secretNames := database.Spec.GetUserMappingSecretNames()
+	if len(secretNames) == 0 ||
+		cluster.IsReplica() ||
+		!database.GetDeletionTimestamp().IsZero() ||
+		cluster.Status.CurrentPrimary != r.instance.GetPodName() {
+		return result, false, nil
+	}
+
+	for _, secretName := range secretNames {
+		var secret corev1.Secret
+		err := r.Get(ctx, client.ObjectKey{Namespace: database.Namespace, Name: secretName}, &secret)
+		if apierrs.IsNotFound(err) {
+			// The reconciliation will report the missing secret
+			return ctrl.Result{}, true, nil
+		}
+		if err != nil {
+			return ctrl.Result{}, false, err
+		}
+
+		if database.Status.SecretResourceVersions[secretName] != secret.ResourceVersion {
+			return ctrl.Result{}, true, nil
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: databaseReconciliationInterval}, false, nil
+}
+
 func (r *DatabaseReconciler) reconcilePostgresDatabase(ctx context.Context, db *sql.DB, obj *apiv1.Database) error {
 	dbExists, err := detectDatabase(ctx, db, obj)
 	if err != nil {
@@ -27,6 +27,7 @@ import (
 	"strings"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/cloudnative-pg/machinery/pkg/stringset"
 	"github.com/jackc/pgx/v5"
 	"github.com/lib/pq"
 
@@ -860,3 +861,630 @@ func dropDatabaseForeignServer(ctx context.Context, db *sql.DB, server apiv1.Ser
 	contextLogger.Info("dropped foreign server", "name", server.Name)
 	return nil
 }
+
+type userMappingInfo struct {
+	Options map[string]string `json:"options"`
+}
+
+// databaseUserMapping is a user mapping of a foreign server, whose
+// options include the values read from the secrets
+type databaseUserMapping struct {
+	apiv1.UserMappingSpec
+
+	// server is the name of the foreign server
+	server string
+
+	// options are the options of the user mapping, with their values
+	options []apiv1.OptionSpec
+
+	// err is the error raised while reading the secrets, if any
+	err error
+}
+
+// GetName gets a name identifying the user mapping in the status
+func (userMapping databaseUserMapping) GetName() string {
+	return fmt.Sprintf("%s@%s", userMapping.Name, userMapping.server)
+}
+
+// GetEnsure gets the ensure status of the user mapping
+func (userMapping databaseUserMapping) GetEnsure() apiv1.EnsureOption {
+	return userMapping.Ensure
+}
+
+// GoString renders the user mapping without the values of its options,
+// which may come from secrets and must never be reported in the status
+func (userMapping databaseUserMapping) GoString() string {
+	return fmt.Sprintf("user mapping %q", userMapping.GetName())
+}
+
+// userMappingRoleName gets the name of the role as reported by
+// pg_user_mappings, where PUBLIC is represented by `public`
+func userMappingRoleName(name string) string {
+	if strings.EqualFold(name, publicRole) {
+		return "public"
+	}
+	return name
+}
+
+const detectDatabaseUserMappingSQL = `
+SELECT umoptions
+FROM pg_catalog.pg_user_mappings
+WHERE srvname = $1 AND usename = $2
+`
+
+func getDatabaseUserMappingInfo(
+	ctx context.Context,
+	db *sql.DB,
+	userMapping databaseUserMapping,
+) (*userMappingInfo, error) {
+	if userMapping.err != nil {
+		return nil, userMapping.err
+	}
+
+	var optionsRaw pq.StringArray
+	if err := db.QueryRowContext(
+		ctx, detectDatabaseUserMappingSQL,
+		userMapping.server, userMappingRoleName(userMapping.Name)).
+		Scan(&optionsRaw); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("while scanning if user mapping %q exists: %w", userMapping.GetName(), err)
+	}
+
+	opts, err := parseOptions(optionsRaw)
+	if err != nil {
+		return nil, fmt.Errorf("while parsing options of user mapping %q", userMapping.GetName())
+	}
+
+	return &userMappingInfo{Options: opts}, nil
+}
+
+// createDatabaseUserMapping creates a user mapping for a foreign server.
+// The statements are never logged, as they may contain passwords.
+func createDatabaseUserMapping(ctx context.Context, db *sql.DB, userMapping databaseUserMapping) error {
+	contextLogger := log.FromContext(ctx)
+
+	var sqlCreateUserMapping strings.Builder
+	fmt.Fprintf(&sqlCreateUserMapping, "CREATE USER MAPPING FOR %s SERVER %s",
+		sanitizeGrantee(userMapping.Name),
+		pgx.Identifier{userMapping.server}.Sanitize())
+
+	if opts := extractOptionsClauses(userMapping.options); len(opts) > 0 {
+		sqlCreateUserMapping.WriteString(" OPTIONS (" + strings.Join(opts, ", ") + ")")
+	}
+
+	if _, err := db.ExecContext(ctx, sqlCreateUserMapping.String()); err != nil {
+		contextLogger.Error(err, "while creating user mapping", "name", userMapping.GetName())
+		return err
+	}
+	contextLogger.Info("created user mapping", "name", userMapping.GetName())
+
+	return nil
+}
+
+// updateDatabaseUserMapping updates the options of a user mapping.
+// The statements are never logged, as they may contain passwords.
+func updateDatabaseUserMapping(
+	ctx context.Context,
+	db *sql.DB,
+	userMapping databaseUserMapping,
+	info *userMappingInfo,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	toUpdateOpts := calculateAlterOptionsClauses(userMapping.options, info.Options)
+	if len(toUpdateOpts) == 0 {
+		return nil
+	}
+
+	changeOptionSQL := fmt.Sprintf(
+		"ALTER USER MAPPING FOR %s SERVER %s OPTIONS (%s)",
+		sanitizeGrantee(userMapping.Name),
+		pgx.Identifier{userMapping.server}.Sanitize(),
+		strings.Join(toUpdateOpts, ", "),
+	)
+
+	if _, err := db.ExecContext(ctx, changeOptionSQL); err != nil {
+		return fmt.Errorf("altering options of user mapping %w", err)
+	}
+	contextLogger.Info("altered user mapping options", "name", userMapping.GetName())
+
+	return nil
+}
+
+// dropDatabaseUserMapping drops a user mapping of a foreign server
+func dropDatabaseUserMapping(ctx context.Context, db *sql.DB, userMapping databaseUserMapping) error {
+	contextLogger := log.FromContext(ctx)
+	query := fmt.Sprintf("DROP USER MAPPING IF EXISTS FOR %s SERVER %s",
+		sanitizeGrantee(userMapping.Name),
+		pgx.Identifier{userMapping.server}.Sanitize())
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		contextLogger.Error(err, "while dropping user mapping", "query", query)
+		return err
+	}
+	contextLogger.Info("dropped user mapping", "name", userMapping.GetName())
+	return nil
+}
+
+// privilegeInfo is the state of a set of privileges held by a role
+type privilegeInfo struct {
+	// Missing is true when some of the privileges are not held on some
+	// of the objects
+	Missing bool `json:"missing"`
+
+	// MissingGrantOption is true when some of the privileges are held
+	// without the grant option
+	MissingGrantOption bool `json:"missingGrantOption"`
+
+	// HasGrantOption is true when some of the privileges are held
+	// with the grant option
+	HasGrantOption bool `json:"hasGrantOption"`
+}
+
+// databaseGrant is a grant of privileges on the objects of a database
+type databaseGrant struct {
+	apiv1.GrantSpec
+
+	// database is the name of the database, used when granting
+	// privileges on the database itself
+	database string
+}
+
+// databaseDefaultPrivilege is a default privilege of a database
+type databaseDefaultPrivilege struct {
+	apiv1.DefaultPrivilegeSpec
+
+	// owner is the owner of the database, used when no role
+	// creating the objects has been specified
+	owner string
+}
+
+// forRole gets the role whose created objects are affected by
+// the default privileges
+func (defaultPrivilege databaseDefaultPrivilege) forRole() string {
+	if defaultPrivilege.ForRole != "" {
+		return defaultPrivilege.ForRole
+	}
+	return defaultPrivilege.owner
+}
+
+// allPrivileges are the privileges `ALL` stands for, for each type of object.
+// MAINTAIN is deliberately left out, as it is only available since
+// PostgreSQL 17
+var allPrivileges = map[apiv1.PrivilegeObjectType][]string{
+	apiv1.PrivilegeObjectTypeDatabase: {"CREATE", "CONNECT", "TEMPORARY"},
+	apiv1.PrivilegeObjectTypeSchema:   {"USAGE", "CREATE"},
+	apiv1.PrivilegeObjectTypeTable: {
+		"SELECT", "INSERT", "UPDATE", "DELETE", "TRUNCATE", "REFERENCES", "TRIGGER",
+	},
+	apiv1.PrivilegeObjectTypeSequence: {"USAGE", "SELECT", "UPDATE"},
+	apiv1.PrivilegeObjectTypeFunction: {"EXECUTE"},
+	apiv1.PrivilegeObjectTypeType:     {"USAGE"},
+}
+
+// knownPrivileges are the privilege keywords that can be used
+// in a GRANT or REVOKE statement
+var knownPrivileges = stringset.From([]string{
+	"ALL", "SELECT", "INSERT", "UPDATE", "DELETE", "TRUNCATE", "REFERENCES", "TRIGGER",
+	"MAINTAIN", "CREATE", "CONNECT", "TEMPORARY", "EXECUTE", "USAGE",
+})
+
+// privilegeList renders a list of privileges for use in a GRANT or REVOKE
+// statement. Since the privileges are interpolated verbatim, any keyword
+// not known to be valid is refused.
+func privilegeList(privileges []apiv1.Privilege) (string, error) {
+	result := make([]string, len(privileges))
+	for i, privilege := range privileges {
+		if !knownPrivileges.Has(string(privilege)) {
+			return "", fmt.Errorf("unknown privilege %q", privilege)
+		}
+		result[i] = string(privilege)
+	}
+	return strings.Join(result, ", "), nil
+}
+
+// expandPrivileges gets the privileges to be checked in the ACLs,
+// replacing `ALL` with the privileges it stands for
+func expandPrivileges(objectType apiv1.PrivilegeObjectType, privileges []apiv1.Privilege) []string {
+	result := stringset.New()
+	for _, privilege := range privileges {
+		if privilege == "ALL" {
+			for _, expanded := range allPrivileges[objectType] {
+				result.Put(expanded)
+			}
+			continue
+		}
+		result.Put(string(privilege))
+	}
+	return result.ToSortedList()
+}
+
+// granteeOIDSQL selects the OID of the grantee passed as first
+// parameter, with PUBLIC being represented by 0 in the ACLs
+const granteeOIDSQL = `
+SELECT CASE WHEN upper($1::text) = 'PUBLIC' THEN 0::oid
+  ELSE (SELECT oid FROM pg_catalog.pg_roles WHERE rolname = $1::text) END AS oid
+`
+
+// detectGrantSQL selects the privileges held by a grantee on the
+// objects returned by the query to be interpolated, that must return
+// the OID and the ACL of each object. An object without privileges
+// is returned with a NULL privilege.
+const detectGrantSQL = `
+WITH grantee AS (` + granteeOIDSQL + `),
+objects AS (%s)
+SELECT o.oid, a.privilege_type, a.is_grantable
+FROM objects o
+CROSS JOIN grantee g
+LEFT JOIN LATERAL pg_catalog.aclexplode(o.acl) a ON a.grantee = g.oid
+`
+
+// grantObjectsSQL gets the query selecting the objects targeted by a
+// grant, together with its parameters
+func grantObjectsSQL(grant databaseGrant) (string, []any, error) {
+	switch grant.ObjectType {
+	case apiv1.PrivilegeObjectTypeDatabase:
+		return `SELECT oid, coalesce(datacl, pg_catalog.acldefault('d', datdba)) AS acl
+FROM pg_catalog.pg_database WHERE datname = $2`, []any{grant.database}, nil
+
+	case apiv1.PrivilegeObjectTypeSchema:
+		return `SELECT oid, coalesce(nspacl, pg_catalog.acldefault('n', nspowner)) AS acl
+FROM pg_catalog.pg_namespace WHERE nspname = $2`, []any{grant.Schema}, nil
+
+	case apiv1.PrivilegeObjectTypeTable, apiv1.PrivilegeObjectTypeSequence:
+		aclKind := "r"
+		relKinds := "'r', 'p', 'v', 'm', 'f'"
+		if grant.ObjectType == apiv1.PrivilegeObjectTypeSequence {
+			aclKind = "s"
+			relKinds = "'S'"
+		}
+		if grant.ObjectName != "" {
+			return fmt.Sprintf(`SELECT oid, coalesce(relacl, pg_catalog.acldefault('%s', relowner)) AS acl
+FROM pg_catalog.pg_class
+WHERE oid = pg_catalog.to_regclass(pg_catalog.format('%%I.%%I', $2::text, $3::text))`, aclKind),
+				[]any{grant.Schema, grant.ObjectName}, nil
+		}
+		return fmt.Sprintf(`SELECT oid, coalesce(relacl, pg_catalog.acldefault('%s', relowner)) AS acl
+FROM pg_catalog.pg_class
+WHERE relnamespace = pg_catalog.to_regnamespace($2::text)::oid AND relkind IN (%s)`, aclKind, relKinds),
+			[]any{grant.Schema}, nil
+
+	case apiv1.PrivilegeObjectTypeFunction:
+		if grant.ObjectName != "" {
+			return `SELECT oid, coalesce(proacl, pg_catalog.acldefault('f', proowner)) AS acl
+FROM pg_catalog.pg_proc
+WHERE oid = pg_catalog.to_regprocedure(pg_catalog.format('%I.%s', $2::text, $3::text))`,
+				[]any{grant.Schema, grant.ObjectName}, nil
+		}
+		return `SELECT oid, coalesce(proacl, pg_catalog.acldefault('f', proowner)) AS acl
+FROM pg_catalog.pg_proc
+WHERE pronamespace = pg_catalog.to_regnamespace($2::text)::oid AND prokind <> 'p'`,
+			[]any{grant.Schema}, nil
+
+	default:
+		return "", nil, fmt.Errorf("unsupported object type %q", grant.ObjectType)
+	}
+}
+
+// scanPrivileges reads the privileges held on each object, as
+// returned by the detection queries, and compares them with the
+// requested ones. Nil is returned when none of the requested
+// privileges is held.
+func scanPrivileges(rows *sql.Rows, requested []string) (*privilegeInfo, error) {
+	type objectPrivileges map[string]bool
+
+	held := make(map[int64]objectPrivileges)
+	for rows.Next() {
+		var (
+			objectID  int64
+			privilege sql.NullString
+			grantable sql.NullBool
+		)
+		if err := rows.Scan(&objectID, &privilege, &grantable); err != nil {
+			return nil, err
+		}
+		if held[objectID] == nil {
+			held[objectID] = make(objectPrivileges)
+		}
+		if privilege.Valid {
+			held[objectID][privilege.String] = grantable.Bool
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var (
+		result privilegeInfo
+		found  bool
+	)
+	for _, privileges := range held {
+		for _, privilege := range requested {
+			grantable, ok := privileges[privilege]
+			switch {
+			case !ok:
+				result.Missing = true
+			case grantable:
+				found = true
+				result.HasGrantOption = true
+			default:
+				found = true
+				result.MissingGrantOption = true
+			}
+		}
+	}
+
+	if !found {
+		return nil, nil
+	}
+	return &result, nil
+}
+
+func getDatabaseGrantInfo(ctx context.Context, db *sql.DB, grant databaseGrant) (*privilegeInfo, error) {
+	objectsSQL, args, err := grantObjectsSQL(grant)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(
+		ctx,
+		fmt.Sprintf(detectGrantSQL, objectsSQL),
+		append([]any{grant.Role}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("while reading the privileges %q: %w", grant.GetName(), err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	info, err := scanPrivileges(rows, expandPrivileges(grant.ObjectType, grant.Privileges))
+	if err != nil {
+		return nil, fmt.Errorf("while scanning the privileges %q: %w", grant.GetName(), err)
+	}
+	return info, nil
+}
+
+const detectFunctionSQL = `
+SELECT pg_catalog.format('%I.%I(%s)', n.nspname, p.proname,
+  pg_catalog.pg_get_function_identity_arguments(p.oid))
+FROM pg_catalog.pg_proc p
+JOIN pg_catalog.pg_namespace n ON n.oid = p.pronamespace
+WHERE p.oid = pg_catalog.to_regprocedure(pg_catalog.format('%I.%s', $1::text, $2::text))
+`
+
+// grantTarget renders the objects targeted by a grant, like
+// `TABLE "public"."users"` or `ALL TABLES IN SCHEMA "public"`
+func grantTarget(ctx context.Context, db *sql.DB, grant databaseGrant) (string, error) {
+	switch grant.ObjectType {
+	case apiv1.PrivilegeObjectTypeDatabase:
+		return "DATABASE " + pgx.Identifier{grant.database}.Sanitize(), nil
+
+	case apiv1.PrivilegeObjectTypeSchema:
+		return "SCHEMA " + pgx.Identifier{grant.Schema}.Sanitize(), nil
+
+	case apiv1.PrivilegeObjectTypeTable, apiv1.PrivilegeObjectTypeSequence:
+		keyword := strings.ToUpper(string(grant.ObjectType))
+		if grant.ObjectName == "" {
+			return fmt.Sprintf("ALL %sS IN SCHEMA %s", keyword, pgx.Identifier{grant.Schema}.Sanitize()), nil
+		}
+		return fmt.Sprintf("%s %s", keyword, pgx.Identifier{grant.Schema, grant.ObjectName}.Sanitize()), nil
+
+	case apiv1.PrivilegeObjectTypeFunction:
+		if grant.ObjectName == "" {
+			return "ALL FUNCTIONS IN SCHEMA " + pgx.Identifier{grant.Schema}.Sanitize(), nil
+		}
+
+		// The function signature cannot be quoted as an identifier,
+		// so we let PostgreSQL resolve it and render it safely
+		var function sql.NullString
+		if err := db.QueryRowContext(ctx, detectFunctionSQL, grant.Schema, grant.ObjectName).
+			Scan(&function); err != nil {
+			return "", fmt.Errorf("while resolving function %q: %w", grant.ObjectName, err)
+		}
+		if !function.Valid {
+			return "", fmt.Errorf("function %q does not exist in schema %q", grant.ObjectName, grant.Schema)
+		}
+		return "FUNCTION " + function.String, nil
+
+	default:
+		return "", fmt.Errorf("unsupported object type %q", grant.ObjectType)
+	}
+}
+
+// applyDatabaseGrant executes a GRANT or REVOKE statement for the
+// privileges of a grant. The action is the beginning of the statement,
+// like `GRANT` or `REVOKE GRANT OPTION FOR`.
+func applyDatabaseGrant(ctx context.Context, db *sql.DB, grant databaseGrant, action string) error {
+	contextLogger := log.FromContext(ctx)
+
+	privileges, err := privilegeList(grant.Privileges)
+	if err != nil {
+		return err
+	}
+	target, err := grantTarget(ctx, db, grant)
+	if err != nil {
+		return err
+	}
+
+	var query string
+	if action == "GRANT" {
+		query = fmt.Sprintf("GRANT %s ON %s TO %s", privileges, target, sanitizeGrantee(grant.Role))
+		if grant.WithGrantOption {
+			query += " WITH GRANT OPTION"
+		}
+	} else {
+		query = fmt.Sprintf("%s %s ON %s FROM %s", action, privileges, target, sanitizeGrantee(grant.Role))
+	}
+
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		contextLogger.Error(err, "while applying privileges", "query", query)
+		return err
+	}
+	contextLogger.Info("applied privileges", "query", query)
+	return nil
+}
+
+func createDatabaseGrant(ctx context.Context, db *sql.DB, grant databaseGrant) error {
+	return applyDatabaseGrant(ctx, db, grant, "GRANT")
+}
+
+func updateDatabaseGrant(ctx context.Context, db *sql.DB, grant databaseGrant, info *privilegeInfo) error {
+	switch {
+	case info.Missing, grant.WithGrantOption && info.MissingGrantOption:
+		return applyDatabaseGrant(ctx, db, grant, "GRANT")
+	case !grant.WithGrantOption && info.HasGrantOption:
+		return applyDatabaseGrant(ctx, db, grant, "REVOKE GRANT OPTION FOR")
+	default:
+		return nil
+	}
+}
+
+func dropDatabaseGrant(ctx context.Context, db *sql.DB, grant databaseGrant) error {
+	return applyDatabaseGrant(ctx, db, grant, "REVOKE")
+}
+
+// defaultPrivilegeObjectTypes maps the type of the objects to the
+// keyword used by `ALTER DEFAULT PRIVILEGES` and to the code used
+// in `pg_default_acl`
+var defaultPrivilegeObjectTypes = map[apiv1.PrivilegeObjectType]struct {
+	keyword string
+	code    string
+}{
+	apiv1.PrivilegeObjectTypeTable:    {keyword: "TABLES", code: "r"},
+	apiv1.PrivilegeObjectTypeSequence: {keyword: "SEQUENCES", code: "S"},
+	apiv1.PrivilegeObjectTypeFunction: {keyword: "FUNCTIONS", code: "f"},
+	apiv1.PrivilegeObjectTypeType:     {keyword: "TYPES", code: "T"},
+	apiv1.PrivilegeObjectTypeSchema:   {keyword: "SCHEMAS", code: "n"},
+}
+
+// detectDefaultPrivilegeSQL selects the default privileges held by a
+// grantee on the objects created by a role, either in a schema or in the
+// whole database. When no database-wide entry exists, the built-in
+// defaults of PostgreSQL apply.
+const detectDefaultPrivilegeSQL = `
+WITH grantee AS (` + granteeOIDSQL + `)
+SELECT r.oid, a.privilege_type, a.is_grantable
+FROM pg_catalog.pg_roles r
+CROSS JOIN grantee g
+LEFT JOIN LATERAL pg_catalog.aclexplode(coalesce(
+  (SELECT d.defaclacl FROM pg_catalog.pg_default_acl d
+   WHERE d.defaclrole = r.oid AND d.defaclobjtype = $3::"char"
+   AND d.defaclnamespace = CASE WHEN $4::text = '' THEN 0::oid
+     ELSE pg_catalog.to_regnamespace($4::text)::oid END),
+  CASE WHEN $4::text = '' THEN pg_catalog.acldefault($5::"char", r.oid)
+    ELSE '{}'::pg_catalog.aclitem[] END)) a ON a.grantee = g.oid
+WHERE r.rolname = $2
+`
+
+func getDatabaseDefaultPrivilegeInfo(
+	ctx context.Context,
+	db *sql.DB,
+	defaultPrivilege databaseDefaultPrivilege,
+) (*privilegeInfo, error) {
+	objectType, ok := defaultPrivilegeObjectTypes[defaultPrivilege.ObjectType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported object type %q", defaultPrivilege.ObjectType)
+	}
+
+	// acldefault uses a different code for sequences
+	aclKind := objectType.code
+	if defaultPrivilege.ObjectType == apiv1.PrivilegeObjectTypeSequence {
+		aclKind = "s"
+	}
+
+	rows, err := db.QueryContext(
+		ctx, detectDefaultPrivilegeSQL,
+		defaultPrivilege.Role, defaultPrivilege.forRole(), objectType.code, defaultPrivilege.Schema, aclKind)
+	if err != nil {
+		return nil, fmt.Errorf("while reading the default privileges %q: %w", defaultPrivilege.GetName(), err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	info, err := scanPrivileges(
+		rows,
+		expandPrivileges(defaultPrivilege.ObjectType, defaultPrivilege.Privileges))
+	if err != nil {
+		return nil, fmt.Errorf("while scanning the default privileges %q: %w", defaultPrivilege.GetName(), err)
+	}
+	return info, nil
+}
+
+// applyDatabaseDefaultPrivilege executes an `ALTER DEFAULT PRIVILEGES`
+// statement for a default privilege. The action is the beginning of the
+// `GRANT` or `REVOKE` clause, like `GRANT` or `REVOKE GRANT OPTION FOR`.
+func applyDatabaseDefaultPrivilege(
+	ctx context.Context,
+	db *sql.DB,
+	defaultPrivilege databaseDefaultPrivilege,
+	action string,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	objectType, ok := defaultPrivilegeObjectTypes[defaultPrivilege.ObjectType]
+	if !ok {
+		return fmt.Errorf("unsupported object type %q", defaultPrivilege.ObjectType)
+	}
+	privileges, err := privilegeList(defaultPrivilege.Privileges)
+	if err != nil {
+		return err
+	}
+
+	var query strings.Builder
+	fmt.Fprintf(&query, "ALTER DEFAULT PRIVILEGES FOR ROLE %s",
+		pgx.Identifier{defaultPrivilege.forRole()}.Sanitize())
+	if defaultPrivilege.Schema != "" {
+		fmt.Fprintf(&query, " IN SCHEMA %s", pgx.Identifier{defaultPrivilege.Schema}.Sanitize())
+	}
+	if action == "GRANT" {
+		fmt.Fprintf(&query, " GRANT %s ON %s TO %s",
+			privileges, objectType.keyword, sanitizeGrantee(defaultPrivilege.Role))
+		if defaultPrivilege.WithGrantOption {
+			query.WriteString(" WITH GRANT OPTION")
+		}
+	} else {
+		fmt.Fprintf(&query, " %s %s ON %s FROM %s",
+			action, privileges, objectType.keyword, sanitizeGrantee(defaultPrivilege.Role))
+	}
+
+	if _, err := db.ExecContext(ctx, query.String()); err != nil {
+		contextLogger.Error(err, "while altering default privileges", "query", query.String())
+		return err
+	}
+	contextLogger.Info("altered default privileges", "query", query.String())
+	return nil
+}
+
+func createDatabaseDefaultPrivilege(
+	ctx context.Context,
+	db *sql.DB,
+	defaultPrivilege databaseDefaultPrivilege,
+) error {
+	return applyDatabaseDefaultPrivilege(ctx, db, defaultPrivilege, "GRANT")
+}
+
+func updateDatabaseDefaultPrivilege(
+	ctx context.Context,
+	db *sql.DB,
+	defaultPrivilege databaseDefaultPrivilege,
+	info *privilegeInfo,
+) error {
+	switch {
+	case info.Missing, defaultPrivilege.WithGrantOption && info.MissingGrantOption:
+		return applyDatabaseDefaultPrivilege(ctx, db, defaultPrivilege, "GRANT")
+	case !defaultPrivilege.WithGrantOption && info.HasGrantOption:
+		return applyDatabaseDefaultPrivilege(ctx, db, defaultPrivilege, "REVOKE GRANT OPTION FOR")
+	default:
+		return nil
+	}
+}
+
+func dropDatabaseDefaultPrivilege(
+	ctx context.Context,
+	db *sql.DB,
+	defaultPrivilege databaseDefaultPrivilege,
+) error {
+	return applyDatabaseDefaultPrivilege(ctx, db, defaultPrivilege, "REVOKE")
+}
@@ -21,6 +21,7 @@ package controller
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -1129,3 +1130,429 @@ var _ = Describe("applyObjectPrivilege", func() {
 			Error().NotTo(HaveOccurred())
 	})
 })
+
+var _ = Describe("Managed privileges SQL", func() {
+	var (
+		dbMock sqlmock.Sqlmock
+		db     *sql.DB
+		grant  databaseGrant
+		err    error
+
+		testError error
+	)
+
+	grantColumns := []string{"oid", "privilege_type", "is_grantable"}
+
+	BeforeEach(func() {
+		db, dbMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		grant = databaseGrant{
+			GrantSpec: apiv1.GrantSpec{
+				Role:       "app",
+				Privileges: []apiv1.Privilege{"SELECT", "INSERT"},
+				ObjectType: apiv1.PrivilegeObjectTypeTable,
+				Schema:     "public",
+				Ensure:     apiv1.EnsurePresent,
+			},
+			database: "appdb",
+		}
+
+		testError = fmt.Errorf("test error")
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	expectGrantQuery := func(grant databaseGrant) *sqlmock.ExpectedQuery {
+		objectsSQL, args, err := grantObjectsSQL(grant)
+		Expect(err).ToNot(HaveOccurred())
+
+		values := []driver.Value{grant.Role}
+		for _, arg := range args {
+			values = append(values, arg)
+		}
+		return dbMock.ExpectQuery(fmt.Sprintf(detectGrantSQL, objectsSQL)).WithArgs(values...)
+	}
+
+	Context("expandPrivileges", func() {
+		It("replaces ALL with the privileges of the object type", func() {
+			Expect(expandPrivileges(apiv1.PrivilegeObjectTypeSequence, []apiv1.Privilege{"ALL", "SELECT"})).
+				To(Equal([]string{"SELECT", "UPDATE", "USAGE"}))
+		})
+	})
+
+	Context("getDatabaseGrantInfo", func() {
+		It("returns nil info when none of the privileges is held", func(ctx SpecContext) {
+			expectGrantQuery(grant).WillReturnRows(
+				sqlmock.NewRows(grantColumns).
+					AddRow(16384, nil, nil).
+					AddRow(16385, "UPDATE", false),
+			)
+			info, err := getDatabaseGrantInfo(ctx, db, grant)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info).To(BeNil())
+		})
+
+		It("returns nil info when there are no objects", func(ctx SpecContext) {
+			expectGrantQuery(grant).WillReturnRows(sqlmock.NewRows(grantColumns))
+			info, err := getDatabaseGrantInfo(ctx, db, grant)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info).To(BeNil())
+		})
+
+		It("detects the objects missing some privileges", func(ctx SpecContext) {
+			expectGrantQuery(grant).WillReturnRows(
+				sqlmock.NewRows(grantColumns).
+					AddRow(16384, "SELECT", false).
+					AddRow(16384, "INSERT", false).
+					AddRow(16385, "SELECT", true),
+			)
+			info, err := getDatabaseGrantInfo(ctx, db, grant)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info).To(Equal(&privilegeInfo{
+				Missing:            true,
+				MissingGrantOption: true,
+				HasGrantOption:     true,
+			}))
+		})
+
+		It("reads the privileges on the database", func(ctx SpecContext) {
+			grant.ObjectType = apiv1.PrivilegeObjectTypeDatabase
+			grant.Schema = ""
+			grant.Privileges = []apiv1.Privilege{"ALL"}
+			expectGrantQuery(grant).WillReturnRows(
+				sqlmock.NewRows(grantColumns).
+					AddRow(16384, "CONNECT", false).
+					AddRow(16384, "CREATE", false).
+					AddRow(16384, "TEMPORARY", false),
+			)
+			info, err := getDatabaseGrantInfo(ctx, db, grant)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info).To(Equal(&privilegeInfo{MissingGrantOption: true}))
+		})
+
+		It("fails when the privileges could not be read", func(ctx SpecContext) {
+			expectGrantQuery(grant).WillReturnError(testError)
+			_, err := getDatabaseGrantInfo(ctx, db, grant)
+			Expect(err).To(MatchError(testError))
+		})
+	})
+
+	Context("createDatabaseGrant", func() {
+		It("grants the privileges on all the tables of a schema", func(ctx SpecContext) {
+			dbMock.ExpectExec(`GRANT SELECT, INSERT ON ALL TABLES IN SCHEMA "public" TO "app"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(createDatabaseGrant(ctx, db, grant)).To(Succeed())
+		})
+
+		It("grants the privileges on a sequence with the grant option", func(ctx SpecContext) {
+			grant.ObjectType = apiv1.PrivilegeObjectTypeSequence
+			grant.ObjectName = "users_id_seq"
+			grant.Privileges = []apiv1.Privilege{"USAGE"}
+			grant.WithGrantOption = true
+			dbMock.ExpectExec(`GRANT USAGE ON SEQUENCE "public"."users_id_seq" TO "app" WITH GRANT OPTION`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(createDatabaseGrant(ctx, db, grant)).To(Succeed())
+		})
+
+		It("grants the privileges on the database to PUBLIC", func(ctx SpecContext) {
+			grant.ObjectType = apiv1.PrivilegeObjectTypeDatabase
+			grant.Schema = ""
+			grant.Role = "public"
+			grant.Privileges = []apiv1.Privilege{"CONNECT"}
+			dbMock.ExpectExec(`GRANT CONNECT ON DATABASE "appdb" TO PUBLIC`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(createDatabaseGrant(ctx, db, grant)).To(Succeed())
+		})
+
+		It("resolves the signature of a function", func(ctx SpecContext) {
+			grant.ObjectType = apiv1.PrivilegeObjectTypeFunction
+			grant.ObjectName = "add(int, int)"
+			grant.Privileges = []apiv1.Privilege{"EXECUTE"}
+			dbMock.ExpectQuery(detectFunctionSQL).
+				WithArgs("public", "add(int, int)").
+				WillReturnRows(sqlmock.NewRows([]string{"format"}).AddRow("public.add(integer, integer)"))
+			dbMock.ExpectExec(`GRANT EXECUTE ON FUNCTION public.add(integer, integer) TO "app"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(createDatabaseGrant(ctx, db, grant)).To(Succeed())
+		})
+
+		It("fails when the function does not exist", func(ctx SpecContext) {
+			grant.ObjectType = apiv1.PrivilegeObjectTypeFunction
+			grant.ObjectName = "missing()"
+			grant.Privileges = []apiv1.Privilege{"EXECUTE"}
+			dbMock.ExpectQuery(detectFunctionSQL).
+				WithArgs("public", "missing()").
+				WillReturnRows(sqlmock.NewRows([]string{"format"}).AddRow(nil))
+			Expect(createDatabaseGrant(ctx, db, grant)).
+				To(MatchError(`function "missing()" does not exist in schema "public"`))
+		})
+
+		It("refuses unknown privileges", func(ctx SpecContext) {
+			grant.Privileges = []apiv1.Privilege{"SELECT; DROP TABLE users"}
+			Expect(createDatabaseGrant(ctx, db, grant)).Error().To(HaveOccurred())
+		})
+
+		It("fails when the privileges could not be granted", func(ctx SpecContext) {
+			dbMock.ExpectExec(`GRANT SELECT, INSERT ON ALL TABLES IN SCHEMA "public" TO "app"`).
+				WillReturnError(testError)
+			Expect(createDatabaseGrant(ctx, db, grant)).To(MatchError(testError))
+		})
+	})
+
+	Context("updateDatabaseGrant", func() {
+		It("does nothing when the privileges are already held", func(ctx SpecContext) {
+			Expect(updateDatabaseGrant(ctx, db, grant, &privilegeInfo{MissingGrantOption: true})).To(Succeed())
+		})
+
+		It("grants the missing privileges", func(ctx SpecContext) {
+			dbMock.ExpectExec(`GRANT SELECT, INSERT ON ALL TABLES IN SCHEMA "public" TO "app"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(updateDatabaseGrant(ctx, db, grant, &privilegeInfo{Missing: true})).To(Succeed())
+		})
+
+		It("revokes the grant option when not requested", func(ctx SpecContext) {
+			grant.ObjectName = "users"
+			dbMock.ExpectExec(`REVOKE GRANT OPTION FOR SELECT, INSERT ON TABLE "public"."users" FROM "app"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(updateDatabaseGrant(ctx, db, grant, &privilegeInfo{HasGrantOption: true})).To(Succeed())
+		})
+	})
+
+	Context("dropDatabaseGrant", func() {
+		It("revokes the privileges", func(ctx SpecContext) {
+			grant.ObjectType = apiv1.PrivilegeObjectTypeSchema
+			grant.Privileges = []apiv1.Privilege{"CREATE"}
+			dbMock.ExpectExec(`REVOKE CREATE ON SCHEMA "public" FROM "app"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(dropDatabaseGrant(ctx, db, grant)).To(Succeed())
+		})
+	})
+})
+
+var _ = Describe("Managed default privileges SQL", func() {
+	var (
+		dbMock           sqlmock.Sqlmock
+		db               *sql.DB
+		defaultPrivilege databaseDefaultPrivilege
+		err              error
+	)
+
+	BeforeEach(func() {
+		db, dbMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		defaultPrivilege = databaseDefaultPrivilege{
+			DefaultPrivilegeSpec: apiv1.DefaultPrivilegeSpec{
+				Role:       "app",
+				Privileges: []apiv1.Privilege{"SELECT"},
+				ObjectType: apiv1.PrivilegeObjectTypeTable,
+				Schema:     "public",
+				Ensure:     apiv1.EnsurePresent,
+			},
+			owner: "owner",
+		}
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	Context("getDatabaseDefaultPrivilegeInfo", func() {
+		It("reads the default privileges of the database owner", func(ctx SpecContext) {
+			dbMock.ExpectQuery(detectDefaultPrivilegeSQL).
+				WithArgs("app", "owner", "r", "public", "r").
+				WillReturnRows(
+					sqlmock.NewRows([]string{"oid", "privilege_type", "is_grantable"}).
+						AddRow(10, "SELECT", false),
+				)
+			info, err := getDatabaseDefaultPrivilegeInfo(ctx, db, defaultPrivilege)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info).To(Equal(&privilegeInfo{MissingGrantOption: true}))
+		})
+
+		It("uses the built-in default of sequences for the given role", func(ctx SpecContext) {
+			defaultPrivilege.ObjectType = apiv1.PrivilegeObjectTypeSequence
+			defaultPrivilege.Schema = ""
+			defaultPrivilege.ForRole = "creator"
+			dbMock.ExpectQuery(detectDefaultPrivilegeSQL).
+				WithArgs("app", "creator", "S", "", "s").
+				WillReturnRows(
+					sqlmock.NewRows([]string{"oid", "privilege_type", "is_grantable"}).
+						AddRow(10, nil, nil),
+				)
+			info, err := getDatabaseDefaultPrivilegeInfo(ctx, db, defaultPrivilege)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info).To(BeNil())
+		})
+	})
+
+	Context("createDatabaseDefaultPrivilege", func() {
+		It("alters the default privileges in a schema", func(ctx SpecContext) {
+			dbMock.ExpectExec(`ALTER DEFAULT PRIVILEGES FOR ROLE "owner" IN SCHEMA "public" ` +
+				`GRANT SELECT ON TABLES TO "app"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(createDatabaseDefaultPrivilege(ctx, db, defaultPrivilege)).To(Succeed())
+		})
+
+		It("alters the default privileges of the whole database", func(ctx SpecContext) {
+			defaultPrivilege.ObjectType = apiv1.PrivilegeObjectTypeFunction
+			defaultPrivilege.Privileges = []apiv1.Privilege{"EXECUTE"}
+			defaultPrivilege.Schema = ""
+			defaultPrivilege.ForRole = "creator"
+			defaultPrivilege.WithGrantOption = true
+			dbMock.ExpectExec(`ALTER DEFAULT PRIVILEGES FOR ROLE "creator" ` +
+				`GRANT EXECUTE ON FUNCTIONS TO "app" WITH GRANT OPTION`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(createDatabaseDefaultPrivilege(ctx, db, defaultPrivilege)).To(Succeed())
+		})
+	})
+
+	Context("updateDatabaseDefaultPrivilege", func() {
+		It("does nothing when the privileges are already held", func(ctx SpecContext) {
+			Expect(updateDatabaseDefaultPrivilege(ctx, db, defaultPrivilege,
+				&privilegeInfo{MissingGrantOption: true})).To(Succeed())
+		})
+
+		It("revokes the grant option when not requested", func(ctx SpecContext) {
+			dbMock.ExpectExec(`ALTER DEFAULT PRIVILEGES FOR ROLE "owner" IN SCHEMA "public" ` +
+				`REVOKE GRANT OPTION FOR SELECT ON TABLES FROM "app"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(updateDatabaseDefaultPrivilege(ctx, db, defaultPrivilege,
+				&privilegeInfo{HasGrantOption: true})).To(Succeed())
+		})
+	})
+
+	Context("dropDatabaseDefaultPrivilege", func() {
+		It("revokes the default privileges from PUBLIC", func(ctx SpecContext) {
+			defaultPrivilege.ObjectType = apiv1.PrivilegeObjectTypeType
+			defaultPrivilege.Privileges = []apiv1.Privilege{"USAGE"}
+			defaultPrivilege.Schema = ""
+			defaultPrivilege.Role = "PUBLIC"
+			dbMock.ExpectExec(`ALTER DEFAULT PRIVILEGES FOR ROLE "owner" REVOKE USAGE ON TYPES FROM PUBLIC`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(dropDatabaseDefaultPrivilege(ctx, db, defaultPrivilege)).To(Succeed())
+		})
+	})
+})
+
+var _ = Describe("Managed user mapping SQL", func() {
+	var (
+		dbMock      sqlmock.Sqlmock
+		db          *sql.DB
+		userMapping databaseUserMapping
+		err         error
+
+		testError error
+	)
+
+	BeforeEach(func() {
+		db, dbMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		userMapping = databaseUserMapping{
+			UserMappingSpec: apiv1.UserMappingSpec{
+				Name:   "app",
+				Ensure: apiv1.EnsurePresent,
+			},
+			server: "angus",
+			options: []apiv1.OptionSpec{
+				{Name: "user", Value: "remote", Ensure: apiv1.EnsurePresent},
+				{Name: "password", Value: "secret", Ensure: apiv1.EnsurePresent},
+			},
+		}
+
+		testError = fmt.Errorf("test error")
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	Context("getDatabaseUserMappingInfo", func() {
+		It("returns info when the user mapping exists", func(ctx SpecContext) {
+			dbMock.
+				ExpectQuery(detectDatabaseUserMappingSQL).
+				WithArgs("angus", "app").
+				WillReturnRows(
+					sqlmock.NewRows([]string{"umoptions"}).
+						AddRow("{user=remote,password=secret}"),
+				)
+			info, err := getDatabaseUserMappingInfo(ctx, db, userMapping)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.Options).To(Equal(map[string]string{"user": "remote", "password": "secret"}))
+		})
+
+		It("looks for the PUBLIC user mapping", func(ctx SpecContext) {
+			userMapping.Name = "PUBLIC"
+			dbMock.
+				ExpectQuery(detectDatabaseUserMappingSQL).
+				WithArgs("angus", "public").
+				WillReturnRows(sqlmock.NewRows([]string{"umoptions"}))
+			info, err := getDatabaseUserMappingInfo(ctx, db, userMapping)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info).To(BeNil())
+		})
+
+		It("reports the errors raised while reading the secrets", func(ctx SpecContext) {
+			userMapping.err = testError
+			_, err := getDatabaseUserMappingInfo(ctx, db, userMapping)
+			Expect(err).To(MatchError(testError))
+		})
+	})
+
+	Context("createDatabaseUserMapping", func() {
+		It("creates the user mapping with its options", func(ctx SpecContext) {
+			dbMock.
+				ExpectExec(`CREATE USER MAPPING FOR "app" SERVER "angus" ` +
+					`OPTIONS ("user" 'remote', "password" 'secret')`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(createDatabaseUserMapping(ctx, db, userMapping)).To(Succeed())
+		})
+
+		It("creates the PUBLIC user mapping without options", func(ctx SpecContext) {
+			userMapping.Name = "public"
+			userMapping.options = nil
+			dbMock.
+				ExpectExec(`CREATE USER MAPPING FOR PUBLIC SERVER "angus"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(createDatabaseUserMapping(ctx, db, userMapping)).To(Succeed())
+		})
+
+		It("fails when the user mapping could not be created", func(ctx SpecContext) {
+			dbMock.
+				ExpectExec(`CREATE USER MAPPING FOR "app" SERVER "angus" ` +
+					`OPTIONS ("user" 'remote', "password" 'secret')`).
+				WillReturnError(testError)
+			Expect(createDatabaseUserMapping(ctx, db, userMapping)).To(MatchError(testError))
+		})
+	})
+
+	Context("updateDatabaseUserMapping", func() {
+		It("does nothing when the options did not change", func(ctx SpecContext) {
+			Expect(updateDatabaseUserMapping(ctx, db, userMapping, &userMappingInfo{
+				Options: map[string]string{"user": "remote", "password": "secret"},
+			})).To(Succeed())
+		})
+
+		It("changes the password read from the secret", func(ctx SpecContext) {
+			dbMock.
+				ExpectExec(`ALTER USER MAPPING FOR "app" SERVER "angus" OPTIONS (SET "password" 'secret')`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(updateDatabaseUserMapping(ctx, db, userMapping, &userMappingInfo{
+				Options: map[string]string{"user": "remote", "password": "old"},
+			})).To(Succeed())
+		})
+	})
+
+	Context("dropDatabaseUserMapping", func() {
+		It("drops the user mapping", func(ctx SpecContext) {
+			dbMock.
+				ExpectExec(`DROP USER MAPPING IF EXISTS FOR "app" SERVER "angus"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(dropDatabaseUserMapping(ctx, db, userMapping)).To(Succeed())
+		})
+	})
+})
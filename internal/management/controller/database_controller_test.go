@@ -661,3 +661,157 @@ func reconcileDatabase(
 		Name:      database.GetName(),
 	}, database)
 }
+
+var _ = Describe("Managed Database user mappings", func() {
+	var (
+		database   *apiv1.Database
+		cluster    *apiv1.Cluster
+		secret     *corev1.Secret
+		r          *DatabaseReconciler
+		fakeClient client.Client
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+		database = &apiv1.Database{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "db-one",
+				Namespace:  "default",
+				Generation: 1,
+			},
+			Spec: apiv1.DatabaseSpec{
+				ClusterRef: corev1.LocalObjectReference{
+					Name: cluster.Name,
+				},
+				Name:  "db-one",
+				Owner: "app",
+				Servers: []apiv1.ServerSpec{
+					{
+						DatabaseObjectSpec: apiv1.DatabaseObjectSpec{
+							Name:   "angus",
+							Ensure: apiv1.EnsurePresent,
+						},
+						FdwName: "postgres_fdw",
+						UserMappings: []apiv1.UserMappingSpec{
+							{
+								Name:   "app",
+								Ensure: apiv1.EnsurePresent,
+								Options: []apiv1.UserMappingOptionSpec{
+									{Name: "user", Value: "remote", Ensure: apiv1.EnsurePresent},
+									{
+										Name: "password",
+										SecretKeyRef: &apiv1.SecretKeySelector{
+											LocalObjectReference: apiv1.LocalObjectReference{Name: "angus-credentials"},
+											Key:                  "password",
+										},
+										Ensure: apiv1.EnsurePresent,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			Status: apiv1.DatabaseStatus{
+				Applied:            ptr.To(true),
+				ObservedGeneration: 1,
+			},
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "angus-credentials",
+				Namespace: "default",
+			},
+			Data: map[string][]byte{
+				"password": []byte("secret-password"),
+			},
+		}
+
+		fakeClient = fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster, database, secret).
+			WithStatusSubresource(&apiv1.Cluster{}, &apiv1.Database{}).
+			Build()
+		Expect(fakeClient.Get(context.Background(), client.ObjectKeyFromObject(secret), secret)).To(Succeed())
+
+		r = &DatabaseReconciler{
+			Client: fakeClient,
+			Scheme: schemeBuilder.BuildWithAllKnownScheme(),
+			instance: postgres.NewInstance().
+				WithNamespace("default").
+				WithPodName("cluster-example-1").
+				WithClusterName("cluster-example"),
+		}
+	})
+
+	It("reads the values of the options from the secrets", func(ctx SpecContext) {
+		versions := make(map[string]string)
+		userMappings := r.getUserMappings(ctx, database.Namespace, &database.Spec.Servers[0], versions)
+
+		Expect(userMappings).To(HaveLen(1))
+		Expect(userMappings[0].err).ToNot(HaveOccurred())
+		Expect(userMappings[0].GetName()).To(Equal("app@angus"))
+		Expect(userMappings[0].options).To(Equal([]apiv1.OptionSpec{
+			{Name: "user", Value: "remote", Ensure: apiv1.EnsurePresent},
+			{Name: "password", Value: "secret-password", Ensure: apiv1.EnsurePresent},
+		}))
+		Expect(versions).To(Equal(map[string]string{"angus-credentials": secret.ResourceVersion}))
+		Expect(fmt.Sprintf("%#v", userMappings[0])).ToNot(ContainSubstring("secret-password"))
+	})
+
+	It("reports a missing key in the secret", func(ctx SpecContext) {
+		database.Spec.Servers[0].UserMappings[0].Options[1].SecretKeyRef.Key = "missing"
+		userMappings := r.getUserMappings(ctx, database.Namespace, &database.Spec.Servers[0], map[string]string{})
+
+		Expect(userMappings).To(HaveLen(1))
+		Expect(userMappings[0].err).To(MatchError(ContainSubstring(`missing key "missing"`)))
+	})
+
+	It("drops the user mappings of the servers to be dropped", func(ctx SpecContext) {
+		database.Spec.Servers[0].Ensure = apiv1.EnsureAbsent
+		versions := make(map[string]string)
+		userMappings := r.getUserMappings(ctx, database.Namespace, &database.Spec.Servers[0], versions)
+
+		Expect(userMappings).To(HaveLen(1))
+		Expect(userMappings[0].GetEnsure()).To(Equal(apiv1.EnsureAbsent))
+		Expect(versions).To(BeEmpty())
+	})
+
+	It("polls the secrets of an applied database", func(ctx SpecContext) {
+		database.Status.SecretResourceVersions = map[string]string{
+			"angus-credentials": secret.ResourceVersion,
+		}
+
+		result, proceed, err := r.checkUserMappingSecrets(ctx, cluster, database, ctrl.Result{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(proceed).To(BeFalse())
+		Expect(result.RequeueAfter).To(Equal(databaseReconciliationInterval))
+	})
+
+	It("reconciles an applied database again when a secret changed", func(ctx SpecContext) {
+		database.Status.SecretResourceVersions = map[string]string{
+			"angus-credentials": "outdated",
+		}
+
+		_, proceed, err := r.checkUserMappingSecrets(ctx, cluster, database, ctrl.Result{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(proceed).To(BeTrue())
+	})
+
+	It("does not poll the secrets on the other instances", func(ctx SpecContext) {
+		cluster.Status.CurrentPrimary = "cluster-example-2"
+
+		result, proceed, err := r.checkUserMappingSecrets(ctx, cluster, database, ctrl.Result{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(proceed).To(BeFalse())
+		Expect(result.IsZero()).To(BeTrue())
+	})
+})
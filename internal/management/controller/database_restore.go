@@ -0,0 +1,123 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// restoreDumpFunc restores the dump contained in filePath into the database
+type restoreDumpFunc func(
+	ctx context.Context,
+	obj *apiv1.Database,
+	filePath string,
+	dump *apiv1.LogicalBackupDatabaseStatus,
+) error
+
+// reconcileDatabaseRestore restores the logical backup referenced by the
+// database, unless it has already been restored
+func (r *DatabaseReconciler) reconcileDatabaseRestore(ctx context.Context, obj *apiv1.Database) error {
+	source := obj.Spec.Restore
+	if source == nil || obj.Status.RestoredFrom == source.BackupRef.Name {
+		return nil
+	}
+
+	contextLogger := log.FromContext(ctx).WithValues("backupName", source.BackupRef.Name)
+
+	var backup apiv1.Backup
+	if err := r.Get(ctx, client.ObjectKey{
+		Namespace: obj.Namespace,
+		Name:      source.BackupRef.Name,
+	}, &backup); err != nil {
+		return fmt.Errorf("while getting backup %q: %w", source.BackupRef.Name, err)
+	}
+
+	if !backup.IsCompletedLogicalBackup() {
+		return fmt.Errorf("backup %q is not a completed logical backup", backup.Name)
+	}
+
+	// the dumps can be restored only if the instances mount the
+	// volume where they are stored
+	cluster, err := r.GetCluster(ctx)
+	if err != nil {
+		return fmt.Errorf("while fetching the cluster: %w", err)
+	}
+	if !cluster.Spec.Backup.IsLogicalBackupConfigured() ||
+		cluster.Spec.Backup.Logical.ClaimName != backup.Status.LogicalBackupStatus.ClaimName {
+		return fmt.Errorf("the volume %q containing backup %q is not mounted in the cluster",
+			backup.Status.LogicalBackupStatus.ClaimName, backup.Name)
+	}
+
+	sourceDatabase := source.Database
+	if sourceDatabase == "" {
+		sourceDatabase = obj.Spec.Name
+	}
+
+	dump := backup.Status.LogicalBackupStatus.GetDatabase(sourceDatabase)
+	if dump == nil {
+		return fmt.Errorf("database %q not found in backup %q", sourceDatabase, backup.Name)
+	}
+
+	filePath := filepath.Join(postgres.GetLogicalBackupDirectory(backup.Status.LogicalBackupStatus), dump.FileName)
+	contextLogger.Info("Restoring logical backup", "sourceDatabase", sourceDatabase, "filePath", filePath)
+	if err := r.restoreDump(ctx, obj, filePath, dump); err != nil {
+		return fmt.Errorf("while restoring backup %q: %w", backup.Name, err)
+	}
+
+	obj.Status.RestoredFrom = backup.Name
+	return nil
+}
+
+// newRestoreDumpFunc creates the function verifying and restoring a dump
+// into a database of the passed instance
+func newRestoreDumpFunc(instance *postgres.Instance) restoreDumpFunc {
+	return func(
+		ctx context.Context,
+		obj *apiv1.Database,
+		filePath string,
+		dump *apiv1.LogicalBackupDatabaseStatus,
+	) error {
+		if err := postgres.VerifyLogicalBackupDump(filePath, dump); err != nil {
+			return err
+		}
+
+		owner := obj.Spec.Owner
+		if owner == "" {
+			owner = "postgres"
+		}
+
+		return postgres.RestoreDatabaseDump(
+			ctx,
+			instance.ConnectionPool().GetDsn(obj.Spec.Name),
+			owner,
+			filePath,
+			obj.Spec.Restore.Schemas,
+			obj.Spec.Restore.Tables,
+		)
+	}
+}
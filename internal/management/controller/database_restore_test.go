@@ -0,0 +1,155 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Database restore from a logical backup", func() {
+	var (
+		cluster      *apiv1.Cluster
+		backup       *apiv1.Backup
+		database     *apiv1.Database
+		restoredFile string
+		restoreErr   error
+	)
+
+	newReconciler := func() *DatabaseReconciler {
+		fakeClient := fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster, backup, database).
+			Build()
+
+		return &DatabaseReconciler{
+			Client: fakeClient,
+			instance: postgres.NewInstance().
+				WithNamespace("default").
+				WithPodName("cluster-example-1").
+				WithClusterName("cluster-example"),
+			restoreDump: func(
+				_ context.Context,
+				_ *apiv1.Database,
+				filePath string,
+				_ *apiv1.LogicalBackupDatabaseStatus,
+			) error {
+				restoredFile = filePath
+				return restoreErr
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		restoredFile = ""
+		restoreErr = nil
+
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{
+					Logical: &apiv1.LogicalBackupConfiguration{ClaimName: "dumps"},
+				},
+			},
+		}
+		backup = &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "backup-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.BackupSpec{
+				Method: apiv1.BackupMethodLogical,
+			},
+			Status: apiv1.BackupStatus{
+				Phase: apiv1.BackupPhaseCompleted,
+				LogicalBackupStatus: &apiv1.BackupLogicalStatus{
+					ClaimName: "dumps",
+					Path:      "cluster-example/backup-example",
+					Databases: []apiv1.LogicalBackupDatabaseStatus{
+						{Name: "app", FileName: "app.dump"},
+					},
+				},
+			},
+		}
+		database = &apiv1.Database{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "db-restored",
+				Namespace: "default",
+			},
+			Spec: apiv1.DatabaseSpec{
+				ClusterRef: corev1.LocalObjectReference{Name: "cluster-example"},
+				Name:       "restored",
+				Owner:      "app",
+				Restore: &apiv1.DatabaseRestoreSource{
+					BackupRef: corev1.LocalObjectReference{Name: "backup-example"},
+					Database:  "app",
+				},
+			},
+		}
+	})
+
+	It("restores the dump of the source database", func(ctx SpecContext) {
+		r := newReconciler()
+		Expect(r.reconcileDatabaseRestore(ctx, database)).To(Succeed())
+		Expect(restoredFile).To(Equal("/var/lib/postgresql/dumps/cluster-example/backup-example/app.dump"))
+		Expect(database.Status.RestoredFrom).To(Equal("backup-example"))
+	})
+
+	It("does not restore the same backup twice", func(ctx SpecContext) {
+		database.Status.RestoredFrom = "backup-example"
+		r := newReconciler()
+		Expect(r.reconcileDatabaseRestore(ctx, database)).To(Succeed())
+		Expect(restoredFile).To(BeEmpty())
+	})
+
+	It("fails when the database is not part of the backup", func(ctx SpecContext) {
+		database.Spec.Restore.Database = "missing"
+		r := newReconciler()
+		Expect(r.reconcileDatabaseRestore(ctx, database)).ToNot(Succeed())
+		Expect(restoredFile).To(BeEmpty())
+	})
+
+	It("fails when the backup volume is not mounted in the cluster", func(ctx SpecContext) {
+		cluster.Spec.Backup.Logical.ClaimName = "other"
+		r := newReconciler()
+		Expect(r.reconcileDatabaseRestore(ctx, database)).ToNot(Succeed())
+		Expect(restoredFile).To(BeEmpty())
+	})
+
+	It("does not record the backup as restored when pg_restore fails", func(ctx SpecContext) {
+		restoreErr = errors.New("pg_restore failed")
+		r := newReconciler()
+		Expect(r.reconcileDatabaseRestore(ctx, database)).ToNot(Succeed())
+		Expect(database.Status.RestoredFrom).To(BeEmpty())
+	})
+})
@@ -55,6 +55,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/configfile"
 	postgresManagement "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/logpipe"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/metrics"
 	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver/metricserver"
@@ -174,6 +175,8 @@ func (r *InstanceReconciler) Reconcile(
 	// Reconcile monitoring section
 	r.reconcileMetrics(ctx, cluster)
 	r.reconcileMonitoringQueries(ctx, cluster)
+	r.reconcileOTLP(ctx, cluster)
+	r.reconcileSlowQueryLog(ctx, cluster)
 
 	// Verify that the promotion token is usable before changing the archive mode and triggering restarts
 	if err := r.verifyPromotionToken(cluster); err != nil {
@@ -984,6 +987,32 @@ func (r *InstanceReconciler) reconcileMetrics(
 	}
 }
 
+// reconcileOTLP applies the OTLP configuration to the push pipeline
+func (r *InstanceReconciler) reconcileOTLP(ctx context.Context, cluster *apiv1.Cluster) {
+	if r.otlpPipeline == nil {
+		return
+	}
+
+	if err := r.otlpPipeline.Configure(ctx, cluster.GetOTLP()); err != nil {
+		log.FromContext(ctx).Error(err, "while configuring the OTLP pipeline")
+	}
+}
+
+// reconcileSlowQueryLog applies the routing of the statement duration
+// records and of the auto_explain plans
+func (r *InstanceReconciler) reconcileSlowQueryLog(ctx context.Context, cluster *apiv1.Cluster) {
+	if r.slowQueryRouter == nil {
+		return
+	}
+
+	if err := r.slowQueryRouter.Configure(
+		logpipe.SlowQueryDestination(cluster.GetSlowQueryLogDestination()),
+		cluster.GetSlowQueryLogMaxFileSize(),
+	); err != nil {
+		log.FromContext(ctx).Error(err, "while configuring the slow query log")
+	}
+}
+
 // reconcileMonitoringQueries applies the custom monitoring queries to the
 // web server
 func (r *InstanceReconciler) reconcileMonitoringQueries(
@@ -1000,6 +1029,7 @@ func (r *InstanceReconciler) reconcileMonitoringQueries(
 
 	queriesCollector := metrics.NewQueriesCollector("cnpg", r.instance, dbname)
 	queriesCollector.InjectUserQueries(metricserver.DefaultQueries)
+	queriesCollector.SetMaxParallelQueries(cluster.GetMetricsQueriesParallelism())
 
 	if cluster.Spec.Monitoring == nil {
 		r.metricsServerExporter.SetCustomQueries(queriesCollector)
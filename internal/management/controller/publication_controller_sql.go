@@ -23,6 +23,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"maps"
+	"strconv"
 	"strings"
 
 	"github.com/jackc/pgx/v5"
@@ -30,6 +32,16 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 )
 
+const (
+	// defaultPublicationPublish is the PostgreSQL default
+	// for the `publish` option of a publication
+	defaultPublicationPublish = "insert, update, delete, truncate"
+
+	// defaultPublicationPublishViaPartitionRoot is the PostgreSQL default
+	// for the `publish_via_partition_root` option of a publication
+	defaultPublicationPublishViaPartitionRoot = "false"
+)
+
 func (r *PublicationReconciler) alignPublication(ctx context.Context, obj *apiv1.Publication) error {
 	db, err := r.getDB(obj.Spec.DBName)
 	if err != nil {
@@ -70,7 +82,7 @@ func (r *PublicationReconciler) patchPublication(
 ) error {
 	sqls := toPublicationAlterSQL(obj)
 	for _, sqlQuery := range sqls {
-		if _, err := db.ExecContext(ctx, sqlQuery); err != nil {
+		if err := execPublicationStatement(ctx, db, sqlQuery); err != nil {
 			return err
 		}
 	}
@@ -84,7 +96,24 @@ func (r *PublicationReconciler) createPublication(
 	obj *apiv1.Publication,
 ) error {
 	sqlQuery := toPublicationCreateSQL(obj)
-	_, err := db.ExecContext(ctx, sqlQuery)
+	return execPublicationStatement(ctx, db, sqlQuery)
+}
+
+// execPublicationStatement runs a statement using the extended query
+// protocol, which rejects queries made of more than one statement.
+// The row filters are embedded in the statements as they are, and the
+// simple query protocol, used by pgx when there are no arguments, would
+// execute any statement appended to them.
+func execPublicationStatement(ctx context.Context, db *sql.DB, sqlQuery string) error {
+	stmt, err := db.PrepareContext(ctx, sqlQuery)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	_, err = stmt.ExecContext(ctx)
 	return err
 }
 
@@ -94,8 +123,8 @@ func toPublicationCreateSQL(obj *apiv1.Publication) string {
 		pgx.Identifier{obj.Spec.Name}.Sanitize(),
 		toPublicationTargetSQL(&obj.Spec.Target),
 	)
-	if len(obj.Spec.Parameters) > 0 {
-		createQuery = fmt.Sprintf("%s WITH (%s)", createQuery, toPostgresParameters(obj.Spec.Parameters))
+	if parameters := toPublicationParameters(&obj.Spec); len(parameters) > 0 {
+		createQuery = fmt.Sprintf("%s WITH (%s)", createQuery, toPostgresParameters(parameters))
 	}
 
 	return createQuery
@@ -114,19 +143,56 @@ func toPublicationAlterSQL(obj *apiv1.Publication) []string {
 		)
 	}
 
-	if len(obj.Spec.Parameters) > 0 {
-		result = append(result,
-			fmt.Sprintf(
-				"ALTER PUBLICATION %s SET (%s)",
-				pgx.Identifier{obj.Spec.Name}.Sanitize(),
-				toPostgresParameters(obj.Spec.Parameters),
-			),
-		)
+	// The publish options removed from the spec are reset to
+	// the PostgreSQL defaults
+	parameters := maps.Clone(toPublicationParameters(&obj.Spec))
+	if parameters == nil {
+		parameters = make(map[string]string, 2)
+	}
+	if _, ok := parameters["publish"]; !ok {
+		parameters["publish"] = defaultPublicationPublish
+	}
+	if _, ok := parameters["publish_via_partition_root"]; !ok {
+		parameters["publish_via_partition_root"] = defaultPublicationPublishViaPartitionRoot
 	}
+	result = append(result,
+		fmt.Sprintf(
+			"ALTER PUBLICATION %s SET (%s)",
+			pgx.Identifier{obj.Spec.Name}.Sanitize(),
+			toPostgresParameters(parameters),
+		),
+	)
 
 	return result
 }
 
+// toPublicationParameters gets the parameters of the `WITH` clause of a
+// publication, merging the typed fields into the generic parameters
+func toPublicationParameters(spec *apiv1.PublicationSpec) map[string]string {
+	if len(spec.Publish) == 0 && spec.PublishViaPartitionRoot == nil {
+		return spec.Parameters
+	}
+
+	parameters := maps.Clone(spec.Parameters)
+	if parameters == nil {
+		parameters = make(map[string]string, 2)
+	}
+
+	if len(spec.Publish) > 0 {
+		operations := make([]string, len(spec.Publish))
+		for i, operation := range spec.Publish {
+			operations[i] = string(operation)
+		}
+		parameters["publish"] = strings.Join(operations, ", ")
+	}
+
+	if spec.PublishViaPartitionRoot != nil {
+		parameters["publish_via_partition_root"] = strconv.FormatBool(*spec.PublishViaPartitionRoot)
+	}
+
+	return parameters
+}
+
 func executeDropPublication(ctx context.Context, db *sql.DB, name string) error {
 	if _, err := db.ExecContext(
 		ctx,
@@ -202,5 +268,9 @@ func toTableDefinitionSQL(table *apiv1.PublicationTargetTable) string {
 		fmt.Fprintf(&result, " (%s)", strings.Join(sanitizedColumns, ", "))
 	}
 
+	if len(table.Where) > 0 {
+		fmt.Fprintf(&result, " WHERE (%s)", table.Where)
+	}
+
 	return result.String()
 }
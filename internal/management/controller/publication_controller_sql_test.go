@@ -25,6 +25,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jackc/pgx/v5"
+	"k8s.io/utils/ptr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 
@@ -103,10 +104,32 @@ var _ = Describe("publication sql", func() {
 		}
 
 		sqls := toPublicationAlterSQL(obj)
-		Expect(sqls).To(ContainElement(`ALTER PUBLICATION "test_pub" SET ("param1" = 'value1', "param2" = 'value2')`))
+		Expect(sqls).To(ContainElement(`ALTER PUBLICATION "test_pub" SET ("param1" = 'value1', "param2" = 'value2', ` +
+			`"publish" = 'insert, update, delete, truncate', "publish_via_partition_root" = 'false')`))
 	})
 
-	It("returns empty SQL list when no alterations are needed", func() {
+	It("alters the publish options and the row filters in place", func() {
+		obj := &apiv1.Publication{
+			Spec: apiv1.PublicationSpec{
+				Name:                    "test_pub",
+				Publish:                 []apiv1.PublicationOperation{apiv1.PublicationOperationInsert},
+				PublishViaPartitionRoot: ptr.To(true),
+				Target: apiv1.PublicationTarget{
+					Objects: []apiv1.PublicationTargetObject{
+						{Table: &apiv1.PublicationTargetTable{Name: "orders", Where: "region = 'EU'"}},
+					},
+				},
+			},
+		}
+
+		sqls := toPublicationAlterSQL(obj)
+		Expect(sqls).To(Equal([]string{
+			`ALTER PUBLICATION "test_pub" SET TABLE "orders" WHERE (region = 'EU')`,
+			`ALTER PUBLICATION "test_pub" SET ("publish" = 'insert', "publish_via_partition_root" = 'true')`,
+		}))
+	})
+
+	It("resets the publish options to their defaults when they are not set", func() {
 		obj := &apiv1.Publication{
 			Spec: apiv1.PublicationSpec{
 				Name: "test_pub",
@@ -114,7 +137,62 @@ var _ = Describe("publication sql", func() {
 		}
 
 		sqls := toPublicationAlterSQL(obj)
-		Expect(sqls).To(BeEmpty())
+		Expect(sqls).To(Equal([]string{
+			`ALTER PUBLICATION "test_pub" SET ("publish" = 'insert, update, delete, truncate', ` +
+				`"publish_via_partition_root" = 'false')`,
+		}))
+	})
+
+	It("resets a publish option removed from the spec", func() {
+		obj := &apiv1.Publication{
+			Spec: apiv1.PublicationSpec{
+				Name:                    "test_pub",
+				Publish:                 []apiv1.PublicationOperation{apiv1.PublicationOperationInsert},
+				PublishViaPartitionRoot: ptr.To(true),
+			},
+		}
+		Expect(toPublicationAlterSQL(obj)).To(Equal([]string{
+			`ALTER PUBLICATION "test_pub" SET ("publish" = 'insert', "publish_via_partition_root" = 'true')`,
+		}))
+
+		obj.Spec.PublishViaPartitionRoot = nil
+		Expect(toPublicationAlterSQL(obj)).To(Equal([]string{
+			`ALTER PUBLICATION "test_pub" SET ("publish" = 'insert', "publish_via_partition_root" = 'false')`,
+		}))
+
+		obj.Spec.Publish = nil
+		obj.Spec.Parameters = map[string]string{"publish_via_partition_root": "true"}
+		Expect(toPublicationAlterSQL(obj)).To(Equal([]string{
+			`ALTER PUBLICATION "test_pub" SET ("publish" = 'insert, update, delete, truncate', ` +
+				`"publish_via_partition_root" = 'true')`,
+		}))
+	})
+
+	It("runs the statements with the extended query protocol", func(ctx SpecContext) {
+		obj := &apiv1.Publication{
+			Spec: apiv1.PublicationSpec{
+				Name: "test_pub",
+				Target: apiv1.PublicationTarget{
+					Objects: []apiv1.PublicationTargetObject{
+						{Table: &apiv1.PublicationTargetTable{
+							Name:  "orders",
+							Where: "true); DROP TABLE orders; SELECT (1",
+						}},
+					},
+				},
+			},
+		}
+
+		// The malicious row filter results in more than one statement,
+		// which PostgreSQL refuses to prepare
+		expectedQuery := `CREATE PUBLICATION "test_pub" FOR TABLE "orders" WHERE (true); DROP TABLE orders; SELECT (1)`
+		Expect(toPublicationCreateSQL(obj)).To(Equal(expectedQuery))
+		dbMock.ExpectPrepare(expectedQuery).
+			WillReturnError(fmt.Errorf("cannot insert multiple commands into a prepared statement"))
+
+		r := &PublicationReconciler{}
+		err := r.createPublication(ctx, db, obj)
+		Expect(err).To(MatchError(ContainSubstring("cannot insert multiple commands")))
 	})
 
 	It("generates correct SQL for creating publication with target schema", func() {
@@ -227,4 +305,42 @@ var _ = Describe("toPublicationCreateSQL", func() {
 		sql := toPublicationCreateSQL(obj)
 		Expect(sql).To(Equal(`CREATE PUBLICATION "test_pub" FOR TABLE "table1" ("a", "b"), "table2" ("c")`))
 	})
+
+	It("returns correct SQL for tables with row filters", func() {
+		obj := &apiv1.Publication{
+			Spec: apiv1.PublicationSpec{
+				Name: "test_pub",
+				Target: apiv1.PublicationTarget{
+					Objects: []apiv1.PublicationTargetObject{
+						{Table: &apiv1.PublicationTargetTable{Name: "table1", Columns: []string{"a"}, Where: "a > 10"}},
+						{Table: &apiv1.PublicationTargetTable{Name: "table2"}},
+					},
+				},
+			},
+		}
+
+		sql := toPublicationCreateSQL(obj)
+		Expect(sql).To(Equal(`CREATE PUBLICATION "test_pub" FOR TABLE "table1" ("a") WHERE (a > 10), "table2"`))
+	})
+
+	It("merges the publish options with the parameters", func() {
+		obj := &apiv1.Publication{
+			Spec: apiv1.PublicationSpec{
+				Name:       "test_pub",
+				Parameters: map[string]string{"param1": "value1"},
+				Publish: []apiv1.PublicationOperation{
+					apiv1.PublicationOperationInsert,
+					apiv1.PublicationOperationUpdate,
+				},
+				PublishViaPartitionRoot: ptr.To(false),
+				Target:                  apiv1.PublicationTarget{AllTables: true},
+			},
+		}
+
+		sql := toPublicationCreateSQL(obj)
+		Expect(sql).To(Equal(
+			`CREATE PUBLICATION "test_pub" FOR ALL TABLES WITH ("param1" = 'value1', ` +
+				`"publish" = 'insert, update', "publish_via_partition_root" = 'false')`))
+		Expect(obj.Spec.Parameters).To(HaveLen(1))
+	})
 })
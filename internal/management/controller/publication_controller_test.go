@@ -133,7 +133,7 @@ var _ = Describe("Managed publication controller tests", func() {
 			"CREATE PUBLICATION %s FOR ALL TABLES",
 			pgx.Identifier{publication.Spec.Name}.Sanitize(),
 		)
-		dbMock.ExpectExec(expectedQuery).WillReturnResult(expectedCreate)
+		dbMock.ExpectPrepare(expectedQuery).ExpectExec().WillReturnResult(expectedCreate)
 
 		err := reconcilePublication(ctx, fakeClient, r, publication)
 		Expect(err).ToNot(HaveOccurred())
@@ -152,7 +152,7 @@ var _ = Describe("Managed publication controller tests", func() {
 		expectedQuery := fmt.Sprintf("ALTER PUBLICATION %s SET TABLES IN SCHEMA \"public\"",
 			pgx.Identifier{publication.Spec.Name}.Sanitize(),
 		)
-		dbMock.ExpectExec(expectedQuery).WillReturnError(expectedError)
+		dbMock.ExpectPrepare(expectedQuery).ExpectExec().WillReturnError(expectedError)
 
 		err := reconcilePublication(ctx, fakeClient, r, publication)
 		Expect(err).ToNot(HaveOccurred())
@@ -174,7 +174,7 @@ var _ = Describe("Managed publication controller tests", func() {
 				"CREATE PUBLICATION %s FOR ALL TABLES",
 				pgx.Identifier{publication.Spec.Name}.Sanitize(),
 			)
-			dbMock.ExpectExec(expectedQuery).WillReturnResult(expectedCreate)
+			dbMock.ExpectPrepare(expectedQuery).ExpectExec().WillReturnResult(expectedCreate)
 
 			// Mocking Drop Publication
 			expectedDrop := fmt.Sprintf("DROP PUBLICATION IF EXISTS %s",
@@ -238,7 +238,7 @@ var _ = Describe("Managed publication controller tests", func() {
 				"CREATE PUBLICATION %s FOR ALL TABLES",
 				pgx.Identifier{publication.Spec.Name}.Sanitize(),
 			)
-			dbMock.ExpectExec(expectedQuery).WillReturnResult(expectedCreate)
+			dbMock.ExpectPrepare(expectedQuery).ExpectExec().WillReturnResult(expectedCreate)
 
 			err := reconcilePublication(ctx, fakeClient, r, publication)
 			Expect(err).ToNot(HaveOccurred())
@@ -277,7 +277,7 @@ var _ = Describe("Managed publication controller tests", func() {
 				"CREATE PUBLICATION %s FOR ALL TABLES",
 				pgx.Identifier{publication.Spec.Name}.Sanitize(),
 			)
-			dbMock.ExpectExec(expectedQuery).WillReturnResult(expectedCreate)
+			dbMock.ExpectPrepare(expectedQuery).ExpectExec().WillReturnResult(expectedCreate)
 
 			err := reconcilePublication(ctx, fakeClient, r, publication)
 			Expect(err).ToNot(HaveOccurred())
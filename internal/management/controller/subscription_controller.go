@@ -26,7 +26,9 @@ import (
 	"time"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -37,6 +39,7 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/external"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
@@ -48,6 +51,7 @@ type SubscriptionReconciler struct {
 	instance                *postgres.Instance
 	finalizerReconciler     *finalizerReconciler[*apiv1.Subscription]
 	getDB                   func(name string) (*sql.DB, error)
+	getPublisherDB          func(connString string) (*sql.DB, error)
 	getPostgresMajorVersion func() (int, error)
 }
 
@@ -101,9 +105,14 @@ func (r *SubscriptionReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		// and evaluate the subscription again after the promotion.
 		result, proceed, err := handleReplicaRoleTransition(
 			ctx, r.Client, r.instance, cluster, &subscription, subscriptionReconciliationInterval)
-		if err != nil || !proceed {
+		if err != nil {
 			return result, err
 		}
+		if !proceed {
+			// An applied subscription still needs its statistics and
+			// sequences to be refreshed
+			return r.refreshStatus(ctx, cluster, &subscription, result)
+		}
 	}
 
 	// Still not for me, we're waiting for a switchover
@@ -180,12 +189,100 @@ func (r *SubscriptionReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	}
 
 	contextLogger.Info("Reconciliation of subscription completed")
+	_ = r.updateStatistics(ctx, &subscription)
+	_ = r.updateSequences(ctx, cluster, &subscription)
 	if err := markAsReady(ctx, r.Client, &subscription); err != nil {
 		return ctrl.Result{}, err
 	}
 	return ctrl.Result{RequeueAfter: subscriptionReconciliationInterval}, nil
 }
 
+// refreshStatus refreshes the statistics reported in the status of an
+// applied subscription, and copies its sequences from the publisher when
+// requested. As they are not event-driven, the primary instance polls
+// them at every reconciliation interval.
+func (r *SubscriptionReconciler) refreshStatus(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	subscription *apiv1.Subscription,
+	result ctrl.Result,
+) (ctrl.Result, error) {
+	if cluster.IsReplica() ||
+		!subscription.GetDeletionTimestamp().IsZero() ||
+		cluster.Status.CurrentPrimary != r.instance.GetPodName() {
+		return result, nil
+	}
+
+	// Updating the status triggers a new reconciliation, which
+	// must not refresh the status again
+	if lastRefresh := getSubscriptionLastRefresh(subscription); !lastRefresh.IsZero() {
+		if elapsed := time.Since(lastRefresh); elapsed < subscriptionReconciliationInterval {
+			return ctrl.Result{RequeueAfter: subscriptionReconciliationInterval - elapsed}, nil
+		}
+	}
+
+	statisticsUpdated := r.updateStatistics(ctx, subscription)
+	sequencesUpdated := r.updateSequences(ctx, cluster, subscription)
+	if statisticsUpdated || sequencesUpdated {
+		if err := r.Status().Update(ctx, subscription); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: subscriptionReconciliationInterval}, nil
+}
+
+// updateStatistics collects the statistics of the subscription into its
+// status, returning true on success. A failure is not a reconciliation
+// error: the statistics that were previously collected are kept, and
+// refreshed at the next attempt.
+func (r *SubscriptionReconciler) updateStatistics(ctx context.Context, subscription *apiv1.Subscription) bool {
+	statistics, err := r.collectSubscriptionStatistics(ctx, subscription)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "while collecting the subscription statistics")
+		return false
+	}
+
+	subscription.Status.Statistics = statistics
+	return true
+}
+
+// updateSequences copies the values of the sequences from the publisher,
+// when requested, recording the time of the synchronization in the status
+// and returning true on success. As for the statistics, a failure is only
+// logged and the synchronization is retried at the next attempt.
+func (r *SubscriptionReconciler) updateSequences(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	subscription *apiv1.Subscription,
+) bool {
+	if !subscription.Spec.SyncSequences {
+		return false
+	}
+
+	if err := r.syncSequences(ctx, cluster, subscription); err != nil {
+		log.FromContext(ctx).Error(err, "while synchronizing the subscription sequences")
+		return false
+	}
+
+	subscription.Status.SequencesSyncedAt = ptr.To(metav1.Now())
+	return true
+}
+
+// getSubscriptionLastRefresh returns when the status of the subscription
+// was last refreshed, or the zero time if it never was
+func getSubscriptionLastRefresh(subscription *apiv1.Subscription) time.Time {
+	var lastRefresh time.Time
+	if statistics := subscription.Status.Statistics; statistics != nil {
+		lastRefresh = statistics.UpdateTime.Time
+	}
+	if syncedAt := subscription.Status.SequencesSyncedAt; syncedAt != nil && syncedAt.After(lastRefresh) {
+		lastRefresh = syncedAt.Time
+	}
+
+	return lastRefresh
+}
+
 func (r *SubscriptionReconciler) evaluateDropSubscription(ctx context.Context, sub *apiv1.Subscription) error {
 	if sub.Spec.ReclaimPolicy != apiv1.SubscriptionReclaimDelete {
 		return nil
@@ -227,6 +324,9 @@ func NewSubscriptionReconciler(
 		getDB: func(name string) (*sql.DB, error) {
 			return instance.ConnectionPool().Connection(name)
 		},
+		getPublisherDB: func(connString string) (*sql.DB, error) {
+			return pool.NewDBConnection(connString, pool.ConnectionProfilePostgresql)
+		},
 		getPostgresMajorVersion: func() (int, error) {
 			version, err := instance.GetPgVersion()
 			return int(version.Major()), err //nolint:gosec
@@ -23,11 +23,18 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
 
+	"github.com/cloudnative-pg/machinery/pkg/log"
 	"github.com/jackc/pgx/v5"
 	"github.com/lib/pq"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 )
 
 func (r *SubscriptionReconciler) alignSubscription(
@@ -57,11 +64,160 @@ func (r *SubscriptionReconciler) alignSubscription(
 		if err := r.patchSubscription(ctx, db, obj, connString); err != nil {
 			return fmt.Errorf("while patching subscription: %w", err)
 		}
+	} else {
+		if err := r.createSubscription(ctx, db, obj, connString); err != nil {
+			return fmt.Errorf("while creating subscription: %w", err)
+		}
+	}
+
+	if err := r.skipSubscriptionLSN(ctx, db, obj); err != nil {
+		return fmt.Errorf("while skipping LSN %s: %w", obj.Spec.SkipLSN, err)
+	}
+
+	return nil
+}
+
+// skipSubscriptionLSN makes the apply worker skip the remote transaction
+// finishing at the requested LSN. Every LSN is applied only once, as the
+// skip is cleared by PostgreSQL once the transaction has been skipped.
+func (r *SubscriptionReconciler) skipSubscriptionLSN(
+	ctx context.Context,
+	db *sql.DB,
+	obj *apiv1.Subscription,
+) error {
+	if obj.Spec.SkipLSN == "" || obj.Spec.SkipLSN == obj.Status.SkippedLSN {
 		return nil
 	}
 
-	if err := r.createSubscription(ctx, db, obj, connString); err != nil {
-		return fmt.Errorf("while creating subscription: %w", err)
+	if _, err := db.ExecContext(ctx, toSubscriptionSkipSQL(obj)); err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info("skipped subscription LSN", "lsn", obj.Spec.SkipLSN)
+	obj.Status.SkippedLSN = obj.Spec.SkipLSN
+	return nil
+}
+
+// collectSubscriptionStatistics reads the statistics of the subscription
+// and the synchronization state of its tables
+func (r *SubscriptionReconciler) collectSubscriptionStatistics(
+	ctx context.Context,
+	obj *apiv1.Subscription,
+) (*apiv1.SubscriptionStatistics, error) {
+	db, err := r.getDB(obj.Spec.DBName)
+	if err != nil {
+		return nil, fmt.Errorf("while getting DB connection: %w", err)
+	}
+
+	version, err := r.getPostgresMajorVersion()
+	if err != nil {
+		return nil, fmt.Errorf("while getting the PostgreSQL major version: %w", err)
+	}
+
+	subscriptionStats, err := postgres.GetSubscriptionStats(ctx, db, version)
+	if err != nil {
+		return nil, fmt.Errorf("while getting the subscription statistics: %w", err)
+	}
+
+	tables, err := postgres.GetSubscriptionTables(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("while getting the subscription tables: %w", err)
+	}
+
+	return toSubscriptionStatistics(obj.Spec.Name, subscriptionStats, tables), nil
+}
+
+// toSubscriptionStatistics builds the statistics reported in the status
+// of the subscription with the passed name. Only the tables not in the
+// ready state are listed, to keep the size of the status bounded.
+func toSubscriptionStatistics(
+	name string,
+	subscriptionStats []postgres.SubscriptionStats,
+	tables []postgres.SubscriptionTable,
+) *apiv1.SubscriptionStatistics {
+	result := &apiv1.SubscriptionStatistics{
+		UpdateTime: metav1.Now(),
+	}
+
+	for _, stats := range subscriptionStats {
+		if stats.Name != name {
+			continue
+		}
+
+		result.Enabled = stats.Enabled
+		result.ReceivedLSN = string(stats.ReceivedLSN)
+		result.LatestEndLSN = string(stats.LatestEndLSN)
+		if stats.LatestEndTime.Valid {
+			result.LatestEndTime = ptr.To(metav1.NewTime(stats.LatestEndTime.Time))
+		}
+		result.ApplyErrorCount = stats.ApplyErrorCount
+		result.SyncErrorCount = stats.SyncErrorCount
+	}
+
+	for _, table := range tables {
+		if table.SubscriptionName != name {
+			continue
+		}
+
+		if table.State == apiv1.SubscriptionTableStateReady {
+			result.ReadyTables++
+			continue
+		}
+
+		result.Tables = append(result.Tables, apiv1.SubscriptionTableStatus{
+			Schema: table.Schema,
+			Name:   table.Name,
+			State:  table.State,
+			LSN:    string(table.LSN),
+		})
+	}
+
+	return result
+}
+
+// syncSequences sets the sequences of the subscriber database to the
+// values they have in the publisher database
+func (r *SubscriptionReconciler) syncSequences(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	obj *apiv1.Subscription,
+) error {
+	connString, err := getSubscriptionConnectionString(
+		cluster,
+		obj.Spec.ExternalClusterName,
+		obj.Spec.PublicationDBName,
+	)
+	if err != nil {
+		return err
+	}
+
+	publisherDB, err := r.getPublisherDB(connString)
+	if err != nil {
+		return fmt.Errorf("while connecting to the publisher: %w", err)
+	}
+	defer func() {
+		_ = publisherDB.Close()
+	}()
+
+	source, err := postgres.GetSequenceValues(ctx, publisherDB)
+	if err != nil {
+		return fmt.Errorf("while getting the sequences of the publisher: %w", err)
+	}
+
+	db, err := r.getDB(obj.Spec.DBName)
+	if err != nil {
+		return fmt.Errorf("while getting DB connection: %w", err)
+	}
+
+	destination, err := postgres.GetSequenceValues(ctx, db)
+	if err != nil {
+		return fmt.Errorf("while getting the sequences of the subscriber: %w", err)
+	}
+
+	for _, sqlQuery := range toSequencesSyncSQL(source, destination) {
+		if _, err := db.ExecContext(ctx, sqlQuery); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -77,7 +233,12 @@ func (r *SubscriptionReconciler) patchSubscription(
 	if err != nil {
 		return fmt.Errorf("while getting the PostgreSQL major version: %w", err)
 	}
-	sqls := toSubscriptionAlterSQL(obj, connString, version)
+	failoverUpdatable, err := isSubscriptionFailoverUpdatable(ctx, db, obj, version)
+	if err != nil {
+		return fmt.Errorf("while checking the failover setting of the subscription: %w", err)
+	}
+
+	sqls := toSubscriptionAlterSQL(obj, connString, version, failoverUpdatable)
 	for _, sqlQuery := range sqls {
 		if _, err := db.ExecContext(ctx, sqlQuery); err != nil {
 			return err
@@ -87,6 +248,53 @@ func (r *SubscriptionReconciler) patchSubscription(
 	return nil
 }
 
+// isSubscriptionFailoverUpdatable checks whether the `failover` parameter
+// has to be sent to PostgreSQL, which rejects any change of it while the
+// subscription is enabled. The parameter is only sent when the requested
+// value differs from the current one and the subscription is disabled.
+func isSubscriptionFailoverUpdatable(
+	ctx context.Context,
+	db *sql.DB,
+	obj *apiv1.Subscription,
+	pgMajorVersion int,
+) (bool, error) {
+	value, present := toSubscriptionParameters(&obj.Spec)["failover"]
+	if !present || pgMajorVersion < 17 {
+		return false, nil
+	}
+
+	requested, err := strconv.ParseBool(value)
+	if err != nil {
+		// let PostgreSQL report the invalid value
+		return true, nil
+	}
+
+	var enabled, failover bool
+	row := db.QueryRowContext(
+		ctx,
+		`
+		SELECT subenabled, subfailover
+		FROM pg_catalog.pg_subscription
+		WHERE subname = $1
+		`,
+		obj.Spec.Name)
+	if err := row.Scan(&enabled, &failover); err != nil {
+		return false, err
+	}
+
+	if requested == failover {
+		return false, nil
+	}
+	if enabled {
+		log.FromContext(ctx).Info(
+			"the failover setting of the subscription can only be changed while it is disabled",
+			"subscriptionName", obj.Spec.Name, "failover", requested)
+		return false, nil
+	}
+
+	return true, nil
+}
+
 func (r *SubscriptionReconciler) createSubscription(
 	ctx context.Context,
 	db *sql.DB,
@@ -105,14 +313,19 @@ func toSubscriptionCreateSQL(obj *apiv1.Subscription, connString string) string
 		pq.QuoteLiteral(connString),
 		pgx.Identifier{obj.Spec.PublicationName}.Sanitize(),
 	)
-	if len(obj.Spec.Parameters) > 0 {
-		createQuery = fmt.Sprintf("%s WITH (%s)", createQuery, toPostgresParameters(obj.Spec.Parameters))
+	if parameters := toSubscriptionParameters(&obj.Spec); len(parameters) > 0 {
+		createQuery = fmt.Sprintf("%s WITH (%s)", createQuery, toPostgresParameters(parameters))
 	}
 
 	return createQuery
 }
 
-func toSubscriptionAlterSQL(obj *apiv1.Subscription, connString string, pgMajorVersion int) []string {
+func toSubscriptionAlterSQL(
+	obj *apiv1.Subscription,
+	connString string,
+	pgMajorVersion int,
+	failoverUpdatable bool,
+) []string {
 	result := make([]string, 0, 3)
 
 	setPublicationSQL := fmt.Sprintf(
@@ -128,12 +341,16 @@ func toSubscriptionAlterSQL(obj *apiv1.Subscription, connString string, pgMajorV
 	)
 	result = append(result, setPublicationSQL, setConnStringSQL)
 
-	if len(obj.Spec.Parameters) > 0 {
+	parameters := filterSubscriptionUpdatableParameters(toSubscriptionParameters(&obj.Spec), pgMajorVersion)
+	if !failoverUpdatable {
+		delete(parameters, "failover")
+	}
+	if len(parameters) > 0 {
 		result = append(result,
 			fmt.Sprintf(
 				"ALTER SUBSCRIPTION %s SET (%s)",
 				pgx.Identifier{obj.Spec.Name}.Sanitize(),
-				toPostgresParameters(filterSubscriptionUpdatableParameters(obj.Spec.Parameters, pgMajorVersion)),
+				toPostgresParameters(parameters),
 			),
 		)
 	}
@@ -141,6 +358,61 @@ func toSubscriptionAlterSQL(obj *apiv1.Subscription, connString string, pgMajorV
 	return result
 }
 
+func toSubscriptionSkipSQL(obj *apiv1.Subscription) string {
+	return fmt.Sprintf(
+		"ALTER SUBSCRIPTION %s SKIP (lsn = %s)",
+		pgx.Identifier{obj.Spec.Name}.Sanitize(),
+		pq.QuoteLiteral(obj.Spec.SkipLSN),
+	)
+}
+
+// toSubscriptionParameters gets the parameters of the `WITH` clause of a
+// subscription, merging the typed fields into the generic parameters
+// toSequencesSyncSQL builds the statements setting the sequences of the
+// destination to their value in the source. The sequences missing in the
+// destination, never used in the source or already having the same value
+// are ignored.
+func toSequencesSyncSQL(source, destination map[string]*int64) []string {
+	var result []string
+	for _, name := range slices.Sorted(maps.Keys(source)) {
+		value := source[name]
+		currentValue, exists := destination[name]
+		if value == nil || !exists || (currentValue != nil && *currentValue == *value) {
+			continue
+		}
+
+		result = append(result, fmt.Sprintf("SELECT pg_catalog.setval(%s, %d)", pq.QuoteLiteral(name), *value))
+	}
+
+	return result
+}
+
+func toSubscriptionParameters(spec *apiv1.SubscriptionSpec) map[string]string {
+	if spec.Streaming == "" && spec.DisableOnError == nil && spec.Origin == "" && spec.Failover == nil {
+		return spec.Parameters
+	}
+
+	parameters := maps.Clone(spec.Parameters)
+	if parameters == nil {
+		parameters = make(map[string]string, 4)
+	}
+
+	if spec.Streaming != "" {
+		parameters["streaming"] = string(spec.Streaming)
+	}
+	if spec.DisableOnError != nil {
+		parameters["disable_on_error"] = strconv.FormatBool(*spec.DisableOnError)
+	}
+	if spec.Origin != "" {
+		parameters["origin"] = string(spec.Origin)
+	}
+	if spec.Failover != nil {
+		parameters["failover"] = strconv.FormatBool(*spec.Failover)
+	}
+
+	return parameters
+}
+
 func filterSubscriptionUpdatableParameters(parameters map[string]string, pgMajorVersion int) map[string]string {
 	// Only a limited set of the parameters can be updated
 	// see https://www.postgresql.org/docs/current/sql-altersubscription.html#SQL-ALTERSUBSCRIPTION-PARAMS-SET
@@ -22,11 +22,14 @@ package controller
 import (
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jackc/pgx/v5"
+	"k8s.io/utils/ptr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -132,7 +135,7 @@ var _ = Describe("subscription sql", func() {
 		}
 		connString := "host=localhost user=test dbname=test"
 
-		sqls := toSubscriptionAlterSQL(obj, connString, defaultPostgresMajorVersion)
+		sqls := toSubscriptionAlterSQL(obj, connString, defaultPostgresMajorVersion, false)
 		Expect(sqls).To(ContainElement(`ALTER SUBSCRIPTION "test_sub" SET PUBLICATION "test_pub"`))
 		Expect(sqls).To(ContainElement(`ALTER SUBSCRIPTION "test_sub" CONNECTION 'host=localhost user=test dbname=test'`))
 	})
@@ -152,7 +155,7 @@ var _ = Describe("subscription sql", func() {
 		}
 		connString := "host=localhost user=test dbname=test"
 
-		sqls := toSubscriptionAlterSQL(obj, connString, 17)
+		sqls := toSubscriptionAlterSQL(obj, connString, 17, true)
 		Expect(sqls).To(ContainElement(`ALTER SUBSCRIPTION "test_sub" SET PUBLICATION "test_pub"`))
 		Expect(sqls).To(ContainElement(`ALTER SUBSCRIPTION "test_sub" CONNECTION 'host=localhost user=test dbname=test'`))
 		Expect(sqls).To(ContainElement(`ALTER SUBSCRIPTION "test_sub" SET ("failover" = 'true', "origin" = 'none')`))
@@ -173,13 +176,34 @@ var _ = Describe("subscription sql", func() {
 		}
 		connString := "host=localhost user=test dbname=test"
 
-		sqls := toSubscriptionAlterSQL(obj, connString, 18)
+		sqls := toSubscriptionAlterSQL(obj, connString, 18, true)
 		Expect(sqls).To(ContainElement(`ALTER SUBSCRIPTION "test_sub" SET PUBLICATION "test_pub"`))
 		Expect(sqls).To(ContainElement(`ALTER SUBSCRIPTION "test_sub" CONNECTION 'host=localhost user=test dbname=test'`))
 		Expect(sqls).To(ContainElement(
 			`ALTER SUBSCRIPTION "test_sub" SET ("failover" = 'true', "origin" = 'none', "two_phase" = 'true')`))
 	})
 
+	It("leaves failover out of the altered parameters when it cannot be updated", func() {
+		obj := &apiv1.Subscription{
+			Spec: apiv1.SubscriptionSpec{
+				Name:            "test_sub",
+				PublicationName: "test_pub",
+				Failover:        ptr.To(true),
+				Parameters: map[string]string{
+					"origin": "none",
+				},
+			},
+		}
+		connString := "host=localhost user=test dbname=test"
+
+		sqls := toSubscriptionAlterSQL(obj, connString, 17, false)
+		Expect(sqls).To(ContainElement(`ALTER SUBSCRIPTION "test_sub" SET ("origin" = 'none')`))
+
+		obj.Spec.Parameters = nil
+		sqls = toSubscriptionAlterSQL(obj, connString, 17, false)
+		Expect(sqls).To(HaveLen(2))
+	})
+
 	It("returns correct SQL for altering subscription with no owner or parameters", func() {
 		obj := &apiv1.Subscription{
 			Spec: apiv1.SubscriptionSpec{
@@ -189,8 +213,131 @@ var _ = Describe("subscription sql", func() {
 		}
 		connString := "host=localhost user=test dbname=test"
 
-		sqls := toSubscriptionAlterSQL(obj, connString, defaultPostgresMajorVersion)
+		sqls := toSubscriptionAlterSQL(obj, connString, defaultPostgresMajorVersion, false)
 		Expect(sqls).To(ContainElement(`ALTER SUBSCRIPTION "test_sub" SET PUBLICATION "test_pub"`))
 		Expect(sqls).To(ContainElement(`ALTER SUBSCRIPTION "test_sub" CONNECTION 'host=localhost user=test dbname=test'`))
 	})
+
+	It("merges the typed options into the parameters when creating the subscription", func() {
+		obj := &apiv1.Subscription{
+			Spec: apiv1.SubscriptionSpec{
+				Name:            "test_sub",
+				PublicationName: "test_pub",
+				Streaming:       apiv1.SubscriptionStreamingParallel,
+				DisableOnError:  ptr.To(true),
+				Origin:          apiv1.SubscriptionOriginNone,
+				Failover:        ptr.To(false),
+				Parameters: map[string]string{
+					"binary": "true",
+				},
+			},
+		}
+		connString := "host=localhost user=test dbname=test"
+
+		sql := toSubscriptionCreateSQL(obj, connString)
+		Expect(sql).To(Equal(`CREATE SUBSCRIPTION "test_sub" ` +
+			`CONNECTION 'host=localhost user=test dbname=test' ` +
+			`PUBLICATION "test_pub" WITH ("binary" = 'true', "disable_on_error" = 'true', ` +
+			`"failover" = 'false', "origin" = 'none', "streaming" = 'parallel')`))
+		Expect(obj.Spec.Parameters).To(HaveLen(1))
+	})
+
+	It("merges the typed options into the parameters when altering the subscription", func() {
+		obj := &apiv1.Subscription{
+			Spec: apiv1.SubscriptionSpec{
+				Name:            "test_sub",
+				PublicationName: "test_pub",
+				Streaming:       apiv1.SubscriptionStreamingOn,
+				DisableOnError:  ptr.To(false),
+			},
+		}
+		connString := "host=localhost user=test dbname=test"
+
+		sqls := toSubscriptionAlterSQL(obj, connString, defaultPostgresMajorVersion, false)
+		Expect(sqls).To(ContainElement(
+			`ALTER SUBSCRIPTION "test_sub" SET ("disable_on_error" = 'false', "streaming" = 'on')`))
+	})
+
+	It("generates correct SQL for skipping a transaction", func() {
+		obj := &apiv1.Subscription{
+			Spec: apiv1.SubscriptionSpec{
+				Name:    "test_sub",
+				SkipLSN: "0/14C0378",
+			},
+		}
+
+		Expect(toSubscriptionSkipSQL(obj)).To(Equal(`ALTER SUBSCRIPTION "test_sub" SKIP (lsn = '0/14C0378')`))
+	})
+
+	It("generates the SQL to copy the sequences changed in the source", func() {
+		source := map[string]*int64{
+			`"public"."orders_id_seq"`: ptr.To[int64](42),
+			`"public"."items_id_seq"`:  ptr.To[int64](7),
+			`"public"."unused_seq"`:    nil,
+			`"public"."missing_seq"`:   ptr.To[int64](3),
+			`"Sales"."invoice-id"`:     ptr.To[int64](100),
+		}
+		destination := map[string]*int64{
+			`"public"."orders_id_seq"`: nil,
+			`"public"."items_id_seq"`:  ptr.To[int64](7),
+			`"public"."unused_seq"`:    ptr.To[int64](1),
+			`"Sales"."invoice-id"`:     ptr.To[int64](90),
+		}
+
+		Expect(toSequencesSyncSQL(source, destination)).To(Equal([]string{
+			`SELECT pg_catalog.setval('"Sales"."invoice-id"', 100)`,
+			`SELECT pg_catalog.setval('"public"."orders_id_seq"', 42)`,
+		}))
+	})
+
+	It("builds the statistics of the subscription listing only the tables not ready", func() {
+		latestEndTime := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+		stats := []postgres.SubscriptionStats{
+			{
+				Name:            "other_sub",
+				Enabled:         false,
+				ApplyErrorCount: 10,
+			},
+			{
+				Name:            "test_sub",
+				Enabled:         true,
+				ReceivedLSN:     "0/3000060",
+				LatestEndLSN:    "0/3000028",
+				LatestEndTime:   sql.NullTime{Time: latestEndTime, Valid: true},
+				ApplyErrorCount: 2,
+				SyncErrorCount:  1,
+			},
+		}
+		tables := []postgres.SubscriptionTable{
+			{SubscriptionName: "test_sub", Schema: "public", Name: "orders", State: apiv1.SubscriptionTableStateReady},
+			{SubscriptionName: "test_sub", Schema: "public", Name: "items", State: apiv1.SubscriptionTableStateReady},
+			{
+				SubscriptionName: "test_sub",
+				Schema:           "public",
+				Name:             "customers",
+				State:            apiv1.SubscriptionTableStateSynchronized,
+				LSN:              "0/3000010",
+			},
+			{SubscriptionName: "other_sub", Schema: "public", Name: "logs", State: apiv1.SubscriptionTableStateDataCopy},
+		}
+
+		statistics := toSubscriptionStatistics("test_sub", stats, tables)
+		Expect(statistics.UpdateTime.IsZero()).To(BeFalse())
+		Expect(statistics.Enabled).To(BeTrue())
+		Expect(statistics.ReceivedLSN).To(Equal("0/3000060"))
+		Expect(statistics.LatestEndLSN).To(Equal("0/3000028"))
+		Expect(statistics.LatestEndTime).ToNot(BeNil())
+		Expect(statistics.LatestEndTime.Time).To(BeTemporally("==", latestEndTime))
+		Expect(statistics.ApplyErrorCount).To(BeEquivalentTo(2))
+		Expect(statistics.SyncErrorCount).To(BeEquivalentTo(1))
+		Expect(statistics.ReadyTables).To(BeEquivalentTo(2))
+		Expect(statistics.Tables).To(Equal([]apiv1.SubscriptionTableStatus{
+			{
+				Schema: "public",
+				Name:   "customers",
+				State:  apiv1.SubscriptionTableStateSynchronized,
+				LSN:    "0/3000010",
+			},
+		}))
+	})
 })
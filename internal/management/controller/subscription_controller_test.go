@@ -23,6 +23,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jackc/pgx/v5"
@@ -49,6 +50,13 @@ const subscriptionDetectionQuery = `SELECT count(*)
 		FROM pg_catalog.pg_subscription
 		WHERE subname = $1`
 
+const subscriptionFailoverQuery = `SELECT subenabled, subfailover
+		FROM pg_catalog.pg_subscription
+		WHERE subname = $1`
+
+const sequencesQuery = `SELECT schemaname, sequencename, last_value
+		FROM pg_catalog.pg_sequences`
+
 var _ = Describe("Managed subscription controller tests", func() {
 	const defaultPostgresMajorVersion = 17
 
@@ -504,6 +512,186 @@ var _ = Describe("Managed subscription controller tests", func() {
 		Expect(subscription.Status.ObservedGeneration).NotTo(BeZero())
 	})
 
+	It("skips the requested LSN once and records it in the status", func(ctx SpecContext) {
+		subscription.Spec.SkipLSN = "0/14C0378"
+		Expect(fakeClient.Update(ctx, subscription)).To(Succeed())
+
+		oneHit := sqlmock.NewRows([]string{""}).AddRow("1")
+		dbMock.ExpectQuery(subscriptionDetectionQuery).WithArgs(subscription.Spec.Name).
+			WillReturnRows(oneHit)
+		for _, query := range toSubscriptionAlterSQL(subscription, connString, defaultPostgresMajorVersion, false) {
+			dbMock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+		dbMock.ExpectExec(`ALTER SUBSCRIPTION "sub-one" SKIP (lsn = '0/14C0378')`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(reconcileSubscription(ctx, fakeClient, r, subscription)).To(Succeed())
+		Expect(subscription.Status.Applied).To(HaveValue(BeTrue()))
+		Expect(subscription.Status.SkippedLSN).To(Equal("0/14C0378"))
+	})
+
+	It("doesn't change failover on an enabled subscription and still skips the LSN", func(ctx SpecContext) {
+		subscription.Spec.Failover = ptr.To(true)
+		subscription.Spec.SkipLSN = "0/14C0378"
+		Expect(fakeClient.Update(ctx, subscription)).To(Succeed())
+
+		oneHit := sqlmock.NewRows([]string{""}).AddRow("1")
+		dbMock.ExpectQuery(subscriptionDetectionQuery).WithArgs(subscription.Spec.Name).
+			WillReturnRows(oneHit)
+		dbMock.ExpectQuery(subscriptionFailoverQuery).WithArgs(subscription.Spec.Name).
+			WillReturnRows(sqlmock.NewRows([]string{"subenabled", "subfailover"}).AddRow(true, false))
+		dbMock.ExpectExec(`ALTER SUBSCRIPTION "sub-one" SET PUBLICATION "pub-all"`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(fmt.Sprintf(`ALTER SUBSCRIPTION "sub-one" CONNECTION %s`, pq.QuoteLiteral(connString))).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(`ALTER SUBSCRIPTION "sub-one" SKIP (lsn = '0/14C0378')`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(reconcileSubscription(ctx, fakeClient, r, subscription)).To(Succeed())
+		Expect(subscription.Status.Applied).To(HaveValue(BeTrue()))
+		Expect(subscription.Status.SkippedLSN).To(Equal("0/14C0378"))
+	})
+
+	It("changes failover on a disabled subscription", func(ctx SpecContext) {
+		subscription.Spec.Failover = ptr.To(true)
+		Expect(fakeClient.Update(ctx, subscription)).To(Succeed())
+
+		oneHit := sqlmock.NewRows([]string{""}).AddRow("1")
+		dbMock.ExpectQuery(subscriptionDetectionQuery).WithArgs(subscription.Spec.Name).
+			WillReturnRows(oneHit)
+		dbMock.ExpectQuery(subscriptionFailoverQuery).WithArgs(subscription.Spec.Name).
+			WillReturnRows(sqlmock.NewRows([]string{"subenabled", "subfailover"}).AddRow(false, false))
+		for _, query := range toSubscriptionAlterSQL(subscription, connString, defaultPostgresMajorVersion, true) {
+			dbMock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+
+		Expect(reconcileSubscription(ctx, fakeClient, r, subscription)).To(Succeed())
+		Expect(subscription.Status.Applied).To(HaveValue(BeTrue()))
+	})
+
+	It("doesn't skip an LSN that was already skipped", func(ctx SpecContext) {
+		subscription.Spec.SkipLSN = "0/14C0378"
+		Expect(fakeClient.Update(ctx, subscription)).To(Succeed())
+		subscription.Status.SkippedLSN = "0/14C0378"
+		Expect(fakeClient.Status().Update(ctx, subscription)).To(Succeed())
+
+		oneHit := sqlmock.NewRows([]string{""}).AddRow("1")
+		dbMock.ExpectQuery(subscriptionDetectionQuery).WithArgs(subscription.Spec.Name).
+			WillReturnRows(oneHit)
+		for _, query := range toSubscriptionAlterSQL(subscription, connString, defaultPostgresMajorVersion, false) {
+			dbMock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+
+		Expect(reconcileSubscription(ctx, fakeClient, r, subscription)).To(Succeed())
+		Expect(subscription.Status.Applied).To(HaveValue(BeTrue()))
+		Expect(subscription.Status.SkippedLSN).To(Equal("0/14C0378"))
+	})
+
+	It("doesn't refresh statistics collected less than a reconciliation interval ago", func(ctx SpecContext) {
+		subscription.Status.Applied = ptr.To(true)
+		subscription.Status.ObservedGeneration = subscription.Generation
+		subscription.Status.Statistics = &apiv1.SubscriptionStatistics{
+			UpdateTime: metav1.Now(),
+			Enabled:    true,
+		}
+		Expect(fakeClient.Status().Update(ctx, subscription)).To(Succeed())
+
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{
+			Namespace: subscription.GetNamespace(),
+			Name:      subscription.GetName(),
+		}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(result.RequeueAfter).To(BeNumerically("<=", subscriptionReconciliationInterval))
+	})
+
+	It("keeps the previous statistics when they cannot be refreshed", func(ctx SpecContext) {
+		updateTime := metav1.NewTime(time.Now().Add(-2 * subscriptionReconciliationInterval).Truncate(time.Second))
+		subscription.Status.Applied = ptr.To(true)
+		subscription.Status.ObservedGeneration = subscription.Generation
+		subscription.Status.Statistics = &apiv1.SubscriptionStatistics{
+			UpdateTime: updateTime,
+			Enabled:    true,
+		}
+		Expect(fakeClient.Status().Update(ctx, subscription)).To(Succeed())
+
+		// no query is expected by the mock, so the collection fails
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{
+			Namespace: subscription.GetNamespace(),
+			Name:      subscription.GetName(),
+		}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(subscriptionReconciliationInterval))
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(subscription), subscription)).To(Succeed())
+		Expect(subscription.Status.Statistics).ToNot(BeNil())
+		Expect(subscription.Status.Statistics.UpdateTime.Time).To(BeTemporally("==", updateTime.Time))
+	})
+
+	It("copies the sequences from the publisher when requested", func(ctx SpecContext) {
+		publisherDB, publisherMock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		r.getPublisherDB = func(publisherConnString string) (*sql.DB, error) {
+			Expect(publisherConnString).To(Equal(connString))
+			return publisherDB, nil
+		}
+
+		subscription.Spec.SyncSequences = true
+		Expect(fakeClient.Update(ctx, subscription)).To(Succeed())
+		subscription.Status.Applied = ptr.To(true)
+		subscription.Status.ObservedGeneration = subscription.Generation
+		Expect(fakeClient.Status().Update(ctx, subscription)).To(Succeed())
+
+		sequenceColumns := []string{"schemaname", "sequencename", "last_value"}
+		publisherMock.ExpectQuery(sequencesQuery).
+			WillReturnRows(sqlmock.NewRows(sequenceColumns).
+				AddRow("public", "orders_id_seq", 42).
+				AddRow("public", "items_id_seq", 7))
+		publisherMock.ExpectClose()
+		// the statistics can't be collected, as no query is expected
+		// by the mock, but the sequences are still synchronized
+		dbMock.ExpectQuery(sequencesQuery).
+			WillReturnRows(sqlmock.NewRows(sequenceColumns).
+				AddRow("public", "orders_id_seq", nil).
+				AddRow("public", "items_id_seq", 7))
+		dbMock.ExpectExec(`SELECT pg_catalog.setval('"public"."orders_id_seq"', 42)`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{
+			Namespace: subscription.GetNamespace(),
+			Name:      subscription.GetName(),
+		}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(subscriptionReconciliationInterval))
+		Expect(publisherMock.ExpectationsWereMet()).To(Succeed())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(subscription), subscription)).To(Succeed())
+		Expect(subscription.Status.SequencesSyncedAt).ToNot(BeNil())
+		Expect(subscription.Status.Statistics).To(BeNil())
+	})
+
+	It("doesn't copy the sequences synchronized less than a reconciliation interval ago", func(ctx SpecContext) {
+		r.getPublisherDB = func(string) (*sql.DB, error) {
+			Fail("the publisher should not be queried")
+			return nil, nil
+		}
+
+		subscription.Spec.SyncSequences = true
+		Expect(fakeClient.Update(ctx, subscription)).To(Succeed())
+		subscription.Status.Applied = ptr.To(true)
+		subscription.Status.ObservedGeneration = subscription.Generation
+		subscription.Status.SequencesSyncedAt = ptr.To(metav1.Now())
+		Expect(fakeClient.Status().Update(ctx, subscription)).To(Succeed())
+
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{
+			Namespace: subscription.GetNamespace(),
+			Name:      subscription.GetName(),
+		}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(result.RequeueAfter).To(BeNumerically("<=", subscriptionReconciliationInterval))
+	})
+
 	// The cluster-fetch behavior is identical across the three
 	// managed-object controllers, and so are its tests.
 	It("keeps a reconciled subscription status when the cluster cannot be fetched", func(ctx SpecContext) { //nolint:dupl
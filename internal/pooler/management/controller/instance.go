@@ -0,0 +1,215 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/util/retry"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/config"
+	pgBouncerMetrics "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/metricsserver"
+	poolerConfig "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pooler/config"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
+)
+
+// PoolerInstanceInterface the public interface for a connection pooler
+// instance, implemented by every supported backend. Implementations
+// should be thread safe
+type PoolerInstanceInterface interface {
+	// BuildConfigurationFiles generates the configuration files of the
+	// pooler given the Pooler specification and the needed secrets
+	BuildConfigurationFiles(
+		pooler *apiv1.Pooler,
+		secrets *poolerConfig.Secrets,
+	) (poolerConfig.ConfigurationFiles, error)
+
+	// Command creates the command running the pooler with the
+	// generated configuration files
+	Command() *exec.Cmd
+
+	// NewMetricsCollector creates the collector of the metrics of
+	// the pooler, which are exposed by the instance manager
+	NewMetricsCollector(ctx context.Context) prometheus.Collector
+
+	Paused() bool
+	Pause() error
+	Resume() error
+	Reload() error
+}
+
+// adminConsole is the administrative interface, shared by PgBouncer
+// and PgCat, used to pause, resume and reload a pooler instance
+type adminConsole struct {
+	// The following two fields are used to keep track of
+	// the pooler being paused or not
+	mu     *sync.RWMutex
+	paused bool
+
+	// This is the connection pool used to connect to the pooler
+	// using the administrative user and the administrative database
+	pool pool.Pooler
+
+	// The name of the administrative database
+	database string
+}
+
+// Paused returns whether the pooler instance is paused or not, thread safe
+func (p *adminConsole) Paused() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.paused
+}
+
+// Pause the instance, thread safe
+func (p *adminConsole) Pause() error {
+	// First step: connect to the administrative database
+	db, err := p.pool.Connection(p.database)
+	if err != nil {
+		return fmt.Errorf("while connecting to %s database locally: %w", p.database, err)
+	}
+
+	// Second step: pause the pooler
+	//
+	// We are retrying the PAUSE query since we need to wait for
+	// the pooler to be really up and the user could have created
+	// a pooler which is paused from the start.
+	err = retry.OnError(retry.DefaultBackoff, func(err error) bool {
+		if errors.Is(err, os.ErrNotExist) {
+			return true
+		}
+		return true
+	}, func() error {
+		_, err = db.Exec("PAUSE")
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// Third step: keep track of the pooler being paused
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused = true
+
+	return nil
+}
+
+// Resume the instance, thread safe
+func (p *adminConsole) Resume() error {
+	// First step: connect to the administrative database
+	db, err := p.pool.Connection(p.database)
+	if err != nil {
+		return fmt.Errorf("while connecting to %s database locally: %w", p.database, err)
+	}
+
+	// Second step: resume the pooler
+	_, err = db.Exec("RESUME")
+	if err != nil {
+		return fmt.Errorf("while resuming instance: %w", err)
+	}
+
+	// Third step: keep track of the pooler being resumed
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused = false
+
+	return nil
+}
+
+// Reload issues a RELOAD command to the pooler instance, returning any error
+func (p *adminConsole) Reload() error {
+	// First step: connect to the administrative database
+	db, err := p.pool.Connection(p.database)
+	if err != nil {
+		return fmt.Errorf("while connecting to %s database locally: %w", p.database, err)
+	}
+
+	// Second step: reload the configuration
+	_, err = db.Exec("RELOAD")
+	if err != nil {
+		return fmt.Errorf("while reloading configuration: %w", err)
+	}
+
+	return nil
+}
+
+// NewPoolerInstance initializes a new instance of the passed pooler backend
+func NewPoolerInstance(backend apiv1.PoolerBackend) (PoolerInstanceInterface, error) {
+	switch backend {
+	case apiv1.PoolerBackendPgBouncer:
+		return NewPgBouncerInstance(), nil
+	case apiv1.PoolerBackendPgCat:
+		return NewPgCatInstance()
+	default:
+		return nil, fmt.Errorf("unknown pooler backend: %s", backend)
+	}
+}
+
+// NewPgBouncerInstance initializes a new pgBouncerInstance
+func NewPgBouncerInstance() PoolerInstanceInterface {
+	dsn := fmt.Sprintf(
+		"host=%s port=%v user=%s sslmode=disable",
+		config.PgBouncerSocketDir,
+		config.PgBouncerPort,
+		config.PgBouncerAdminUser,
+	)
+
+	return &pgBouncerInstance{
+		adminConsole: adminConsole{
+			mu:       &sync.RWMutex{},
+			paused:   false,
+			pool:     pool.NewPgbouncerConnectionPool(dsn),
+			database: "pgbouncer",
+		},
+	}
+}
+
+// pgBouncerInstance is a PgBouncer instance
+type pgBouncerInstance struct {
+	adminConsole
+}
+
+// BuildConfigurationFiles generates the PgBouncer configuration files
+func (p *pgBouncerInstance) BuildConfigurationFiles(
+	pooler *apiv1.Pooler,
+	secrets *poolerConfig.Secrets,
+) (poolerConfig.ConfigurationFiles, error) {
+	return config.BuildConfigurationFiles(pooler, secrets)
+}
+
+// Command creates the command running PgBouncer
+func (p *pgBouncerInstance) Command() *exec.Cmd {
+	pgBouncerIni := filepath.Join(poolerConfig.ConfigsDir, config.PgBouncerIniFileName)
+	return exec.Command("/usr/bin/pgbouncer", pgBouncerIni) //nolint:gosec
+}
+
+// NewMetricsCollector creates the collector of the PgBouncer metrics
+func (p *pgBouncerInstance) NewMetricsCollector(ctx context.Context) prometheus.Collector {
+	return pgBouncerMetrics.NewExporter(ctx)
+}
@@ -25,6 +25,8 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -50,9 +52,12 @@ var _ = Describe("PgBouncerInstance", func() {
 			mock.ExpectExec("PAUSE").WillReturnResult(sqlmock.NewResult(1, 1))
 
 			pgBouncerInstance := &pgBouncerInstance{
-				mu:     &sync.RWMutex{},
-				paused: false,
-				pool:   &fakePooler{DB: db},
+				adminConsole: adminConsole{
+					mu:       &sync.RWMutex{},
+					paused:   false,
+					pool:     &fakePooler{DB: db},
+					database: "pgbouncer",
+				},
 			}
 
 			err := pgBouncerInstance.Pause()
@@ -66,9 +71,12 @@ var _ = Describe("PgBouncerInstance", func() {
 			mock.ExpectExec("RESUME").WillReturnResult(sqlmock.NewResult(1, 1))
 
 			pgBouncerInstance := &pgBouncerInstance{
-				mu:     &sync.RWMutex{},
-				paused: true,
-				pool:   &fakePooler{DB: db},
+				adminConsole: adminConsole{
+					mu:       &sync.RWMutex{},
+					paused:   true,
+					pool:     &fakePooler{DB: db},
+					database: "pgbouncer",
+				},
 			}
 
 			err := pgBouncerInstance.Resume()
@@ -82,9 +90,12 @@ var _ = Describe("PgBouncerInstance", func() {
 			mock.ExpectExec("RELOAD").WillReturnResult(sqlmock.NewResult(1, 1))
 
 			pgBouncerInstance := &pgBouncerInstance{
-				mu:     &sync.RWMutex{},
-				paused: false,
-				pool:   &fakePooler{DB: db},
+				adminConsole: adminConsole{
+					mu:       &sync.RWMutex{},
+					paused:   false,
+					pool:     &fakePooler{DB: db},
+					database: "pgbouncer",
+				},
 			}
 
 			err := pgBouncerInstance.Reload()
@@ -93,6 +104,24 @@ var _ = Describe("PgBouncerInstance", func() {
 	})
 })
 
+var _ = Describe("NewPoolerInstance", func() {
+	It("creates an instance of the requested backend", func() {
+		instance, err := NewPoolerInstance(apiv1.PoolerBackendPgBouncer)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(instance).To(BeAssignableToTypeOf(&pgBouncerInstance{}))
+		Expect(instance.Command().Args).To(Equal([]string{"/usr/bin/pgbouncer", "/controller/configs/pgbouncer.ini"}))
+
+		instance, err = NewPoolerInstance(apiv1.PoolerBackendPgCat)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(instance).To(BeAssignableToTypeOf(&pgCatInstance{}))
+	})
+
+	It("refuses unknown backends", func() {
+		_, err := NewPoolerInstance("odyssey")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
 type fakePooler struct {
 	DB *sql.DB
 }
@@ -17,7 +17,7 @@ limitations under the License.
 SPDX-License-Identifier: Apache-2.0
 */
 
-// Package controller contains the functions in pgbouncer instance manager
+// Package controller contains the functions in the pooler instance manager
 // that reacts to changes in the Pooler resource.
 package controller
 
@@ -39,33 +39,40 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/config"
+	poolerConfig "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pooler/config"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver/client/remote"
 )
 
-// PgBouncerReconciler reconciles the status of the Pooler resource with
-// the one of this pgbouncer instance
-type PgBouncerReconciler struct {
+// PoolerReconciler reconciles the status of the Pooler resource with
+// the one of this pooler instance
+type PoolerReconciler struct {
 	client               ctrl.WithWatch
 	poolerWatch          watch.Interface
-	instance             PgBouncerInstanceInterface
+	instance             PoolerInstanceInterface
+	instanceClient       remote.InstanceClient
 	poolerNamespacedName types.NamespacedName
 }
 
-// NewPgBouncerReconciler creates a new pgbouncer reconciler
-func NewPgBouncerReconciler(poolerNamespacedName types.NamespacedName) (*PgBouncerReconciler, error) {
+// NewPoolerReconciler creates a new reconciler managing the passed pooler instance
+func NewPoolerReconciler(
+	poolerNamespacedName types.NamespacedName,
+	instance PoolerInstanceInterface,
+) (*PoolerReconciler, error) {
 	client, err := management.NewControllerRuntimeClient()
 	if err != nil {
 		return nil, err
 	}
 
-	return &PgBouncerReconciler{
+	return &PoolerReconciler{
 		client:               client,
-		instance:             NewPgBouncerInstance(),
+		instance:             instance,
+		instanceClient:       remote.NewClient().Instance(),
 		poolerNamespacedName: poolerNamespacedName,
 	}, nil
 }
 
 // Run runs the reconciliation loop for this resource
-func (r *PgBouncerReconciler) Run(ctx context.Context) {
+func (r *PoolerReconciler) Run(ctx context.Context) {
 	contextLogger := log.FromContext(ctx)
 
 	for {
@@ -87,7 +94,7 @@ func (r *PgBouncerReconciler) Run(ctx context.Context) {
 }
 
 // watch contains the main reconciler loop
-func (r *PgBouncerReconciler) watch(ctx context.Context) error {
+func (r *PoolerReconciler) watch(ctx context.Context) error {
 	contextLogger := log.FromContext(ctx)
 
 	var err error
@@ -114,19 +121,19 @@ func (r *PgBouncerReconciler) watch(ctx context.Context) error {
 }
 
 // Stop stops the controller
-func (r *PgBouncerReconciler) Stop() {
+func (r *PoolerReconciler) Stop() {
 	if r.poolerWatch != nil {
 		r.poolerWatch.Stop()
 	}
 }
 
 // GetClient returns the dynamic client that is being used for a certain reconciler
-func (r *PgBouncerReconciler) GetClient() ctrl.Client {
+func (r *PoolerReconciler) GetClient() ctrl.Client {
 	return r.client
 }
 
-// Reconcile is the main reconciliation loop for the pgbouncer instance
-func (r *PgBouncerReconciler) Reconcile(ctx context.Context, event *watch.Event) error {
+// Reconcile is the main reconciliation loop for the pooler instance
+func (r *PoolerReconciler) Reconcile(ctx context.Context, event *watch.Event) error {
 	contextLogger := log.FromContext(ctx)
 	contextLogger.Debug(
 		"Reconciliation loop",
@@ -147,10 +154,11 @@ func (r *PgBouncerReconciler) Reconcile(ctx context.Context, event *watch.Event)
 }
 
 // synchronizePause ensure that the pause flag inside the Pooler
-// specification matches the PgBouncer status
-func (r *PgBouncerReconciler) synchronizePause(pooler *apiv1.Pooler) error {
+// specification, or the pause requested by the operator while draining
+// the connections before a switchover, matches the pooler status
+func (r *PoolerReconciler) synchronizePause(pooler *apiv1.Pooler) error {
 	isPaused := r.instance.Paused()
-	shouldBePaused := pooler.Spec.PgBouncer.IsPaused()
+	shouldBePaused := pooler.ShouldBePaused()
 	if shouldBePaused && !isPaused {
 		if err := r.instance.Pause(); err != nil {
 			return fmt.Errorf("while pausing instance: %w", err)
@@ -165,15 +173,15 @@ func (r *PgBouncerReconciler) synchronizePause(pooler *apiv1.Pooler) error {
 }
 
 // synchronizeConfig ensure that the configuration derived from
-// the pooler specification matches the one loaded in PgBouncer
-func (r *PgBouncerReconciler) synchronizeConfig(ctx context.Context, pooler *apiv1.Pooler) error {
+// the pooler specification matches the one loaded in the pooler
+func (r *PoolerReconciler) synchronizeConfig(ctx context.Context, pooler *apiv1.Pooler) error {
 	var (
 		configurationChanged bool
 		err                  error
 	)
 
-	if configurationChanged, err = r.writePgBouncerConfig(ctx, pooler); err != nil {
-		return fmt.Errorf("while writing PgBouncer configuration: %w", err)
+	if configurationChanged, err = r.writeConfig(ctx, pooler); err != nil {
+		return fmt.Errorf("while writing pooler configuration: %w", err)
 	}
 
 	if !configurationChanged {
@@ -187,13 +195,13 @@ func (r *PgBouncerReconciler) synchronizeConfig(ctx context.Context, pooler *api
 	return nil
 }
 
-// writePgBouncerConfig writes the PgBouncer configuration files given the Pooler
+// writeConfig writes the pooler configuration files given the Pooler
 // specification, returning a boolean flag indicating if the configuration has
 // changed or not
-func (r *PgBouncerReconciler) writePgBouncerConfig(ctx context.Context, pooler *apiv1.Pooler) (bool, error) {
+func (r *PoolerReconciler) writeConfig(ctx context.Context, pooler *apiv1.Pooler) (bool, error) {
 	var (
-		secrets     *config.Secrets
-		configFiles config.ConfigurationFiles
+		secrets     *poolerConfig.Secrets
+		configFiles poolerConfig.ConfigurationFiles
 
 		err error
 	)
@@ -210,19 +218,19 @@ func (r *PgBouncerReconciler) writePgBouncerConfig(ctx context.Context, pooler *
 		return false, fmt.Errorf("while reading secrets: %w", err)
 	}
 
-	if configFiles, err = config.BuildConfigurationFiles(pooler, secrets); err != nil {
-		return false, fmt.Errorf("while generating pgbouncer configuration: %w", err)
+	if configFiles, err = r.instance.BuildConfigurationFiles(pooler, secrets); err != nil {
+		return false, fmt.Errorf("while generating pooler configuration: %w", err)
 	}
 
 	return refreshConfigurationFiles(ctx, configFiles)
 }
 
-// Init ensures that all PgBouncer requirement are met.
+// Init ensures that all the pooler requirement are met.
 //
 // In detail:
-// 1. create the pgbouncer configuration and the required secrets
+// 1. create the pooler configuration and the required secrets
 // 2. ensure that every needed folder is existent
-func (r *PgBouncerReconciler) Init(ctx context.Context) error {
+func (r *PoolerReconciler) Init(ctx context.Context) error {
 	contextLogger := log.FromContext(ctx)
 
 	var pooler apiv1.Pooler
@@ -232,8 +240,8 @@ func (r *PgBouncerReconciler) Init(ctx context.Context) error {
 		return fmt.Errorf("while getting pooler for the first time: %w", err)
 	}
 
-	// Write the startup configuration for PgBouncer
-	if _, err := r.writePgBouncerConfig(ctx, &pooler); err != nil {
+	// Write the startup configuration for the pooler
+	if _, err := r.writeConfig(ctx, &pooler); err != nil {
 		return err
 	}
 
@@ -0,0 +1,92 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sethvargo/go-password/password"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	pgCatConfig "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgcat/config"
+	pgCatMetrics "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgcat/metricsserver"
+	poolerConfig "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pooler/config"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
+)
+
+// NewPgCatInstance initializes a new pgCatInstance. As PgCat doesn't
+// listen on a Unix socket, the administrative console is protected by
+// a password which is generated every time the instance manager starts.
+func NewPgCatInstance() (PoolerInstanceInterface, error) {
+	adminPassword, err := password.Generate(64, 10, 0, false, true)
+	if err != nil {
+		return nil, fmt.Errorf("while generating the PgCat admin password: %w", err)
+	}
+
+	dsn := fmt.Sprintf(
+		"host=127.0.0.1 port=%v user=%s password=%s sslmode=disable",
+		poolerConfig.Port,
+		pgCatConfig.PgCatAdminUser,
+		adminPassword,
+	)
+
+	return &pgCatInstance{
+		adminConsole: adminConsole{
+			mu:       &sync.RWMutex{},
+			paused:   false,
+			pool:     pool.NewPgbouncerConnectionPool(dsn),
+			database: pgCatConfig.PgCatAdminDatabase,
+		},
+		adminPassword: adminPassword,
+	}, nil
+}
+
+// pgCatInstance is a PgCat instance
+type pgCatInstance struct {
+	adminConsole
+
+	// The password of the administrative console
+	adminPassword string
+}
+
+// BuildConfigurationFiles generates the PgCat configuration files
+func (p *pgCatInstance) BuildConfigurationFiles(
+	pooler *apiv1.Pooler,
+	secrets *poolerConfig.Secrets,
+) (poolerConfig.ConfigurationFiles, error) {
+	return pgCatConfig.BuildConfigurationFiles(pooler, secrets, p.adminPassword)
+}
+
+// Command creates the command running PgCat
+func (p *pgCatInstance) Command() *exec.Cmd {
+	pgCatConfigFile := filepath.Join(poolerConfig.ConfigsDir, pgCatConfig.PgCatConfigFileName)
+	return exec.Command("/usr/bin/pgcat", pgCatConfigFile) //nolint:gosec
+}
+
+// NewMetricsCollector creates the collector of the PgCat metrics, which
+// are read from the administrative console
+func (p *pgCatInstance) NewMetricsCollector(ctx context.Context) prometheus.Collector {
+	return pgCatMetrics.NewExporter(ctx, p.pool)
+}
@@ -0,0 +1,126 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"path/filepath"
+	"sync"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	corev1 "k8s.io/api/core/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/metricstest"
+	pgCatConfig "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgcat/config"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pooler/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PgCatInstance", func() {
+	It("generates a different admin password for every instance", func() {
+		first, err := NewPgCatInstance()
+		Expect(err).ToNot(HaveOccurred())
+		second, err := NewPgCatInstance()
+		Expect(err).ToNot(HaveOccurred())
+
+		firstInstance := first.(*pgCatInstance)
+		Expect(firstInstance.adminPassword).To(HaveLen(64))
+		Expect(firstInstance.adminPassword).ToNot(Equal(second.(*pgCatInstance).adminPassword))
+		Expect(firstInstance.database).To(Equal(pgCatConfig.PgCatAdminDatabase))
+	})
+
+	It("writes the admin password in the configuration", func() {
+		instance := &pgCatInstance{adminPassword: "admin-secret"}
+		pooler := &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				Cluster: apiv1.LocalObjectReference{Name: "cluster-example"},
+				Type:    apiv1.PoolerTypeRW,
+				PgCat: &apiv1.PgCatSpec{
+					AuthQuerySecret: apiv1.LocalObjectReference{Name: "auth"},
+					Databases:       []apiv1.PgCatDatabase{{Name: "app", Users: []string{"app"}}},
+				},
+			},
+		}
+
+		files, err := instance.BuildConfigurationFiles(pooler, &config.Secrets{
+			AuthQuery: &corev1.Secret{
+				Type: corev1.SecretTypeBasicAuth,
+				Data: map[string][]byte{
+					corev1.BasicAuthUsernameKey: []byte("auth_user"),
+					corev1.BasicAuthPasswordKey: []byte("auth_password"),
+				},
+			},
+			ClientTLS: &corev1.Secret{},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(files[filepath.Join(config.ConfigsDir, pgCatConfig.PgCatConfigFileName)])).
+			To(ContainSubstring("admin_password = \"admin-secret\"\n"))
+	})
+
+	It("pauses and resumes the instance via the admin console", func() {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+		mock.ExpectExec("PAUSE").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("RESUME").WillReturnResult(sqlmock.NewResult(1, 1))
+
+		instance := &pgCatInstance{
+			adminConsole: adminConsole{
+				mu:       &sync.RWMutex{},
+				pool:     &fakePooler{DB: db},
+				database: pgCatConfig.PgCatAdminDatabase,
+			},
+		}
+
+		Expect(instance.Pause()).To(Succeed())
+		Expect(instance.Paused()).To(BeTrue())
+		Expect(instance.Resume()).To(Succeed())
+		Expect(instance.Paused()).To(BeFalse())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("runs PgCat with the generated configuration", func() {
+		instance := &pgCatInstance{}
+		Expect(instance.Command().Args).To(Equal([]string{
+			"/usr/bin/pgcat",
+			filepath.Join(config.ConfigsDir, pgCatConfig.PgCatConfigFileName),
+		}))
+	})
+
+	It("collects the metrics via the admin console", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+		mock.ExpectQuery("SHOW POOLS").WillReturnRows(
+			sqlmock.NewRows([]string{"database", "user", "cl_waiting"}).AddRow("app", "app", 2))
+
+		instance := &pgCatInstance{
+			adminConsole: adminConsole{
+				mu:       &sync.RWMutex{},
+				pool:     &fakePooler{DB: db},
+				database: pgCatConfig.PgCatAdminDatabase,
+			},
+		}
+
+		collector := instance.NewMetricsCollector(ctx)
+		Expect(metricstest.Count(collector, "cnpg_pgcat_pools_cl_waiting")).To(Equal(1))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+})
@@ -0,0 +1,217 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// RunReplicaLoadBalancing periodically checks the replicas of the cluster,
+// updating the set of replicas PgBouncer is balancing the connections
+// among, until the context is cancelled
+func (r *PoolerReconciler) RunReplicaLoadBalancing(ctx context.Context) {
+	contextLogger := log.FromContext(ctx)
+
+	for {
+		var pooler apiv1.Pooler
+		interval := (*apiv1.PoolerReplicaLoadBalancing)(nil).GetCheckInterval()
+		if err := r.GetClient().Get(ctx, r.poolerNamespacedName, &pooler); err != nil {
+			contextLogger.Error(err, "while getting pooler to check the replicas")
+		} else if pooler.IsReplicaLoadBalancingEnabled() {
+			interval = pooler.Spec.ReplicaLoadBalancing.GetCheckInterval()
+			if err := r.updateReplicaLoadBalancing(ctx, &pooler); err != nil {
+				contextLogger.Error(err, "while checking the replicas")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// updateReplicaLoadBalancing checks the replicas of the cluster and stores
+// the ones that should receive the connections in the Pooler status. Every
+// PgBouncer instance generates its configuration from the Pooler status,
+// and is reloaded when the membership changes.
+func (r *PoolerReconciler) updateReplicaLoadBalancing(ctx context.Context, pooler *apiv1.Pooler) error {
+	contextLogger := log.FromContext(ctx)
+
+	var pods corev1.PodList
+	if err := r.GetClient().List(
+		ctx,
+		&pods,
+		ctrl.InNamespace(pooler.Namespace),
+		ctrl.MatchingLabels{
+			utils.ClusterLabelName:             pooler.Spec.Cluster.Name,
+			utils.ClusterInstanceRoleLabelName: specs.ClusterRoleLabelReplica,
+		},
+	); err != nil {
+		return fmt.Errorf("while listing the replicas: %w", err)
+	}
+
+	var readyPods corev1.PodList
+	for _, pod := range utils.FilterActivePods(pods.Items) {
+		if utils.IsPodReady(pod) && pod.Status.PodIP != "" {
+			readyPods.Items = append(readyPods.Items, pod)
+		}
+	}
+
+	var statuses postgres.PostgresqlStatusList
+	if len(readyPods.Items) > 0 {
+		statusCtx, err := r.newInstanceStatusContext(ctx, pooler)
+		if err != nil {
+			return err
+		}
+		statuses = r.instanceClient.GetStatusFromInstances(statusCtx, readyPods)
+	}
+
+	status := buildReplicaLoadBalancingStatus(
+		utils.FilterActivePods(pods.Items),
+		statuses,
+		pooler.Spec.ReplicaLoadBalancing.MaxLag.Duration,
+	)
+	if isSameReplicaLoadBalancingMembership(pooler.Status.ReplicaLoadBalancing, status) {
+		return nil
+	}
+
+	contextLogger.Info("Updating the replicas receiving the connections",
+		"members", status.Members,
+		"excluded", status.Excluded)
+
+	origPooler := pooler.DeepCopy()
+	status.LastTransitionTime = ptr.To(metav1.Now())
+	pooler.Status.ReplicaLoadBalancing = status
+	return r.GetClient().Status().Patch(
+		ctx, pooler, ctrl.MergeFromWithOptions(origPooler, ctrl.MergeFromWithOptimisticLock{}))
+}
+
+// newInstanceStatusContext returns a context holding the TLS configuration
+// needed to query the status of the instances, verifying their
+// certificates with the server CA used by PgBouncer
+func (r *PoolerReconciler) newInstanceStatusContext(
+	ctx context.Context,
+	pooler *apiv1.Pooler,
+) (context.Context, error) {
+	if pooler.Status.Secrets == nil || pooler.Status.Secrets.ServerCA.Name == "" {
+		return nil, fmt.Errorf("status not populated yet")
+	}
+
+	statusCtx, err := certs.NewTLSConfigForContext(ctx, certs.TLSConfigOptions{
+		Client: r.GetClient(),
+		CASecret: types.NamespacedName{
+			Namespace: pooler.Namespace,
+			Name:      pooler.Status.Secrets.ServerCA.Name,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("while building the TLS configuration to query the replicas: %w", err)
+	}
+
+	return statusCtx, nil
+}
+
+// buildReplicaLoadBalancingStatus computes the set of replicas that should
+// receive the connections, excluding the ones that are not ready, whose
+// status is unknown or whose replay lag exceeds the passed maximum
+func buildReplicaLoadBalancingStatus(
+	pods []corev1.Pod,
+	statuses postgres.PostgresqlStatusList,
+	maxLag time.Duration,
+) *apiv1.PoolerReplicaLoadBalancingStatus {
+	result := &apiv1.PoolerReplicaLoadBalancingStatus{}
+
+	for idx := range pods {
+		pod := &pods[idx]
+
+		statusIdx := slices.IndexFunc(statuses.Items, func(item postgres.PostgresqlStatus) bool {
+			return item.Pod != nil && item.Pod.Name == pod.Name
+		})
+
+		switch {
+		case !utils.IsPodReady(*pod) || pod.Status.PodIP == "" || statusIdx < 0:
+			result.Excluded = append(result.Excluded, apiv1.PoolerExcludedReplica{
+				Name:   pod.Name,
+				Reason: apiv1.PoolerExcludedReplicaReasonNotReady,
+			})
+
+		case statuses.Items[statusIdx].Error != nil:
+			result.Excluded = append(result.Excluded, apiv1.PoolerExcludedReplica{
+				Name:   pod.Name,
+				Reason: apiv1.PoolerExcludedReplicaReasonUnknownStatus,
+			})
+
+		case statuses.Items[statusIdx].IsPrimary:
+			// The instance has just been promoted and its
+			// role label is not updated yet
+			continue
+
+		case statuses.Items[statusIdx].ReplayLagSeconds > maxLag.Seconds():
+			result.Excluded = append(result.Excluded, apiv1.PoolerExcludedReplica{
+				Name:   pod.Name,
+				Reason: apiv1.PoolerExcludedReplicaReasonLag,
+			})
+
+		default:
+			result.Members = append(result.Members, apiv1.PoolerReplicaMember{
+				Name: pod.Name,
+				IP:   pod.Status.PodIP,
+			})
+		}
+	}
+
+	slices.SortFunc(result.Members, func(a, b apiv1.PoolerReplicaMember) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	slices.SortFunc(result.Excluded, func(a, b apiv1.PoolerExcludedReplica) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return result
+}
+
+// isSameReplicaLoadBalancingMembership checks if two statuses
+// have the same members and excluded replicas
+func isSameReplicaLoadBalancingMembership(a, b *apiv1.PoolerReplicaLoadBalancingStatus) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return slices.Equal(a.Members, b.Members) && slices.Equal(a.Excluded, b.Excluded)
+}
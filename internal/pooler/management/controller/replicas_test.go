@@ -0,0 +1,183 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver/client/remote"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type fakeInstanceClient struct {
+	remote.InstanceClient
+	statuses map[string]postgres.PostgresqlStatus
+}
+
+func (f *fakeInstanceClient) GetStatusFromInstances(
+	_ context.Context,
+	pods corev1.PodList,
+) postgres.PostgresqlStatusList {
+	var result postgres.PostgresqlStatusList
+	for _, pod := range pods.Items {
+		status := f.statuses[pod.Name]
+		status.AddPod(pod)
+		result.Items = append(result.Items, status)
+	}
+	return result
+}
+
+func newReplicaPod(name, ip string, ready bool) corev1.Pod {
+	readyStatus := corev1.ConditionFalse
+	if ready {
+		readyStatus = corev1.ConditionTrue
+	}
+
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels: map[string]string{
+				utils.ClusterLabelName:             "cluster",
+				utils.ClusterInstanceRoleLabelName: specs.ClusterRoleLabelReplica,
+			},
+		},
+		Status: corev1.PodStatus{
+			PodIP: ip,
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: readyStatus},
+			},
+		},
+	}
+}
+
+var _ = Describe("buildReplicaLoadBalancingStatus", func() {
+	It("excludes the replicas that can't serve the connections", func() {
+		pods := []corev1.Pod{
+			newReplicaPod("cluster-4", "10.0.0.4", true),
+			newReplicaPod("cluster-2", "10.0.0.2", true),
+			newReplicaPod("cluster-3", "10.0.0.3", true),
+			newReplicaPod("cluster-5", "10.0.0.5", false),
+			newReplicaPod("cluster-6", "10.0.0.6", true),
+			newReplicaPod("cluster-7", "10.0.0.7", true),
+		}
+		statuses := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{Pod: &pods[0], ReplayLagSeconds: 1},
+				{Pod: &pods[1]},
+				{Pod: &pods[2], ReplayLagSeconds: 120},
+				{Pod: &pods[4], Error: errors.New("connection refused")},
+				{Pod: &pods[5], IsPrimary: true},
+			},
+		}
+
+		status := buildReplicaLoadBalancingStatus(pods, statuses, 30*time.Second)
+		Expect(status.Members).To(Equal([]apiv1.PoolerReplicaMember{
+			{Name: "cluster-2", IP: "10.0.0.2"},
+			{Name: "cluster-4", IP: "10.0.0.4"},
+		}))
+		Expect(status.Excluded).To(Equal([]apiv1.PoolerExcludedReplica{
+			{Name: "cluster-3", Reason: apiv1.PoolerExcludedReplicaReasonLag},
+			{Name: "cluster-5", Reason: apiv1.PoolerExcludedReplicaReasonNotReady},
+			{Name: "cluster-6", Reason: apiv1.PoolerExcludedReplicaReasonUnknownStatus},
+		}))
+	})
+})
+
+var _ = Describe("updateReplicaLoadBalancing", func() {
+	var (
+		cli            client.WithWatch
+		pooler         *apiv1.Pooler
+		instanceClient *fakeInstanceClient
+		reconciler     *PoolerReconciler
+	)
+
+	BeforeEach(func(ctx context.Context) {
+		cli, pooler = buildTestEnv()
+
+		var serverCA corev1.Secret
+		Expect(cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: serverCAName}, &serverCA)).To(Succeed())
+		serverCA.Data = map[string][]byte{certs.CACertKey: []byte("ca")}
+		Expect(cli.Update(ctx, &serverCA)).To(Succeed())
+
+		for _, pod := range []corev1.Pod{
+			newReplicaPod("cluster-2", "10.0.0.2", true),
+			newReplicaPod("cluster-3", "10.0.0.3", true),
+		} {
+			Expect(cli.Create(ctx, &pod)).To(Succeed())
+		}
+
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(pooler), pooler)).To(Succeed())
+		pooler.Spec.Type = apiv1.PoolerTypeRO
+		pooler.Spec.ReplicaLoadBalancing = &apiv1.PoolerReplicaLoadBalancing{
+			MaxLag: metav1.Duration{Duration: 30 * time.Second},
+		}
+		Expect(cli.Update(ctx, pooler)).To(Succeed())
+
+		instanceClient = &fakeInstanceClient{statuses: map[string]postgres.PostgresqlStatus{}}
+		reconciler = &PoolerReconciler{
+			client:               cli,
+			instanceClient:       instanceClient,
+			poolerNamespacedName: client.ObjectKeyFromObject(pooler),
+		}
+	})
+
+	It("drops the lagging replicas and adds them back when they catch up", func(ctx context.Context) {
+		instanceClient.statuses["cluster-3"] = postgres.PostgresqlStatus{ReplayLagSeconds: 60}
+		Expect(reconciler.updateReplicaLoadBalancing(ctx, pooler)).To(Succeed())
+
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(pooler), pooler)).To(Succeed())
+		Expect(pooler.GetReplicaLoadBalancingHosts()).To(Equal([]string{"10.0.0.2"}))
+		Expect(pooler.Status.ReplicaLoadBalancing.Excluded).To(Equal([]apiv1.PoolerExcludedReplica{
+			{Name: "cluster-3", Reason: apiv1.PoolerExcludedReplicaReasonLag},
+		}))
+		Expect(pooler.Status.ReplicaLoadBalancing.LastTransitionTime).ToNot(BeNil())
+
+		instanceClient.statuses["cluster-3"] = postgres.PostgresqlStatus{ReplayLagSeconds: 2}
+		Expect(reconciler.updateReplicaLoadBalancing(ctx, pooler)).To(Succeed())
+
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(pooler), pooler)).To(Succeed())
+		Expect(pooler.GetReplicaLoadBalancingHosts()).To(Equal([]string{"10.0.0.2", "10.0.0.3"}))
+		Expect(pooler.Status.ReplicaLoadBalancing.Excluded).To(BeEmpty())
+	})
+
+	It("doesn't update the status when the membership is unchanged", func(ctx context.Context) {
+		Expect(reconciler.updateReplicaLoadBalancing(ctx, pooler)).To(Succeed())
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(pooler), pooler)).To(Succeed())
+		resourceVersion := pooler.ResourceVersion
+
+		Expect(reconciler.updateReplicaLoadBalancing(ctx, pooler)).To(Succeed())
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(pooler), pooler)).To(Succeed())
+		Expect(pooler.ResourceVersion).To(Equal(resourceVersion))
+	})
+})
@@ -28,7 +28,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
-	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/config"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pooler/config"
 )
 
 // getSecrets loads the data needed to generate the configuration
@@ -91,5 +91,49 @@ func getSecrets(ctx context.Context, client ctrl.Client, pooler *apiv1.Pooler) (
 	}
 	result.ClientCA = &clientCASecret
 
+	if len(pooler.Status.Secrets.Clusters) > 0 {
+		result.Clusters = make(map[string]*config.ClusterSecrets, len(pooler.Status.Secrets.Clusters))
+	}
+	for _, clusterSecretsVersions := range pooler.Status.Secrets.Clusters {
+		clusterSecrets, err := getClusterSecrets(ctx, client, pooler.Namespace, clusterSecretsVersions)
+		if err != nil {
+			return nil, fmt.Errorf("while getting secrets for cluster %s: %w", clusterSecretsVersions.Cluster, err)
+		}
+		result.Clusters[clusterSecretsVersions.Cluster] = clusterSecrets
+	}
+
+	return result, nil
+}
+
+// getClusterSecrets loads the secrets needed to connect to a cluster
+// serving some of the databases exposed by PgBouncer
+func getClusterSecrets(
+	ctx context.Context,
+	client ctrl.Client,
+	namespace string,
+	versions apiv1.PoolerClusterSecrets,
+) (*config.ClusterSecrets, error) {
+	result := &config.ClusterSecrets{}
+
+	if versions.ServerCA.Name != "" {
+		var serverCASecret corev1.Secret
+		if err := client.Get(ctx,
+			types.NamespacedName{Name: versions.ServerCA.Name, Namespace: namespace},
+			&serverCASecret); err != nil {
+			return nil, fmt.Errorf("while getting server CA secret: %w", err)
+		}
+		result.ServerCA = &serverCASecret
+	}
+
+	if versions.AuthQuery.Name != "" {
+		var authQuerySecret corev1.Secret
+		if err := client.Get(ctx,
+			types.NamespacedName{Name: versions.AuthQuery.Name, Namespace: namespace},
+			&authQuerySecret); err != nil {
+			return nil, fmt.Errorf("while getting auth query secret: %w", err)
+		}
+		result.AuthQuery = &authQuerySecret
+	}
+
 	return result, nil
 }
@@ -63,6 +63,35 @@ var _ = Describe("getSecrets tests", func() {
 		})
 	})
 
+	Context("when the pooler serves databases from other clusters", func() {
+		BeforeEach(func() {
+			pooler.Status.Secrets.Clusters = []apiv1.PoolerClusterSecrets{
+				{
+					Cluster:   "other-cluster",
+					ServerCA:  apiv1.SecretVersion{Name: serverCAName},
+					AuthQuery: apiv1.SecretVersion{Name: authQueryName},
+				},
+			}
+		})
+
+		It("should return the secrets of every cluster", func(ctx context.Context) {
+			res, err := getSecrets(ctx, client, pooler)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Clusters).To(HaveKey("other-cluster"))
+			Expect(res.Clusters["other-cluster"].ServerCA.Name).To(Equal(serverCAName))
+			Expect(res.Clusters["other-cluster"].AuthQuery.Name).To(Equal(authQueryName))
+		})
+
+		It("should return error if a secret of a cluster is not found", func(ctx context.Context) {
+			pooler.Status.Secrets.Clusters[0].AuthQuery = apiv1.SecretVersion{Name: "nonexistent"}
+
+			_, err := getSecrets(ctx, client, pooler)
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	Context("when a secret is not found", func() {
 		BeforeEach(func() {
 			pooler.Status.Secrets.ServerCA = apiv1.SecretVersion{Name: "nonexistent"}
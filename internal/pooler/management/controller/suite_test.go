@@ -83,5 +83,6 @@ func buildTestEnv() (client.WithWatch, *apiv1.Pooler) {
 
 	return fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).
 		WithObjects(pooler, authQuerySecret, serverCASecret, serverCertSecret, clientCASecret).
+		WithStatusSubresource(&apiv1.Pooler{}).
 		Build(), pooler
 }
@@ -21,9 +21,11 @@ package v1
 
 import (
 	"context"
+	"slices"
 	"strconv"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/cloudnative-pg/machinery/pkg/stringset"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -134,7 +136,13 @@ func (v *BackupCustomValidator) validate(r *apiv1.Backup) field.ErrorList {
 		))
 	}
 
-	if r.Spec.Method == apiv1.BackupMethodBarmanObjectStore && r.Spec.Online != nil {
+	isOnlineSupported := !slices.Contains([]apiv1.BackupMethod{
+		apiv1.BackupMethodBarmanObjectStore,
+		apiv1.BackupMethodPgBaseBackup,
+		apiv1.BackupMethodLogical,
+	}, r.Spec.Method)
+
+	if !isOnlineSupported && r.Spec.Online != nil {
 		result = append(result, field.Invalid(
 			field.NewPath("spec", "online"),
 			r.Spec.Online,
@@ -142,7 +150,7 @@ func (v *BackupCustomValidator) validate(r *apiv1.Backup) field.ErrorList {
 		))
 	}
 
-	if r.Spec.Method == apiv1.BackupMethodBarmanObjectStore && r.Spec.OnlineConfiguration != nil {
+	if !isOnlineSupported && r.Spec.OnlineConfiguration != nil {
 		result = append(result, field.Invalid(
 			field.NewPath("spec", "onlineConfiguration"),
 			r.Spec.OnlineConfiguration,
@@ -150,6 +158,36 @@ func (v *BackupCustomValidator) validate(r *apiv1.Backup) field.ErrorList {
 		))
 	}
 
+	if r.Spec.Method != apiv1.BackupMethodLogical && len(r.Spec.Databases) > 0 {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "databases"),
+			r.Spec.Databases,
+			"Databases parameter can be specified only if the backup method is logical",
+		))
+	}
+
+	if r.Spec.Method == apiv1.BackupMethodLogical && r.Spec.PluginConfiguration != nil {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "pluginConfiguration"),
+			r.Spec.PluginConfiguration,
+			"the logical backup method only supports the volume configured in the cluster as destination",
+		))
+	}
+
+	if r.Spec.Method != apiv1.BackupMethodPgBaseBackup && r.Spec.Type != "" {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "type"),
+			r.Spec.Type,
+			"Type parameter can be specified only if the backup method is pgBaseBackup",
+		))
+	}
+
+	result = append(result, validateBackupVerification(
+		field.NewPath("spec", "verification"),
+		r.Spec.Method,
+		r.Spec.Verification,
+	)...)
+
 	if r.Spec.Method == apiv1.BackupMethodPlugin && r.Spec.PluginConfiguration.IsEmpty() {
 		result = append(result, field.Invalid(
 			field.NewPath("spec", "pluginConfiguration"),
@@ -171,3 +209,44 @@ func (v *BackupCustomValidator) validate(r *apiv1.Backup) field.ErrorList {
 
 	return result
 }
+
+// validateBackupVerification checks that the backups taken with the passed
+// method can be verified, and that every assertion has a unique name not
+// clashing with the built-in checks
+func validateBackupVerification(
+	path *field.Path,
+	method apiv1.BackupMethod,
+	verification *apiv1.BackupVerificationConfiguration,
+) field.ErrorList {
+	if verification == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	if !method.SupportsVerification() {
+		result = append(result, field.Invalid(
+			path,
+			verification,
+			"Verification can be specified only if the method is "+
+				"barmanObjectStore, volumeSnapshot or pgBaseBackup",
+		))
+	}
+
+	names := stringset.New()
+	for idx, assertion := range verification.Assertions {
+		namePath := path.Child("assertions").Index(idx).Child("name")
+		switch {
+		case assertion.Name == apiv1.BackupVerificationAmcheckName:
+			result = append(result, field.Invalid(
+				namePath,
+				assertion.Name,
+				"this name is reserved for the built-in pg_amcheck check",
+			))
+		case names.Has(assertion.Name):
+			result = append(result, field.Duplicate(namePath, assertion.Name))
+		}
+		names.Put(assertion.Name)
+	}
+
+	return result
+}
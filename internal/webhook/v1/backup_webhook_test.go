@@ -21,6 +21,7 @@ package v1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/ptr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -83,6 +84,88 @@ var _ = Describe("Backup webhook validate", func() {
 		Expect(result[0].Field).To(Equal("spec.onlineConfiguration"))
 	})
 
+	It("complains if online is set on a pgBaseBackup backup", func() {
+		backup := &apiv1.Backup{
+			Spec: apiv1.BackupSpec{
+				Method: apiv1.BackupMethodPgBaseBackup,
+				Online: ptr.To(true),
+			},
+		}
+		result := v.validate(backup)
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.online"))
+	})
+
+	It("complains if online is set on a logical backup", func() {
+		backup := &apiv1.Backup{
+			Spec: apiv1.BackupSpec{
+				Method: apiv1.BackupMethodLogical,
+				Online: ptr.To(true),
+			},
+		}
+		result := v.validate(backup)
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.online"))
+	})
+
+	It("doesn't complain if databases are set on a logical backup", func() {
+		backup := &apiv1.Backup{
+			Spec: apiv1.BackupSpec{
+				Method:    apiv1.BackupMethodLogical,
+				Databases: []string{"app"},
+			},
+		}
+		result := v.validate(backup)
+		Expect(result).To(BeEmpty())
+	})
+
+	It("complains if databases are set on a barman backup", func() {
+		backup := &apiv1.Backup{
+			Spec: apiv1.BackupSpec{
+				Method:    apiv1.BackupMethodBarmanObjectStore,
+				Databases: []string{"app"},
+			},
+		}
+		result := v.validate(backup)
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.databases"))
+	})
+
+	It("complains if a plugin destination is set on a logical backup", func() {
+		backup := &apiv1.Backup{
+			Spec: apiv1.BackupSpec{
+				Method:              apiv1.BackupMethodLogical,
+				PluginConfiguration: &apiv1.BackupPluginConfiguration{Name: "plugin-example"},
+			},
+		}
+		result := v.validate(backup)
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.pluginConfiguration"))
+	})
+
+	It("doesn't complain if type is set on a pgBaseBackup backup", func() {
+		backup := &apiv1.Backup{
+			Spec: apiv1.BackupSpec{
+				Method: apiv1.BackupMethodPgBaseBackup,
+				Type:   apiv1.BackupTypeIncremental,
+			},
+		}
+		result := v.validate(backup)
+		Expect(result).To(BeEmpty())
+	})
+
+	It("complains if type is set on a barman backup", func() {
+		backup := &apiv1.Backup{
+			Spec: apiv1.BackupSpec{
+				Method: apiv1.BackupMethodBarmanObjectStore,
+				Type:   apiv1.BackupTypeIncremental,
+			},
+		}
+		result := v.validate(backup)
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.type"))
+	})
+
 	It("returns error if BackupVolumeSnapshotDeadlineAnnotationName is not an integer", func() {
 		backup := &apiv1.Backup{
 			ObjectMeta: metav1.ObjectMeta{
@@ -119,3 +202,60 @@ var _ = Describe("Backup webhook validate", func() {
 		Expect(result).To(BeEmpty())
 	})
 })
+
+var _ = Describe("Backup verification validation", func() {
+	var v *BackupCustomValidator
+	BeforeEach(func() {
+		v = &BackupCustomValidator{}
+	})
+
+	It("accepts verification on barmanObjectStore backups", func() {
+		backup := &apiv1.Backup{
+			Spec: apiv1.BackupSpec{
+				Method: apiv1.BackupMethodBarmanObjectStore,
+				Verification: &apiv1.BackupVerificationConfiguration{
+					Assertions: []apiv1.BackupVerificationAssertion{
+						{Name: "orders", Database: "app", Query: "SELECT count(*) > 0 FROM orders"},
+					},
+				},
+			},
+		}
+		Expect(v.validate(backup)).To(BeEmpty())
+	})
+
+	It("complains if verification is set on logical backups", func() {
+		backup := &apiv1.Backup{
+			Spec: apiv1.BackupSpec{
+				Method:       apiv1.BackupMethodLogical,
+				Verification: &apiv1.BackupVerificationConfiguration{},
+			},
+		}
+		result := v.validate(backup)
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.verification"))
+	})
+
+	It("complains about duplicate and reserved assertion names", func() {
+		backup := &apiv1.Backup{
+			Spec: apiv1.BackupSpec{
+				Method: apiv1.BackupMethodVolumeSnapshot,
+				Verification: &apiv1.BackupVerificationConfiguration{
+					Assertions: []apiv1.BackupVerificationAssertion{
+						{Name: "orders", Query: "SELECT true"},
+						{Name: "orders", Query: "SELECT true"},
+						{Name: apiv1.BackupVerificationAmcheckName, Query: "SELECT true"},
+					},
+				},
+			},
+		}
+		result := validateBackupVerification(
+			field.NewPath("spec", "verification"),
+			backup.Spec.Method,
+			backup.Spec.Verification,
+		)
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.verification.assertions[1].name"))
+		Expect(result[0].Type).To(Equal(field.ErrorTypeDuplicate))
+		Expect(result[1].Field).To(Equal("spec.verification.assertions[2].name"))
+	})
+})
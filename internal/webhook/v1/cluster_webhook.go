@@ -28,6 +28,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	barmanWebhooks "github.com/cloudnative-pg/barman-cloud/pkg/api/webhooks"
 	"github.com/cloudnative-pg/machinery/pkg/image/reference"
@@ -37,6 +38,7 @@ import (
 	"github.com/cloudnative-pg/machinery/pkg/types"
 	jsonpatch "github.com/evanphx/json-patch/v5"
 	volumesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"github.com/robfig/cron"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -48,12 +50,14 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/webhook/guard"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres/hba"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/backup/recoveryplan"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
@@ -69,7 +73,7 @@ var clusterLog = log.WithName("cluster-resource").WithValues("version", "v1")
 // SetupClusterWebhookWithManager registers the webhook for Cluster in the manager.
 func SetupClusterWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr, &apiv1.Cluster{}).
-		WithValidator(newBypassableValidator[*apiv1.Cluster](&ClusterCustomValidator{})).
+		WithValidator(newBypassableValidator[*apiv1.Cluster](&ClusterCustomValidator{client: mgr.GetClient()})).
 		WithDefaulter(&ClusterCustomDefaulter{}).
 		Complete()
 }
@@ -106,16 +110,27 @@ func (d *ClusterCustomDefaulter) Default(_ context.Context, cluster *apiv1.Clust
 
 // ClusterCustomValidator struct is responsible for validating the Cluster resource
 // when it is created, updated, or deleted.
-type ClusterCustomValidator struct{}
+type ClusterCustomValidator struct {
+	// client is used to check the recovery target against the backup
+	// catalog. It is nil when the validator is used as an admission
+	// guard, to avoid querying the API server at every reconciliation
+	client client.Client
+}
 
 // ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type Cluster.
-func (v *ClusterCustomValidator) ValidateCreate(_ context.Context, cluster *apiv1.Cluster) (admission.Warnings, error) {
+func (v *ClusterCustomValidator) ValidateCreate(ctx context.Context, cluster *apiv1.Cluster) (admission.Warnings, error) {
 	clusterLog.Debug("Validation for Cluster upon creation", "name", cluster.GetName(), "namespace",
 		cluster.GetNamespace())
 
 	allErrs := v.validate(cluster)
 	allWarnings := v.getAdmissionWarnings(cluster)
 
+	if len(allErrs) == 0 {
+		recoveryPlanErrs, recoveryPlanWarnings := v.validateRecoveryPlan(ctx, cluster)
+		allErrs = append(allErrs, recoveryPlanErrs...)
+		allWarnings = append(allWarnings, recoveryPlanWarnings...)
+	}
+
 	if len(allErrs) == 0 {
 		return allWarnings, nil
 	}
@@ -176,12 +191,16 @@ func (v *ClusterCustomValidator) validate(r *apiv1.Cluster) (allErrs field.Error
 		v.validateImagePullPolicy,
 		v.validateRecoveryTarget,
 		v.validatePrimaryUpdateStrategy,
+		v.validateMaintenanceWindows,
 		v.validateMinSyncReplicas,
 		v.validateMaxSyncReplicas,
+		v.validateReplicaAutoscaling,
+		v.validateSwitchoverDrain,
 		v.validateStorageSize,
 		v.validateWalStorageSize,
 		v.validateEphemeralVolumeSource,
 		v.validateTablespaceStorageSize,
+		v.validateStorageAutoscale,
 		v.validateName,
 		v.validateTablespaceNames,
 		v.validateBootstrapPgBaseBackupSource,
@@ -194,6 +213,7 @@ func (v *ClusterCustomValidator) validate(r *apiv1.Cluster) (allErrs field.Error
 		v.validateReplicaMode,
 		v.validateBackupConfiguration,
 		v.validateRetentionPolicy,
+		v.validatePgBaseBackupConfiguration,
 		v.validateConfiguration,
 		v.validateSynchronousReplicaConfiguration,
 		v.validateFailoverQuorumAlphaAnnotation,
@@ -1511,6 +1531,41 @@ func (v *ClusterCustomValidator) validateRecoveryTarget(r *apiv1.Cluster) field.
 	return result
 }
 
+// validateRecoveryPlan resolves the base backup of a cluster bootstrapped
+// from a recovery source, rejecting the recovery targets that cannot be
+// reached. Given the base backup is chosen only once, this is checked
+// only upon creation
+func (v *ClusterCustomValidator) validateRecoveryPlan(
+	ctx context.Context,
+	r *apiv1.Cluster,
+) (field.ErrorList, admission.Warnings) {
+	if v.client == nil || r.Spec.Bootstrap == nil || r.Spec.Bootstrap.Recovery == nil {
+		return nil, nil
+	}
+
+	plan, err := recoveryplan.Build(ctx, v.client, r)
+	if err != nil {
+		clusterLog.Info("Cannot check the recovery target against the backup catalog",
+			"name", r.Name, "namespace", r.Namespace, "error", err)
+		return nil, nil
+	}
+
+	var result field.ErrorList
+	for _, problem := range plan.Problems {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "bootstrap", "recovery"),
+			plan.SourceName,
+			problem))
+	}
+
+	warnings := make(admission.Warnings, 0, len(plan.Warnings))
+	for _, warning := range plan.Warnings {
+		warnings = append(warnings, "spec.bootstrap.recovery: "+warning)
+	}
+
+	return result, warnings
+}
+
 func validateTargetExclusiveness(recoveryTarget *apiv1.RecoveryTarget) field.ErrorList {
 	targets := 0
 	if recoveryTarget.TargetImmediate != nil {
@@ -1569,6 +1624,41 @@ func (v *ClusterCustomValidator) validatePrimaryUpdateStrategy(r *apiv1.Cluster)
 	return nil
 }
 
+// validateMaintenanceWindows validates the schedule, the time zone
+// and the duration of the maintenance windows
+func (v *ClusterCustomValidator) validateMaintenanceWindows(r *apiv1.Cluster) field.ErrorList {
+	var result field.ErrorList
+
+	for idx, window := range r.Spec.MaintenanceWindows {
+		windowPath := field.NewPath("spec", "maintenanceWindows").Index(idx)
+
+		if _, err := cron.Parse(window.Schedule); err != nil {
+			result = append(result, field.Invalid(
+				windowPath.Child("schedule"),
+				window.Schedule,
+				fmt.Sprintf("invalid schedule: %s", err.Error())))
+		}
+
+		if window.TimeZone != "" {
+			if _, err := time.LoadLocation(window.TimeZone); err != nil {
+				result = append(result, field.Invalid(
+					windowPath.Child("timeZone"),
+					window.TimeZone,
+					fmt.Sprintf("invalid time zone: %s", err.Error())))
+			}
+		}
+
+		if window.Duration.Duration <= 0 {
+			result = append(result, field.Invalid(
+				windowPath.Child("duration"),
+				window.Duration.String(),
+				"the duration of a maintenance window must be greater than zero"))
+		}
+	}
+
+	return result
+}
+
 // Validate the maximum number of synchronous instances
 // that should be kept in sync with the primary server
 func (v *ClusterCustomValidator) validateMaxSyncReplicas(r *apiv1.Cluster) field.ErrorList {
@@ -1738,6 +1828,146 @@ func validateStorageConfigurationSize(
 	return result
 }
 
+// validateReplicaAutoscaling checks the configuration of the read-replica autoscaler
+func (v *ClusterCustomValidator) validateReplicaAutoscaling(r *apiv1.Cluster) field.ErrorList {
+	autoscaling := r.Spec.Autoscaling
+	if autoscaling == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	autoscalingPath := field.NewPath("spec", "autoscaling")
+
+	if minInstances := r.GetMinInstancesForSynchronousReplication(); autoscaling.MinInstances < minInstances {
+		result = append(result, field.Invalid(
+			autoscalingPath.Child("minInstances"),
+			autoscaling.MinInstances,
+			fmt.Sprintf("minInstances must be at least %d to satisfy the synchronous replication requirements",
+				minInstances)))
+	}
+
+	if autoscaling.MaxInstances < autoscaling.MinInstances {
+		result = append(result, field.Invalid(
+			autoscalingPath.Child("maxInstances"),
+			autoscaling.MaxInstances,
+			"maxInstances must be greater than or equal to minInstances"))
+	}
+
+	if autoscaling.TargetCPUUtilization == nil &&
+		autoscaling.TargetActiveConnections == nil &&
+		autoscaling.MaxReplicationLag == nil {
+		result = append(result, field.Required(
+			autoscalingPath,
+			"at least one of targetCPUUtilization, targetActiveConnections "+
+				"and maxReplicationLag must be specified"))
+	}
+
+	if autoscaling.TargetCPUUtilization != nil && r.Spec.Resources.Requests.Cpu().IsZero() {
+		result = append(result, field.Invalid(
+			autoscalingPath.Child("targetCPUUtilization"),
+			*autoscaling.TargetCPUUtilization,
+			"targetCPUUtilization requires a CPU request in spec.resources"))
+	}
+
+	return result
+}
+
+// validateSwitchoverDrain checks the configuration of the
+// connection draining preceding a switchover
+func (v *ClusterCustomValidator) validateSwitchoverDrain(r *apiv1.Cluster) field.ErrorList {
+	drain := r.Spec.SwitchoverDrain
+	if drain == nil || drain.Timeout == nil {
+		return nil
+	}
+
+	timeoutPath := field.NewPath("spec", "switchoverDrain", "timeout")
+	if drain.Timeout.Duration <= 0 {
+		return field.ErrorList{field.Invalid(
+			timeoutPath,
+			drain.Timeout.String(),
+			"timeout must be greater than zero")}
+	}
+
+	if r.Spec.MaxSwitchoverDelay > 0 &&
+		drain.Timeout.Duration >= time.Duration(r.Spec.MaxSwitchoverDelay)*time.Second {
+		return field.ErrorList{field.Invalid(
+			timeoutPath,
+			drain.Timeout.String(),
+			"timeout must be lower than switchoverDelay")}
+	}
+
+	return nil
+}
+
+// validateStorageAutoscale checks the autoscale configuration of
+// the storage, of the WAL storage and of the tablespaces
+func (v *ClusterCustomValidator) validateStorageAutoscale(r *apiv1.Cluster) field.ErrorList {
+	result := validateStorageConfigurationAutoscale(
+		r, field.NewPath("spec", "storage"), r.Spec.StorageConfiguration)
+
+	if r.ShouldCreateWalArchiveVolume() {
+		result = append(result, validateStorageConfigurationAutoscale(
+			r, field.NewPath("spec", "walStorage"), *r.Spec.WalStorage)...)
+	}
+
+	for idx, tablespaceConf := range r.Spec.Tablespaces {
+		result = append(result, validateStorageConfigurationAutoscale(
+			r, field.NewPath("spec", "tablespaces").Index(idx).Child("storage"), tablespaceConf.Storage)...)
+	}
+
+	return result
+}
+
+func validateStorageConfigurationAutoscale(
+	r *apiv1.Cluster,
+	structPath *field.Path,
+	storageConfiguration apiv1.StorageConfiguration,
+) field.ErrorList {
+	autoscale := storageConfiguration.Autoscale
+	if autoscale == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	autoscalePath := structPath.Child("autoscale")
+
+	if !r.ShouldResizeInUseVolumes() {
+		result = append(result, field.Invalid(
+			autoscalePath,
+			autoscale,
+			"autoscale requires spec.storage.resizeInUseVolumes to be enabled"))
+	}
+
+	maxSize, err := autoscale.GetMaxSize()
+	if err != nil {
+		return append(result, field.Invalid(
+			autoscalePath.Child("maxSize"),
+			autoscale.MaxSize,
+			"maxSize value isn't valid"))
+	}
+
+	size := storageConfiguration.GetSizeOrNil()
+	if size == nil {
+		size = &resource.Quantity{}
+	}
+
+	if size.Cmp(maxSize) > 0 {
+		result = append(result, field.Invalid(
+			autoscalePath.Child("maxSize"),
+			autoscale.MaxSize,
+			fmt.Sprintf("maxSize can't be lower than the size of the storage (%v)", size)))
+	}
+
+	if _, err := autoscale.GetNextSize(*size); err != nil {
+		result = append(result, field.Invalid(
+			autoscalePath.Child("increment"),
+			autoscale.Increment,
+			"increment must be either a positive quantity or a percentage, like 20%"))
+	}
+
+	return result
+}
+
 // Validate a change in the storage
 func (v *ClusterCustomValidator) validateStorageChange(r, old *apiv1.Cluster) field.ErrorList {
 	return validateStorageConfigurationChange(
@@ -2299,15 +2529,50 @@ func (v *ClusterCustomValidator) validateBackupConfiguration(r *apiv1.Cluster) f
 	)
 }
 
+// validatePgBaseBackupConfiguration validates the configuration
+// of the pgBaseBackup backup method
+func (v *ClusterCustomValidator) validatePgBaseBackupConfiguration(r *apiv1.Cluster) field.ErrorList {
+	if !r.Spec.Backup.IsPgBaseBackupConfigured() || !r.ContainsTablespaces() {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			field.NewPath("spec", "backup", "pgBaseBackup"),
+			r.Spec.Backup.PgBaseBackup.ClaimName,
+			"the pgBaseBackup backup method does not support clusters with tablespaces"),
+	}
+}
+
 // validateRetentionPolicy validates the retention policy configuration
 func (v *ClusterCustomValidator) validateRetentionPolicy(r *apiv1.Cluster) field.ErrorList {
 	if r.Spec.Backup == nil {
 		return nil
 	}
-	return barmanWebhooks.ValidateRetentionPolicy(
+	result := barmanWebhooks.ValidateRetentionPolicy(
 		r.Spec.Backup.RetentionPolicy,
 		field.NewPath("spec", "backup", "retentionPolicy"),
 	)
+
+	if r.Spec.Backup.VolumeSnapshot != nil {
+		retentionPolicy := r.Spec.Backup.VolumeSnapshot.RetentionPolicy
+		if retentionPolicy != nil && retentionPolicy.IsEmpty() {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", "backup", "volumeSnapshot", "retentionPolicy"),
+				retentionPolicy,
+				"at least one retention rule must be specified",
+			))
+		}
+		if _, err := retentionPolicy.GetWindowStart(time.Now()); err != nil {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", "backup", "volumeSnapshot", "retentionPolicy", "keepWithin"),
+				retentionPolicy.KeepWithin,
+				err.Error(),
+			))
+		}
+	}
+
+	return result
 }
 
 func (v *ClusterCustomValidator) validateReplicationSlots(r *apiv1.Cluster) field.ErrorList {
@@ -36,8 +36,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 
@@ -1993,6 +1995,58 @@ var _ = Describe("primary update strategy", func() {
 	})
 })
 
+var _ = Describe("maintenance windows", func() {
+	var v *ClusterCustomValidator
+	BeforeEach(func() {
+		v = &ClusterCustomValidator{}
+	})
+
+	It("allows clusters without maintenance windows", func() {
+		Expect(v.validateMaintenanceWindows(&apiv1.Cluster{})).To(BeEmpty())
+	})
+
+	It("allows valid maintenance windows", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				MaintenanceWindows: []apiv1.MaintenanceWindow{
+					{
+						Schedule: "0 0 2 * * 6",
+						Duration: metav1.Duration{Duration: 4 * time.Hour},
+						TimeZone: "Europe/Rome",
+					},
+				},
+			},
+		}
+		Expect(v.validateMaintenanceWindows(cluster)).To(BeEmpty())
+	})
+
+	It("complains about invalid schedules, time zones and durations", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				MaintenanceWindows: []apiv1.MaintenanceWindow{
+					{
+						Schedule: "every saturday",
+						Duration: metav1.Duration{Duration: 4 * time.Hour},
+					},
+					{
+						Schedule: "0 0 2 * * 6",
+						Duration: metav1.Duration{Duration: 4 * time.Hour},
+						TimeZone: "Moon/Tranquility",
+					},
+					{
+						Schedule: "0 0 2 * * 6",
+					},
+				},
+			},
+		}
+		result := v.validateMaintenanceWindows(cluster)
+		Expect(result).To(HaveLen(3))
+		Expect(result[0].Field).To(Equal("spec.maintenanceWindows[0].schedule"))
+		Expect(result[1].Field).To(Equal("spec.maintenanceWindows[1].timeZone"))
+		Expect(result[2].Field).To(Equal("spec.maintenanceWindows[2].duration"))
+	})
+})
+
 var _ = Describe("Number of synchronous replicas", func() {
 	var v *ClusterCustomValidator
 	BeforeEach(func() {
@@ -3596,6 +3650,89 @@ var _ = Describe("Backup retention policy validation", func() {
 		err := v.validateRetentionPolicy(cluster)
 		Expect(err).To(HaveLen(1))
 	})
+
+	It("doesn't complain if a volume snapshot retention policy is valid", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{
+					VolumeSnapshot: &apiv1.VolumeSnapshotConfiguration{
+						RetentionPolicy: &apiv1.VolumeSnapshotRetentionPolicy{
+							KeepWithin: "7d",
+							KeepDaily:  ptr.To(7),
+						},
+					},
+				},
+			},
+		}
+		err := v.validateRetentionPolicy(cluster)
+		Expect(err).To(BeEmpty())
+	})
+
+	It("complains if a volume snapshot retention policy has no rules", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{
+					VolumeSnapshot: &apiv1.VolumeSnapshotConfiguration{
+						RetentionPolicy: &apiv1.VolumeSnapshotRetentionPolicy{},
+					},
+				},
+			},
+		}
+		err := v.validateRetentionPolicy(cluster)
+		Expect(err).To(HaveLen(1))
+		Expect(err[0].Field).To(Equal("spec.backup.volumeSnapshot.retentionPolicy"))
+	})
+
+	It("complains if a volume snapshot recovery window is not valid", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{
+					VolumeSnapshot: &apiv1.VolumeSnapshotConfiguration{
+						RetentionPolicy: &apiv1.VolumeSnapshotRetentionPolicy{
+							KeepWithin: "7y",
+						},
+					},
+				},
+			},
+		}
+		err := v.validateRetentionPolicy(cluster)
+		Expect(err).To(HaveLen(1))
+		Expect(err[0].Field).To(Equal("spec.backup.volumeSnapshot.retentionPolicy.keepWithin"))
+	})
+})
+
+var _ = Describe("pgBaseBackup configuration validation", func() {
+	var v *ClusterCustomValidator
+	BeforeEach(func() {
+		v = &ClusterCustomValidator{}
+	})
+
+	It("doesn't complain if the cluster has no tablespaces", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{
+					PgBaseBackup: &apiv1.PgBaseBackupConfiguration{ClaimName: "backups"},
+				},
+			},
+		}
+		Expect(v.validatePgBaseBackupConfiguration(cluster)).To(BeEmpty())
+	})
+
+	It("complains if the cluster has tablespaces", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{
+					PgBaseBackup: &apiv1.PgBaseBackupConfiguration{ClaimName: "backups"},
+				},
+				Tablespaces: []apiv1.TablespaceConfiguration{
+					{Name: "tbs1"},
+				},
+			},
+		}
+		result := v.validatePgBaseBackupConfiguration(cluster)
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.backup.pgBaseBackup"))
+	})
 })
 
 var _ = Describe("validation of imports", func() {
@@ -4333,6 +4470,152 @@ var _ = Describe("Storage configuration validation", func() {
 	})
 })
 
+var _ = Describe("Replica autoscaling validation", func() {
+	var v *ClusterCustomValidator
+	BeforeEach(func() {
+		v = &ClusterCustomValidator{}
+	})
+
+	newCluster := func(autoscaling *apiv1.ReplicaAutoscalingConfiguration) *apiv1.Cluster {
+		return &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Instances:   3,
+				Autoscaling: autoscaling,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+				},
+			},
+		}
+	}
+
+	It("succeeds if autoscaling is not configured", func() {
+		Expect(v.validateReplicaAutoscaling(newCluster(nil))).To(BeEmpty())
+	})
+
+	It("succeeds with a valid configuration", func() {
+		Expect(v.validateReplicaAutoscaling(newCluster(&apiv1.ReplicaAutoscalingConfiguration{
+			MinInstances:         2,
+			MaxInstances:         5,
+			TargetCPUUtilization: ptr.To(70),
+		}))).To(BeEmpty())
+	})
+
+	It("complains if no target is specified", func() {
+		Expect(v.validateReplicaAutoscaling(newCluster(&apiv1.ReplicaAutoscalingConfiguration{
+			MinInstances: 2,
+			MaxInstances: 5,
+		}))).To(HaveLen(1))
+	})
+
+	It("complains if maxInstances is lower than minInstances", func() {
+		Expect(v.validateReplicaAutoscaling(newCluster(&apiv1.ReplicaAutoscalingConfiguration{
+			MinInstances:            4,
+			MaxInstances:            3,
+			TargetActiveConnections: ptr.To(10),
+		}))).To(HaveLen(1))
+	})
+
+	It("complains if minInstances doesn't satisfy the synchronous replication", func() {
+		cluster := newCluster(&apiv1.ReplicaAutoscalingConfiguration{
+			MinInstances:            2,
+			MaxInstances:            5,
+			TargetActiveConnections: ptr.To(10),
+		})
+		cluster.Spec.MaxSyncReplicas = 2
+		Expect(v.validateReplicaAutoscaling(cluster)).To(HaveLen(1))
+	})
+
+	It("complains if the CPU target is used without a CPU request", func() {
+		cluster := newCluster(&apiv1.ReplicaAutoscalingConfiguration{
+			MinInstances:         2,
+			MaxInstances:         5,
+			TargetCPUUtilization: ptr.To(70),
+		})
+		cluster.Spec.Resources = corev1.ResourceRequirements{}
+		Expect(v.validateReplicaAutoscaling(cluster)).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("Storage autoscale validation", func() {
+	var v *ClusterCustomValidator
+	BeforeEach(func() {
+		v = &ClusterCustomValidator{}
+	})
+
+	newCluster := func(autoscale *apiv1.StorageAutoscaleConfiguration) *apiv1.Cluster {
+		return &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				StorageConfiguration: apiv1.StorageConfiguration{
+					Size:      "10Gi",
+					Autoscale: autoscale,
+				},
+			},
+		}
+	}
+
+	It("succeeds if autoscale is not configured", func() {
+		Expect(v.validateStorageAutoscale(newCluster(nil))).To(BeEmpty())
+	})
+
+	It("succeeds with a valid autoscale configuration", func() {
+		Expect(v.validateStorageAutoscale(newCluster(&apiv1.StorageAutoscaleConfiguration{
+			MaxSize: "100Gi",
+		}))).To(BeEmpty())
+		Expect(v.validateStorageAutoscale(newCluster(&apiv1.StorageAutoscaleConfiguration{
+			MaxSize:   "100Gi",
+			Increment: "5Gi",
+		}))).To(BeEmpty())
+	})
+
+	It("complains if maxSize is invalid", func() {
+		Expect(v.validateStorageAutoscale(newCluster(&apiv1.StorageAutoscaleConfiguration{
+			MaxSize: "a lot",
+		}))).To(HaveLen(1))
+	})
+
+	It("complains if maxSize is lower than the storage size", func() {
+		Expect(v.validateStorageAutoscale(newCluster(&apiv1.StorageAutoscaleConfiguration{
+			MaxSize: "1Gi",
+		}))).To(HaveLen(1))
+	})
+
+	It("complains if the increment is invalid", func() {
+		for _, increment := range []string{"0%", "-5Gi", "ten%", "more"} {
+			Expect(v.validateStorageAutoscale(newCluster(&apiv1.StorageAutoscaleConfiguration{
+				MaxSize:   "100Gi",
+				Increment: increment,
+			}))).To(HaveLen(1), increment)
+		}
+	})
+
+	It("complains if in-use volumes can't be resized", func() {
+		cluster := newCluster(&apiv1.StorageAutoscaleConfiguration{MaxSize: "100Gi"})
+		cluster.Spec.StorageConfiguration.ResizeInUseVolumes = ptr.To(false)
+		Expect(v.validateStorageAutoscale(cluster)).To(HaveLen(1))
+	})
+
+	It("validates the WAL storage and the tablespaces", func() {
+		cluster := newCluster(nil)
+		cluster.Spec.WalStorage = &apiv1.StorageConfiguration{
+			Size:      "10Gi",
+			Autoscale: &apiv1.StorageAutoscaleConfiguration{MaxSize: "1Gi"},
+		}
+		cluster.Spec.Tablespaces = []apiv1.TablespaceConfiguration{
+			{
+				Name: "tbs1",
+				Storage: apiv1.StorageConfiguration{
+					Size:      "10Gi",
+					Autoscale: &apiv1.StorageAutoscaleConfiguration{MaxSize: "100Gi", Increment: "0"},
+				},
+			},
+		}
+		errs := v.validateStorageAutoscale(cluster)
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Field).To(Equal("spec.walStorage.autoscale.maxSize"))
+		Expect(errs[1].Field).To(Equal("spec.tablespaces[0].storage.autoscale.increment"))
+	})
+})
+
 var _ = Describe("Ephemeral volume configuration validation", func() {
 	var v *ClusterCustomValidator
 	BeforeEach(func() {
@@ -7306,3 +7589,108 @@ var _ = Describe("getFailureDomainTopologyWarnings", func() {
 		Expect(warnings[0]).To(ContainSubstring("Topology labels could not be extracted"))
 	})
 })
+
+var _ = Describe("validateRecoveryPlan", func() {
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "restored", Namespace: "default"},
+		Spec: apiv1.ClusterSpec{
+			Instances:            1,
+			StorageConfiguration: apiv1.StorageConfiguration{Size: "1Gi"},
+			Bootstrap: &apiv1.BootstrapConfiguration{
+				Recovery: &apiv1.BootstrapRecovery{
+					Backup: &apiv1.BackupSource{
+						LocalObjectReference: apiv1.LocalObjectReference{Name: "backup-1"},
+					},
+					RecoveryTarget: &apiv1.RecoveryTarget{TargetTime: "2025-03-10 11:30:00Z"},
+				},
+			},
+		},
+	}
+
+	It("is skipped without a client, as in the admission guard", func(ctx context.Context) {
+		v := &ClusterCustomValidator{}
+		errs, warnings := v.validateRecoveryPlan(ctx, cluster)
+		Expect(errs).To(BeEmpty())
+		Expect(warnings).To(BeEmpty())
+	})
+
+	It("rejects a missing backup", func(ctx context.Context) {
+		v := &ClusterCustomValidator{
+			client: fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).Build(),
+		}
+		errs, _ := v.validateRecoveryPlan(ctx, cluster)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Detail).To(ContainSubstring("backup backup-1 does not exist"))
+	})
+
+	It("rejects a recovery target preceding the end of the backup", func(ctx context.Context) {
+		backup := &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup-1", Namespace: "default"},
+			Status: apiv1.BackupStatus{
+				Phase:     apiv1.BackupPhaseCompleted,
+				Method:    apiv1.BackupMethodBarmanObjectStore,
+				StoppedAt: &metav1.Time{Time: time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)},
+			},
+		}
+		v := &ClusterCustomValidator{
+			client: fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).
+				WithObjects(backup).
+				Build(),
+		}
+
+		_, err := v.ValidateCreate(ctx, cluster)
+		Expect(err).To(MatchError(ContainSubstring("precedes the end of the base backup")))
+
+		backup.Status.StoppedAt = &metav1.Time{Time: time.Date(2025, 3, 10, 11, 0, 0, 0, time.UTC)}
+		v.client = fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(backup).
+			Build()
+		_, err = v.ValidateCreate(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
+
+var _ = Describe("Switchover drain validation", func() {
+	var v *ClusterCustomValidator
+	BeforeEach(func() {
+		v = &ClusterCustomValidator{}
+	})
+
+	newCluster := func(drain *apiv1.SwitchoverDrainConfiguration) *apiv1.Cluster {
+		return &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Instances:          3,
+				MaxSwitchoverDelay: 3600,
+				SwitchoverDrain:    drain,
+			},
+		}
+	}
+
+	It("succeeds if the drain is not configured", func() {
+		Expect(v.validateSwitchoverDrain(newCluster(nil))).To(BeEmpty())
+	})
+
+	It("succeeds with the default timeout", func() {
+		Expect(v.validateSwitchoverDrain(newCluster(&apiv1.SwitchoverDrainConfiguration{}))).To(BeEmpty())
+	})
+
+	It("succeeds with a valid timeout", func() {
+		Expect(v.validateSwitchoverDrain(newCluster(&apiv1.SwitchoverDrainConfiguration{
+			Timeout: &metav1.Duration{Duration: time.Minute},
+		}))).To(BeEmpty())
+	})
+
+	It("complains if the timeout is not positive", func() {
+		result := v.validateSwitchoverDrain(newCluster(&apiv1.SwitchoverDrainConfiguration{
+			Timeout: &metav1.Duration{},
+		}))
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.switchoverDrain.timeout"))
+	})
+
+	It("complains if the timeout exceeds the switchover delay", func() {
+		Expect(v.validateSwitchoverDrain(newCluster(&apiv1.SwitchoverDrainConfiguration{
+			Timeout: &metav1.Duration{Duration: 2 * time.Hour},
+		}))).To(HaveLen(1))
+	})
+})
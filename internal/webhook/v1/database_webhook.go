@@ -21,6 +21,7 @@ package v1
 
 import (
 	"context"
+	"strings"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
 	"github.com/cloudnative-pg/machinery/pkg/stringset"
@@ -144,6 +145,9 @@ func (v *DatabaseCustomValidator) validate(d *apiv1.Database) (allErrs field.Err
 		v.validateSchemas,
 		v.validateFDWs,
 		v.validateForeignServers,
+		v.validateGrants,
+		v.validateDefaultPrivileges,
+		v.validateRestore,
 	}
 
 	for _, validate := range validations {
@@ -236,11 +240,50 @@ func (v *DatabaseCustomValidator) validateForeignServers(d *apiv1.Database) fiel
 
 		allErrs = append(allErrs,
 			validateNameOptionsUsages(itemPath, server.Name, server.Options, server.Usages, nameSet)...)
+
+		allErrs = append(allErrs, validateUserMappings(itemPath.Child("userMappings"), server.UserMappings)...)
 	}
 
 	return allErrs
 }
 
+// validateUserMappings validates the user mappings of a foreign server: each
+// role can be mapped only once, and each option can be set only once.
+func validateUserMappings(basePath *field.Path, userMappings []apiv1.UserMappingSpec) field.ErrorList {
+	var errs field.ErrorList
+
+	roleNames := stringset.New()
+	for i, userMapping := range userMappings {
+		itemPath := basePath.Index(i)
+
+		// PUBLIC is a keyword, and it is case-insensitive
+		roleName := userMapping.Name
+		if strings.EqualFold(roleName, "public") {
+			roleName = "PUBLIC"
+		}
+		if roleNames.Has(roleName) {
+			errs = append(errs, field.Duplicate(itemPath.Child("name"), userMapping.Name))
+		}
+		roleNames.Put(roleName)
+
+		optionNames := stringset.New()
+		for j, option := range userMapping.Options {
+			optionPath := itemPath.Child("options").Index(j)
+			if optionNames.Has(option.Name) {
+				errs = append(errs, field.Duplicate(optionPath.Child("name"), option.Name))
+			}
+			optionNames.Put(option.Name)
+
+			if option.SecretKeyRef != nil && option.Value != "" {
+				errs = append(errs, field.Invalid(optionPath, option.Name,
+					"value and secretKeyRef are mutually exclusive"))
+			}
+		}
+	}
+
+	return errs
+}
+
 // validateServerFDWReference ensures the server references an existing FDW (and is non-empty).
 func (v *DatabaseCustomValidator) validateServerFDWReference(
 	fdwNames *stringset.Data,
@@ -291,3 +334,106 @@ func validateNameOptionsUsages(
 
 	return errs
 }
+
+// privilegesByObjectType are the privileges that can be granted
+// on each type of object
+var privilegesByObjectType = map[apiv1.PrivilegeObjectType][]string{
+	apiv1.PrivilegeObjectTypeDatabase: {"ALL", "CREATE", "CONNECT", "TEMPORARY"},
+	apiv1.PrivilegeObjectTypeSchema:   {"ALL", "USAGE", "CREATE"},
+	apiv1.PrivilegeObjectTypeTable: {
+		"ALL", "SELECT", "INSERT", "UPDATE", "DELETE", "TRUNCATE", "REFERENCES", "TRIGGER", "MAINTAIN",
+	},
+	apiv1.PrivilegeObjectTypeSequence: {"ALL", "USAGE", "SELECT", "UPDATE"},
+	apiv1.PrivilegeObjectTypeFunction: {"ALL", "EXECUTE"},
+	apiv1.PrivilegeObjectTypeType:     {"ALL", "USAGE"},
+}
+
+// validatePrivileges ensures that the privileges can be granted
+// on the given type of object
+func validatePrivileges(
+	itemPath *field.Path,
+	objectType apiv1.PrivilegeObjectType,
+	privileges []apiv1.Privilege,
+) field.ErrorList {
+	var errs field.ErrorList
+
+	allowed := stringset.From(privilegesByObjectType[objectType])
+	for i, privilege := range privileges {
+		if !allowed.Has(string(privilege)) {
+			errs = append(errs, field.NotSupported(
+				itemPath.Child("privileges").Index(i),
+				privilege,
+				privilegesByObjectType[objectType],
+			))
+		}
+	}
+
+	return errs
+}
+
+// validateGrants validates the privileges granted on the database objects.
+// Each privilege set must be unique in .spec.grants
+func (v *DatabaseCustomValidator) validateGrants(d *apiv1.Database) field.ErrorList {
+	var result field.ErrorList
+
+	basePath := field.NewPath("spec", "grants")
+	grantNames := stringset.New()
+	for i, grant := range d.Spec.Grants {
+		itemPath := basePath.Index(i)
+
+		name := grant.GetName()
+		if grantNames.Has(name) {
+			result = append(result, field.Duplicate(itemPath, name))
+		}
+		grantNames.Put(name)
+
+		result = append(result, validatePrivileges(itemPath, grant.ObjectType, grant.Privileges)...)
+	}
+
+	return result
+}
+
+// validateDefaultPrivileges validates the default privileges of the database.
+// Each privilege set must be unique in .spec.defaultPrivileges
+func (v *DatabaseCustomValidator) validateDefaultPrivileges(d *apiv1.Database) field.ErrorList {
+	var result field.ErrorList
+
+	basePath := field.NewPath("spec", "defaultPrivileges")
+	defaultPrivilegeNames := stringset.New()
+	for i, defaultPrivilege := range d.Spec.DefaultPrivileges {
+		itemPath := basePath.Index(i)
+
+		name := defaultPrivilege.GetName()
+		if defaultPrivilegeNames.Has(name) {
+			result = append(result, field.Duplicate(itemPath, name))
+		}
+		defaultPrivilegeNames.Put(name)
+
+		result = append(result,
+			validatePrivileges(itemPath, defaultPrivilege.ObjectType, defaultPrivilege.Privileges)...)
+	}
+
+	return result
+}
+
+// validateRestore validates the logical backup restored into the database
+func (v *DatabaseCustomValidator) validateRestore(d *apiv1.Database) field.ErrorList {
+	if d.Spec.Restore == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "restore")
+
+	if d.Spec.Restore.BackupRef.Name == "" {
+		result = append(result, field.Required(basePath.Child("backup", "name"),
+			"the name of the backup to be restored is required"))
+	}
+
+	if d.Spec.Ensure == apiv1.EnsureAbsent {
+		result = append(result, field.Invalid(basePath, d.Spec.Restore.BackupRef.Name,
+			"a backup cannot be restored into a database that must be absent"))
+	}
+
+	return result
+}
@@ -20,6 +20,7 @@ SPDX-License-Identifier: Apache-2.0
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -319,4 +320,189 @@ var _ = Describe("Database validation", func() {
 			"spec.servers[1].name":            "server1",
 		})
 	})
+
+	It("doesn't complain when restoring a logical backup", func() {
+		db := &apiv1.Database{
+			Spec: apiv1.DatabaseSpec{
+				Restore: &apiv1.DatabaseRestoreSource{
+					BackupRef: corev1.LocalObjectReference{Name: "backup-example"},
+					Tables:    []string{"public.orders"},
+				},
+			},
+		}
+		Expect(v.validate(db)).To(BeEmpty())
+	})
+
+	It("complains when the backup to be restored is not set", func() {
+		db := &apiv1.Database{
+			Spec: apiv1.DatabaseSpec{
+				Restore: &apiv1.DatabaseRestoreSource{},
+			},
+		}
+		errs := v.validate(db)
+		Expect(extractErrorFields(errs)).To(ConsistOf("spec.restore.backup.name"))
+	})
+
+	It("complains when restoring a backup into an absent database", func() {
+		db := &apiv1.Database{
+			Spec: apiv1.DatabaseSpec{
+				Ensure: apiv1.EnsureAbsent,
+				Restore: &apiv1.DatabaseRestoreSource{
+					BackupRef: corev1.LocalObjectReference{Name: "backup-example"},
+				},
+			},
+		}
+		errs := v.validate(db)
+		Expect(extractErrorFields(errs)).To(ConsistOf("spec.restore"))
+	})
+
+	It("doesn't complain with distinct grants and default privileges", func() {
+		db := &apiv1.Database{
+			Spec: apiv1.DatabaseSpec{
+				Grants: []apiv1.GrantSpec{
+					{
+						Role:       "app",
+						Privileges: []apiv1.Privilege{"SELECT", "INSERT"},
+						ObjectType: apiv1.PrivilegeObjectTypeTable,
+						Schema:     "public",
+					},
+					{
+						Role:       "app",
+						Privileges: []apiv1.Privilege{"CONNECT"},
+						ObjectType: apiv1.PrivilegeObjectTypeDatabase,
+					},
+				},
+				DefaultPrivileges: []apiv1.DefaultPrivilegeSpec{
+					{
+						Role:       "app",
+						Privileges: []apiv1.Privilege{"USAGE"},
+						ObjectType: apiv1.PrivilegeObjectTypeType,
+					},
+				},
+			},
+		}
+		Expect(v.validate(db)).To(BeEmpty())
+	})
+
+	It("complains for duplicate grants", func() {
+		grant := apiv1.GrantSpec{
+			Role:       "app",
+			Privileges: []apiv1.Privilege{"USAGE"},
+			ObjectType: apiv1.PrivilegeObjectTypeSchema,
+			Schema:     "public",
+		}
+		db := &apiv1.Database{
+			Spec: apiv1.DatabaseSpec{
+				Grants: []apiv1.GrantSpec{grant, grant},
+			},
+		}
+		errs := v.validate(db)
+		expectDuplicateErrors(errs, map[string]string{"spec.grants[1]": "USAGE on schema public to app"})
+	})
+
+	It("complains for duplicate default privileges", func() {
+		defaultPrivilege := apiv1.DefaultPrivilegeSpec{
+			Role:       "app",
+			Privileges: []apiv1.Privilege{"SELECT"},
+			ObjectType: apiv1.PrivilegeObjectTypeTable,
+			Schema:     "public",
+		}
+		db := &apiv1.Database{
+			Spec: apiv1.DatabaseSpec{
+				DefaultPrivileges: []apiv1.DefaultPrivilegeSpec{defaultPrivilege, defaultPrivilege},
+			},
+		}
+		errs := v.validate(db)
+		expectDuplicateErrors(errs, map[string]string{
+			"spec.defaultPrivileges[1]": "SELECT on table in schema public to app",
+		})
+	})
+
+	It("complains for privileges not available on the object type", func() {
+		db := &apiv1.Database{
+			Spec: apiv1.DatabaseSpec{
+				Grants: []apiv1.GrantSpec{
+					{
+						Role:       "app",
+						Privileges: []apiv1.Privilege{"SELECT", "EXECUTE"},
+						ObjectType: apiv1.PrivilegeObjectTypeTable,
+						Schema:     "public",
+					},
+				},
+				DefaultPrivileges: []apiv1.DefaultPrivilegeSpec{
+					{
+						Role:       "app",
+						Privileges: []apiv1.Privilege{"CONNECT"},
+						ObjectType: apiv1.PrivilegeObjectTypeSchema,
+					},
+				},
+			},
+		}
+		errs := v.validate(db)
+		Expect(extractErrorFields(errs)).To(ConsistOf(
+			"spec.grants[0].privileges[1]",
+			"spec.defaultPrivileges[0].privileges[0]",
+		))
+	})
+
+	It("complains for duplicate user mappings and options within a foreign server", func() {
+		db := &apiv1.Database{
+			Spec: apiv1.DatabaseSpec{
+				FDWs: []apiv1.FDWSpec{createFDWSpec("postgres_fdw")},
+				Servers: []apiv1.ServerSpec{
+					{
+						DatabaseObjectSpec: apiv1.DatabaseObjectSpec{Name: "server1"},
+						FdwName:            "postgres_fdw",
+						UserMappings: []apiv1.UserMappingSpec{
+							{
+								Name: "app",
+								Options: []apiv1.UserMappingOptionSpec{
+									{Name: "user", Value: "remote"},
+									{Name: "user", Value: "other"},
+								},
+							},
+							{Name: "PUBLIC"},
+							{Name: "public"},
+						},
+					},
+				},
+			},
+		}
+		errs := v.validate(db)
+		expectDuplicateErrors(errs, map[string]string{
+			"spec.servers[0].userMappings[0].options[1].name": "user",
+			"spec.servers[0].userMappings[2].name":            "public",
+		})
+	})
+
+	It("complains when a user mapping option has both a value and a secret", func() {
+		db := &apiv1.Database{
+			Spec: apiv1.DatabaseSpec{
+				FDWs: []apiv1.FDWSpec{createFDWSpec("postgres_fdw")},
+				Servers: []apiv1.ServerSpec{
+					{
+						DatabaseObjectSpec: apiv1.DatabaseObjectSpec{Name: "server1"},
+						FdwName:            "postgres_fdw",
+						UserMappings: []apiv1.UserMappingSpec{
+							{
+								Name: "app",
+								Options: []apiv1.UserMappingOptionSpec{
+									{
+										Name:  "password",
+										Value: "clear-text",
+										SecretKeyRef: &apiv1.SecretKeySelector{
+											LocalObjectReference: apiv1.LocalObjectReference{Name: "credentials"},
+											Key:                  "password",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		errs := v.validate(db)
+		Expect(extractErrorFields(errs)).To(ConsistOf("spec.servers[0].userMappings[0].options[0]"))
+	})
 })
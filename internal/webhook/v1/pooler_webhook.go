@@ -22,6 +22,7 @@ package v1
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
 	"github.com/cloudnative-pg/machinery/pkg/stringset"
@@ -33,6 +34,7 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/webhook/guard"
+	pgbouncerConfig "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/config"
 )
 
 // AllowedPgbouncerGenericConfigurationParameters is the list of allowed parameters for PgBouncer
@@ -97,6 +99,29 @@ var AllowedPgbouncerGenericConfigurationParameters = stringset.From([]string{
 	"verbose",
 })
 
+// AllowedPgCatGeneralConfigurationParameters is the list of parameters
+// of the `[general]` section allowed for PgCat
+var AllowedPgCatGeneralConfigurationParameters = stringset.From([]string{
+	"ban_time",
+	"connect_timeout",
+	"healthcheck_delay",
+	"healthcheck_timeout",
+	"idle_client_in_transaction_timeout",
+	"idle_timeout",
+	"log_client_connections",
+	"log_client_disconnections",
+	"prepared_statements",
+	"prepared_statements_cache_size",
+	"server_lifetime",
+	"server_round_robin",
+	"shutdown_timeout",
+	"tcp_keepalives_count",
+	"tcp_keepalives_idle",
+	"tcp_keepalives_interval",
+	"verify_server_certificate",
+	"worker_threads",
+})
+
 // poolerLog is for logging in this package.
 var poolerLog = log.WithName("pooler-resource").WithValues("version", "v1")
 
@@ -193,6 +218,20 @@ func (v *PoolerCustomValidator) ValidateDelete(_ context.Context, pooler *apiv1.
 }
 
 func (v *PoolerCustomValidator) validatePgBouncer(r *apiv1.Pooler) field.ErrorList {
+	if r.Spec.PgBouncer != nil && r.Spec.PgCat != nil {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "pgcat"),
+				"",
+				"pgbouncer and pgcat configurations are mutually exclusive",
+			),
+		}
+	}
+
+	if r.Spec.PgCat != nil {
+		return nil
+	}
+
 	if r.Spec.PgBouncer == nil {
 		return field.ErrorList{
 			field.Invalid(
@@ -227,6 +266,51 @@ func (v *PoolerCustomValidator) validatePgBouncer(r *apiv1.Pooler) field.ErrorLi
 	return result
 }
 
+// validatePgCat validates the configuration of the Poolers running PgCat
+func (v *PoolerCustomValidator) validatePgCat(r *apiv1.Pooler) field.ErrorList {
+	if r.Spec.PgCat == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	pgCatPath := field.NewPath("spec", "pgcat")
+
+	if r.Spec.PgCat.Image == "" {
+		result = append(result,
+			field.Required(pgCatPath.Child("image"), "must specify the PgCat image"))
+	}
+
+	if r.Spec.PgCat.AuthQuerySecret.Name == "" {
+		result = append(result,
+			field.Required(pgCatPath.Child("authQuerySecret", "name"),
+				"must specify the secret with the credentials to run the auth query"))
+	}
+
+	if len(r.Spec.PgCat.Databases) == 0 {
+		result = append(result,
+			field.Required(pgCatPath.Child("databases"), "must specify at least a database"))
+	}
+	for idx := range r.Spec.PgCat.Databases {
+		database := &r.Spec.PgCat.Databases[idx]
+		if len(database.Users) == 0 {
+			result = append(result,
+				field.Required(pgCatPath.Child("databases").Index(idx).Child("users"),
+					"must specify at least a user"))
+		}
+	}
+
+	for param := range r.Spec.PgCat.Parameters {
+		if !AllowedPgCatGeneralConfigurationParameters.Has(param) {
+			result = append(result,
+				field.Invalid(
+					pgCatPath.Child("parameters"),
+					param, "Invalid or reserved parameter"))
+		}
+	}
+
+	return result
+}
+
 func (v *PoolerCustomValidator) validateCluster(r *apiv1.Pooler) field.ErrorList {
 	var result field.ErrorList
 	if r.Spec.Cluster.Name == "" {
@@ -248,11 +332,150 @@ func (v *PoolerCustomValidator) validateCluster(r *apiv1.Pooler) field.ErrorList
 // a list of errors
 func (v *PoolerCustomValidator) validate(r *apiv1.Pooler) (allErrs field.ErrorList) {
 	allErrs = append(allErrs, v.validatePgBouncer(r)...)
+	allErrs = append(allErrs, v.validatePgCat(r)...)
 	allErrs = append(allErrs, v.validateCluster(r)...)
 	allErrs = append(allErrs, v.validateMonitoring(r)...)
+	allErrs = append(allErrs, v.validateDatabases(r)...)
+	allErrs = append(allErrs, v.validateUsers(r)...)
+	allErrs = append(allErrs, v.validateReplicaLoadBalancing(r)...)
+	allErrs = append(allErrs, v.validateAutoscaling(r)...)
 	return allErrs
 }
 
+// validateDatabases validates the databases exposed by PgBouncer
+func (v *PoolerCustomValidator) validateDatabases(r *apiv1.Pooler) field.ErrorList {
+	if r.Spec.PgBouncer == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	for idx := range r.Spec.PgBouncer.Databases {
+		database := &r.Spec.PgBouncer.Databases[idx]
+		databasePath := field.NewPath("spec", "pgbouncer", "databases").Index(idx)
+
+		if strings.HasPrefix(database.Name, "cnpg_auth_") {
+			result = append(result,
+				field.Invalid(
+					databasePath.Child("name"),
+					database.Name, "the cnpg_auth_ prefix is reserved to the operator"))
+		}
+
+		if database.IsFallback() && database.DBName != "" {
+			result = append(result,
+				field.Invalid(
+					databasePath.Child("dbname"),
+					database.DBName, "the fallback entry cannot specify a database name"))
+		}
+
+		if database.GetClusterName(r) == r.Name {
+			result = append(result,
+				field.Invalid(
+					databasePath.Child("cluster", "name"),
+					database.GetClusterName(r), "the pooler resource cannot have the same name of a cluster"))
+		}
+	}
+
+	return result
+}
+
+// validateUsers validates the per-user settings of PgBouncer
+func (v *PoolerCustomValidator) validateUsers(r *apiv1.Pooler) field.ErrorList {
+	if r.Spec.PgBouncer == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	for idx := range r.Spec.PgBouncer.Users {
+		user := &r.Spec.PgBouncer.Users[idx]
+		userPath := field.NewPath("spec", "pgbouncer", "users").Index(idx)
+
+		if user.Name == pgbouncerConfig.PgBouncerAdminUser {
+			result = append(result,
+				field.Invalid(
+					userPath.Child("name"),
+					user.Name, "the PgBouncer administrative user cannot be customized"))
+		}
+
+		if user.PoolMode == "" && user.PoolSize == nil && user.MaxUserConnections == nil {
+			result = append(result,
+				field.Required(
+					userPath,
+					"at least one of poolMode, poolSize and maxUserConnections must be specified"))
+		}
+	}
+
+	return result
+}
+
+// validateReplicaLoadBalancing validates the load balancing among the replicas
+func (v *PoolerCustomValidator) validateReplicaLoadBalancing(r *apiv1.Pooler) field.ErrorList {
+	if r.Spec.ReplicaLoadBalancing == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	path := field.NewPath("spec", "replicaLoadBalancing")
+
+	if r.Spec.Type != apiv1.PoolerTypeRO {
+		result = append(result,
+			field.Invalid(
+				path,
+				r.Spec.ReplicaLoadBalancing, "replica load balancing is only allowed for ro poolers"))
+	}
+
+	if r.Spec.ReplicaLoadBalancing.MaxLag.Duration <= 0 {
+		result = append(result,
+			field.Invalid(
+				path.Child("maxLag"),
+				r.Spec.ReplicaLoadBalancing.MaxLag.String(), "must be greater than zero"))
+	}
+
+	if r.Spec.PgBouncer != nil && r.Spec.PgBouncer.Parameters["server_tls_sslmode"] == "verify-full" {
+		// PgBouncer connects to the replicas using their IP addresses,
+		// which are not included in the server certificates
+		result = append(result,
+			field.Invalid(
+				field.NewPath("spec", "pgbouncer", "parameters", "server_tls_sslmode"),
+				"verify-full", "not supported when the replicas are load balanced"))
+	}
+
+	return result
+}
+
+// validateAutoscaling validates the configuration of the pooler autoscaler
+func (v *PoolerCustomValidator) validateAutoscaling(r *apiv1.Pooler) field.ErrorList {
+	autoscaling := r.Spec.Autoscaling
+	if autoscaling == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	path := field.NewPath("spec", "autoscaling")
+
+	if autoscaling.TargetClientsWaiting == nil && autoscaling.TargetMaxWait == nil {
+		result = append(result,
+			field.Required(
+				path,
+				"at least one of targetClientsWaiting and targetMaxWait must be specified"))
+	}
+
+	if autoscaling.TargetMaxWait != nil && autoscaling.TargetMaxWait.Duration <= 0 {
+		result = append(result,
+			field.Invalid(
+				path.Child("targetMaxWait"),
+				autoscaling.TargetMaxWait.String(), "must be greater than zero"))
+	}
+
+	if autoscaling.ScaleDownStabilizationWindow != nil && autoscaling.ScaleDownStabilizationWindow.Duration < 0 {
+		result = append(result,
+			field.Invalid(
+				path.Child("scaleDownStabilizationWindow"),
+				autoscaling.ScaleDownStabilizationWindow.String(), "cannot be negative"))
+	}
+
+	return result
+}
+
 // validateMonitoring enforces a configuration hygiene rule: when the metrics
 // endpoint is switched to TLS *and* the operator is asked to generate the
 // PodMonitor, the user must supply a clientTLSSecret so the pooler presents
@@ -276,6 +499,12 @@ func (v *PoolerCustomValidator) validateMonitoring(r *apiv1.Pooler) field.ErrorL
 		return nil
 	}
 
+	// PgCat has no client certificate of its own, so its metrics endpoint
+	// always presents the server certificate of the cluster
+	if r.Spec.PgCat != nil {
+		return nil
+	}
+
 	// No operator-generated PodMonitor → no misleading operator-side TLS
 	// config to prevent. Let the user manage their own scrape config.
 	//nolint:staticcheck // EnablePodMonitor is a deprecated sub-field but still honoured during the deprecation window
@@ -20,8 +20,11 @@ SPDX-License-Identifier: Apache-2.0
 package v1
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 
@@ -86,6 +89,27 @@ var _ = Describe("Pooler validation", func() {
 		Expect(v.validatePgBouncer(pooler)).To(BeEmpty())
 	})
 
+	It("allows not specifying pgbouncer when pgcat is used", func() {
+		pooler := &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				PgCat: &apiv1.PgCatSpec{},
+			},
+		}
+
+		Expect(v.validatePgBouncer(pooler)).To(BeEmpty())
+	})
+
+	It("doesn't allow specifying both pgbouncer and pgcat", func() {
+		pooler := &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				PgBouncer: &apiv1.PgBouncerSpec{},
+				PgCat:     &apiv1.PgCatSpec{},
+			},
+		}
+
+		Expect(v.validatePgBouncer(pooler)).NotTo(BeEmpty())
+	})
+
 	It("doesn't allow not specifying a cluster name", func() {
 		pooler := &apiv1.Pooler{
 			Spec: apiv1.PoolerSpec{
@@ -150,6 +174,199 @@ var _ = Describe("Pooler validation", func() {
 		}
 		Expect(v.validatePgbouncerGenericParameters(pooler)).To(BeEmpty())
 	})
+
+	It("does not complain when given valid databases", func() {
+		pooler := &apiv1.Pooler{
+			ObjectMeta: metav1.ObjectMeta{Name: "pooler-example"},
+			Spec: apiv1.PoolerSpec{
+				Cluster: apiv1.LocalObjectReference{Name: "cluster-example"},
+				PgBouncer: &apiv1.PgBouncerSpec{
+					Databases: []apiv1.PgBouncerDatabase{
+						{Name: "*"},
+						{Name: "app", Cluster: &apiv1.LocalObjectReference{Name: "cluster-other"}, DBName: "other"},
+					},
+				},
+			},
+		}
+		Expect(v.validateDatabases(pooler)).To(BeEmpty())
+	})
+
+	It("does complain when given invalid databases", func() {
+		pooler := &apiv1.Pooler{
+			ObjectMeta: metav1.ObjectMeta{Name: "pooler-example"},
+			Spec: apiv1.PoolerSpec{
+				Cluster: apiv1.LocalObjectReference{Name: "cluster-example"},
+				PgBouncer: &apiv1.PgBouncerSpec{
+					Databases: []apiv1.PgBouncerDatabase{
+						{Name: "*", DBName: "app"},
+						{Name: "cnpg_auth_app"},
+						{Name: "app", Cluster: &apiv1.LocalObjectReference{Name: "pooler-example"}},
+					},
+				},
+			},
+		}
+		Expect(v.validateDatabases(pooler)).To(HaveLen(3))
+	})
+})
+
+var _ = Describe("Pooler validateReplicaLoadBalancing", func() {
+	var v *PoolerCustomValidator
+	BeforeEach(func() {
+		v = &PoolerCustomValidator{}
+	})
+
+	It("doesn't complain when the replicas are load balanced by a ro pooler", func() {
+		pooler := &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				Type:      apiv1.PoolerTypeRO,
+				PgBouncer: &apiv1.PgBouncerSpec{},
+				ReplicaLoadBalancing: &apiv1.PoolerReplicaLoadBalancing{
+					MaxLag: metav1.Duration{Duration: 30 * time.Second},
+				},
+			},
+		}
+		Expect(v.validateReplicaLoadBalancing(pooler)).To(BeEmpty())
+	})
+
+	It("does complain when the configuration is invalid", func() {
+		pooler := &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				Type: apiv1.PoolerTypeRW,
+				PgBouncer: &apiv1.PgBouncerSpec{
+					Parameters: map[string]string{"server_tls_sslmode": "verify-full"},
+				},
+				ReplicaLoadBalancing: &apiv1.PoolerReplicaLoadBalancing{},
+			},
+		}
+		Expect(v.validateReplicaLoadBalancing(pooler)).To(HaveLen(3))
+	})
+})
+
+var _ = Describe("Pooler validateAutoscaling", func() {
+	var v *PoolerCustomValidator
+	BeforeEach(func() {
+		v = &PoolerCustomValidator{}
+	})
+
+	It("doesn't complain when the autoscaler has a target", func() {
+		pooler := &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				Autoscaling: &apiv1.PoolerAutoscalingConfiguration{
+					MinInstances:  1,
+					MaxInstances:  4,
+					TargetMaxWait: &metav1.Duration{Duration: time.Second},
+				},
+			},
+		}
+		Expect(v.validateAutoscaling(pooler)).To(BeEmpty())
+	})
+
+	It("does complain when the configuration is invalid", func() {
+		pooler := &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				Autoscaling: &apiv1.PoolerAutoscalingConfiguration{
+					MinInstances:                 1,
+					MaxInstances:                 4,
+					ScaleDownStabilizationWindow: &metav1.Duration{Duration: -time.Second},
+				},
+			},
+		}
+		Expect(v.validateAutoscaling(pooler)).To(HaveLen(2))
+
+		pooler.Spec.Autoscaling.ScaleDownStabilizationWindow = nil
+		pooler.Spec.Autoscaling.TargetMaxWait = &metav1.Duration{}
+		Expect(v.validateAutoscaling(pooler)).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("Pooler validateUsers", func() {
+	var v *PoolerCustomValidator
+	BeforeEach(func() {
+		v = &PoolerCustomValidator{}
+	})
+
+	It("doesn't complain when given valid users", func() {
+		pooler := &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				PgBouncer: &apiv1.PgBouncerSpec{
+					PoolMode: apiv1.PgBouncerPoolModeTransaction,
+					Users: []apiv1.PgBouncerUser{
+						{Name: "batch", PoolMode: apiv1.PgBouncerPoolModeSession},
+						{Name: "web", PoolSize: ptr.To(int32(30)), MaxUserConnections: ptr.To(int32(100))},
+					},
+				},
+			},
+		}
+		Expect(v.validateUsers(pooler)).To(BeEmpty())
+	})
+
+	It("does complain when given invalid users", func() {
+		pooler := &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				PgBouncer: &apiv1.PgBouncerSpec{
+					Users: []apiv1.PgBouncerUser{
+						{Name: "pgbouncer", PoolMode: apiv1.PgBouncerPoolModeSession},
+						{Name: "web"},
+					},
+				},
+			},
+		}
+		Expect(v.validateUsers(pooler)).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("Pooler validatePgCat", func() {
+	var v *PoolerCustomValidator
+	BeforeEach(func() {
+		v = &PoolerCustomValidator{}
+	})
+
+	newPooler := func() *apiv1.Pooler {
+		return &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				PgCat: &apiv1.PgCatSpec{
+					Image:           "pgcat:latest",
+					AuthQuerySecret: apiv1.LocalObjectReference{Name: "auth"},
+					Databases:       []apiv1.PgCatDatabase{{Name: "app", Users: []string{"app"}}},
+					Parameters:      map[string]string{"ban_time": "30"},
+				},
+			},
+		}
+	}
+
+	It("ignores the Poolers running PgBouncer", func() {
+		Expect(v.validatePgCat(&apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{PgBouncer: &apiv1.PgBouncerSpec{}},
+		})).To(BeEmpty())
+	})
+
+	It("doesn't complain when given a valid configuration", func() {
+		Expect(v.validatePgCat(newPooler())).To(BeEmpty())
+	})
+
+	It("requires the image, the auth query secret and the databases", func() {
+		pooler := newPooler()
+		pooler.Spec.PgCat.Image = ""
+		pooler.Spec.PgCat.AuthQuerySecret.Name = ""
+		pooler.Spec.PgCat.Databases = nil
+		Expect(v.validatePgCat(pooler)).To(HaveLen(3))
+	})
+
+	It("requires the users of every database", func() {
+		pooler := newPooler()
+		pooler.Spec.PgCat.Databases = append(pooler.Spec.PgCat.Databases, apiv1.PgCatDatabase{Name: "reports"})
+		errs := v.validatePgCat(pooler)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.pgcat.databases[1].users"))
+	})
+
+	It("rejects the reserved parameters", func() {
+		pooler := newPooler()
+		pooler.Spec.PgCat.Parameters["admin_password"] = "secret"
+		errs := v.validatePgCat(pooler)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].BadValue).To(Equal("admin_password"))
+	})
 })
 
 var _ = Describe("Pooler validateMonitoring", func() {
@@ -180,6 +397,15 @@ var _ = Describe("Pooler validateMonitoring", func() {
 		Expect(v.validateMonitoring(&apiv1.Pooler{})).To(BeEmpty())
 	})
 
+	It("accepts metrics TLS when PgCat is used", func() {
+		for _, enablePodMonitor := range []bool{false, true} {
+			pooler := tlsOnPooler(enablePodMonitor, nil)
+			pooler.Spec.PgBouncer = nil
+			pooler.Spec.PgCat = &apiv1.PgCatSpec{}
+			Expect(v.validateMonitoring(pooler)).To(BeEmpty())
+		}
+	})
+
 	It("returns no error when metrics TLS is enabled and clientTLSSecret is set", func() {
 		pooler := tlsOnPooler(true, &apiv1.LocalObjectReference{Name: "my-tls"})
 		Expect(v.validateMonitoring(pooler)).To(BeEmpty())
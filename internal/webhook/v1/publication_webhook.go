@@ -0,0 +1,281 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// publicationRowFilterMinMajorVersion is the first PostgreSQL major
+// version supporting row filters and column lists in publications
+const publicationRowFilterMinMajorVersion = 15
+
+// publicationLog is for logging in this package.
+var publicationLog = log.WithName("publication-resource").WithValues("version", "v1")
+
+// SetupPublicationWebhookWithManager registers the webhook for Publication in the manager.
+func SetupPublicationWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &apiv1.Publication{}).
+		WithValidator(newBypassableValidator[*apiv1.Publication](
+			&PublicationCustomValidator{client: mgr.GetClient()})).
+		Complete()
+}
+
+// NOTE: The 'path' attribute must follow a specific pattern and should not be modified directly here.
+// Modifying the path for an invalid path can cause API server errors; failing to locate the webhook.
+//
+// +kubebuilder:webhook:webhookVersions={v1},admissionReviewVersions={v1},verbs=create;update,path=/validate-postgresql-cnpg-io-v1-publication,mutating=false,failurePolicy=fail,groups=postgresql.cnpg.io,resources=publications,versions=v1,name=vpublication.cnpg.io,sideEffects=None
+
+// PublicationCustomValidator is responsible for validating the Publication
+// resource when it is created, updated, or deleted.
+type PublicationCustomValidator struct {
+	// client is used to get the PostgreSQL major version of the
+	// publisher cluster
+	client client.Client
+}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type Publication.
+func (v *PublicationCustomValidator) ValidateCreate(
+	ctx context.Context, publication *apiv1.Publication,
+) (admission.Warnings, error) {
+	publicationLog.Info(
+		"Validation for Publication upon creation",
+		"name", publication.GetName(), "namespace", publication.GetNamespace())
+
+	return v.validateAndWrap(ctx, publication)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type Publication.
+func (v *PublicationCustomValidator) ValidateUpdate(
+	ctx context.Context,
+	_ *apiv1.Publication, publication *apiv1.Publication,
+) (admission.Warnings, error) {
+	publicationLog.Info(
+		"Validation for Publication upon update",
+		"name", publication.GetName(), "namespace", publication.GetNamespace())
+
+	return v.validateAndWrap(ctx, publication)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type Publication.
+func (v *PublicationCustomValidator) ValidateDelete(
+	_ context.Context, publication *apiv1.Publication,
+) (admission.Warnings, error) {
+	publicationLog.Info(
+		"Validation for Publication upon deletion",
+		"name", publication.GetName(), "namespace", publication.GetNamespace())
+
+	return nil, nil
+}
+
+func (v *PublicationCustomValidator) validateAndWrap(
+	ctx context.Context,
+	publication *apiv1.Publication,
+) (admission.Warnings, error) {
+	allErrs := v.validate(publication)
+
+	majorVersion, err := v.getClusterMajorVersion(ctx, publication)
+	if err != nil {
+		return nil, err
+	}
+	allErrs = append(allErrs, v.validateMajorVersionFeatures(publication, majorVersion)...)
+
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+
+	return nil, apierrors.NewInvalid(
+		schema.GroupKind{Group: apiv1.SchemeGroupVersion.Group, Kind: "Publication"},
+		publication.Name, allErrs)
+}
+
+// validate groups the validation logic for publications returning a list of all encountered errors
+func (v *PublicationCustomValidator) validate(publication *apiv1.Publication) field.ErrorList {
+	var result field.ErrorList
+
+	// The typed fields take precedence over the generic parameters,
+	// so setting both would be ambiguous
+	parametersPath := field.NewPath("spec", "parameters")
+	if _, ok := publication.Spec.Parameters["publish"]; ok && len(publication.Spec.Publish) > 0 {
+		result = append(result, field.Invalid(
+			parametersPath.Key("publish"),
+			publication.Spec.Parameters["publish"],
+			"cannot be set together with spec.publish"))
+	}
+	if _, ok := publication.Spec.Parameters["publish_via_partition_root"]; ok &&
+		publication.Spec.PublishViaPartitionRoot != nil {
+		result = append(result, field.Invalid(
+			parametersPath.Key("publish_via_partition_root"),
+			publication.Spec.Parameters["publish_via_partition_root"],
+			"cannot be set together with spec.publishViaPartitionRoot"))
+	}
+
+	objectsPath := field.NewPath("spec", "target", "objects")
+	for i, object := range publication.Spec.Target.Objects {
+		if object.Table == nil || len(object.Table.Where) == 0 {
+			continue
+		}
+
+		if err := validateRowFilter(object.Table.Where); err != nil {
+			result = append(result, field.Invalid(
+				objectsPath.Index(i).Child("table", "where"),
+				object.Table.Where,
+				err.Error()))
+		}
+	}
+
+	return result
+}
+
+// validateRowFilter checks that a row filter is a single expression that
+// cannot escape the parentheses of the WHERE clause it is embedded in.
+// String literals and quoted identifiers are skipped, while statement
+// separators, comments, dollar quoting, backslashes and unbalanced
+// parentheses are rejected.
+func validateRowFilter(where string) error {
+	// Backslashes are rejected everywhere, as they can escape the quotes
+	// of the string literals using the escape string syntax
+	if strings.ContainsRune(where, '\\') {
+		return fmt.Errorf("backslashes are not allowed")
+	}
+
+	depth := 0
+	for i := 0; i < len(where); i++ {
+		switch c := where[i]; c {
+		case '\'', '"':
+			end := strings.IndexByte(where[i+1:], c)
+			if end < 0 {
+				return fmt.Errorf("unterminated quoted string or identifier")
+			}
+			i += end + 1
+		case ';':
+			return fmt.Errorf("statement separators are not allowed")
+		case '$':
+			return fmt.Errorf("dollar quoting and positional parameters are not allowed")
+		case '-', '/', '*':
+			if i+1 < len(where) && isCommentToken(c, where[i+1]) {
+				return fmt.Errorf("comments are not allowed")
+			}
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return fmt.Errorf("unbalanced parentheses")
+			}
+		}
+	}
+
+	if depth != 0 {
+		return fmt.Errorf("unbalanced parentheses")
+	}
+	return nil
+}
+
+// isCommentToken returns true if the passed characters start or end a comment
+func isCommentToken(first, second byte) bool {
+	return (first == '-' && second == '-') ||
+		(first == '/' && second == '*') ||
+		(first == '*' && second == '/')
+}
+
+// validateMajorVersionFeatures checks that the features used by the
+// publication are supported by the PostgreSQL major version of the
+// publisher cluster. A zero major version means that it is not known.
+func (v *PublicationCustomValidator) validateMajorVersionFeatures(
+	publication *apiv1.Publication,
+	majorVersion int,
+) field.ErrorList {
+	if majorVersion == 0 || majorVersion >= publicationRowFilterMinMajorVersion {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "target", "objects")
+	for i, object := range publication.Spec.Target.Objects {
+		if object.Table == nil {
+			continue
+		}
+
+		tablePath := basePath.Index(i).Child("table")
+		if len(object.Table.Where) > 0 {
+			result = append(result, field.Invalid(
+				tablePath.Child("where"),
+				object.Table.Where,
+				fmt.Sprintf("row filters require PostgreSQL %d or later, cluster is running version %d",
+					publicationRowFilterMinMajorVersion, majorVersion)))
+		}
+		if len(object.Table.Columns) > 0 {
+			result = append(result, field.Invalid(
+				tablePath.Child("columns"),
+				object.Table.Columns,
+				fmt.Sprintf("column lists require PostgreSQL %d or later, cluster is running version %d",
+					publicationRowFilterMinMajorVersion, majorVersion)))
+		}
+	}
+
+	return result
+}
+
+// getClusterMajorVersion gets the PostgreSQL major version of the publisher
+// cluster, preferring the one of the image running on the data directory.
+// It returns zero when the cluster does not exist yet.
+func (v *PublicationCustomValidator) getClusterMajorVersion(
+	ctx context.Context,
+	publication *apiv1.Publication,
+) (int, error) {
+	if v.client == nil {
+		return 0, nil
+	}
+
+	var cluster apiv1.Cluster
+	if err := v.client.Get(ctx, client.ObjectKey{
+		Namespace: publication.Namespace,
+		Name:      publication.Spec.ClusterRef.Name,
+	}, &cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("while getting the publisher cluster: %w", err)
+	}
+
+	if cluster.Status.PGDataImageInfo != nil {
+		return cluster.Status.PGDataImageInfo.MajorVersion, nil
+	}
+
+	majorVersion, err := cluster.GetPostgresqlMajorVersion()
+	if err != nil {
+		// The cluster webhook reports the invalid image
+		return 0, nil
+	}
+	return majorVersion, nil
+}
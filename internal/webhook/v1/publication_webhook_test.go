@@ -0,0 +1,162 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Publication validation", func() {
+	var v *PublicationCustomValidator
+
+	newPublication := func(table apiv1.PublicationTargetTable) *apiv1.Publication {
+		return &apiv1.Publication{
+			ObjectMeta: metav1.ObjectMeta{Name: "pub", Namespace: "default"},
+			Spec: apiv1.PublicationSpec{
+				ClusterRef: corev1.LocalObjectReference{Name: "cluster-example"},
+				Name:       "pub",
+				DBName:     "app",
+				Target: apiv1.PublicationTarget{
+					Objects: []apiv1.PublicationTargetObject{{Table: &table}},
+				},
+			},
+		}
+	}
+
+	newCluster := func(majorVersion int) *apiv1.Cluster {
+		return &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Status: apiv1.ClusterStatus{
+				PGDataImageInfo: &apiv1.ImageInfo{MajorVersion: majorVersion},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		v = &PublicationCustomValidator{}
+	})
+
+	It("accepts a publication using the typed publish options", func() {
+		publication := newPublication(apiv1.PublicationTargetTable{Name: "orders"})
+		publication.Spec.Publish = []apiv1.PublicationOperation{apiv1.PublicationOperationInsert}
+		publication.Spec.PublishViaPartitionRoot = ptr.To(true)
+		publication.Spec.Parameters = map[string]string{"other": "value"}
+
+		Expect(v.validate(publication)).To(BeEmpty())
+	})
+
+	It("rejects publish options set both as typed fields and parameters", func() {
+		publication := newPublication(apiv1.PublicationTargetTable{Name: "orders"})
+		publication.Spec.Publish = []apiv1.PublicationOperation{apiv1.PublicationOperationInsert}
+		publication.Spec.PublishViaPartitionRoot = ptr.To(true)
+		publication.Spec.Parameters = map[string]string{
+			"publish":                    "insert",
+			"publish_via_partition_root": "true",
+		}
+
+		errs := v.validate(publication)
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Field).To(Equal("spec.parameters[publish]"))
+		Expect(errs[1].Field).To(Equal("spec.parameters[publish_via_partition_root]"))
+	})
+
+	It("rejects row filters and column lists before PostgreSQL 15", func() {
+		publication := newPublication(apiv1.PublicationTargetTable{
+			Name:    "orders",
+			Columns: []string{"id"},
+			Where:   "id > 10",
+		})
+
+		errs := v.validateMajorVersionFeatures(publication, 14)
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Field).To(Equal("spec.target.objects[0].table.where"))
+		Expect(errs[1].Field).To(Equal("spec.target.objects[0].table.columns"))
+
+		Expect(v.validateMajorVersionFeatures(publication, 15)).To(BeEmpty())
+		Expect(v.validateMajorVersionFeatures(publication, 0)).To(BeEmpty())
+	})
+
+	It("validates row filters against the major version of the cluster", func(ctx SpecContext) {
+		publication := newPublication(apiv1.PublicationTargetTable{Name: "orders", Where: "id > 10"})
+
+		v.client = fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(newCluster(14)).Build()
+		_, err := v.ValidateCreate(ctx, publication)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("row filters require PostgreSQL 15"))
+
+		v.client = fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(newCluster(17)).Build()
+		_, err = v.ValidateCreate(ctx, publication)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("skips the major version checks when the cluster does not exist", func(ctx SpecContext) {
+		publication := newPublication(apiv1.PublicationTargetTable{Name: "orders", Where: "id > 10"})
+
+		v.client = fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).Build()
+		_, err := v.ValidateUpdate(ctx, publication, publication)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("rejects row filters escaping the WHERE clause", func() {
+		publication := newPublication(apiv1.PublicationTargetTable{
+			Name:  "orders",
+			Where: "true); DROP TABLE orders; SELECT (1",
+		})
+
+		errs := v.validate(publication)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.target.objects[0].table.where"))
+		Expect(errs[0].Detail).To(Equal("unbalanced parentheses"))
+	})
+
+	DescribeTable("validates the row filters",
+		func(where string, expectedError string) {
+			err := validateRowFilter(where)
+			if expectedError == "" {
+				Expect(err).ToNot(HaveOccurred())
+				return
+			}
+			Expect(err).To(MatchError(expectedError))
+		},
+		Entry("simple expression", "id > 10", ""),
+		Entry("nested parentheses", "(id > 10 AND (status = 'open' OR total < 5))", ""),
+		Entry("special characters in literals", "note = 'a; b -- c /* d ) $e'", ""),
+		Entry("escaped quotes in literals", "note = 'it''s' AND \"odd)name\" IS NULL", ""),
+		Entry("statement separator", "id > 10; DROP TABLE orders", "statement separators are not allowed"),
+		Entry("line comment", "id > 10 --", "comments are not allowed"),
+		Entry("block comment", "id > 10 /* x */", "comments are not allowed"),
+		Entry("closing parenthesis", "true) OR (false", "unbalanced parentheses"),
+		Entry("opening parenthesis", "(id > 10", "unbalanced parentheses"),
+		Entry("unterminated literal", "note = 'open", "unterminated quoted string or identifier"),
+		Entry("dollar quoting", "note = $$')$$", "dollar quoting and positional parameters are not allowed"),
+		Entry("escape string", "note = E'\\'') OR (true'", "backslashes are not allowed"),
+	)
+})
@@ -21,6 +21,7 @@ package v1
 
 import (
 	"context"
+	"slices"
 	"strings"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
@@ -159,7 +160,13 @@ func (v *ScheduledBackupCustomValidator) validate(r *apiv1.ScheduledBackup) (adm
 		))
 	}
 
-	if r.Spec.Method == apiv1.BackupMethodBarmanObjectStore && r.Spec.Online != nil {
+	isOnlineSupported := !slices.Contains([]apiv1.BackupMethod{
+		apiv1.BackupMethodBarmanObjectStore,
+		apiv1.BackupMethodPgBaseBackup,
+		apiv1.BackupMethodLogical,
+	}, r.Spec.Method)
+
+	if !isOnlineSupported && r.Spec.Online != nil {
 		result = append(result, field.Invalid(
 			field.NewPath("spec", "online"),
 			r.Spec.Online,
@@ -167,7 +174,7 @@ func (v *ScheduledBackupCustomValidator) validate(r *apiv1.ScheduledBackup) (adm
 		))
 	}
 
-	if r.Spec.Method == apiv1.BackupMethodBarmanObjectStore && r.Spec.OnlineConfiguration != nil {
+	if !isOnlineSupported && r.Spec.OnlineConfiguration != nil {
 		result = append(result, field.Invalid(
 			field.NewPath("spec", "onlineConfiguration"),
 			r.Spec.OnlineConfiguration,
@@ -175,5 +182,35 @@ func (v *ScheduledBackupCustomValidator) validate(r *apiv1.ScheduledBackup) (adm
 		))
 	}
 
+	if r.Spec.Method != apiv1.BackupMethodLogical && len(r.Spec.Databases) > 0 {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "databases"),
+			r.Spec.Databases,
+			"Databases parameter can be specified only if the method is logical",
+		))
+	}
+
+	if r.Spec.Method == apiv1.BackupMethodLogical && r.Spec.PluginConfiguration != nil {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "pluginConfiguration"),
+			r.Spec.PluginConfiguration,
+			"the logical backup method only supports the volume configured in the cluster as destination",
+		))
+	}
+
+	if r.Spec.Method != apiv1.BackupMethodPgBaseBackup && r.Spec.Type != "" {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "type"),
+			r.Spec.Type,
+			"Type parameter can be specified only if the method is pgBaseBackup",
+		))
+	}
+
+	result = append(result, validateBackupVerification(
+		field.NewPath("spec", "verification"),
+		r.Spec.Method,
+		r.Spec.Verification,
+	)...)
+
 	return warnings, result
 }
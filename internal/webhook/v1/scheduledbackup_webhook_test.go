@@ -126,4 +126,32 @@ var _ = Describe("Validate schedule", func() {
 		Expect(result).To(HaveLen(1))
 		Expect(result[0].Field).To(Equal("spec.onlineConfiguration"))
 	})
+
+	It("complains if a plugin destination is set on a logical backup", func() {
+		scheduledBackup := &apiv1.ScheduledBackup{
+			Spec: apiv1.ScheduledBackupSpec{
+				Method:              apiv1.BackupMethodLogical,
+				PluginConfiguration: &apiv1.BackupPluginConfiguration{Name: "plugin-example"},
+				Schedule:            "* * * * * *",
+			},
+		}
+		warnings, result := v.validate(scheduledBackup)
+		Expect(warnings).To(BeEmpty())
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.pluginConfiguration"))
+	})
+
+	It("complains if verification is set on a plugin backup", func() {
+		scheduledBackup := &apiv1.ScheduledBackup{
+			Spec: apiv1.ScheduledBackupSpec{
+				Method:       apiv1.BackupMethodPlugin,
+				Verification: &apiv1.BackupVerificationConfiguration{},
+				Schedule:     "* * * * * *",
+			},
+		}
+		warnings, result := v.validate(scheduledBackup)
+		Expect(warnings).To(BeEmpty())
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.verification"))
+	})
 })
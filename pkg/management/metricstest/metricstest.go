@@ -0,0 +1,108 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package metricstest contains the testing utils for the Prometheus
+// metrics exported by the operator and the instance manager
+package metricstest
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Value collects the only metric exposed by the passed collector and
+// returns its value. It panics if the collector doesn't expose exactly one
+// gauge, counter or untyped metric.
+func Value(collector prometheus.Collector) float64 {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		collector.Collect(ch)
+		close(ch)
+	}()
+
+	var metrics []prometheus.Metric
+	for metric := range ch {
+		metrics = append(metrics, metric)
+	}
+	if len(metrics) != 1 {
+		panic(fmt.Sprintf("collected %d metrics instead of exactly 1", len(metrics)))
+	}
+
+	var m dto.Metric
+	if err := metrics[0].Write(&m); err != nil {
+		panic(fmt.Sprintf("while writing the metric: %v", err))
+	}
+
+	switch {
+	case m.Gauge != nil:
+		return m.GetGauge().GetValue()
+	case m.Counter != nil:
+		return m.GetCounter().GetValue()
+	case m.Untyped != nil:
+		return m.GetUntyped().GetValue()
+	default:
+		panic("collected a metric that is not a gauge, a counter or untyped")
+	}
+}
+
+// Gather registers the passed collector in a new registry and gathers its
+// metric families, indexed by name
+func Gather(collector prometheus.Collector) (map[string]*dto.MetricFamily, error) {
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(collector); err != nil {
+		return nil, err
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*dto.MetricFamily, len(families))
+	for _, family := range families {
+		result[family.GetName()] = family
+	}
+	return result, nil
+}
+
+// Count returns the number of metrics exposed by the passed collector.
+// If any metric name is passed, only the metrics having one of those
+// names are counted. It panics if the metrics cannot be gathered.
+func Count(collector prometheus.Collector, names ...string) int {
+	families, err := Gather(collector)
+	if err != nil {
+		panic(fmt.Sprintf("while gathering the metrics: %v", err))
+	}
+
+	if len(names) == 0 {
+		count := 0
+		for _, family := range families {
+			count += len(family.GetMetric())
+		}
+		return count
+	}
+
+	count := 0
+	for _, name := range names {
+		count += len(families[name].GetMetric())
+	}
+	return count
+}
@@ -0,0 +1,158 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package otlp
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	logsv1 "go.opentelemetry.io/proto/otlp/logs/v1"
+	metricsv1 "go.opentelemetry.io/proto/otlp/metrics/v1"
+	tracev1 "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+
+	. "github.com/onsi/gomega"
+)
+
+// fakeCollector is a local stand-in for an OpenTelemetry collector,
+// storing everything it receives via OTLP/gRPC
+type fakeCollector struct {
+	server   *grpc.Server
+	endpoint string
+
+	mu      sync.Mutex
+	logs    []*logsv1.ResourceLogs
+	metrics []*metricsv1.ResourceMetrics
+	spans   []*tracev1.ResourceSpans
+}
+
+func startFakeCollector() *fakeCollector {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).ToNot(HaveOccurred())
+
+	collector := &fakeCollector{
+		server:   grpc.NewServer(),
+		endpoint: listener.Addr().String(),
+	}
+	collectorlogs.RegisterLogsServiceServer(collector.server, logsService{collector: collector})
+	collectormetrics.RegisterMetricsServiceServer(collector.server, metricsService{collector: collector})
+	collectortrace.RegisterTraceServiceServer(collector.server, traceService{collector: collector})
+	go func() {
+		_ = collector.server.Serve(listener)
+	}()
+
+	return collector
+}
+
+func (c *fakeCollector) stop() {
+	c.server.Stop()
+}
+
+func (c *fakeCollector) getLogRecords() []*logsv1.LogRecord {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var result []*logsv1.LogRecord
+	for _, resourceLogs := range c.logs {
+		for _, scopeLogs := range resourceLogs.GetScopeLogs() {
+			result = append(result, scopeLogs.GetLogRecords()...)
+		}
+	}
+	return result
+}
+
+func (c *fakeCollector) getMetricNames() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var result []string
+	for _, resourceMetrics := range c.metrics {
+		for _, scopeMetrics := range resourceMetrics.GetScopeMetrics() {
+			for _, metric := range scopeMetrics.GetMetrics() {
+				result = append(result, metric.GetName())
+			}
+		}
+	}
+	return result
+}
+
+func (c *fakeCollector) getSpans() []*tracev1.Span {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var result []*tracev1.Span
+	for _, resourceSpans := range c.spans {
+		for _, scopeSpans := range resourceSpans.GetScopeSpans() {
+			result = append(result, scopeSpans.GetSpans()...)
+		}
+	}
+	return result
+}
+
+// The OTLP services share the Export method name, so each one
+// is implemented by a different type
+type logsService struct {
+	collectorlogs.UnimplementedLogsServiceServer
+	collector *fakeCollector
+}
+
+func (s logsService) Export(
+	_ context.Context,
+	req *collectorlogs.ExportLogsServiceRequest,
+) (*collectorlogs.ExportLogsServiceResponse, error) {
+	s.collector.mu.Lock()
+	defer s.collector.mu.Unlock()
+	s.collector.logs = append(s.collector.logs, req.GetResourceLogs()...)
+	return &collectorlogs.ExportLogsServiceResponse{}, nil
+}
+
+type metricsService struct {
+	collectormetrics.UnimplementedMetricsServiceServer
+	collector *fakeCollector
+}
+
+func (s metricsService) Export(
+	_ context.Context,
+	req *collectormetrics.ExportMetricsServiceRequest,
+) (*collectormetrics.ExportMetricsServiceResponse, error) {
+	s.collector.mu.Lock()
+	defer s.collector.mu.Unlock()
+	s.collector.metrics = append(s.collector.metrics, req.GetResourceMetrics()...)
+	return &collectormetrics.ExportMetricsServiceResponse{}, nil
+}
+
+type traceService struct {
+	collectortrace.UnimplementedTraceServiceServer
+	collector *fakeCollector
+}
+
+func (s traceService) Export(
+	_ context.Context,
+	req *collectortrace.ExportTraceServiceRequest,
+) (*collectortrace.ExportTraceServiceResponse, error) {
+	s.collector.mu.Lock()
+	defer s.collector.mu.Unlock()
+	s.collector.spans = append(s.collector.spans, req.GetResourceSpans()...)
+	return &collectortrace.ExportTraceServiceResponse{}, nil
+}
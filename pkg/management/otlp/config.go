@@ -0,0 +1,117 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package otlp
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+const (
+	// ServiceName is the name of the service reported in the telemetry data
+	ServiceName = "cloudnative-pg"
+
+	// instrumentationScope is the name of the instrumentation scope used
+	// for the telemetry data not coming from PostgreSQL
+	instrumentationScope = "github.com/cloudnative-pg/cloudnative-pg"
+)
+
+// ResourceInfo identifies the entity producing the telemetry data
+type ResourceInfo struct {
+	// Component is the CloudNativePG component, i.e. "instance" or "operator"
+	Component string
+
+	// Namespace is the namespace of the Cluster
+	Namespace string
+
+	// ClusterName is the name of the Cluster
+	ClusterName string
+
+	// PodName is the name of the Pod, if any
+	PodName string
+}
+
+// newResource creates the OpenTelemetry resource describing the
+// entity producing the telemetry data
+func newResource(info ResourceInfo) *resource.Resource {
+	attributes := []attribute.KeyValue{
+		attribute.String("service.name", ServiceName),
+	}
+	for key, value := range map[string]string{
+		"service.namespace":  info.Namespace,
+		"k8s.namespace.name": info.Namespace,
+		"k8s.pod.name":       info.PodName,
+		"cnpg.cluster.name":  info.ClusterName,
+		"cnpg.component":     info.Component,
+	} {
+		if value != "" {
+			attributes = append(attributes, attribute.String(key, value))
+		}
+	}
+
+	return resource.NewSchemaless(attributes...)
+}
+
+// getConfigurationKey returns a string identifying the passed
+// configuration, used to detect when the exporters need to be recreated
+func getConfigurationKey(config *apiv1.OTLPConfiguration) string {
+	if config == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%s|%t|%t|%t|%t|%s",
+		config.Endpoint,
+		config.Insecure,
+		config.IsMetricsEnabled(),
+		config.IsLogsEnabled(),
+		config.IsTracesEnabled(),
+		config.GetExportInterval())
+}
+
+func metricExporterOptions(config *apiv1.OTLPConfiguration) []otlpmetricgrpc.Option {
+	options := []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpoint(config.Endpoint)}
+	if config.Insecure {
+		options = append(options, otlpmetricgrpc.WithInsecure())
+	}
+	return options
+}
+
+func logExporterOptions(config *apiv1.OTLPConfiguration) []otlploggrpc.Option {
+	options := []otlploggrpc.Option{otlploggrpc.WithEndpoint(config.Endpoint)}
+	if config.Insecure {
+		options = append(options, otlploggrpc.WithInsecure())
+	}
+	return options
+}
+
+func traceExporterOptions(config *apiv1.OTLPConfiguration) []otlptracegrpc.Option {
+	options := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(config.Endpoint)}
+	if config.Insecure {
+		options = append(options, otlptracegrpc.WithInsecure())
+	}
+	return options
+}
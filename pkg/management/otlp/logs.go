@@ -0,0 +1,186 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package otlp
+
+import (
+	"strings"
+	"time"
+
+	otellog "go.opentelemetry.io/otel/log"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/logpipe"
+)
+
+// postgresLogTimeLayout is the layout of the log_time field
+// of the PostgreSQL CSV log
+const postgresLogTimeLayout = "2006-01-02 15:04:05.999 MST"
+
+// postgresSeverities maps the PostgreSQL message severity levels to
+// the OpenTelemetry ones.
+// See https://www.postgresql.org/docs/current/runtime-config-logging.html#RUNTIME-CONFIG-SEVERITY-LEVELS
+var postgresSeverities = map[string]otellog.Severity{
+	"DEBUG5":  otellog.SeverityTrace1,
+	"DEBUG4":  otellog.SeverityTrace2,
+	"DEBUG3":  otellog.SeverityDebug1,
+	"DEBUG2":  otellog.SeverityDebug2,
+	"DEBUG1":  otellog.SeverityDebug3,
+	"INFO":    otellog.SeverityInfo1,
+	"NOTICE":  otellog.SeverityInfo2,
+	"LOG":     otellog.SeverityInfo3,
+	"WARNING": otellog.SeverityWarn1,
+	"ERROR":   otellog.SeverityError1,
+	"FATAL":   otellog.SeverityFatal1,
+	"PANIC":   otellog.SeverityFatal4,
+}
+
+// getSeverity maps a PostgreSQL severity level to the OpenTelemetry one
+func getSeverity(errorSeverity string) otellog.Severity {
+	if severity, ok := postgresSeverities[strings.ToUpper(errorSeverity)]; ok {
+		return severity
+	}
+
+	return otellog.SeverityUndefined
+}
+
+// newLogRecord converts a record parsed from the PostgreSQL CSV log
+// into an OpenTelemetry log record. It returns false if the record
+// is not supported.
+func newLogRecord(record logpipe.NamedRecord) (otellog.Record, bool) {
+	var (
+		result          otellog.Record
+		loggingRecord   *logpipe.LoggingRecord
+		auditRecord     *logpipe.PgAuditRecord
+		durationRecord  *logpipe.StatementDuration
+		autoExplainPlan *logpipe.AutoExplainPlan
+	)
+
+	switch typedRecord := record.(type) {
+	case *logpipe.LoggingRecord:
+		loggingRecord = typedRecord
+	case *logpipe.PgAuditLoggingDecorator:
+		loggingRecord = typedRecord.LoggingRecord
+		auditRecord = typedRecord.Audit
+	case *logpipe.StatementDurationRecord:
+		loggingRecord = typedRecord.LoggingRecord
+		durationRecord = typedRecord.Duration
+	case *logpipe.AutoExplainRecord:
+		loggingRecord = typedRecord.LoggingRecord
+		autoExplainPlan = typedRecord.Explain
+	default:
+		return result, false
+	}
+	if loggingRecord == nil {
+		return result, false
+	}
+
+	result.SetObservedTimestamp(time.Now())
+	if timestamp, err := time.Parse(postgresLogTimeLayout, loggingRecord.LogTime); err == nil {
+		result.SetTimestamp(timestamp)
+	}
+	result.SetSeverity(getSeverity(loggingRecord.ErrorSeverity))
+	result.SetSeverityText(loggingRecord.ErrorSeverity)
+	result.SetEventName(record.GetName())
+
+	body := loggingRecord.Message
+	switch {
+	case auditRecord != nil && body == "":
+		body = auditRecord.Statement
+	case durationRecord != nil:
+		body = durationRecord.Statement
+	case autoExplainPlan != nil:
+		body = autoExplainPlan.QueryText
+	}
+	result.SetBody(otellog.StringValue(body))
+
+	result.AddAttributes(stringAttributes("postgresql.", []string{
+		"user_name", loggingRecord.Username,
+		"database_name", loggingRecord.DatabaseName,
+		"process_id", loggingRecord.ProcessID,
+		"connection_from", loggingRecord.ConnectionFrom,
+		"session_id", loggingRecord.SessionID,
+		"session_line_num", loggingRecord.SessionLineNum,
+		"command_tag", loggingRecord.CommandTag,
+		"session_start_time", loggingRecord.SessionStartTime,
+		"virtual_transaction_id", loggingRecord.VirtualTransactionID,
+		"transaction_id", loggingRecord.TransactionID,
+		"sql_state_code", loggingRecord.SQLStateCode,
+		"detail", loggingRecord.Detail,
+		"hint", loggingRecord.Hint,
+		"internal_query", loggingRecord.InternalQuery,
+		"internal_query_pos", loggingRecord.InternalQueryPos,
+		"context", loggingRecord.Context,
+		"query", loggingRecord.Query,
+		"query_pos", loggingRecord.QueryPos,
+		"location", loggingRecord.Location,
+		"application_name", loggingRecord.ApplicationName,
+		"backend_type", loggingRecord.BackendType,
+		"leader_pid", loggingRecord.LeaderPid,
+		"query_id", loggingRecord.QueryID,
+	})...)
+
+	if auditRecord != nil {
+		result.AddAttributes(stringAttributes("pgaudit.", []string{
+			"audit_type", auditRecord.AuditType,
+			"statement_id", auditRecord.StatementID,
+			"substatement_id", auditRecord.SubstatementID,
+			"class", auditRecord.Class,
+			"command", auditRecord.Command,
+			"object_type", auditRecord.ObjectType,
+			"object_name", auditRecord.ObjectName,
+			"statement", auditRecord.Statement,
+			"parameter", auditRecord.Parameter,
+			"rows", auditRecord.Rows,
+		})...)
+	}
+
+	if durationRecord != nil {
+		result.AddAttributes(otellog.Float64("postgresql.duration_ms", durationRecord.DurationMs))
+		result.AddAttributes(stringAttributes("postgresql.", []string{
+			"statement_kind", durationRecord.Kind,
+		})...)
+	}
+
+	if autoExplainPlan != nil {
+		plan := autoExplainPlan.PlanText
+		if len(autoExplainPlan.Plan) > 0 {
+			plan = string(autoExplainPlan.Plan)
+		}
+		result.AddAttributes(otellog.Float64("postgresql.duration_ms", autoExplainPlan.DurationMs))
+		result.AddAttributes(stringAttributes("auto_explain.", []string{
+			"plan", plan,
+		})...)
+	}
+
+	return result, true
+}
+
+// stringAttributes builds the log attributes from a list of
+// key/value pairs, skipping the empty values
+func stringAttributes(prefix string, pairs []string) []otellog.KeyValue {
+	attributes := make([]otellog.KeyValue, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] == "" {
+			continue
+		}
+		attributes = append(attributes, otellog.String(prefix+pairs[i], pairs[i+1]))
+	}
+
+	return attributes
+}
@@ -0,0 +1,130 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package otlp
+
+import (
+	otellog "go.opentelemetry.io/otel/log"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/logpipe"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PostgreSQL log records conversion", func() {
+	DescribeTable("maps the PostgreSQL severities",
+		func(pgSeverity string, expected otellog.Severity) {
+			Expect(getSeverity(pgSeverity)).To(Equal(expected))
+		},
+		Entry("DEBUG5", "DEBUG5", otellog.SeverityTrace1),
+		Entry("DEBUG1", "DEBUG1", otellog.SeverityDebug3),
+		Entry("LOG", "LOG", otellog.SeverityInfo3),
+		Entry("NOTICE", "NOTICE", otellog.SeverityInfo2),
+		Entry("WARNING", "WARNING", otellog.SeverityWarn1),
+		Entry("ERROR", "ERROR", otellog.SeverityError1),
+		Entry("FATAL", "FATAL", otellog.SeverityFatal1),
+		Entry("PANIC", "PANIC", otellog.SeverityFatal4),
+		Entry("unknown", "SOMETHING", otellog.SeverityUndefined),
+	)
+
+	getAttributes := func(record otellog.Record) map[string]string {
+		result := make(map[string]string)
+		record.WalkAttributes(func(kv otellog.KeyValue) bool {
+			result[kv.Key] = kv.Value.AsString()
+			return true
+		})
+		return result
+	}
+
+	It("converts the logging collector records", func() {
+		record, ok := newLogRecord(&logpipe.LoggingRecord{
+			LogTime:       "2024-01-02 10:00:00.123 UTC",
+			ErrorSeverity: "WARNING",
+			Message:       "this is a warning",
+			DatabaseName:  "app",
+			Username:      "app",
+		})
+		Expect(ok).To(BeTrue())
+		Expect(record.Severity()).To(Equal(otellog.SeverityWarn1))
+		Expect(record.SeverityText()).To(Equal("WARNING"))
+		Expect(record.Body().AsString()).To(Equal("this is a warning"))
+		Expect(record.EventName()).To(Equal(logpipe.LoggingCollectorRecordName))
+		Expect(record.Timestamp().UTC().Format("15:04:05.000")).To(Equal("10:00:00.123"))
+		Expect(getAttributes(record)).To(Equal(map[string]string{
+			"postgresql.database_name": "app",
+			"postgresql.user_name":     "app",
+		}))
+	})
+
+	It("converts the pgaudit records", func() {
+		decorator := logpipe.NewPgAuditLoggingDecorator()
+		parsed := decorator.FromCSV([]string{
+			"2024-01-02 10:00:00.000 UTC", "app", "app", "42", "", "", "", "", "", "", "",
+			"LOG", "00000", "AUDIT: SESSION,1,1,READ,SELECT,,,SELECT 1,<not logged>",
+			"", "", "", "", "", "", "", "", "", "client backend",
+		})
+
+		record, ok := newLogRecord(parsed)
+		Expect(ok).To(BeTrue())
+		Expect(record.Severity()).To(Equal(otellog.SeverityInfo3))
+		Expect(record.EventName()).To(Equal(logpipe.PgAuditRecordName))
+		Expect(record.Body().AsString()).To(Equal("SELECT 1"))
+		Expect(getAttributes(record)).To(HaveKeyWithValue("pgaudit.class", "READ"))
+		Expect(getAttributes(record)).To(HaveKeyWithValue("pgaudit.command", "SELECT"))
+	})
+	It("converts the statement duration records", func() {
+		record, ok := newLogRecord(&logpipe.StatementDurationRecord{
+			LoggingRecord: &logpipe.LoggingRecord{ErrorSeverity: "LOG", DatabaseName: "app"},
+			Duration: &logpipe.StatementDuration{
+				DurationMs: 1500.5,
+				Kind:       "statement",
+				Statement:  "SELECT pg_sleep(1.5)",
+			},
+		})
+		Expect(ok).To(BeTrue())
+		Expect(record.EventName()).To(Equal(logpipe.StatementDurationRecordName))
+		Expect(record.Body().AsString()).To(Equal("SELECT pg_sleep(1.5)"))
+		Expect(getAttributes(record)).To(HaveKeyWithValue("postgresql.statement_kind", "statement"))
+
+		var durationMs float64
+		record.WalkAttributes(func(kv otellog.KeyValue) bool {
+			if kv.Key == "postgresql.duration_ms" {
+				durationMs = kv.Value.AsFloat64()
+			}
+			return true
+		})
+		Expect(durationMs).To(Equal(1500.5))
+	})
+
+	It("converts the auto_explain records", func() {
+		record, ok := newLogRecord(&logpipe.AutoExplainRecord{
+			LoggingRecord: &logpipe.LoggingRecord{ErrorSeverity: "LOG"},
+			Explain: &logpipe.AutoExplainPlan{
+				DurationMs: 3,
+				QueryText:  "SELECT 1",
+				Plan:       []byte(`{"Query Text": "SELECT 1"}`),
+			},
+		})
+		Expect(ok).To(BeTrue())
+		Expect(record.EventName()).To(Equal(logpipe.AutoExplainRecordName))
+		Expect(record.Body().AsString()).To(Equal("SELECT 1"))
+		Expect(getAttributes(record)).To(HaveKeyWithValue("auto_explain.plan", `{"Query Text": "SELECT 1"}`))
+	})
+})
@@ -0,0 +1,272 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package otlp
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// prometheusProducer is an OpenTelemetry metric producer exporting the
+// metrics gathered from a set of Prometheus collectors. This allows the
+// OTLP pipeline to share the same collectors used by the metrics endpoint.
+type prometheusProducer struct {
+	gatherer  prometheus.Gatherer
+	startTime time.Time
+}
+
+func newPrometheusProducer(gatherer prometheus.Gatherer) *prometheusProducer {
+	return &prometheusProducer{
+		gatherer:  gatherer,
+		startTime: time.Now(),
+	}
+}
+
+// Produce implements the metric.Producer interface
+func (p *prometheusProducer) Produce(context.Context) ([]metricdata.ScopeMetrics, error) {
+	families, err := p.gatherer.Gather()
+	// Gather may return a partial result together with an error,
+	// and we export whatever we have been able to collect
+	if len(families) == 0 {
+		return nil, err
+	}
+
+	now := time.Now()
+	metrics := make([]metricdata.Metrics, 0, len(families))
+	for _, family := range families {
+		if data := p.convertFamily(family, now); data != nil {
+			metrics = append(metrics, metricdata.Metrics{
+				Name:        family.GetName(),
+				Description: family.GetHelp(),
+				Data:        data,
+			})
+		}
+	}
+
+	return []metricdata.ScopeMetrics{
+		{
+			Scope:   instrumentation.Scope{Name: instrumentationScope},
+			Metrics: metrics,
+		},
+	}, err
+}
+
+// convertFamily converts a Prometheus metric family to the OpenTelemetry
+// data model, returning nil for the unsupported types
+func (p *prometheusProducer) convertFamily(family *dto.MetricFamily, now time.Time) metricdata.Aggregation {
+	switch family.GetType() {
+	case dto.MetricType_GAUGE, dto.MetricType_UNTYPED:
+		points := make([]metricdata.DataPoint[float64], 0, len(family.GetMetric()))
+		for _, metric := range family.GetMetric() {
+			value := metric.GetGauge().GetValue()
+			if family.GetType() == dto.MetricType_UNTYPED {
+				value = metric.GetUntyped().GetValue()
+			}
+			points = append(points, metricdata.DataPoint[float64]{
+				Attributes: convertLabels(metric.GetLabel()),
+				Time:       now,
+				Value:      value,
+			})
+		}
+		return metricdata.Gauge[float64]{DataPoints: points}
+
+	case dto.MetricType_COUNTER:
+		points := make([]metricdata.DataPoint[float64], 0, len(family.GetMetric()))
+		for _, metric := range family.GetMetric() {
+			points = append(points, metricdata.DataPoint[float64]{
+				Attributes: convertLabels(metric.GetLabel()),
+				StartTime:  p.startTime,
+				Time:       now,
+				Value:      metric.GetCounter().GetValue(),
+			})
+		}
+		return metricdata.Sum[float64]{
+			DataPoints:  points,
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+		}
+
+	case dto.MetricType_HISTOGRAM:
+		if isNativeHistogram(family) {
+			points := make([]metricdata.ExponentialHistogramDataPoint[float64], 0, len(family.GetMetric()))
+			for _, metric := range family.GetMetric() {
+				points = append(points, p.convertNativeHistogram(metric, now))
+			}
+			return metricdata.ExponentialHistogram[float64]{
+				DataPoints:  points,
+				Temporality: metricdata.CumulativeTemporality,
+			}
+		}
+
+		points := make([]metricdata.HistogramDataPoint[float64], 0, len(family.GetMetric()))
+		for _, metric := range family.GetMetric() {
+			points = append(points, p.convertHistogram(metric, now))
+		}
+		return metricdata.Histogram[float64]{
+			DataPoints:  points,
+			Temporality: metricdata.CumulativeTemporality,
+		}
+
+	case dto.MetricType_SUMMARY:
+		points := make([]metricdata.SummaryDataPoint, 0, len(family.GetMetric()))
+		for _, metric := range family.GetMetric() {
+			summary := metric.GetSummary()
+			quantiles := make([]metricdata.QuantileValue, 0, len(summary.GetQuantile()))
+			for _, quantile := range summary.GetQuantile() {
+				quantiles = append(quantiles, metricdata.QuantileValue{
+					Quantile: quantile.GetQuantile(),
+					Value:    quantile.GetValue(),
+				})
+			}
+			points = append(points, metricdata.SummaryDataPoint{
+				Attributes:     convertLabels(metric.GetLabel()),
+				StartTime:      p.startTime,
+				Time:           now,
+				Count:          summary.GetSampleCount(),
+				Sum:            summary.GetSampleSum(),
+				QuantileValues: quantiles,
+			})
+		}
+		return metricdata.Summary{DataPoints: points}
+
+	default:
+		return nil
+	}
+}
+
+// convertHistogram converts a Prometheus histogram, whose buckets are
+// cumulative, to an OpenTelemetry explicit bucket histogram
+func (p *prometheusProducer) convertHistogram(
+	metric *dto.Metric,
+	now time.Time,
+) metricdata.HistogramDataPoint[float64] {
+	histogram := metric.GetHistogram()
+	bounds := make([]float64, 0, len(histogram.GetBucket()))
+	counts := make([]uint64, 0, len(histogram.GetBucket())+1)
+
+	var previous uint64
+	for _, bucket := range histogram.GetBucket() {
+		if math.IsInf(bucket.GetUpperBound(), +1) {
+			continue
+		}
+		bounds = append(bounds, bucket.GetUpperBound())
+		counts = append(counts, bucket.GetCumulativeCount()-previous)
+		previous = bucket.GetCumulativeCount()
+	}
+	counts = append(counts, histogram.GetSampleCount()-previous)
+
+	return metricdata.HistogramDataPoint[float64]{
+		Attributes:   convertLabels(metric.GetLabel()),
+		StartTime:    p.startTime,
+		Time:         now,
+		Count:        histogram.GetSampleCount(),
+		Sum:          histogram.GetSampleSum(),
+		Bounds:       bounds,
+		BucketCounts: counts,
+	}
+}
+
+// isNativeHistogram checks if the histograms of a family only have
+// native buckets, which are better represented by OpenTelemetry
+// exponential histograms
+func isNativeHistogram(family *dto.MetricFamily) bool {
+	if len(family.GetMetric()) == 0 {
+		return false
+	}
+
+	histogram := family.GetMetric()[0].GetHistogram()
+	return len(histogram.GetBucket()) == 0 &&
+		(histogram.GetZeroThreshold() > 0 ||
+			len(histogram.GetPositiveSpan()) > 0 || len(histogram.GetNegativeSpan()) > 0)
+}
+
+// convertNativeHistogram converts a Prometheus native histogram to an
+// OpenTelemetry exponential histogram. The two share the same bucket
+// boundaries, as the Prometheus schema corresponds to the OpenTelemetry
+// scale.
+func (p *prometheusProducer) convertNativeHistogram(
+	metric *dto.Metric,
+	now time.Time,
+) metricdata.ExponentialHistogramDataPoint[float64] {
+	histogram := metric.GetHistogram()
+	return metricdata.ExponentialHistogramDataPoint[float64]{
+		Attributes:     convertLabels(metric.GetLabel()),
+		StartTime:      p.startTime,
+		Time:           now,
+		Count:          histogram.GetSampleCount(),
+		Sum:            histogram.GetSampleSum(),
+		Scale:          histogram.GetSchema(),
+		ZeroCount:      histogram.GetZeroCount(),
+		ZeroThreshold:  histogram.GetZeroThreshold(),
+		PositiveBucket: convertNativeBuckets(histogram.GetPositiveSpan(), histogram.GetPositiveDelta()),
+		NegativeBucket: convertNativeBuckets(histogram.GetNegativeSpan(), histogram.GetNegativeDelta()),
+	}
+}
+
+// convertNativeBuckets decodes the spans and the delta-encoded counts of
+// the Prometheus native buckets. A Prometheus bucket with index i covers
+// the (base^(i-1), base^i] range, while the OpenTelemetry one covers
+// (base^i, base^(i+1)], hence the offset is shifted by one.
+func convertNativeBuckets(spans []*dto.BucketSpan, deltas []int64) metricdata.ExponentialBucket {
+	if len(spans) == 0 {
+		return metricdata.ExponentialBucket{}
+	}
+
+	var (
+		counts  []uint64
+		current int64
+	)
+	for i, span := range spans {
+		if i > 0 {
+			for range span.GetOffset() {
+				counts = append(counts, 0)
+			}
+		}
+		for range span.GetLength() {
+			if len(deltas) == 0 {
+				break
+			}
+			current += deltas[0]
+			deltas = deltas[1:]
+			counts = append(counts, uint64(max(current, 0)))
+		}
+	}
+
+	return metricdata.ExponentialBucket{
+		Offset: spans[0].GetOffset() - 1,
+		Counts: counts,
+	}
+}
+
+func convertLabels(labels []*dto.LabelPair) attribute.Set {
+	attributes := make([]attribute.KeyValue, 0, len(labels))
+	for _, label := range labels {
+		attributes = append(attributes, attribute.String(label.GetName(), label.GetValue()))
+	}
+
+	return attribute.NewSet(attributes...)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package otlp
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Prometheus metrics producer", func() {
+	It("converts the Prometheus metric types", func(ctx SpecContext) {
+		registry := prometheus.NewRegistry()
+		gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_gauge", Help: "gauge"}, []string{"value"})
+		gauge.WithLabelValues("ready").Set(3)
+		counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "test_counter", Help: "counter"})
+		counter.Add(5)
+		histogram := prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "test_histogram",
+			Help:    "histogram",
+			Buckets: []float64{1, 10},
+		})
+		histogram.Observe(0.5)
+		histogram.Observe(5)
+		histogram.Observe(50)
+		registry.MustRegister(gauge, counter, histogram)
+
+		scopes, err := newPrometheusProducer(registry).Produce(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(scopes).To(HaveLen(1))
+
+		data := make(map[string]metricdata.Aggregation)
+		for _, metric := range scopes[0].Metrics {
+			data[metric.Name] = metric.Data
+		}
+
+		Expect(data["test_gauge"]).To(BeAssignableToTypeOf(metricdata.Gauge[float64]{}))
+		gaugePoint := data["test_gauge"].(metricdata.Gauge[float64]).DataPoints[0]
+		Expect(gaugePoint.Value).To(BeEquivalentTo(3))
+		labelValue, ok := gaugePoint.Attributes.Value("value")
+		Expect(ok).To(BeTrue())
+		Expect(labelValue.AsString()).To(Equal("ready"))
+
+		sum := data["test_counter"].(metricdata.Sum[float64])
+		Expect(sum.IsMonotonic).To(BeTrue())
+		Expect(sum.Temporality).To(Equal(metricdata.CumulativeTemporality))
+		Expect(sum.DataPoints[0].Value).To(BeEquivalentTo(5))
+
+		histogramPoint := data["test_histogram"].(metricdata.Histogram[float64]).DataPoints[0]
+		Expect(histogramPoint.Count).To(BeEquivalentTo(3))
+		Expect(histogramPoint.Bounds).To(Equal([]float64{1, 10}))
+		Expect(histogramPoint.BucketCounts).To(Equal([]uint64{1, 1, 1}))
+	})
+
+	It("converts the native histograms to exponential histograms", func(ctx SpecContext) {
+		registry := prometheus.NewRegistry()
+		histogram := prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:                        "test_native_histogram",
+			Help:                        "native histogram",
+			NativeHistogramBucketFactor: 2,
+		})
+		for _, value := range []float64{1, 3, 3, 20, 0} {
+			histogram.Observe(value)
+		}
+		registry.MustRegister(histogram)
+
+		scopes, err := newPrometheusProducer(registry).Produce(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(scopes).To(HaveLen(1))
+		Expect(scopes[0].Metrics).To(HaveLen(1))
+
+		data := scopes[0].Metrics[0].Data
+		Expect(data).To(BeAssignableToTypeOf(metricdata.ExponentialHistogram[float64]{}))
+		point := data.(metricdata.ExponentialHistogram[float64]).DataPoints[0]
+		Expect(point.Count).To(BeEquivalentTo(5))
+		Expect(point.Sum).To(BeEquivalentTo(27))
+		Expect(point.Scale).To(BeEquivalentTo(0))
+		Expect(point.ZeroCount).To(BeEquivalentTo(1))
+		Expect(point.PositiveBucket.Offset).To(BeEquivalentTo(-1))
+		Expect(point.PositiveBucket.Counts).To(Equal([]uint64{1, 0, 2, 0, 0, 1}))
+		Expect(point.NegativeBucket.Counts).To(BeEmpty())
+	})
+})
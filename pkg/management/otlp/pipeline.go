@@ -0,0 +1,189 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package otlp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/logpipe"
+)
+
+// shutdownTimeout is the maximum time spent flushing the pending
+// telemetry data when an exporter is stopped
+const shutdownTimeout = 5 * time.Second
+
+// Pipeline pushes the metrics and the PostgreSQL logs of an instance
+// to an OTLP receiver. The exporters are created, replaced and removed
+// following the configuration of the Cluster.
+type Pipeline struct {
+	gatherer prometheus.Gatherer
+	info     ResourceInfo
+
+	mu             sync.RWMutex
+	configKey      string
+	meterProvider  *sdkmetric.MeterProvider
+	loggerProvider *sdklog.LoggerProvider
+}
+
+// NewPipeline creates a new OTLP pipeline exporting the metrics from
+// the passed collectors
+func NewPipeline(info ResourceInfo, collectors ...prometheus.Collector) (*Pipeline, error) {
+	registry := prometheus.NewRegistry()
+	for _, collector := range collectors {
+		if err := registry.Register(collector); err != nil {
+			return nil, fmt.Errorf("while registering OTLP collectors: %w", err)
+		}
+	}
+
+	return &Pipeline{
+		gatherer: registry,
+		info:     info,
+	}, nil
+}
+
+// Configure applies the passed configuration to the pipeline, replacing
+// the exporters if it changed. A nil configuration disables the pipeline.
+func (p *Pipeline) Configure(ctx context.Context, config *apiv1.OTLPConfiguration) error {
+	contextLogger := log.FromContext(ctx)
+
+	configKey := getConfigurationKey(config)
+	p.mu.RLock()
+	unchanged := configKey == p.configKey
+	p.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	var (
+		meterProvider  *sdkmetric.MeterProvider
+		loggerProvider *sdklog.LoggerProvider
+	)
+	res := newResource(p.info)
+
+	if config.IsMetricsEnabled() {
+		exporter, err := otlpmetricgrpc.New(ctx, metricExporterOptions(config)...)
+		if err != nil {
+			return fmt.Errorf("while creating the OTLP metrics exporter: %w", err)
+		}
+		reader := sdkmetric.NewPeriodicReader(
+			exporter,
+			sdkmetric.WithInterval(config.GetExportInterval()),
+			sdkmetric.WithProducer(newPrometheusProducer(p.gatherer)),
+		)
+		meterProvider = sdkmetric.NewMeterProvider(
+			sdkmetric.WithReader(reader),
+			sdkmetric.WithResource(res),
+		)
+	}
+
+	if config.IsLogsEnabled() {
+		exporter, err := otlploggrpc.New(ctx, logExporterOptions(config)...)
+		if err != nil {
+			if meterProvider != nil {
+				_ = meterProvider.Shutdown(ctx)
+			}
+			return fmt.Errorf("while creating the OTLP logs exporter: %w", err)
+		}
+		loggerProvider = sdklog.NewLoggerProvider(
+			sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+			sdklog.WithResource(res),
+		)
+	}
+
+	p.mu.Lock()
+	oldMeterProvider, oldLoggerProvider := p.meterProvider, p.loggerProvider
+	p.meterProvider, p.loggerProvider = meterProvider, loggerProvider
+	p.configKey = configKey
+	p.mu.Unlock()
+
+	if config == nil {
+		contextLogger.Info("OTLP export disabled")
+	} else {
+		contextLogger.Info("OTLP export configured",
+			"endpoint", config.Endpoint,
+			"metrics", config.IsMetricsEnabled(),
+			"logs", config.IsLogsEnabled())
+	}
+
+	return shutdownProviders(ctx, oldMeterProvider, oldLoggerProvider)
+}
+
+// Write implements the logpipe.RecordWriter interface, sending the
+// PostgreSQL log records to the OTLP receiver when enabled
+func (p *Pipeline) Write(record logpipe.NamedRecord) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.loggerProvider == nil {
+		return
+	}
+
+	logRecord, ok := newLogRecord(record)
+	if !ok {
+		return
+	}
+	p.loggerProvider.Logger(record.GetName()).Emit(context.Background(), logRecord)
+}
+
+// Start implements the manager.Runnable interface, flushing and
+// stopping the exporters when the instance manager terminates
+func (p *Pipeline) Start(ctx context.Context) error {
+	<-ctx.Done()
+
+	p.mu.Lock()
+	meterProvider, loggerProvider := p.meterProvider, p.loggerProvider
+	p.meterProvider, p.loggerProvider = nil, nil
+	p.configKey = ""
+	p.mu.Unlock()
+
+	// The passed context is already done, and a fresh one
+	// is needed to flush the pending telemetry data
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+	defer cancel()
+	return shutdownProviders(shutdownCtx, meterProvider, loggerProvider)
+}
+
+func shutdownProviders(
+	ctx context.Context,
+	meterProvider *sdkmetric.MeterProvider,
+	loggerProvider *sdklog.LoggerProvider,
+) error {
+	var errs []error
+	if meterProvider != nil {
+		errs = append(errs, meterProvider.Shutdown(ctx))
+	}
+	if loggerProvider != nil {
+		errs = append(errs, loggerProvider.Shutdown(ctx))
+	}
+
+	return errors.Join(errs...)
+}
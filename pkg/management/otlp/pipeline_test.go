@@ -0,0 +1,162 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package otlp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/propagation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/logpipe"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OTLP pipeline", func() {
+	var (
+		collector *fakeCollector
+		pipeline  *Pipeline
+		gauge     prometheus.Gauge
+	)
+
+	BeforeEach(func() {
+		collector = startFakeCollector()
+		DeferCleanup(collector.stop)
+
+		gauge = prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "cnpg_collector_up",
+			Help: "1 if PostgreSQL is up, 0 otherwise.",
+		})
+		gauge.Set(1)
+
+		var err error
+		pipeline, err = NewPipeline(ResourceInfo{Component: "instance", ClusterName: "cluster-example"}, gauge)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("pushes the metrics gathered from the collectors", func(ctx SpecContext) {
+		Expect(pipeline.Configure(ctx, &apiv1.OTLPConfiguration{
+			Endpoint:       collector.endpoint,
+			Insecure:       true,
+			Logs:           ptr.To(false),
+			ExportInterval: &metav1.Duration{Duration: 100 * time.Millisecond},
+		})).To(Succeed())
+		DeferCleanup(func(ctx context.Context) {
+			Expect(pipeline.Configure(ctx, nil)).To(Succeed())
+		})
+
+		Eventually(collector.getMetricNames).WithContext(ctx).Should(ContainElement("cnpg_collector_up"))
+	})
+
+	It("pushes the PostgreSQL log records", func(ctx SpecContext) {
+		Expect(pipeline.Configure(ctx, &apiv1.OTLPConfiguration{
+			Endpoint: collector.endpoint,
+			Insecure: true,
+			Metrics:  ptr.To(false),
+		})).To(Succeed())
+
+		pipeline.Write(&logpipe.LoggingRecord{
+			LogTime:       "2024-01-02 10:00:00.000 UTC",
+			ErrorSeverity: "ERROR",
+			Message:       "relation \"missing\" does not exist",
+			DatabaseName:  "app",
+		})
+
+		// Stopping the pipeline flushes the pending records
+		Expect(pipeline.Configure(ctx, nil)).To(Succeed())
+		Eventually(collector.getLogRecords).WithContext(ctx).Should(HaveLen(1))
+
+		record := collector.getLogRecords()[0]
+		Expect(record.GetSeverityText()).To(Equal("ERROR"))
+		Expect(int32(record.GetSeverityNumber())).To(BeEquivalentTo(otellog.SeverityError1))
+		Expect(record.GetBody().GetStringValue()).To(Equal("relation \"missing\" does not exist"))
+	})
+
+	It("discards the log records when disabled", func(ctx SpecContext) {
+		pipeline.Write(&logpipe.LoggingRecord{ErrorSeverity: "LOG", Message: "ignored"})
+		Consistently(collector.getLogRecords).WithContext(ctx).
+			WithTimeout(200 * time.Millisecond).Should(BeEmpty())
+	})
+})
+
+var _ = Describe("OTLP traces", func() {
+	It("exports the spans of a cluster and of the calls to the instance manager", func(ctx SpecContext) {
+		collector := startFakeCollector()
+		DeferCleanup(collector.stop)
+
+		var traceparent string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			traceparent = r.Header.Get("traceparent")
+			w.WriteHeader(http.StatusOK)
+		}))
+		DeferCleanup(server.Close)
+
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Monitoring: &apiv1.MonitoringConfiguration{
+					OTLP: &apiv1.OTLPConfiguration{Endpoint: collector.endpoint, Insecure: true},
+				},
+			},
+		}
+
+		providers := NewTracerProviders("operator")
+		spanCtx, span := providers.StartSpan(ctx, cluster, "Reconcile Cluster")
+		Expect(span.IsRecording()).To(BeTrue())
+
+		httpClient := &http.Client{Transport: NewTracingRoundTripper(http.DefaultTransport)}
+		req, err := http.NewRequestWithContext(spanCtx, http.MethodGet, server.URL+"/pg/status", nil)
+		Expect(err).ToNot(HaveOccurred())
+		resp, err := httpClient.Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+		EndSpan(span, nil)
+
+		Expect(traceparent).ToNot(BeEmpty())
+		propagated := propagation.TraceContext{}.Extract(
+			context.Background(),
+			propagation.HeaderCarrier{"Traceparent": []string{traceparent}})
+		Expect(propagated).ToNot(BeNil())
+
+		Expect(providers.Shutdown(ctx)).To(Succeed())
+		Eventually(collector.getSpans).WithContext(ctx).Should(HaveLen(2))
+
+		names := make([]string, 0, 2)
+		for _, span := range collector.getSpans() {
+			names = append(names, span.GetName())
+		}
+		Expect(names).To(ConsistOf("Reconcile Cluster", "GET /pg/status"))
+	})
+
+	It("does not trace the clusters without an OTLP configuration", func(ctx SpecContext) {
+		providers := NewTracerProviders("operator")
+		_, span := providers.StartSpan(ctx, &apiv1.Cluster{}, "Reconcile Cluster")
+		Expect(span.IsRecording()).To(BeFalse())
+	})
+})
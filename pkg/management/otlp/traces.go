@@ -0,0 +1,250 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package otlp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/types"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// TracerProviders keeps a tracer provider for every Cluster exporting
+// its traces, allowing the operator to send the traces of each Cluster
+// to its own receiver
+type TracerProviders struct {
+	component string
+
+	mu        sync.Mutex
+	providers map[types.NamespacedName]clusterTracerProvider
+}
+
+// clusterTracerProvider is the tracer provider of a Cluster, together
+// with the key of the configuration it was created from
+type clusterTracerProvider struct {
+	configKey string
+	provider  *sdktrace.TracerProvider
+}
+
+// NewTracerProviders creates an empty set of tracer providers for
+// the passed component
+func NewTracerProviders(component string) *TracerProviders {
+	return &TracerProviders{
+		component: component,
+		providers: make(map[types.NamespacedName]clusterTracerProvider),
+	}
+}
+
+// get returns the tracer provider of the passed Cluster, creating it if
+// needed. If the configuration changed, the previous tracer provider is
+// replaced, flushed and stopped.
+func (t *TracerProviders) get(
+	ctx context.Context,
+	cluster types.NamespacedName,
+	config *apiv1.OTLPConfiguration,
+) (*sdktrace.TracerProvider, error) {
+	configKey := fmt.Sprintf("%s|%t", config.Endpoint, config.Insecure)
+
+	t.mu.Lock()
+	current, ok := t.providers[cluster]
+	t.mu.Unlock()
+	if ok && current.configKey == configKey {
+		return current.provider, nil
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, traceExporterOptions(config)...)
+	if err != nil {
+		return nil, fmt.Errorf("while creating the OTLP traces exporter: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(newResource(ResourceInfo{Component: t.component})),
+	)
+
+	t.mu.Lock()
+	old, replaced := t.providers[cluster]
+	t.providers[cluster] = clusterTracerProvider{configKey: configKey, provider: provider}
+	t.mu.Unlock()
+
+	if replaced {
+		shutdownTracerProvider(ctx, old.provider)
+	}
+
+	return provider, nil
+}
+
+// Remove flushes, stops and forgets the tracer provider of the passed
+// Cluster, if any. It is used when the export of traces is disabled
+// or the Cluster is deleted.
+func (t *TracerProviders) Remove(ctx context.Context, cluster types.NamespacedName) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	current, ok := t.providers[cluster]
+	delete(t.providers, cluster)
+	t.mu.Unlock()
+
+	if ok {
+		shutdownTracerProvider(ctx, current.provider)
+	}
+}
+
+// shutdownTracerProvider flushes and stops a tracer provider that
+// is not used anymore, logging any error
+func shutdownTracerProvider(ctx context.Context, provider *sdktrace.TracerProvider) {
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+	defer cancel()
+
+	if err := provider.Shutdown(shutdownCtx); err != nil {
+		log.FromContext(ctx).Warning("Error while stopping the OTLP tracer provider", "err", err)
+	}
+}
+
+// StartSpan starts a span for an operation on the passed Cluster,
+// if the export of traces is enabled for it. Otherwise, the returned
+// span is a no-op one. The spans created by the functions called with
+// the returned context, i.e. the calls to the instance manager, are
+// children of this span.
+func (t *TracerProviders) StartSpan(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	name string,
+) (context.Context, trace.Span) {
+	if t == nil {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+
+	clusterName := types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}
+	config := cluster.GetOTLP()
+	if !config.IsTracesEnabled() {
+		t.Remove(ctx, clusterName)
+		return ctx, trace.SpanFromContext(ctx)
+	}
+
+	provider, err := t.get(ctx, clusterName, config)
+	if err != nil {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+
+	return provider.Tracer(instrumentationScope).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(
+			attribute.String("k8s.namespace.name", cluster.Namespace),
+			attribute.String("cnpg.cluster.name", cluster.Name),
+		))
+}
+
+// Start implements the manager.Runnable interface, flushing and
+// stopping the tracer providers when the operator terminates
+func (t *TracerProviders) Start(ctx context.Context) error {
+	<-ctx.Done()
+
+	// The passed context is already done, and a fresh one
+	// is needed to flush the pending spans
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+	defer cancel()
+	return t.Shutdown(shutdownCtx)
+}
+
+// Shutdown flushes and stops every tracer provider
+func (t *TracerProviders) Shutdown(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	errs := make([]error, 0, len(t.providers))
+	for key, current := range t.providers {
+		errs = append(errs, current.provider.Shutdown(ctx))
+		delete(t.providers, key)
+	}
+
+	return errors.Join(errs...)
+}
+
+// EndSpan records the outcome of an operation in the span and ends it
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// tracingRoundTripper creates a client span for every HTTP request whose
+// context contains a recording span, propagating the trace context to
+// the server via the W3C Trace Context headers
+type tracingRoundTripper struct {
+	next http.RoundTripper
+}
+
+// NewTracingRoundTripper wraps the passed transport adding the
+// tracing of the requests
+func NewTracingRoundTripper(next http.RoundTripper) http.RoundTripper {
+	return &tracingRoundTripper{next: next}
+}
+
+// RoundTrip implements the http.RoundTripper interface
+func (t *tracingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	parentSpan := trace.SpanFromContext(req.Context())
+	if !parentSpan.IsRecording() {
+		return t.next.RoundTrip(req)
+	}
+
+	ctx, span := parentSpan.TracerProvider().Tracer(instrumentationScope).Start(
+		req.Context(),
+		fmt.Sprintf("%s %s", req.Method, req.URL.Path),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.path", req.URL.Path),
+			attribute.String("server.address", req.URL.Hostname()),
+		))
+	defer span.End()
+
+	req = req.Clone(ctx)
+	propagation.TraceContext{}.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+
+	return resp, nil
+}
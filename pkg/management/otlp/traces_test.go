@@ -0,0 +1,114 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package otlp
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tracer providers", func() {
+	var (
+		providers *TracerProviders
+		config    *apiv1.OTLPConfiguration
+		first     types.NamespacedName
+		second    types.NamespacedName
+	)
+
+	BeforeEach(func() {
+		providers = NewTracerProviders("operator")
+		config = &apiv1.OTLPConfiguration{Endpoint: "collector:4317", Insecure: true}
+		first = types.NamespacedName{Namespace: "default", Name: "first"}
+		second = types.NamespacedName{Namespace: "default", Name: "second"}
+	})
+
+	AfterEach(func(ctx SpecContext) {
+		Expect(providers.Shutdown(ctx)).To(Succeed())
+	})
+
+	It("keeps a tracer provider for every cluster", func(ctx SpecContext) {
+		firstProvider, err := providers.get(ctx, first, config)
+		Expect(err).ToNot(HaveOccurred())
+		secondProvider, err := providers.get(ctx, second, config)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(secondProvider).ToNot(BeIdenticalTo(firstProvider))
+
+		again, err := providers.get(ctx, first, config)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(again).To(BeIdenticalTo(firstProvider))
+		Expect(providers.providers).To(HaveLen(2))
+	})
+
+	It("replaces and stops the tracer provider when the configuration changes", func(ctx SpecContext) {
+		oldProvider, err := providers.get(ctx, first, config)
+		Expect(err).ToNot(HaveOccurred())
+
+		newProvider, err := providers.get(ctx, first, &apiv1.OTLPConfiguration{Endpoint: "other:4317"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(newProvider).ToNot(BeIdenticalTo(oldProvider))
+		Expect(providers.providers).To(HaveLen(1))
+		Expect(isRecording(oldProvider.Tracer(instrumentationScope).Start(ctx, "test"))).To(BeFalse())
+		Expect(isRecording(newProvider.Tracer(instrumentationScope).Start(ctx, "test"))).To(BeTrue())
+	})
+
+	It("removes the tracer provider when the traces are disabled", func(ctx SpecContext) {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: first.Namespace, Name: first.Name},
+			Spec: apiv1.ClusterSpec{
+				Monitoring: &apiv1.MonitoringConfiguration{OTLP: config},
+			},
+		}
+		_, span := providers.StartSpan(ctx, cluster, "test")
+		Expect(span.IsRecording()).To(BeTrue())
+		EndSpan(span, nil)
+		Expect(providers.providers).To(HaveKey(first))
+
+		config.Traces = ptr.To(false)
+		_, span = providers.StartSpan(ctx, cluster, "test")
+		Expect(span.IsRecording()).To(BeFalse())
+		Expect(providers.providers).To(BeEmpty())
+	})
+
+	It("removes the tracer provider of a deleted cluster", func(ctx SpecContext) {
+		provider, err := providers.get(ctx, first, config)
+		Expect(err).ToNot(HaveOccurred())
+
+		providers.Remove(ctx, first)
+		Expect(providers.providers).To(BeEmpty())
+		Expect(isRecording(provider.Tracer(instrumentationScope).Start(ctx, "test"))).To(BeFalse())
+
+		// Removing an unknown cluster is a no-op
+		providers.Remove(ctx, second)
+	})
+})
+
+// isRecording tells whether the span returned by Tracer.Start is recording
+func isRecording(_ context.Context, span trace.Span) bool {
+	return span.IsRecording()
+}
@@ -29,40 +29,30 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	poolerConfig "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pooler/config"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
 const (
-	// ConfigsDir is the directory in which all pgbouncer configurations are
-	ConfigsDir = postgres.ScratchDataDirectory + "/configs"
-
 	// ServerTLSCAPath is the path where the server CA is stored
-	serverTLSCAPath = ConfigsDir + "/server-tls/ca.crt"
-
-	// ClientTLSCertPath is the path where the client TLS certificate
-	// is stored
-	ClientTLSCertPath = ConfigsDir + "/client-tls/tls.crt"
-
-	// ClientTLSKeyPath is the path where the client TLS private key
-	// is stored
-	ClientTLSKeyPath = ConfigsDir + "/client-tls/tls.key"
+	serverTLSCAPath = poolerConfig.ConfigsDir + "/server-tls/ca.crt"
 
 	// ServerTLSCertPath is the path where the server TLS certificate
 	// is stored
-	serverTLSCertPath = ConfigsDir + "/server-tls/tls.crt"
+	serverTLSCertPath = poolerConfig.ConfigsDir + "/server-tls/tls.crt"
 
 	// ServerTLSKeyPath is the path where the server TLS private key
 	// is stored
-	serverTLSKeyPath = ConfigsDir + "/server-tls/tls.key"
+	serverTLSKeyPath = poolerConfig.ConfigsDir + "/server-tls/tls.key"
 
 	// ClientTLSCAPath is the path where the public key of the CA
 	// used to authenticate clients is stored
-	clientTLSCAPath = ConfigsDir + "/client-ca/ca.crt"
+	clientTLSCAPath = poolerConfig.ConfigsDir + "/client-ca/ca.crt"
 
 	ignoreStartupParametersKey = "ignore_startup_parameters"
-	authUserCrtPath            = ConfigsDir + "/authUser/tls.crt"
-	authUserKeyPath            = ConfigsDir + "/authUser/tls.key"
-	authFilePath               = ConfigsDir + "/userlist.txt"
+	authUserCrtPath            = poolerConfig.ConfigsDir + "/authUser/tls.crt"
+	authUserKeyPath            = poolerConfig.ConfigsDir + "/authUser/tls.key"
+	authFilePath               = poolerConfig.ConfigsDir + "/userlist.txt"
 
 	// PgBouncerIniFileName is the name of PgBouncer configuration file
 	PgBouncerIniFileName = "pgbouncer.ini"
@@ -81,9 +71,10 @@ const (
 
 	pgBouncerIniTemplateString = `
 [databases]
-* = host={{.Pooler.Spec.Cluster.Name}}-{{.Pooler.Spec.Type}}
-
-[pgbouncer]
+{{ .Databases }}
+{{ if .Users }}[users]
+{{ .Users }}
+{{ end }}[pgbouncer]
 pool_mode = {{ .Pooler.Spec.PgBouncer.PoolMode }}
 auth_user = {{ .AuthQueryUser }}
 auth_query = {{ .AuthQuery }}
@@ -103,8 +94,8 @@ host all all ::/0 md5
 `
 
 	pgBouncerUserListTemplateString = `
-"{{ .AuthQueryUser }}" "{{ .AuthQueryPassword }}"
-`
+{{ range .UserList }}"{{ .Name }}" "{{ .Password }}"
+{{ end }}`
 )
 
 var (
@@ -133,25 +124,25 @@ var (
 		"listen_port":          "5432",
 		"listen_addr":          "*",
 		"admin_users":          PgBouncerAdminUser,
-		"auth_hba_file":        ConfigsDir + "/pg_hba.conf",
+		"auth_hba_file":        poolerConfig.ConfigsDir + "/pg_hba.conf",
 		"server_tls_ca_file":   serverTLSCAPath,
-		"client_tls_cert_file": ClientTLSCertPath,
-		"client_tls_key_file":  ClientTLSKeyPath,
+		"client_tls_cert_file": poolerConfig.ClientTLSCertPath,
+		"client_tls_key_file":  poolerConfig.ClientTLSKeyPath,
 		"client_tls_ca_file":   clientTLSCAPath,
 	}
 )
 
 // BuildConfigurationFiles create the config files containing the pgbouncer configuration and
 // the users file
-func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (ConfigurationFiles, error) {
+func BuildConfigurationFiles(
+	pooler *apiv1.Pooler,
+	secrets *poolerConfig.Secrets,
+) (poolerConfig.ConfigurationFiles, error) {
 	files := make(map[string][]byte)
 	var pgbouncerIni bytes.Buffer
 	var pgbouncerUserList bytes.Buffer
 	var pgbouncerHBA bytes.Buffer
 
-	var authQueryUser, authQueryPassword string
-	var isCertAuth bool
-
 	// if no user is provided we have to check the secret for a username, and we must be using basic auth
 	// if a user is provided it will overwrite the user in the secret, or we could be using cert auth
 	authQuerySecret := secrets.AuthQuery
@@ -159,37 +150,19 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 		authQuerySecret = secrets.ServerTLS
 	}
 
+	var authQuery authQueryCredentials
 	if authQuerySecret != nil {
-		authQuerySecretType, err := detectSecretType(authQuerySecret)
+		credentials, err := parseAuthQuerySecret(authQuerySecret)
 		if err != nil {
-			return nil, fmt.Errorf("while detecting auth user secret type: %w", err)
+			return nil, err
 		}
-
-		switch authQuerySecretType {
-		case corev1.SecretTypeBasicAuth:
-			authQueryUser = string(authQuerySecret.Data["username"])
-			authQueryPassword = escapePgBouncerUserListValue(string(authQuerySecret.Data["password"]))
-
-		case corev1.SecretTypeTLS:
-			keyPair, err := certs.ParseServerSecret(authQuerySecret)
-			if err != nil {
-				return nil, fmt.Errorf("while parsing TLS secret for auth user: %w", err)
-			}
-
-			certificate, err := keyPair.ParseCertificate()
-			if err != nil {
-				return nil, fmt.Errorf("while parsing certificate for auth user: %w", err)
-			}
-
-			authQueryUser = certificate.Subject.CommonName
-			isCertAuth = true
-			files[authUserCrtPath] = authQuerySecret.Data[certs.TLSCertKey]
-			files[authUserKeyPath] = authQuerySecret.Data[certs.TLSPrivateKeyKey]
-
-		default:
-			return nil, fmt.Errorf("unsupported secret type for auth query: %s", authQuerySecret.Type)
+		authQuery = *credentials
+		if authQuery.isCertAuth {
+			files[authUserCrtPath] = authQuery.certificate
+			files[authUserKeyPath] = authQuery.privateKey
 		}
 	}
+	authQueryUser, isCertAuth := authQuery.user, authQuery.isCertAuth
 
 	parameters := buildPgBouncerParameters(pooler.Spec.PgBouncer.Parameters)
 
@@ -203,6 +176,13 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 	delete(parameters, "auth_user")
 	authQueryUser = escapePgBouncerUserListValue(authQueryUser)
 
+	// The clusters serving the databases, other than the referenced
+	// one, may need a different user to run the authentication query
+	clustersAuthUser, userList, err := buildClustersAuthUsers(secrets, authQuery, authQueryUser)
+	if err != nil {
+		return nil, err
+	}
+
 	if isCertAuth {
 		parameters["server_tls_cert_file"] = authUserCrtPath
 		parameters["server_tls_key_file"] = authUserKeyPath
@@ -216,19 +196,23 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 	}
 
 	templateData := struct {
-		Pooler            *apiv1.Pooler
-		AuthQuery         string
-		AuthQueryUser     string
-		AuthQueryPassword string
-		AuthDBName        string
-		Parameters        string
-		PgHba             []string
+		Pooler        *apiv1.Pooler
+		Databases     string
+		Users         string
+		AuthQuery     string
+		AuthQueryUser string
+		AuthDBName    string
+		UserList      []userListEntry
+		Parameters    string
+		PgHba         []string
 	}{
-		Pooler:            pooler,
-		AuthQuery:         pooler.GetAuthQuery(),
-		AuthQueryUser:     authQueryUser,
-		AuthQueryPassword: authQueryPassword,
-		AuthDBName:        apiv1.PoolerAuthDBName,
+		Pooler:        pooler,
+		Databases:     buildDatabasesSection(pooler, clustersAuthUser),
+		Users:         buildUsersSection(pooler),
+		AuthQuery:     pooler.GetAuthQuery(),
+		AuthQueryUser: authQueryUser,
+		AuthDBName:    apiv1.PoolerAuthDBName,
+		UserList:      userList,
 		// We are not directly passing the map of parameters inside the template
 		// because the iteration order of the entries inside a map is undefined
 		// and this could lead to the secret being rewritten where isn't really
@@ -243,26 +227,26 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 	if err := pgBouncerIniTemplate.Execute(&pgbouncerIni, templateData); err != nil {
 		return nil, fmt.Errorf("while executing %s template: %w", PgBouncerIniFileName, err)
 	}
-	files[filepath.Join(ConfigsDir, PgBouncerIniFileName)] = pgbouncerIni.Bytes()
+	files[filepath.Join(poolerConfig.ConfigsDir, PgBouncerIniFileName)] = pgbouncerIni.Bytes()
 
 	if !isCertAuth {
 		err := pgBouncerUserListTemplate.Execute(&pgbouncerUserList, templateData)
 		if err != nil {
 			return nil, fmt.Errorf("while executing %s template: %w", PgBouncerUserListFileName, err)
 		}
-		files[filepath.Join(ConfigsDir, PgBouncerUserListFileName)] = pgbouncerUserList.Bytes()
+		files[filepath.Join(poolerConfig.ConfigsDir, PgBouncerUserListFileName)] = pgbouncerUserList.Bytes()
 	}
 
 	if err := pgBouncerHBATemplate.Execute(&pgbouncerHBA, templateData); err != nil {
 		return nil, fmt.Errorf("while executing %s template: %w", PgBouncerHBAConfFileName, err)
 	}
-	files[filepath.Join(ConfigsDir, PgBouncerHBAConfFileName)] = pgbouncerHBA.Bytes()
+	files[filepath.Join(poolerConfig.ConfigsDir, PgBouncerHBAConfFileName)] = pgbouncerHBA.Bytes()
 
 	// The required crypto-material
-	files[serverTLSCAPath] = secrets.ServerCA.Data[certs.CACertKey]
+	files[serverTLSCAPath] = buildServerCABundle(secrets)
 	files[clientTLSCAPath] = secrets.ClientCA.Data[certs.CACertKey]
-	files[ClientTLSCertPath] = secrets.ClientTLS.Data[certs.TLSCertKey]
-	files[ClientTLSKeyPath] = secrets.ClientTLS.Data[certs.TLSPrivateKeyKey]
+	files[poolerConfig.ClientTLSCertPath] = secrets.ClientTLS.Data[certs.TLSCertKey]
+	files[poolerConfig.ClientTLSKeyPath] = secrets.ClientTLS.Data[certs.TLSPrivateKeyKey]
 
 	if secrets.ServerTLS != nil {
 		files[serverTLSCertPath] = secrets.ServerTLS.Data[certs.TLSCertKey]
@@ -271,3 +255,51 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 
 	return files, nil
 }
+
+// authQueryCredentials are the credentials used by PgBouncer
+// to run the authentication query
+type authQueryCredentials struct {
+	user        string
+	password    string
+	isCertAuth  bool
+	certificate []byte
+	privateKey  []byte
+}
+
+// parseAuthQuerySecret extracts the credentials used to run the
+// authentication query from a basic-auth or a TLS secret
+func parseAuthQuerySecret(authQuerySecret *corev1.Secret) (*authQueryCredentials, error) {
+	authQuerySecretType, err := detectSecretType(authQuerySecret)
+	if err != nil {
+		return nil, fmt.Errorf("while detecting auth user secret type: %w", err)
+	}
+
+	switch authQuerySecretType {
+	case corev1.SecretTypeBasicAuth:
+		return &authQueryCredentials{
+			user:     string(authQuerySecret.Data["username"]),
+			password: escapePgBouncerUserListValue(string(authQuerySecret.Data["password"])),
+		}, nil
+
+	case corev1.SecretTypeTLS:
+		keyPair, err := certs.ParseServerSecret(authQuerySecret)
+		if err != nil {
+			return nil, fmt.Errorf("while parsing TLS secret for auth user: %w", err)
+		}
+
+		certificate, err := keyPair.ParseCertificate()
+		if err != nil {
+			return nil, fmt.Errorf("while parsing certificate for auth user: %w", err)
+		}
+
+		return &authQueryCredentials{
+			user:        certificate.Subject.CommonName,
+			isCertAuth:  true,
+			certificate: authQuerySecret.Data[certs.TLSCertKey],
+			privateKey:  authQuerySecret.Data[certs.TLSPrivateKeyKey],
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported secret type for auth query: %s", authQuerySecret.Type)
+	}
+}
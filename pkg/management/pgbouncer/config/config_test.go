@@ -27,20 +27,21 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	poolerConfig "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pooler/config"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
 
 var _ = Describe("BuildConfigurationFiles", func() {
-	iniPath := filepath.Join(ConfigsDir, PgBouncerIniFileName)
-	userListPath := filepath.Join(ConfigsDir, PgBouncerUserListFileName)
+	iniPath := filepath.Join(poolerConfig.ConfigsDir, PgBouncerIniFileName)
+	userListPath := filepath.Join(poolerConfig.ConfigsDir, PgBouncerUserListFileName)
 
 	// newSecrets returns a Secrets value backed by a basic-auth auth query
 	// secret. BuildConfigurationFiles dereferences the crypto-material secrets
 	// unconditionally, so they are populated with placeholder, non-nil content.
-	newSecrets := func() *Secrets {
-		return &Secrets{
+	newSecrets := func() *poolerConfig.Secrets {
+		return &poolerConfig.Secrets{
 			AuthQuery: &corev1.Secret{
 				Type: corev1.SecretTypeBasicAuth,
 				Data: map[string][]byte{
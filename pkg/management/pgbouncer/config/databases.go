@@ -0,0 +1,242 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	poolerConfig "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pooler/config"
+)
+
+// authDatabasePrefix is the prefix of the name of the databases used
+// to run the authentication query against the clusters serving the
+// databases explicitly listed in the Pooler
+const authDatabasePrefix = "cnpg_auth_"
+
+// unquotedIdentifierRegexp matches the database names that can
+// be used in the PgBouncer configuration without being quoted
+var unquotedIdentifierRegexp = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// userListEntry is an entry of the PgBouncer auth_file
+type userListEntry struct {
+	Name     string
+	Password string
+}
+
+// buildClustersAuthUsers returns the users running the authentication query
+// against the clusters serving the databases, other than the referenced one,
+// when they differ from the main one, together with the content of the
+// PgBouncer auth_file.
+func buildClustersAuthUsers(
+	secrets *poolerConfig.Secrets,
+	authQuery authQueryCredentials,
+	authQueryUser string,
+) (map[string]string, []userListEntry, error) {
+	clustersAuthUser := make(map[string]string, len(secrets.Clusters))
+	userList := []userListEntry{{Name: authQueryUser, Password: authQuery.password}}
+
+	clusterNames := make([]string, 0, len(secrets.Clusters))
+	for clusterName := range secrets.Clusters {
+		clusterNames = append(clusterNames, clusterName)
+	}
+	slices.Sort(clusterNames)
+
+	for _, clusterName := range clusterNames {
+		clusterSecrets := secrets.Clusters[clusterName]
+		if clusterSecrets == nil || clusterSecrets.AuthQuery == nil {
+			continue
+		}
+
+		credentials, err := parseAuthQuerySecret(clusterSecrets.AuthQuery)
+		if err != nil {
+			return nil, nil, fmt.Errorf("while parsing the auth query secret for cluster %s: %w", clusterName, err)
+		}
+
+		user := escapePgBouncerUserListValue(credentials.user)
+		switch {
+		case credentials.isCertAuth != authQuery.isCertAuth:
+			return nil, nil, fmt.Errorf(
+				"the auth query secret for cluster %s uses a different authentication method", clusterName)
+
+		case credentials.isCertAuth && user != authQueryUser:
+			// PgBouncer presents the same certificate to every server
+			return nil, nil, fmt.Errorf(
+				"the auth query user for cluster %s is %q, but PgBouncer authenticates as %q",
+				clusterName, credentials.user, authQueryUser)
+
+		case credentials.isCertAuth:
+			continue
+		}
+
+		if idx := slices.IndexFunc(userList, func(entry userListEntry) bool {
+			return entry.Name == user
+		}); idx >= 0 {
+			if userList[idx].Password != credentials.password {
+				return nil, nil, fmt.Errorf(
+					"the auth query user %q for cluster %s has a different password", credentials.user, clusterName)
+			}
+		} else {
+			userList = append(userList, userListEntry{Name: user, Password: credentials.password})
+		}
+
+		if user != authQueryUser {
+			clustersAuthUser[clusterName] = user
+		}
+	}
+
+	return clustersAuthUser, userList, nil
+}
+
+// buildDatabasesSection generates the content of the `[databases]` section
+// of the PgBouncer configuration. When the Pooler doesn't list any database,
+// every database is forwarded to the referenced cluster.
+func buildDatabasesSection(pooler *apiv1.Pooler, clustersAuthUser map[string]string) string {
+	if len(pooler.Spec.PgBouncer.Databases) == 0 {
+		service := fmt.Sprintf("%s-%s", pooler.Spec.Cluster.Name, pooler.Spec.Type)
+		return fmt.Sprintf("* = host=%s\n", getServiceHosts(pooler, service))
+	}
+
+	// The services, by host name, serving the databases, each
+	// one needing a database to run the authentication query
+	var result bytes.Buffer
+	services := make(map[string]string)
+
+	for idx := range pooler.Spec.PgBouncer.Databases {
+		database := &pooler.Spec.PgBouncer.Databases[idx]
+		clusterName := database.GetClusterName(pooler)
+		host := fmt.Sprintf("%s-%s", clusterName, database.GetType(pooler))
+		authDatabase := getAuthDatabaseName(host)
+		services[host] = clusterName
+
+		options := []string{"host=" + getServiceHosts(pooler, host)}
+		if !database.IsFallback() {
+			dbName := database.DBName
+			if dbName == "" {
+				dbName = database.Name
+			}
+			options = append(options, "dbname="+dbName)
+		}
+		if database.PoolSize != nil {
+			options = append(options, fmt.Sprintf("pool_size=%d", *database.PoolSize))
+		}
+		if database.PoolMode != "" {
+			options = append(options, fmt.Sprintf("pool_mode=%s", database.PoolMode))
+		}
+		if database.MaxDBConnections != nil {
+			options = append(options, fmt.Sprintf("max_db_connections=%d", *database.MaxDBConnections))
+		}
+		if authUser, ok := clustersAuthUser[clusterName]; ok {
+			options = append(options, "auth_user="+authUser)
+		}
+		options = append(options, "auth_dbname="+authDatabase)
+
+		fmt.Fprintf(&result, "%s = %s\n", quotePgBouncerIdentifier(database.Name), strings.Join(options, " "))
+	}
+
+	// Every cluster needs a database where the authentication query is
+	// executed, as the global auth_dbname is only valid for the databases
+	// of the referenced cluster
+	hosts := make([]string, 0, len(services))
+	for host := range services {
+		hosts = append(hosts, host)
+	}
+	slices.Sort(hosts)
+	for _, host := range hosts {
+		authDatabase := getAuthDatabaseName(host)
+		options := []string{"host=" + getServiceHosts(pooler, host), "dbname=" + apiv1.PoolerAuthDBName}
+		if authUser, ok := clustersAuthUser[services[host]]; ok {
+			options = append(options, "auth_user="+authUser)
+		}
+		options = append(options, "auth_dbname="+authDatabase)
+
+		fmt.Fprintf(&result, "%s = %s\n", authDatabase, strings.Join(options, " "))
+	}
+
+	return result.String()
+}
+
+// getServiceHosts gets the value of the host option for the databases
+// served by the passed service. When the replicas of the referenced cluster
+// are load balanced by PgBouncer, their addresses are used in place of
+// the read-only service.
+func getServiceHosts(pooler *apiv1.Pooler, service string) string {
+	hosts := pooler.GetReplicaLoadBalancingHosts()
+	if len(hosts) == 0 || service != fmt.Sprintf("%s-%s", pooler.Spec.Cluster.Name, apiv1.PoolerTypeRO) {
+		return service
+	}
+
+	return strings.Join(hosts, ",")
+}
+
+// getAuthDatabaseName gets the name of the database used to run the
+// authentication query for the databases served by the passed host
+func getAuthDatabaseName(host string) string {
+	return authDatabasePrefix + strings.ReplaceAll(host, "-", "_")
+}
+
+// buildServerCABundle concatenates the CAs needed to validate the
+// certificates of every cluster serving the databases of PgBouncer
+func buildServerCABundle(secrets *poolerConfig.Secrets) []byte {
+	bundle := secrets.ServerCA.Data[certs.CACertKey]
+	if len(secrets.Clusters) == 0 {
+		return bundle
+	}
+
+	clusterNames := make([]string, 0, len(secrets.Clusters))
+	for clusterName := range secrets.Clusters {
+		clusterNames = append(clusterNames, clusterName)
+	}
+	slices.Sort(clusterNames)
+
+	result := slices.Clone(bundle)
+	for _, clusterName := range clusterNames {
+		clusterSecrets := secrets.Clusters[clusterName]
+		if clusterSecrets == nil || clusterSecrets.ServerCA == nil {
+			continue
+		}
+
+		ca := clusterSecrets.ServerCA.Data[certs.CACertKey]
+		if len(ca) == 0 || bytes.Contains(result, ca) {
+			continue
+		}
+		if len(result) > 0 && !bytes.HasSuffix(result, []byte("\n")) {
+			result = append(result, '\n')
+		}
+		result = append(result, ca...)
+	}
+
+	return result
+}
+
+// quotePgBouncerIdentifier quotes a database name, when needed,
+// to be used as a key in the PgBouncer configuration
+func quotePgBouncerIdentifier(name string) string {
+	if name == "*" || unquotedIdentifierRegexp.MatchString(name) {
+		return name
+	}
+
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,215 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	poolerConfig "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pooler/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func newBasicAuthSecret(username, password string) *corev1.Secret {
+	return &corev1.Secret{
+		Type: corev1.SecretTypeBasicAuth,
+		Data: map[string][]byte{
+			corev1.BasicAuthUsernameKey: []byte(username),
+			corev1.BasicAuthPasswordKey: []byte(password),
+		},
+	}
+}
+
+func newCASecret(ca string) *corev1.Secret {
+	return &corev1.Secret{Data: map[string][]byte{certs.CACertKey: []byte(ca)}}
+}
+
+var _ = Describe("PgBouncer databases", func() {
+	newPooler := func(databases ...apiv1.PgBouncerDatabase) *apiv1.Pooler {
+		return &apiv1.Pooler{
+			ObjectMeta: metav1.ObjectMeta{Name: "pooler-example"},
+			Spec: apiv1.PoolerSpec{
+				Cluster: apiv1.LocalObjectReference{Name: "cluster-example"},
+				Type:    apiv1.PoolerTypeRW,
+				PgBouncer: &apiv1.PgBouncerSpec{
+					PoolMode:  apiv1.PgBouncerPoolModeSession,
+					Databases: databases,
+				},
+			},
+		}
+	}
+
+	It("forwards every database to the referenced cluster by default", func() {
+		Expect(buildDatabasesSection(newPooler(), nil)).To(Equal("* = host=cluster-example-rw\n"))
+	})
+
+	It("generates an entry for every database, with its overrides", func() {
+		pooler := newPooler(
+			apiv1.PgBouncerDatabase{Name: "*"},
+			apiv1.PgBouncerDatabase{
+				Name:             "payments",
+				Cluster:          &apiv1.LocalObjectReference{Name: "cluster-payments"},
+				Type:             apiv1.PoolerTypeRO,
+				DBName:           "app",
+				PoolSize:         ptr.To(int32(20)),
+				PoolMode:         apiv1.PgBouncerPoolModeTransaction,
+				MaxDBConnections: ptr.To(int32(50)),
+			},
+			apiv1.PgBouncerDatabase{Name: "my-app"},
+		)
+
+		Expect(buildDatabasesSection(pooler, map[string]string{"cluster-payments": "payments_auth"})).To(Equal(
+			"* = host=cluster-example-rw auth_dbname=cnpg_auth_cluster_example_rw\n" +
+				"payments = host=cluster-payments-ro dbname=app pool_size=20 pool_mode=transaction " +
+				"max_db_connections=50 auth_user=payments_auth auth_dbname=cnpg_auth_cluster_payments_ro\n" +
+				"\"my-app\" = host=cluster-example-rw dbname=my-app auth_dbname=cnpg_auth_cluster_example_rw\n" +
+				"cnpg_auth_cluster_example_rw = host=cluster-example-rw dbname=postgres " +
+				"auth_dbname=cnpg_auth_cluster_example_rw\n" +
+				"cnpg_auth_cluster_payments_ro = host=cluster-payments-ro dbname=postgres " +
+				"auth_user=payments_auth auth_dbname=cnpg_auth_cluster_payments_ro\n"))
+	})
+
+	It("connects directly to the replicas when they are load balanced", func() {
+		pooler := newPooler(
+			apiv1.PgBouncerDatabase{Name: "*"},
+			apiv1.PgBouncerDatabase{Name: "billing", Cluster: &apiv1.LocalObjectReference{Name: "cluster-billing"}},
+		)
+		pooler.Spec.Type = apiv1.PoolerTypeRO
+		pooler.Spec.ReplicaLoadBalancing = &apiv1.PoolerReplicaLoadBalancing{}
+		pooler.Status.ReplicaLoadBalancing = &apiv1.PoolerReplicaLoadBalancingStatus{
+			Members: []apiv1.PoolerReplicaMember{
+				{Name: "cluster-example-2", IP: "10.0.0.2"},
+				{Name: "cluster-example-3", IP: "10.0.0.3"},
+			},
+		}
+
+		Expect(buildDatabasesSection(pooler, nil)).To(Equal(
+			"* = host=10.0.0.2,10.0.0.3 auth_dbname=cnpg_auth_cluster_example_ro\n" +
+				"billing = host=cluster-billing-ro dbname=billing auth_dbname=cnpg_auth_cluster_billing_ro\n" +
+				"cnpg_auth_cluster_billing_ro = host=cluster-billing-ro dbname=postgres " +
+				"auth_dbname=cnpg_auth_cluster_billing_ro\n" +
+				"cnpg_auth_cluster_example_ro = host=10.0.0.2,10.0.0.3 dbname=postgres " +
+				"auth_dbname=cnpg_auth_cluster_example_ro\n"))
+
+		pooler.Spec.PgBouncer.Databases = nil
+		Expect(buildDatabasesSection(pooler, nil)).To(Equal("* = host=10.0.0.2,10.0.0.3\n"))
+
+		pooler.Status.ReplicaLoadBalancing.Members = nil
+		Expect(buildDatabasesSection(pooler, nil)).To(Equal("* = host=cluster-example-ro\n"))
+	})
+
+	It("adds the users of the other clusters to the auth_file", func() {
+		secrets := &poolerConfig.Secrets{
+			Clusters: map[string]*poolerConfig.ClusterSecrets{
+				"cluster-a": {AuthQuery: newBasicAuthSecret("main", "secret")},
+				"cluster-b": {AuthQuery: newBasicAuthSecret("other", "password")},
+			},
+		}
+
+		clustersAuthUser, userList, err := buildClustersAuthUsers(
+			secrets, authQueryCredentials{user: "main", password: "secret"}, "main")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(clustersAuthUser).To(Equal(map[string]string{"cluster-b": "other"}))
+		Expect(userList).To(Equal([]userListEntry{
+			{Name: "main", Password: "secret"},
+			{Name: "other", Password: "password"},
+		}))
+	})
+
+	It("complains when the same user has different passwords", func() {
+		secrets := &poolerConfig.Secrets{
+			Clusters: map[string]*poolerConfig.ClusterSecrets{
+				"cluster-b": {AuthQuery: newBasicAuthSecret("main", "different")},
+			},
+		}
+
+		_, _, err := buildClustersAuthUsers(secrets, authQueryCredentials{user: "main", password: "secret"}, "main")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("complains when the authentication methods are mixed", func() {
+		secrets := &poolerConfig.Secrets{
+			Clusters: map[string]*poolerConfig.ClusterSecrets{
+				"cluster-b": {AuthQuery: newBasicAuthSecret("main", "secret")},
+			},
+		}
+
+		_, _, err := buildClustersAuthUsers(secrets, authQueryCredentials{user: "main", isCertAuth: true}, "main")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("bundles the CAs of every cluster", func() {
+		secrets := &poolerConfig.Secrets{
+			ServerCA: newCASecret("ca-main\n"),
+			Clusters: map[string]*poolerConfig.ClusterSecrets{
+				"cluster-b": {ServerCA: newCASecret("ca-b")},
+				"cluster-c": {ServerCA: newCASecret("ca-main\n")},
+				"cluster-d": {ServerCA: newCASecret("ca-d\n")},
+			},
+		}
+
+		Expect(string(buildServerCABundle(secrets))).To(Equal("ca-main\nca-b\nca-d\n"))
+		Expect(string(secrets.ServerCA.Data[certs.CACertKey])).To(Equal("ca-main\n"))
+	})
+
+	It("quotes the database names when needed", func() {
+		Expect(quotePgBouncerIdentifier("*")).To(Equal("*"))
+		Expect(quotePgBouncerIdentifier("app_1")).To(Equal("app_1"))
+		Expect(quotePgBouncerIdentifier("my.app")).To(Equal(`"my.app"`))
+	})
+
+	It("generates the configuration files for a pooler serving several clusters", func() {
+		pooler := newPooler(
+			apiv1.PgBouncerDatabase{Name: "app"},
+			apiv1.PgBouncerDatabase{Name: "billing", Cluster: &apiv1.LocalObjectReference{Name: "cluster-billing"}},
+		)
+		secrets := &poolerConfig.Secrets{
+			AuthQuery: newBasicAuthSecret("main", "secret"),
+			ServerCA:  newCASecret("ca-main\n"),
+			ClientCA:  &corev1.Secret{Data: map[string][]byte{}},
+			ClientTLS: &corev1.Secret{Data: map[string][]byte{}},
+			Clusters: map[string]*poolerConfig.ClusterSecrets{
+				"cluster-billing": {
+					ServerCA:  newCASecret("ca-billing\n"),
+					AuthQuery: newBasicAuthSecret("billing", "password"),
+				},
+			},
+		}
+
+		files, err := BuildConfigurationFiles(pooler, secrets)
+		Expect(err).ToNot(HaveOccurred())
+
+		ini := string(files[filepath.Join(poolerConfig.ConfigsDir, PgBouncerIniFileName)])
+		Expect(ini).To(ContainSubstring(
+			"billing = host=cluster-billing-rw dbname=billing auth_user=billing " +
+				"auth_dbname=cnpg_auth_cluster_billing_rw\n"))
+		Expect(ini).ToNot(ContainSubstring("* = "))
+		Expect(string(files[filepath.Join(poolerConfig.ConfigsDir, PgBouncerUserListFileName)])).To(
+			Equal("\n\"main\" \"secret\"\n\"billing\" \"password\"\n"))
+		Expect(string(files[serverTLSCAPath])).To(Equal("ca-main\nca-billing\n"))
+	})
+})
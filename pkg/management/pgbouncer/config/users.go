@@ -0,0 +1,56 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// buildUsersSection generates the content of the `[users]` section
+// of the PgBouncer configuration, which is empty when the Pooler
+// doesn't have any per-user setting
+func buildUsersSection(pooler *apiv1.Pooler) string {
+	var result bytes.Buffer
+	for idx := range pooler.Spec.PgBouncer.Users {
+		user := &pooler.Spec.PgBouncer.Users[idx]
+
+		var options []string
+		if user.PoolMode != "" {
+			options = append(options, fmt.Sprintf("pool_mode=%s", user.PoolMode))
+		}
+		if user.PoolSize != nil {
+			options = append(options, fmt.Sprintf("pool_size=%d", *user.PoolSize))
+		}
+		if user.MaxUserConnections != nil {
+			options = append(options, fmt.Sprintf("max_user_connections=%d", *user.MaxUserConnections))
+		}
+		if len(options) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&result, "%s = %s\n", quotePgBouncerIdentifier(user.Name), strings.Join(options, " "))
+	}
+
+	return result.String()
+}
@@ -0,0 +1,77 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"path/filepath"
+
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	poolerConfig "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pooler/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PgBouncer users", func() {
+	newPooler := func(users ...apiv1.PgBouncerUser) *apiv1.Pooler {
+		return &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				Cluster: apiv1.LocalObjectReference{Name: "cluster-example"},
+				Type:    apiv1.PoolerTypeRW,
+				PgBouncer: &apiv1.PgBouncerSpec{
+					PoolMode: apiv1.PgBouncerPoolModeTransaction,
+					Users:    users,
+				},
+			},
+		}
+	}
+
+	It("is empty when there are no per-user settings", func() {
+		Expect(buildUsersSection(newPooler())).To(BeEmpty())
+		Expect(buildUsersSection(newPooler(apiv1.PgBouncerUser{Name: "app"}))).To(BeEmpty())
+	})
+
+	It("generates an entry for every user", func() {
+		pooler := newPooler(
+			apiv1.PgBouncerUser{Name: "batch", PoolMode: apiv1.PgBouncerPoolModeSession},
+			apiv1.PgBouncerUser{Name: "web.app", PoolSize: ptr.To(int32(30)), MaxUserConnections: ptr.To(int32(100))},
+		)
+		Expect(buildUsersSection(pooler)).To(Equal(
+			"batch = pool_mode=session\n" +
+				"\"web.app\" = pool_size=30 max_user_connections=100\n"))
+	})
+
+	It("renders the users section in the configuration file", func() {
+		pooler := newPooler(apiv1.PgBouncerUser{Name: "batch", PoolMode: apiv1.PgBouncerPoolModeSession})
+		files, err := BuildConfigurationFiles(pooler, &poolerConfig.Secrets{
+			AuthQuery: newBasicAuthSecret("main", "secret"),
+			ServerCA:  newCASecret("ca"),
+			ClientCA:  newCASecret("ca"),
+			ClientTLS: newCASecret("ca"),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(files[filepath.Join(poolerConfig.ConfigsDir, PgBouncerIniFileName)])).To(HavePrefix(
+			"\n[databases]\n* = host=cluster-example-rw\n\n" +
+				"[users]\nbatch = pool_mode=session\n\n" +
+				"[pgbouncer]\npool_mode = transaction\n"))
+	})
+})
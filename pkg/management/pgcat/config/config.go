@@ -0,0 +1,268 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package config contains the code generating the PgCat configuration
+// starting from a Pooler resource
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	poolerConfig "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pooler/config"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+const (
+	// PgCatConfigFileName is the name of the PgCat configuration file
+	PgCatConfigFileName = "pgcat.toml"
+
+	// PgCatAdminUser is the user of the PgCat administrative console
+	PgCatAdminUser = "pgcat"
+
+	// PgCatAdminDatabase is the database of the PgCat administrative console
+	PgCatAdminDatabase = "pgcat"
+
+	// PgCatPortName is the name of the port where PgCat will be listening
+	PgCatPortName = "pgcat"
+
+	pgCatConfigTemplateString = `
+[general]
+{{ .Parameters }}
+{{- range $pool := .Pools }}
+[pools.{{ $pool.Name }}]
+pool_mode = {{ $pool.PoolMode }}
+default_role = {{ $pool.DefaultRole }}
+query_parser_enabled = {{ $pool.ReadWriteSplitting }}
+query_parser_read_write_splitting = {{ $pool.ReadWriteSplitting }}
+primary_reads_enabled = {{ not $pool.ReadWriteSplitting }}
+auth_query = {{ $.AuthQuery }}
+auth_query_user = {{ $.AuthQueryUser }}
+auth_query_password = {{ $.AuthQueryPassword }}
+{{ range $idx, $user := $pool.Users }}
+[pools.{{ $pool.Name }}.users.{{ $idx }}]
+username = {{ $user }}
+pool_size = {{ $pool.PoolSize }}
+{{ end }}
+[pools.{{ $pool.Name }}.shards.0]
+database = {{ $pool.DBName }}
+servers = [
+{{- range $server := $pool.Servers }}
+  [{{ $server.Host }}, {{ $server.Port }}, {{ $server.Role }}],
+{{- end }}
+]
+{{ end -}}
+`
+)
+
+var (
+	pgCatConfigTemplate = template.Must(
+		template.New(PgCatConfigFileName).Parse(pgCatConfigTemplateString))
+
+	// The PgCat parameters we want to have a default different from the default one
+	defaultPgCatParameters = map[string]string{
+		"log_client_connections":    "false",
+		"log_client_disconnections": "false",
+		// PgCat can only verify the server certificates against its
+		// bundled public roots, which don't include the CA generated by
+		// the operator: the verification can be enabled by the user when
+		// the server certificates of the cluster are signed by one of them
+		"verify_server_certificate": "false",
+	}
+)
+
+// pgCatServer is an entry of the list of servers of a PgCat shard
+type pgCatServer struct {
+	Host string
+	Port int
+	Role string
+}
+
+// pgCatPool is a pool of the PgCat configuration, whose
+// string fields are already quoted
+type pgCatPool struct {
+	Name               string
+	DBName             string
+	PoolMode           string
+	DefaultRole        string
+	ReadWriteSplitting bool
+	PoolSize           int32
+	Users              []string
+	Servers            []pgCatServer
+}
+
+// BuildConfigurationFiles creates the configuration files of PgCat, using the
+// passed password for the administrative console
+func BuildConfigurationFiles(
+	pooler *apiv1.Pooler,
+	secrets *poolerConfig.Secrets,
+	adminPassword string,
+) (poolerConfig.ConfigurationFiles, error) {
+	if pooler.Spec.PgCat == nil {
+		return nil, fmt.Errorf("missing PgCat configuration")
+	}
+
+	if secrets.AuthQuery == nil {
+		return nil, fmt.Errorf("missing auth query secret")
+	}
+	if secrets.AuthQuery.Type != corev1.SecretTypeBasicAuth {
+		return nil, fmt.Errorf("unsupported secret type for auth query: %s", secrets.AuthQuery.Type)
+	}
+
+	parameters := buildPgCatParameters(pooler.Spec.PgCat.Parameters)
+	parameters["admin_password"] = quoteTOMLString(adminPassword)
+
+	templateData := struct {
+		Parameters        string
+		Pools             []pgCatPool
+		AuthQuery         string
+		AuthQueryUser     string
+		AuthQueryPassword string
+	}{
+		// The parameters are sorted to keep the configuration stable
+		// and avoid spurious reloads
+		Parameters:        stringifyPgCatParameters(parameters),
+		Pools:             buildPools(pooler),
+		AuthQuery:         quoteTOMLString(pooler.GetAuthQuery()),
+		AuthQueryUser:     quoteTOMLString(string(secrets.AuthQuery.Data[corev1.BasicAuthUsernameKey])),
+		AuthQueryPassword: quoteTOMLString(string(secrets.AuthQuery.Data[corev1.BasicAuthPasswordKey])),
+	}
+
+	var pgCatConfig bytes.Buffer
+	if err := pgCatConfigTemplate.Execute(&pgCatConfig, templateData); err != nil {
+		return nil, fmt.Errorf("while executing %s template: %w", PgCatConfigFileName, err)
+	}
+
+	return poolerConfig.ConfigurationFiles{
+		filepath.Join(poolerConfig.ConfigsDir, PgCatConfigFileName): pgCatConfig.Bytes(),
+		poolerConfig.ClientTLSCertPath:                              secrets.ClientTLS.Data[certs.TLSCertKey],
+		poolerConfig.ClientTLSKeyPath:                               secrets.ClientTLS.Data[certs.TLSPrivateKeyKey],
+	}, nil
+}
+
+// buildPools generates the pools of the PgCat configuration, one
+// for each database exposed by the Pooler
+func buildPools(pooler *apiv1.Pooler) []pgCatPool {
+	servers, defaultRole := buildServers(pooler)
+
+	pools := make([]pgCatPool, 0, len(pooler.Spec.PgCat.Databases))
+	for idx := range pooler.Spec.PgCat.Databases {
+		database := &pooler.Spec.PgCat.Databases[idx]
+
+		users := make([]string, len(database.Users))
+		for userIdx, user := range database.Users {
+			users[userIdx] = quoteTOMLString(user)
+		}
+
+		pools = append(pools, pgCatPool{
+			Name:               quoteTOMLString(database.Name),
+			DBName:             quoteTOMLString(database.GetDBName()),
+			PoolMode:           quoteTOMLString(string(pooler.Spec.PgCat.PoolMode)),
+			DefaultRole:        quoteTOMLString(defaultRole),
+			ReadWriteSplitting: pooler.Spec.PgCat.ReadWriteSplitting,
+			PoolSize:           database.GetPoolSize(),
+			Users:              users,
+			Servers:            servers,
+		})
+	}
+
+	return pools
+}
+
+// buildServers gets the servers of the referenced cluster PgCat connects
+// to, together with the role of the servers the queries are sent to by
+// default. When the read/write splitting is enabled, PgCat connects to both
+// the primary and the replicas, and chooses the server by parsing the queries.
+func buildServers(pooler *apiv1.Pooler) ([]pgCatServer, string) {
+	serviceHost := func(poolerType apiv1.PoolerType) string {
+		return fmt.Sprintf("%s-%s", pooler.Spec.Cluster.Name, poolerType)
+	}
+	primary := []pgCatServer{{Host: serviceHost(apiv1.PoolerTypeRW), Role: "primary"}}
+
+	var replicas []pgCatServer
+	if hosts := pooler.GetReplicaLoadBalancingHosts(); len(hosts) > 0 {
+		for _, host := range hosts {
+			replicas = append(replicas, pgCatServer{Host: host, Role: "replica"})
+		}
+	} else {
+		replicas = []pgCatServer{{Host: serviceHost(apiv1.PoolerTypeRO), Role: "replica"}}
+	}
+
+	var (
+		servers     []pgCatServer
+		defaultRole string
+	)
+	switch {
+	case pooler.Spec.PgCat.ReadWriteSplitting:
+		servers, defaultRole = append(primary, replicas...), "any"
+	case pooler.Spec.Type == apiv1.PoolerTypeRO:
+		servers, defaultRole = replicas, "replica"
+	case pooler.Spec.Type == apiv1.PoolerTypeR:
+		servers, defaultRole = []pgCatServer{{Host: serviceHost(apiv1.PoolerTypeR), Role: "replica"}}, "any"
+	default:
+		servers, defaultRole = primary, "primary"
+	}
+
+	for idx := range servers {
+		servers[idx].Host = quoteTOMLString(servers[idx].Host)
+		servers[idx].Port = postgres.ServerPort
+		servers[idx].Role = quoteTOMLString(servers[idx].Role)
+	}
+
+	return servers, defaultRole
+}
+
+// buildPgCatParameters builds the content of the `[general]` section of the
+// PgCat configuration applying any default parameters and forcing any
+// required parameter needed for the controller to work correctly.
+// The returned values are already in TOML syntax.
+func buildPgCatParameters(userParameters map[string]string) map[string]string {
+	params := make(map[string]string, len(userParameters))
+
+	for k, v := range defaultPgCatParameters {
+		params[k] = v
+	}
+
+	for k, v := range userParameters {
+		params[k] = v
+	}
+
+	for k, v := range params {
+		params[k] = toTOMLValue(v)
+	}
+
+	params["host"] = quoteTOMLString("0.0.0.0")
+	params["port"] = strconv.Itoa(poolerConfig.Port)
+	params["admin_username"] = quoteTOMLString(PgCatAdminUser)
+	// The metrics are exposed by the instance manager, which
+	// queries the administrative console of PgCat
+	params["enable_prometheus_exporter"] = "false"
+	params["tls_certificate"] = quoteTOMLString(poolerConfig.ClientTLSCertPath)
+	params["tls_private_key"] = quoteTOMLString(poolerConfig.ClientTLSKeyPath)
+	params["server_tls"] = "true"
+
+	return params
+}
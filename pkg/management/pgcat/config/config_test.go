@@ -0,0 +1,192 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	poolerConfig "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pooler/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PgCat configuration", func() {
+	var (
+		pooler  *apiv1.Pooler
+		secrets *poolerConfig.Secrets
+	)
+
+	configFileName := filepath.Join(poolerConfig.ConfigsDir, PgCatConfigFileName)
+
+	BeforeEach(func() {
+		pooler = &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				Cluster: apiv1.LocalObjectReference{Name: "cluster-example"},
+				Type:    apiv1.PoolerTypeRW,
+				PgCat: &apiv1.PgCatSpec{
+					Image:           "pgcat:latest",
+					PoolMode:        apiv1.PgBouncerPoolModeTransaction,
+					AuthQuerySecret: apiv1.LocalObjectReference{Name: "auth"},
+					Databases: []apiv1.PgCatDatabase{
+						{Name: "app", Users: []string{"app", "reporting"}},
+						{Name: "orders", DBName: "shop", Users: []string{"shop"}, PoolSize: ptr.To(int32(20))},
+					},
+				},
+			},
+		}
+		secrets = &poolerConfig.Secrets{
+			AuthQuery: &corev1.Secret{
+				Type: corev1.SecretTypeBasicAuth,
+				Data: map[string][]byte{
+					corev1.BasicAuthUsernameKey: []byte("auth_user"),
+					corev1.BasicAuthPasswordKey: []byte(`pa"ss`),
+				},
+			},
+			ClientTLS: &corev1.Secret{
+				Data: map[string][]byte{
+					certs.TLSCertKey:       []byte("cert"),
+					certs.TLSPrivateKeyKey: []byte("key"),
+				},
+			},
+		}
+	})
+
+	It("generates the configuration file and the client certificate", func() {
+		files, err := BuildConfigurationFiles(pooler, secrets, "admin-secret")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(files).To(HaveLen(3))
+		Expect(files[poolerConfig.ClientTLSCertPath]).To(BeEquivalentTo("cert"))
+		Expect(files[poolerConfig.ClientTLSKeyPath]).To(BeEquivalentTo("key"))
+
+		content := string(files[configFileName])
+		Expect(content).To(ContainSubstring("admin_password = \"admin-secret\"\n"))
+		Expect(content).To(ContainSubstring("admin_username = \"pgcat\"\n"))
+		Expect(content).To(ContainSubstring("enable_prometheus_exporter = false\n"))
+		Expect(content).To(ContainSubstring("log_client_connections = false\n"))
+		Expect(content).To(ContainSubstring("verify_server_certificate = false\n"))
+		Expect(content).To(ContainSubstring(
+			"[pools.\"app\"]\npool_mode = \"transaction\"\ndefault_role = \"primary\"\n" +
+				"query_parser_enabled = false\nquery_parser_read_write_splitting = false\n" +
+				"primary_reads_enabled = true\n" +
+				"auth_query = \"SELECT usename, passwd FROM public.user_search('$1')\"\n" +
+				"auth_query_user = \"auth_user\"\nauth_query_password = \"pa\\\"ss\"\n"))
+		Expect(content).To(ContainSubstring(
+			"[pools.\"app\".users.0]\nusername = \"app\"\npool_size = 10\n"))
+		Expect(content).To(ContainSubstring(
+			"[pools.\"app\".users.1]\nusername = \"reporting\"\npool_size = 10\n"))
+		Expect(content).To(ContainSubstring(
+			"[pools.\"orders\".users.0]\nusername = \"shop\"\npool_size = 20\n"))
+		Expect(content).To(ContainSubstring(
+			"[pools.\"orders\".shards.0]\ndatabase = \"shop\"\nservers = [\n" +
+				"  [\"cluster-example-rw\", 5432, \"primary\"],\n]\n"))
+	})
+
+	It("honors the user parameters", func() {
+		pooler.Spec.PgCat.Parameters = map[string]string{
+			"ban_time":               "30",
+			"log_client_connections": "true",
+		}
+		files, err := BuildConfigurationFiles(pooler, secrets, "admin-secret")
+		Expect(err).ToNot(HaveOccurred())
+		content := string(files[configFileName])
+		Expect(content).To(ContainSubstring("ban_time = 30\n"))
+		Expect(content).To(ContainSubstring("log_client_connections = true\n"))
+	})
+
+	It("verifies the server certificates when requested", func() {
+		pooler.Spec.PgCat.Parameters = map[string]string{
+			"verify_server_certificate": "true",
+		}
+		files, err := BuildConfigurationFiles(pooler, secrets, "admin-secret")
+		Expect(err).ToNot(HaveOccurred())
+		content := string(files[configFileName])
+		Expect(content).To(ContainSubstring("server_tls = true\n"))
+		Expect(content).To(ContainSubstring("verify_server_certificate = true\n"))
+	})
+
+	It("doesn't let the user enable the PgCat exporter", func() {
+		pooler.Spec.PgCat.Parameters = map[string]string{
+			"enable_prometheus_exporter": "true",
+		}
+		files, err := BuildConfigurationFiles(pooler, secrets, "admin-secret")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(files[configFileName])).To(ContainSubstring("enable_prometheus_exporter = false\n"))
+	})
+
+	It("connects to the replicas for ro poolers", func() {
+		pooler.Spec.Type = apiv1.PoolerTypeRO
+		files, err := BuildConfigurationFiles(pooler, secrets, "admin-secret")
+		Expect(err).ToNot(HaveOccurred())
+		content := string(files[configFileName])
+		Expect(content).To(ContainSubstring("default_role = \"replica\"\n"))
+		Expect(content).To(ContainSubstring("  [\"cluster-example-ro\", 5432, \"replica\"],\n]\n"))
+		Expect(content).ToNot(ContainSubstring("cluster-example-rw"))
+	})
+
+	It("balances the connections among the replicas", func() {
+		pooler.Spec.Type = apiv1.PoolerTypeRO
+		pooler.Spec.ReplicaLoadBalancing = &apiv1.PoolerReplicaLoadBalancing{}
+		pooler.Status.ReplicaLoadBalancing = &apiv1.PoolerReplicaLoadBalancingStatus{
+			Members: []apiv1.PoolerReplicaMember{
+				{Name: "cluster-example-2", IP: "10.0.0.2"},
+				{Name: "cluster-example-3", IP: "10.0.0.3"},
+			},
+		}
+		files, err := BuildConfigurationFiles(pooler, secrets, "admin-secret")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(files[configFileName])).To(ContainSubstring(
+			"  [\"10.0.0.2\", 5432, \"replica\"],\n  [\"10.0.0.3\", 5432, \"replica\"],\n]\n"))
+	})
+
+	It("splits the reads and the writes", func() {
+		pooler.Spec.PgCat.ReadWriteSplitting = true
+		files, err := BuildConfigurationFiles(pooler, secrets, "admin-secret")
+		Expect(err).ToNot(HaveOccurred())
+		content := string(files[configFileName])
+		Expect(content).To(ContainSubstring("default_role = \"any\"\n" +
+			"query_parser_enabled = true\nquery_parser_read_write_splitting = true\n" +
+			"primary_reads_enabled = false\n"))
+		Expect(content).To(ContainSubstring(
+			"  [\"cluster-example-rw\", 5432, \"primary\"],\n" +
+				"  [\"cluster-example-ro\", 5432, \"replica\"],\n]\n"))
+	})
+
+	It("requires a basic-auth secret to run the authentication query", func() {
+		secrets.AuthQuery.Type = corev1.SecretTypeTLS
+		_, err := BuildConfigurationFiles(pooler, secrets, "admin-secret")
+		Expect(err).To(HaveOccurred())
+
+		secrets.AuthQuery = nil
+		_, err = BuildConfigurationFiles(pooler, secrets, "admin-secret")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("requires the PgCat configuration", func() {
+		pooler.Spec.PgCat = nil
+		_, err := BuildConfigurationFiles(pooler, secrets, "admin-secret")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,76 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// tomlLiteralRegexp matches the values that can be used in the PgCat
+// configuration without being quoted, being booleans or integers
+var tomlLiteralRegexp = regexp.MustCompile(`^(true|false|[+-]?[0-9]+)$`)
+
+// quoteTOMLString returns the passed value as a TOML basic string
+func quoteTOMLString(value string) string {
+	var result strings.Builder
+
+	result.WriteByte('"')
+	for _, r := range value {
+		switch {
+		case r == '"' || r == '\\':
+			result.WriteByte('\\')
+			result.WriteRune(r)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&result, "\\u%04X", r)
+		default:
+			result.WriteRune(r)
+		}
+	}
+	result.WriteByte('"')
+
+	return result.String()
+}
+
+// toTOMLValue converts the value of a parameter to TOML, keeping
+// booleans and integers and quoting everything else
+func toTOMLValue(value string) string {
+	if tomlLiteralRegexp.MatchString(value) {
+		return value
+	}
+
+	return quoteTOMLString(value)
+}
+
+// stringifyPgCatParameters emits the passed parameters, sorted by name,
+// to keep the configuration stable
+func stringifyPgCatParameters(parameters map[string]string) (paramsString string) {
+	keys := make([]string, 0, len(parameters))
+	for k := range parameters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		paramsString += fmt.Sprintf("%s = %s\n", k, parameters[k])
+	}
+	return paramsString
+}
@@ -0,0 +1,56 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TOML values", func() {
+	DescribeTable("quoting strings",
+		func(value, expected string) {
+			Expect(quoteTOMLString(value)).To(Equal(expected))
+		},
+		Entry("plain string", "app", `"app"`),
+		Entry("double quotes", `a"b`, `"a\"b"`),
+		Entry("backslash", `a\b`, `"a\\b"`),
+		Entry("newline", "a\nb", `"a\u000Ab"`),
+	)
+
+	DescribeTable("converting parameters",
+		func(value, expected string) {
+			Expect(toTOMLValue(value)).To(Equal(expected))
+		},
+		Entry("boolean", "true", "true"),
+		Entry("integer", "5000", "5000"),
+		Entry("negative integer", "-1", "-1"),
+		Entry("string", "random", `"random"`),
+		Entry("float-like string", "1.5", `"1.5"`),
+	)
+
+	It("emits the parameters sorted by name", func() {
+		Expect(stringifyPgCatParameters(map[string]string{
+			"port":     "5432",
+			"host":     `"0.0.0.0"`,
+			"ban_time": "60",
+		})).To(Equal("ban_time = 60\nhost = \"0.0.0.0\"\nport = 5432\n"))
+	})
+})
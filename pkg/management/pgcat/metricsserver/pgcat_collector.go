@@ -0,0 +1,142 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package metricsserver contains the collector of the metrics of a PgCat
+// instance, which are gathered from its administrative console
+package metricsserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/prometheus/client_golang/prometheus"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	pgCatConfig "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgcat/config"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pooler/metricsserver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
+)
+
+// Exporter exports the metrics of a PgCat instance
+type Exporter struct {
+	ctx     context.Context
+	Metrics *metrics
+	pool    pool.Pooler
+}
+
+// metrics here are related to the exporter itself, which is instrumented to
+// expose them
+type metrics struct {
+	CollectionsTotal   prometheus.Counter
+	PgCollectionErrors *prometheus.CounterVec
+	Error              prometheus.Gauge
+	CollectionDuration *prometheus.GaugeVec
+	PgCatUp            prometheus.Gauge
+	ShowPools          ShowPoolsMetrics
+}
+
+// NewExporter creates an exporter connecting to the administrative
+// console of PgCat via the passed connection pool
+func NewExporter(ctx context.Context, pool pool.Pooler) *Exporter {
+	return &Exporter{
+		ctx:     ctx,
+		Metrics: newMetrics(),
+		pool:    pool,
+	}
+}
+
+// newMetrics returns collector metrics
+func newMetrics() *metrics {
+	subsystem := string(apiv1.PoolerBackendPgCat)
+	return &metrics{
+		CollectionsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsserver.PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "collections_total",
+			Help:      "Total number of times PgCat was accessed for metrics.",
+		}),
+		PgCollectionErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsserver.PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "collection_errors_total",
+			Help:      "Total errors occurred accessing PgCat for metrics.",
+		}, []string{"collector"}),
+		PgCatUp: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsserver.PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "up",
+			Help:      "1 if pgcat is up, 0 otherwise.",
+		}),
+		Error: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsserver.PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "last_collection_error",
+			Help:      "1 if the last collection ended with error, 0 otherwise.",
+		}),
+		CollectionDuration: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsserver.PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "collection_duration_seconds",
+			Help:      "Collection time duration in seconds",
+		}, []string{"collector"}),
+		ShowPools: NewShowPoolsMetrics(subsystem),
+	}
+}
+
+// Describe implements prometheus.Collector, defining the Metrics we return.
+func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.Metrics.CollectionsTotal.Desc()
+	ch <- e.Metrics.Error.Desc()
+	ch <- e.Metrics.PgCatUp.Desc()
+	e.Metrics.PgCollectionErrors.Describe(ch)
+	e.Metrics.CollectionDuration.Describe(ch)
+	e.Metrics.ShowPools.Describe(ch)
+}
+
+// Collect implements prometheus.Collector, collecting the Metrics values to
+// export.
+func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	e.collectPgCatMetrics(ch)
+
+	ch <- e.Metrics.CollectionsTotal
+	ch <- e.Metrics.Error
+	ch <- e.Metrics.PgCatUp
+	e.Metrics.PgCollectionErrors.Collect(ch)
+	e.Metrics.CollectionDuration.Collect(ch)
+}
+
+func (e *Exporter) collectPgCatMetrics(ch chan<- prometheus.Metric) {
+	contextLogger := log.FromContext(e.ctx)
+
+	e.Metrics.CollectionsTotal.Inc()
+	collectionStart := time.Now()
+	defer func() {
+		e.Metrics.CollectionDuration.WithLabelValues("Collect.up").Set(time.Since(collectionStart).Seconds())
+	}()
+	db, err := e.pool.Connection(pgCatConfig.PgCatAdminDatabase)
+	if err != nil {
+		contextLogger.Error(err, "Error opening connection to PgCat")
+		e.Metrics.PgCatUp.Set(0)
+		e.Metrics.Error.Set(1)
+		return
+	}
+
+	e.collectShowPools(ch, db)
+}
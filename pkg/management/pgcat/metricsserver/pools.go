@@ -0,0 +1,168 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metricsserver
+
+import (
+	"database/sql"
+	"strconv"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pooler/metricsserver"
+)
+
+const (
+	databaseLabel = "database"
+	userLabel     = "user"
+)
+
+// showPoolsColumns are the numeric columns of the output of SHOW POOLS
+// exported as metrics, with their description
+var showPoolsColumns = map[string]string{
+	"cl_idle":       "Client connections that are idle.",
+	"cl_active":     "Client connections that are linked to server connection and can process queries.",
+	"cl_waiting":    "Client connections that have sent queries but have not yet got a server connection.",
+	"cl_cancel_req": "Client connections that have not forwarded query cancellations to the server yet.",
+	"sv_active":     "Server connections that are linked to a client.",
+	"sv_idle":       "Server connections that are unused and immediately usable for client queries.",
+	"sv_used":       "Server connections that are idle and need to be checked before being used again.",
+	"sv_tested":     "Server connections that are currently being checked.",
+	"sv_login":      "Server connections currently in the process of logging in.",
+	"maxwait":       "How long the first (oldest) client in the queue has waited, in seconds.",
+	"maxwait_us":    "Microsecond part of the maximum waiting time.",
+}
+
+// ShowPoolsMetrics contains all the SHOW POOLS Metrics, by column
+type ShowPoolsMetrics map[string]*prometheus.GaugeVec
+
+// NewShowPoolsMetrics builds the default ShowPoolsMetrics
+func NewShowPoolsMetrics(subsystem string) ShowPoolsMetrics {
+	result := make(ShowPoolsMetrics, len(showPoolsColumns))
+	for column, help := range showPoolsColumns {
+		result[column] = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsserver.PrometheusNamespace,
+			Subsystem: subsystem + "_pools",
+			Name:      column,
+			Help:      help,
+		}, []string{databaseLabel, userLabel})
+	}
+	return result
+}
+
+// Describe produces the description for all the contained Metrics
+func (r ShowPoolsMetrics) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range r {
+		metric.Describe(ch)
+	}
+}
+
+// Reset resets all the contained Metrics
+func (r ShowPoolsMetrics) Reset() {
+	for _, metric := range r {
+		metric.Reset()
+	}
+}
+
+// Collect collects all the contained Metrics
+func (r ShowPoolsMetrics) Collect(ch chan<- prometheus.Metric) {
+	for _, metric := range r {
+		metric.Collect(ch)
+	}
+}
+
+// collectShowPools exports the statistics about the pools. The columns
+// are matched by name, as they change between the versions of PgCat.
+func (e *Exporter) collectShowPools(ch chan<- prometheus.Metric, db *sql.DB) {
+	contextLogger := log.FromContext(e.ctx)
+
+	e.Metrics.ShowPools.Reset()
+	rows, err := db.Query("SHOW POOLS")
+	if err != nil {
+		contextLogger.Error(err, "Error while executing SHOW POOLS")
+		e.Metrics.PgCatUp.Set(0)
+		e.Metrics.Error.Set(1)
+		return
+	}
+
+	e.Metrics.PgCatUp.Set(1)
+	e.Metrics.Error.Set(0)
+	defer func() {
+		if err := rows.Close(); err != nil {
+			contextLogger.Error(err, "while closing rows for SHOW POOLS")
+		}
+	}()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		contextLogger.Error(err, "Error while getting the columns of SHOW POOLS")
+		e.Metrics.PgCatUp.Set(0)
+		e.Metrics.Error.Set(1)
+		return
+	}
+
+	values := make([]sql.NullString, len(columns))
+	destinations := make([]any, len(columns))
+	for idx := range values {
+		destinations[idx] = &values[idx]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(destinations...); err != nil {
+			contextLogger.Error(err, "Error while scanning SHOW POOLS")
+			e.Metrics.Error.Set(1)
+			e.Metrics.PgCollectionErrors.WithLabelValues(err.Error()).Inc()
+			continue
+		}
+
+		var database, user string
+		for idx, column := range columns {
+			switch column {
+			case databaseLabel:
+				database = values[idx].String
+			case userLabel:
+				user = values[idx].String
+			}
+		}
+
+		for idx, column := range columns {
+			metric, ok := e.Metrics.ShowPools[column]
+			if !ok || !values[idx].Valid {
+				continue
+			}
+
+			value, err := strconv.ParseFloat(values[idx].String, 64)
+			if err != nil {
+				contextLogger.Error(err, "Error while parsing SHOW POOLS", "column", column)
+				e.Metrics.Error.Set(1)
+				e.Metrics.PgCollectionErrors.WithLabelValues(err.Error()).Inc()
+				continue
+			}
+			metric.WithLabelValues(database, user).Set(value)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues(err.Error()).Inc()
+	}
+
+	e.Metrics.ShowPools.Collect(ch)
+}
@@ -0,0 +1,120 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metricsserver
+
+import (
+	"database/sql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/metricstest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Exporter", func() {
+	var (
+		db      *sql.DB
+		mock    sqlmock.Sqlmock
+		exp     *Exporter
+		ch      chan prometheus.Metric
+		columns = []string{
+			"database",
+			"user",
+			"pool_mode",
+			"cl_idle",
+			"cl_active",
+			"cl_waiting",
+			"cl_cancel_req",
+			"sv_active",
+			"sv_idle",
+			"sv_used",
+			"sv_tested",
+			"sv_login",
+			"maxwait",
+			"maxwait_us",
+		}
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		var err error
+		db, mock, err = sqlmock.New()
+		Expect(err).ShouldNot(HaveOccurred())
+
+		exp = NewExporter(ctx, fakePooler{db: db})
+		ch = make(chan prometheus.Metric, 1000)
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("exports the statistics of the pools", func() {
+		mock.ExpectQuery("SHOW POOLS").
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow("app", "app", "transaction", 1, 2, 3, 0, 4, 5, 0, 0, 0, 2, 100000).
+				AddRow("reports", "app", "transaction", 0, 1, 4, 0, 1, 0, 0, 0, 0, 0, 0))
+
+		exp.collectShowPools(ch, db)
+
+		Expect(metricstest.Value(exp.Metrics.PgCatUp)).To(BeEquivalentTo(1))
+		Expect(metricstest.Value(exp.Metrics.Error)).To(BeZero())
+		Expect(metricstest.Value(exp.Metrics.ShowPools["cl_waiting"].WithLabelValues("app", "app"))).
+			To(BeEquivalentTo(3))
+		Expect(metricstest.Value(exp.Metrics.ShowPools["cl_waiting"].WithLabelValues("reports", "app"))).
+			To(BeEquivalentTo(4))
+		Expect(metricstest.Value(exp.Metrics.ShowPools["maxwait_us"].WithLabelValues("app", "app"))).
+			To(BeEquivalentTo(100000))
+	})
+
+	It("ignores the columns it doesn't know about", func() {
+		mock.ExpectQuery("SHOW POOLS").
+			WillReturnRows(sqlmock.NewRows([]string{"database", "user", "cl_waiting", "unknown"}).
+				AddRow("app", "app", 7, "something"))
+
+		exp.collectShowPools(ch, db)
+
+		Expect(metricstest.Value(exp.Metrics.Error)).To(BeZero())
+		Expect(metricstest.Value(exp.Metrics.ShowPools["cl_waiting"].WithLabelValues("app", "app"))).
+			To(BeEquivalentTo(7))
+	})
+
+	It("reports PgCat as down when SHOW POOLS fails", func() {
+		mock.ExpectQuery("SHOW POOLS").WillReturnError(sql.ErrConnDone)
+
+		exp.collectShowPools(ch, db)
+
+		Expect(metricstest.Value(exp.Metrics.PgCatUp)).To(BeZero())
+		Expect(metricstest.Value(exp.Metrics.Error)).To(BeEquivalentTo(1))
+	})
+
+	It("counts the values that can't be parsed as errors", func() {
+		mock.ExpectQuery("SHOW POOLS").
+			WillReturnRows(sqlmock.NewRows([]string{"database", "user", "cl_waiting"}).
+				AddRow("app", "app", "error"))
+
+		exp.collectShowPools(ch, db)
+
+		Expect(metricstest.Value(exp.Metrics.Error)).To(BeEquivalentTo(1))
+		Expect(metricstest.Count(exp.Metrics.PgCollectionErrors)).To(Equal(1))
+	})
+})
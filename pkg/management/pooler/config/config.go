@@ -0,0 +1,42 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package config contains the configuration shared by the connection
+// poolers run by the instance manager
+package config
+
+import (
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+const (
+	// ConfigsDir is the directory in which all the pooler configurations are
+	ConfigsDir = postgres.ScratchDataDirectory + "/configs"
+
+	// ClientTLSCertPath is the path where the client TLS certificate
+	// is stored
+	ClientTLSCertPath = ConfigsDir + "/client-tls/tls.crt"
+
+	// ClientTLSKeyPath is the path where the client TLS private key
+	// is stored
+	ClientTLSKeyPath = ConfigsDir + "/client-tls/tls.key"
+
+	// Port is the port where the pooler will be listening
+	Port = 5432
+)
@@ -23,12 +23,13 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
-// Secrets is the set of data that is needed to compute a PgBouncer configuration
+// Secrets is the set of data that is needed to compute the configuration
+// of a connection pooler
 type Secrets struct {
 	// The secret containing the credentials to be used to execute the auth_query queries.
 	AuthQuery *corev1.Secret
 
-	// The secret containing the credentials for PgBouncer to authenticate
+	// The secret containing the credentials for the pooler to authenticate
 	// against PostgreSQL server.
 	ServerTLS *corev1.Secret
 
@@ -40,8 +41,23 @@ type Secrets struct {
 
 	// The CA that will be used to validate the connections to PostgreSQL
 	ServerCA *corev1.Secret
+
+	// The secrets needed to connect to the clusters, other than the
+	// referenced one, serving the databases of the pooler, by cluster name
+	Clusters map[string]*ClusterSecrets
+}
+
+// ClusterSecrets is the set of secrets needed to connect to a
+// cluster serving some of the databases of the pooler
+type ClusterSecrets struct {
+	// The CA that will be used to validate the connections to the cluster
+	ServerCA *corev1.Secret
+
+	// The secret containing the credentials to be used to execute
+	// the auth_query queries against the cluster
+	AuthQuery *corev1.Secret
 }
 
 // ConfigurationFiles is a set of configuration files that are needed for
-// pgbouncer to work
+// the pooler to work, by path
 type ConfigurationFiles map[string][]byte
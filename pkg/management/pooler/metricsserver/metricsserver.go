@@ -17,7 +17,8 @@ limitations under the License.
 SPDX-License-Identifier: Apache-2.0
 */
 
-// Package metricsserver contains the web server powering metrics
+// Package metricsserver contains the web server exposing the metrics
+// of a connection pooler
 package metricsserver
 
 import (
@@ -31,30 +32,35 @@ import (
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
 )
 
+// PrometheusNamespace is the namespace of the metrics exposed
+// by the connection poolers
+const PrometheusNamespace = "cnpg"
+
 var (
 	// metricsServer is the HTTP metrics server instance
 	server *http.Server
 
 	// registry is the Prometheus query registry
 	registry *prometheus.Registry
-
-	// exporter is the exporter for predefined queries and for
-	// custom ones
-	exporter *Exporter
 )
 
-// Setup configure the web statusServer for a certain PostgreSQL instance, and
-// must be invoked before starting the real web statusServer
-func Setup(ctx context.Context) error {
-	// create the exporter and serve it on the /metrics endpoint
+// PoolsMetricsPrefix is the prefix of the names of the metrics about
+// the pools, which every pooler backend exports under its own subsystem
+func PoolsMetricsPrefix(backend apiv1.PoolerBackend) string {
+	return fmt.Sprintf("%s_%s_pools_", PrometheusNamespace, backend)
+}
+
+// Setup configures the web server exposing the metrics gathered by the
+// passed collector, and must be invoked before starting the real web server
+func Setup(collector prometheus.Collector) error {
 	registry = prometheus.NewRegistry()
-	exporter = NewExporter(ctx)
-	if err := registry.Register(exporter); err != nil {
-		return fmt.Errorf("while registering PgBouncer exporters: %w", err)
+	if err := registry.Register(collector); err != nil {
+		return fmt.Errorf("while registering pooler exporters: %w", err)
 	}
 	if err := registry.Register(collectors.NewGoCollector()); err != nil {
 		return fmt.Errorf("while registering Go exporters: %w", err)
@@ -68,7 +74,7 @@ func ListenAndServe(tlsConfig *tls.Config) error {
 	serveMux.Handle(url.PathMetrics, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
 
 	server = &http.Server{
-		Addr:              fmt.Sprintf(":%d", url.PgBouncerMetricsPort),
+		Addr:              fmt.Sprintf(":%d", url.PoolerMetricsPort),
 		Handler:           serveMux,
 		ReadTimeout:       webserver.DefaultReadTimeout,
 		ReadHeaderTimeout: webserver.DefaultReadHeaderTimeout,
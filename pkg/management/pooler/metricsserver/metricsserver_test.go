@@ -20,6 +20,10 @@ SPDX-License-Identifier: Apache-2.0
 package metricsserver
 
 import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -29,25 +33,30 @@ var _ = Describe("MetricsServer", func() {
 		BeforeEach(func() {
 			server = nil
 			registry = nil
-			exporter = nil
 		})
 
-		It("should register exporters and collectors successfully", func(ctx SpecContext) {
-			err := Setup(ctx)
-			Expect(err).NotTo(HaveOccurred())
+		It("should register the pooler collector and the Go collector", func() {
+			up := prometheus.NewGauge(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: "pgcat",
+				Name:      "up",
+			})
+			Expect(Setup(up)).To(Succeed())
 
 			mfs, err := registry.Gather()
 			Expect(err).NotTo(HaveOccurred())
-			Expect(mfs).NotTo(BeEmpty())
-
-			Expect(exporter.Metrics.CollectionsTotal).NotTo(BeNil())
-			Expect(exporter.Metrics.PgCollectionErrors).NotTo(BeNil())
-			Expect(exporter.Metrics.Error).NotTo(BeNil())
-			Expect(exporter.Metrics.CollectionDuration).NotTo(BeNil())
-			Expect(exporter.Metrics.PgbouncerUp).NotTo(BeNil())
-			Expect(exporter.Metrics.ShowLists).NotTo(BeNil())
-			Expect(exporter.Metrics.ShowPools).NotTo(BeNil())
-			Expect(exporter.Metrics.ShowStats).NotTo(BeNil())
+			names := make([]string, 0, len(mfs))
+			for _, mf := range mfs {
+				names = append(names, mf.GetName())
+			}
+			Expect(names).To(ContainElements("cnpg_pgcat_up", "go_goroutines"))
+		})
+	})
+
+	Describe("PoolsMetricsPrefix", func() {
+		It("includes the backend in the prefix", func() {
+			Expect(PoolsMetricsPrefix(apiv1.PoolerBackendPgBouncer)).To(Equal("cnpg_pgbouncer_pools_"))
+			Expect(PoolsMetricsPrefix(apiv1.PoolerBackendPgCat)).To(Equal("cnpg_pgcat_pools_"))
 		})
 	})
 })
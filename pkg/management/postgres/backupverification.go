@@ -0,0 +1,175 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/cloudnative-pg/machinery/pkg/fileutils"
+	"github.com/cloudnative-pg/machinery/pkg/log"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/configfile"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
+)
+
+const (
+	pgAmcheckName = "pg_amcheck"
+
+	// maxVerificationCheckMessageLength is the maximum length of the
+	// message recorded for a failed check in the backup status
+	maxVerificationCheckMessageLength = 1024
+)
+
+// VerifyRestoredBackup starts the instance restored from a backup, waits
+// for it to be promoted, and runs pg_amcheck and the passed assertions,
+// returning the result of each check. The instance is detached from the
+// cluster before being started: WAL archiving and SSL are disabled and
+// only local connections are allowed
+func (info InitInfo) VerifyRestoredBackup(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	assertions []apiv1.BackupVerificationAssertion,
+) ([]apiv1.BackupVerificationCheckStatus, error) {
+	if err := info.detachRestoredInstance(ctx); err != nil {
+		return nil, err
+	}
+
+	instance := info.GetInstance(cluster)
+	checks := make([]apiv1.BackupVerificationCheckStatus, 0, len(assertions)+1)
+	err := instance.WithActiveInstance(func() error {
+		db, err := instance.GetSuperUserDB()
+		if err != nil {
+			return err
+		}
+
+		if err := waitUntilRecoveryFinishes(db); err != nil {
+			return fmt.Errorf("while waiting for PostgreSQL to stop recovery mode: %w", err)
+		}
+
+		checks = append(checks, runAmcheck(ctx, instance.ConnectionPool().GetDsn("postgres")))
+		for _, assertion := range assertions {
+			checks = append(checks, runVerificationAssertion(ctx, instance, assertion))
+		}
+
+		return nil
+	})
+
+	return checks, err
+}
+
+// detachRestoredInstance ensures that the restored instance starts as a
+// primary without archiving WAL files, and that only local connections
+// are allowed
+func (info InitInfo) detachRestoredInstance(ctx context.Context) error {
+	if err := fileutils.RemoveFile(path.Join(info.PgData, "standby.signal")); err != nil {
+		return fmt.Errorf("while removing standby.signal: %w", err)
+	}
+
+	configuration := configfile.RenderPostgresConfiguration(map[string]string{
+		"archive_mode": "off",
+		"ssl":          "off",
+	})
+	if _, err := fileutils.WriteStringToFile(
+		path.Join(info.PgData, constants.PostgresqlOverrideConfigurationFile),
+		configuration,
+	); err != nil {
+		return fmt.Errorf("while writing %s: %w", constants.PostgresqlOverrideConfigurationFile, err)
+	}
+
+	return info.WriteRestoreHbaConf(ctx)
+}
+
+// runAmcheck checks the integrity of every database of the instance
+// reachable with the passed connection string with pg_amcheck
+func runAmcheck(ctx context.Context, connectionString string) apiv1.BackupVerificationCheckStatus {
+	contextLogger := log.FromContext(ctx)
+
+	options := []string{
+		"--all",
+		"--install-missing",
+		"--maintenance-db", connectionString,
+	}
+
+	contextLogger.Info("Running pg_amcheck", "options", options)
+	pgAmcheckCmd := exec.Command(pgAmcheckName, options...) // #nosec
+	output, err := pgAmcheckCmd.CombinedOutput()
+	if err != nil {
+		contextLogger.Info("pg_amcheck failed", "output", string(output), "error", err.Error())
+		message := strings.TrimSpace(string(output))
+		if message == "" {
+			message = err.Error()
+		}
+		return newFailedVerificationCheck(apiv1.BackupVerificationAmcheckName, message)
+	}
+
+	return apiv1.BackupVerificationCheckStatus{Name: apiv1.BackupVerificationAmcheckName, Passed: true}
+}
+
+// runVerificationAssertion runs the query of the passed assertion,
+// which passes when it returns true
+func runVerificationAssertion(
+	ctx context.Context,
+	instance *Instance,
+	assertion apiv1.BackupVerificationAssertion,
+) apiv1.BackupVerificationCheckStatus {
+	contextLogger := log.FromContext(ctx).WithValues("assertionName", assertion.Name)
+
+	database := assertion.Database
+	if database == "" {
+		database = "postgres"
+	}
+
+	db, err := instance.ConnectionPool().Connection(database)
+	if err != nil {
+		return newFailedVerificationCheck(assertion.Name, err.Error())
+	}
+
+	var result bool
+	if err := db.QueryRowContext(ctx, assertion.Query).Scan(&result); err != nil {
+		contextLogger.Info("Assertion failed", "error", err.Error())
+		return newFailedVerificationCheck(assertion.Name, err.Error())
+	}
+
+	if !result {
+		contextLogger.Info("Assertion does not hold")
+		return newFailedVerificationCheck(assertion.Name, "the query returned false")
+	}
+
+	return apiv1.BackupVerificationCheckStatus{Name: assertion.Name, Passed: true}
+}
+
+// newFailedVerificationCheck creates the status of a failed check,
+// truncating its message
+func newFailedVerificationCheck(name, message string) apiv1.BackupVerificationCheckStatus {
+	if len(message) > maxVerificationCheckMessageLength {
+		message = message[:maxVerificationCheckMessageLength]
+	}
+
+	return apiv1.BackupVerificationCheckStatus{
+		Name:    name,
+		Passed:  false,
+		Message: message,
+	}
+}
@@ -0,0 +1,41 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package postgres
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("backup verification", func() {
+	It("records the message of a failed check", func() {
+		check := newFailedVerificationCheck("orders", "the query returned false")
+		Expect(check.Name).To(Equal("orders"))
+		Expect(check.Passed).To(BeFalse())
+		Expect(check.Message).To(Equal("the query returned false"))
+	})
+
+	It("truncates long messages", func() {
+		check := newFailedVerificationCheck("pg_amcheck", strings.Repeat("x", 2*maxVerificationCheckMessageLength))
+		Expect(check.Message).To(HaveLen(maxVerificationCheckMessageLength))
+	})
+})
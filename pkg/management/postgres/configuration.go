@@ -350,6 +350,7 @@ func createPostgresqlConfiguration(
 		IsWalArchivingDisabled:           utils.IsWalArchivingDisabled(&cluster.ObjectMeta),
 		IsAlterSystemEnabled:             cluster.Spec.PostgresConfiguration.EnableAlterSystem,
 		SynchronousStandbyNames:          replication.GetSynchronousStandbyNames(ctx, cluster),
+		IsWalSummarizationEnabled:        cluster.Spec.Backup.IsPgBaseBackupConfigured(),
 	}
 
 	if preserveUserSettings {
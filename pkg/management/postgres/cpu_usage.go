@@ -0,0 +1,73 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package postgres
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/utils/ptr"
+)
+
+// minCPUUsageSamplingInterval is the minimum time between the two
+// samples of the CPU time used to compute the CPU usage. Status requests
+// closer than this report the previously computed value
+const minCPUUsageSamplingInterval = 5 * time.Second
+
+// cpuUsageSampler computes the CPU usage of the instance container
+// from the CPU time consumed between two samples
+type cpuUsageSampler struct {
+	mu sync.Mutex
+
+	lastCPUTime    time.Duration
+	lastSampleTime time.Time
+	millicores     *int64
+}
+
+// update stores a new sample of the CPU time consumed by the container and
+// returns the CPU usage in millicores, or nil if it is not known yet
+func (s *cpuUsageSampler) update(cpuTime time.Duration, now time.Time) *int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.lastSampleTime.IsZero() {
+		elapsed := now.Sub(s.lastSampleTime)
+		if elapsed < minCPUUsageSamplingInterval {
+			return s.getMillicores()
+		}
+
+		// The CPU time goes backwards only when the cgroup is recreated
+		if cpuTime >= s.lastCPUTime {
+			s.millicores = ptr.To(int64((cpuTime - s.lastCPUTime) * 1000 / elapsed))
+		}
+	}
+
+	s.lastCPUTime = cpuTime
+	s.lastSampleTime = now
+	return s.getMillicores()
+}
+
+func (s *cpuUsageSampler) getMillicores() *int64 {
+	if s.millicores == nil {
+		return nil
+	}
+
+	return ptr.To(*s.millicores)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package postgres
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CPU usage sampler", func() {
+	It("computes the CPU usage between two samples", func() {
+		var sampler cpuUsageSampler
+		now := time.Now()
+
+		Expect(sampler.update(10*time.Second, now)).To(BeNil())
+
+		// A sample too close to the previous one is not considered
+		Expect(sampler.update(11*time.Second, now.Add(time.Second))).To(BeNil())
+
+		usage := sampler.update(15*time.Second, now.Add(10*time.Second))
+		Expect(usage).ToNot(BeNil())
+		Expect(*usage).To(BeEquivalentTo(500))
+
+		usage = sampler.update(16*time.Second, now.Add(11*time.Second))
+		Expect(usage).ToNot(BeNil())
+		Expect(*usage).To(BeEquivalentTo(500))
+
+		usage = sampler.update(35*time.Second, now.Add(20*time.Second))
+		Expect(usage).ToNot(BeNil())
+		Expect(*usage).To(BeEquivalentTo(2000))
+	})
+
+	It("keeps the previous value when the CPU time goes backwards", func() {
+		var sampler cpuUsageSampler
+		now := time.Now()
+
+		sampler.update(10*time.Second, now)
+		Expect(*sampler.update(20*time.Second, now.Add(10*time.Second))).To(BeEquivalentTo(1000))
+		Expect(*sampler.update(time.Second, now.Add(20*time.Second))).To(BeEquivalentTo(1000))
+		Expect(*sampler.update(6*time.Second, now.Add(30*time.Second))).To(BeEquivalentTo(500))
+	})
+})
@@ -125,6 +125,11 @@ type InitInfo struct {
 
 	// TablespaceMapFile holds the content returned by pg_stop_backup. Needed for a hot backup restore
 	TablespaceMapFile []byte
+
+	// BackupToVerify is the name of the backup to be restored, instead of
+	// the recovery source of the cluster, in a throwaway instance that
+	// is used to verify it
+	BackupToVerify string
 }
 
 // EnsureTargetDirectoriesDoNotExist ensures that the target data and WAL directories do not exist.
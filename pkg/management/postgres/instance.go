@@ -233,6 +233,10 @@ type Instance struct {
 
 	serverCertificateHandler serverCertificateHandler
 
+	// cpuUsage computes the CPU usage of the instance container
+	// reported in the instance status
+	cpuUsage cpuUsageSampler
+
 	// cluster is the cached cluster this instance belongs to.
 	// Access via GetClusterOrDefault() which returns an empty cluster if nil.
 	// It is read from HTTP handler goroutines (e.g. the remote webserver
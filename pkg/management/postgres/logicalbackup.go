@@ -0,0 +1,228 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package postgres
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/cloudnative-pg/machinery/pkg/execlog"
+	"github.com/cloudnative-pg/machinery/pkg/log"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/configfile"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+)
+
+const (
+	pgDumpName    = "pg_dump"
+	pgRestoreName = "pg_restore"
+)
+
+// GetLogicalBackupPath returns the path, relative to the root of the
+// logical backup volume, where the dumps of a backup are stored
+func GetLogicalBackupPath(clusterName, backupName string) string {
+	return filepath.Join(clusterName, backupName)
+}
+
+// GetLogicalBackupDirectory returns the directory where the dumps of a
+// backup taken with the logical method are found, given its status
+func GetLogicalBackupDirectory(backupStatus *apiv1.BackupLogicalStatus) string {
+	return filepath.Join(specs.LogicalBackupVolumePath, backupStatus.Path)
+}
+
+// getLogicalBackupFileName returns the name of the file containing the
+// dump of the passed database. Database names are escaped, as they may
+// contain characters that are not allowed in file names
+func getLogicalBackupFileName(database string) string {
+	return url.PathEscape(database) + ".dump"
+}
+
+// ListDatabasesToDump returns the databases exported by a logical backup
+// when no explicit list is given: every database accepting connections,
+// except templates and the postgres database
+func ListDatabasesToDump(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx,
+		`SELECT datname FROM pg_catalog.pg_database
+		WHERE datallowconn
+		AND NOT datistemplate
+		AND datname != 'postgres'
+		ORDER BY datname`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var databases []string
+	for rows.Next() {
+		var database string
+		if err := rows.Scan(&database); err != nil {
+			return nil, err
+		}
+		databases = append(databases, database)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return databases, nil
+}
+
+// DumpDatabase exports the passed database with pg_dump, in the custom
+// format, into the backup directory. The connection parameters are taken
+// from the environment
+func DumpDatabase(
+	ctx context.Context,
+	database string,
+	backupDirectory string,
+) (*apiv1.LogicalBackupDatabaseStatus, error) {
+	contextLogger := log.FromContext(ctx)
+
+	fileName := getLogicalBackupFileName(database)
+	filePath := filepath.Join(backupDirectory, fileName)
+	options := []string{
+		"-Fc",
+		"-f", filePath,
+		"-d", configfile.CreateConnectionString(map[string]string{"dbname": database}),
+		"-v",
+	}
+
+	contextLogger.Info("Running pg_dump", "databaseName", database, "options", options)
+	pgDumpCmd := exec.Command(pgDumpName, options...) // #nosec
+	if err := execlog.RunStreaming(pgDumpCmd, pgDumpName); err != nil {
+		return nil, fmt.Errorf("error in pg_dump of database %s, %w", database, err)
+	}
+
+	size, checksum, err := ComputeFileChecksum(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiv1.LogicalBackupDatabaseStatus{
+		Name:     database,
+		FileName: fileName,
+		Size:     size,
+		Checksum: checksum,
+	}, nil
+}
+
+// ComputeFileChecksum returns the size and the hex encoded
+// SHA-256 checksum of the passed file
+func ComputeFileChecksum(filePath string) (int64, string, error) {
+	file, err := os.Open(filePath) // #nosec
+	if err != nil {
+		return 0, "", fmt.Errorf("while opening %s: %w", filePath, err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return 0, "", fmt.Errorf("while reading %s: %w", filePath, err)
+	}
+
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// VerifyLogicalBackupDump checks that the dump of a database is
+// still matching the size and checksum recorded in the backup
+func VerifyLogicalBackupDump(filePath string, dump *apiv1.LogicalBackupDatabaseStatus) error {
+	size, checksum, err := ComputeFileChecksum(filePath)
+	if err != nil {
+		return err
+	}
+
+	if size != dump.Size || checksum != dump.Checksum {
+		return fmt.Errorf("the dump of database %s does not match the recorded size and checksum", dump.Name)
+	}
+
+	return nil
+}
+
+// buildPgRestoreOptions returns the pg_restore command line used to restore
+// a dump into the database reachable with the passed connection string. The
+// restored objects are owned by the passed role, and the ones already
+// existing are dropped first, so the same database can be restored again
+func buildPgRestoreOptions(
+	connectionString string,
+	owner string,
+	filePath string,
+	schemas []string,
+	tables []string,
+) []string {
+	options := make([]string, 0, 10+2*len(schemas)+2*len(tables))
+	options = append(options,
+		"-U", "postgres",
+		"-d", connectionString,
+		"--no-owner",
+		"--no-privileges",
+		fmt.Sprintf("--role=%s", owner),
+		"--single-transaction",
+		"--exit-on-error",
+		"--clean",
+		"--if-exists",
+	)
+	for _, schema := range schemas {
+		options = append(options, "-n", schema)
+	}
+	for _, table := range tables {
+		options = append(options, "-t", table)
+	}
+	options = append(options, filePath)
+
+	return options
+}
+
+// RestoreDatabaseDump restores a dump taken with the logical backup method
+// in a single transaction, optionally limiting it to the passed schemas
+// and tables
+func RestoreDatabaseDump(
+	ctx context.Context,
+	connectionString string,
+	owner string,
+	filePath string,
+	schemas []string,
+	tables []string,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	options := buildPgRestoreOptions(connectionString, owner, filePath, schemas, tables)
+	contextLogger.Info("Running pg_restore", "options", options)
+
+	pgRestoreCmd := exec.Command(pgRestoreName, options...) // #nosec
+	if err := execlog.RunStreaming(pgRestoreCmd, pgRestoreName); err != nil {
+		return fmt.Errorf("error while executing pg_restore, %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("logical backups", func() {
+	It("escapes the database name in the dump file name", func() {
+		Expect(getLogicalBackupFileName("app")).To(Equal("app.dump"))
+		Expect(getLogicalBackupFileName("my/db")).To(Equal("my%2Fdb.dump"))
+	})
+
+	It("builds the options to restore a whole dump", func() {
+		options := buildPgRestoreOptions("dbname=app", "app", "/dumps/app.dump", nil, nil)
+		Expect(options).To(Equal([]string{
+			"-U", "postgres",
+			"-d", "dbname=app",
+			"--no-owner",
+			"--no-privileges",
+			"--role=app",
+			"--single-transaction",
+			"--exit-on-error",
+			"--clean",
+			"--if-exists",
+			"/dumps/app.dump",
+		}))
+	})
+
+	It("builds the options to restore a subset of a dump", func() {
+		options := buildPgRestoreOptions("dbname=app", "app", "/dumps/app.dump",
+			[]string{"sales"}, []string{"orders", "customers"})
+		Expect(options).To(ContainElements("-n", "sales", "-t", "orders", "customers"))
+		Expect(options[len(options)-1]).To(Equal("/dumps/app.dump"))
+	})
+
+	It("verifies the size and checksum of a dump", func() {
+		filePath := filepath.Join(GinkgoT().TempDir(), "app.dump")
+		Expect(os.WriteFile(filePath, []byte("PGDMP"), 0o600)).To(Succeed())
+
+		size, checksum, err := ComputeFileChecksum(filePath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(size).To(BeEquivalentTo(5))
+
+		dump := &apiv1.LogicalBackupDatabaseStatus{Name: "app", Size: size, Checksum: checksum}
+		Expect(VerifyLogicalBackupDump(filePath, dump)).To(Succeed())
+
+		Expect(os.WriteFile(filePath, []byte("PGDMP-tampered"), 0o600)).To(Succeed())
+		Expect(VerifyLogicalBackupDump(filePath, dump)).ToNot(Succeed())
+	})
+})
@@ -44,6 +44,7 @@ type LogPipe struct {
 	fileName        string
 	record          CSVRecordParser
 	fieldsValidator FieldsValidator
+	writer          RecordWriter
 
 	initialized *concurrency.Executed
 	exited      *concurrency.Executed
@@ -59,14 +60,31 @@ type FieldsValidator func(int) *ErrFieldCountExtended
 func NewLogPipe() *LogPipe {
 	return &LogPipe{
 		fileName:        filepath.Join(postgres.LogPath, postgres.LogFileName+".csv"),
-		record:          NewPgAuditLoggingDecorator(),
+		record:          NewSlowQueryLoggingDecorator(),
 		fieldsValidator: LogFieldValidator,
+		writer:          &LogRecordWriter{},
 
 		initialized: concurrency.NewExecuted(),
 		exited:      concurrency.NewExecuted(),
 	}
 }
 
+// WithSlowQueryRouter makes the log pipe route the statement duration
+// and the auto_explain records through the passed router, which
+// forwards every other record to the instance manager logger
+func (p *LogPipe) WithSlowQueryRouter(router *SlowQueryRouter) *LogPipe {
+	router.next = p.writer
+	p.writer = router
+	return p
+}
+
+// WithRecordWriter makes the log pipe send the parsed records to the
+// passed writer too, in addition to the instance manager logger
+func (p *LogPipe) WithRecordWriter(writer RecordWriter) *LogPipe {
+	p.writer = MultiRecordWriter{p.writer, writer}
+	return p
+}
+
 // GetInitializedCondition returns the condition that can be checked in order to
 // be sure initialization has been done
 func (p *LogPipe) GetInitializedCondition() *concurrency.Executed {
@@ -157,7 +175,7 @@ func (p *LogPipe) collectLogsFromFile(ctx context.Context) error {
 	// the cancellation signal happened
 	go func() {
 		defer close(errChan)
-		errChan <- p.streamLogFromCSVFile(ctx, f, &LogRecordWriter{})
+		errChan <- p.streamLogFromCSVFile(ctx, f, p.writer)
 	}()
 	select {
 	case <-ctx.Done():
@@ -0,0 +1,172 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logpipe
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// StatementDurationRecordName is the value of the logger field for
+	// the statement duration records, emitted by PostgreSQL when
+	// log_min_duration_statement or log_duration are set
+	StatementDurationRecordName = "slow_query"
+
+	// AutoExplainRecordName is the value of the logger field for the
+	// execution plans logged by auto_explain
+	AutoExplainRecordName = "auto_explain"
+)
+
+var (
+	// durationRegex matches the messages like
+	// "duration: 1.234 ms  statement: SELECT 1" and "duration: 1.234 ms"
+	durationRegex = regexp.MustCompile(
+		`(?s)^duration: ([0-9]+(?:\.[0-9]+)?) ms(?:  ((?:statement|execute|parse|bind)[^:]*): (.*))?$`)
+
+	// autoExplainRegex matches the messages like
+	// "duration: 1.234 ms  plan:\n{...}"
+	autoExplainRegex = regexp.MustCompile(`(?s)^duration: ([0-9]+(?:\.[0-9]+)?) ms  plan:\s*(.*)$`)
+)
+
+// StatementDurationRecord stores a PostgreSQL log record reporting
+// the duration of a statement
+type StatementDurationRecord struct {
+	*LoggingRecord
+	Duration *StatementDuration `json:"duration,omitempty"`
+}
+
+// GetName implements the NamedRecord interface
+func (r *StatementDurationRecord) GetName() string {
+	return StatementDurationRecordName
+}
+
+// StatementDuration stores the fields parsed from a statement duration message
+type StatementDuration struct {
+	// DurationMs is the duration of the statement in milliseconds
+	DurationMs float64 `json:"duration_ms"`
+
+	// Kind is the kind of the logged operation, like "statement" or
+	// "execute <unnamed>", if the statement text has been logged
+	Kind string `json:"kind,omitempty"`
+
+	// Statement is the text of the statement, if logged
+	Statement string `json:"statement,omitempty"`
+}
+
+// AutoExplainRecord stores a PostgreSQL log record containing the
+// execution plan of a statement logged by auto_explain
+type AutoExplainRecord struct {
+	*LoggingRecord
+	Explain *AutoExplainPlan `json:"explain,omitempty"`
+}
+
+// GetName implements the NamedRecord interface
+func (r *AutoExplainRecord) GetName() string {
+	return AutoExplainRecordName
+}
+
+// AutoExplainPlan stores the fields parsed from an auto_explain message
+type AutoExplainPlan struct {
+	// DurationMs is the duration of the statement in milliseconds
+	DurationMs float64 `json:"duration_ms"`
+
+	// QueryText is the text of the statement, as reported in the plan
+	QueryText string `json:"query_text,omitempty"`
+
+	// Plan is the execution plan, when auto_explain.log_format is set to json
+	Plan json.RawMessage `json:"plan,omitempty"`
+
+	// PlanText is the execution plan, when auto_explain.log_format
+	// is set to a format other than json
+	PlanText string `json:"plan_text,omitempty"`
+}
+
+// SlowQueryLoggingDecorator recognizes the statement duration and the
+// auto_explain records among the ones parsed by the pgaudit decorator
+type SlowQueryLoggingDecorator struct {
+	*PgAuditLoggingDecorator
+	statementDuration *StatementDurationRecord
+	autoExplain       *AutoExplainRecord
+}
+
+// NewSlowQueryLoggingDecorator builds SlowQueryLoggingDecorator
+func NewSlowQueryLoggingDecorator() *SlowQueryLoggingDecorator {
+	auditDecorator := NewPgAuditLoggingDecorator()
+	return &SlowQueryLoggingDecorator{
+		PgAuditLoggingDecorator: auditDecorator,
+		statementDuration: &StatementDurationRecord{
+			LoggingRecord: auditDecorator.LoggingRecord,
+			Duration:      &StatementDuration{},
+		},
+		autoExplain: &AutoExplainRecord{
+			LoggingRecord: auditDecorator.LoggingRecord,
+			Explain:       &AutoExplainPlan{},
+		},
+	}
+}
+
+// FromCSV implements the CSVRecordParser interface, parsing a LoggingRecord and then
+// recognizing the statement duration and the auto_explain messages
+func (r *SlowQueryLoggingDecorator) FromCSV(content []string) NamedRecord {
+	result := r.PgAuditLoggingDecorator.FromCSV(content)
+	record, ok := result.(*LoggingRecord)
+	if !ok {
+		return result
+	}
+
+	if matches := autoExplainRegex.FindStringSubmatch(record.Message); matches != nil {
+		r.autoExplain.Explain.fromMessage(matches[1], matches[2])
+		record.Message = ""
+		return r.autoExplain
+	}
+
+	if matches := durationRegex.FindStringSubmatch(record.Message); matches != nil {
+		durationMs, _ := strconv.ParseFloat(matches[1], 64)
+		r.statementDuration.Duration.DurationMs = durationMs
+		r.statementDuration.Duration.Kind = matches[2]
+		r.statementDuration.Duration.Statement = matches[3]
+		record.Message = ""
+		return r.statementDuration
+	}
+
+	return record
+}
+
+func (p *AutoExplainPlan) fromMessage(duration, plan string) {
+	p.DurationMs, _ = strconv.ParseFloat(duration, 64)
+	p.QueryText = ""
+	p.Plan = nil
+	p.PlanText = ""
+
+	plan = strings.TrimSpace(plan)
+	var parsedPlan struct {
+		QueryText string `json:"Query Text"`
+	}
+	if err := json.Unmarshal([]byte(plan), &parsedPlan); err != nil {
+		p.PlanText = plan
+		return
+	}
+
+	p.QueryText = parsedPlan.QueryText
+	p.Plan = json.RawMessage(plan)
+}
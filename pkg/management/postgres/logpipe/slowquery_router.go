@@ -0,0 +1,174 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logpipe
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// SlowQueryLogFileName is the name of the file where the statement
+// duration and the auto_explain records are written, when requested
+const SlowQueryLogFileName = "slow_queries.json"
+
+// SlowQueryDestination is where the statement duration and the
+// auto_explain records are written
+type SlowQueryDestination string
+
+const (
+	// SlowQueryDestinationStdout writes the records to the instance
+	// manager logger, together with the other PostgreSQL logs
+	SlowQueryDestinationStdout SlowQueryDestination = "stdout"
+
+	// SlowQueryDestinationFile writes the records, as JSON lines, to a
+	// dedicated file in the log directory
+	SlowQueryDestinationFile SlowQueryDestination = "file"
+)
+
+// SlowQueryRouter implements the RecordWriter interface, routing the
+// statement duration and the auto_explain records to the configured
+// destination, and every other record to the next writer
+type SlowQueryRouter struct {
+	next     RecordWriter
+	fileName string
+
+	mu          sync.Mutex
+	destination SlowQueryDestination
+	maxFileSize int64
+	file        *os.File
+	fileSize    int64
+}
+
+// NewSlowQueryRouter creates a new router writing every record to
+// the instance manager logger until configured otherwise
+func NewSlowQueryRouter() *SlowQueryRouter {
+	return &SlowQueryRouter{
+		next:        &LogRecordWriter{},
+		fileName:    filepath.Join(postgres.LogPath, SlowQueryLogFileName),
+		destination: SlowQueryDestinationStdout,
+	}
+}
+
+// Configure sets the destination of the statement duration and the
+// auto_explain records. When writing to a file, it is rotated once it
+// exceeds maxFileSize bytes, keeping only the previous one.
+func (r *SlowQueryRouter) Configure(destination SlowQueryDestination, maxFileSize int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.maxFileSize = maxFileSize
+	if destination == r.destination {
+		return nil
+	}
+
+	switch destination {
+	case SlowQueryDestinationStdout:
+		r.destination = destination
+		return r.closeFile()
+	case SlowQueryDestinationFile:
+		r.destination = destination
+		return nil
+	default:
+		return fmt.Errorf("unknown slow query log destination: %s", destination)
+	}
+}
+
+// Write implements the RecordWriter interface
+func (r *SlowQueryRouter) Write(record NamedRecord) {
+	switch record.(type) {
+	case *StatementDurationRecord, *AutoExplainRecord:
+	default:
+		r.next.Write(record)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.destination != SlowQueryDestinationFile {
+		r.next.Write(record)
+		return
+	}
+
+	if err := r.writeToFile(record); err != nil {
+		log.Error(err, "while writing to the slow query log, falling back to the standard output",
+			"fileName", r.fileName)
+		r.next.Write(record)
+	}
+}
+
+// writeToFile appends the record to the slow query log file as a
+// JSON line, rotating the file if needed
+func (r *SlowQueryRouter) writeToFile(record NamedRecord) error {
+	line, err := json.Marshal(struct {
+		Logger string      `json:"logger"`
+		Record NamedRecord `json:"record"`
+	}{
+		Logger: record.GetName(),
+		Record: record,
+	})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	if r.file != nil && r.maxFileSize > 0 && r.fileSize+int64(len(line)) > r.maxFileSize {
+		if err := r.closeFile(); err != nil {
+			return err
+		}
+		if err := os.Rename(r.fileName, r.fileName+".1"); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	if r.file == nil {
+		file, err := os.OpenFile(r.fileName, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600) //nolint:gosec
+		if err != nil {
+			return err
+		}
+		info, err := file.Stat()
+		if err != nil {
+			_ = file.Close()
+			return err
+		}
+		r.file, r.fileSize = file, info.Size()
+	}
+
+	written, err := r.file.Write(line)
+	r.fileSize += int64(written)
+	return err
+}
+
+func (r *SlowQueryRouter) closeFile() error {
+	if r.file == nil {
+		return nil
+	}
+
+	err := r.file.Close()
+	r.file, r.fileSize = nil, 0
+	return err
+}
@@ -0,0 +1,121 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logpipe
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Slow query router", func() {
+	var (
+		spy      *SpyRecordWriter
+		router   *SlowQueryRouter
+		fileName string
+	)
+
+	durationRecord := func(statement string) *StatementDurationRecord {
+		return &StatementDurationRecord{
+			LoggingRecord: &LoggingRecord{DatabaseName: "app"},
+			Duration:      &StatementDuration{DurationMs: 1500, Kind: "statement", Statement: statement},
+		}
+	}
+
+	BeforeEach(func() {
+		fileName = filepath.Join(GinkgoT().TempDir(), SlowQueryLogFileName)
+		spy = &SpyRecordWriter{}
+		router = NewSlowQueryRouter()
+		router.fileName = fileName
+		router.next = spy
+		DeferCleanup(func() {
+			Expect(router.closeFile()).To(Succeed())
+		})
+	})
+
+	It("writes every record to the next writer by default", func() {
+		router.Write(durationRecord("SELECT 1"))
+		router.Write(&LoggingRecord{Message: "checkpoint starting: time"})
+		Expect(spy.records).To(HaveLen(2))
+		Expect(fileName).ToNot(BeAnExistingFile())
+	})
+
+	It("rejects unknown destinations", func() {
+		Expect(router.Configure("syslog", 0)).ToNot(Succeed())
+	})
+
+	It("writes the slow query records to the file when requested", func() {
+		Expect(router.Configure(SlowQueryDestinationFile, 0)).To(Succeed())
+
+		router.Write(durationRecord("SELECT 1"))
+		router.Write(&AutoExplainRecord{
+			LoggingRecord: &LoggingRecord{},
+			Explain:       &AutoExplainPlan{DurationMs: 1500, QueryText: "SELECT 1"},
+		})
+		router.Write(&LoggingRecord{Message: "checkpoint starting: time"})
+
+		Expect(spy.records).To(HaveLen(1))
+		Expect(spy.records[0].GetName()).To(Equal(LoggingCollectorRecordName))
+
+		content, err := os.ReadFile(fileName) //nolint:gosec
+		Expect(err).ToNot(HaveOccurred())
+		lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+		Expect(lines).To(HaveLen(2))
+
+		var line struct {
+			Logger string                  `json:"logger"`
+			Record StatementDurationRecord `json:"record"`
+		}
+		Expect(json.Unmarshal([]byte(lines[0]), &line)).To(Succeed())
+		Expect(line.Logger).To(Equal(StatementDurationRecordName))
+		Expect(line.Record.DatabaseName).To(Equal("app"))
+		Expect(line.Record.Duration.Statement).To(Equal("SELECT 1"))
+		Expect(lines[1]).To(ContainSubstring(`"logger":"auto_explain"`))
+	})
+
+	It("rotates the file when it exceeds the maximum size", func() {
+		Expect(router.Configure(SlowQueryDestinationFile, 200)).To(Succeed())
+
+		for range 5 {
+			router.Write(durationRecord("SELECT 1"))
+		}
+
+		Expect(fileName).To(BeAnExistingFile())
+		Expect(fileName + ".1").To(BeAnExistingFile())
+		info, err := os.Stat(fileName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Size()).To(BeNumerically("<=", 200))
+	})
+
+	It("goes back to the next writer when the destination is changed", func() {
+		Expect(router.Configure(SlowQueryDestinationFile, 0)).To(Succeed())
+		router.Write(durationRecord("SELECT 1"))
+		Expect(spy.records).To(BeEmpty())
+
+		Expect(router.Configure(SlowQueryDestinationStdout, 0)).To(Succeed())
+		Expect(router.file).To(BeNil())
+		router.Write(durationRecord("SELECT 2"))
+		Expect(spy.records).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,112 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logpipe
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Slow query logging decorator", func() {
+	recordWithMessage := func(message string) []string {
+		values := make([]string, FieldsPerRecord13)
+		for i := range values {
+			values[i] = fmt.Sprintf("%d", i)
+		}
+		values[13] = message
+		return values
+	}
+
+	It("recognizes a statement duration record with the statement text", func() {
+		result := NewSlowQueryLoggingDecorator().FromCSV(
+			recordWithMessage("duration: 1234.567 ms  statement: SELECT pg_sleep(1)"))
+		Expect(result.GetName()).To(Equal(StatementDurationRecordName))
+
+		typedResult := result.(*StatementDurationRecord)
+		Expect(typedResult.Message).To(BeEmpty())
+		Expect(typedResult.DatabaseName).To(Equal("2"))
+		Expect(*typedResult.Duration).To(Equal(StatementDuration{
+			DurationMs: 1234.567,
+			Kind:       "statement",
+			Statement:  "SELECT pg_sleep(1)",
+		}))
+	})
+
+	It("recognizes the duration of an extended query protocol execution", func() {
+		result := NewSlowQueryLoggingDecorator().FromCSV(
+			recordWithMessage("duration: 12.5 ms  execute <unnamed>: SELECT $1"))
+		Expect(result.GetName()).To(Equal(StatementDurationRecordName))
+		Expect(*result.(*StatementDurationRecord).Duration).To(Equal(StatementDuration{
+			DurationMs: 12.5,
+			Kind:       "execute <unnamed>",
+			Statement:  "SELECT $1",
+		}))
+	})
+
+	It("recognizes a statement duration record without the statement text", func() {
+		result := NewSlowQueryLoggingDecorator().FromCSV(recordWithMessage("duration: 0.042 ms"))
+		Expect(result.GetName()).To(Equal(StatementDurationRecordName))
+		Expect(*result.(*StatementDurationRecord).Duration).To(Equal(StatementDuration{
+			DurationMs: 0.042,
+		}))
+	})
+
+	It("recognizes an auto_explain record in JSON format", func() {
+		plan := `{"Query Text": "SELECT 1", "Plan": {"Node Type": "Result"}}`
+		result := NewSlowQueryLoggingDecorator().FromCSV(
+			recordWithMessage("duration: 3.000 ms  plan:\n" + plan))
+		Expect(result.GetName()).To(Equal(AutoExplainRecordName))
+
+		typedResult := result.(*AutoExplainRecord)
+		Expect(typedResult.Message).To(BeEmpty())
+		Expect(typedResult.Explain.DurationMs).To(Equal(3.0))
+		Expect(typedResult.Explain.QueryText).To(Equal("SELECT 1"))
+		Expect(typedResult.Explain.Plan).To(MatchJSON(plan))
+		Expect(typedResult.Explain.PlanText).To(BeEmpty())
+	})
+
+	It("keeps the auto_explain plan as text when not in JSON format", func() {
+		plan := "Query Text: SELECT 1\nResult  (cost=0.00..0.01 rows=1 width=4)"
+		result := NewSlowQueryLoggingDecorator().FromCSV(
+			recordWithMessage("duration: 3.000 ms  plan:\n" + plan))
+		Expect(result.GetName()).To(Equal(AutoExplainRecordName))
+
+		typedResult := result.(*AutoExplainRecord)
+		Expect(typedResult.Explain.Plan).To(BeNil())
+		Expect(typedResult.Explain.PlanText).To(Equal(plan))
+	})
+
+	It("leaves the other records untouched", func() {
+		result := NewSlowQueryLoggingDecorator().FromCSV(recordWithMessage("checkpoint starting: time"))
+		Expect(result.GetName()).To(Equal(LoggingCollectorRecordName))
+		Expect(result.(*LoggingRecord).Message).To(Equal("checkpoint starting: time"))
+	})
+
+	It("recognizes the pgAudit records", func() {
+		auditValues := make([]string, PgAuditFieldsPerRecord)
+		for i := range auditValues {
+			auditValues[i] = fmt.Sprintf("A%d", i)
+		}
+		result := NewSlowQueryLoggingDecorator().FromCSV(recordWithMessage(writePgAuditMessage(auditValues)))
+		Expect(result.GetName()).To(Equal(PgAuditRecordName))
+	})
+})
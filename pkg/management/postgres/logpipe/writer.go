@@ -40,3 +40,14 @@ type LogRecordWriter struct{}
 func (writer *LogRecordWriter) Write(record NamedRecord) {
 	log.WithName(record.GetName()).Info(logRecordKey, logRecordKey, record)
 }
+
+// MultiRecordWriter implements the `RecordWriter` interface writing
+// every record to each of the contained writers
+type MultiRecordWriter []RecordWriter
+
+// Write writes the PostgreSQL log record to each of the contained writers
+func (writers MultiRecordWriter) Write(record NamedRecord) {
+	for _, writer := range writers {
+		writer.Write(record)
+	}
+}
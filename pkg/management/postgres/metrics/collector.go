@@ -24,17 +24,21 @@ package metrics
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"path"
 	"regexp"
 	"slices"
+	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/cloudnative-pg/cnpg-i/pkg/metrics"
 	"github.com/cloudnative-pg/machinery/pkg/log"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	pluginClient "github.com/cloudnative-pg/cloudnative-pg/internal/cnpi/plugin/client"
@@ -55,11 +59,22 @@ type QueriesCollector struct {
 	mappings       map[string]MetricMapSet
 	variableLabels map[string]VariableSet
 
+	// observers are the histograms and the summaries of each query,
+	// keyed by column name, kept across the executions so that their
+	// counts and sums are cumulative
+	observers map[string]map[string]prometheus.ObserverVec
+
 	errorUserQueries      *prometheus.CounterVec
 	errorUserQueriesGauge prometheus.Gauge
 	lastUpdateTimestamp   prometheus.Gauge
 	cacheHits             prometheus.Gauge
 	cacheMiss             prometheus.Gauge
+	queryErrors           *prometheus.CounterVec
+	queryDuration         *prometheus.GaugeVec
+
+	// maxParallelQueries is the maximum number of queries
+	// executed concurrently, counting each target database
+	maxParallelQueries int
 
 	computedMetrics []prometheus.Metric
 	timeLastUpdated time.Time
@@ -85,6 +100,7 @@ func (q *QueriesCollector) Update() error {
 	// Start a fresh error state for this cycle
 	q.errorUserQueriesGauge.Set(0)
 	q.errorUserQueries.Reset()
+	q.queryDuration.Reset()
 
 	// Reset cache hit/miss counters when we update (cache miss)
 	q.cacheHits.Set(0)
@@ -129,12 +145,27 @@ func (q *QueriesCollector) Collect(ch chan<- prometheus.Metric) {
 	for _, m := range q.computedMetrics {
 		ch <- m
 	}
+	for _, queryObservers := range q.observers {
+		for _, observer := range queryObservers {
+			observer.Collect(ch)
+		}
+	}
 	// Add errors into errorUserQueriesVec and errorUserQueriesGauge metrics
 	q.errorUserQueriesGauge.Collect(ch)
 	q.errorUserQueries.Collect(ch)
 	q.lastUpdateTimestamp.Collect(ch)
 	q.cacheHits.Collect(ch)
 	q.cacheMiss.Collect(ch)
+	q.queryErrors.Collect(ch)
+	q.queryDuration.Collect(ch)
+}
+
+// SetMaxParallelQueries sets the maximum number of queries executed
+// concurrently, counting each target database separately
+func (q *QueriesCollector) SetMaxParallelQueries(maxParallelQueries int) {
+	q.metricsMutex.Lock()
+	defer q.metricsMutex.Unlock()
+	q.maxParallelQueries = maxParallelQueries
 }
 
 func (q *QueriesCollector) createMetricsFromUserQueries(isPrimary bool) {
@@ -142,7 +173,12 @@ func (q *QueriesCollector) createMetricsFromUserQueries(isPrimary bool) {
 	// we need to get them just once
 	var allAccessibleDatabasesCache []string
 
-	var generatedMetrics []prometheus.Metric
+	var (
+		generatedMetrics      []prometheus.Metric
+		generatedMetricsMutex sync.Mutex
+		wg                    sync.WaitGroup
+	)
+	semaphore := make(chan struct{}, max(q.maxParallelQueries, 1))
 	for name, userQuery := range q.userQueries {
 		queryLogger := log.WithValues("query", name)
 		queryRunner := QueryRunner{
@@ -150,6 +186,7 @@ func (q *QueriesCollector) createMetricsFromUserQueries(isPrimary bool) {
 			userQuery:      userQuery,
 			columnMapping:  q.mappings[name],
 			variableLabels: q.variableLabels[name],
+			observers:      q.observers[name],
 		}
 
 		if !q.toBeChecked(name, userQuery, isPrimary, queryLogger) {
@@ -180,25 +217,56 @@ func (q *QueriesCollector) createMetricsFromUserQueries(isPrimary bool) {
 
 		allTargetDatabases := q.expandTargetDatabases(targetDatabases, allAccessibleDatabasesCache)
 		for targetDatabase := range allTargetDatabases {
-			db, err := q.instance.GetMetricsDB(targetDatabase)
-			if err != nil {
-				q.reportUserQueryErrorMetric(name + ": " + err.Error())
-				continue
-			}
-
-			computedMetrics, err := queryRunner.computeMetrics(db)
-			if err != nil {
-				queryLogger.Error(err, "Error collecting user query",
-					"targetDatabase", targetDatabase)
-				// Increment metrics counters.
-				q.reportUserQueryErrorMetric(name + " on db " + targetDatabase + ": " + err.Error())
-			}
-			generatedMetrics = append(generatedMetrics, computedMetrics...)
+			semaphore <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer func() {
+					<-semaphore
+					wg.Done()
+				}()
+
+				computedMetrics := q.runUserQuery(queryRunner, name, targetDatabase, queryLogger)
+				generatedMetricsMutex.Lock()
+				generatedMetrics = append(generatedMetrics, computedMetrics...)
+				generatedMetricsMutex.Unlock()
+			}()
 		}
 	}
+	wg.Wait()
 	q.computedMetrics = generatedMetrics
 }
 
+// runUserQuery runs a query in a target database, returning the generated
+// metrics. Failures are reported in the error metrics, so that the metrics
+// generated by the other queries and databases can still be exported.
+func (q *QueriesCollector) runUserQuery(
+	queryRunner QueryRunner,
+	name, targetDatabase string,
+	queryLogger log.Logger,
+) []prometheus.Metric {
+	start := time.Now()
+	defer func() {
+		q.queryDuration.WithLabelValues(name, targetDatabase).Set(time.Since(start).Seconds())
+	}()
+
+	db, err := q.instance.GetMetricsDB(targetDatabase)
+	if err != nil {
+		q.queryErrors.WithLabelValues(name, targetDatabase).Inc()
+		q.reportUserQueryErrorMetric(name + ": " + err.Error())
+		return nil
+	}
+
+	computedMetrics, err := queryRunner.computeMetrics(db)
+	if err != nil {
+		queryLogger.Error(err, "Error collecting user query",
+			"targetDatabase", targetDatabase)
+		// Increment metrics counters.
+		q.queryErrors.WithLabelValues(name, targetDatabase).Inc()
+		q.reportUserQueryErrorMetric(name + " on db " + targetDatabase + ": " + err.Error())
+	}
+	return computedMetrics
+}
+
 func (q *QueriesCollector) toBeChecked(name string, userQuery UserQuery, isPrimary bool, queryLogger log.Logger) bool {
 	if (userQuery.Primary || userQuery.Master) && !isPrimary { // wokeignore:rule=master
 		queryLogger.Debug("Skipping because runs only on primary")
@@ -272,7 +340,7 @@ func (q *QueriesCollector) getAllAccessibleDatabases() ([]string, error) {
 	if err != nil {
 		return nil, fmt.Errorf("while connecting to expand target_database *: %w", err)
 	}
-	tx, err := createMonitoringTx(conn)
+	tx, err := createMonitoringTx(context.Background(), conn)
 	if err != nil {
 		return nil, fmt.Errorf("while creating monitoring tx to retrieve accessible databases list: %w", err)
 	}
@@ -332,6 +400,8 @@ func (q *QueriesCollector) Describe(ch chan<- *prometheus.Desc) {
 	q.lastUpdateTimestamp.Describe(ch)
 	q.cacheHits.Describe(ch)
 	q.cacheMiss.Describe(ch)
+	q.queryErrors.Describe(ch)
+	q.queryDuration.Describe(ch)
 }
 
 // NewQueriesCollector creates a new PgCollector working over a set of custom queries
@@ -346,6 +416,7 @@ func NewQueriesCollector(
 		instance:       instance,
 		mappings:       make(map[string]MetricMapSet),
 		variableLabels: make(map[string]VariableSet),
+		observers:      make(map[string]map[string]prometheus.ObserverVec),
 		userQueries:    make(UserQueries),
 		defaultDBName:  defaultDBName,
 		errorUserQueries: prometheus.NewCounterVec(prometheus.CounterOpts{
@@ -373,6 +444,17 @@ func NewQueriesCollector(
 			Name:      "cache_miss",
 			Help:      "Indicator: 1 if metrics were recomputed on last update (cache miss), 0 if cache used.",
 		}),
+		queryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: name,
+			Name:      "query_errors_total",
+			Help:      "Total errors occurred running each user query, per target database.",
+		}, []string{"query", "datname"}),
+		queryDuration: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: name,
+			Name:      "query_duration_seconds",
+			Help:      "Duration of the last execution of each user query, per target database.",
+		}, []string{"query", "datname"}),
+		maxParallelQueries: 1,
 	}
 }
 
@@ -400,6 +482,7 @@ func (q *QueriesCollector) ParseQueries(customQueries []byte) error {
 		}
 		q.mappings[name], q.variableLabels[name] = query.ToMetricMap(
 			fmt.Sprintf("%v_%v", q.Name(), metricMapNamespace))
+		q.observers[name] = newObservers(q.mappings[name])
 	}
 
 	return nil
@@ -415,6 +498,7 @@ func (q *QueriesCollector) InjectUserQueries(defaultQueries UserQueries) {
 		q.userQueries[name] = query
 		q.mappings[name], q.variableLabels[name] = query.ToMetricMap(
 			fmt.Sprintf("%v_%v", q.Name(), name))
+		q.observers[name] = newObservers(q.mappings[name])
 	}
 }
 
@@ -425,13 +509,22 @@ type QueryRunner struct {
 	userQuery      UserQuery
 	columnMapping  MetricMapSet
 	variableLabels VariableSet
+	observers      map[string]prometheus.ObserverVec
 }
 
 // computeMetrics runs the queries and generates prometheus metrics from them
 func (c QueryRunner) computeMetrics(conn *sql.DB) ([]prometheus.Metric, error) {
-	tx, err := createMonitoringTx(conn)
+	ctx := context.Background()
+	timeout := c.userQuery.getTimeout()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	tx, err := createMonitoringTx(ctx, conn)
 	if err != nil {
-		return nil, err
+		return nil, wrapTimeoutError(ctx, err)
 	}
 	var computedMetrics []prometheus.Metric
 
@@ -441,18 +534,27 @@ func (c QueryRunner) computeMetrics(conn *sql.DB) ([]prometheus.Metric, error) {
 		}
 	}()
 
-	shouldBeCollected, err := c.userQuery.isCollectable(tx)
+	// The statement timeout also stops the query on the server side
+	// when the context expires
+	if timeout > 0 {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d",
+			max(timeout.Milliseconds(), 1))); err != nil {
+			return nil, wrapTimeoutError(ctx, err)
+		}
+	}
+
+	shouldBeCollected, err := c.userQuery.isCollectable(ctx, tx)
 	if err != nil {
-		return nil, err
+		return nil, wrapTimeoutError(ctx, err)
 	}
 
 	if !shouldBeCollected {
 		return nil, nil
 	}
 
-	rows, err := tx.Query(c.userQuery.Query)
+	rows, err := tx.QueryContext(ctx, c.userQuery.Query)
 	if err != nil {
-		return nil, err
+		return nil, wrapTimeoutError(ctx, err)
 	}
 	defer func() {
 		if err := rows.Close(); err != nil {
@@ -487,18 +589,31 @@ func (c QueryRunner) computeMetrics(conn *sql.DB) ([]prometheus.Metric, error) {
 
 		labels, done := c.listLabels(columns, columnData)
 		if done {
-			computedMetrics = append(computedMetrics, c.createMetricsFromColumns(columns, columnData, labels)...)
+			computedMetrics = append(computedMetrics,
+				c.createMetricsFromColumns(columns, columnData, labels, c.observers)...)
 		}
 	}
 	if err := rows.Err(); err != nil {
 		log.Warning("Error while loading metrics",
 			"err", err.Error())
-		return nil, err
+		return nil, wrapTimeoutError(ctx, err)
 	}
 
 	return computedMetrics, nil
 }
 
+// newObservers creates the histograms and the summaries aggregating
+// the values of the columns across the rows and the executions of a query
+func newObservers(columnMapping MetricMapSet) map[string]prometheus.ObserverVec {
+	observers := make(map[string]prometheus.ObserverVec)
+	for columnName, mapping := range columnMapping {
+		if mapping.NewObserverVec != nil {
+			observers[columnName] = mapping.NewObserverVec()
+		}
+	}
+	return observers
+}
+
 // Collect the list of labels from the database, and returns true if the
 // label extraction succeeded, false otherwise
 func (c QueryRunner) listLabels(columns []string, columnData []interface{}) ([]string, bool) {
@@ -524,6 +639,7 @@ func (c QueryRunner) createMetricsFromColumns(
 	columns []string,
 	columnData []interface{},
 	labels []string,
+	observers map[string]prometheus.ObserverVec,
 ) []prometheus.Metric {
 	var computedMetrics []prometheus.Metric
 	for idx, columnName := range columns {
@@ -561,21 +677,159 @@ func (c QueryRunner) createMetricsFromColumns(
 				}
 			}
 
+		case mapping.NewObserverVec != nil:
+			observer, ok := observers[columnName]
+			if !ok {
+				continue
+			}
+			exemplarLabels := c.listExemplarLabels(mapping, columns, columnData)
+			c.observe(mapping, observer, columnData[idx], labels, exemplarLabels)
+
 		default:
 			m := c.createConstMetric(mapping, columnData[idx], labels)
-			if m != nil {
-				computedMetrics = append(computedMetrics, m)
+			if m == nil {
+				continue
+			}
+			if exemplarLabels := c.listExemplarLabels(mapping, columns, columnData); len(exemplarLabels) > 0 {
+				m = c.attachExemplar(mapping, m, columnData[idx], exemplarLabels)
 			}
+			computedMetrics = append(computedMetrics, m)
 		}
 	}
 	return computedMetrics
 }
 
+// listExemplarLabels collects the exemplar labels of a metric from the
+// values of the corresponding columns, skipping the empty ones
+func (c QueryRunner) listExemplarLabels(
+	mapping MetricMap,
+	columns []string,
+	columnData []interface{},
+) prometheus.Labels {
+	if len(mapping.ExemplarLabels) == 0 {
+		return nil
+	}
+
+	result := make(prometheus.Labels, len(mapping.ExemplarLabels))
+	for _, exemplarLabel := range mapping.ExemplarLabels {
+		idx := slices.Index(columns, exemplarLabel)
+		if idx < 0 {
+			log.Warning("Missing column for exemplar label",
+				"namespace", c.namespace,
+				"column", exemplarLabel)
+			continue
+		}
+
+		value, ok := postgresutils.DBToString(columnData[idx])
+		if !ok || value == "" {
+			continue
+		}
+		result[exemplarLabel] = value
+	}
+
+	return result
+}
+
+// attachExemplar wraps a metric adding the exemplar labels, returning
+// the original metric if the exemplar is not valid
+func (c QueryRunner) attachExemplar(
+	mapping MetricMap,
+	metric prometheus.Metric,
+	value interface{},
+	exemplarLabels prometheus.Labels,
+) prometheus.Metric {
+	// The conversion already succeeded when creating the metric
+	floatData, _ := mapping.Conversion(value)
+	result, err := prometheus.NewMetricWithExemplars(metric, prometheus.Exemplar{
+		Value:  floatData,
+		Labels: exemplarLabels,
+	})
+	if err != nil {
+		log.Warning("Error while attaching exemplar",
+			"namespace", c.namespace,
+			"metric", mapping.Name,
+			"err", err.Error())
+		return metric
+	}
+	return result
+}
+
+// observe adds the value of a column to the corresponding histogram
+// or summary
+func (c QueryRunner) observe(
+	mapping MetricMap,
+	observer prometheus.ObserverVec,
+	value interface{},
+	variableLabels []string,
+	exemplarLabels prometheus.Labels,
+) {
+	floatData, ok := mapping.Conversion(value)
+	if !ok {
+		log.Warning("Error while parsing value",
+			"namespace", c.namespace,
+			"value", value,
+			"mapping", mapping)
+		return
+	}
+
+	metric, err := observer.GetMetricWithLabelValues(variableLabels...)
+	if err != nil {
+		log.Error(err, "while observing metric", "metric", mapping.Name)
+		return
+	}
+
+	if exemplarObserver, ok := metric.(prometheus.ExemplarObserver); ok && len(exemplarLabels) > 0 {
+		if err := validateExemplarLabels(exemplarLabels); err != nil {
+			log.Warning("Error while attaching exemplar",
+				"namespace", c.namespace,
+				"metric", mapping.Name,
+				"err", err.Error())
+		} else {
+			exemplarObserver.ObserveWithExemplar(floatData, exemplarLabels)
+			return
+		}
+	}
+
+	metric.Observe(floatData)
+}
+
+// validateExemplarLabels checks the exemplar labels with the same rules
+// applied by the Prometheus library, which panics when observing a value
+// with invalid exemplar labels
+func validateExemplarLabels(labels prometheus.Labels) error {
+	var runes int
+	for name, value := range labels {
+		if !model.LegacyValidation.IsValidLabelName(name) || strings.HasPrefix(name, model.ReservedLabelPrefix) {
+			return fmt.Errorf("exemplar label name %q is invalid", name)
+		}
+		if !utf8.ValidString(value) {
+			return fmt.Errorf("exemplar label value %q is not valid UTF-8", value)
+		}
+		runes += utf8.RuneCountInString(name) + utf8.RuneCountInString(value)
+	}
+	if runes > prometheus.ExemplarMaxRunes {
+		return fmt.Errorf("exemplar labels have %d runes, exceeding the limit of %d",
+			runes, prometheus.ExemplarMaxRunes)
+	}
+
+	return nil
+}
+
+// wrapTimeoutError makes the errors caused by the expiration of the
+// query timeout recognizable
+func wrapTimeoutError(ctx context.Context, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("query timed out: %w", err)
+	}
+
+	return err
+}
+
 // createMonitoringTx creates a read-only monitoring transaction. The connection
 // is already established as cnpg_metrics_exporter (which inherits pg_monitor),
 // so no role switching is required.
-func createMonitoringTx(conn *sql.DB) (*sql.Tx, error) {
-	tx, err := conn.BeginTx(context.Background(), &sql.TxOptions{
+func createMonitoringTx(ctx context.Context, conn *sql.DB) (*sql.Tx, error) {
+	tx, err := conn.BeginTx(ctx, &sql.TxOptions{
 		ReadOnly: true,
 	})
 	if err != nil {
@@ -21,6 +21,8 @@ package metrics
 
 import (
 	"database/sql"
+	"strings"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/cloudnative-pg/cnpg-i/pkg/metrics"
@@ -239,6 +241,244 @@ var _ = Describe("QueryRunner tests", func() {
 	})
 })
 
+var _ = Describe("QueryRunner observations", func() {
+	var (
+		dbMock sqlmock.Sqlmock
+		db     *sql.DB
+	)
+
+	latencyQuery := "SELECT datname, query_id, latency, wait FROM app_latencies"
+	yamlQueries := `
+latencies:
+  query: "SELECT datname, query_id, latency, wait FROM app_latencies"
+  metrics:
+    - datname:
+        usage: "LABEL"
+    - query_id:
+        usage: "DISCARD"
+    - latency:
+        usage: "NATIVEHISTOGRAM"
+        description: "Latency of the requests"
+        buckets: [1, 5]
+        native_histogram_bucket_factor: 1.5
+        exemplar_labels: ["query_id"]
+    - wait:
+        usage: "SUMMARY"
+        description: "Wait time of the requests"
+        objectives:
+          0.5: 0.05
+`
+
+	BeforeEach(func() {
+		var err error
+		db, dbMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("aggregates the values of the rows in histograms and summaries", func() {
+		collector := NewQueriesCollector("test", nil, "postgres")
+		Expect(collector.ParseQueries([]byte(yamlQueries))).To(Succeed())
+		userQuery := collector.userQueries["latencies"]
+		Expect(userQuery.Metrics[2]["latency"].NativeHistogramBucketFactor).To(Equal(1.5))
+		Expect(userQuery.Metrics[3]["wait"].Objectives).To(HaveKeyWithValue(0.5, 0.05))
+
+		qc := QueryRunner{
+			namespace:      "latencies",
+			userQuery:      userQuery,
+			columnMapping:  collector.mappings["latencies"],
+			variableLabels: collector.variableLabels["latencies"],
+			observers:      collector.observers["latencies"],
+		}
+
+		// The observations of every execution are added to the same
+		// histograms and summaries
+		for range 2 {
+			dbMock.ExpectBegin()
+			dbMock.ExpectExec("SET LOCAL search_path = pg_catalog, public, pg_temp").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			dbMock.ExpectExec("SET standard_conforming_strings TO on").WillReturnResult(sqlmock.NewResult(0, 1))
+			dbMock.ExpectQuery(latencyQuery).WillReturnRows(sqlmock.NewRows(
+				[]string{"datname", "query_id", "latency", "wait"}).
+				AddRow("app", "q1", 0.5, 1.0).
+				AddRow("app", "q2", 2.0, 3.0))
+			dbMock.ExpectCommit()
+
+			computedMetrics, err := qc.computeMetrics(db)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(computedMetrics).To(BeEmpty())
+		}
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+
+		ch := make(chan prometheus.Metric, 20)
+		collector.Collect(ch)
+		close(ch)
+		var observed int
+		for m := range ch {
+			var metric io_prometheus_client.Metric
+			Expect(m.Write(&metric)).To(Succeed())
+
+			switch {
+			case metric.GetHistogram() != nil:
+				Expect(m.Desc().String()).To(ContainSubstring("test_latencies_latency"))
+				Expect(metric.GetLabel()).To(HaveLen(1))
+				Expect(metric.GetLabel()[0].GetValue()).To(Equal("app"))
+				Expect(metric.GetHistogram().GetSampleCount()).To(BeEquivalentTo(4))
+				Expect(metric.GetHistogram().GetSampleSum()).To(Equal(5.0))
+				Expect(metric.GetHistogram().GetBucket()).To(HaveLen(2))
+				Expect(metric.GetHistogram().GetPositiveSpan()).ToNot(BeEmpty())
+				Expect(metric.GetHistogram().GetExemplars()).ToNot(BeEmpty())
+				observed++
+			case metric.GetSummary() != nil:
+				Expect(m.Desc().String()).To(ContainSubstring("test_latencies_wait"))
+				Expect(metric.GetLabel()).To(HaveLen(1))
+				Expect(metric.GetLabel()[0].GetValue()).To(Equal("app"))
+				Expect(metric.GetSummary().GetSampleCount()).To(BeEquivalentTo(4))
+				Expect(metric.GetSummary().GetSampleSum()).To(Equal(8.0))
+				Expect(metric.GetSummary().GetQuantile()).To(HaveLen(1))
+				observed++
+			}
+		}
+		Expect(observed).To(Equal(2))
+	})
+
+	It("attaches the exemplar labels to the counters", func() {
+		columnMapping := UserQuery{
+			Metrics: []Mapping{
+				{"query_id": ColumnMapping{Usage: DISCARD}},
+				{"calls": ColumnMapping{Usage: COUNTER, ExemplarLabels: []string{"query_id"}}},
+			},
+		}
+		metricMap, _ := columnMapping.ToMetricMap("test")
+		qc := QueryRunner{namespace: "test", columnMapping: metricMap}
+
+		computedMetrics := qc.createMetricsFromColumns(
+			[]string{"query_id", "calls"}, []interface{}{"q1", int64(42)}, nil, nil)
+		Expect(computedMetrics).To(HaveLen(1))
+
+		var metric io_prometheus_client.Metric
+		Expect(computedMetrics[0].Write(&metric)).To(Succeed())
+		Expect(metric.GetCounter().GetValue()).To(BeEquivalentTo(42))
+		Expect(metric.GetCounter().GetExemplar().GetValue()).To(BeEquivalentTo(42))
+		Expect(metric.GetCounter().GetExemplar().GetLabel()).To(HaveLen(1))
+		Expect(metric.GetCounter().GetExemplar().GetLabel()[0].GetName()).To(Equal("query_id"))
+		Expect(metric.GetCounter().GetExemplar().GetLabel()[0].GetValue()).To(Equal("q1"))
+	})
+
+	It("rejects the invalid exemplar labels", func() {
+		Expect(validateExemplarLabels(prometheus.Labels{"query_id": "q1"})).To(Succeed())
+		Expect(validateExemplarLabels(prometheus.Labels{"__name__": "q1"})).ToNot(Succeed())
+		Expect(validateExemplarLabels(prometheus.Labels{"query-id": "q1"})).ToNot(Succeed())
+		Expect(validateExemplarLabels(prometheus.Labels{
+			"query_id": strings.Repeat("q", prometheus.ExemplarMaxRunes),
+		})).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("QueryRunner timeouts", func() {
+	var (
+		dbMock sqlmock.Sqlmock
+		db     *sql.DB
+	)
+
+	slowQuery := "SELECT pg_catalog.pg_sleep(10) AS value"
+	userQuery := UserQuery{
+		Query:   slowQuery,
+		Timeout: "100ms",
+		Metrics: []Mapping{
+			{"value": ColumnMapping{Usage: GAUGE}},
+		},
+	}
+
+	BeforeEach(func() {
+		var err error
+		db, dbMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec("SET LOCAL search_path = pg_catalog, public, pg_temp").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec("SET standard_conforming_strings TO on").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec("SET LOCAL statement_timeout = 100").WillReturnResult(sqlmock.NewResult(0, 0))
+	})
+
+	It("sets the statement timeout for the query", func() {
+		columnMapping, variableLabels := userQuery.ToMetricMap("test")
+		qc := QueryRunner{
+			namespace:      "test",
+			userQuery:      userQuery,
+			columnMapping:  columnMapping,
+			variableLabels: variableLabels,
+		}
+
+		dbMock.ExpectQuery(slowQuery).WillReturnRows(sqlmock.NewRows([]string{"value"}).AddRow(1))
+		dbMock.ExpectCommit()
+
+		computedMetrics, err := qc.computeMetrics(db)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(computedMetrics).To(HaveLen(1))
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("stops the query once the timeout expires", func() {
+		columnMapping, variableLabels := userQuery.ToMetricMap("test")
+		qc := QueryRunner{
+			namespace:      "test",
+			userQuery:      userQuery,
+			columnMapping:  columnMapping,
+			variableLabels: variableLabels,
+		}
+
+		dbMock.ExpectQuery(slowQuery).
+			WillDelayFor(time.Second).
+			WillReturnRows(sqlmock.NewRows([]string{"value"}).AddRow(1))
+
+		computedMetrics, err := qc.computeMetrics(db)
+		Expect(err).To(MatchError(ContainSubstring("query timed out")))
+		Expect(computedMetrics).To(BeEmpty())
+	})
+})
+
+var _ = Describe("QueriesCollector per-query metrics", func() {
+	It("describes and collects the per-query errors and durations", func() {
+		collector := NewQueriesCollector("test_collector", nil, "postgres")
+		collector.SetMaxParallelQueries(8)
+		Expect(collector.maxParallelQueries).To(Equal(8))
+
+		collector.queryErrors.WithLabelValues("some_query", "app").Inc()
+		collector.queryDuration.WithLabelValues("some_query", "app").Set(0.5)
+
+		descriptions := make(chan *prometheus.Desc, 20)
+		collector.Describe(descriptions)
+		close(descriptions)
+		var describedNames []string
+		for desc := range descriptions {
+			describedNames = append(describedNames, desc.String())
+		}
+		Expect(describedNames).To(ContainElements(
+			ContainSubstring("test_collector_query_errors_total"),
+			ContainSubstring("test_collector_query_duration_seconds"),
+		))
+
+		ch := make(chan prometheus.Metric, 20)
+		collector.Collect(ch)
+		close(ch)
+		var values []float64
+		for metric := range ch {
+			if !strings.Contains(metric.Desc().String(), "test_collector_query_") {
+				continue
+			}
+			var m io_prometheus_client.Metric
+			Expect(metric.Write(&m)).To(Succeed())
+			if m.Counter != nil {
+				values = append(values, m.GetCounter().GetValue())
+			} else {
+				values = append(values, m.GetGauge().GetValue())
+			}
+		}
+		Expect(values).To(ConsistOf(1.0, 0.5))
+	})
+})
+
 var _ = Describe("sendPluginMetrics tests", func() {
 	It("should successfully send metrics when definitions and metrics match", func() {
 		ch := make(chan prometheus.Metric, 10)
@@ -24,6 +24,7 @@ import (
 	"math"
 
 	"github.com/prometheus/client_golang/prometheus"
+	io_prometheus_client "github.com/prometheus/client_model/go"
 
 	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
 
@@ -235,6 +236,75 @@ var _ = Describe("ColumnMapping ToMetricMap", func() {
 				"", variableLabels, nil).String()))
 		})
 	})
+
+	Context("when usage is NATIVEHISTOGRAM", func() {
+		It("should create a native histogram with the sorted classic buckets", func() {
+			columnMapping := ColumnMapping{
+				Usage:          "NATIVEHISTOGRAM",
+				Description:    "Test native histogram",
+				Buckets:        []float64{1, 0.1, 1, 10},
+				ExemplarLabels: []string{"query_id"},
+			}
+			columnName := "histogram_column"
+
+			result := columnMapping.ToMetricMap(columnName, namespace, variableLabels)
+			Expect(result[columnName].Desc.String()).To(Equal(prometheus.NewDesc(
+				fmt.Sprintf("%s_%s", namespace, columnName),
+				columnMapping.Description, variableLabels, nil).String()))
+			Expect(result[columnName].ExemplarLabels).To(Equal([]string{"query_id"}))
+			Expect(result[columnName].NewObserverVec).ToNot(BeNil())
+
+			observer := result[columnName].NewObserverVec()
+			observer.WithLabelValues("a", "b").Observe(0.5)
+
+			var metric io_prometheus_client.Metric
+			Expect(observer.(*prometheus.HistogramVec).WithLabelValues("a", "b").(prometheus.Metric).
+				Write(&metric)).To(Succeed())
+			Expect(metric.GetHistogram().GetSampleCount()).To(BeEquivalentTo(1))
+			Expect(metric.GetHistogram().GetSchema()).To(BeEquivalentTo(3))
+			Expect(metric.GetHistogram().GetBucket()).To(HaveLen(3))
+			Expect(metric.GetHistogram().GetBucket()[0].GetUpperBound()).To(Equal(0.1))
+		})
+
+		It("should discard the column when using a reserved label", func() {
+			columnMapping := ColumnMapping{Usage: "NATIVEHISTOGRAM"}
+			columnName := "histogram_column"
+
+			result := columnMapping.ToMetricMap(columnName, namespace, []string{"le"})
+			Expect(result[columnName].Discard).To(BeTrue())
+			Expect(result[columnName].NewObserverVec).To(BeNil())
+		})
+	})
+
+	Context("when usage is SUMMARY", func() {
+		It("should create a summary with the default objectives", func() {
+			columnMapping := ColumnMapping{
+				Usage:       "SUMMARY",
+				Description: "Test summary",
+			}
+			columnName := "summary_column"
+
+			result := columnMapping.ToMetricMap(columnName, namespace, variableLabels)
+			Expect(result[columnName].NewObserverVec).ToNot(BeNil())
+
+			observer := result[columnName].NewObserverVec()
+			observer.WithLabelValues("a", "b").Observe(0.5)
+
+			var metric io_prometheus_client.Metric
+			Expect(observer.(*prometheus.SummaryVec).WithLabelValues("a", "b").(prometheus.Metric).
+				Write(&metric)).To(Succeed())
+			Expect(metric.GetSummary().GetSampleCount()).To(BeEquivalentTo(1))
+			Expect(metric.GetSummary().GetQuantile()).To(HaveLen(len(defaultSummaryObjectives)))
+		})
+
+		It("should discard the column when using a reserved label", func() {
+			columnMapping := ColumnMapping{Usage: "SUMMARY"}
+			columnName := "summary_column"
+
+			result := columnMapping.ToMetricMap(columnName, namespace, []string{"quantile"})
+			Expect(result[columnName].Discard).To(BeTrue())
+		})
+	})
 })
 
 var _ = Describe("UserQuery ToMetricMap", func() {
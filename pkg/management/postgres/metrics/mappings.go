@@ -24,8 +24,10 @@ package metrics
 import (
 	"fmt"
 	"math"
+	"slices"
 	"time"
 
+	"github.com/cloudnative-pg/machinery/pkg/log"
 	"github.com/prometheus/client_golang/prometheus"
 
 	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
@@ -48,6 +50,14 @@ type MetricMap struct {
 	// Should metric be treated as a histogram?
 	Histogram bool
 
+	// NewObserverVec, when set, creates the histogram or the summary
+	// aggregating the values of this column across the rows
+	NewObserverVec func() prometheus.ObserverVec `json:"-"`
+
+	// ExemplarLabels are the columns whose values are attached to the
+	// metric as exemplar labels
+	ExemplarLabels []string
+
 	// Vtype is the prometheus valueType
 	Vtype prometheus.ValueType
 
@@ -59,6 +69,21 @@ type MetricMap struct {
 	Conversion func(interface{}) (float64, bool) `json:"-"`
 }
 
+const (
+	// defaultNativeHistogramBucketFactor is the growth factor between
+	// the native buckets, used when not specified by the user
+	defaultNativeHistogramBucketFactor = 1.1
+
+	// bucketLabel and quantileLabel are reserved by the Prometheus
+	// library for histograms and summaries respectively
+	bucketLabel   = "le"
+	quantileLabel = "quantile"
+)
+
+// defaultSummaryObjectives are the quantiles of a summary, used when
+// not specified by the user
+var defaultSummaryObjectives = map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001}
+
 // MetricMapSet is a set of MetricMap, usually associated to a UserQuery
 type MetricMapSet map[string]MetricMap
 
@@ -126,8 +151,9 @@ func (columnMapping ColumnMapping) ToMetricMap(
 			Desc: prometheus.NewDesc(
 				columnFQName,
 				columnMapping.Description, variableLabels, nil),
-			Conversion: postgresutils.DBToFloat64,
-			Label:      false,
+			Conversion:     postgresutils.DBToFloat64,
+			Label:          false,
+			ExemplarLabels: columnMapping.ExemplarLabels,
 		}
 
 	case GAUGE:
@@ -174,6 +200,69 @@ func (columnMapping ColumnMapping) ToMetricMap(
 			Label:     false,
 		}
 
+	case NATIVEHISTOGRAM:
+		if slices.Contains(variableLabels, bucketLabel) {
+			log.Warning("Discarding native histogram using a reserved label",
+				"column", columnName, "label", bucketLabel)
+			result[columnName] = MetricMap{Name: columnName, Discard: true}
+			break
+		}
+
+		bucketFactor := columnMapping.NativeHistogramBucketFactor
+		if bucketFactor <= 1 {
+			bucketFactor = defaultNativeHistogramBucketFactor
+		}
+		// The Prometheus library requires the classic buckets
+		// to be strictly increasing
+		buckets := slices.Clone(columnMapping.Buckets)
+		slices.Sort(buckets)
+		buckets = slices.Compact(buckets)
+		opts := prometheus.HistogramOpts{
+			Name:                        columnFQName,
+			Help:                        columnMapping.Description,
+			Buckets:                     buckets,
+			NativeHistogramBucketFactor: bucketFactor,
+		}
+		result[columnName] = MetricMap{
+			Name: columnName,
+			Desc: prometheus.NewDesc(
+				columnFQName,
+				columnMapping.Description, variableLabels, nil),
+			Conversion: postgresutils.DBToFloat64,
+			NewObserverVec: func() prometheus.ObserverVec {
+				return prometheus.NewHistogramVec(opts, variableLabels)
+			},
+			ExemplarLabels: columnMapping.ExemplarLabels,
+		}
+
+	case SUMMARY:
+		if slices.Contains(variableLabels, quantileLabel) {
+			log.Warning("Discarding summary using a reserved label",
+				"column", columnName, "label", quantileLabel)
+			result[columnName] = MetricMap{Name: columnName, Discard: true}
+			break
+		}
+
+		objectives := columnMapping.Objectives
+		if len(objectives) == 0 {
+			objectives = defaultSummaryObjectives
+		}
+		opts := prometheus.SummaryOpts{
+			Name:       columnFQName,
+			Help:       columnMapping.Description,
+			Objectives: objectives,
+		}
+		result[columnName] = MetricMap{
+			Name: columnName,
+			Desc: prometheus.NewDesc(
+				columnFQName,
+				columnMapping.Description, variableLabels, nil),
+			Conversion: postgresutils.DBToFloat64,
+			NewObserverVec: func() prometheus.ObserverVec {
+				return prometheus.NewSummaryVec(opts, variableLabels)
+			},
+		}
+
 	case MAPPEDMETRIC:
 		result[columnName] = MetricMap{
 			Name:  columnName,
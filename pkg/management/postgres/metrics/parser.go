@@ -21,9 +21,11 @@ SPDX-License-Identifier: Apache-2.0
 package metrics
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"go.yaml.in/yaml/v3"
 )
@@ -43,6 +45,9 @@ type UserQuery struct {
 	TargetDatabases []string  `yaml:"target_databases"`
 	// Name allows overriding the key name in the metric namespace
 	Name string `yaml:"name"`
+	// Timeout is the maximum duration of the query in each target
+	// database, as a Go duration string (e.g. "5s")
+	Timeout string `yaml:"timeout"`
 }
 
 // Mapping decide how a certain field, extracted from the query's result, should be used
@@ -61,6 +66,24 @@ type ColumnMapping struct {
 
 	// Name allows overriding the key name when naming the column
 	Name string `yaml:"name"`
+
+	// Buckets are the upper bounds of the classic buckets of a
+	// NATIVEHISTOGRAM. When empty, only the native buckets are exposed.
+	Buckets []float64 `yaml:"buckets"`
+
+	// NativeHistogramBucketFactor is the growth factor between the
+	// native buckets of a NATIVEHISTOGRAM, defaulting to 1.1
+	NativeHistogramBucketFactor float64 `yaml:"native_histogram_bucket_factor"`
+
+	// Objectives are the quantiles of a SUMMARY, with their absolute
+	// error, defaulting to 0.5, 0.9 and 0.99
+	Objectives map[float64]float64 `yaml:"objectives"`
+
+	// ExemplarLabels are the columns whose values are attached to a
+	// COUNTER or to the observations of a NATIVEHISTOGRAM as exemplar
+	// labels. Those columns must be listed in the metrics too, usually
+	// with the DISCARD usage.
+	ExemplarLabels []string `yaml:"exemplar_labels"`
 }
 
 // ColumnUsage represent how a certain column should be used
@@ -87,6 +110,14 @@ const (
 
 	// HISTOGRAM means use this column as an histogram
 	HISTOGRAM ColumnUsage = "HISTOGRAM"
+
+	// NATIVEHISTOGRAM means use the values of this column, one per row,
+	// as the observations of a native histogram
+	NATIVEHISTOGRAM ColumnUsage = "NATIVEHISTOGRAM"
+
+	// SUMMARY means use the values of this column, one per row,
+	// as the observations of a summary
+	SUMMARY ColumnUsage = "SUMMARY"
 )
 
 // ParseQueries parse a YAML file containing custom queries
@@ -97,21 +128,42 @@ func ParseQueries(content []byte) (UserQueries, error) {
 		return nil, fmt.Errorf("parsing user queries: %w", err)
 	}
 
+	for name, userQuery := range result {
+		if userQuery.Timeout == "" {
+			continue
+		}
+		if timeout, err := time.ParseDuration(userQuery.Timeout); err != nil || timeout < 0 {
+			return nil, fmt.Errorf("parsing user queries: invalid timeout %q for query %s", userQuery.Timeout, name)
+		}
+	}
+
 	return result, nil
 }
 
+// getTimeout returns the maximum duration of the query, or zero
+// if the query has no timeout
+func (userQuery UserQuery) getTimeout() time.Duration {
+	if userQuery.Timeout == "" {
+		return 0
+	}
+
+	// The timeout has already been validated while parsing the queries
+	timeout, _ := time.ParseDuration(userQuery.Timeout)
+	return timeout
+}
+
 // isCollectable checks if a query to collect metrics should be executed.
 // The method tests the query provided in the PredicateQuery property within the same transaction
 // used to collect metrics.
 // PredicateQuery should return at most a single row with a single column with type bool.
 // If no PredicateQuery is provided, the query is considered collectable by default
-func (userQuery UserQuery) isCollectable(tx *sql.Tx) (bool, error) {
+func (userQuery UserQuery) isCollectable(ctx context.Context, tx *sql.Tx) (bool, error) {
 	if userQuery.PredicateQuery == "" {
 		return true, nil
 	}
 
 	var isCollectable sql.NullBool
-	if err := tx.QueryRow(userQuery.PredicateQuery).Scan(&isCollectable); err != nil {
+	if err := tx.QueryRowContext(ctx, userQuery.PredicateQuery).Scan(&isCollectable); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return false, nil
 		}
@@ -21,6 +21,7 @@ package metrics
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 
@@ -97,6 +98,31 @@ test:
 		Expect(err).To(HaveOccurred())
 		Expect(result).To(BeNil())
 	})
+
+	It("parses the query timeout", func() {
+		result, err := ParseQueries([]byte(`
+some_query:
+  query: "SELECT 1 AS value"
+  timeout: 1500ms
+  metrics:
+  - value:
+      usage: "GAUGE"
+`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result["some_query"].Timeout).To(Equal("1500ms"))
+		Expect(result["some_query"].getTimeout()).To(Equal(1500 * time.Millisecond))
+		Expect(UserQuery{}.getTimeout()).To(BeZero())
+	})
+
+	It("rejects invalid query timeouts", func() {
+		result, err := ParseQueries([]byte(`
+some_query:
+  query: "SELECT 1 AS value"
+  timeout: soon
+`))
+		Expect(err).To(MatchError(ContainSubstring("invalid timeout")))
+		Expect(result).To(BeNil())
+	})
 })
 
 var _ = Describe("userQuery", func() {
@@ -119,7 +145,7 @@ var _ = Describe("userQuery", func() {
 
 		tx, err := db.BeginTx(ctx, nil)
 		Expect(err).ToNot(HaveOccurred())
-		res, err := uq.isCollectable(tx)
+		res, err := uq.isCollectable(ctx, tx)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(res).To(BeTrue())
 	})
@@ -133,7 +159,7 @@ var _ = Describe("userQuery", func() {
 
 		tx, err := db.BeginTx(ctx, nil)
 		Expect(err).ToNot(HaveOccurred())
-		res, err := uq.isCollectable(tx)
+		res, err := uq.isCollectable(ctx, tx)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(res).To(BeFalse())
 	})
@@ -147,7 +173,7 @@ var _ = Describe("userQuery", func() {
 
 		tx, err := db.BeginTx(ctx, nil)
 		Expect(err).ToNot(HaveOccurred())
-		res, err := uq.isCollectable(tx)
+		res, err := uq.isCollectable(ctx, tx)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(res).To(BeFalse())
 	})
@@ -161,7 +187,7 @@ var _ = Describe("userQuery", func() {
 
 		tx, err := db.BeginTx(ctx, nil)
 		Expect(err).ToNot(HaveOccurred())
-		res, err := uq.isCollectable(tx)
+		res, err := uq.isCollectable(ctx, tx)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(res).To(BeFalse())
 	})
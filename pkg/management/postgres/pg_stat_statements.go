@@ -0,0 +1,142 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// PgStatStatementsExtensionName is the name of the extension
+// providing query-level statistics
+const PgStatStatementsExtensionName = "pg_stat_statements"
+
+// StatementText is the text of a statement tracked by pg_stat_statements
+type StatementText struct {
+	// QueryID is the normalized query identifier
+	QueryID string `json:"queryid"`
+
+	// DatabaseName is the database where the statement was executed
+	DatabaseName string `json:"datname"`
+
+	// UserName is the user who executed the statement
+	UserName string `json:"usename"`
+
+	// Query is the text of the normalized statement
+	Query string `json:"query"`
+}
+
+// FormatQueryID normalizes a pg_stat_statements query identifier, which is
+// a signed 64-bit integer, to a fixed-length hexadecimal string. This is the
+// representation used in the metric labels.
+func FormatQueryID(queryID int64) string {
+	return fmt.Sprintf("%016x", uint64(queryID)) //nolint:gosec
+}
+
+// ParseQueryID parses a query identifier normalized by FormatQueryID
+func ParseQueryID(queryID string) (int64, error) {
+	value, err := strconv.ParseUint(queryID, 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid queryid %q: %w", queryID, err)
+	}
+
+	return int64(value), nil //nolint:gosec
+}
+
+// GetPgStatStatementsSchema returns the schema where pg_stat_statements is
+// installed in the database, or an empty string if it is not installed
+func GetPgStatStatementsSchema(ctx context.Context, db *sql.DB) (string, error) {
+	var schema string
+	row := db.QueryRowContext(
+		ctx,
+		`SELECT n.nspname
+		FROM pg_catalog.pg_extension e
+		JOIN pg_catalog.pg_namespace n ON n.oid = e.extnamespace
+		WHERE e.extname = $1`,
+		PgStatStatementsExtensionName)
+	if err := row.Scan(&schema); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return schema, nil
+}
+
+// GetStatementTexts returns the text of the statements tracked by
+// pg_stat_statements having the passed query identifier
+func (instance *Instance) GetStatementTexts(ctx context.Context, queryID int64) ([]StatementText, error) {
+	db, err := instance.GetMetricsDB("postgres")
+	if err != nil {
+		return nil, err
+	}
+
+	return getStatementTexts(ctx, db, queryID)
+}
+
+func getStatementTexts(ctx context.Context, db *sql.DB, queryID int64) ([]StatementText, error) {
+	schema, err := GetPgStatStatementsSchema(ctx, db)
+	if err != nil {
+		return nil, EnrichMetricsConnError(err)
+	}
+	if schema == "" {
+		return nil, fmt.Errorf("extension %s is not installed in the postgres database",
+			PgStatStatementsExtensionName)
+	}
+
+	rows, err := db.QueryContext(
+		ctx,
+		fmt.Sprintf(
+			`SELECT DISTINCT d.datname, r.rolname, s.query
+			FROM %s.pg_stat_statements(true) s
+			JOIN pg_catalog.pg_database d ON d.oid = s.dbid
+			JOIN pg_catalog.pg_roles r ON r.oid = s.userid
+			WHERE s.queryid = $1
+			ORDER BY 1, 2`,
+			pgx.Identifier{schema}.Sanitize()),
+		queryID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result := make([]StatementText, 0, 1)
+	for rows.Next() {
+		statement := StatementText{QueryID: FormatQueryID(queryID)}
+		if err := rows.Scan(&statement.DatabaseName, &statement.UserName, &statement.Query); err != nil {
+			return nil, err
+		}
+		result = append(result, statement)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pg_stat_statements query identifiers", func() {
+	It("formats positive and negative identifiers as fixed-length hexadecimal strings", func() {
+		Expect(FormatQueryID(255)).To(Equal("00000000000000ff"))
+		Expect(FormatQueryID(-1)).To(Equal("ffffffffffffffff"))
+	})
+
+	It("parses back the normalized identifiers", func() {
+		for _, id := range []int64{0, 42, -1, -8349813482394728} {
+			parsed, err := ParseQueryID(FormatQueryID(id))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed).To(Equal(id))
+		}
+	})
+
+	It("rejects malformed identifiers", func() {
+		_, err := ParseQueryID("not-a-queryid")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("pg_stat_statements statement texts", func() {
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("returns an empty schema when the extension is not installed", func(ctx context.Context) {
+		mock.ExpectQuery("FROM pg_catalog.pg_extension").
+			WithArgs(PgStatStatementsExtensionName).
+			WillReturnRows(sqlmock.NewRows([]string{"nspname"}))
+
+		schema, err := GetPgStatStatementsSchema(ctx, db)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(schema).To(BeEmpty())
+	})
+
+	It("fails when the extension is not installed", func(ctx context.Context) {
+		mock.ExpectQuery("FROM pg_catalog.pg_extension").
+			WithArgs(PgStatStatementsExtensionName).
+			WillReturnRows(sqlmock.NewRows([]string{"nspname"}))
+
+		_, err := getStatementTexts(ctx, db, 42)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns the statement texts for the queryid", func(ctx context.Context) {
+		mock.ExpectQuery("FROM pg_catalog.pg_extension").
+			WithArgs(PgStatStatementsExtensionName).
+			WillReturnRows(sqlmock.NewRows([]string{"nspname"}).AddRow("public"))
+		mock.ExpectQuery(`FROM "public".pg_stat_statements\(true\)`).
+			WithArgs(int64(-1)).
+			WillReturnRows(sqlmock.NewRows([]string{"datname", "rolname", "query"}).
+				AddRow("app", "app", "SELECT $1"))
+
+		statements, err := getStatementTexts(ctx, db, -1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(statements).To(ConsistOf(StatementText{
+			QueryID:      "ffffffffffffffff",
+			DatabaseName: "app",
+			UserName:     "app",
+			Query:        "SELECT $1",
+		}))
+	})
+})
@@ -0,0 +1,218 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/cloudnative-pg/machinery/pkg/execlog"
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/cloudnative-pg/machinery/pkg/types"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+)
+
+const (
+	pgCombineBackupName = "pg_combinebackup"
+
+	// backupManifestFileName is the name of the manifest file written
+	// by pg_basebackup in the backup directory
+	backupManifestFileName = "backup_manifest"
+
+	// pgBaseBackupApplicationName is the application name used by the
+	// replication connection taking backups with the pgBaseBackup method.
+	// It must not match any instance name, to avoid being considered a
+	// synchronous standby
+	pgBaseBackupApplicationName = "cnpg-pgbasebackup"
+)
+
+// PgBaseBackupWALRange is the range of WAL required to restore a
+// backup, as reported in its manifest
+type PgBaseBackupWALRange struct {
+	TimeLineID int    `json:"Timeline"`
+	StartLSN   string `json:"Start-LSN"`
+	EndLSN     string `json:"End-LSN"`
+}
+
+// pgBaseBackupManifest is the subset of the backup manifest
+// we are interested in
+type pgBaseBackupManifest struct {
+	WALRanges []PgBaseBackupWALRange `json:"WAL-Ranges"`
+}
+
+// GetPgBaseBackupPath returns the path, relative to the root of the backup
+// volume, where a backup of the passed cluster is stored
+func GetPgBaseBackupPath(clusterName, backupID string) string {
+	return filepath.Join(clusterName, backupID)
+}
+
+// GetPgBaseBackupDirectory returns the directory where a backup taken with the
+// pgBaseBackup method is found, given its status
+func GetPgBaseBackupDirectory(backupStatus *apiv1.BackupPgBaseBackupStatus) string {
+	return filepath.Join(specs.PgBaseBackupVolumePath, backupStatus.Path)
+}
+
+// buildPgBaseBackupConnInfo returns the DSN used by pg_basebackup to
+// connect to the local instance via the streaming replication user
+func buildPgBaseBackupConnInfo() string {
+	return buildPrimaryConnInfo("localhost", pgBaseBackupApplicationName) + " dbname=postgres"
+}
+
+// buildPgBaseBackupOptions returns the pg_basebackup command line used to take
+// a backup into backupDirectory. When parentDirectory is not empty, the
+// backup will be incremental with respect to the one found there
+func buildPgBaseBackupOptions(connectionString, backupDirectory, parentDirectory string) []string {
+	options := []string{
+		"-D", backupDirectory,
+		"-X", "stream",
+		"-c", "fast",
+		"-v",
+		"-w",
+		"-d", connectionString,
+	}
+
+	if parentDirectory != "" {
+		options = append(options, "--incremental", filepath.Join(parentDirectory, backupManifestFileName))
+	}
+
+	return options
+}
+
+// TakePgBaseBackup takes a backup of the local instance into backupDirectory
+// using pg_basebackup. When parentDirectory is not empty, the backup will
+// be incremental with respect to the one found there
+func TakePgBaseBackup(ctx context.Context, backupDirectory, parentDirectory string) error {
+	contextLogger := log.FromContext(ctx)
+
+	if err := os.MkdirAll(filepath.Dir(backupDirectory), 0o700); err != nil {
+		return fmt.Errorf("while creating the backup directory: %w", err)
+	}
+
+	options := buildPgBaseBackupOptions(buildPgBaseBackupConnInfo(), backupDirectory, parentDirectory)
+	contextLogger.Info("Starting pg_basebackup",
+		"backupDirectory", backupDirectory,
+		"parentDirectory", parentDirectory)
+
+	pgBaseBackupCmd := exec.Command(pgBaseBackupName, options...) // #nosec
+	if err := execlog.RunStreaming(pgBaseBackupCmd, pgBaseBackupName); err != nil {
+		return fmt.Errorf("error in pg_basebackup, %w", err)
+	}
+
+	return nil
+}
+
+// ReadPgBaseBackupWALRange reads the WAL range required to restore the
+// backup contained in backupDirectory from its manifest
+func ReadPgBaseBackupWALRange(backupDirectory string) (*PgBaseBackupWALRange, error) {
+	manifestContent, err := os.ReadFile(filepath.Join(backupDirectory, backupManifestFileName)) // #nosec
+	if err != nil {
+		return nil, fmt.Errorf("while reading the backup manifest: %w", err)
+	}
+
+	var manifest pgBaseBackupManifest
+	if err := json.Unmarshal(manifestContent, &manifest); err != nil {
+		return nil, fmt.Errorf("while decoding the backup manifest: %w", err)
+	}
+
+	if len(manifest.WALRanges) == 0 {
+		return nil, fmt.Errorf("no WAL range found in the backup manifest")
+	}
+
+	// A backup taken on a standby whose timeline changed during the
+	// backup has more than one range, one per timeline: we report the
+	// latest timeline and the whole LSN range
+	result := manifest.WALRanges[0]
+	for _, walRange := range manifest.WALRanges[1:] {
+		if walRange.TimeLineID > result.TimeLineID {
+			result.TimeLineID = walRange.TimeLineID
+		}
+		if isLSNBefore(walRange.StartLSN, result.StartLSN) {
+			result.StartLSN = walRange.StartLSN
+		}
+		if isLSNBefore(result.EndLSN, walRange.EndLSN) {
+			result.EndLSN = walRange.EndLSN
+		}
+	}
+
+	return &result, nil
+}
+
+// isLSNBefore checks if the first LSN precedes the second one. Invalid
+// LSNs are never considered preceding
+func isLSNBefore(first, second string) bool {
+	firstPosition, err := types.LSN(first).Parse()
+	if err != nil {
+		return false
+	}
+	secondPosition, err := types.LSN(second).Parse()
+	if err != nil {
+		return false
+	}
+	return firstPosition < secondPosition
+}
+
+// CopyPgBaseBackup copies a full backup, which isn't based on any other
+// backup, into outputDirectory
+func CopyPgBaseBackup(ctx context.Context, backupDirectory string, outputDirectory string) error {
+	contextLogger := log.FromContext(ctx)
+
+	if err := os.MkdirAll(outputDirectory, 0o700); err != nil {
+		return fmt.Errorf("while creating the output directory: %w", err)
+	}
+
+	contextLogger.Info("Copying the full backup",
+		"backupDirectory", backupDirectory,
+		"outputDirectory", outputDirectory)
+
+	// "cp" preserves the permissions of the files, which
+	// are checked by PostgreSQL
+	cpCmd := exec.Command("cp", "-a", backupDirectory+"/.", outputDirectory) // #nosec
+	if err := execlog.RunStreaming(cpCmd, "cp"); err != nil {
+		return fmt.Errorf("error while copying the backup, %w", err)
+	}
+
+	return nil
+}
+
+// CombinePgBaseBackups reconstructs a full data directory into outputDirectory
+// from a chain of backups, starting with the full one and followed by the
+// incremental backups in the order they were taken
+func CombinePgBaseBackups(ctx context.Context, backupDirectories []string, outputDirectory string) error {
+	contextLogger := log.FromContext(ctx)
+
+	options := make([]string, 0, 2+len(backupDirectories))
+	options = append(options, "-o", outputDirectory)
+	options = append(options, backupDirectories...)
+
+	contextLogger.Info("Starting pg_combinebackup", "options", options)
+
+	pgCombineBackupCmd := exec.Command(pgCombineBackupName, options...) // #nosec
+	if err := execlog.RunStreaming(pgCombineBackupCmd, pgCombineBackupName); err != nil {
+		return fmt.Errorf("error in pg_combinebackup, %w", err)
+	}
+
+	return nil
+}
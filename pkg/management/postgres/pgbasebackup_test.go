@@ -0,0 +1,97 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pgBaseBackup backups", func() {
+	It("builds the options for a full backup", func() {
+		options := buildPgBaseBackupOptions("host=localhost", "/backups/full", "")
+		Expect(options).To(Equal([]string{
+			"-D", "/backups/full",
+			"-X", "stream",
+			"-c", "fast",
+			"-v",
+			"-w",
+			"-d", "host=localhost",
+		}))
+	})
+
+	It("builds the options for an incremental backup", func() {
+		options := buildPgBaseBackupOptions("host=localhost", "/backups/incr", "/backups/full")
+		Expect(options).To(ContainElements("--incremental", "/backups/full/backup_manifest"))
+	})
+
+	It("copies a full backup preserving the permissions of the files", func(ctx SpecContext) {
+		backupDirectory := GinkgoT().TempDir()
+		Expect(os.Mkdir(filepath.Join(backupDirectory, "global"), 0o700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(backupDirectory, "global", "pg_control"), []byte("control"), 0o600)).
+			To(Succeed())
+		Expect(os.WriteFile(filepath.Join(backupDirectory, "PG_VERSION"), []byte("16\n"), 0o600)).
+			To(Succeed())
+
+		outputDirectory := filepath.Join(GinkgoT().TempDir(), "pgdata")
+		Expect(CopyPgBaseBackup(ctx, backupDirectory, outputDirectory)).To(Succeed())
+
+		content, err := os.ReadFile(filepath.Join(outputDirectory, "global", "pg_control")) // #nosec
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("control"))
+		info, err := os.Stat(filepath.Join(outputDirectory, "PG_VERSION"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0o600)))
+		info, err = os.Stat(outputDirectory)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0o700)))
+	})
+
+	It("reads the WAL range from the backup manifest", func() {
+		backupDirectory := GinkgoT().TempDir()
+		manifest := `{
+			"PostgreSQL-Backup-Manifest-Version": 2,
+			"WAL-Ranges": [
+				{ "Timeline": 1, "Start-LSN": "0/2000028", "End-LSN": "0/3000000" },
+				{ "Timeline": 2, "Start-LSN": "0/3000000", "End-LSN": "0/4000100" }
+			]
+		}`
+		Expect(os.WriteFile(filepath.Join(backupDirectory, "backup_manifest"), []byte(manifest), 0o600)).
+			To(Succeed())
+
+		walRange, err := ReadPgBaseBackupWALRange(backupDirectory)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(walRange.TimeLineID).To(Equal(2))
+		Expect(walRange.StartLSN).To(Equal("0/2000028"))
+		Expect(walRange.EndLSN).To(Equal("0/4000100"))
+	})
+
+	It("fails when the manifest has no WAL range", func() {
+		backupDirectory := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(backupDirectory, "backup_manifest"), []byte(`{}`), 0o600)).
+			To(Succeed())
+
+		_, err := ReadPgBaseBackupWALRange(backupDirectory)
+		Expect(err).To(HaveOccurred())
+	})
+})
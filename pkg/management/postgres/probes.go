@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/cloudnative-pg/machinery/pkg/fileutils"
 	"github.com/cloudnative-pg/machinery/pkg/log"
@@ -34,6 +35,8 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/executablehash"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/system/compatibility"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 )
 
@@ -62,6 +65,11 @@ func (instance *Instance) GetStatus() (result *postgres.PostgresqlStatus, err er
 		}
 	}()
 
+	// The filesystem usage is collected before connecting to PostgreSQL,
+	// as it is meaningful even when the instance is not working
+	instance.fillVolumesUsage(result)
+	instance.fillCPUUsage(result)
+
 	if instance.PgRewindIsRunning {
 		// We know that pg_rewind is running, so we exit with the proper status
 		// updated, and we can provide that information to the user.
@@ -242,6 +250,63 @@ WHERE pending_settings.name IN (
 	return decreasedSensibleValues, nil
 }
 
+// fillVolumesUsage reports the usage of the filesystems of the volumes
+// mounted by the instance, which drives the autoscaling of the PVCs
+func (instance *Instance) fillVolumesUsage(result *postgres.PostgresqlStatus) {
+	cluster := instance.GetClusterOrDefault()
+
+	type volume struct {
+		usage postgres.VolumeUsage
+		path  string
+	}
+	volumes := []volume{{usage: postgres.VolumeUsage{Role: utils.PVCRolePgData}, path: instance.PgData}}
+	if cluster.ShouldCreateWalArchiveVolume() {
+		volumes = append(volumes, volume{
+			usage: postgres.VolumeUsage{Role: utils.PVCRolePgWal},
+			path:  specs.PgWalVolumePath,
+		})
+	}
+	for _, tablespace := range cluster.Spec.Tablespaces {
+		volumes = append(volumes, volume{
+			usage: postgres.VolumeUsage{Role: utils.PVCRolePgTablespace, TablespaceName: tablespace.Name},
+			path:  specs.MountForTablespace(tablespace.Name),
+		})
+	}
+
+	for _, vol := range volumes {
+		var err error
+		vol.usage.TotalBytes, vol.usage.UsedBytes, vol.usage.AvailableBytes, err =
+			compatibility.GetFilesystemUsage(vol.path)
+		if err != nil {
+			log.Debug("Cannot get the filesystem usage", "path", vol.path, "error", err)
+			continue
+		}
+		result.VolumesUsage = append(result.VolumesUsage, vol.usage)
+	}
+}
+
+// fillCPUUsage fills the CPU usage of the instance container, when
+// it is available
+func (instance *Instance) fillCPUUsage(result *postgres.PostgresqlStatus) {
+	cpuTime, err := compatibility.GetCPUUsage()
+	if err != nil {
+		log.Debug("Cannot get the CPU usage", "error", err)
+		return
+	}
+
+	result.CPUUsageMillicores = instance.cpuUsage.update(cpuTime, time.Now())
+}
+
+// fillActiveConnections counts the client connections which are not idle,
+// excluding the one used by the instance manager to collect the status
+func fillActiveConnections(superUserDB *sql.DB, result *postgres.PostgresqlStatus) error {
+	row := superUserDB.QueryRow(
+		"SELECT COUNT(*) FROM pg_catalog.pg_stat_activity " +
+			"WHERE backend_type = 'client backend' AND state <> 'idle' " +
+			"AND pid <> pg_catalog.pg_backend_pid()")
+	return row.Scan(&result.ActiveConnections)
+}
+
 // fillStatus extract the current instance information into the PostgresqlStatus
 // structure
 func (instance *Instance) fillStatus(result *postgres.PostgresqlStatus) error {
@@ -272,6 +337,10 @@ func (instance *Instance) fillStatus(result *postgres.PostgresqlStatus) error {
 		return err
 	}
 
+	if err := fillActiveConnections(superUserDB, result); err != nil {
+		return err
+	}
+
 	return instance.fillWalStatus(result)
 }
 
@@ -534,13 +603,24 @@ func (instance *Instance) fillStatusFromReplica(result *postgres.PostgresqlStatu
 
 	// pg_last_wal_receive_lsn may be NULL when using non-streaming
 	// replicas
+	// The replay lag is measured only when there's something to
+	// replay, as an idle primary doesn't generate transactions
 	row := superUserDB.QueryRow(
 		"SELECT " +
 			"(SELECT timeline_id FROM pg_catalog.pg_control_checkpoint()), " +
 			"COALESCE(pg_catalog.pg_last_wal_receive_lsn()::varchar, ''), " +
 			"COALESCE(pg_catalog.pg_last_wal_replay_lsn()::varchar, ''), " +
-			"pg_catalog.pg_is_wal_replay_paused()")
-	if err := row.Scan(&result.TimeLineID, &result.ReceivedLsn, &result.ReplayLsn, &result.ReplayPaused); err != nil {
+			"pg_catalog.pg_is_wal_replay_paused(), " +
+			"CASE WHEN pg_catalog.pg_last_wal_receive_lsn() = pg_catalog.pg_last_wal_replay_lsn() THEN 0 " +
+			"ELSE COALESCE(EXTRACT(EPOCH FROM " +
+			"pg_catalog.now() - pg_catalog.pg_last_xact_replay_timestamp()), 0) END")
+	if err := row.Scan(
+		&result.TimeLineID,
+		&result.ReceivedLsn,
+		&result.ReplayLsn,
+		&result.ReplayPaused,
+		&result.ReplayLagSeconds,
+	); err != nil {
 		return err
 	}
 
@@ -49,6 +49,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -277,11 +278,19 @@ func (info InitInfo) Restore(ctx context.Context, cli client.Client) error {
 		info.ApplicationDatabase = cluster.GetApplicationDatabaseName()
 	}
 
+	pgBaseBackup, err := info.loadPgBaseBackupReference(ctx, cli, cluster)
+	if err != nil {
+		return err
+	}
+
 	var config string
 	var envs []string
-	if pluginConfiguration := cluster.GetRecoverySourcePlugin(); pluginConfiguration != nil {
+	switch pluginConfiguration := cluster.GetRecoverySourcePlugin(); {
+	case pgBaseBackup != nil:
+		config, err = info.restoreViaPgBaseBackup(ctx, cli, pgBaseBackup)
+	case pluginConfiguration != nil:
 		config, envs, err = restoreViaPlugin(ctx, cluster, pluginConfiguration)
-	} else {
+	default:
 		config, envs, err = info.restoreViaBarmanObjectStore(ctx, cli, cluster)
 	}
 	if err != nil {
@@ -402,7 +411,9 @@ func (info InitInfo) restoreDataDir(ctx context.Context, backup *apiv1.Backup, e
 	return nil
 }
 
-// loadCluster loads the cluster definition from the API server
+// loadCluster loads the cluster definition from the API server. When
+// a backup is being verified, the returned definition restores that
+// backup in an instance detached from the cluster
 func (info InitInfo) loadCluster(ctx context.Context, typedClient client.Client) (*apiv1.Cluster, error) {
 	var cluster apiv1.Cluster
 	err := typedClient.Get(ctx, client.ObjectKey{Namespace: info.Namespace, Name: info.ClusterName}, &cluster)
@@ -410,7 +421,20 @@ func (info InitInfo) loadCluster(ctx context.Context, typedClient client.Client)
 		return nil, err
 	}
 
-	return &cluster, nil
+	if info.BackupToVerify == "" {
+		return &cluster, nil
+	}
+
+	var backup apiv1.Backup
+	if err := typedClient.Get(
+		ctx,
+		client.ObjectKey{Namespace: info.Namespace, Name: info.BackupToVerify},
+		&backup,
+	); err != nil {
+		return nil, fmt.Errorf("while getting the backup to verify: %w", err)
+	}
+
+	return cluster.GetBackupVerificationCluster(&backup), nil
 }
 
 // loadBackup loads the backup manifest from the API server of from the object store.
@@ -1107,3 +1131,106 @@ func (info InitInfo) restoreViaBarmanObjectStore(
 
 	return getRestoreWalConfig(), env, nil
 }
+
+// pgBaseBackupRestoreWalConfig is the recovery configuration used when
+// restoring a backup taken with the pgBaseBackup method. The WAL files
+// needed to reach a consistent state are streamed into the backup by
+// pg_basebackup, and there's no WAL archive to fetch further WAL files from,
+// so the recovery always ends at the end of the backup
+const pgBaseBackupRestoreWalConfig = "restore_command = 'false'\n"
+
+// loadPgBaseBackupReference loads the backup referenced in the recovery
+// stanza of the cluster if it has been taken with the pgBaseBackup method,
+// returning nil otherwise
+func (info InitInfo) loadPgBaseBackupReference(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+) (*apiv1.Backup, error) {
+	if cluster.Spec.Bootstrap == nil || cluster.Spec.Bootstrap.Recovery == nil ||
+		cluster.Spec.Bootstrap.Recovery.Backup == nil {
+		return nil, nil
+	}
+
+	var backup apiv1.Backup
+	if err := cli.Get(
+		ctx,
+		client.ObjectKey{Namespace: info.Namespace, Name: cluster.Spec.Bootstrap.Recovery.Backup.Name},
+		&backup,
+	); err != nil {
+		return nil, err
+	}
+
+	if backup.Spec.Method != apiv1.BackupMethodPgBaseBackup {
+		return nil, nil
+	}
+
+	// There's no WAL archive to replay from after the end of the backup,
+	// so a point in time recovery can't be honored. Stopping as soon as
+	// the backup is consistent, as done when verifying it, is allowed
+	if target := cluster.Spec.Bootstrap.Recovery.RecoveryTarget; target != nil && !isImmediateRecoveryTarget(target) {
+		return nil, fmt.Errorf(
+			"backup %s has been taken with the pgBaseBackup method, which doesn't support recovery targets",
+			backup.Name)
+	}
+
+	return &backup, nil
+}
+
+// isImmediateRecoveryTarget checks if the passed recovery target only
+// requires the recovery to end as soon as a consistent state is reached
+func isImmediateRecoveryTarget(target *apiv1.RecoveryTarget) bool {
+	return ptr.Deref(target.TargetImmediate, false) &&
+		target.BackupID == "" &&
+		target.TargetTLI == "" &&
+		target.TargetXID == "" &&
+		target.TargetName == "" &&
+		target.TargetLSN == "" &&
+		target.TargetTime == ""
+}
+
+// restoreViaPgBaseBackup reconstructs PGDATA combining the passed backup
+// with the backups it is based on, and returns the WAL-restore
+// configuration for the recovery phase. A full backup is copied as is,
+// as pg_combinebackup is only available from PostgreSQL 17
+func (info InitInfo) restoreViaPgBaseBackup(
+	ctx context.Context,
+	cli client.Client,
+	backup *apiv1.Backup,
+) (string, error) {
+	contextLogger := log.FromContext(ctx)
+
+	var backups apiv1.BackupList
+	if err := cli.List(ctx, &backups, client.InNamespace(info.Namespace)); err != nil {
+		return "", fmt.Errorf("while getting backups: %w", err)
+	}
+
+	chain, err := backups.GetPgBaseBackupChain(backup)
+	if err != nil {
+		return "", err
+	}
+
+	backupDirectories := make([]string, len(chain))
+	for idx, item := range chain {
+		if item.Status.PgBaseBackupStatus.ClaimName != backup.Status.PgBaseBackupStatus.ClaimName {
+			return "", fmt.Errorf("backup %s is not stored in the same volume of backup %s",
+				item.Name, backup.Name)
+		}
+		backupDirectories[idx] = GetPgBaseBackupDirectory(item.Status.PgBaseBackupStatus)
+	}
+
+	contextLogger.Info("Recovering existing pgBaseBackup backup",
+		"backup", backup.Name,
+		"chain", backupDirectories)
+
+	if len(backupDirectories) == 1 {
+		err = CopyPgBaseBackup(ctx, backupDirectories[0], info.PgData)
+	} else {
+		err = CombinePgBaseBackups(ctx, backupDirectories, info.PgData)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return pgBaseBackupRestoreWalConfig, nil
+}
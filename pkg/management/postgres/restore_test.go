@@ -26,9 +26,13 @@ import (
 
 	"github.com/cloudnative-pg/machinery/pkg/fileutils"
 	"github.com/thoas/go-funk"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -311,3 +315,78 @@ var _ = Describe("setupBootstrapWALRestoreCache", func() {
 		Expect(store.Wal.RestoreAdditionalCommandArgs).To(ContainElement("--read-timeout=60"))
 	})
 })
+
+var _ = Describe("loadPgBaseBackupReference", func() {
+	const namespace = "test"
+
+	newCluster := func(recoveryTarget *apiv1.RecoveryTarget) *apiv1.Cluster {
+		return &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: namespace},
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					Recovery: &apiv1.BootstrapRecovery{
+						Backup: &apiv1.BackupSource{
+							LocalObjectReference: apiv1.LocalObjectReference{Name: "backup-example"},
+						},
+						RecoveryTarget: recoveryTarget,
+					},
+				},
+			},
+		}
+	}
+
+	newInitInfo := func(method apiv1.BackupMethod) (InitInfo, *fake.ClientBuilder) {
+		backup := &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup-example", Namespace: namespace},
+			Spec: apiv1.BackupSpec{
+				Cluster: apiv1.LocalObjectReference{Name: "source-example"},
+				Method:  method,
+			},
+		}
+		return InitInfo{Namespace: namespace}, fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(backup)
+	}
+
+	It("returns the backup when it has been taken with the pgBaseBackup method", func(ctx SpecContext) {
+		info, builder := newInitInfo(apiv1.BackupMethodPgBaseBackup)
+		backup, err := info.loadPgBaseBackupReference(ctx, builder.Build(), newCluster(nil))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backup).ToNot(BeNil())
+		Expect(backup.Name).To(Equal("backup-example"))
+	})
+
+	It("ignores backups taken with other methods", func(ctx SpecContext) {
+		info, builder := newInitInfo(apiv1.BackupMethodVolumeSnapshot)
+		backup, err := info.loadPgBaseBackupReference(ctx, builder.Build(),
+			newCluster(&apiv1.RecoveryTarget{TargetLSN: "0/3000060"}))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backup).To(BeNil())
+	})
+
+	It("accepts the verification of a pgBaseBackup backup", func(ctx SpecContext) {
+		info, builder := newInitInfo(apiv1.BackupMethodPgBaseBackup)
+		cli := builder.Build()
+		var backup apiv1.Backup
+		Expect(cli.Get(ctx, client.ObjectKey{Namespace: namespace, Name: "backup-example"}, &backup)).
+			To(Succeed())
+
+		source := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "source-example", Namespace: namespace},
+		}
+		verificationCluster := source.GetBackupVerificationCluster(&backup)
+		Expect(verificationCluster.Spec.Bootstrap.Recovery.RecoveryTarget.TargetImmediate).To(HaveValue(BeTrue()))
+
+		result, err := info.loadPgBaseBackupReference(ctx, cli, verificationCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).ToNot(BeNil())
+		Expect(result.Name).To(Equal("backup-example"))
+	})
+
+	It("rejects point in time recovery from a pgBaseBackup backup", func(ctx SpecContext) {
+		info, builder := newInitInfo(apiv1.BackupMethodPgBaseBackup)
+		_, err := info.loadPgBaseBackupReference(ctx, builder.Build(),
+			newCluster(&apiv1.RecoveryTarget{TargetLSN: "0/3000060"}))
+		Expect(err).To(MatchError(ContainSubstring("doesn't support recovery targets")))
+	})
+})
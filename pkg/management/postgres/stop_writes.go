@@ -0,0 +1,154 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+)
+
+// ErrNotReadOnly is returned when the writes are stopped on an instance
+// whose new transactions are not read-only yet
+var ErrNotReadOnly = errors.New("default_transaction_read_only is not enabled")
+
+// errBackendsRunning is returned while the terminated backends are exiting
+var errBackendsRunning = errors.New("the terminated backends are still running")
+
+// terminatedBackendsBackoff is the time given to the terminated
+// backends to abort their transactions and exit
+var terminatedBackendsBackoff = wait.Backoff{
+	Duration: 100 * time.Millisecond,
+	Factor:   1.5,
+	Steps:    15,
+}
+
+// StopWrites terminates the client connections of the instance, whose new
+// transactions must already be read-only, and returns the current WAL
+// location. Every transaction committed on the instance is included in the
+// WAL up to the returned location.
+func (instance *Instance) StopWrites(ctx context.Context) (string, error) {
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return "", err
+	}
+
+	return stopWrites(ctx, superUserDB)
+}
+
+func stopWrites(ctx context.Context, db *sql.DB) (string, error) {
+	contextLogger := log.FromContext(ctx)
+
+	// A single connection is used, as the other ones in the pool
+	// are terminated too
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	var readOnly string
+	if err := conn.QueryRowContext(ctx,
+		"SELECT pg_catalog.current_setting('default_transaction_read_only')").Scan(&readOnly); err != nil {
+		return "", fmt.Errorf("while checking default_transaction_read_only: %w", err)
+	}
+	if readOnly != "on" {
+		return "", ErrNotReadOnly
+	}
+
+	// The transactions started before the configuration was
+	// reloaded can still write, and are aborted
+	pids, err := terminateClientBackends(ctx, conn)
+	if err != nil {
+		return "", err
+	}
+	contextLogger.Info("Terminated the client backends to stop the writes", "pids", pids)
+
+	if len(pids) > 0 {
+		if err := retry.OnError(terminatedBackendsBackoff, func(err error) bool {
+			return errors.Is(err, errBackendsRunning)
+		}, func() error {
+			return checkBackendsExited(ctx, conn, pids)
+		}); err != nil {
+			return "", err
+		}
+	}
+
+	var lsn string
+	if err := conn.QueryRowContext(ctx, "SELECT pg_catalog.pg_current_wal_lsn()").Scan(&lsn); err != nil {
+		return "", fmt.Errorf("while getting the current WAL location: %w", err)
+	}
+
+	return lsn, nil
+}
+
+// terminateClientBackends terminates every client backend, except the one
+// serving the passed connection, returning their PIDs. The WAL senders,
+// including the ones used by logical replication, are kept.
+func terminateClientBackends(ctx context.Context, conn *sql.Conn) ([]string, error) {
+	rows, err := conn.QueryContext(ctx,
+		"SELECT pid FROM pg_catalog.pg_stat_activity "+
+			"WHERE backend_type = 'client backend' "+
+			"AND pid <> pg_catalog.pg_backend_pid() "+
+			"AND pg_catalog.pg_terminate_backend(pid)")
+	if err != nil {
+		return nil, fmt.Errorf("while terminating the client backends: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var pids []string
+	for rows.Next() {
+		var pid string
+		if err := rows.Scan(&pid); err != nil {
+			return nil, err
+		}
+		pids = append(pids, pid)
+	}
+
+	return pids, rows.Err()
+}
+
+// checkBackendsExited returns errBackendsRunning if any
+// of the passed backends is still running
+func checkBackendsExited(ctx context.Context, conn *sql.Conn, pids []string) error {
+	var running int
+	if err := conn.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM pg_catalog.pg_stat_activity "+
+			"WHERE pid::text = ANY(pg_catalog.string_to_array($1, ','))",
+		strings.Join(pids, ",")).Scan(&running); err != nil {
+		return err
+	}
+	if running > 0 {
+		return errBackendsRunning
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("stopping the writes", func() {
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		DeferCleanup(func(backoff wait.Backoff) {
+			terminatedBackendsBackoff = backoff
+		}, terminatedBackendsBackoff)
+		terminatedBackendsBackoff = wait.Backoff{Duration: time.Millisecond, Steps: 3}
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("refuses to stop the writes if new transactions are not read-only", func(ctx context.Context) {
+		mock.ExpectQuery(`current_setting\('default_transaction_read_only'\)`).
+			WillReturnRows(sqlmock.NewRows([]string{"current_setting"}).AddRow("off"))
+
+		_, err := stopWrites(ctx, db)
+		Expect(err).To(MatchError(ErrNotReadOnly))
+	})
+
+	It("reads the WAL location after the terminated backends exited", func(ctx context.Context) {
+		mock.ExpectQuery(`current_setting\('default_transaction_read_only'\)`).
+			WillReturnRows(sqlmock.NewRows([]string{"current_setting"}).AddRow("on"))
+		mock.ExpectQuery(`backend_type = 'client backend'.*pg_terminate_backend\(pid\)`).
+			WillReturnRows(sqlmock.NewRows([]string{"pid"}).AddRow("42").AddRow("43"))
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM pg_catalog.pg_stat_activity`).
+			WithArgs("42,43").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM pg_catalog.pg_stat_activity`).
+			WithArgs("42,43").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectQuery(`pg_current_wal_lsn\(\)`).
+			WillReturnRows(sqlmock.NewRows([]string{"pg_current_wal_lsn"}).AddRow("0/3000060"))
+
+		lsn, err := stopWrites(ctx, db)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lsn).To(Equal("0/3000060"))
+	})
+
+	It("fails if the terminated backends don't exit", func(ctx context.Context) {
+		mock.ExpectQuery(`current_setting\('default_transaction_read_only'\)`).
+			WillReturnRows(sqlmock.NewRows([]string{"current_setting"}).AddRow("on"))
+		mock.ExpectQuery(`backend_type = 'client backend'.*pg_terminate_backend\(pid\)`).
+			WillReturnRows(sqlmock.NewRows([]string{"pid"}).AddRow("42"))
+		for range terminatedBackendsBackoff.Steps {
+			mock.ExpectQuery(`SELECT COUNT\(\*\) FROM pg_catalog.pg_stat_activity`).
+				WithArgs("42").
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		}
+
+		_, err := stopWrites(ctx, db)
+		Expect(err).To(MatchError(errBackendsRunning))
+	})
+})
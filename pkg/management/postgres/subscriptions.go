@@ -0,0 +1,248 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/cloudnative-pg/machinery/pkg/types"
+	"github.com/jackc/pgx/v5"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// subscriptionTableStates maps the states of pg_subscription_rel
+// to the ones reported in the Subscription status
+var subscriptionTableStates = map[string]apiv1.SubscriptionTableState{
+	"i": apiv1.SubscriptionTableStateInitialize,
+	"d": apiv1.SubscriptionTableStateDataCopy,
+	"f": apiv1.SubscriptionTableStateFinishedCopy,
+	"s": apiv1.SubscriptionTableStateSynchronized,
+	"r": apiv1.SubscriptionTableStateReady,
+}
+
+// SubscriptionStats are the statistics of the main apply worker of a
+// subscription, read from pg_stat_subscription and pg_stat_subscription_stats
+type SubscriptionStats struct {
+	// Name is the name of the subscription
+	Name string
+
+	// DatabaseName is the database where the subscription is defined
+	DatabaseName string
+
+	// Enabled is true if the subscription is enabled
+	Enabled bool
+
+	// ReceivedLSN is the last write-ahead log location received
+	ReceivedLSN types.LSN
+
+	// LatestEndLSN is the last write-ahead log location reported to the publisher
+	LatestEndLSN types.LSN
+
+	// LatestEndTime is the time of the last write-ahead log location
+	// reported to the publisher
+	LatestEndTime sql.NullTime
+
+	// ApplyErrorCount is the number of errors that occurred while applying
+	// changes. Always zero before PostgreSQL 15
+	ApplyErrorCount int64
+
+	// SyncErrorCount is the number of errors that occurred during the initial
+	// table synchronization. Always zero before PostgreSQL 15
+	SyncErrorCount int64
+}
+
+// SubscriptionTable is the synchronization state of a table of a subscription
+type SubscriptionTable struct {
+	// SubscriptionName is the name of the subscription
+	SubscriptionName string
+
+	// Schema is the schema of the table
+	Schema string
+
+	// Name is the name of the table
+	Name string
+
+	// State is the synchronization state of the table
+	State apiv1.SubscriptionTableState
+
+	// LSN is the remote LSN of the state change, if any
+	LSN types.LSN
+}
+
+// GetSubscriptionStats gets the statistics of every subscription in the
+// PostgreSQL instance. Only the main apply worker of each subscription is
+// considered, ignoring the table synchronization and parallel apply workers.
+func GetSubscriptionStats(ctx context.Context, db *sql.DB, majorVersion int) ([]SubscriptionStats, error) {
+	workerFilter := ""
+	if majorVersion >= 16 {
+		workerFilter = "AND ss.leader_pid IS NULL"
+	}
+
+	errorCounts := "0, 0"
+	errorCountsJoin := ""
+	if majorVersion >= 15 {
+		errorCounts = "COALESCE(sst.apply_error_count, 0), COALESCE(sst.sync_error_count, 0)"
+		errorCountsJoin = "LEFT JOIN pg_catalog.pg_stat_subscription_stats sst ON sst.subid = s.oid"
+	}
+
+	rows, err := db.QueryContext(
+		ctx,
+		fmt.Sprintf(
+			`SELECT s.subname, d.datname, s.subenabled,
+			COALESCE(ss.received_lsn::text, ''),
+			COALESCE(ss.latest_end_lsn::text, ''),
+			ss.latest_end_time,
+			%s
+			FROM pg_catalog.pg_subscription s
+			JOIN pg_catalog.pg_database d ON d.oid = s.subdbid
+			LEFT JOIN pg_catalog.pg_stat_subscription ss
+				ON ss.subid = s.oid AND ss.relid IS NULL %s
+			%s
+			ORDER BY s.subname`,
+			errorCounts,
+			workerFilter,
+			errorCountsJoin,
+		))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var result []SubscriptionStats
+	for rows.Next() {
+		var stats SubscriptionStats
+		if err := rows.Scan(
+			&stats.Name,
+			&stats.DatabaseName,
+			&stats.Enabled,
+			&stats.ReceivedLSN,
+			&stats.LatestEndLSN,
+			&stats.LatestEndTime,
+			&stats.ApplyErrorCount,
+			&stats.SyncErrorCount,
+		); err != nil {
+			return nil, err
+		}
+		result = append(result, stats)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetSubscriptionTables gets the synchronization state of the tables of
+// the subscriptions defined in the database the connection refers to,
+// as pg_subscription_rel is not a shared catalog
+func GetSubscriptionTables(ctx context.Context, db *sql.DB) ([]SubscriptionTable, error) {
+	rows, err := db.QueryContext(
+		ctx,
+		`SELECT s.subname, n.nspname, c.relname, sr.srsubstate,
+		COALESCE(sr.srsublsn::text, '')
+		FROM pg_catalog.pg_subscription_rel sr
+		JOIN pg_catalog.pg_subscription s ON s.oid = sr.srsubid
+		JOIN pg_catalog.pg_class c ON c.oid = sr.srrelid
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		ORDER BY s.subname, n.nspname, c.relname`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var result []SubscriptionTable
+	for rows.Next() {
+		var (
+			table SubscriptionTable
+			state string
+		)
+		if err := rows.Scan(
+			&table.SubscriptionName,
+			&table.Schema,
+			&table.Name,
+			&state,
+			&table.LSN,
+		); err != nil {
+			return nil, err
+		}
+
+		var ok bool
+		if table.State, ok = subscriptionTableStates[state]; !ok {
+			return nil, fmt.Errorf("unknown state %q of table %s.%s in subscription %s",
+				state, table.Schema, table.Name, table.SubscriptionName)
+		}
+		result = append(result, table)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetSequenceValues gets the last value of the sequences defined in the
+// database the connection refers to, indexed by their quoted qualified
+// name. The value is nil for the sequences that have never been used,
+// or that the current user is not allowed to read.
+func GetSequenceValues(ctx context.Context, db *sql.DB) (map[string]*int64, error) {
+	rows, err := db.QueryContext(
+		ctx,
+		`SELECT schemaname, sequencename, last_value
+		FROM pg_catalog.pg_sequences`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result := make(map[string]*int64)
+	for rows.Next() {
+		var (
+			schema string
+			name   string
+			value  sql.NullInt64
+		)
+		if err := rows.Scan(&schema, &name, &value); err != nil {
+			return nil, err
+		}
+
+		var lastValue *int64
+		if value.Valid {
+			lastValue = &value.Int64
+		}
+		result[pgx.Identifier{schema, name}.Sanitize()] = lastValue
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,146 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cloudnative-pg/machinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("subscription statistics", func() {
+	var (
+		db        *sql.DB
+		mock      sqlmock.Sqlmock
+		lastQuery string
+	)
+
+	statsColumns := []string{
+		"subname", "datname", "subenabled", "received_lsn", "latest_end_lsn", "latest_end_time",
+		"apply_error_count", "sync_error_count",
+	}
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherFunc(
+			func(expectedSQL, actualSQL string) error {
+				lastQuery = actualSQL
+				return sqlmock.QueryMatcherRegexp.Match(expectedSQL, actualSQL)
+			})))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("reads the error counts and ignores the parallel apply workers on recent versions",
+		func(ctx context.Context) {
+			latestEndTime := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+			mock.ExpectQuery(`(?s)COALESCE\(sst.apply_error_count, 0\).*ss.leader_pid IS NULL.*` +
+				`pg_stat_subscription_stats`).
+				WillReturnRows(sqlmock.NewRows(statsColumns).
+					AddRow("sub", "app", true, "0/3000060", "0/3000028", latestEndTime, 2, 1))
+
+			stats, err := GetSubscriptionStats(ctx, db, 17)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stats).To(ConsistOf(SubscriptionStats{
+				Name:            "sub",
+				DatabaseName:    "app",
+				Enabled:         true,
+				ReceivedLSN:     types.LSN("0/3000060"),
+				LatestEndLSN:    types.LSN("0/3000028"),
+				LatestEndTime:   sql.NullTime{Time: latestEndTime, Valid: true},
+				ApplyErrorCount: 2,
+				SyncErrorCount:  1,
+			}))
+		})
+
+	It("doesn't read pg_stat_subscription_stats before PostgreSQL 15", func(ctx context.Context) {
+		mock.ExpectQuery(`(?s)0, 0\s+FROM pg_catalog.pg_subscription s`).
+			WillReturnRows(sqlmock.NewRows(statsColumns).
+				AddRow("sub", "app", false, "", "", nil, 0, 0))
+
+		stats, err := GetSubscriptionStats(ctx, db, 14)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lastQuery).ToNot(ContainSubstring("pg_stat_subscription_stats"))
+		Expect(lastQuery).ToNot(ContainSubstring("leader_pid"))
+		Expect(stats).To(HaveLen(1))
+		Expect(stats[0].Enabled).To(BeFalse())
+		Expect(stats[0].LatestEndTime.Valid).To(BeFalse())
+	})
+
+	It("maps the synchronization state of the tables", func(ctx context.Context) {
+		mock.ExpectQuery("FROM pg_catalog.pg_subscription_rel").
+			WillReturnRows(sqlmock.NewRows([]string{"subname", "nspname", "relname", "srsubstate", "srsublsn"}).
+				AddRow("sub", "public", "orders", "r", "0/3000028").
+				AddRow("sub", "public", "customers", "d", ""))
+
+		tables, err := GetSubscriptionTables(ctx, db)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tables).To(Equal([]SubscriptionTable{
+			{
+				SubscriptionName: "sub",
+				Schema:           "public",
+				Name:             "orders",
+				State:            apiv1.SubscriptionTableStateReady,
+				LSN:              types.LSN("0/3000028"),
+			},
+			{
+				SubscriptionName: "sub",
+				Schema:           "public",
+				Name:             "customers",
+				State:            apiv1.SubscriptionTableStateDataCopy,
+			},
+		}))
+	})
+
+	It("fails on an unknown synchronization state", func(ctx context.Context) {
+		mock.ExpectQuery("FROM pg_catalog.pg_subscription_rel").
+			WillReturnRows(sqlmock.NewRows([]string{"subname", "nspname", "relname", "srsubstate", "srsublsn"}).
+				AddRow("sub", "public", "orders", "x", ""))
+
+		_, err := GetSubscriptionTables(ctx, db)
+		Expect(err).To(MatchError(ContainSubstring(`unknown state "x"`)))
+	})
+
+	It("reads the last value of the sequences", func(ctx context.Context) {
+		mock.ExpectQuery("FROM pg_catalog.pg_sequences").
+			WillReturnRows(sqlmock.NewRows([]string{"schemaname", "sequencename", "last_value"}).
+				AddRow("public", "orders_id_seq", 42).
+				AddRow("Sales", "items-id", nil))
+
+		values, err := GetSequenceValues(ctx, db)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(values).To(Equal(map[string]*int64{
+			`"public"."orders_id_seq"`: ptr.To[int64](42),
+			`"Sales"."items-id"`:       nil,
+		}))
+	})
+})
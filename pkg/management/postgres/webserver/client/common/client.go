@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/otlp"
 )
 
 // NewHTTPClient returns a client capable of executing HTTP methods both in HTTPS and HTTP depending on the passed
@@ -35,7 +36,7 @@ func NewHTTPClient(connectionTimeout, requestTimeout time.Duration) *http.Client
 	dialer := &net.Dialer{Timeout: connectionTimeout}
 
 	return &http.Client{
-		Transport: &http.Transport{
+		Transport: otlp.NewTracingRoundTripper(&http.Transport{
 			DialContext: dialer.DialContext,
 			DialTLSContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
 				tlsConfig, err := certs.GetTLSConfigFromContext(ctx)
@@ -48,7 +49,7 @@ func NewHTTPClient(connectionTimeout, requestTimeout time.Duration) *http.Client
 				}
 				return tlsDialer.DialContext(ctx, network, addr)
 			},
-		},
+		}),
 		Timeout: requestTimeout,
 	}
 }
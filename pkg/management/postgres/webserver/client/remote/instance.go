@@ -85,6 +85,11 @@ type InstanceClient interface {
 	// ArchivePartialWAL trigger the archiver for the latest partial WAL
 	// file created in a specific Pod
 	ArchivePartialWAL(context.Context, *corev1.Pod) (string, error)
+
+	// StopWrites terminates the client connections of a primary instance
+	// whose new transactions are read-only, returning the WAL location
+	// where the writes stopped
+	StopWrites(context.Context, *corev1.Pod) (string, error)
 }
 
 type instanceClientImpl struct {
@@ -436,3 +441,44 @@ func (r *instanceClientImpl) ArchivePartialWAL(ctx context.Context, pod *corev1.
 
 	return result.Data, nil
 }
+
+func (r *instanceClientImpl) StopWrites(ctx context.Context, pod *corev1.Pod) (string, error) {
+	contextLogger := log.FromContext(ctx)
+
+	statusURL := url.Build(
+		GetStatusSchemeFromPod(pod).ToString(), pod.Status.PodIP, url.PathPgStopWrites, url.StatusPort)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, statusURL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := r.Do(req) //nolint:gosec // URL built from internal pod IP
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			contextLogger.Error(err, "while closing body")
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	type pgStopWritesResponse struct {
+		Data string `json:"data,omitempty"`
+	}
+
+	var result pgStopWritesResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", err
+	}
+
+	return result.Data, nil
+}
@@ -201,6 +201,15 @@ func (ws *localWebserverEndpoints) requestBackup(w http.ResponseWriter, r *http.
 		ws.startPluginBackup(ctx, cluster, &backup)
 		_, _ = fmt.Fprint(w, "OK")
 
+	case apiv1.BackupMethodPgBaseBackup:
+		if !cluster.Spec.Backup.IsPgBaseBackupConfigured() {
+			http.Error(w, "pgBaseBackup backup not configured in the cluster", http.StatusConflict)
+			return
+		}
+
+		NewPgBaseBackupCommand(cluster, &backup, ws.typedClient, ws.eventRecorder).Start(ctx)
+		_, _ = fmt.Fprint(w, "OK")
+
 	default:
 		http.Error(
 			w,
@@ -53,7 +53,7 @@ func New(serverInstance *postgres.Instance, exporter *Exporter) (*MetricsServer,
 		return nil, fmt.Errorf("while registering Go exporters: %w", err)
 	}
 	serveMux := http.NewServeMux()
-	serveMux.Handle(url.PathMetrics, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	serveMux.Handle(url.PathMetrics, newMetricsHandler(registry))
 
 	server := &http.Server{
 		Addr:              fmt.Sprintf(":%d", url.PostgresMetricsPort),
@@ -78,3 +78,12 @@ func New(serverInstance *postgres.Instance, exporter *Exporter) (*MetricsServer,
 
 	return metricServer, nil
 }
+
+// newMetricsHandler creates the handler serving the metrics of the passed
+// registry. The OpenMetrics format is negotiated with the scrapers
+// supporting it, as it is the only one exposing the exemplars
+func newMetricsHandler(registry *prometheus.Registry) http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{
+		EnableOpenMetrics: true,
+	})
+}
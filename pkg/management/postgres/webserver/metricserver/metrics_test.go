@@ -0,0 +1,77 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metricserver
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("metrics handler", func() {
+	var handler http.Handler
+
+	BeforeEach(func() {
+		counter := prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "test_calls_total",
+			Help: "Number of calls",
+		})
+		counter.(prometheus.ExemplarAdder).AddWithExemplar(42, prometheus.Labels{"query_id": "q1"})
+
+		registry := prometheus.NewRegistry()
+		Expect(registry.Register(counter)).To(Succeed())
+		handler = newMetricsHandler(registry)
+	})
+
+	scrape := func(accept string) (string, string) {
+		req := httptest.NewRequest(http.MethodGet, url.PathMetrics, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		body, err := io.ReadAll(rec.Body)
+		Expect(err).ToNot(HaveOccurred())
+		return rec.Header().Get("Content-Type"), string(body)
+	}
+
+	It("exposes the exemplars to the scrapers accepting OpenMetrics", func() {
+		contentType, body := scrape("application/openmetrics-text; version=1.0.0; charset=utf-8")
+		Expect(contentType).To(HavePrefix("application/openmetrics-text"))
+		Expect(body).To(ContainSubstring(`test_calls_total 42.0 # {query_id="q1"} 42.0`))
+		Expect(body).To(HaveSuffix("# EOF\n"))
+	})
+
+	It("keeps the text format for the other scrapers", func() {
+		contentType, body := scrape("")
+		Expect(contentType).To(HavePrefix("text/plain"))
+		Expect(body).To(ContainSubstring("test_calls_total 42\n"))
+		Expect(body).ToNot(ContainSubstring("# {"))
+	})
+})
@@ -79,6 +79,9 @@ type metrics struct {
 	LastFailedBackupTimestamp    prometheus.Gauge
 	FencingOn                    prometheus.Gauge
 	PgStatWalMetrics             PgStatWalMetrics
+	PgStatStatements             *PgStatStatementsMetrics
+	PgStatSubscription           *PgStatSubscriptionMetrics
+	StatementDuration            *prometheus.HistogramVec
 	NodesUsed                    prometheus.Gauge
 }
 
@@ -271,6 +274,9 @@ func newMetrics() *metrics {
 					"fsync_writethrough, otherwise zero). Only available on PG 14 to 17.",
 			}, []string{"stats_reset"}),
 		},
+		PgStatStatements:   newPgStatStatementsMetrics(),
+		PgStatSubscription: newPgStatSubscriptionMetrics(),
+		StatementDuration:  newStatementDurationHistogram(),
 	}
 }
 
@@ -292,6 +298,9 @@ func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
 	e.Metrics.LastFailedBackupTimestamp.Describe(ch)
 	e.Metrics.LastAvailableBackupTimestamp.Describe(ch)
 	e.Metrics.NodesUsed.Describe(ch)
+	e.Metrics.PgStatStatements.describe(ch)
+	e.Metrics.PgStatSubscription.describe(ch)
+	e.Metrics.StatementDuration.Describe(ch)
 
 	if e.queries != nil {
 		e.queries.Describe(ch)
@@ -375,6 +384,9 @@ func (e *Exporter) collectInstanceMetrics(ch chan<- prometheus.Metric) {
 	e.Metrics.LastFailedBackupTimestamp.Collect(ch)
 	e.Metrics.LastAvailableBackupTimestamp.Collect(ch)
 	e.Metrics.NodesUsed.Collect(ch)
+	e.Metrics.PgStatStatements.collect(ch)
+	e.Metrics.PgStatSubscription.collect(ch)
+	e.Metrics.StatementDuration.Collect(ch)
 
 	if version, _ := e.instance.GetPgVersion(); version.Major() >= 14 {
 		e.Metrics.PgStatWalMetrics.WalRecords.Collect(ch)
@@ -454,6 +466,15 @@ func (e *Exporter) updateInstanceMetrics() {
 		e.collectFromPrimaryLastAvailableBackupTimestamp()
 
 		e.collectFromPrimaryLastFailedBackupTimestamp()
+
+		if err := collectPgStatSubscription(e, db); err != nil {
+			log.Error(err, "while collecting the subscription statistics")
+			e.Metrics.Error.Set(1)
+			e.Metrics.PgCollectionErrors.WithLabelValues("Collect.PgStatSubscription").Inc()
+		}
+	} else {
+		// subscriptions are only active on the primary
+		e.Metrics.PgStatSubscription.reset()
 	}
 
 	if err := collectPGWalArchiveMetric(e); err != nil {
@@ -484,6 +505,15 @@ func (e *Exporter) updateInstanceMetrics() {
 			e.Metrics.PgCollectionErrors.WithLabelValues("Collect.PGWALStat").Inc()
 		}
 	}
+
+	pgStatStatementsStart := time.Now()
+	if err := collectPgStatStatements(e, db); err != nil {
+		log.Error(err, "while collecting pg_stat_statements")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.PgStatStatements").Inc()
+	}
+	e.Metrics.CollectionDuration.WithLabelValues("Collect.PgStatStatements").
+		Set(time.Since(pgStatStatementsStart).Seconds())
 }
 
 func (e *Exporter) setTimestampMetric(
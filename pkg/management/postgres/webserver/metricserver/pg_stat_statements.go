@@ -0,0 +1,308 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metricserver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// pgStatStatementsSortColumns maps each sort key to the column of the
+// statistics query used to rank the statements
+var pgStatStatementsSortColumns = map[apiv1.QueryStatisticsSortKey]string{
+	apiv1.QueryStatisticsSortByTotalTime: "total_exec_time",
+	apiv1.QueryStatisticsSortByCalls:     "calls",
+	apiv1.QueryStatisticsSortByIO:        "io_blocks",
+}
+
+// statementKey identifies a statement exported by the collector, and
+// contains the values of the labels of its metrics
+type statementKey struct {
+	queryID  string
+	datname  string
+	username string
+}
+
+// statementStats are the cumulative statistics of a statement
+type statementStats struct {
+	calls         float64
+	totalExecTime float64
+	rows          float64
+	ioBlocks      float64
+}
+
+// delta returns the increment of the statistics since the previous
+// snapshot. When a counter went backwards the statistics have been
+// reset, and the current value is the best estimate of the increment.
+func (s statementStats) delta(previous statementStats) statementStats {
+	increment := func(current, previous float64) float64 {
+		if current < previous {
+			return current
+		}
+		return current - previous
+	}
+
+	return statementStats{
+		calls:         increment(s.calls, previous.calls),
+		totalExecTime: increment(s.totalExecTime, previous.totalExecTime),
+		rows:          increment(s.rows, previous.rows),
+		ioBlocks:      increment(s.ioBlocks, previous.ioBlocks),
+	}
+}
+
+// PgStatStatementsMetrics are the query-level statistics exported
+// from pg_stat_statements. As the statistics are cumulative, they
+// are exported as counters, together with their increments since
+// the previous collection.
+type PgStatStatementsMetrics struct {
+	Calls              *prometheus.Desc
+	ExecTimeSeconds    *prometheus.Desc
+	Rows               *prometheus.Desc
+	IOBlocks           *prometheus.Desc
+	CallsDelta         *prometheus.Desc
+	ExecTimeDelta      *prometheus.Desc
+	RowsDelta          *prometheus.Desc
+	IOBlocksDelta      *prometheus.Desc
+	ExportedStatements prometheus.Gauge
+
+	// snapshot contains the statistics of the top-N statements
+	// taken at the latest collection
+	snapshot map[statementKey]statementStats
+
+	// deltas contains the increments of the statements that
+	// were also part of the previous collection
+	deltas map[statementKey]statementStats
+	mu     sync.Mutex
+}
+
+func newPgStatStatementsMetrics() *PgStatStatementsMetrics {
+	subsystem := "pg_stat_statements"
+	labels := []string{"queryid", "datname", "usename"}
+	newDesc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(
+			prometheus.BuildFQName(PrometheusNamespace, subsystem, name),
+			help,
+			labels,
+			nil)
+	}
+
+	return &PgStatStatementsMetrics{
+		Calls: newDesc("calls_total",
+			"Number of times the statement was executed"),
+		ExecTimeSeconds: newDesc("exec_time_seconds_total",
+			"Total time spent executing the statement, in seconds"),
+		Rows: newDesc("rows_total",
+			"Total number of rows retrieved or affected by the statement"),
+		IOBlocks: newDesc("io_blocks_total",
+			"Total number of shared, local and temporary blocks read and written by the statement"),
+		CallsDelta: newDesc("calls_delta",
+			"Number of times the statement was executed since the previous collection"),
+		ExecTimeDelta: newDesc("exec_time_seconds_delta",
+			"Time spent executing the statement since the previous collection, in seconds"),
+		RowsDelta: newDesc("rows_delta",
+			"Number of rows retrieved or affected by the statement since the previous collection"),
+		IOBlocksDelta: newDesc("io_blocks_delta",
+			"Number of blocks read and written by the statement since the previous collection"),
+		ExportedStatements: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "exported_statements",
+			Help:      "Number of statements exported by the query statistics collector",
+		}),
+	}
+}
+
+func (m *PgStatStatementsMetrics) describe(ch chan<- *prometheus.Desc) {
+	ch <- m.Calls
+	ch <- m.ExecTimeSeconds
+	ch <- m.Rows
+	ch <- m.IOBlocks
+	ch <- m.CallsDelta
+	ch <- m.ExecTimeDelta
+	ch <- m.RowsDelta
+	ch <- m.IOBlocksDelta
+	ch <- m.ExportedStatements.Desc()
+}
+
+func (m *PgStatStatementsMetrics) collect(ch chan<- prometheus.Metric) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, stats := range m.snapshot {
+		labels := []string{key.queryID, key.datname, key.username}
+		ch <- prometheus.MustNewConstMetric(m.Calls, prometheus.CounterValue, stats.calls, labels...)
+		ch <- prometheus.MustNewConstMetric(
+			m.ExecTimeSeconds, prometheus.CounterValue, stats.totalExecTime/1000, labels...)
+		ch <- prometheus.MustNewConstMetric(m.Rows, prometheus.CounterValue, stats.rows, labels...)
+		ch <- prometheus.MustNewConstMetric(m.IOBlocks, prometheus.CounterValue, stats.ioBlocks, labels...)
+	}
+	for key, delta := range m.deltas {
+		labels := []string{key.queryID, key.datname, key.username}
+		ch <- prometheus.MustNewConstMetric(m.CallsDelta, prometheus.GaugeValue, delta.calls, labels...)
+		ch <- prometheus.MustNewConstMetric(
+			m.ExecTimeDelta, prometheus.GaugeValue, delta.totalExecTime/1000, labels...)
+		ch <- prometheus.MustNewConstMetric(m.RowsDelta, prometheus.GaugeValue, delta.rows, labels...)
+		ch <- prometheus.MustNewConstMetric(m.IOBlocksDelta, prometheus.GaugeValue, delta.ioBlocks, labels...)
+	}
+	m.ExportedStatements.Collect(ch)
+}
+
+// reset drops every exported statement and the previous snapshot
+func (m *PgStatStatementsMetrics) reset() {
+	m.update(nil)
+}
+
+// update replaces the exported statements with the passed snapshot.
+// Statements no longer in the top-N are dropped, keeping the cardinality
+// bounded. Deltas are only exported for statements that were already
+// part of the previous snapshot.
+func (m *PgStatStatementsMetrics) update(snapshot map[statementKey]statementStats) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	deltas := make(map[statementKey]statementStats, len(snapshot))
+	for key, stats := range snapshot {
+		if previous, ok := m.snapshot[key]; ok {
+			deltas[key] = stats.delta(previous)
+		}
+	}
+
+	m.snapshot = snapshot
+	m.deltas = deltas
+	m.ExportedStatements.Set(float64(len(snapshot)))
+}
+
+// collectPgStatStatements exports the statistics of the top-N statements
+// tracked by pg_stat_statements, if enabled in the cluster
+func collectPgStatStatements(e *Exporter, db *sql.DB) error {
+	cluster, err := e.getCluster()
+	// there isn't a cached object yet
+	if errors.Is(err, cache.ErrCacheMiss) {
+		return nil
+	}
+	if err != nil {
+		e.Metrics.PgStatStatements.reset()
+		return err
+	}
+
+	config := cluster.GetQueryStatistics()
+	if config == nil {
+		e.Metrics.PgStatStatements.reset()
+		return nil
+	}
+
+	snapshot, err := getPgStatStatementsSnapshot(context.Background(), db, config)
+	if err != nil {
+		e.Metrics.PgStatStatements.reset()
+		return err
+	}
+
+	e.Metrics.PgStatStatements.update(snapshot)
+	return nil
+}
+
+// getPgStatStatementsSnapshot fetches the statistics of the top-N statements.
+// The text of the statements is not read, as it is never exported as a label.
+func getPgStatStatementsSnapshot(
+	ctx context.Context,
+	db *sql.DB,
+	config *apiv1.QueryStatisticsConfiguration,
+) (map[statementKey]statementStats, error) {
+	schema, err := postgres.GetPgStatStatementsSchema(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	if schema == "" {
+		return nil, fmt.Errorf("extension %s is not installed in the postgres database",
+			postgres.PgStatStatementsExtensionName)
+	}
+
+	sortColumn, ok := pgStatStatementsSortColumns[config.GetSortBy()]
+	if !ok {
+		return nil, fmt.Errorf("unknown query statistics sort key: %s", config.GetSortBy())
+	}
+
+	// Statements executed both at top level and nested have two entries
+	// sharing the same queryid, which are aggregated
+	rows, err := db.QueryContext(
+		ctx,
+		fmt.Sprintf(
+			`SELECT s.queryid, d.datname, r.rolname,
+			SUM(s.calls)::float8 AS calls,
+			SUM(s.total_exec_time)::float8 AS total_exec_time,
+			SUM(s.rows)::float8 AS rows,
+			SUM(s.shared_blks_read + s.shared_blks_written +
+				s.local_blks_read + s.local_blks_written +
+				s.temp_blks_read + s.temp_blks_written)::float8 AS io_blocks
+			FROM %s.pg_stat_statements(false) s
+			JOIN pg_catalog.pg_database d ON d.oid = s.dbid
+			JOIN pg_catalog.pg_roles r ON r.oid = s.userid
+			WHERE s.queryid IS NOT NULL
+			GROUP BY s.queryid, d.datname, r.rolname
+			ORDER BY %s DESC
+			LIMIT $1`,
+			pgx.Identifier{schema}.Sanitize(),
+			sortColumn),
+		config.GetTopN())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	snapshot := make(map[statementKey]statementStats, config.GetTopN())
+	for rows.Next() {
+		var (
+			queryID int64
+			key     statementKey
+			stats   statementStats
+		)
+		if err := rows.Scan(
+			&queryID,
+			&key.datname,
+			&key.username,
+			&stats.calls,
+			&stats.totalExecTime,
+			&stats.rows,
+			&stats.ioBlocks,
+		); err != nil {
+			return nil, err
+		}
+		key.queryID = postgres.FormatQueryID(queryID)
+		snapshot[key] = stats
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
@@ -0,0 +1,221 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metricserver
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/metricstest"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pg_stat_statements collector", func() {
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+	)
+
+	statisticsColumns := []string{
+		"queryid", "datname", "rolname", "calls", "total_exec_time", "rows", "io_blocks",
+	}
+
+	expectSchema := func() {
+		mock.ExpectQuery("FROM pg_catalog.pg_extension").
+			WithArgs(postgres.PgStatStatementsExtensionName).
+			WillReturnRows(sqlmock.NewRows([]string{"nspname"}).AddRow("public"))
+	}
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("ranks the statements by the configured sort key and limits them to the top-N", func(ctx context.Context) {
+		expectSchema()
+		mock.ExpectQuery(`ORDER BY calls DESC\s+LIMIT \$1`).
+			WithArgs(5).
+			WillReturnRows(sqlmock.NewRows(statisticsColumns).
+				AddRow(int64(-2), "app", "app", 10.0, 1500.0, 10.0, 3.0))
+
+		snapshot, err := getPgStatStatementsSnapshot(ctx, db, &apiv1.QueryStatisticsConfiguration{
+			Enabled: true,
+			TopN:    5,
+			SortBy:  apiv1.QueryStatisticsSortByCalls,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(snapshot).To(HaveKeyWithValue(
+			statementKey{queryID: "fffffffffffffffe", datname: "app", username: "app"},
+			statementStats{calls: 10, totalExecTime: 1500, rows: 10, ioBlocks: 3},
+		))
+	})
+
+	It("fails when the extension is not installed", func(ctx context.Context) {
+		mock.ExpectQuery("FROM pg_catalog.pg_extension").
+			WithArgs(postgres.PgStatStatementsExtensionName).
+			WillReturnRows(sqlmock.NewRows([]string{"nspname"}))
+
+		_, err := getPgStatStatementsSnapshot(ctx, db, &apiv1.QueryStatisticsConfiguration{Enabled: true})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("exports the statistics as counters and drops the statements leaving the top-N", func() {
+		metrics := newPgStatStatementsMetrics()
+		first := statementKey{queryID: "0000000000000001", datname: "app", username: "app"}
+		second := statementKey{queryID: "0000000000000002", datname: "app", username: "app"}
+
+		metrics.update(map[statementKey]statementStats{
+			first:  {calls: 10, totalExecTime: 2000, rows: 10, ioBlocks: 5},
+			second: {calls: 1, totalExecTime: 1000, rows: 1, ioBlocks: 1},
+		})
+		families, err := metricstest.Gather(pgStatStatementsCollector{metrics})
+		Expect(err).ToNot(HaveOccurred())
+		execTime := families["cnpg_pg_stat_statements_exec_time_seconds_total"]
+		Expect(execTime.GetType()).To(Equal(dto.MetricType_COUNTER))
+		Expect(execTime.GetMetric()).To(HaveLen(2))
+		Expect(counterValue(execTime, first.queryID)).To(BeEquivalentTo(2))
+		Expect(counterValue(execTime, second.queryID)).To(BeEquivalentTo(1))
+		Expect(metricstest.Value(metrics.ExportedStatements)).To(BeEquivalentTo(2))
+
+		metrics.update(map[statementKey]statementStats{
+			first: {calls: 15, totalExecTime: 2500, rows: 12, ioBlocks: 5},
+		})
+		families, err = metricstest.Gather(pgStatStatementsCollector{metrics})
+		Expect(err).ToNot(HaveOccurred())
+		calls := families["cnpg_pg_stat_statements_calls_total"]
+		Expect(calls.GetMetric()).To(HaveLen(1))
+		Expect(counterValue(calls, first.queryID)).To(BeEquivalentTo(15))
+		Expect(metricstest.Value(metrics.ExportedStatements)).To(BeEquivalentTo(1))
+
+		metrics.reset()
+		Expect(metricstest.Count(pgStatStatementsCollector{metrics})).To(Equal(1))
+	})
+
+	It("exports the increments since the previous collection", func() {
+		metrics := newPgStatStatementsMetrics()
+		first := statementKey{queryID: "0000000000000001", datname: "app", username: "app"}
+		second := statementKey{queryID: "0000000000000002", datname: "app", username: "app"}
+
+		metrics.update(map[statementKey]statementStats{
+			first: {calls: 10, totalExecTime: 2000, rows: 10, ioBlocks: 5},
+		})
+		Expect(metricstest.Count(pgStatStatementsCollector{metrics}, "cnpg_pg_stat_statements_calls_delta")).
+			To(BeZero())
+
+		metrics.update(map[statementKey]statementStats{
+			first:  {calls: 15, totalExecTime: 3500, rows: 12, ioBlocks: 5},
+			second: {calls: 1, totalExecTime: 1000, rows: 1, ioBlocks: 1},
+		})
+		families, err := metricstest.Gather(pgStatStatementsCollector{metrics})
+		Expect(err).ToNot(HaveOccurred())
+		callsDelta := families["cnpg_pg_stat_statements_calls_delta"]
+		Expect(callsDelta.GetType()).To(Equal(dto.MetricType_GAUGE))
+		Expect(callsDelta.GetMetric()).To(HaveLen(1))
+		Expect(gaugeValue(callsDelta, first.queryID)).To(BeEquivalentTo(5))
+		Expect(gaugeValue(families["cnpg_pg_stat_statements_exec_time_seconds_delta"], first.queryID)).
+			To(BeEquivalentTo(1.5))
+		Expect(gaugeValue(families["cnpg_pg_stat_statements_rows_delta"], first.queryID)).
+			To(BeEquivalentTo(2))
+		Expect(gaugeValue(families["cnpg_pg_stat_statements_io_blocks_delta"], first.queryID)).
+			To(BeZero())
+
+		// The statistics have been reset
+		metrics.update(map[statementKey]statementStats{
+			first: {calls: 3, totalExecTime: 500, rows: 3, ioBlocks: 1},
+		})
+		families, err = metricstest.Gather(pgStatStatementsCollector{metrics})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(gaugeValue(families["cnpg_pg_stat_statements_calls_delta"], first.queryID)).
+			To(BeEquivalentTo(3))
+
+		metrics.reset()
+		metrics.update(map[statementKey]statementStats{
+			first: {calls: 20, totalExecTime: 4000, rows: 20, ioBlocks: 6},
+		})
+		Expect(metricstest.Count(pgStatStatementsCollector{metrics}, "cnpg_pg_stat_statements_calls_delta")).
+			To(BeZero())
+	})
+
+	It("does nothing when query statistics are disabled", func() {
+		exporter := &Exporter{
+			Metrics: newMetrics(),
+			getCluster: func() (*apiv1.Cluster, error) {
+				return &apiv1.Cluster{}, nil
+			},
+		}
+		Expect(collectPgStatStatements(exporter, db)).To(Succeed())
+		Expect(metricstest.Value(exporter.Metrics.PgStatStatements.ExportedStatements)).To(BeZero())
+	})
+})
+
+// pgStatStatementsCollector exposes the query statistics
+// as a collector, to be gathered in the tests
+type pgStatStatementsCollector struct {
+	metrics *PgStatStatementsMetrics
+}
+
+func (c pgStatStatementsCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.metrics.describe(ch)
+}
+
+func (c pgStatStatementsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.metrics.collect(ch)
+}
+
+// counterValue returns the value of the counter having the passed queryid
+func counterValue(family *dto.MetricFamily, queryID string) float64 {
+	if metric := findStatementMetric(family, queryID); metric != nil {
+		return metric.GetCounter().GetValue()
+	}
+	return -1
+}
+
+// gaugeValue returns the value of the gauge having the passed queryid
+func gaugeValue(family *dto.MetricFamily, queryID string) float64 {
+	if metric := findStatementMetric(family, queryID); metric != nil {
+		return metric.GetGauge().GetValue()
+	}
+	return -1
+}
+
+// findStatementMetric returns the metric having the passed queryid
+func findStatementMetric(family *dto.MetricFamily, queryID string) *dto.Metric {
+	for _, metric := range family.GetMetric() {
+		for _, label := range metric.GetLabel() {
+			if label.GetName() == "queryid" && label.GetValue() == queryID {
+				return metric
+			}
+		}
+	}
+	return nil
+}
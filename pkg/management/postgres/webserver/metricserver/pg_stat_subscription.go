@@ -0,0 +1,230 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metricserver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"github.com/cloudnative-pg/machinery/pkg/types"
+	"github.com/prometheus/client_golang/prometheus"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// subscriptionTableStates are the synchronization states of the
+// tables of a subscription, always exported to ease alerting
+var subscriptionTableStates = []apiv1.SubscriptionTableState{
+	apiv1.SubscriptionTableStateInitialize,
+	apiv1.SubscriptionTableStateDataCopy,
+	apiv1.SubscriptionTableStateFinishedCopy,
+	apiv1.SubscriptionTableStateSynchronized,
+	apiv1.SubscriptionTableStateReady,
+}
+
+// PgStatSubscriptionMetrics are the statistics of the logical replication
+// subscriptions, exported from pg_stat_subscription, pg_stat_subscription_stats
+// and pg_subscription_rel
+type PgStatSubscriptionMetrics struct {
+	Enabled         *prometheus.GaugeVec
+	ReceivedLSN     *prometheus.GaugeVec
+	LatestEndLSN    *prometheus.GaugeVec
+	LatestEndTime   *prometheus.GaugeVec
+	ApplyErrorCount *prometheus.GaugeVec
+	SyncErrorCount  *prometheus.GaugeVec
+	Tables          *prometheus.GaugeVec
+
+	mu sync.Mutex
+}
+
+func newPgStatSubscriptionMetrics() *PgStatSubscriptionMetrics {
+	subsystem := "pg_stat_subscription"
+	labels := []string{"subname", "datname"}
+	newGaugeVec := func(name, help string, labels []string) *prometheus.GaugeVec {
+		return prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      name,
+			Help:      help,
+		}, labels)
+	}
+
+	return &PgStatSubscriptionMetrics{
+		Enabled: newGaugeVec("enabled",
+			"1 if the subscription is enabled, 0 otherwise", labels),
+		ReceivedLSN: newGaugeVec("received_lsn",
+			"Last write-ahead log location received by the apply worker, in bytes", labels),
+		LatestEndLSN: newGaugeVec("latest_end_lsn",
+			"Last write-ahead log location reported to the publisher, in bytes", labels),
+		LatestEndTime: newGaugeVec("latest_end_time",
+			"Time of the last write-ahead log location reported to the publisher, as a unix timestamp", labels),
+		ApplyErrorCount: newGaugeVec("apply_error_count",
+			"Number of errors that occurred while applying changes. Available from PG 15", labels),
+		SyncErrorCount: newGaugeVec("sync_error_count",
+			"Number of errors that occurred during the initial table synchronization. Available from PG 15", labels),
+		Tables: newGaugeVec("tables",
+			"Number of tables of the subscription in each synchronization state",
+			[]string{"subname", "datname", "state"}),
+	}
+}
+
+func (m *PgStatSubscriptionMetrics) describe(ch chan<- *prometheus.Desc) {
+	m.Enabled.Describe(ch)
+	m.ReceivedLSN.Describe(ch)
+	m.LatestEndLSN.Describe(ch)
+	m.LatestEndTime.Describe(ch)
+	m.ApplyErrorCount.Describe(ch)
+	m.SyncErrorCount.Describe(ch)
+	m.Tables.Describe(ch)
+}
+
+func (m *PgStatSubscriptionMetrics) collect(ch chan<- prometheus.Metric) {
+	m.Enabled.Collect(ch)
+	m.ReceivedLSN.Collect(ch)
+	m.LatestEndLSN.Collect(ch)
+	m.LatestEndTime.Collect(ch)
+	m.ApplyErrorCount.Collect(ch)
+	m.SyncErrorCount.Collect(ch)
+	m.Tables.Collect(ch)
+}
+
+// reset drops every exported subscription
+func (m *PgStatSubscriptionMetrics) reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.resetGauges()
+}
+
+func (m *PgStatSubscriptionMetrics) resetGauges() {
+	m.Enabled.Reset()
+	m.ReceivedLSN.Reset()
+	m.LatestEndLSN.Reset()
+	m.LatestEndTime.Reset()
+	m.ApplyErrorCount.Reset()
+	m.SyncErrorCount.Reset()
+	m.Tables.Reset()
+}
+
+// update replaces the exported subscriptions with the passed statistics
+// and tables, grouped by database name, dropping the subscriptions that
+// don't exist anymore. The LSNs and the time are only exported while the
+// apply worker is running.
+func (m *PgStatSubscriptionMetrics) update(
+	stats []postgres.SubscriptionStats,
+	tables map[string][]postgres.SubscriptionTable,
+) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.resetGauges()
+	setLSN := func(gauge *prometheus.GaugeVec, lsn types.LSN, labels []string) {
+		if lsn == "" {
+			return
+		}
+		if value, err := lsn.Parse(); err == nil {
+			gauge.WithLabelValues(labels...).Set(float64(value))
+		}
+	}
+
+	for _, subscription := range stats {
+		labels := []string{subscription.Name, subscription.DatabaseName}
+		enabled := 0.0
+		if subscription.Enabled {
+			enabled = 1
+		}
+		m.Enabled.WithLabelValues(labels...).Set(enabled)
+		setLSN(m.ReceivedLSN, subscription.ReceivedLSN, labels)
+		setLSN(m.LatestEndLSN, subscription.LatestEndLSN, labels)
+		if subscription.LatestEndTime.Valid {
+			m.LatestEndTime.WithLabelValues(labels...).Set(float64(subscription.LatestEndTime.Time.Unix()))
+		}
+		m.ApplyErrorCount.WithLabelValues(labels...).Set(float64(subscription.ApplyErrorCount))
+		m.SyncErrorCount.WithLabelValues(labels...).Set(float64(subscription.SyncErrorCount))
+
+		counts := make(map[apiv1.SubscriptionTableState]int, len(subscriptionTableStates))
+		for _, table := range tables[subscription.DatabaseName] {
+			if table.SubscriptionName == subscription.Name {
+				counts[table.State]++
+			}
+		}
+		for _, state := range subscriptionTableStates {
+			m.Tables.WithLabelValues(append(labels, string(state))...).Set(float64(counts[state]))
+		}
+	}
+}
+
+// collectPgStatSubscription exports the statistics of the subscriptions
+// defined in every database of the instance
+func collectPgStatSubscription(e *Exporter, db *sql.DB) error {
+	ctx := context.Background()
+
+	version, err := e.instance.GetPgVersion()
+	if err != nil {
+		e.Metrics.PgStatSubscription.reset()
+		return err
+	}
+
+	stats, err := postgres.GetSubscriptionStats(ctx, db, int(version.Major())) //nolint:gosec
+	if err != nil {
+		e.Metrics.PgStatSubscription.reset()
+		return err
+	}
+
+	// pg_subscription_rel is not a shared catalog, so the tables
+	// need to be read from every database having a subscription
+	tables := make(map[string][]postgres.SubscriptionTable, len(stats))
+	for _, subscription := range stats {
+		if _, ok := tables[subscription.DatabaseName]; ok {
+			continue
+		}
+
+		databaseTables, err := getDatabaseSubscriptionTables(ctx, e, subscription.DatabaseName)
+		if err != nil {
+			e.Metrics.PgStatSubscription.reset()
+			return err
+		}
+		tables[subscription.DatabaseName] = databaseTables
+	}
+
+	e.Metrics.PgStatSubscription.update(stats, tables)
+	return nil
+}
+
+func getDatabaseSubscriptionTables(
+	ctx context.Context,
+	e *Exporter,
+	dbname string,
+) ([]postgres.SubscriptionTable, error) {
+	db, err := e.instance.GetMetricsDB(dbname)
+	if err != nil {
+		return nil, fmt.Errorf("while connecting to database %s: %w", dbname, err)
+	}
+
+	tables, err := postgres.GetSubscriptionTables(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("while getting the subscription tables of database %s: %w", dbname, err)
+	}
+
+	return tables, nil
+}
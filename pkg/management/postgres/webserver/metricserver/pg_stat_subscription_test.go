@@ -0,0 +1,99 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metricserver
+
+import (
+	"database/sql"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/metricstest"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pg_stat_subscription collector", func() {
+	It("exports the statistics and the table states of every subscription", func() {
+		metrics := newPgStatSubscriptionMetrics()
+		latestEndTime := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+		metrics.update(
+			[]postgres.SubscriptionStats{
+				{
+					Name:            "sub",
+					DatabaseName:    "app",
+					Enabled:         true,
+					ReceivedLSN:     "0/3000060",
+					LatestEndLSN:    "0/3000028",
+					LatestEndTime:   sql.NullTime{Time: latestEndTime, Valid: true},
+					ApplyErrorCount: 2,
+					SyncErrorCount:  1,
+				},
+				{
+					Name:         "sub",
+					DatabaseName: "other",
+				},
+			},
+			map[string][]postgres.SubscriptionTable{
+				"app": {
+					{SubscriptionName: "sub", Name: "orders", State: apiv1.SubscriptionTableStateReady},
+					{SubscriptionName: "sub", Name: "items", State: apiv1.SubscriptionTableStateReady},
+					{SubscriptionName: "sub", Name: "customers", State: apiv1.SubscriptionTableStateDataCopy},
+				},
+				"other": {
+					{SubscriptionName: "sub", Name: "logs", State: apiv1.SubscriptionTableStateInitialize},
+				},
+			},
+		)
+
+		Expect(metricstest.Value(metrics.Enabled.WithLabelValues("sub", "app"))).To(BeEquivalentTo(1))
+		Expect(metricstest.Value(metrics.ReceivedLSN.WithLabelValues("sub", "app"))).
+			To(BeEquivalentTo(0x3000060))
+		Expect(metricstest.Value(metrics.LatestEndLSN.WithLabelValues("sub", "app"))).
+			To(BeEquivalentTo(0x3000028))
+		Expect(metricstest.Value(metrics.LatestEndTime.WithLabelValues("sub", "app"))).
+			To(BeEquivalentTo(latestEndTime.Unix()))
+		Expect(metricstest.Value(metrics.ApplyErrorCount.WithLabelValues("sub", "app"))).To(BeEquivalentTo(2))
+		Expect(metricstest.Value(metrics.SyncErrorCount.WithLabelValues("sub", "app"))).To(BeEquivalentTo(1))
+		Expect(metricstest.Value(metrics.Tables.WithLabelValues("sub", "app", "ready"))).To(BeEquivalentTo(2))
+		Expect(metricstest.Value(metrics.Tables.WithLabelValues("sub", "app", "dataCopy"))).To(BeEquivalentTo(1))
+		Expect(metricstest.Value(metrics.Tables.WithLabelValues("sub", "app", "initialize"))).To(BeZero())
+		Expect(metricstest.Value(metrics.Tables.WithLabelValues("sub", "other", "initialize"))).
+			To(BeEquivalentTo(1))
+
+		// the apply worker of the disabled subscription is not running
+		Expect(metricstest.Value(metrics.Enabled.WithLabelValues("sub", "other"))).To(BeZero())
+		Expect(metricstest.Count(metrics.ReceivedLSN)).To(Equal(1))
+		Expect(metricstest.Count(metrics.LatestEndTime)).To(Equal(1))
+	})
+
+	It("drops the subscriptions that don't exist anymore", func() {
+		metrics := newPgStatSubscriptionMetrics()
+		metrics.update([]postgres.SubscriptionStats{{Name: "sub", DatabaseName: "app"}}, nil)
+		Expect(metricstest.Count(metrics.Enabled)).To(Equal(1))
+		Expect(metricstest.Count(metrics.Tables)).To(Equal(5))
+
+		metrics.update(nil, nil)
+		Expect(metricstest.Count(metrics.Enabled)).To(BeZero())
+		Expect(metricstest.Count(metrics.Tables)).To(BeZero())
+	})
+})
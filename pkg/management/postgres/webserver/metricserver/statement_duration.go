@@ -0,0 +1,64 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metricserver
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/logpipe"
+)
+
+// newStatementDurationHistogram creates the histogram of the statement
+// durations, fed from the PostgreSQL logs
+func newStatementDurationHistogram() *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: PrometheusNamespace,
+		Subsystem: "collector",
+		Name:      "statement_duration_seconds",
+		Help: "Duration of the statements logged by PostgreSQL, as reported in the " +
+			"'duration:' log records (requires log_min_duration_statement or log_duration)",
+		Buckets: prometheus.ExponentialBuckets(0.001, 4, 10),
+	}, []string{"datname"})
+}
+
+// statementDurationObserver implements the logpipe.RecordWriter
+// interface, observing the durations of the statements in the histogram
+type statementDurationObserver struct {
+	histogram *prometheus.HistogramVec
+}
+
+// Write implements the logpipe.RecordWriter interface
+func (o statementDurationObserver) Write(record logpipe.NamedRecord) {
+	// Only the statement duration records are considered, as the
+	// auto_explain ones may report the same statements
+	durationRecord, ok := record.(*logpipe.StatementDurationRecord)
+	if !ok {
+		return
+	}
+
+	o.histogram.WithLabelValues(durationRecord.DatabaseName).
+		Observe(durationRecord.Duration.DurationMs / 1000)
+}
+
+// StatementDurationObserver returns a log record writer feeding the
+// histogram of the statement durations
+func (e *Exporter) StatementDurationObserver() logpipe.RecordWriter {
+	return statementDurationObserver{histogram: e.Metrics.StatementDuration}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metricserver
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/metricstest"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/logpipe"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("statement duration histogram", func() {
+	It("observes only the statement duration records", func() {
+		histogram := newStatementDurationHistogram()
+		observer := statementDurationObserver{histogram: histogram}
+
+		observer.Write(&logpipe.StatementDurationRecord{
+			LoggingRecord: &logpipe.LoggingRecord{DatabaseName: "app"},
+			Duration:      &logpipe.StatementDuration{DurationMs: 1500},
+		})
+		observer.Write(&logpipe.AutoExplainRecord{
+			LoggingRecord: &logpipe.LoggingRecord{DatabaseName: "app"},
+			Explain:       &logpipe.AutoExplainPlan{DurationMs: 1500},
+		})
+		observer.Write(&logpipe.LoggingRecord{DatabaseName: "app"})
+
+		Expect(metricstest.Count(histogram)).To(Equal(1))
+
+		var metric dto.Metric
+		observed := histogram.WithLabelValues("app").(prometheus.Metric)
+		Expect(observed.Write(&metric)).To(Succeed())
+		Expect(metric.GetHistogram().GetSampleCount()).To(BeEquivalentTo(1))
+		Expect(metric.GetHistogram().GetSampleSum()).To(Equal(1.5))
+	})
+})
@@ -133,3 +133,25 @@ var _ = Describe("withOperatorAuth", func() {
 		Expect(nextCalled).To(BeTrue())
 	})
 })
+
+var _ = Describe("pgStatStatements endpoint", func() {
+	It("rejects requests with a malformed queryid", func() {
+		ws := remoteWebserverEndpoints{instance: &postgres.Instance{}}
+		req := httptest.NewRequest(http.MethodGet, "/pg/stat_statements?queryid=invalid", nil)
+		w := httptest.NewRecorder()
+
+		ws.pgStatStatements(w, req)
+
+		Expect(w.Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("rejects requests with a method other than GET", func() {
+		ws := remoteWebserverEndpoints{instance: &postgres.Instance{}}
+		req := httptest.NewRequest(http.MethodPost, "/pg/stat_statements?queryid=00000000000000ff", nil)
+		w := httptest.NewRecorder()
+
+		ws.pgStatStatements(w, req)
+
+		Expect(w.Code).To(Equal(http.StatusMethodNotAllowed))
+	})
+})
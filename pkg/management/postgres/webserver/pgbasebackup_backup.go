@@ -0,0 +1,256 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webserver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	pgTime "github.com/cloudnative-pg/machinery/pkg/postgres/time"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources/status"
+)
+
+// PgBaseBackupCommand represent a backup command, taken with
+// pg_basebackup, that is being executed
+type PgBaseBackupCommand struct {
+	Cluster  *apiv1.Cluster
+	Backup   *apiv1.Backup
+	Client   client.Client
+	Recorder record.EventRecorder
+}
+
+// NewPgBaseBackupCommand initializes a PgBaseBackupCommand object, taking
+// a physical backup using pg_basebackup
+func NewPgBaseBackupCommand(
+	cluster *apiv1.Cluster,
+	backup *apiv1.Backup,
+	client client.Client,
+	recorder record.EventRecorder,
+) *PgBaseBackupCommand {
+	return &PgBaseBackupCommand{
+		Cluster:  cluster,
+		Backup:   backup,
+		Client:   client,
+		Recorder: recorder,
+	}
+}
+
+// Start starts a backup using pg_basebackup
+func (b *PgBaseBackupCommand) Start(ctx context.Context) {
+	go b.run(ctx)
+}
+
+func (b *PgBaseBackupCommand) run(ctx context.Context) {
+	contextLogger := log.FromContext(ctx).WithValues(
+		"backupName", b.Backup.Name,
+		"backupNamespace", b.Backup.Namespace)
+	ctx = log.IntoContext(ctx, contextLogger)
+
+	if err := b.setupBackupStatus(ctx); err != nil {
+		b.markBackupAsFailed(ctx, err)
+		return
+	}
+
+	if err := postgres.PatchBackupStatusAndRetry(ctx, b.Client, b.Backup); err != nil {
+		contextLogger.Error(err, "Can't set backup as running")
+	}
+
+	contextLogger.Info("pg_basebackup backup started",
+		"type", b.Backup.Status.PgBaseBackupStatus.Type,
+		"parentBackupName", b.Backup.Status.PgBaseBackupStatus.ParentBackupName)
+	b.Recorder.Event(b.Backup, "Normal", "Starting", "Backup started")
+
+	// Update backup status in cluster conditions on startup
+	if err := b.retryWithRefreshedCluster(ctx, func() error {
+		return status.PatchConditionsWithOptimisticLock(ctx, b.Client, b.Cluster, apiv1.BackupStartingCondition)
+	}); err != nil {
+		contextLogger.Error(err, "Error changing backup condition (backup started)")
+		// We do not terminate here because we could still have a good backup
+		// even if we are unable to communicate with the Kubernetes API server
+	}
+
+	if err := b.takeBackup(ctx); err != nil {
+		b.markBackupAsFailed(ctx, err)
+		return
+	}
+
+	contextLogger.Info("Backup completed")
+	b.Recorder.Event(b.Backup, "Normal", "Completed", "Backup completed")
+
+	if err := postgres.PatchBackupStatusAndRetry(ctx, b.Client, b.Backup); err != nil {
+		contextLogger.Error(err, "Can't set backup status as completed")
+	}
+
+	// Update backup status in cluster conditions on backup completion
+	if err := b.retryWithRefreshedCluster(ctx, func() error {
+		return status.PatchConditionsWithOptimisticLock(ctx, b.Client, b.Cluster, apiv1.BackupSucceededCondition)
+	}); err != nil {
+		contextLogger.Error(err, "Can't update the cluster with the completed backup data")
+	}
+
+	if err := b.updateClusterBackupTimes(ctx); err != nil {
+		contextLogger.Error(err, "while setting the firstRecoverabilityPoint and latestSuccessfulBackup")
+	}
+}
+
+// setupBackupStatus chooses the location of the backup and, for incremental
+// and differential backups, the parent backup it will be based on
+func (b *PgBaseBackupCommand) setupBackupStatus(ctx context.Context) error {
+	contextLogger := log.FromContext(ctx)
+
+	backupStatus := b.Backup.GetStatus()
+	backupStatus.BackupID = pgTime.ToCompactISO8601(time.Now())
+	backupStatus.BackupName = fmt.Sprintf("backup-%v", backupStatus.BackupID)
+	backupStatus.Phase = apiv1.BackupPhaseRunning
+	backupStatus.StartedAt = ptr.To(metav1.Now())
+	backupStatus.Online = ptr.To(true)
+	backupStatus.PgBaseBackupStatus = &apiv1.BackupPgBaseBackupStatus{
+		Type:      b.Backup.GetType(),
+		ClaimName: b.Cluster.Spec.Backup.PgBaseBackup.ClaimName,
+		Path:      postgres.GetPgBaseBackupPath(b.Cluster.Name, backupStatus.BackupID),
+	}
+
+	if backupStatus.PgBaseBackupStatus.Type == apiv1.BackupTypeFull {
+		return nil
+	}
+
+	var backups apiv1.BackupList
+	if err := b.Client.List(ctx, &backups, client.InNamespace(b.Cluster.Namespace)); err != nil {
+		return fmt.Errorf("while getting backups: %w", err)
+	}
+
+	parent := backups.FindPgBaseBackupParent(
+		b.Cluster.Name,
+		backupStatus.PgBaseBackupStatus.ClaimName,
+		backupStatus.PgBaseBackupStatus.Type,
+	)
+	if parent == nil {
+		const message = "No parent backup available, taking a full backup instead"
+		contextLogger.Info(message, "requestedType", backupStatus.PgBaseBackupStatus.Type)
+		b.Recorder.Event(b.Backup, "Normal", "FullBackupRequired", message)
+		backupStatus.PgBaseBackupStatus.Type = apiv1.BackupTypeFull
+		return nil
+	}
+
+	backupStatus.PgBaseBackupStatus.ParentBackupName = parent.Name
+	return nil
+}
+
+// takeBackup runs pg_basebackup and collects the WAL range of the backup
+// from its manifest
+func (b *PgBaseBackupCommand) takeBackup(ctx context.Context) error {
+	backupStatus := b.Backup.GetStatus()
+
+	var parentDirectory string
+	if parentName := backupStatus.PgBaseBackupStatus.ParentBackupName; parentName != "" {
+		var parent apiv1.Backup
+		if err := b.Client.Get(ctx, client.ObjectKey{Namespace: b.Backup.Namespace, Name: parentName}, &parent); err != nil {
+			return fmt.Errorf("while getting parent backup %s: %w", parentName, err)
+		}
+		if !parent.IsCompletedPgBaseBackup() {
+			return fmt.Errorf("parent backup %s is not a completed pgBaseBackup backup", parentName)
+		}
+		parentDirectory = postgres.GetPgBaseBackupDirectory(parent.Status.PgBaseBackupStatus)
+	}
+
+	backupDirectory := postgres.GetPgBaseBackupDirectory(backupStatus.PgBaseBackupStatus)
+	if err := postgres.TakePgBaseBackup(ctx, backupDirectory, parentDirectory); err != nil {
+		return err
+	}
+
+	walRange, err := postgres.ReadPgBaseBackupWALRange(backupDirectory)
+	if err != nil {
+		return err
+	}
+
+	backupStatus.SetAsCompleted()
+	backupStatus.StoppedAt = ptr.To(metav1.Now())
+	backupStatus.BeginLSN = walRange.StartLSN
+	backupStatus.EndLSN = walRange.EndLSN
+	backupStatus.PgBaseBackupStatus.TimeLineID = walRange.TimeLineID
+	return nil
+}
+
+// updateClusterBackupTimes sets the first recoverability point and the
+// last successful backup of the pgBaseBackup method in the cluster status,
+// based on the completed backups
+func (b *PgBaseBackupCommand) updateClusterBackupTimes(ctx context.Context) error {
+	var backups apiv1.BackupList
+	if err := b.Client.List(ctx, &backups, client.InNamespace(b.Cluster.Namespace)); err != nil {
+		return fmt.Errorf("while getting backups: %w", err)
+	}
+
+	var oldest, newest *time.Time
+	for idx := range backups.Items {
+		backup := &backups.Items[idx]
+		if backup.Spec.Cluster.Name != b.Cluster.Name ||
+			!backup.IsCompletedPgBaseBackup() ||
+			backup.Status.StoppedAt == nil {
+			continue
+		}
+
+		stoppedAt := backup.Status.StoppedAt.Time
+		if oldest == nil || stoppedAt.Before(*oldest) {
+			oldest = &stoppedAt
+		}
+		if newest == nil || stoppedAt.After(*newest) {
+			newest = &stoppedAt
+		}
+	}
+
+	return b.retryWithRefreshedCluster(ctx, func() error {
+		origCluster := b.Cluster.DeepCopy()
+
+		b.Cluster.UpdateBackupTimes(apiv1.BackupMethodPgBaseBackup, oldest, newest)
+
+		if equality.Semantic.DeepEqual(origCluster.Status, b.Cluster.Status) {
+			return nil
+		}
+		return b.Client.Status().Patch(ctx, b.Cluster, client.MergeFrom(origCluster))
+	})
+}
+
+func (b *PgBaseBackupCommand) markBackupAsFailed(ctx context.Context, failure error) {
+	contextLogger := log.FromContext(ctx)
+
+	// record the failure
+	contextLogger.Error(failure, "Backup failed")
+	b.Recorder.Event(b.Backup, "Normal", "Failed", "Backup failed")
+
+	_ = status.FlagBackupAsFailed(ctx, b.Client, b.Backup, b.Cluster, failure)
+}
+
+func (b *PgBaseBackupCommand) retryWithRefreshedCluster(
+	ctx context.Context,
+	cb func() error,
+) error {
+	return resources.RetryWithRefreshedResource(ctx, b.Client, b.Cluster, cb)
+}
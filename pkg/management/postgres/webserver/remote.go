@@ -178,6 +178,11 @@ func NewRemoteWebServer(
 	serveMux.HandleFunc(url.PathPGControlData, endpoints.withOperatorAuth(endpoints.pgControlData))
 	// Authenticated: pgarchivepartial triggers WAL archival and must not be callable by arbitrary clients.
 	serveMux.HandleFunc(url.PathPgArchivePartial, endpoints.withOperatorAuth(endpoints.pgArchivePartial))
+	// Authenticated: the text of the statements may contain sensitive data
+	// and is deliberately not exposed by the metrics endpoint.
+	serveMux.HandleFunc(url.PathPgStatStatements, endpoints.withOperatorAuth(endpoints.pgStatStatements))
+	// Authenticated: stopwrites terminates every client connection of the primary.
+	serveMux.HandleFunc(url.PathPgStopWrites, endpoints.withOperatorAuth(endpoints.pgStopWrites))
 	// Authenticated: update replaces the running instance manager binary.
 	serveMux.HandleFunc(
 		url.PathUpdate,
@@ -370,6 +375,43 @@ func (ws *remoteWebserverEndpoints) pgControlData(w http.ResponseWriter, _ *http
 	_, _ = w.Write(res)
 }
 
+// pgStatStatements returns the text of the statements tracked by
+// pg_stat_statements having the queryid passed as query parameter,
+// normalized as in the labels of the query statistics metrics
+func (ws *remoteWebserverEndpoints) pgStatStatements(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	queryID, err := postgres.ParseQueryID(req.URL.Query().Get("queryid"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	statements, err := ws.instance.GetStatementTexts(req.Context(), queryID)
+	if err != nil {
+		log.Debug(
+			"Instance pg_stat_statements endpoint failing",
+			"err", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	res, err := json.Marshal(statements)
+	if err != nil {
+		log.Warning(
+			"Internal error marshalling pg_stat_statements response",
+			"err", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(res)
+}
+
 // updateInstanceManager replace the instance with one in the
 // new binary
 func (ws *remoteWebserverEndpoints) updateInstanceManager(
@@ -602,3 +644,33 @@ func (ws *remoteWebserverEndpoints) pgArchivePartial(w http.ResponseWriter, req
 
 	sendJSONResponseWithData(w, 200, walFile)
 }
+
+func (ws *remoteWebserverEndpoints) pgStopWrites(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "wrong method used", http.StatusMethodNotAllowed)
+		return
+	}
+
+	isPrimary, err := ws.instance.IsPrimary()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !isPrimary {
+		sendBadRequestJSONResponse(w, "NOT_PRIMARY", "")
+		return
+	}
+
+	lsn, err := ws.instance.StopWrites(req.Context())
+	if errors.Is(err, postgres.ErrNotReadOnly) {
+		sendBadRequestJSONResponse(w, "NOT_READ_ONLY", err.Error())
+		return
+	}
+	if err != nil {
+		log.Warning("Error while stopping the writes", "err", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	sendJSONResponseWithData(w, http.StatusOK, lsn)
+}
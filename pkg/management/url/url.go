@@ -33,8 +33,8 @@ const (
 	// PostgresMetricsPort is the port for the exporter of PostgreSQL related metrics (HTTP)
 	PostgresMetricsPort int32 = 9187
 
-	// PgBouncerMetricsPort is the port for the exporter of PgBouncer related metrics (HTTP)
-	PgBouncerMetricsPort int32 = 9127
+	// PoolerMetricsPort is the port for the exporter of connection pooler related metrics (HTTP)
+	PoolerMetricsPort int32 = 9127
 
 	// PathFailSafe is the path for the failsafe entrypoint
 	PathFailSafe string = "/failsafe"
@@ -66,6 +66,14 @@ const (
 	// PathPgArchivePartial is the URL path to interact with the partial wal archive
 	PathPgArchivePartial string = "/pg/archive/partial"
 
+	// PathPgStatStatements is the URL path for the text of the statements
+	// tracked by pg_stat_statements
+	PathPgStatStatements string = "/pg/stat_statements"
+
+	// PathPgStopWrites is the URL path to terminate the client connections
+	// of a read-only primary and get the WAL location where the writes stopped
+	PathPgStopWrites string = "/pg/stopwrites"
+
 	// PathMetrics is the URL path for Metrics
 	PathMetrics string = "/metrics"
 
@@ -394,6 +394,10 @@ type ConfigurationInfo struct {
 	// IsAlterSystemEnabled is true when 'allow_alter_system' should be set to on
 	IsAlterSystemEnabled bool
 
+	// IsWalSummarizationEnabled is true when the WAL summarizer is needed
+	// to take incremental backups
+	IsWalSummarizationEnabled bool
+
 	// Minimum apply delay of transaction
 	RecoveryMinApplyDelay time.Duration
 
@@ -773,6 +777,11 @@ func CreatePostgresqlConfiguration(info ConfigurationInfo) *PgConfiguration {
 		}
 	}
 
+	// Incremental backups require WAL summaries, available since PostgreSQL 17
+	if info.IsWalSummarizationEnabled && info.MajorVersion >= 17 {
+		configuration.OverwriteConfig("summarize_wal", "on")
+	}
+
 	if info.ClusterName != "" {
 		configuration.OverwriteConfig("cluster_name", info.ClusterName)
 	}
@@ -57,6 +57,20 @@ var _ = Describe("PostgreSQL configuration creation", func() {
 		Expect(config.GetConfig("hot_standby")).To(Equal("true"))
 	})
 
+	It("enables the WAL summarizer when required", func() {
+		info := ConfigurationInfo{
+			Settings:                  CnpgConfigurationSettings,
+			MajorVersion:              17,
+			IsWalSummarizationEnabled: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("summarize_wal")).To(Equal("on"))
+
+		info.MajorVersion = 16
+		config = CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("summarize_wal")).To(BeEmpty())
+	})
+
 	It("generate a config file", func() {
 		info := ConfigurationInfo{
 			Settings:           CnpgConfigurationSettings,
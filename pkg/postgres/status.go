@@ -81,6 +81,17 @@ type PostgresqlStatus struct {
 	ReplicationSlotsInfo PgReplicationSlotList `json:"replicationSlotsInfo,omitempty"`
 	// contains the PgStatBasebackup rows content.
 	PgStatBasebackupsInfo []PgStatBasebackup `json:"pgStatBasebackupsInfo,omitempty"`
+	// contains the usage of the filesystems of the volumes of the instance
+	VolumesUsage []VolumeUsage `json:"volumesUsage,omitempty"`
+
+	// The number of client connections which are not idle
+	ActiveConnections int `json:"activeConnections,omitempty"`
+	// The time elapsed since the last transaction replayed by a replica,
+	// zero when the replica replayed all the WAL it received
+	ReplayLagSeconds float64 `json:"replayLagSeconds,omitempty"`
+	// The CPU usage of the instance container, in millicores, computed
+	// between two consecutive status requests
+	CPUUsageMillicores *int64 `json:"cpuUsageMillicores,omitempty"`
 
 	// Status of the instance manager
 	ExecutableHash             string `json:"executableHash"`
@@ -105,6 +116,36 @@ type PostgresqlStatus struct {
 	IsPodReady bool `json:"isPodReady"`
 }
 
+// VolumeUsage is the usage of the filesystem of a volume mounted by the instance
+type VolumeUsage struct {
+	// The role of the PVC backing the volume
+	Role utils.PVCRole `json:"role"`
+
+	// The name of the tablespace, for tablespace volumes
+	TablespaceName string `json:"tablespaceName,omitempty"`
+
+	// The size of the filesystem
+	TotalBytes uint64 `json:"totalBytes"`
+
+	// The space used in the filesystem
+	UsedBytes uint64 `json:"usedBytes"`
+
+	// The space available to non-root users in the filesystem
+	AvailableBytes uint64 `json:"availableBytes"`
+}
+
+// GetUsagePercentage returns the percentage of used space in the
+// filesystem, rounded up and computed like df does, without
+// considering the space reserved to root
+func (usage VolumeUsage) GetUsagePercentage() int {
+	usable := usage.UsedBytes + usage.AvailableBytes
+	if usable == 0 {
+		return 0
+	}
+
+	return int((usage.UsedBytes*100 + usable - 1) / usable)
+}
+
 // PgStatReplication contains the replications of replicas as reported by the primary instance
 type PgStatReplication struct {
 	ApplicationName string    `json:"applicationName,omitempty"`
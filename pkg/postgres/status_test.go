@@ -354,3 +354,15 @@ var _ = Describe("Configuration report", func() {
 		),
 	)
 })
+
+var _ = Describe("VolumeUsage", func() {
+	DescribeTable("computes the usage percentage",
+		func(usage VolumeUsage, expected int) {
+			Expect(usage.GetUsagePercentage()).To(Equal(expected))
+		},
+		Entry("with an empty filesystem", VolumeUsage{UsedBytes: 0, AvailableBytes: 100}, 0),
+		Entry("with a full filesystem", VolumeUsage{UsedBytes: 100, AvailableBytes: 0}, 100),
+		Entry("rounding up", VolumeUsage{UsedBytes: 801, AvailableBytes: 199}, 81),
+		Entry("without any usable space", VolumeUsage{}, 0),
+	)
+})
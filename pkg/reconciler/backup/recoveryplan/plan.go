@@ -0,0 +1,519 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package recoveryplan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	barmanCatalog "github.com/cloudnative-pg/barman-cloud/pkg/catalog"
+	"github.com/cloudnative-pg/machinery/pkg/types"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// ErrNotRecovery is returned when a plan is requested for a
+// cluster that is not bootstrapped from a recovery source
+var ErrNotRecovery = errors.New("the cluster is not bootstrapped with the recovery method")
+
+// SourceKind is the kind of source the base backup is restored from
+type SourceKind string
+
+const (
+	// SourceKindBackup means that the base backup is
+	// a Backup object referenced by the cluster
+	SourceKindBackup SourceKind = "backup"
+
+	// SourceKindVolumeSnapshots means that the base backup
+	// is a set of volume snapshots
+	SourceKindVolumeSnapshots SourceKind = "volumeSnapshots"
+
+	// SourceKindBarmanObjectStore means that the base backup is chosen
+	// from the catalog of an object store defined in an external cluster
+	SourceKindBarmanObjectStore SourceKind = "barmanObjectStore"
+
+	// SourceKindPlugin means that the base backup is chosen by the
+	// plugin defined in an external cluster
+	SourceKindPlugin SourceKind = "plugin"
+)
+
+// BaseBackup is the backup chosen as the starting point of the recovery
+type BaseBackup struct {
+	// The name of the Backup object, if any
+	Name string `json:"name,omitempty"`
+
+	// The ID of the backup in the backup catalog
+	BackupID string `json:"backupID,omitempty"`
+
+	// The method used to take the backup
+	Method apiv1.BackupMethod `json:"method,omitempty"`
+
+	// When the backup was started
+	BeginTime *metav1.Time `json:"beginTime,omitempty"`
+
+	// When the backup was terminated, which is the first
+	// point in time the cluster can be recovered to
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+
+	// The first WAL file of the backup
+	BeginWal string `json:"beginWal,omitempty"`
+
+	// The last WAL file of the backup
+	EndWal string `json:"endWal,omitempty"`
+
+	// The LSN where the backup was started
+	BeginLSN string `json:"beginLSN,omitempty"`
+
+	// The LSN where the backup was terminated
+	EndLSN string `json:"endLSN,omitempty"`
+
+	// The timeline of the backup
+	Timeline int `json:"timeline,omitempty"`
+}
+
+// Plan describes how the recovery of a cluster is going to be executed
+type Plan struct {
+	// The kind of source the base backup is restored from
+	Source SourceKind `json:"source"`
+
+	// The name of the Backup, of the external cluster or of
+	// the PGDATA volume snapshot the recovery starts from
+	SourceName string `json:"sourceName"`
+
+	// The recovery target, if any
+	RecoveryTarget *apiv1.RecoveryTarget `json:"recoveryTarget,omitempty"`
+
+	// The chosen base backup. This is empty when the base backup
+	// can be chosen only while the recovery is running
+	BaseBackup *BaseBackup `json:"baseBackup,omitempty"`
+
+	// The first point in time the source can be recovered to, when known
+	FirstRecoverabilityPoint *metav1.Time `json:"firstRecoverabilityPoint,omitempty"`
+
+	// The first WAL file that is needed by the recovery
+	FirstRequiredWAL string `json:"firstRequiredWAL,omitempty"`
+
+	// The last WAL file that is needed by the recovery. This is empty when
+	// the recovery replays every WAL file available after the base backup
+	// until the recovery target is reached
+	LastRequiredWAL string `json:"lastRequiredWAL,omitempty"`
+
+	// The reasons why the recovery target cannot be reached
+	Problems []string `json:"problems,omitempty"`
+
+	// Details that cannot be checked before the recovery is started
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// IsFeasible is true when no problem has been detected
+func (plan *Plan) IsFeasible() bool {
+	return len(plan.Problems) == 0
+}
+
+func (plan *Plan) addProblem(format string, args ...any) {
+	plan.Problems = append(plan.Problems, fmt.Sprintf(format, args...))
+}
+
+func (plan *Plan) addWarning(format string, args ...any) {
+	plan.Warnings = append(plan.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Build resolves the base backup the passed cluster is going to be
+// recovered from, using the Backup objects, the volume snapshot metadata
+// and the status of the source cluster found in the namespace of the
+// cluster. An error is returned only when the API server cannot be queried:
+// the problems preventing the recovery are reported inside the plan
+func Build(ctx context.Context, cli client.Client, cluster *apiv1.Cluster) (*Plan, error) {
+	if cluster.Spec.Bootstrap == nil || cluster.Spec.Bootstrap.Recovery == nil {
+		return nil, ErrNotRecovery
+	}
+
+	recovery := cluster.Spec.Bootstrap.Recovery
+	plan := &Plan{
+		RecoveryTarget: recovery.RecoveryTarget,
+	}
+
+	var err error
+	switch {
+	case recovery.Backup != nil:
+		err = plan.resolveBackup(ctx, cli, cluster.Namespace, recovery.Backup.Name)
+	case recovery.VolumeSnapshots != nil:
+		err = plan.resolveVolumeSnapshots(ctx, cli, cluster)
+	case recovery.Source != "":
+		err = plan.resolveExternalCluster(ctx, cli, cluster)
+	default:
+		return nil, fmt.Errorf("the recovery source of cluster %s is not defined", cluster.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if plan.BaseBackup != nil {
+		plan.checkRecoveryTarget()
+		plan.FirstRequiredWAL = plan.BaseBackup.BeginWal
+		if plan.RecoveryTarget != nil && plan.RecoveryTarget.TargetImmediate != nil &&
+			*plan.RecoveryTarget.TargetImmediate {
+			plan.LastRequiredWAL = plan.BaseBackup.EndWal
+		}
+	}
+
+	return plan, nil
+}
+
+// resolveBackup uses the Backup object with the passed name as base backup
+func (plan *Plan) resolveBackup(ctx context.Context, cli client.Client, namespace, name string) error {
+	plan.Source = SourceKindBackup
+	plan.SourceName = name
+
+	var backup apiv1.Backup
+	err := cli.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &backup)
+	if apierrs.IsNotFound(err) {
+		plan.addProblem("backup %s does not exist", name)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if backup.Status.Phase != apiv1.BackupPhaseCompleted {
+		plan.addProblem("backup %s is not completed (phase: %s)", name, backup.Status.Phase)
+		return nil
+	}
+	if backup.Status.Method == apiv1.BackupMethodLogical {
+		plan.addProblem("backup %s has been taken with the logical method and "+
+			"cannot be used to recover a cluster", name)
+		return nil
+	}
+
+	plan.BaseBackup = newBaseBackupFromBackup(&backup)
+	if plan.RecoveryTarget != nil && plan.RecoveryTarget.BackupID != "" &&
+		plan.RecoveryTarget.BackupID != backup.Status.BackupID {
+		plan.addProblem("the recovery target backupID %s does not match the ID of backup %s (%s)",
+			plan.RecoveryTarget.BackupID, name, backup.Status.BackupID)
+	}
+
+	return nil
+}
+
+// resolveVolumeSnapshots uses the metadata of the volume snapshots
+// referenced by the cluster to describe the base backup
+func (plan *Plan) resolveVolumeSnapshots(ctx context.Context, cli client.Client, cluster *apiv1.Cluster) error {
+	recovery := cluster.Spec.Bootstrap.Recovery
+	plan.Source = SourceKindVolumeSnapshots
+	plan.SourceName = recovery.VolumeSnapshots.Storage.Name
+
+	pgData, err := persistentvolumeclaim.GetSourceMetadataOrNil(
+		ctx, cli, cluster.Namespace, recovery.VolumeSnapshots.Storage)
+	if err != nil {
+		return err
+	}
+	if pgData == nil {
+		plan.addProblem("%s %s does not exist",
+			recovery.VolumeSnapshots.Storage.Kind, recovery.VolumeSnapshots.Storage.Name)
+		return nil
+	}
+
+	if walStorage := recovery.VolumeSnapshots.WalStorage; walStorage != nil {
+		pgWal, err := persistentvolumeclaim.GetSourceMetadataOrNil(ctx, cli, cluster.Namespace, *walStorage)
+		if err != nil {
+			return err
+		}
+		if pgWal == nil {
+			plan.addProblem("%s %s does not exist", walStorage.Kind, walStorage.Name)
+		}
+	}
+
+	baseBackup, err := newBaseBackupFromSnapshotMetadata(pgData)
+	if err != nil {
+		return err
+	}
+	plan.BaseBackup = baseBackup
+
+	if plan.RecoveryTarget != nil && recovery.Source == "" {
+		plan.addWarning("no recovery source is defined: only the WAL files " +
+			"contained in the volume snapshots can be used to reach the recovery target")
+	}
+
+	return nil
+}
+
+// resolveExternalCluster chooses the base backup in the catalog of the
+// external cluster used as recovery source. Only the backups having a
+// Backup object in the namespace of the cluster are known, so a missing
+// base backup is reported as a warning
+func (plan *Plan) resolveExternalCluster(ctx context.Context, cli client.Client, cluster *apiv1.Cluster) error {
+	recovery := cluster.Spec.Bootstrap.Recovery
+	plan.SourceName = recovery.Source
+
+	server, found := cluster.ExternalCluster(recovery.Source)
+	if !found {
+		plan.addProblem("external cluster %s does not exist", recovery.Source)
+		return nil
+	}
+
+	var backupList apiv1.BackupList
+	if err := cli.List(ctx, &backupList, client.InNamespace(cluster.Namespace)); err != nil {
+		return err
+	}
+
+	var backups []apiv1.Backup
+	switch {
+	case server.PluginConfiguration != nil:
+		plan.Source = SourceKindPlugin
+		plan.addWarning("the %s plugin restores the base backup: "+
+			"its catalog is only known when the recovery is started", server.PluginConfiguration.Name)
+		backups = filterBackups(backupList.Items, func(backup *apiv1.Backup) bool {
+			return backup.Status.Method == apiv1.BackupMethodPlugin &&
+				backup.Spec.PluginConfiguration != nil &&
+				backup.Spec.PluginConfiguration.Name == server.PluginConfiguration.Name &&
+				backup.Spec.Cluster.Name == server.Name
+		})
+
+	case server.BarmanObjectStore != nil:
+		plan.Source = SourceKindBarmanObjectStore
+		serverName := server.GetServerName()
+		backups = filterBackups(backupList.Items, func(backup *apiv1.Backup) bool {
+			return backup.Status.Method == apiv1.BackupMethodBarmanObjectStore &&
+				backup.Status.DestinationPath == server.BarmanObjectStore.DestinationPath &&
+				backup.Status.ServerName == serverName
+		})
+		if err := plan.checkSourceClusterRecoverability(ctx, cli, cluster.Namespace, server); err != nil {
+			return err
+		}
+
+	default:
+		plan.addProblem("external cluster %s has neither a barmanObjectStore "+
+			"nor a plugin configuration", recovery.Source)
+		return nil
+	}
+
+	plan.chooseFromCatalog(backups)
+	return nil
+}
+
+// checkSourceClusterRecoverability uses the status of the cluster archiving
+// into the object store of the external cluster, when it lives in the
+// same namespace, to know the first point the recovery can target
+func (plan *Plan) checkSourceClusterRecoverability(
+	ctx context.Context,
+	cli client.Client,
+	namespace string,
+	server apiv1.ExternalCluster,
+) error {
+	var sourceCluster apiv1.Cluster
+	err := cli.Get(ctx, client.ObjectKey{Namespace: namespace, Name: server.GetServerName()}, &sourceCluster)
+	if apierrs.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if sourceCluster.Spec.Backup == nil || sourceCluster.Spec.Backup.BarmanObjectStore == nil ||
+		sourceCluster.Spec.Backup.BarmanObjectStore.DestinationPath != server.BarmanObjectStore.DestinationPath {
+		return nil
+	}
+
+	firstRecoverabilityPoint, ok := sourceCluster.Status.FirstRecoverabilityPointByMethod[apiv1.BackupMethodBarmanObjectStore]
+	if !ok {
+		return nil
+	}
+	plan.FirstRecoverabilityPoint = &firstRecoverabilityPoint
+
+	if plan.RecoveryTarget == nil || plan.RecoveryTarget.TargetTime == "" {
+		return nil
+	}
+	targetTime, err := types.ParseTargetTime(nil, plan.RecoveryTarget.TargetTime)
+	if err != nil {
+		plan.addProblem("invalid recovery target time %s: %v", plan.RecoveryTarget.TargetTime, err)
+		return nil
+	}
+	if targetTime.Before(firstRecoverabilityPoint.Time) {
+		plan.addProblem("the recovery target time %s precedes the first recoverability point "+
+			"of cluster %s (%s)", plan.RecoveryTarget.TargetTime, sourceCluster.Name,
+			firstRecoverabilityPoint.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// chooseFromCatalog picks the base backup for the recovery target
+// from the catalog made of the passed backups, with the same
+// rules used when the recovery is started
+func (plan *Plan) chooseFromCatalog(backups []apiv1.Backup) {
+	barmanBackups := make([]barmanCatalog.BarmanBackup, 0, len(backups))
+	for idx := range backups {
+		barmanBackups = append(barmanBackups, newBarmanBackup(&backups[idx]))
+	}
+	catalog := barmanCatalog.NewCatalog(barmanBackups)
+
+	var chosen *barmanCatalog.BarmanBackup
+	if plan.RecoveryTarget != nil {
+		var err error
+		if chosen, err = catalog.FindBackupInfo(plan.RecoveryTarget); err != nil {
+			plan.addWarning("%v among the Backup objects, the catalog of %s is "+
+				"going to be searched when the recovery is started", err, plan.SourceName)
+			return
+		}
+	} else {
+		chosen = catalog.LatestBackupInfo()
+	}
+
+	if chosen == nil {
+		plan.addWarning("no Backup object matches the recovery target, the catalog of %s is "+
+			"going to be searched when the recovery is started", plan.SourceName)
+		return
+	}
+
+	for idx := range backups {
+		if backups[idx].Status.BackupID == chosen.ID {
+			plan.BaseBackup = newBaseBackupFromBackup(&backups[idx])
+			return
+		}
+	}
+}
+
+// checkRecoveryTarget detects the recovery targets preceding the end of
+// the base backup, which PostgreSQL cannot reach as a consistent state
+// is only available after that point
+func (plan *Plan) checkRecoveryTarget() {
+	target := plan.RecoveryTarget
+	baseBackup := plan.BaseBackup
+	if target == nil {
+		return
+	}
+
+	if target.TargetTime != "" && baseBackup.EndTime != nil {
+		targetTime, err := types.ParseTargetTime(nil, target.TargetTime)
+		if err != nil {
+			plan.addProblem("invalid recovery target time %s: %v", target.TargetTime, err)
+		} else if targetTime.Before(baseBackup.EndTime.Time) {
+			plan.addProblem("the recovery target time %s precedes the end of the base backup (%s)",
+				target.TargetTime, baseBackup.EndTime.Format(time.RFC3339))
+		}
+	}
+
+	if target.TargetLSN != "" && baseBackup.EndLSN != "" &&
+		types.LSN(target.TargetLSN).Less(types.LSN(baseBackup.EndLSN)) {
+		plan.addProblem("the recovery target LSN %s precedes the end of the base backup (%s)",
+			target.TargetLSN, baseBackup.EndLSN)
+	}
+
+	if targetTLI, err := strconv.Atoi(target.TargetTLI); err == nil &&
+		baseBackup.Timeline != 0 && targetTLI < baseBackup.Timeline {
+		plan.addProblem("the recovery target timeline %d precedes the timeline of the base backup (%d)",
+			targetTLI, baseBackup.Timeline)
+	}
+}
+
+func filterBackups(backups []apiv1.Backup, predicate func(*apiv1.Backup) bool) []apiv1.Backup {
+	var result []apiv1.Backup
+	for idx := range backups {
+		if backups[idx].Status.Phase == apiv1.BackupPhaseCompleted && predicate(&backups[idx]) {
+			result = append(result, backups[idx])
+		}
+	}
+
+	return result
+}
+
+func newBaseBackupFromBackup(backup *apiv1.Backup) *BaseBackup {
+	return &BaseBackup{
+		Name:      backup.Name,
+		BackupID:  backup.Status.BackupID,
+		Method:    backup.Status.Method,
+		BeginTime: backup.Status.StartedAt,
+		EndTime:   backup.Status.StoppedAt,
+		BeginWal:  backup.Status.BeginWal,
+		EndWal:    backup.Status.EndWal,
+		BeginLSN:  backup.Status.BeginLSN,
+		EndLSN:    backup.Status.EndLSN,
+		Timeline:  getWALTimeline(backup.Status.BeginWal),
+	}
+}
+
+func newBaseBackupFromSnapshotMetadata(metadata *metav1.ObjectMeta) (*BaseBackup, error) {
+	result := &BaseBackup{
+		Name:     metadata.Labels[utils.BackupNameLabelName],
+		Method:   apiv1.BackupMethodVolumeSnapshot,
+		BeginWal: metadata.Annotations[utils.BackupStartWALAnnotationName],
+		EndWal:   metadata.Annotations[utils.BackupEndWALAnnotationName],
+	}
+	result.Timeline = getWALTimeline(result.BeginWal)
+
+	for annotation, target := range map[string]**metav1.Time{
+		utils.BackupStartTimeAnnotationName: &result.BeginTime,
+		utils.BackupEndTimeAnnotationName:   &result.EndTime,
+	} {
+		value, ok := metadata.Annotations[annotation]
+		if !ok {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("while parsing annotation %s of %s: %w", annotation, metadata.Name, err)
+		}
+		*target = &metav1.Time{Time: parsed}
+	}
+
+	return result, nil
+}
+
+func newBarmanBackup(backup *apiv1.Backup) barmanCatalog.BarmanBackup {
+	result := barmanCatalog.BarmanBackup{
+		ID:       backup.Status.BackupID,
+		BeginWal: backup.Status.BeginWal,
+		EndWal:   backup.Status.EndWal,
+		BeginLSN: backup.Status.BeginLSN,
+		EndLSN:   backup.Status.EndLSN,
+		TimeLine: getWALTimeline(backup.Status.BeginWal),
+	}
+	if backup.Status.StartedAt != nil {
+		result.BeginTime = backup.Status.StartedAt.Time
+	}
+	if backup.Status.StoppedAt != nil {
+		result.EndTime = backup.Status.StoppedAt.Time
+	}
+
+	return result
+}
+
+// getWALTimeline extracts the timeline from the name of a WAL file,
+// returning zero if the name is not valid
+func getWALTimeline(walName string) int {
+	if len(walName) < 8 {
+		return 0
+	}
+
+	timeline, err := strconv.ParseUint(walName[:8], 16, 32)
+	if err != nil {
+		return 0
+	}
+
+	return int(timeline)
+}
@@ -0,0 +1,303 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package recoveryplan
+
+import (
+	"context"
+	"time"
+
+	volumesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	namespace       = "default"
+	destinationPath = "s3://backups/"
+)
+
+var backupEnd = time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+func newCompletedBackup(name, backupID string, endTime time.Time) *apiv1.Backup {
+	return &apiv1.Backup{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: apiv1.BackupSpec{
+			Cluster: apiv1.LocalObjectReference{Name: "origin"},
+		},
+		Status: apiv1.BackupStatus{
+			DestinationPath: destinationPath,
+			ServerName:      "origin",
+			BackupID:        backupID,
+			Phase:           apiv1.BackupPhaseCompleted,
+			Method:          apiv1.BackupMethodBarmanObjectStore,
+			StartedAt:       &metav1.Time{Time: endTime.Add(-time.Hour)},
+			StoppedAt:       &metav1.Time{Time: endTime},
+			BeginWal:        "000000020000000000000004",
+			EndWal:          "000000020000000000000005",
+			BeginLSN:        "0/4000028",
+			EndLSN:          "0/5000100",
+		},
+	}
+}
+
+func newRecoveryCluster(recovery *apiv1.BootstrapRecovery) *apiv1.Cluster {
+	return &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "restored", Namespace: namespace},
+		Spec: apiv1.ClusterSpec{
+			Bootstrap: &apiv1.BootstrapConfiguration{Recovery: recovery},
+			ExternalClusters: []apiv1.ExternalCluster{
+				{
+					Name: "origin",
+					BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+						DestinationPath: destinationPath,
+					},
+				},
+			},
+		},
+	}
+}
+
+func newFakeClient(objects ...client.Object) client.Client {
+	return fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).
+		WithObjects(objects...).
+		Build()
+}
+
+var _ = Describe("getWALTimeline", func() {
+	It("extracts the timeline from a WAL file name", func() {
+		Expect(getWALTimeline("0000000A0000000000000004")).To(Equal(10))
+	})
+
+	It("returns zero for invalid names", func() {
+		Expect(getWALTimeline("")).To(BeZero())
+		Expect(getWALTimeline("nothexadecimal")).To(BeZero())
+	})
+})
+
+var _ = Describe("Build", func() {
+	It("refuses clusters not bootstrapped with recovery", func(ctx context.Context) {
+		_, err := Build(ctx, newFakeClient(), &apiv1.Cluster{})
+		Expect(err).To(MatchError(ErrNotRecovery))
+	})
+
+	Context("recovering from a Backup object", func() {
+		It("describes the base backup and the WAL range", func(ctx context.Context) {
+			cluster := newRecoveryCluster(&apiv1.BootstrapRecovery{
+				Backup: &apiv1.BackupSource{
+					LocalObjectReference: apiv1.LocalObjectReference{Name: "backup-1"},
+				},
+				RecoveryTarget: &apiv1.RecoveryTarget{TargetImmediate: ptr.To(true)},
+			})
+
+			plan, err := Build(ctx, newFakeClient(newCompletedBackup("backup-1", "20250310T110000", backupEnd)), cluster)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plan.IsFeasible()).To(BeTrue())
+			Expect(plan.Source).To(Equal(SourceKindBackup))
+			Expect(plan.BaseBackup.BackupID).To(Equal("20250310T110000"))
+			Expect(plan.BaseBackup.Timeline).To(Equal(2))
+			Expect(plan.FirstRequiredWAL).To(Equal("000000020000000000000004"))
+			Expect(plan.LastRequiredWAL).To(Equal("000000020000000000000005"))
+		})
+
+		It("reports a missing backup", func(ctx context.Context) {
+			cluster := newRecoveryCluster(&apiv1.BootstrapRecovery{
+				Backup: &apiv1.BackupSource{
+					LocalObjectReference: apiv1.LocalObjectReference{Name: "backup-1"},
+				},
+			})
+
+			plan, err := Build(ctx, newFakeClient(), cluster)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plan.Problems).To(ConsistOf(ContainSubstring("does not exist")))
+		})
+
+		It("reports recovery targets preceding the end of the backup", func(ctx context.Context) {
+			cluster := newRecoveryCluster(&apiv1.BootstrapRecovery{
+				Backup: &apiv1.BackupSource{
+					LocalObjectReference: apiv1.LocalObjectReference{Name: "backup-1"},
+				},
+				RecoveryTarget: &apiv1.RecoveryTarget{
+					BackupID:   "20250101T000000",
+					TargetTime: "2025-03-10 11:30:00Z",
+					TargetTLI:  "1",
+				},
+			})
+
+			plan, err := Build(ctx, newFakeClient(newCompletedBackup("backup-1", "20250310T110000", backupEnd)), cluster)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plan.IsFeasible()).To(BeFalse())
+			Expect(plan.Problems).To(ConsistOf(
+				ContainSubstring("backupID 20250101T000000 does not match"),
+				ContainSubstring("recovery target time"),
+				ContainSubstring("recovery target timeline 1"),
+			))
+		})
+
+		It("reports recovery target LSNs preceding the end of the backup", func(ctx context.Context) {
+			cluster := newRecoveryCluster(&apiv1.BootstrapRecovery{
+				Backup: &apiv1.BackupSource{
+					LocalObjectReference: apiv1.LocalObjectReference{Name: "backup-1"},
+				},
+				RecoveryTarget: &apiv1.RecoveryTarget{TargetLSN: "0/4000100"},
+			})
+
+			plan, err := Build(ctx, newFakeClient(newCompletedBackup("backup-1", "20250310T110000", backupEnd)), cluster)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plan.Problems).To(ConsistOf(ContainSubstring("recovery target LSN")))
+		})
+	})
+
+	Context("recovering from volume snapshots", func() {
+		It("reads the base backup from the snapshot annotations", func(ctx context.Context) {
+			snapshot := &volumesnapshotv1.VolumeSnapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "snapshot-data",
+					Namespace: namespace,
+					Labels:    map[string]string{utils.BackupNameLabelName: "backup-1"},
+					Annotations: map[string]string{
+						utils.BackupStartWALAnnotationName:  "000000010000000000000004",
+						utils.BackupEndWALAnnotationName:    "000000010000000000000004",
+						utils.BackupStartTimeAnnotationName: backupEnd.Add(-time.Hour).Format(time.RFC3339),
+						utils.BackupEndTimeAnnotationName:   backupEnd.Format(time.RFC3339),
+					},
+				},
+			}
+			cluster := newRecoveryCluster(&apiv1.BootstrapRecovery{
+				VolumeSnapshots: &apiv1.DataSource{
+					Storage: corev1.TypedLocalObjectReference{
+						APIGroup: ptr.To(volumesnapshotv1.GroupName),
+						Kind:     apiv1.VolumeSnapshotKind,
+						Name:     "snapshot-data",
+					},
+				},
+				RecoveryTarget: &apiv1.RecoveryTarget{TargetTime: "2025-03-10 10:00:00Z"},
+			})
+
+			plan, err := Build(ctx, newFakeClient(snapshot), cluster)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plan.Source).To(Equal(SourceKindVolumeSnapshots))
+			Expect(plan.BaseBackup.Name).To(Equal("backup-1"))
+			Expect(plan.BaseBackup.EndTime.Time).To(BeTemporally("==", backupEnd))
+			Expect(plan.Problems).To(ConsistOf(ContainSubstring("precedes the end of the base backup")))
+			Expect(plan.Warnings).To(ConsistOf(ContainSubstring("no recovery source")))
+		})
+
+		It("reports missing snapshots", func(ctx context.Context) {
+			cluster := newRecoveryCluster(&apiv1.BootstrapRecovery{
+				VolumeSnapshots: &apiv1.DataSource{
+					Storage: corev1.TypedLocalObjectReference{
+						APIGroup: ptr.To(volumesnapshotv1.GroupName),
+						Kind:     apiv1.VolumeSnapshotKind,
+						Name:     "snapshot-data",
+					},
+				},
+			})
+
+			plan, err := Build(ctx, newFakeClient(), cluster)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plan.Problems).To(ConsistOf(ContainSubstring("snapshot-data does not exist")))
+		})
+	})
+
+	Context("recovering from an object store", func() {
+		backups := []client.Object{
+			newCompletedBackup("backup-1", "20250309T110000", backupEnd.Add(-24*time.Hour)),
+			newCompletedBackup("backup-2", "20250310T110000", backupEnd),
+		}
+
+		It("chooses the closest backup preceding the recovery target", func(ctx context.Context) {
+			cluster := newRecoveryCluster(&apiv1.BootstrapRecovery{
+				Source:         "origin",
+				RecoveryTarget: &apiv1.RecoveryTarget{TargetTime: "2025-03-10 08:00:00Z"},
+			})
+
+			plan, err := Build(ctx, newFakeClient(backups...), cluster)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plan.IsFeasible()).To(BeTrue())
+			Expect(plan.Source).To(Equal(SourceKindBarmanObjectStore))
+			Expect(plan.BaseBackup.Name).To(Equal("backup-1"))
+		})
+
+		It("only warns when the backup is not known", func(ctx context.Context) {
+			cluster := newRecoveryCluster(&apiv1.BootstrapRecovery{
+				Source:         "origin",
+				RecoveryTarget: &apiv1.RecoveryTarget{BackupID: "20240101T000000"},
+			})
+
+			plan, err := Build(ctx, newFakeClient(backups...), cluster)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plan.IsFeasible()).To(BeTrue())
+			Expect(plan.BaseBackup).To(BeNil())
+			Expect(plan.Warnings).To(HaveLen(1))
+		})
+
+		It("rejects targets preceding the first recoverability point of the source cluster",
+			func(ctx context.Context) {
+				sourceCluster := &apiv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "origin", Namespace: namespace},
+					Spec: apiv1.ClusterSpec{
+						Backup: &apiv1.BackupConfiguration{
+							BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+								DestinationPath: destinationPath,
+							},
+						},
+					},
+					Status: apiv1.ClusterStatus{
+						FirstRecoverabilityPointByMethod: map[apiv1.BackupMethod]metav1.Time{
+							apiv1.BackupMethodBarmanObjectStore: {Time: backupEnd.Add(-24 * time.Hour)},
+						},
+					},
+				}
+				cluster := newRecoveryCluster(&apiv1.BootstrapRecovery{
+					Source:         "origin",
+					RecoveryTarget: &apiv1.RecoveryTarget{TargetTime: "2025-03-01 00:00:00Z"},
+				})
+
+				plan, err := Build(ctx, newFakeClient(append(backups, sourceCluster)...), cluster)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(plan.FirstRecoverabilityPoint).ToNot(BeNil())
+				Expect(plan.Problems).To(ConsistOf(ContainSubstring("first recoverability point")))
+			})
+
+		It("warns that the catalog of plugins is only known at restore time", func(ctx context.Context) {
+			cluster := newRecoveryCluster(&apiv1.BootstrapRecovery{Source: "origin"})
+			cluster.Spec.ExternalClusters[0].BarmanObjectStore = nil
+			cluster.Spec.ExternalClusters[0].PluginConfiguration = &apiv1.PluginConfiguration{
+				Name: "barman-cloud.cloudnative-pg.io",
+			}
+
+			plan, err := Build(ctx, newFakeClient(backups...), cluster)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plan.IsFeasible()).To(BeTrue())
+			Expect(plan.Source).To(Equal(SourceKindPlugin))
+			Expect(plan.Warnings).To(ContainElement(ContainSubstring("barman-cloud.cloudnative-pg.io plugin")))
+		})
+	})
+})
@@ -0,0 +1,336 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package volumesnapshot
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/cloudnative-pg/machinery/pkg/stringset"
+	volumesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// snapshotBackup is a completed backup taken with volume
+// snapshots, as found in the snapshots of the cluster
+type snapshotBackup struct {
+	name      string
+	endTime   time.Time
+	snapshots []volumesnapshotv1.VolumeSnapshot
+}
+
+// EnforceRetentionPolicy removes the backups taken with volume snapshots
+// which are not kept by the retention policy of the cluster, together with
+// their snapshots, and returns the names of the removed backups.
+// Backups whose verification is still pending are never removed.
+// Before removing anything, the first recoverability point of the cluster
+// is moved forward to the oldest retained backup, and the backup backing
+// the first recoverability point is never removed
+func EnforceRetentionPolicy(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+	now time.Time,
+) ([]string, error) {
+	contextLogger := log.FromContext(ctx)
+
+	if cluster.Spec.Backup == nil || cluster.Spec.Backup.VolumeSnapshot == nil {
+		return nil, nil
+	}
+	policy := cluster.Spec.Backup.VolumeSnapshot.RetentionPolicy
+	if policy.IsEmpty() {
+		return nil, nil
+	}
+
+	backups, err := listSnapshotBackups(ctx, cli, cluster.Namespace, cluster.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	expiredBackups, err := getExpiredSnapshotBackups(backups, policy, now)
+	if err != nil {
+		return nil, err
+	}
+	if len(expiredBackups) == 0 {
+		return nil, nil
+	}
+
+	var backupList apiv1.BackupList
+	if err := cli.List(ctx, &backupList, client.InNamespace(cluster.Namespace)); err != nil {
+		return nil, err
+	}
+
+	getBackup := func(name string) *apiv1.Backup {
+		idx := slices.IndexFunc(backupList.Items, func(backup apiv1.Backup) bool {
+			return backup.Name == name
+		})
+		if idx < 0 {
+			return nil
+		}
+		return &backupList.Items[idx]
+	}
+
+	removableBackups := make([]snapshotBackup, 0, len(expiredBackups))
+	for _, expiredBackup := range expiredBackups {
+		if backup := getBackup(expiredBackup.name); backup != nil && backup.IsVerificationPending() {
+			contextLogger.Debug("Postponing the removal of a backup being verified", "backupName", backup.Name)
+			continue
+		}
+		removableBackups = append(removableBackups, expiredBackup)
+	}
+	if len(removableBackups) == 0 {
+		return nil, nil
+	}
+
+	firstRecoverabilityPoint, err := advanceFirstRecoverabilityPoint(ctx, cli, cluster, backups, removableBackups)
+	if err != nil {
+		return nil, err
+	}
+
+	removedBackups := make([]string, 0, len(removableBackups))
+	for _, expiredBackup := range removableBackups {
+		if expiredBackup.endTime.Equal(firstRecoverabilityPoint) {
+			contextLogger.Info("Keeping the backup backing the first recoverability point",
+				"backupName", expiredBackup.name, "endTime", expiredBackup.endTime)
+			continue
+		}
+
+		contextLogger.Info("Removing backup expired by the retention policy",
+			"backupName", expiredBackup.name, "endTime", expiredBackup.endTime)
+		if err := removeSnapshotBackup(ctx, cli, expiredBackup, getBackup(expiredBackup.name)); err != nil {
+			return removedBackups, err
+		}
+		removedBackups = append(removedBackups, expiredBackup.name)
+	}
+
+	return removedBackups, nil
+}
+
+// advanceFirstRecoverabilityPoint sets the first recoverability point of
+// the volume snapshot method to the end time of the oldest backup which
+// is not going to be removed, so that the status of the cluster never
+// refers to a removed backup. The backups are sorted from the most recent
+// one. It returns the first recoverability point recorded in the cluster
+func advanceFirstRecoverabilityPoint(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+	backups []snapshotBackup,
+	removableBackups []snapshotBackup,
+) (time.Time, error) {
+	var oldestRetainedBackup *snapshotBackup
+	for idx := range backups {
+		isRemovable := slices.ContainsFunc(removableBackups, func(backup snapshotBackup) bool {
+			return backup.name == backups[idx].name
+		})
+		if !isRemovable {
+			oldestRetainedBackup = &backups[idx]
+		}
+	}
+
+	// the most recent backup is always retained, but we
+	// never leave the cluster without a recoverability point
+	if oldestRetainedBackup == nil {
+		return time.Time{}, fmt.Errorf("the retention policy would remove every backup of the cluster")
+	}
+
+	origCluster := cluster.DeepCopy()
+	cluster.UpdateBackupTimes(
+		apiv1.BackupMethodVolumeSnapshot,
+		&oldestRetainedBackup.endTime,
+		&backups[0].endTime,
+	)
+	if err := cli.Status().Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+		return time.Time{}, fmt.Errorf("while moving the first recoverability point forward: %w", err)
+	}
+
+	return cluster.Status.FirstRecoverabilityPointByMethod[apiv1.BackupMethodVolumeSnapshot].Time, nil
+}
+
+// removeSnapshotBackup deletes the snapshots of a backup and,
+// if it still exists, the backup object
+func removeSnapshotBackup(
+	ctx context.Context,
+	cli client.Client,
+	expiredBackup snapshotBackup,
+	backup *apiv1.Backup,
+) error {
+	for idx := range expiredBackup.snapshots {
+		snapshot := &expiredBackup.snapshots[idx]
+		if err := cli.Delete(ctx, snapshot); err != nil && !apierrs.IsNotFound(err) {
+			return fmt.Errorf("while deleting volume snapshot %s: %w", snapshot.Name, err)
+		}
+	}
+
+	if backup == nil {
+		return nil
+	}
+
+	if err := cli.Delete(ctx, backup); err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("while deleting backup %s: %w", backup.Name, err)
+	}
+
+	return nil
+}
+
+// listSnapshotBackups returns the completed backups found in the volume
+// snapshots of the cluster, sorted from the most recent one
+func listSnapshotBackups(
+	ctx context.Context,
+	cli client.Client,
+	namespace string,
+	clusterName string,
+) ([]snapshotBackup, error) {
+	var list volumesnapshotv1.VolumeSnapshotList
+	if err := cli.List(
+		ctx,
+		&list,
+		client.InNamespace(namespace),
+		client.MatchingLabels{
+			utils.ClusterLabelName: clusterName,
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	backupsByName := make(map[string]*snapshotBackup)
+	for _, snapshot := range list.Items {
+		backupName := snapshot.Labels[utils.BackupNameLabelName]
+		if backupName == "" {
+			continue
+		}
+
+		backup, ok := backupsByName[backupName]
+		if !ok {
+			backup = &snapshotBackup{name: backupName}
+			backupsByName[backupName] = backup
+		}
+		backup.snapshots = append(backup.snapshots, snapshot)
+
+		if snapshot.Annotations[utils.PvcRoleLabelName] != string(utils.PVCRolePgData) {
+			continue
+		}
+		endTimeStr, hasTime := snapshot.Annotations[utils.BackupEndTimeAnnotationName]
+		if !hasTime {
+			continue
+		}
+		endTime, err := time.Parse(time.RFC3339, endTimeStr)
+		if err != nil {
+			return nil, err
+		}
+		backup.endTime = endTime
+	}
+
+	backups := make([]snapshotBackup, 0, len(backupsByName))
+	for _, backup := range backupsByName {
+		// backups without an end time are still running
+		if backup.endTime.IsZero() {
+			continue
+		}
+		backups = append(backups, *backup)
+	}
+
+	slices.SortFunc(backups, func(a, b snapshotBackup) int {
+		return b.endTime.Compare(a.endTime)
+	})
+
+	return backups, nil
+}
+
+// getExpiredSnapshotBackups returns the backups, sorted from the most
+// recent one, which are not kept by any rule of the retention policy.
+// The most recent backup is always kept
+func getExpiredSnapshotBackups(
+	backups []snapshotBackup,
+	policy *apiv1.VolumeSnapshotRetentionPolicy,
+	now time.Time,
+) ([]snapshotBackup, error) {
+	if len(backups) == 0 || policy.IsEmpty() {
+		return nil, nil
+	}
+
+	keep := make([]bool, len(backups))
+	keep[0] = true
+
+	if policy.KeepLast != nil {
+		for idx := 0; idx < len(backups) && idx < *policy.KeepLast; idx++ {
+			keep[idx] = true
+		}
+	}
+
+	windowStart, err := policy.GetWindowStart(now)
+	if err != nil {
+		return nil, err
+	}
+	if windowStart != nil {
+		for idx, backup := range backups {
+			keep[idx] = true
+			// the most recent backup completed before the window
+			// start is needed to recover to the window start
+			if !backup.endTime.After(*windowStart) {
+				break
+			}
+		}
+	}
+
+	keepOnePerPeriod := func(count *int, period func(time.Time) string) {
+		if count == nil {
+			return
+		}
+		periods := stringset.New()
+		for idx, backup := range backups {
+			key := period(backup.endTime.UTC())
+			if periods.Has(key) {
+				continue
+			}
+			if periods.Len() == *count {
+				break
+			}
+			periods.Put(key)
+			keep[idx] = true
+		}
+	}
+	keepOnePerPeriod(policy.KeepDaily, func(t time.Time) string {
+		return t.Format(time.DateOnly)
+	})
+	keepOnePerPeriod(policy.KeepWeekly, func(t time.Time) string {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-%02d", year, week)
+	})
+	keepOnePerPeriod(policy.KeepMonthly, func(t time.Time) string {
+		return t.Format("2006-01")
+	})
+
+	var expiredBackups []snapshotBackup
+	for idx, backup := range backups {
+		if !keep[idx] {
+			expiredBackups = append(expiredBackups, backup)
+		}
+	}
+
+	return expiredBackups, nil
+}
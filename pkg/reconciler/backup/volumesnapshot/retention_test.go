@@ -0,0 +1,268 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package volumesnapshot
+
+import (
+	"context"
+	"time"
+
+	volumesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	k8client "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("volume snapshot retention policy", func() {
+	now := time.Date(2025, time.March, 31, 12, 0, 0, 0, time.UTC)
+
+	// newBackups builds one backup per passed age, sorted
+	// from the most recent one as found in the catalog
+	newBackups := func(ages ...time.Duration) []snapshotBackup {
+		backups := make([]snapshotBackup, 0, len(ages))
+		for _, age := range ages {
+			endTime := now.Add(-age)
+			backups = append(backups, snapshotBackup{
+				name:    endTime.Format("20060102150405"),
+				endTime: endTime,
+			})
+		}
+		return backups
+	}
+
+	getNames := func(backups []snapshotBackup) []string {
+		names := make([]string, 0, len(backups))
+		for _, backup := range backups {
+			names = append(names, backup.name)
+		}
+		return names
+	}
+
+	const day = 24 * time.Hour
+
+	It("keeps every backup without rules", func() {
+		expired, err := getExpiredSnapshotBackups(newBackups(day, 2*day), nil, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expired).To(BeEmpty())
+	})
+
+	It("keeps the last N backups", func() {
+		backups := newBackups(day, 2*day, 3*day, 4*day)
+		expired, err := getExpiredSnapshotBackups(backups,
+			&apiv1.VolumeSnapshotRetentionPolicy{KeepLast: ptr.To(2)}, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getNames(expired)).To(Equal(getNames(backups[2:])))
+	})
+
+	It("keeps the backups in the recovery window and the one backing its start", func() {
+		backups := newBackups(day, 3*day, 8*day, 9*day, 20*day)
+		expired, err := getExpiredSnapshotBackups(backups,
+			&apiv1.VolumeSnapshotRetentionPolicy{KeepWithin: "1w"}, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getNames(expired)).To(Equal(getNames(backups[3:])))
+	})
+
+	It("always keeps the most recent backup", func() {
+		backups := newBackups(30*day, 60*day)
+		expired, err := getExpiredSnapshotBackups(backups,
+			&apiv1.VolumeSnapshotRetentionPolicy{KeepDaily: ptr.To(1), KeepWithin: "1d"}, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getNames(expired)).To(Equal(getNames(backups[1:])))
+	})
+
+	It("keeps the most recent backup of each day, week and month", func() {
+		// two backups per day in the last two weeks, plus two older monthly ones
+		var ages []time.Duration
+		for idx := range 14 {
+			ages = append(ages, time.Duration(idx)*day+time.Hour, time.Duration(idx)*day+2*time.Hour)
+		}
+		ages = append(ages, 40*day, 70*day)
+		backups := newBackups(ages...)
+
+		expired, err := getExpiredSnapshotBackups(backups, &apiv1.VolumeSnapshotRetentionPolicy{
+			KeepDaily:   ptr.To(3),
+			KeepWeekly:  ptr.To(2),
+			KeepMonthly: ptr.To(3),
+		}, now)
+		Expect(err).ToNot(HaveOccurred())
+
+		kept := len(backups) - len(expired)
+		// the last 3 daily backups, which also cover the current and
+		// the previous week (the 31st is a Monday), and the monthly
+		// ones for February and January
+		Expect(kept).To(Equal(5))
+		Expect(getNames(expired)).ToNot(ContainElements(
+			backups[0].name, backups[2].name, backups[4].name,
+			backups[len(backups)-2].name, backups[len(backups)-1].name,
+		))
+	})
+
+	Context("EnforceRetentionPolicy", func() {
+		const namespace = "default"
+
+		newSnapshot := func(backupName string, role utils.PVCRole, endTime time.Time) *volumesnapshotv1.VolumeSnapshot {
+			return &volumesnapshotv1.VolumeSnapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      backupName + "-" + string(role),
+					Labels: map[string]string{
+						utils.ClusterLabelName:    "cluster-example",
+						utils.BackupNameLabelName: backupName,
+					},
+					Annotations: map[string]string{
+						utils.PvcRoleLabelName:            string(role),
+						utils.BackupEndTimeAnnotationName: endTime.Format(time.RFC3339),
+					},
+				},
+			}
+		}
+
+		newBackup := func(name string) *apiv1.Backup {
+			return &apiv1.Backup{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+				Spec: apiv1.BackupSpec{
+					Cluster: apiv1.LocalObjectReference{Name: "cluster-example"},
+					Method:  apiv1.BackupMethodVolumeSnapshot,
+				},
+				Status: apiv1.BackupStatus{Phase: apiv1.BackupPhaseCompleted},
+			}
+		}
+
+		var cluster *apiv1.Cluster
+		BeforeEach(func() {
+			cluster = &apiv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "cluster-example"},
+				Spec: apiv1.ClusterSpec{
+					Backup: &apiv1.BackupConfiguration{
+						VolumeSnapshot: &apiv1.VolumeSnapshotConfiguration{
+							RetentionPolicy: &apiv1.VolumeSnapshotRetentionPolicy{KeepLast: ptr.To(1)},
+						},
+					},
+				},
+				Status: apiv1.ClusterStatus{
+					FirstRecoverabilityPointByMethod: map[apiv1.BackupMethod]metav1.Time{
+						apiv1.BackupMethodVolumeSnapshot: metav1.NewTime(now.Add(-3 * day)),
+					},
+				},
+			}
+		})
+
+		It("removes the expired backups with their snapshots", func(ctx context.Context) {
+			verifiedBackup := newBackup("backup-2")
+			verifiedBackup.Spec.Verification = &apiv1.BackupVerificationConfiguration{}
+
+			cli := fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).
+				WithObjects(
+					cluster,
+					newSnapshot("backup-1", utils.PVCRolePgData, now.Add(-day)),
+					newSnapshot("backup-1", utils.PVCRolePgWal, now.Add(-day)),
+					newSnapshot("backup-2", utils.PVCRolePgData, now.Add(-2*day)),
+					newSnapshot("backup-3", utils.PVCRolePgData, now.Add(-3*day)),
+					newSnapshot("backup-3", utils.PVCRolePgWal, now.Add(-3*day)),
+					newBackup("backup-1"),
+					verifiedBackup,
+					newBackup("backup-3"),
+				).
+				WithStatusSubresource(cluster).
+				Build()
+
+			removed, err := EnforceRetentionPolicy(ctx, cli, cluster, now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(removed).To(Equal([]string{"backup-3"}))
+
+			var snapshots volumesnapshotv1.VolumeSnapshotList
+			Expect(cli.List(ctx, &snapshots)).To(Succeed())
+			Expect(snapshots.Items).To(HaveLen(3))
+			for _, snapshot := range snapshots.Items {
+				Expect(snapshot.Labels[utils.BackupNameLabelName]).ToNot(Equal("backup-3"))
+			}
+
+			err = cli.Get(ctx, k8client.ObjectKey{Namespace: namespace, Name: "backup-3"}, &apiv1.Backup{})
+			Expect(apierrs.IsNotFound(err)).To(BeTrue())
+			Expect(cli.Get(ctx, k8client.ObjectKey{Namespace: namespace, Name: "backup-2"}, &apiv1.Backup{})).
+				To(Succeed())
+		})
+
+		It("moves the first recoverability point forward before removing the backups", func(ctx context.Context) {
+			cli := fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).
+				WithObjects(
+					cluster,
+					newSnapshot("backup-1", utils.PVCRolePgData, now.Add(-day)),
+					newSnapshot("backup-2", utils.PVCRolePgData, now.Add(-2*day)),
+					newSnapshot("backup-3", utils.PVCRolePgData, now.Add(-3*day)),
+				).
+				WithStatusSubresource(cluster).
+				Build()
+
+			removed, err := EnforceRetentionPolicy(ctx, cli, cluster, now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(removed).To(ConsistOf("backup-2", "backup-3"))
+
+			var updatedCluster apiv1.Cluster
+			Expect(cli.Get(ctx, k8client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+			Expect(updatedCluster.Status.FirstRecoverabilityPointByMethod[apiv1.BackupMethodVolumeSnapshot].Time).
+				To(BeTemporally("==", now.Add(-day)))
+			Expect(updatedCluster.Status.LastSuccessfulBackupByMethod[apiv1.BackupMethodVolumeSnapshot].Time).
+				To(BeTemporally("==", now.Add(-day)))
+		})
+
+		It("doesn't remove the backup backing the first recoverability point if it can't be moved",
+			func(ctx context.Context) {
+				cli := fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).
+					WithObjects(
+						newSnapshot("backup-1", utils.PVCRolePgData, now.Add(-day)),
+						newSnapshot("backup-3", utils.PVCRolePgData, now.Add(-3*day)),
+					).
+					Build()
+
+				removed, err := EnforceRetentionPolicy(ctx, cli, cluster, now)
+				Expect(err).To(HaveOccurred())
+				Expect(removed).To(BeEmpty())
+
+				var snapshots volumesnapshotv1.VolumeSnapshotList
+				Expect(cli.List(ctx, &snapshots)).To(Succeed())
+				Expect(snapshots.Items).To(HaveLen(2))
+			})
+
+		It("ignores the backups still running", func(ctx context.Context) {
+			running := newSnapshot("backup-0", utils.PVCRolePgData, now)
+			delete(running.Annotations, utils.BackupEndTimeAnnotationName)
+
+			cli := fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).
+				WithObjects(
+					running,
+					newSnapshot("backup-1", utils.PVCRolePgData, now.Add(-day)),
+				).
+				Build()
+
+			removed, err := EnforceRetentionPolicy(ctx, cli, cluster, now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(removed).To(BeEmpty())
+		})
+	})
+})
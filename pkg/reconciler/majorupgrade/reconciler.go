@@ -92,6 +92,15 @@ func Reconcile(
 		return nil, err
 	}
 
+	// The image of the new major version is only selected inside a
+	// maintenance window. Until then, the rest of the cluster is
+	// reconciled with the current image
+	if cluster.Status.Image == cluster.Status.PGDataImageInfo.Image {
+		contextLogger.Debug("Major version upgrade waiting for the image of the new major version",
+			"requestedMajor", requestedMajor)
+		return nil, nil
+	}
+
 	contextLogger.Info("Reconciling in-place major version upgrades",
 		"primaryNodeSerial", primaryNodeSerial, "requestedMajor", requestedMajor)
 
@@ -124,6 +133,7 @@ func Reconcile(
 		status.SetPhase(apiv1.PhaseMajorUpgrade,
 			fmt.Sprintf("Upgrading cluster to major version %v", requestedMajor)),
 		status.SetClusterReadyCondition,
+		status.RemoveMaintenancePendingCondition,
 		status.SetTargetPGDataImageInfo(&apiv1.ImageInfo{
 			Image:        cluster.Status.Image,
 			MajorVersion: requestedMajor,
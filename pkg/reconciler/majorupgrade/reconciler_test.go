@@ -269,6 +269,46 @@ var _ = Describe("Major upgrade reconcile early-revert handling", func() {
 	})
 })
 
+var _ = Describe("Major upgrade maintenance windows", func() {
+	It("waits for the image of the new major version before starting the upgrade", func(ctx SpecContext) {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				ImageName: "postgres:16",
+			},
+			Status: apiv1.ClusterStatus{
+				Phase: apiv1.PhaseHealthy,
+				Image: "postgres:15",
+				PGDataImageInfo: &apiv1.ImageInfo{
+					Image:        "postgres:15",
+					MajorVersion: 15,
+				},
+			},
+		}
+
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithRuntimeObjects(cluster).
+			WithStatusSubresource(cluster).
+			Build()
+
+		result, err := Reconcile(
+			ctx, fakeClient, record.NewFakeRecorder(10),
+			cluster, nil, []corev1.PersistentVolumeClaim{buildPrimaryPVC(1)}, nil,
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(BeNil())
+
+		var updated apiv1.Cluster
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(cluster), &updated)).To(Succeed())
+		Expect(updated.Status.Phase).To(Equal(apiv1.PhaseHealthy))
+		Expect(updated.Status.TargetPGDataImageInfo).To(BeNil())
+	})
+})
+
 var _ = Describe("Major upgrade rollback handling", func() {
 	DescribeTable("deletes the job and resets the image when the user rolls back",
 		func(
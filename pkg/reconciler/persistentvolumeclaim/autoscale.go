@@ -0,0 +1,210 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package persistentvolumeclaim
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// AutoscaledPVC describes a decision of the storage autoscaler about a PVC
+type AutoscaledPVC struct {
+	// The name of the PVC
+	Name string
+
+	// The usage of the filesystem of the PVC, in percentage
+	Usage int
+
+	// The size of the PVC before the growth
+	OldSize resource.Quantity
+
+	// The size of the PVC after the growth. This is equal to
+	// OldSize when the PVC already reached its maximum size
+	NewSize resource.Quantity
+}
+
+// IsLimitReached is true when the PVC could not be grown
+// because it already reached its maximum size
+func (a AutoscaledPVC) IsLimitReached() bool {
+	return a.NewSize.Cmp(a.OldSize) <= 0
+}
+
+// GetFullWALVolumeUsage returns the usage of the volume containing the WAL
+// files of an instance that stopped because of missing disk space
+func GetFullWALVolumeUsage(cluster *apiv1.Cluster) postgres.VolumeUsage {
+	role := utils.PVCRolePgData
+	if cluster.ShouldCreateWalArchiveVolume() {
+		role = utils.PVCRolePgWal
+	}
+
+	return postgres.VolumeUsage{Role: role, UsedBytes: 1}
+}
+
+// ReconcileAutoscaling grows the PVCs whose filesystem usage, as reported
+// by the instances, reached the threshold of their autoscale configuration.
+// The usage is passed per instance name. A PVC is not grown while its
+// previous expansion is running, nor before the cooldown period since its
+// previous growth is elapsed
+func ReconcileAutoscaling(
+	ctx context.Context,
+	c client.Client,
+	cluster *apiv1.Cluster,
+	volumesUsage map[string][]postgres.VolumeUsage,
+	pvcs []corev1.PersistentVolumeClaim,
+	now time.Time,
+) ([]AutoscaledPVC, error) {
+	contextLogger := log.FromContext(ctx).WithName("storage_autoscaler")
+
+	var result []AutoscaledPVC
+	for instanceName, usages := range volumesUsage {
+		for _, usage := range usages {
+			calculator, err := GetExpectedObjectCalculator(map[string]string{
+				utils.PvcRoleLabelName:        string(usage.Role),
+				utils.TablespaceNameLabelName: usage.TablespaceName,
+			})
+			if err != nil {
+				return result, err
+			}
+
+			storageConfiguration, err := calculator.GetStorageConfiguration(cluster)
+			if err != nil || storageConfiguration.Autoscale == nil {
+				continue
+			}
+			autoscale := storageConfiguration.Autoscale
+
+			percentage := usage.GetUsagePercentage()
+			if percentage < autoscale.GetUsageThreshold() {
+				continue
+			}
+
+			pvc := findPVCByName(pvcs, calculator.GetName(instanceName))
+			if pvc == nil || !isAutoscalable(pvc, autoscale, now) {
+				continue
+			}
+
+			oldSize := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+			newSize, err := autoscale.GetNextSize(oldSize)
+			if err != nil {
+				contextLogger.Error(err, "Cannot compute the new size of the PVC", "pvcName", pvc.Name)
+				continue
+			}
+
+			decision := AutoscaledPVC{Name: pvc.Name, Usage: percentage, OldSize: oldSize, NewSize: newSize}
+			if !decision.IsLimitReached() {
+				contextLogger.Info("Growing PVC",
+					"pvcName", pvc.Name, "usage", percentage, "from", oldSize.String(), "to", newSize.String())
+				if err := growPVC(ctx, c, pvc, newSize, now); err != nil {
+					return result, err
+				}
+			}
+			result = append(result, decision)
+		}
+	}
+
+	return result, nil
+}
+
+// isAutoscalable checks that the previous expansion of the PVC
+// is completed and that the cooldown period is elapsed
+func isAutoscalable(
+	pvc *corev1.PersistentVolumeClaim,
+	autoscale *apiv1.StorageAutoscaleConfiguration,
+	now time.Time,
+) bool {
+	if pvc.DeletionTimestamp != nil || isResizing(*pvc) || isExpansionPending(*pvc) {
+		return false
+	}
+
+	autoscaledAt, err := time.Parse(time.RFC3339, pvc.Annotations[utils.PVCAutoscaledAtAnnotationName])
+	if err != nil {
+		return true
+	}
+
+	return now.Sub(autoscaledAt) >= autoscale.GetCooldown()
+}
+
+func growPVC(
+	ctx context.Context,
+	c client.Client,
+	pvc *corev1.PersistentVolumeClaim,
+	size resource.Quantity,
+	now time.Time,
+) error {
+	oldPVC := pvc.DeepCopy()
+	pvc = resources.NewPersistentVolumeClaimBuilderFromPVC(pvc).
+		WithRequests(corev1.ResourceList{corev1.ResourceStorage: size}).
+		Build()
+	if pvc.Annotations == nil {
+		pvc.Annotations = make(map[string]string)
+	}
+	pvc.Annotations[utils.PVCAutoscaledAtAnnotationName] = now.Format(time.RFC3339)
+
+	if err := c.Patch(ctx, pvc, client.MergeFrom(oldPVC)); err != nil {
+		return fmt.Errorf("error while growing PVC %s: %w", pvc.Name, err)
+	}
+
+	return nil
+}
+
+func findPVCByName(pvcs []corev1.PersistentVolumeClaim, name string) *corev1.PersistentVolumeClaim {
+	for idx := range pvcs {
+		if pvcs[idx].Name == name {
+			return &pvcs[idx]
+		}
+	}
+
+	return nil
+}
+
+// getLargestPVCRequest returns the largest storage request among the PVCs
+// with the same role of the passed calculator, which is used as the size
+// of the new PVCs when the autoscaler already grew the existing ones
+func getLargestPVCRequest(
+	pvcs []corev1.PersistentVolumeClaim,
+	calculator ExpectedObjectCalculator,
+) *resource.Quantity {
+	var result *resource.Quantity
+	expectedLabels := calculator.GetLabels("")
+	for idx := range pvcs {
+		pvc := &pvcs[idx]
+		if pvc.Labels[utils.PvcRoleLabelName] != expectedLabels[utils.PvcRoleLabelName] ||
+			pvc.Labels[utils.TablespaceNameLabelName] != expectedLabels[utils.TablespaceNameLabelName] {
+			continue
+		}
+
+		request, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		if ok && (result == nil || request.Cmp(*result) > 0) {
+			result = &request
+		}
+	}
+
+	return result
+}
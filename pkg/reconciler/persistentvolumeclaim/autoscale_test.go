@@ -0,0 +1,218 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package persistentvolumeclaim
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Storage autoscaling", func() {
+	const (
+		clusterName  = "cluster-example"
+		instanceName = "cluster-example-1"
+	)
+
+	var (
+		cluster *apiv1.Cluster
+		now     time.Time
+	)
+
+	makeAutoscaledPVC := func(calculator ExpectedObjectCalculator, size string) corev1.PersistentVolumeClaim {
+		quantity := resource.MustParse(size)
+		return corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      calculator.GetName(instanceName),
+				Namespace: "default",
+				Labels:    calculator.GetLabels(instanceName),
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: quantity},
+				},
+			},
+			Status: corev1.PersistentVolumeClaimStatus{
+				Phase:    corev1.ClaimBound,
+				Capacity: corev1.ResourceList{corev1.ResourceStorage: quantity},
+			},
+		}
+	}
+
+	usageOf := func(role utils.PVCRole, percentage uint64) map[string][]postgres.VolumeUsage {
+		return map[string][]postgres.VolumeUsage{
+			instanceName: {{Role: role, UsedBytes: percentage, AvailableBytes: 100 - percentage}},
+		}
+	}
+
+	reconcile := func(
+		ctx context.Context,
+		volumesUsage map[string][]postgres.VolumeUsage,
+		pvcs ...corev1.PersistentVolumeClaim,
+	) ([]AutoscaledPVC, client.Client) {
+		builder := fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme())
+		for idx := range pvcs {
+			builder = builder.WithObjects(&pvcs[idx])
+		}
+		cli := builder.Build()
+
+		result, err := ReconcileAutoscaling(ctx, cli, cluster, volumesUsage, pvcs, now)
+		Expect(err).ToNot(HaveOccurred())
+		return result, cli
+	}
+
+	getRequest := func(ctx context.Context, cli client.Client, name string) string {
+		var pvc corev1.PersistentVolumeClaim
+		Expect(cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: name}, &pvc)).To(Succeed())
+		return pvc.Spec.Resources.Requests.Storage().String()
+	}
+
+	BeforeEach(func() {
+		now = time.Now()
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterName, Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				StorageConfiguration: apiv1.StorageConfiguration{
+					Size: "10Gi",
+					Autoscale: &apiv1.StorageAutoscaleConfiguration{
+						MaxSize: "15Gi",
+					},
+				},
+			},
+		}
+	})
+
+	It("grows the PVCs over the usage threshold", func(ctx context.Context) {
+		pvc := makeAutoscaledPVC(NewPgDataCalculator(), "10Gi")
+		result, cli := reconcile(ctx, usageOf(utils.PVCRolePgData, 85), pvc)
+
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Name).To(Equal(pvc.Name))
+		Expect(result[0].Usage).To(Equal(85))
+		Expect(result[0].IsLimitReached()).To(BeFalse())
+		Expect(result[0].NewSize.String()).To(Equal("12Gi"))
+		Expect(getRequest(ctx, cli, pvc.Name)).To(Equal("12Gi"))
+	})
+
+	It("doesn't grow the PVCs under the usage threshold", func(ctx context.Context) {
+		pvc := makeAutoscaledPVC(NewPgDataCalculator(), "10Gi")
+		result, cli := reconcile(ctx, usageOf(utils.PVCRolePgData, 50), pvc)
+
+		Expect(result).To(BeEmpty())
+		Expect(getRequest(ctx, cli, pvc.Name)).To(Equal("10Gi"))
+	})
+
+	It("doesn't grow the PVCs without an autoscale configuration", func(ctx context.Context) {
+		pvc := makeAutoscaledPVC(NewPgWalCalculator(), "10Gi")
+		cluster.Spec.WalStorage = &apiv1.StorageConfiguration{Size: "10Gi"}
+		result, _ := reconcile(ctx, usageOf(utils.PVCRolePgWal, 99), pvc)
+
+		Expect(result).To(BeEmpty())
+	})
+
+	It("waits for the cooldown period", func(ctx context.Context) {
+		pvc := makeAutoscaledPVC(NewPgDataCalculator(), "10Gi")
+		pvc.Annotations = map[string]string{
+			utils.PVCAutoscaledAtAnnotationName: now.Add(-time.Minute).Format(time.RFC3339),
+		}
+		result, _ := reconcile(ctx, usageOf(utils.PVCRolePgData, 90), pvc)
+		Expect(result).To(BeEmpty())
+
+		now = now.Add(10 * time.Minute)
+		result, _ = reconcile(ctx, usageOf(utils.PVCRolePgData, 90), pvc)
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("waits for the previous expansion to complete", func(ctx context.Context) {
+		pvc := makeAutoscaledPVC(NewPgDataCalculator(), "10Gi")
+		pvc.Status.Capacity[corev1.ResourceStorage] = resource.MustParse("8Gi")
+		result, _ := reconcile(ctx, usageOf(utils.PVCRolePgData, 90), pvc)
+
+		Expect(result).To(BeEmpty())
+	})
+
+	It("reports the PVCs that reached their maximum size", func(ctx context.Context) {
+		pvc := makeAutoscaledPVC(NewPgDataCalculator(), "15Gi")
+		result, cli := reconcile(ctx, usageOf(utils.PVCRolePgData, 90), pvc)
+
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].IsLimitReached()).To(BeTrue())
+		Expect(getRequest(ctx, cli, pvc.Name)).To(Equal("15Gi"))
+	})
+
+	It("grows the tablespace PVCs", func(ctx context.Context) {
+		cluster.Spec.Tablespaces = []apiv1.TablespaceConfiguration{
+			{
+				Name: "tbs1",
+				Storage: apiv1.StorageConfiguration{
+					Size: "1Gi",
+					Autoscale: &apiv1.StorageAutoscaleConfiguration{
+						Increment: "512Mi",
+						MaxSize:   "5Gi",
+					},
+				},
+			},
+		}
+		pvc := makeAutoscaledPVC(NewPgTablespaceCalculator("tbs1"), "1Gi")
+		result, cli := reconcile(ctx, map[string][]postgres.VolumeUsage{
+			instanceName: {{
+				Role:           utils.PVCRolePgTablespace,
+				TablespaceName: "tbs1",
+				UsedBytes:      95,
+				AvailableBytes: 5,
+			}},
+		}, pvc)
+
+		Expect(result).To(HaveLen(1))
+		Expect(getRequest(ctx, cli, pvc.Name)).To(Equal("1536Mi"))
+	})
+
+	It("considers full the WAL volume of the instances without disk space", func() {
+		usage := GetFullWALVolumeUsage(cluster)
+		Expect(usage.Role).To(Equal(utils.PVCRolePgData))
+		Expect(usage.GetUsagePercentage()).To(Equal(100))
+
+		cluster.Spec.WalStorage = &apiv1.StorageConfiguration{Size: "1Gi"}
+		Expect(GetFullWALVolumeUsage(cluster).Role).To(Equal(utils.PVCRolePgWal))
+	})
+
+	It("uses the largest PVC size for the new PVCs", func() {
+		pvcs := []corev1.PersistentVolumeClaim{
+			makeAutoscaledPVC(NewPgDataCalculator(), "12Gi"),
+			makeAutoscaledPVC(NewPgWalCalculator(), "20Gi"),
+		}
+
+		Expect(getLargestPVCRequest(pvcs, NewPgDataCalculator()).String()).To(Equal("12Gi"))
+		Expect(getLargestPVCRequest(pvcs, NewPgTablespaceCalculator("tbs1"))).To(BeNil())
+	})
+})
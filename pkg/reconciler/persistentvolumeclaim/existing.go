@@ -140,6 +140,10 @@ func reconcilePVCQuantity(
 	case 0:
 		return nil
 	case 1:
+		if storageConfiguration.Autoscale != nil {
+			// the PVC has been grown by the autoscaler
+			return nil
+		}
 		contextLogger.Warning("cannot decrease storage requirement",
 			"from", currentSize, "to", parsedSize,
 			"pvcName", pvc.Name)
@@ -30,6 +30,7 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 // CreateInstancePVCs creates the expected pvcs for the instance
@@ -94,6 +95,12 @@ func reconcileSingleInstanceMissingPVCs(
 			return ctrl.Result{}, err
 		}
 
+		if conf.Autoscale != nil {
+			if conf, err = getAutoscaledStorageConfiguration(ctx, c, cluster, expectedPVC.calculator, conf, pvcs); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
 		pvcSource, err := expectedPVC.calculator.GetSource(source)
 		if err != nil {
 			return ctrl.Result{}, err
@@ -114,3 +121,38 @@ func reconcileSingleInstanceMissingPVCs(
 
 	return ctrl.Result{}, nil
 }
+
+// getAutoscaledStorageConfiguration returns the storage configuration of a
+// new PVC whose role has autoscaling enabled. When the autoscaler already
+// grew the existing PVCs with the same role, the new one is created with
+// their largest size, as their content may not fit in the initial size
+func getAutoscaledStorageConfiguration(
+	ctx context.Context,
+	c client.Client,
+	cluster *apiv1.Cluster,
+	calculator ExpectedObjectCalculator,
+	conf apiv1.StorageConfiguration,
+	pvcs []corev1.PersistentVolumeClaim,
+) (apiv1.StorageConfiguration, error) {
+	if pvcs == nil {
+		var pvcList corev1.PersistentVolumeClaimList
+		if err := c.List(ctx, &pvcList,
+			client.InNamespace(cluster.Namespace),
+			client.MatchingLabels{utils.ClusterLabelName: cluster.Name},
+		); err != nil {
+			return conf, err
+		}
+		pvcs = pvcList.Items
+	}
+
+	largestRequest := getLargestPVCRequest(pvcs, calculator)
+	if largestRequest == nil {
+		return conf, nil
+	}
+
+	if size := conf.GetSizeOrNil(); size == nil || largestRequest.Cmp(*size) > 0 {
+		conf.Size = largestRequest.String()
+	}
+
+	return conf, nil
+}
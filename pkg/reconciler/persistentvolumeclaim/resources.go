@@ -79,6 +79,14 @@ func isResizing(pvc corev1.PersistentVolumeClaim) bool {
 	return hasPVCCondition(pvc, corev1.PersistentVolumeClaimResizing)
 }
 
+// isExpansionPending returns true when the PVC requests more storage than
+// the capacity of its volume, meaning that its expansion is still running
+func isExpansionPending(pvc corev1.PersistentVolumeClaim) bool {
+	capacity, hasCapacity := pvc.Status.Capacity[corev1.ResourceStorage]
+	requested, hasRequest := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	return hasCapacity && hasRequest && requested.Cmp(capacity) > 0
+}
+
 // BelongToInstance returns a boolean indicating if that given PVC belongs to an instance
 func BelongToInstance(cluster *apiv1.Cluster, instanceName, pvcName string) bool {
 	expectedPVCs := GetExpectedInstancePVCNamesFromCluster(cluster, instanceName)
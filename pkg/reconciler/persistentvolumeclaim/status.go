@@ -264,7 +264,7 @@ func classifyPVC(
 
 	// PVC has a corresponding Pod
 	if hasPod(pvc, podList) {
-		if isResizing(pvc) {
+		if isResizing(pvc) || isExpansionPending(pvc) {
 			return resizing
 		}
 		return healthy
@@ -24,6 +24,7 @@ import (
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
@@ -151,6 +152,21 @@ var _ = Describe("PVC classification with resizing PVCs", func() {
 		Expect(cluster.Status.DanglingPVC).Should(Equal([]string{clusterName + "-2"}))
 		Expect(cluster.Status.Instances).Should(BeEquivalentTo(2))
 	})
+	It("classifies PVC with a pending expansion and a pod as resizing", func(ctx SpecContext) {
+		pvc := makePVC(clusterName, "1", "1", NewPgDataCalculator(), false)
+		pvc.Spec.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("2Gi")}
+		pvc.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")}
+		cluster := makeCluster()
+		EnrichStatus(
+			ctx,
+			cluster,
+			[]corev1.Pod{makePod(clusterName, "1", specs.ClusterRoleLabelPrimary)},
+			[]batchv1.Job{},
+			[]corev1.PersistentVolumeClaim{pvc},
+		)
+		Expect(cluster.Status.ResizingPVC).Should(Equal([]string{clusterName + "-1"}))
+		Expect(cluster.Status.HealthyPVC).Should(BeEmpty())
+	})
 })
 
 var _ = Describe("EnsureHealthyPVCsAnnotation", func() {
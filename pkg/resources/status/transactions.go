@@ -91,3 +91,38 @@ func SetTimelineID(timelineID int) Transaction {
 		cluster.Status.TimelineID = timelineID
 	}
 }
+
+// SetMaintenancePendingCondition is a transaction that reports a disruptive
+// operation waiting for the next maintenance window
+func SetMaintenancePendingCondition(message string) Transaction {
+	return func(cluster *apiv1.Cluster) {
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:    string(apiv1.ConditionMaintenancePending),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(apiv1.WaitingForMaintenanceWindow),
+			Message: message,
+		})
+	}
+}
+
+// RemoveMaintenancePendingCondition is a transaction that removes the
+// report of a disruptive operation waiting for a maintenance window
+func RemoveMaintenancePendingCondition(cluster *apiv1.Cluster) {
+	meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionMaintenancePending))
+}
+
+// SetReplicaAutoscalingStatus is a transaction that records the
+// last decision taken by the read-replica autoscaler
+func SetReplicaAutoscalingStatus(autoscalingStatus *apiv1.ReplicaAutoscalingStatus) Transaction {
+	return func(cluster *apiv1.Cluster) {
+		cluster.Status.Autoscaling = autoscalingStatus
+	}
+}
+
+// SetSwitchoverDrainStatus is a transaction that records the progress
+// of the connection draining preceding a switchover
+func SetSwitchoverDrainStatus(drainStatus *apiv1.SwitchoverDrainStatus) Transaction {
+	return func(cluster *apiv1.Cluster) {
+		cluster.Status.SwitchoverDrain = drainStatus
+	}
+}
@@ -0,0 +1,212 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package specs
+
+import (
+	"fmt"
+	"slices"
+
+	volumesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// jobRoleBackupVerification is the role of the jobs restoring
+// a backup into a throwaway instance to verify it
+const jobRoleBackupVerification jobRole = "backup-verification"
+
+// GetBackupVerificationJobName returns the name of the job
+// verifying the passed backup
+func GetBackupVerificationJobName(backupName string) string {
+	return fmt.Sprintf("%s-%s", backupName, jobRoleBackupVerification)
+}
+
+// CreateBackupVerificationJob creates the job verifying the passed backup.
+// The backup is restored by the same "instance restore" or "instance
+// restoresnapshot" init container used to bootstrap a cluster, and then
+// checked by the "instance verifybackup" container. The volumes of the
+// throwaway instance are ephemeral, provisioned like the ones of the
+// cluster and, for volume snapshot backups, populated from the snapshots.
+// The PG_DATA snapshot is needed to restore a volume snapshot backup,
+// and the job is owned by the backup
+func CreateBackupVerificationJob(
+	cluster apiv1.Cluster,
+	backup *apiv1.Backup,
+	dataSnapshot *metav1.ObjectMeta,
+) (*batchv1.Job, error) {
+	verificationCluster := *cluster.GetBackupVerificationCluster(backup)
+
+	var job *batchv1.Job
+	if backup.Spec.Method == apiv1.BackupMethodVolumeSnapshot {
+		job = CreatePrimaryJobViaRestoreSnapshot(verificationCluster, 1, dataSnapshot, backup)
+	} else {
+		job = CreatePrimaryJobViaRecovery(verificationCluster, 1, backup)
+	}
+
+	jobName := GetBackupVerificationJobName(backup.Name)
+	for _, labels := range []map[string]string{job.Labels, job.Spec.Template.Labels} {
+		delete(labels, utils.InstanceNameLabelName)
+		labels[utils.JobRoleLabelName] = string(jobRoleBackupVerification)
+		labels[utils.BackupNameLabelName] = backup.Name
+	}
+	job.Name = jobName
+	job.Namespace = backup.Namespace
+	job.OwnerReferences = nil
+	job.Spec.BackoffLimit = ptr.To[int32](0)
+
+	podSpec := &job.Spec.Template.Spec
+	podSpec.Hostname = jobName
+
+	restoreContainer := podSpec.Containers[0]
+	restoreContainer.Command = append(restoreContainer.Command, "--verify-backup", backup.Name)
+	for idx := range restoreContainer.Env {
+		if restoreContainer.Env[idx].Name == "POD_NAME" {
+			restoreContainer.Env[idx].Value = jobName
+		}
+	}
+
+	verifyContainer := *restoreContainer.DeepCopy()
+	verifyContainer.Name = string(jobRoleBackupVerification)
+	verifyContainer.Command = []string{
+		"/controller/manager",
+		"instance",
+		"verifybackup",
+		backup.Name,
+	}
+	addManagerLoggingOptions(cluster, &verifyContainer)
+
+	podSpec.InitContainers = append(podSpec.InitContainers, restoreContainer)
+	podSpec.Containers = []corev1.Container{verifyContainer}
+
+	if err := useBackupVerificationVolumes(cluster, backup, podSpec); err != nil {
+		return nil, err
+	}
+
+	utils.SetAsOwnedBy(&job.ObjectMeta, backup.ObjectMeta, metav1.TypeMeta{
+		APIVersion: apiv1.SchemeGroupVersion.String(),
+		Kind:       apiv1.BackupKind,
+	})
+
+	return job, nil
+}
+
+// useBackupVerificationVolumes replaces the persistent volume claims of
+// the instance with ephemeral volumes, provisioned like the ones of the
+// cluster and, when restoring a volume snapshot backup, populated from
+// the corresponding snapshots
+func useBackupVerificationVolumes(cluster apiv1.Cluster, backup *apiv1.Backup, podSpec *corev1.PodSpec) error {
+	snapshots := make(map[string]string)
+	if backup.Spec.Method == apiv1.BackupMethodVolumeSnapshot {
+		for _, element := range backup.Status.BackupSnapshotStatus.Elements {
+			switch utils.PVCRole(element.Type) {
+			case utils.PVCRolePgData:
+				snapshots[pgdataVolumeName] = element.Name
+			case utils.PVCRolePgWal:
+				snapshots["pg-wal"] = element.Name
+			case utils.PVCRolePgTablespace:
+				snapshots[VolumeMountNameForTablespace(element.TablespaceName)] = element.Name
+			}
+		}
+	}
+
+	storages := map[string]apiv1.StorageConfiguration{
+		pgdataVolumeName: cluster.Spec.StorageConfiguration,
+	}
+	if cluster.Spec.WalStorage != nil {
+		storages["pg-wal"] = *cluster.Spec.WalStorage
+	}
+	for _, tablespace := range cluster.Spec.Tablespaces {
+		storages[VolumeMountNameForTablespace(tablespace.Name)] = tablespace.Storage
+	}
+
+	for name, storage := range storages {
+		idx := slices.IndexFunc(podSpec.Volumes, func(volume corev1.Volume) bool {
+			return volume.Name == name
+		})
+		if idx < 0 {
+			continue
+		}
+
+		volume, err := createBackupVerificationVolume(name, storage, snapshots[name])
+		if err != nil {
+			return err
+		}
+		podSpec.Volumes[idx] = volume
+	}
+
+	return nil
+}
+
+// createBackupVerificationVolume creates an ephemeral volume provisioned
+// like the passed storage configuration, optionally populated from the
+// passed volume snapshot
+func createBackupVerificationVolume(
+	name string,
+	storage apiv1.StorageConfiguration,
+	snapshotName string,
+) (corev1.Volume, error) {
+	var claimSpec corev1.PersistentVolumeClaimSpec
+	if storage.PersistentVolumeClaimTemplate != nil {
+		claimSpec = *storage.PersistentVolumeClaimTemplate.DeepCopy()
+	}
+
+	if len(claimSpec.AccessModes) == 0 {
+		claimSpec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+	}
+
+	if storage.StorageClass != nil {
+		claimSpec.StorageClassName = storage.StorageClass
+	}
+
+	if storage.Size != "" {
+		size, err := resource.ParseQuantity(storage.Size)
+		if err != nil {
+			return corev1.Volume{}, fmt.Errorf("while parsing the size of volume %s: %w", name, err)
+		}
+		claimSpec.Resources.Requests = corev1.ResourceList{
+			corev1.ResourceStorage: size,
+		}
+	}
+
+	if snapshotName != "" {
+		claimSpec.DataSource = &corev1.TypedLocalObjectReference{
+			APIGroup: ptr.To(volumesnapshotv1.GroupName),
+			Kind:     apiv1.VolumeSnapshotKind,
+			Name:     snapshotName,
+		}
+	}
+
+	return corev1.Volume{
+		Name: name,
+		VolumeSource: corev1.VolumeSource{
+			Ephemeral: &corev1.EphemeralVolumeSource{
+				VolumeClaimTemplate: &corev1.PersistentVolumeClaimTemplate{
+					Spec: claimSpec,
+				},
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package specs
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Backup verification jobs", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+		},
+		Spec: apiv1.ClusterSpec{
+			StorageConfiguration: apiv1.StorageConfiguration{
+				Size:         "1Gi",
+				StorageClass: ptr.To("fast"),
+			},
+			WalStorage: &apiv1.StorageConfiguration{
+				Size: "512Mi",
+			},
+		},
+	}
+
+	getVolume := func(podSpec corev1.PodSpec, name string) corev1.Volume {
+		for _, volume := range podSpec.Volumes {
+			if volume.Name == name {
+				return volume
+			}
+		}
+		Fail("missing volume " + name)
+		return corev1.Volume{}
+	}
+
+	It("restores a barmanObjectStore backup into ephemeral volumes", func() {
+		backup := &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "backup-example",
+				Namespace: "default",
+				UID:       "backup-uid",
+			},
+			Spec: apiv1.BackupSpec{
+				Method: apiv1.BackupMethodBarmanObjectStore,
+			},
+		}
+
+		job, err := CreateBackupVerificationJob(cluster, backup, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(job.Name).To(Equal("backup-example-backup-verification"))
+		Expect(job.Labels).To(HaveKeyWithValue(utils.BackupNameLabelName, "backup-example"))
+		Expect(job.Labels).To(HaveKeyWithValue(utils.JobRoleLabelName, "backup-verification"))
+		Expect(job.Labels).ToNot(HaveKey(utils.InstanceNameLabelName))
+		Expect(job.OwnerReferences).To(HaveLen(1))
+		Expect(job.OwnerReferences[0].Kind).To(Equal(apiv1.BackupKind))
+		Expect(job.Spec.BackoffLimit).To(HaveValue(BeEquivalentTo(0)))
+
+		podSpec := job.Spec.Template.Spec
+		restoreContainer := podSpec.InitContainers[len(podSpec.InitContainers)-1]
+		Expect(restoreContainer.Command).To(ContainElements("restore", "--verify-backup", "backup-example"))
+		Expect(podSpec.Containers).To(HaveLen(1))
+		Expect(podSpec.Containers[0].Command).To(ContainElements("verifybackup", "backup-example"))
+
+		pgData := getVolume(podSpec, pgdataVolumeName)
+		Expect(pgData.PersistentVolumeClaim).To(BeNil())
+		Expect(pgData.Ephemeral).ToNot(BeNil())
+		claimSpec := pgData.Ephemeral.VolumeClaimTemplate.Spec
+		Expect(claimSpec.StorageClassName).To(HaveValue(Equal("fast")))
+		Expect(claimSpec.Resources.Requests.Storage().String()).To(Equal("1Gi"))
+		Expect(claimSpec.DataSource).To(BeNil())
+
+		pgWal := getVolume(podSpec, "pg-wal")
+		Expect(pgWal.Ephemeral).ToNot(BeNil())
+		Expect(pgWal.Ephemeral.VolumeClaimTemplate.Spec.Resources.Requests.Storage().String()).To(Equal("512Mi"))
+	})
+
+	It("populates the ephemeral volumes from the snapshots of a volumeSnapshot backup", func() {
+		backup := &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "backup-snapshot",
+				Namespace: "default",
+			},
+			Spec: apiv1.BackupSpec{
+				Method: apiv1.BackupMethodVolumeSnapshot,
+			},
+			Status: apiv1.BackupStatus{
+				BackupSnapshotStatus: apiv1.BackupSnapshotStatus{
+					Elements: []apiv1.BackupSnapshotElementStatus{
+						{Name: "backup-snapshot-data", Type: string(utils.PVCRolePgData)},
+						{Name: "backup-snapshot-wal", Type: string(utils.PVCRolePgWal)},
+					},
+				},
+			},
+		}
+		dataSnapshot := &metav1.ObjectMeta{Name: "backup-snapshot-data"}
+
+		job, err := CreateBackupVerificationJob(cluster, backup, dataSnapshot)
+		Expect(err).ToNot(HaveOccurred())
+
+		podSpec := job.Spec.Template.Spec
+		restoreContainer := podSpec.InitContainers[len(podSpec.InitContainers)-1]
+		Expect(restoreContainer.Command).To(ContainElement("restoresnapshot"))
+
+		dataSource := getVolume(podSpec, pgdataVolumeName).Ephemeral.VolumeClaimTemplate.Spec.DataSource
+		Expect(dataSource).ToNot(BeNil())
+		Expect(dataSource.Kind).To(Equal(apiv1.VolumeSnapshotKind))
+		Expect(dataSource.Name).To(Equal("backup-snapshot-data"))
+		Expect(getVolume(podSpec, "pg-wal").Ephemeral.VolumeClaimTemplate.Spec.DataSource.Name).
+			To(Equal("backup-snapshot-wal"))
+	})
+
+	It("fails with an invalid storage size", func() {
+		invalidCluster := cluster.DeepCopy()
+		invalidCluster.Spec.StorageConfiguration.Size = "a lot"
+		backup := &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup-example"},
+			Spec:       apiv1.BackupSpec{Method: apiv1.BackupMethodBarmanObjectStore},
+		}
+		_, err := CreateBackupVerificationJob(*invalidCluster, backup, nil)
+		Expect(err).To(HaveOccurred())
+	})
+})
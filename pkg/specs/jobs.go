@@ -21,6 +21,7 @@ package specs
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
 	"github.com/kballard/go-shellquote"
@@ -220,6 +221,7 @@ func CreatePrimaryJobViaRecovery(cluster apiv1.Cluster, nodeSerial int, backup *
 	job := CreatePrimaryJob(cluster, nodeSerial, jobRoleFullRecovery, initCommand, getExtensions(&cluster))
 
 	addBarmanEndpointCAToJobFromCluster(cluster, backup, job)
+	addPgBaseBackupVolumeToJob(backup, job)
 
 	return job
 }
@@ -248,6 +250,37 @@ func addBarmanEndpointCAToJobFromCluster(cluster apiv1.Cluster, backup *apiv1.Ba
 	}
 }
 
+// addPgBaseBackupVolumeToJob mounts the volume containing the passed backup,
+// when it has been taken with the pgBaseBackup method, in the containers
+// of the job. The volume of the backup replaces the one configured in the
+// cluster, if any, as the job only needs to read the backup
+func addPgBaseBackupVolumeToJob(backup *apiv1.Backup, job *batchv1.Job) {
+	if backup == nil || backup.Status.PgBaseBackupStatus == nil ||
+		backup.Status.PgBaseBackupStatus.ClaimName == "" {
+		return
+	}
+
+	podSpec := &job.Spec.Template.Spec
+	volume := createPgBaseBackupVolume(backup.Status.PgBaseBackupStatus.ClaimName)
+	volumeIndex := slices.IndexFunc(podSpec.Volumes, func(v corev1.Volume) bool {
+		return v.Name == pgBaseBackupVolumeName
+	})
+	if volumeIndex >= 0 {
+		podSpec.Volumes[volumeIndex] = volume
+		return
+	}
+
+	podSpec.Volumes = append(podSpec.Volumes, volume)
+	for idx := range podSpec.Containers {
+		podSpec.Containers[idx].VolumeMounts = append(podSpec.Containers[idx].VolumeMounts,
+			corev1.VolumeMount{
+				Name:      pgBaseBackupVolumeName,
+				MountPath: PgBaseBackupVolumePath,
+			},
+		)
+	}
+}
+
 // CreatePrimaryJobViaPgBaseBackup creates a new primary instance in a Pod
 func CreatePrimaryJobViaPgBaseBackup(cluster apiv1.Cluster, nodeSerial int) *batchv1.Job {
 	commonFlags := buildCommonInitJobFlags(cluster)
@@ -0,0 +1,238 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package specs
+
+import (
+	"fmt"
+	"strconv"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// jobRoleLogicalBackup is the role of the jobs taking
+	// backups with the logical method
+	jobRoleLogicalBackup jobRole = "logical-backup"
+
+	// logicalBackupContainerName is the name of the container
+	// running pg_dump in the logical backup jobs
+	logicalBackupContainerName = "logical-backup"
+
+	// serverCAVolumeName is the name of the volume containing
+	// the CA used to verify the certificate of the primary
+	serverCAVolumeName = "server-ca"
+)
+
+// GetLogicalBackupJobName returns the name of the job taking the
+// passed backup with the logical method
+func GetLogicalBackupJobName(backupName string) string {
+	return fmt.Sprintf("%s-%s", backupName, jobRoleLogicalBackup)
+}
+
+// CreateLogicalBackupJob creates the job exporting the databases of the
+// cluster with pg_dump, connecting to the primary via the read-write
+// service. The job is owned by the backup
+func CreateLogicalBackupJob(cluster apiv1.Cluster, backup *apiv1.Backup) *batchv1.Job {
+	jobName := GetLogicalBackupJobName(backup.Name)
+	version, _ := cluster.GetPostgresqlMajorVersion()
+
+	labels := map[string]string{
+		utils.ClusterLabelName:                cluster.Name,
+		utils.BackupNameLabelName:             backup.Name,
+		utils.JobRoleLabelName:                string(jobRoleLogicalBackup),
+		utils.KubernetesAppLabelName:          utils.AppName,
+		utils.KubernetesAppInstanceLabelName:  cluster.Name,
+		utils.KubernetesAppVersionLabelName:   fmt.Sprint(version),
+		utils.KubernetesAppComponentLabelName: utils.DatabaseComponentName,
+		utils.KubernetesAppManagedByLabelName: utils.ManagerName,
+	}
+
+	scratchDataMount := corev1.VolumeMount{
+		Name:      "scratch-data",
+		MountPath: postgres.ScratchDataDirectory,
+	}
+
+	bootstrapContainer := corev1.Container{
+		Name:            BootstrapControllerContainerName,
+		Image:           configuration.Current.OperatorImageName,
+		ImagePullPolicy: cluster.Spec.ImagePullPolicy,
+		Command: []string{
+			"/manager",
+			"bootstrap",
+			"/controller/manager",
+		},
+		VolumeMounts:    []corev1.VolumeMount{scratchDataMount},
+		Resources:       cluster.Spec.Resources,
+		SecurityContext: GetSecurityContext(&cluster),
+	}
+	addManagerLoggingOptions(cluster, &bootstrapContainer)
+
+	logicalBackupContainer := corev1.Container{
+		Name:            logicalBackupContainerName,
+		Image:           cluster.Status.Image,
+		ImagePullPolicy: cluster.Spec.ImagePullPolicy,
+		Command: []string{
+			"/controller/manager",
+			"logicalbackup",
+			backup.Name,
+		},
+		Env: createLogicalBackupEnvVars(cluster),
+		VolumeMounts: []corev1.VolumeMount{
+			scratchDataMount,
+			{
+				Name:      serverCAVolumeName,
+				MountPath: postgres.CertificatesDir,
+				ReadOnly:  true,
+			},
+			{
+				Name:      kubeAPIAccessVolumeName,
+				MountPath: kubeAPIAccessMountPath,
+				ReadOnly:  true,
+			},
+			{
+				Name:      logicalBackupVolumeName,
+				MountPath: LogicalBackupVolumePath,
+			},
+		},
+		Resources:       cluster.Spec.Resources,
+		SecurityContext: GetSecurityContext(&cluster),
+	}
+	addManagerLoggingOptions(cluster, &logicalBackupContainer)
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName,
+			Namespace: backup.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: ptr.To[int32](0),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{bootstrapContainer},
+					SchedulerName:  cluster.Spec.SchedulerName,
+					Containers:     []corev1.Container{logicalBackupContainer},
+					Volumes: []corev1.Volume{
+						createEphemeralVolume(&cluster),
+						{
+							Name: serverCAVolumeName,
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{
+									SecretName: cluster.GetServerCASecretName(),
+									Items: []corev1.KeyToPath{
+										{
+											Key:  certs.CACertKey,
+											Path: "server-ca.crt",
+										},
+									},
+								},
+							},
+						},
+						createKubeAPIAccessVolume(),
+						createLogicalBackupVolume(cluster.Spec.Backup.Logical.ClaimName),
+					},
+					SecurityContext:              GetPodSecurityContext(&cluster),
+					Tolerations:                  cluster.Spec.Affinity.Tolerations,
+					ServiceAccountName:           cluster.GetServiceAccountName(),
+					AutomountServiceAccountToken: ptr.To(false),
+					RestartPolicy:                corev1.RestartPolicyNever,
+					NodeSelector:                 cluster.Spec.Affinity.NodeSelector,
+				},
+			},
+		},
+	}
+
+	utils.SetAsOwnedBy(&job.ObjectMeta, backup.ObjectMeta, metav1.TypeMeta{
+		APIVersion: apiv1.SchemeGroupVersion.String(),
+		Kind:       apiv1.BackupKind,
+	})
+	if utils.IsAnnotationAppArmorPresent(&job.Spec.Template.Spec, cluster.Annotations) {
+		utils.AnnotateAppArmor(&job.ObjectMeta, &job.Spec.Template.Spec, cluster.Annotations)
+	}
+
+	if cluster.Spec.PriorityClassName != "" {
+		job.Spec.Template.Spec.PriorityClassName = cluster.Spec.PriorityClassName
+	}
+
+	return job
+}
+
+// createLogicalBackupEnvVars creates the environment variables used by
+// pg_dump to connect to the primary with the configured credentials
+func createLogicalBackupEnvVars(cluster apiv1.Cluster) []corev1.EnvVar {
+	credentialsSecretName := cluster.GetLogicalBackupCredentialsSecretName()
+
+	return []corev1.EnvVar{
+		{
+			Name:  "NAMESPACE",
+			Value: cluster.Namespace,
+		},
+		{
+			Name:  "CLUSTER_NAME",
+			Value: cluster.Name,
+		},
+		{
+			Name:  "PGHOST",
+			Value: cluster.GetServiceReadWriteName(),
+		},
+		{
+			Name:  "PGPORT",
+			Value: strconv.Itoa(postgres.ServerPort),
+		},
+		{
+			Name:  "PGSSLMODE",
+			Value: "verify-ca",
+		},
+		{
+			Name:  "PGSSLROOTCERT",
+			Value: postgres.ServerCACertificateLocation,
+		},
+		{
+			Name: "PGUSER",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: credentialsSecretName},
+					Key:                  corev1.BasicAuthUsernameKey,
+				},
+			},
+		},
+		{
+			Name: "PGPASSWORD",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: credentialsSecretName},
+					Key:                  corev1.BasicAuthPasswordKey,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package specs
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Logical backup jobs", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+		},
+		Spec: apiv1.ClusterSpec{
+			Backup: &apiv1.BackupConfiguration{
+				Logical: &apiv1.LogicalBackupConfiguration{
+					ClaimName: "dumps",
+				},
+			},
+		},
+	}
+	backup := &apiv1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "backup-example",
+			Namespace: "default",
+			UID:       "backup-uid",
+		},
+	}
+
+	It("is owned by the backup", func() {
+		job := CreateLogicalBackupJob(cluster, backup)
+		Expect(job.Name).To(Equal("backup-example-logical-backup"))
+		Expect(job.Labels).To(HaveKeyWithValue(utils.BackupNameLabelName, "backup-example"))
+		Expect(job.OwnerReferences).To(HaveLen(1))
+		Expect(job.OwnerReferences[0].Kind).To(Equal(apiv1.BackupKind))
+		Expect(job.OwnerReferences[0].UID).To(BeEquivalentTo("backup-uid"))
+	})
+
+	It("mounts the logical backup volume", func() {
+		job := CreateLogicalBackupJob(cluster, backup)
+		Expect(job.Spec.Template.Spec.Volumes).To(ContainElement(HaveField("Name", logicalBackupVolumeName)))
+		Expect(job.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      logicalBackupVolumeName,
+			MountPath: LogicalBackupVolumePath,
+		}))
+	})
+
+	It("connects with the superuser credentials by default", func() {
+		env := createLogicalBackupEnvVars(cluster)
+		Expect(env).To(ContainElement(HaveField("Name", "PGHOST")))
+		for _, envVar := range env {
+			if envVar.Name == "PGPASSWORD" {
+				Expect(envVar.ValueFrom.SecretKeyRef.Name).To(Equal(cluster.GetSuperuserSecretName()))
+			}
+		}
+	})
+
+	It("connects with the configured credentials", func() {
+		customCluster := cluster.DeepCopy()
+		customCluster.Spec.Backup.Logical.Credentials = &apiv1.LocalObjectReference{Name: "dump-user"}
+		for _, envVar := range createLogicalBackupEnvVars(*customCluster) {
+			if envVar.Name == "PGUSER" || envVar.Name == "PGPASSWORD" {
+				Expect(envVar.ValueFrom.SecretKeyRef.Name).To(Equal("dump-user"))
+			}
+		}
+	})
+})
@@ -33,6 +33,8 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	config "github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	pgBouncerConfig "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/config"
+	pgCatConfig "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgcat/config"
+	poolerConfig "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pooler/config"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/podspec"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
@@ -41,7 +43,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils/hash"
 )
 
-// Deployment creates the pgbouncer Deployment for the given Pooler. The
+// Deployment creates the Deployment running the pooler for the given Pooler. The
 // container image and deployment hash both derive from
 // pooler.Status.Image; the caller (updateDeployment) must populate it
 // first.
@@ -53,7 +55,8 @@ func Deployment(pooler *apiv1.Pooler, cluster *apiv1.Cluster) (*appsv1.Deploymen
 		return nil, err
 	}
 
-	podTemplate := podspec.NewFrom(pooler.Spec.Template).
+	containerName := string(pooler.GetBackend())
+	podTemplateBuilder := podspec.NewFrom(pooler.Spec.Template).
 		WithLabel(utils.PgbouncerNameLabel, pooler.Name).
 		WithLabel(utils.ClusterLabelName, cluster.Name).
 		WithLabel(utils.PodRoleLabelName, string(utils.PodRolePooler)).
@@ -78,19 +81,19 @@ func Deployment(pooler *apiv1.Pooler, cluster *apiv1.Cluster) (*appsv1.Deploymen
 			},
 		}).
 		WithSecurityContext(createPodSecurityContext(cluster.GetSeccompProfile(), 998, 996), true).
-		WithContainerImage("pgbouncer", pooler.Status.Image, false).
-		WithContainerCommand("pgbouncer", []string{
+		WithContainerImage(containerName, pooler.Status.Image, false).
+		WithContainerCommand(containerName, []string{
 			"/controller/manager",
-			"pgbouncer",
+			containerName,
 			"run",
 		}, false).
-		WithContainerPort("pgbouncer", &corev1.ContainerPort{
-			Name:          pgBouncerConfig.PgBouncerPortName,
-			ContainerPort: pgBouncerConfig.PgBouncerPort,
+		WithContainerPort(containerName, &corev1.ContainerPort{
+			Name:          getPortName(pooler),
+			ContainerPort: poolerConfig.Port,
 		}).
-		WithContainerPort("pgbouncer", &corev1.ContainerPort{
+		WithContainerPort(containerName, &corev1.ContainerPort{
 			Name:          "metrics",
-			ContainerPort: url.PgBouncerMetricsPort,
+			ContainerPort: url.PoolerMetricsPort,
 		}).
 		WithInitContainerImage(specs.BootstrapControllerContainerName, operatorImageName, true).
 		WithInitContainerCommand(specs.BootstrapControllerContainerName,
@@ -109,30 +112,37 @@ func Deployment(pooler *apiv1.Pooler, cluster *apiv1.Cluster) (*appsv1.Deploymen
 			Name:      "scratch-data",
 			MountPath: postgres.ScratchDataDirectory,
 		}, true).
-		WithContainerVolumeMount("pgbouncer", &corev1.VolumeMount{
+		WithContainerVolumeMount(containerName, &corev1.VolumeMount{
 			Name:      "scratch-data",
 			MountPath: postgres.ScratchDataDirectory,
 		}, true).
-		WithContainerEnv("pgbouncer", corev1.EnvVar{Name: "NAMESPACE", Value: pooler.Namespace}, true).
-		WithContainerEnv("pgbouncer", corev1.EnvVar{Name: "POOLER_NAME", Value: pooler.Name}, true).
-		WithContainerEnv("pgbouncer", corev1.EnvVar{Name: "PGUSER", Value: "pgbouncer"}, false).
-		WithContainerEnv("pgbouncer", corev1.EnvVar{Name: "PGDATABASE", Value: "pgbouncer"}, false).
-		WithContainerEnv("pgbouncer", corev1.EnvVar{Name: "PGHOST", Value: "/controller/run"}, false).
-		WithContainerEnv("pgbouncer", corev1.EnvVar{
-			Name:  "PSQL_HISTORY",
-			Value: path.Join(postgres.TemporaryDirectory, ".psql_history"),
-		}, false).
-		WithContainerEnv("pgbouncer", corev1.EnvVar{
+		WithContainerEnv(containerName, corev1.EnvVar{Name: "NAMESPACE", Value: pooler.Namespace}, true).
+		WithContainerEnv(containerName, corev1.EnvVar{Name: "POOLER_NAME", Value: pooler.Name}, true).
+		WithContainerEnv(containerName, corev1.EnvVar{
 			Name:  "METRICS_PORT_TLS",
 			Value: strconv.FormatBool(pooler.IsMetricsTLSEnabled()),
-		}, true).
-		WithContainerSecurityContext("pgbouncer", specs.GetSecurityContext(cluster), true).
+		}, true)
+
+	// The admin console of PgCat is not reachable via the Unix socket
+	if pooler.GetBackend() == apiv1.PoolerBackendPgBouncer {
+		podTemplateBuilder = podTemplateBuilder.
+			WithContainerEnv("pgbouncer", corev1.EnvVar{Name: "PGUSER", Value: "pgbouncer"}, false).
+			WithContainerEnv("pgbouncer", corev1.EnvVar{Name: "PGDATABASE", Value: "pgbouncer"}, false).
+			WithContainerEnv("pgbouncer", corev1.EnvVar{Name: "PGHOST", Value: "/controller/run"}, false).
+			WithContainerEnv("pgbouncer", corev1.EnvVar{
+				Name:  "PSQL_HISTORY",
+				Value: path.Join(postgres.TemporaryDirectory, ".psql_history"),
+			}, false)
+	}
+
+	podTemplate := podTemplateBuilder.
+		WithContainerSecurityContext(containerName, specs.GetSecurityContext(cluster), true).
 		WithServiceAccountName(pooler.GetServiceAccountName(), true).
-		WithReadinessProbe("pgbouncer", &corev1.Probe{
+		WithReadinessProbe(containerName, &corev1.Probe{
 			TimeoutSeconds: 5,
 			ProbeHandler: corev1.ProbeHandler{
 				TCPSocket: &corev1.TCPSocketAction{
-					Port: intstr.FromInt32(pgBouncerConfig.PgBouncerPort),
+					Port: intstr.FromInt32(poolerConfig.Port),
 				},
 			},
 		}, false).
@@ -156,7 +166,7 @@ func Deployment(pooler *apiv1.Pooler, cluster *apiv1.Cluster) (*appsv1.Deploymen
 			},
 		},
 		Spec: appsv1.DeploymentSpec{
-			Replicas: pooler.Spec.Instances,
+			Replicas: pooler.GetDesiredInstances(),
 			Selector: &metav1.LabelSelector{
 				MatchLabels: map[string]string{
 					utils.PgbouncerNameLabel: pooler.Name,
@@ -174,9 +184,19 @@ func Deployment(pooler *apiv1.Pooler, cluster *apiv1.Cluster) (*appsv1.Deploymen
 	}, nil
 }
 
+// getPortName gets the name of the port where the pooler is listening
+func getPortName(pooler *apiv1.Pooler) string {
+	if pooler.GetBackend() == apiv1.PoolerBackendPgCat {
+		return pgCatConfig.PgCatPortName
+	}
+
+	return pgBouncerConfig.PgBouncerPortName
+}
+
 func computeTemplateHash(pooler *apiv1.Pooler, operatorImageName string) (string, error) {
 	type deploymentHash struct {
 		poolerSpec                      apiv1.PoolerSpec
+		desiredInstances                *int32
 		operatorImageName               string
 		pgbouncerImage                  string
 		isPodSpecReconciliationDisabled bool
@@ -184,6 +204,7 @@ func computeTemplateHash(pooler *apiv1.Pooler, operatorImageName string) (string
 
 	return hash.ComputeHash(deploymentHash{
 		poolerSpec:                      pooler.Spec,
+		desiredInstances:                pooler.GetDesiredInstances(),
 		operatorImageName:               operatorImageName,
 		pgbouncerImage:                  pooler.Status.Image,
 		isPodSpecReconciliationDisabled: utils.IsPodSpecReconciliationDisabled(&pooler.ObjectMeta),
@@ -29,7 +29,8 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	config "github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
-	pgBouncerConfig "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/config"
+	poolerConfig "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pooler/config"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -183,7 +184,35 @@ var _ = Describe("Deployment", func() {
 		Expect(deployment).ToNot(BeNil())
 		Expect(deployment.Spec.Template.Spec.Containers[0].ReadinessProbe.TimeoutSeconds).To(Equal(int32(5)))
 		Expect(deployment.Spec.Template.Spec.Containers[0].ReadinessProbe.TCPSocket.Port).
-			To(Equal(intstr.FromInt32(pgBouncerConfig.PgBouncerPort)))
+			To(Equal(intstr.FromInt32(poolerConfig.Port)))
+	})
+
+	It("runs PgCat when it is the backend of the Pooler", func() {
+		pooler.Spec.PgBouncer = nil
+		pooler.Spec.PgCat = &apiv1.PgCatSpec{Image: "pgcat:latest"}
+		pooler.Status.Image = "pgcat:latest"
+
+		deployment, err := Deployment(pooler, cluster)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(deployment.Spec.Template.Spec.Containers).To(HaveLen(1))
+
+		container := deployment.Spec.Template.Spec.Containers[0]
+		Expect(container.Name).To(Equal("pgcat"))
+		Expect(container.Image).To(Equal("pgcat:latest"))
+		Expect(container.Command).To(Equal([]string{"/controller/manager", "pgcat", "run"}))
+		Expect(container.Ports).To(ContainElement(corev1.ContainerPort{
+			Name:          "pgcat",
+			ContainerPort: poolerConfig.Port,
+		}))
+		Expect(container.Env).To(ConsistOf(
+			corev1.EnvVar{Name: "NAMESPACE", Value: pooler.Namespace},
+			corev1.EnvVar{Name: "POOLER_NAME", Value: pooler.Name},
+			corev1.EnvVar{Name: "METRICS_PORT_TLS", Value: "false"},
+		))
+		Expect(container.Ports).To(ContainElement(corev1.ContainerPort{
+			Name:          "metrics",
+			ContainerPort: url.PoolerMetricsPort,
+		}))
 	})
 
 	It("should correctly set pod resources to the bootstrap init container", func() {
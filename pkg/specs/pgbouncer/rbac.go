@@ -20,6 +20,8 @@ SPDX-License-Identifier: Apache-2.0
 package pgbouncer
 
 import (
+	"slices"
+
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -55,9 +57,19 @@ func Role(pooler *apiv1.Pooler) *rbacv1.Role {
 		if pooler.Status.Secrets.ClientTLS.Name != "" {
 			secretNames = append(secretNames, pooler.Status.Secrets.ClientTLS.Name)
 		}
+
+		for _, clusterSecrets := range pooler.Status.Secrets.Clusters {
+			if clusterSecrets.ServerCA.Name != "" && !slices.Contains(secretNames, clusterSecrets.ServerCA.Name) {
+				secretNames = append(secretNames, clusterSecrets.ServerCA.Name)
+			}
+
+			if clusterSecrets.AuthQuery.Name != "" && !slices.Contains(secretNames, clusterSecrets.AuthQuery.Name) {
+				secretNames = append(secretNames, clusterSecrets.AuthQuery.Name)
+			}
+		}
 	}
 
-	return &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{
+	role := &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{
 		Name: pooler.Name, Namespace: pooler.Namespace,
 	}, Rules: []rbacv1.PolicyRule{
 		{
@@ -106,6 +118,25 @@ func Role(pooler *apiv1.Pooler) *rbacv1.Role {
 			ResourceNames: secretNames,
 		},
 	}}
+
+	// The replicas of the cluster are discovered by the
+	// PgBouncer instance manager to balance the connections
+	if pooler.IsReplicaLoadBalancingEnabled() {
+		role.Rules = append(role.Rules, rbacv1.PolicyRule{
+			APIGroups: []string{
+				"",
+			},
+			Resources: []string{
+				"pods",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+			},
+		})
+	}
+
+	return role
 }
 
 // RoleBinding creates a role binding for a given pooler
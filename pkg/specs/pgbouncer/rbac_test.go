@@ -20,6 +20,8 @@ SPDX-License-Identifier: Apache-2.0
 package pgbouncer
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -65,6 +67,43 @@ var _ = Describe("Pooler ServiceAccount, Role, and RoleBinding", func() {
 			Expect(role.Rules[2].Resources).To(ContainElement("secrets"))
 			Expect(role.Rules[2].Verbs).To(ConsistOf("get", "watch"))
 		})
+
+		It("grants access to the secrets of every referenced cluster", func() {
+			pooler.Status.Secrets.ServerCA = apiv1.SecretVersion{Name: "cluster-example-ca"}
+			pooler.Status.Secrets.Clusters = []apiv1.PoolerClusterSecrets{
+				{
+					Cluster:   "cluster-billing",
+					ServerCA:  apiv1.SecretVersion{Name: "cluster-billing-ca"},
+					AuthQuery: apiv1.SecretVersion{Name: "cluster-billing-pooler"},
+				},
+				{
+					Cluster:  "cluster-shared-ca",
+					ServerCA: apiv1.SecretVersion{Name: "cluster-example-ca"},
+				},
+			}
+
+			role := Role(pooler)
+			Expect(role.Rules[2].ResourceNames).To(ConsistOf(
+				pooler.GetAuthQuerySecretName(),
+				"cluster-example-ca",
+				"cluster-billing-ca",
+				"cluster-billing-pooler",
+			))
+		})
+	})
+
+	Context("when the replicas are load balanced", func() {
+		It("grants access to the pods", func() {
+			pooler.Spec.Type = apiv1.PoolerTypeRO
+			pooler.Spec.ReplicaLoadBalancing = &apiv1.PoolerReplicaLoadBalancing{
+				MaxLag: metav1.Duration{Duration: 30 * time.Second},
+			}
+
+			role := Role(pooler)
+			Expect(role.Rules).To(HaveLen(4))
+			Expect(role.Rules[3].Resources).To(ConsistOf("pods"))
+			Expect(role.Rules[3].Verbs).To(ConsistOf("get", "list"))
+		})
 	})
 
 	Context("when creating a RoleBinding", func() {
@@ -25,7 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
-	pgBouncerConfig "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/config"
+	poolerConfig "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pooler/config"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/servicespec"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils/hash"
@@ -50,9 +50,9 @@ func Service(pooler *apiv1.Pooler, cluster *apiv1.Cluster) (*corev1.Service, err
 		WithAnnotation(utils.PoolerSpecHashAnnotationName, poolerHash).
 		WithServiceType(corev1.ServiceTypeClusterIP, false).
 		WithServicePortNoOverwrite(&corev1.ServicePort{
-			Name:       pgBouncerConfig.PgBouncerPortName,
-			Port:       pgBouncerConfig.PgBouncerPort,
-			TargetPort: intstr.FromString(pgBouncerConfig.PgBouncerPortName),
+			Name:       getPortName(pooler),
+			Port:       poolerConfig.Port,
+			TargetPort: intstr.FromString(getPortName(pooler)),
 			Protocol:   corev1.ProtocolTCP,
 		}).
 		SetPGBouncerSelector(pooler.Name).
@@ -25,7 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
-	pgBouncerConfig "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/config"
+	poolerConfig "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pooler/config"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils/hash"
 
@@ -89,7 +89,7 @@ var _ = Describe("Pooler Service", func() {
 			Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeClusterIP))
 			Expect(service.Spec.Ports).To(ConsistOf(corev1.ServicePort{
 				Name:       "pgbouncer",
-				Port:       pgBouncerConfig.PgBouncerPort,
+				Port:       poolerConfig.Port,
 				TargetPort: intstr.FromString("pgbouncer"),
 				Protocol:   corev1.ProtocolTCP,
 			}))
@@ -97,5 +97,18 @@ var _ = Describe("Pooler Service", func() {
 				utils.PgbouncerNameLabel: pooler.Name,
 			}))
 		})
+
+		It("targets the PgCat port when PgCat is the backend", func() {
+			pooler.Spec.PgCat = &apiv1.PgCatSpec{Image: "pgcat:latest"}
+
+			service, err := Service(pooler, cluster)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(service.Spec.Ports).To(ConsistOf(corev1.ServicePort{
+				Name:       "pgcat",
+				Port:       poolerConfig.Port,
+				TargetPort: intstr.FromString("pgcat"),
+				Protocol:   corev1.ProtocolTCP,
+			}))
+		})
 	})
 })
@@ -45,6 +45,11 @@ type RoleOptions struct {
 	// the instance manager permissions to read the secrets that
 	// contain the roles' password.
 	Roles []apiv1.DatabaseRole
+
+	// Databases is the list of databases of the cluster. It is used
+	// to grant the instance manager permissions to read the secrets
+	// used by the user mappings of their foreign servers.
+	Databases []apiv1.Database
 }
 
 // CreateRole create a role with the permissions needed by the instance manager
@@ -342,6 +347,7 @@ func getInvolvedSecretNames(opts RoleOptions) []string {
 	involvedSecretNames = append(involvedSecretNames, externalClusterSecrets(opts.Cluster)...)
 	involvedSecretNames = append(involvedSecretNames, managedRolesSecrets(opts.Cluster)...)
 	involvedSecretNames = append(involvedSecretNames, customResourceRolesSecrets(opts.Roles)...)
+	involvedSecretNames = append(involvedSecretNames, databasesSecrets(opts.Databases)...)
 
 	return cleanupResourceList(involvedSecretNames)
 }
@@ -548,6 +554,16 @@ func customResourceRolesSecrets(roles []apiv1.DatabaseRole) []string {
 	return result
 }
 
+func databasesSecrets(databases []apiv1.Database) []string {
+	var result []string
+
+	for i := range databases {
+		result = append(result, databases[i].Spec.GetUserMappingSecretNames()...)
+	}
+
+	return result
+}
+
 func crdRoleSecretName(role *apiv1.DatabaseRole) string {
 	if role.Spec.DisablePassword || role.Spec.PasswordSecret == nil {
 		return ""
@@ -378,6 +378,48 @@ var _ = Describe("Database Roles", func() {
 	})
 })
 
+var _ = Describe("Secrets used by the databases", func() {
+	It("gets the secrets used by the user mappings", func() {
+		passwordOption := func(secretName string) apiv1.UserMappingOptionSpec {
+			return apiv1.UserMappingOptionSpec{
+				Name: "password",
+				SecretKeyRef: &apiv1.SecretKeySelector{
+					LocalObjectReference: apiv1.LocalObjectReference{Name: secretName},
+					Key:                  "password",
+				},
+			}
+		}
+		databases := []apiv1.Database{
+			{
+				Spec: apiv1.DatabaseSpec{
+					Servers: []apiv1.ServerSpec{
+						{
+							UserMappings: []apiv1.UserMappingSpec{
+								{
+									Name: "app",
+									Options: []apiv1.UserMappingOptionSpec{
+										{Name: "user", Value: "remote"},
+										passwordOption("remote-app"),
+									},
+								},
+								{
+									Name:    "PUBLIC",
+									Options: []apiv1.UserMappingOptionSpec{passwordOption("remote-public")},
+								},
+							},
+						},
+					},
+				},
+			},
+			{
+				Spec: apiv1.DatabaseSpec{},
+			},
+		}
+
+		Expect(databasesSecrets(databases)).To(ConsistOf("remote-app", "remote-public"))
+	})
+})
+
 var _ = Describe("CRD database role secret name", func() {
 	It("should be empty when password is disabled", func() {
 		role := apiv1.DatabaseRole{
@@ -53,6 +53,22 @@ const PgTablespaceVolumePath = "/var/lib/postgresql/tablespaces"
 // pgdataVolumeName is the name of the PGDATA volume
 const pgdataVolumeName = "pgdata"
 
+// PgBaseBackupVolumePath is the path used by the volume containing the
+// backups taken with the pgBaseBackup method, when present
+const PgBaseBackupVolumePath = "/var/lib/postgresql/backups"
+
+// pgBaseBackupVolumeName is the name of the volume containing the
+// backups taken with the pgBaseBackup method
+const pgBaseBackupVolumeName = "pgbasebackup"
+
+// LogicalBackupVolumePath is the path used by the volume containing the
+// dumps taken with the logical backup method, when present
+const LogicalBackupVolumePath = "/var/lib/postgresql/dumps"
+
+// logicalBackupVolumeName is the name of the volume containing the
+// dumps taken with the logical backup method
+const logicalBackupVolumeName = "logical-backup"
+
 // MountForTablespace returns the normalized tablespace volume name for a given
 // tablespace, on a cluster pod
 func MountForTablespace(tablespaceName string) string {
@@ -161,6 +177,14 @@ func createPostgresVolumes(
 		}
 	}
 
+	if cluster.Spec.Backup.IsPgBaseBackupConfigured() {
+		result = append(result, createPgBaseBackupVolume(cluster.Spec.Backup.PgBaseBackup.ClaimName))
+	}
+
+	if cluster.Spec.Backup.IsLogicalBackupConfigured() {
+		result = append(result, createLogicalBackupVolume(cluster.Spec.Backup.Logical.ClaimName))
+	}
+
 	if cluster.ShouldCreateProjectedVolume() {
 		result = append(result, createProjectedVolume(cluster))
 	}
@@ -172,6 +196,32 @@ func createPostgresVolumes(
 	return result
 }
 
+// createPgBaseBackupVolume creates the volume containing the backups
+// taken with the pgBaseBackup method
+func createPgBaseBackupVolume(claimName string) corev1.Volume {
+	return corev1.Volume{
+		Name: pgBaseBackupVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: claimName,
+			},
+		},
+	}
+}
+
+// createLogicalBackupVolume creates the volume containing the dumps
+// taken with the logical backup method
+func createLogicalBackupVolume(claimName string) corev1.Volume {
+	return corev1.Volume{
+		Name: logicalBackupVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: claimName,
+			},
+		},
+	}
+}
+
 func createVolumesAndVolumeMountsForSQLRefs(
 	folder postInitFolder,
 	refs *apiv1.SQLRefs,
@@ -324,6 +374,24 @@ func CreatePostgresVolumeMounts(cfg VolumeMountsConfig) []corev1.VolumeMount {
 		}
 	}
 
+	if cluster.Spec.Backup.IsPgBaseBackupConfigured() {
+		volumeMounts = append(volumeMounts,
+			corev1.VolumeMount{
+				Name:      pgBaseBackupVolumeName,
+				MountPath: PgBaseBackupVolumePath,
+			},
+		)
+	}
+
+	if cluster.Spec.Backup.IsLogicalBackupConfigured() {
+		volumeMounts = append(volumeMounts,
+			corev1.VolumeMount{
+				Name:      logicalBackupVolumeName,
+				MountPath: LogicalBackupVolumePath,
+			},
+		)
+	}
+
 	volumeMounts = append(volumeMounts, CreateExtensionVolumeMounts(extensions)...)
 
 	return volumeMounts
@@ -21,7 +21,31 @@ SPDX-License-Identifier: Apache-2.0
 
 package compatibility
 
+import (
+	"errors"
+	"syscall"
+	"time"
+)
+
 // SetCoredumpFilter for darwin compatibility
 func SetCoredumpFilter(_ string) error {
 	return nil
 }
+
+// GetFilesystemUsage returns the size of the filesystem containing the
+// passed path, together with the used space and the space available to
+// non-root users
+func GetFilesystemUsage(path string) (total, used, available uint64, err error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, 0, err
+	}
+
+	blockSize := uint64(stat.Bsize)
+	return stat.Blocks * blockSize, (stat.Blocks - stat.Bfree) * blockSize, stat.Bavail * blockSize, nil
+}
+
+// GetCPUUsage for darwin compatibility
+func GetCPUUsage() (time.Duration, error) {
+	return 0, errors.New("CPU usage is not supported on darwin")
+}
@@ -23,7 +23,24 @@ SPDX-License-Identifier: Apache-2.0
 package compatibility
 
 import (
+	"bufio"
+	"bytes"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// cgroupV2CPUStatFile contains the CPU usage of the cgroup
+	// of the container, when cgroup v2 is in use
+	cgroupV2CPUStatFile = "/sys/fs/cgroup/cpu.stat"
+
+	// cgroupV1CPUUsageFile contains the CPU usage of the cgroup
+	// of the container in nanoseconds, when cgroup v1 is in use
+	cgroupV1CPUUsageFile = "/sys/fs/cgroup/cpuacct/cpuacct.usage"
 )
 
 // SetCoredumpFilter set the value of /proc/self/coredump_filter
@@ -31,3 +48,46 @@ func SetCoredumpFilter(coredumpFilter string) error {
 	coredumpFilterFile := "/proc/self/coredump_filter"
 	return os.WriteFile(coredumpFilterFile, []byte(coredumpFilter), 0o600)
 }
+
+// GetFilesystemUsage returns the size of the filesystem containing the
+// passed path, together with the used space and the space available to
+// non-root users
+func GetFilesystemUsage(path string) (total, used, available uint64, err error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, 0, err
+	}
+
+	blockSize := uint64(stat.Bsize) //nolint:gosec // the block size is always positive
+	return stat.Blocks * blockSize, (stat.Blocks - stat.Bfree) * blockSize, stat.Bavail * blockSize, nil
+}
+
+// GetCPUUsage returns the total CPU time consumed by the
+// processes of the cgroup of the container
+func GetCPUUsage() (time.Duration, error) {
+	if content, err := os.ReadFile(cgroupV2CPUStatFile); err == nil {
+		scanner := bufio.NewScanner(bytes.NewReader(content))
+		for scanner.Scan() {
+			value, found := strings.CutPrefix(scanner.Text(), "usage_usec ")
+			if !found {
+				continue
+			}
+			usec, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("while parsing %s: %w", cgroupV2CPUStatFile, err)
+			}
+			return time.Duration(usec) * time.Microsecond, nil
+		}
+		return 0, fmt.Errorf("missing usage_usec in %s", cgroupV2CPUStatFile)
+	}
+
+	content, err := os.ReadFile(cgroupV1CPUUsageFile)
+	if err != nil {
+		return 0, err
+	}
+	nsec, err := strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("while parsing %s: %w", cgroupV1CPUUsageFile, err)
+	}
+	return time.Duration(nsec), nil
+}
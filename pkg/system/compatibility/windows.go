@@ -22,7 +22,22 @@ SPDX-License-Identifier: Apache-2.0
 // Package compatibility provides a layer to cross-compile with other OS than Linux
 package compatibility
 
+import (
+	"errors"
+	"time"
+)
+
 // SetCoredumpFilter for Windows compatibility
 func SetCoredumpFilter(_ string) error {
 	return nil
 }
+
+// GetFilesystemUsage for Windows compatibility
+func GetFilesystemUsage(_ string) (total, used, available uint64, err error) {
+	return 0, 0, 0, errors.New("filesystem usage is not supported on Windows")
+}
+
+// GetCPUUsage for Windows compatibility
+func GetCPUUsage() (time.Duration, error) {
+	return 0, errors.New("CPU usage is not supported on Windows")
+}
@@ -97,6 +97,10 @@ const (
 	// BackupNameLabelName is the name of the label containing the backup id, available on backup resources
 	BackupNameLabelName = MetadataNamespace + "/backupName"
 
+	// MajorMigrationLabelName is the name of the label containing the name of the
+	// MajorMigration that created a cluster, a publication or a subscription
+	MajorMigrationLabelName = MetadataNamespace + "/majorMigration"
+
 	// MajorVersionLabelName is the Postgres major version contained in a snapshot backup
 	MajorVersionLabelName = MetadataNamespace + "/majorVersion"
 
@@ -216,6 +220,10 @@ const (
 	// The status can be "initializing", "ready" or "detached"
 	PVCStatusAnnotationName = MetadataNamespace + "/pvcStatus"
 
+	// PVCAutoscaledAtAnnotationName is the name of the annotation containing the
+	// time when the PVC was last grown by the storage autoscaler
+	PVCAutoscaledAtAnnotationName = MetadataNamespace + "/autoscaledAt"
+
 	// LegacyBackupAnnotationName is the name of the annotation represents whether taking a backup without passing
 	// the name argument even on barman version 3.3.0+. The value can be "true" or "false"
 	LegacyBackupAnnotationName = MetadataNamespace + "/forceLegacyBackup"
@@ -228,6 +236,22 @@ const (
 	// the hash of the Pooler Specification
 	PoolerSpecHashAnnotationName = MetadataNamespace + "/poolerSpecHash"
 
+	// PoolerPausedForSwitchoverAnnotationName is the name of the annotation
+	// added to a Pooler paused by the operator while draining the connections
+	// before a switchover. The value is the name of the target primary
+	PoolerPausedForSwitchoverAnnotationName = MetadataNamespace + "/pausedForSwitchover"
+
+	// PoolerPausedForMigrationAnnotationName is the name of the annotation
+	// added to a Pooler paused by the operator during the cutover of a
+	// major migration. The value is the name of the MajorMigration
+	PoolerPausedForMigrationAnnotationName = MetadataNamespace + "/pausedForMigration"
+
+	// ClusterReadOnlyForMigrationAnnotationName is the name of the annotation
+	// added to the source Cluster of a major migration when the operator makes
+	// its transactions read-only during the cutover. The value is the name of
+	// the MajorMigration
+	ClusterReadOnlyForMigrationAnnotationName = MetadataNamespace + "/readOnlyForMigration"
+
 	// OperatorManagedSecretsAnnotationName is the name of the annotation containing
 	// the secrets managed by the operator inside the generated service account
 	OperatorManagedSecretsAnnotationName = MetadataNamespace + "/managedSecrets"
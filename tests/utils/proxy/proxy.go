@@ -72,7 +72,7 @@ func RetrieveMetricsFromPgBouncer(
 	pod corev1.Pod,
 	tlsEnabled bool,
 ) (string, error) {
-	body, err := runProxyRequest(ctx, kubeInterface, &pod, tlsEnabled, url.PathMetrics, int(url.PgBouncerMetricsPort))
+	body, err := runProxyRequest(ctx, kubeInterface, &pod, tlsEnabled, url.PathMetrics, int(url.PoolerMetricsPort))
 	return string(body), err
 }
 